@@ -0,0 +1,239 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cell
+
+// ansi.go contains code that converts text with ANSI escape sequences into a
+// RichTextString.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// escape is the rune that starts ANSI escape sequences.
+const escape = '\x1b'
+
+// ParseANSI converts text containing ANSI escape sequences into a
+// RichTextString, e.g. the colored output of command line tools.
+//
+// SGR (Select Graphic Rendition) sequences are converted into cell options.
+// Supported are the reset, bold, dim, italic, underline, blink, inverse and
+// strikethrough attributes (and the codes that turn them off) and foreground
+// and background colors in the 16 color, 256 color and 24 bit formats.
+// Unsupported SGR codes are ignored. All the other escape sequences, e.g.
+// the ones moving the cursor, are removed from the text.
+//
+// Returns an error if the text ends in the middle of an escape sequence or
+// if a color in an SGR sequence is invalid.
+func ParseANSI(s string) (*RichTextString, error) {
+	rts := NewRichTextString(ColorDefault)
+	cur := Options{}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			rts.AddText(text.String())
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		if s[i] != escape {
+			text.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		seq, n, err := escapeSeq(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+		if seq == nil {
+			// Not an SGR sequence, removed.
+			continue
+		}
+
+		next := cur
+		if err := applySGR(&next, seq); err != nil {
+			return nil, err
+		}
+		if next != cur {
+			flush()
+			cur = next
+			opt := cur
+			rts.AddOpt(&opt)
+		}
+	}
+	flush()
+	return rts, nil
+}
+
+// escapeSeq parses the escape sequence at the start of the text. Returns the
+// parameters of the sequence if it is an SGR sequence or nil otherwise and
+// the number of bytes the sequence occupies.
+func escapeSeq(s string) ([]string, int, error) {
+	if len(s) < 2 {
+		return nil, 0, fmt.Errorf("the text ends in the middle of an escape sequence %q", s)
+	}
+
+	switch s[1] {
+	case '[': // CSI, Control Sequence Introducer.
+		// Terminated by the first byte in the range 0x40–0x7E.
+		for i := 2; i < len(s); i++ {
+			if b := s[i]; b >= 0x40 && b <= 0x7e {
+				if b != 'm' {
+					return nil, i + 1, nil
+				}
+				return strings.Split(s[2:i], ";"), i + 1, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("the text ends in the middle of an escape sequence %q", s)
+
+	case ']': // OSC, Operating System Command.
+		// Terminated by BEL or by ST (ESC \).
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return nil, i + 1, nil
+			}
+			if s[i] == escape && i+1 < len(s) && s[i+1] == '\\' {
+				return nil, i + 2, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("the text ends in the middle of an escape sequence %q", s)
+
+	default:
+		// Any number of intermediate bytes in the range 0x20–0x2F, followed
+		// by the final byte.
+		for i := 1; i < len(s); i++ {
+			if b := s[i]; b < 0x20 || b > 0x2f {
+				return nil, i + 1, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("the text ends in the middle of an escape sequence %q", s)
+	}
+}
+
+// applySGR applies the parameters of an SGR sequence onto the options.
+func applySGR(opts *Options, params []string) error {
+	codes := make([]int, len(params))
+	for i, p := range params {
+		if p == "" {
+			// Omitted parameters default to zero, e.g. "ESC[m" is a reset.
+			continue
+		}
+		c, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %q in the SGR escape sequence %q: %v", p, strings.Join(params, ";"), err)
+		}
+		codes[i] = c
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			*opts = Options{}
+		case c == 1:
+			opts.Bold = true
+		case c == 2:
+			opts.Dim = true
+		case c == 3:
+			opts.Italic = true
+		case c == 4:
+			opts.Underline = true
+		case c == 5 || c == 6:
+			opts.Blink = true
+		case c == 7:
+			opts.Inverse = true
+		case c == 9:
+			opts.Strikethrough = true
+		case c == 22:
+			opts.Bold = false
+			opts.Dim = false
+		case c == 23:
+			opts.Italic = false
+		case c == 24:
+			opts.Underline = false
+		case c == 25:
+			opts.Blink = false
+		case c == 27:
+			opts.Inverse = false
+		case c == 29:
+			opts.Strikethrough = false
+		case c >= 30 && c <= 37:
+			opts.FgColor = ColorNumber(c - 30)
+		case c == 38:
+			color, n, err := extendedColor(codes[i+1:])
+			if err != nil {
+				return err
+			}
+			opts.FgColor = color
+			i += n
+		case c == 39:
+			opts.FgColor = ColorDefault
+		case c >= 40 && c <= 47:
+			opts.BgColor = ColorNumber(c - 40)
+		case c == 48:
+			color, n, err := extendedColor(codes[i+1:])
+			if err != nil {
+				return err
+			}
+			opts.BgColor = color
+			i += n
+		case c == 49:
+			opts.BgColor = ColorDefault
+		case c >= 90 && c <= 97:
+			opts.FgColor = ColorNumber(c - 90 + 8)
+		case c >= 100 && c <= 107:
+			opts.BgColor = ColorNumber(c - 100 + 8)
+		}
+	}
+	return nil
+}
+
+// extendedColor parses the parameters following the SGR codes 38 and 48,
+// i.e. either "5;n" for one of the 256 colors or "2;r;g;b" for a 24 bit
+// color. Returns the color and the number of parameters it occupies.
+func extendedColor(codes []int) (Color, int, error) {
+	if len(codes) == 0 {
+		return 0, 0, fmt.Errorf("missing the color type in an SGR extended color")
+	}
+
+	switch codes[0] {
+	case 5:
+		if len(codes) < 2 {
+			return 0, 0, fmt.Errorf("missing the color number in an SGR 256 color")
+		}
+		if n := codes[1]; n < 0 || n > 255 {
+			return 0, 0, fmt.Errorf("invalid SGR 256 color number %d, must be in range 0 <= n <= 255", n)
+		}
+		return ColorNumber(codes[1]), 2, nil
+
+	case 2:
+		if len(codes) < 4 {
+			return 0, 0, fmt.Errorf("missing the color components in an SGR 24 bit color")
+		}
+		r, g, b := codes[1], codes[2], codes[3]
+		for _, c := range []int{r, g, b} {
+			if c < 0 || c > 255 {
+				return 0, 0, fmt.Errorf("invalid SGR 24 bit color (%d, %d, %d), each component must be in range 0 <= c <= 255", r, g, b)
+			}
+		}
+		return ColorRGB24(r, g, b), 4, nil
+
+	default:
+		return 0, 0, fmt.Errorf("unsupported SGR extended color type %d, must be either 5 or 2", codes[0])
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cell
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// segment is a part of a RichTextString that uses the same options.
+type segment struct {
+	text string
+	opts Options
+}
+
+// segments splits the RichTextString into parts that use the same options.
+func segments(rts *RichTextString) []segment {
+	var (
+		segs []segment
+		cur  Options
+	)
+	text := rts.Text()
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		o := rts.Opts(i)
+		if i < len(text) && (o == nil || *o == cur) {
+			continue
+		}
+		if i > start {
+			segs = append(segs, segment{text: text[start:i], opts: cur})
+		}
+		if o != nil {
+			cur = *o
+		}
+		start = i
+	}
+	return segs
+}
+
+func TestParseANSI(t *testing.T) {
+	tests := []struct {
+		desc    string
+		text    string
+		want    []segment
+		wantErr bool
+	}{
+		{
+			desc: "empty text",
+			text: "",
+		},
+		{
+			desc: "text without escape sequences",
+			text: "hello",
+			want: []segment{
+				{text: "hello"},
+			},
+		},
+		{
+			desc: "16 color foreground and background",
+			text: "a\x1b[31mb\x1b[42mc\x1b[0md",
+			want: []segment{
+				{text: "a"},
+				{text: "b", opts: Options{FgColor: ColorMaroon}},
+				{text: "c", opts: Options{FgColor: ColorMaroon, BgColor: ColorGreen}},
+				{text: "d"},
+			},
+		},
+		{
+			desc: "bright colors",
+			text: "\x1b[91;104mab",
+			want: []segment{
+				{text: "ab", opts: Options{FgColor: ColorRed, BgColor: ColorBlue}},
+			},
+		},
+		{
+			desc: "256 and 24 bit colors",
+			text: "\x1b[38;5;196ma\x1b[48;2;1;2;3mb",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorNumber(196)}},
+				{text: "b", opts: Options{FgColor: ColorNumber(196), BgColor: ColorRGB24(1, 2, 3)}},
+			},
+		},
+		{
+			desc: "default colors",
+			text: "\x1b[31;42ma\x1b[39mb\x1b[49mc",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorMaroon, BgColor: ColorGreen}},
+				{text: "b", opts: Options{BgColor: ColorGreen}},
+				{text: "c"},
+			},
+		},
+		{
+			desc: "attributes turned on and off",
+			text: "\x1b[1;4ma\x1b[22mb\x1b[24mc",
+			want: []segment{
+				{text: "a", opts: Options{Bold: true, Underline: true}},
+				{text: "b", opts: Options{Underline: true}},
+				{text: "c"},
+			},
+		},
+		{
+			desc: "all the supported attributes",
+			text: "\x1b[1;2;3;4;5;7;9ma\x1b[23;25;27;29mb",
+			want: []segment{
+				{text: "a", opts: Options{Bold: true, Dim: true, Italic: true, Underline: true, Blink: true, Inverse: true, Strikethrough: true}},
+				{text: "b", opts: Options{Bold: true, Dim: true, Underline: true}},
+			},
+		},
+		{
+			desc: "empty SGR sequence resets the options",
+			text: "\x1b[1ma\x1b[mb",
+			want: []segment{
+				{text: "a", opts: Options{Bold: true}},
+				{text: "b"},
+			},
+		},
+		{
+			desc: "ignores unsupported SGR codes",
+			text: "\x1b[53;1ma",
+			want: []segment{
+				{text: "a", opts: Options{Bold: true}},
+			},
+		},
+		{
+			desc: "removes other escape sequences",
+			text: "a\x1b[2Kb\x1b]0;title\ac\x1b]0;title\x1b\\d\x1b(Be",
+			want: []segment{
+				{text: "abcde"},
+			},
+		},
+		{
+			desc: "preserves full-width runes",
+			text: "\x1b[32m世界",
+			want: []segment{
+				{text: "世界", opts: Options{FgColor: ColorGreen}},
+			},
+		},
+		{
+			desc:    "fails on unterminated escape sequence",
+			text:    "a\x1b[31",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on escape at the end of the text",
+			text:    "a\x1b",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unterminated OSC sequence",
+			text:    "a\x1b]0;title",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on invalid SGR parameter",
+			text:    "\x1b[3;?1ma",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on missing 256 color number",
+			text:    "\x1b[38;5ma",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on 256 color number out of range",
+			text:    "\x1b[38;5;256ma",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on missing 24 bit color components",
+			text:    "\x1b[48;2;1;2ma",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on 24 bit color component out of range",
+			text:    "\x1b[48;2;1;2;300ma",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unsupported extended color type",
+			text:    "\x1b[38;3;1ma",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := ParseANSI(tc.text)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseANSI => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := pretty.Compare(tc.want, segments(got)); diff != "" {
+				t.Errorf("ParseANSI => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
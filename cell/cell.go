@@ -16,6 +16,9 @@
 package cell
 
 // Option is used to provide options for cells on a 2-D terminal.
+//
+// Options are applied in the order they are provided. When options conflict,
+// e.g. two FgColor options, the option provided last takes precedence.
 type Option interface {
 	// Set sets the provided option.
 	Set(*Options)
@@ -32,14 +35,21 @@ type Options struct {
 	Inverse       bool
 	Blink         bool
 	Dim           bool
+	// Link is the URL the cell's text links to. Terminals that support the
+	// OSC 8 escape sequence display the text as a clickable hyperlink. Only
+	// works when using the tcell backend, other backends ignore it.
+	Link string
 }
 
-// Set allows existing options to be passed as an option.
+// Set allows existing options to be passed as an option. All the fields are
+// copied, so the Options replace any options applied before them, including
+// the ones they leave at the zero value.
 func (o *Options) Set(other *Options) {
 	*other = *o
 }
 
-// NewOptions returns a new Options instance after applying the provided options.
+// NewOptions returns a new Options instance after applying the provided
+// options in order.
 func NewOptions(opts ...Option) *Options {
 	o := &Options{}
 	for _, opt := range opts {
@@ -119,16 +129,30 @@ func Dim() Option {
 	})
 }
 
+// Link makes the cell's text a hyperlink to the provided URL. Terminals that
+// support the OSC 8 escape sequence display the text as clickable, e.g. with
+// ctrl-click, which opens the URL in a browser. Only works when using the tcell
+// backend.
+func Link(url string) Option {
+	return option(func(co *Options) {
+		co.Link = url
+	})
+}
+
+// RichTextString is a text with cell options that can change along the text.
 type RichTextString struct {
 	text    string
 	opt     []*Options
 	fgColor Color
 }
 
+// Text returns the text without the options.
 func (this *RichTextString) Text() string {
 	return this.text
 }
 
+// Opts returns the options that take effect at the byte offset in the text,
+// or nil if the options in effect don't change at the offset.
 func (this *RichTextString) Opts(offset int) *Options {
 	if offset >= len(this.opt) {
 		return nil
@@ -136,23 +160,30 @@ func (this *RichTextString) Opts(offset int) *Options {
 	return this.opt[offset]
 }
 
+// AddText appends the text, which uses the options currently in effect.
 func (this *RichTextString) AddText(txt string) *RichTextString {
 	this.text = this.text + txt
 	return this
 }
 
+// ResetColor sets the foreground color of text added from now on back to the
+// default provided to NewRichTextString.
 func (this *RichTextString) ResetColor() *RichTextString {
 	newOpt := FgColor(this.fgColor)
 	this.AddOpt(newOpt)
 	return this
 }
 
+// SetFgColor sets the foreground color of text added from now on.
 func (this *RichTextString) SetFgColor(clr Color) *RichTextString {
 	newOpt := FgColor(clr)
 	this.AddOpt(newOpt)
 	return this
 }
 
+// AddOpt applies the option to text added from now on, on top of the options
+// already in effect. Options added without any text added in between are
+// applied in order, so the option added last takes precedence on conflicts.
 func (this *RichTextString) AddOpt(opt Option) *RichTextString {
 	txtlen := len(this.text)
 
@@ -188,6 +219,8 @@ func (this *RichTextString) AddOpt(opt Option) *RichTextString {
 	return this
 }
 
+// NewRichTextString returns a new empty RichTextString that uses the provided
+// foreground color until a different one is set.
 func NewRichTextString(defaultFgColor Color) *RichTextString {
 	text := &RichTextString{
 		text:    "",
@@ -94,6 +94,55 @@ func TestNewOptions(t *testing.T) {
 				Dim:           true,
 			},
 		},
+		{
+			desc: "the last of conflicting options takes precedence",
+			opts: []Option{
+				FgColor(ColorRed),
+				BgColor(ColorBlue),
+				FgColor(ColorGreen),
+				BgColor(ColorYellow),
+			},
+			want: &Options{
+				FgColor: ColorGreen,
+				BgColor: ColorYellow,
+			},
+		},
+		{
+			desc: "setting a link",
+			opts: []Option{
+				Link("https://example.com"),
+			},
+			want: &Options{
+				Link: "https://example.com",
+			},
+		},
+		{
+			desc: "options struct replaces all options provided before it",
+			opts: []Option{
+				FgColor(ColorRed),
+				Bold(),
+				&Options{
+					BgColor: ColorBlue,
+				},
+			},
+			want: &Options{
+				BgColor: ColorBlue,
+			},
+		},
+		{
+			desc: "options provided after the options struct override it",
+			opts: []Option{
+				&Options{
+					FgColor: ColorRed,
+					BgColor: ColorBlue,
+				},
+				FgColor(ColorGreen),
+			},
+			want: &Options{
+				FgColor: ColorGreen,
+				BgColor: ColorBlue,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -105,3 +154,70 @@ func TestNewOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRichTextStringAddOpt(t *testing.T) {
+	tests := []struct {
+		desc string
+		// build builds the rich text string.
+		build func(*RichTextString)
+		// want are the options at the byte offsets of the text.
+		want map[int]*Options
+	}{
+		{
+			desc:  "default color applies from the start",
+			build: func(rts *RichTextString) { rts.AddText("ab") },
+			want: map[int]*Options{
+				0: {FgColor: ColorWhite},
+				1: nil,
+			},
+		},
+		{
+			desc: "the last of conflicting options at the same offset takes precedence",
+			build: func(rts *RichTextString) {
+				rts.SetFgColor(ColorRed).AddOpt(BgColor(ColorBlue)).SetFgColor(ColorGreen).AddText("a")
+			},
+			want: map[int]*Options{
+				0: {FgColor: ColorGreen, BgColor: ColorBlue},
+			},
+		},
+		{
+			desc: "options added later apply on top of the earlier ones",
+			build: func(rts *RichTextString) {
+				rts.AddOpt(BgColor(ColorBlue)).AddOpt(Bold()).AddText("a")
+				rts.SetFgColor(ColorRed).AddText("b")
+				rts.ResetColor().AddText("c")
+			},
+			want: map[int]*Options{
+				0: {FgColor: ColorWhite, BgColor: ColorBlue, Bold: true},
+				1: {FgColor: ColorRed, BgColor: ColorBlue, Bold: true},
+				2: {FgColor: ColorWhite, BgColor: ColorBlue, Bold: true},
+			},
+		},
+		{
+			desc: "options added later don't modify the earlier text",
+			build: func(rts *RichTextString) {
+				rts.AddText("a")
+				rts.AddOpt(Bold()).AddText("b")
+			},
+			want: map[int]*Options{
+				0: {FgColor: ColorWhite},
+				1: {FgColor: ColorWhite, Bold: true},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			rts := NewRichTextString(ColorWhite)
+			tc.build(rts)
+
+			got := map[int]*Options{}
+			for offset := range tc.want {
+				got[offset] = rts.Opts(offset)
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Opts => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package cell
 
 import (
 	"fmt"
+	"math"
 )
 
 // color.go defines constants for cell colors.
@@ -28,6 +29,9 @@ func (cc Color) String() string {
 	if n, ok := colorNames[cc]; ok {
 		return n
 	}
+	if r, g, b, ok := cc.RGB24(); ok {
+		return fmt.Sprintf("ColorRGB24(%d, %d, %d)", r, g, b)
+	}
 	return fmt.Sprintf("Color:%d", cc)
 }
 
@@ -106,18 +110,159 @@ func ColorRGB6(r, g, b int) Color {
 	return Color(0x10 + 36*r + 6*g + b + 1) // Colors are off-by-one due to ColorDefault being zero.
 }
 
+// rgb24Flag marks colors created by ColorRGB24. The bits below the flag hold
+// the red, green and blue components of the color.
+const rgb24Flag Color = 1 << 24
+
 // ColorRGB24 sets a color using the 24 bit web color scheme.
-// Make sure your terminal is set to the terminalapi.ColorMode256 mode.
 // The provided values (r, g, b) must be in the range 0-255.
 // Larger or smaller values will be reset to the default color.
 //
-// For reference on these colors see the RGB column in:
-// https://jonasjacek.github.io/colors/
+// The tcell backend displays these colors in the terminalapi.ColorMode256
+// mode, tcell itself falls back to the nearest color on terminals that don't
+// support 24 bit colors. In all the other cases, the nearest of the 256
+// terminal colors is displayed instead, see Nearest256.
 func ColorRGB24(r, g, b int) Color {
 	for _, c := range []int{r, g, b} {
 		if c < 0 || c > 255 {
 			return ColorDefault
 		}
 	}
-	return ColorRGB6(r/51, g/51, b/51)
+	return rgb24Flag | Color(r<<16|g<<8|b)
+}
+
+// RGB24 returns the red, green and blue components of a color created by
+// ColorRGB24. Returns false for all other colors.
+func (cc Color) RGB24() (r, g, b int, ok bool) {
+	if cc < rgb24Flag || cc >= rgb24Flag<<1 {
+		return 0, 0, 0, false
+	}
+	v := int(cc - rgb24Flag)
+	return v >> 16, v >> 8 & 0xff, v & 0xff, true
+}
+
+// Nearest256 returns the one of the 256 terminal colors that is the closest to
+// the provided color created by ColorRGB24. The 16 Xterm colors are never
+// returned, since terminal themes often change them. All the other colors are
+// returned unchanged.
+func Nearest256(c Color) Color {
+	r, g, b, ok := c.RGB24()
+	if !ok {
+		return c
+	}
+
+	cube := ColorRGB6(nearestLevel(r), nearestLevel(g), nearestLevel(b))
+	// The shades of grey start at value 8 and increment by 10.
+	shade := int(math.Round((float64(r+g+b)/3 - 8) / 10))
+	if shade < 0 {
+		shade = 0
+	}
+	if shade > 23 {
+		shade = 23
+	}
+	grey := ColorNumber(232 + shade)
+
+	if distance(c, grey) < distance(c, cube) {
+		return grey
+	}
+	return cube
+}
+
+// nearestLevel returns the index of the value in cubeLevels that is the
+// closest to the provided color component.
+func nearestLevel(v int) int {
+	nearest := 0
+	for i, l := range cubeLevels {
+		if abs(v-l) < abs(v-cubeLevels[nearest]) {
+			nearest = i
+		}
+	}
+	return nearest
+}
+
+// distance returns the squared euclidean distance of the two colors in the
+// RGB space.
+func distance(c1, c2 Color) int {
+	r1, g1, b1, _ := c1.RGB()
+	r2, g2, b2, _ := c2.RGB()
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
+
+// abs returns the absolute value of the integer.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// xterm16 are the RGB values of the 16 Xterm colors.
+var xterm16 = [16][3]int{
+	{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+	{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+	{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the values of the individual components in the 6x6x6
+// terminal colors.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// RGB returns the red, green and blue components of the color as displayed
+// by Xterm. Returns false for the ColorDefault and for invalid colors, since
+// their appearance depends on the terminal.
+func (cc Color) RGB() (r, g, b int, ok bool) {
+	if r, g, b, ok := cc.RGB24(); ok {
+		return r, g, b, true
+	}
+	n := int(cc) - 1 // Colors are off-by-one due to ColorDefault being zero.
+	switch {
+	case n < 0 || n > 255:
+		return 0, 0, 0, false
+	case n < 16:
+		v := xterm16[n]
+		return v[0], v[1], v[2], true
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6], true
+	default:
+		v := 8 + (n-232)*10
+		return v, v, v, true
+	}
+}
+
+// luminance returns the relative luminance of the RGB color in the range
+// 0-255 as defined by ITU-R BT.709.
+func luminance(r, g, b int) float64 {
+	return 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)
+}
+
+// Grayscale returns the shade of grey that has the same luminance as the
+// provided color. The returned color is one of the 24 shades of grey, black
+// or white of the 256 terminal colors, so the terminal must be set to
+// terminalapi.ColorMode256.
+// The ColorDefault is returned unchanged.
+func Grayscale(c Color) Color {
+	r, g, b, ok := c.RGB()
+	if !ok {
+		return c
+	}
+
+	l := luminance(r, g, b)
+	switch {
+	case l < 4:
+		return ColorNumber(16) // Black in the 6x6x6 colors.
+	case l > 243:
+		return ColorNumber(231) // White in the 6x6x6 colors.
+	}
+	// The shades of grey start at value 8 and increment by 10.
+	shade := int(math.Round((l - 8) / 10))
+	if shade < 0 {
+		shade = 0
+	}
+	if shade > 23 {
+		shade = 23
+	}
+	return ColorNumber(232 + shade)
 }
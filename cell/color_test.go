@@ -180,27 +180,239 @@ func TestColorRGB24(t *testing.T) {
 			b:    256,
 			want: ColorDefault,
 		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := ColorRGB24(tc.r, tc.g, tc.b)
+			if got != tc.want {
+				t.Errorf("ColorRGB24(%v, %v, %v) => %v, want %v", tc.r, tc.g, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRGB24(t *testing.T) {
+	tests := []struct {
+		desc    string
+		color   Color
+		wantR   int
+		wantG   int
+		wantB   int
+		wantOK  bool
+		wantStr string
+	}{
 		{
-			desc: "translates black",
-			r:    0,
-			g:    0,
-			b:    0,
-			want: Color(17),
+			desc:    "not a 24 bit color",
+			color:   ColorNumber(255),
+			wantStr: "Color:256",
 		},
 		{
-			desc: "adds one to value",
-			r:    95,
-			g:    255,
-			b:    135,
-			want: Color(85),
+			desc:    "the default color",
+			color:   ColorDefault,
+			wantStr: "ColorDefault",
+		},
+		{
+			desc:    "black",
+			color:   ColorRGB24(0, 0, 0),
+			wantOK:  true,
+			wantStr: "ColorRGB24(0, 0, 0)",
+		},
+		{
+			desc:    "white",
+			color:   ColorRGB24(255, 255, 255),
+			wantR:   255,
+			wantG:   255,
+			wantB:   255,
+			wantOK:  true,
+			wantStr: "ColorRGB24(255, 255, 255)",
+		},
+		{
+			desc:    "distinct components",
+			color:   ColorRGB24(1, 128, 254),
+			wantR:   1,
+			wantG:   128,
+			wantB:   254,
+			wantOK:  true,
+			wantStr: "ColorRGB24(1, 128, 254)",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			got := ColorRGB24(tc.r, tc.g, tc.b)
+			r, g, b, ok := tc.color.RGB24()
+			if r != tc.wantR || g != tc.wantG || b != tc.wantB || ok != tc.wantOK {
+				t.Errorf("RGB24 => (%d, %d, %d, %v), want (%d, %d, %d, %v)", r, g, b, ok, tc.wantR, tc.wantG, tc.wantB, tc.wantOK)
+			}
+			if got := tc.color.String(); got != tc.wantStr {
+				t.Errorf("String => %q, want %q", got, tc.wantStr)
+			}
+		})
+	}
+}
+
+func TestRGB(t *testing.T) {
+	tests := []struct {
+		desc                string
+		color               Color
+		wantR, wantG, wantB int
+		wantOK              bool
+	}{
+		{
+			desc:  "default color has no RGB values",
+			color: ColorDefault,
+		},
+		{
+			desc:  "invalid color has no RGB values",
+			color: Color(257),
+		},
+		{
+			desc:   "one of the Xterm colors",
+			color:  ColorRed,
+			wantR:  255,
+			wantOK: true,
+		},
+		{
+			desc:   "one of the 6x6x6 colors",
+			color:  ColorRGB6(1, 2, 5),
+			wantR:  95,
+			wantG:  135,
+			wantB:  255,
+			wantOK: true,
+		},
+		{
+			desc:   "one of the shades of grey",
+			color:  ColorNumber(233),
+			wantR:  18,
+			wantG:  18,
+			wantB:  18,
+			wantOK: true,
+		},
+		{
+			desc:   "24 bit color",
+			color:  ColorRGB24(1, 2, 3),
+			wantR:  1,
+			wantG:  2,
+			wantB:  3,
+			wantOK: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r, g, b, ok := tc.color.RGB()
+			if r != tc.wantR || g != tc.wantG || b != tc.wantB || ok != tc.wantOK {
+				t.Errorf("RGB => (%d, %d, %d, %v), want (%d, %d, %d, %v)", r, g, b, ok, tc.wantR, tc.wantG, tc.wantB, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestNearest256(t *testing.T) {
+	tests := []struct {
+		desc  string
+		color Color
+		want  Color
+	}{
+		{
+			desc:  "returns other colors unchanged",
+			color: ColorRed,
+			want:  ColorRed,
+		},
+		{
+			desc:  "black",
+			color: ColorRGB24(0, 0, 0),
+			want:  ColorNumber(16),
+		},
+		{
+			desc:  "white",
+			color: ColorRGB24(255, 255, 255),
+			want:  ColorNumber(231),
+		},
+		{
+			desc:  "exact match in the 6x6x6 colors",
+			color: ColorRGB24(95, 255, 135),
+			want:  ColorRGB6(1, 5, 2),
+		},
+		{
+			desc:  "rounds to the nearest of the 6x6x6 colors",
+			color: ColorRGB24(100, 250, 130),
+			want:  ColorRGB6(1, 5, 2),
+		},
+		{
+			desc:  "prefers a closer shade of grey",
+			color: ColorRGB24(58, 58, 58),
+			want:  ColorNumber(237),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := Nearest256(tc.color); got != tc.want {
+				t.Errorf("Nearest256(%v) => %v, want %v", tc.color, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	tests := []struct {
+		desc  string
+		color Color
+		want  Color
+	}{
+		{
+			desc:  "default color is unchanged",
+			color: ColorDefault,
+			want:  ColorDefault,
+		},
+		{
+			desc:  "invalid color is unchanged",
+			color: Color(300),
+			want:  Color(300),
+		},
+		{
+			desc:  "black",
+			color: ColorBlack,
+			want:  ColorNumber(16),
+		},
+		{
+			desc:  "white",
+			color: ColorWhite,
+			want:  ColorNumber(231),
+		},
+		{
+			desc:  "red",
+			color: ColorRed,
+			want:  ColorNumber(237),
+		},
+		{
+			desc:  "blue",
+			color: ColorBlue,
+			want:  ColorNumber(233),
+		},
+		{
+			desc:  "gray",
+			color: ColorGray,
+			want:  ColorNumber(244),
+		},
+		{
+			desc:  "color from the 6x6x6 cube",
+			color: ColorRGB6(0, 5, 0),
+			want:  ColorNumber(249),
+		},
+		{
+			desc:  "shade of grey is unchanged",
+			color: ColorNumber(240),
+			want:  ColorNumber(240),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := Grayscale(tc.color)
 			if got != tc.want {
-				t.Errorf("ColorRGB24(%v, %v, %v) => %v, want %v", tc.r, tc.g, tc.b, got, tc.want)
+				t.Errorf("Grayscale(%v) => %v, want %v", tc.color, got, tc.want)
 			}
 		})
 	}
@@ -0,0 +1,201 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cell
+
+// markup.go contains code that converts text with style tags into a
+// RichTextString.
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseMarkup converts text containing style tags into a RichTextString.
+//
+// A style tag is enclosed in square brackets and has the form
+// "[foreground:background:flags]", the background and the flags can be
+// omitted. Each field is either empty which keeps its current value, "-"
+// which resets it to the default, or:
+//
+//   - a color for the foreground and the background. Either one of the color
+//     names, e.g. "red" or "navy", one of the 256 terminal colors prefixed
+//     with "color", e.g. "color196", or a 24 bit color, e.g. "#ff8700".
+//   - a sequence of flags, each lower case letter turns an attribute on and the
+//     corresponding upper case letter turns it off: "b" bold, "d" dim,
+//     "i" italic, "l" blink, "r" inverse, "s" strikethrough and "u" underline.
+//
+// For example:
+//
+//	ParseMarkup("[red::b]error[-:-:-] occurred")
+//
+// Text in square brackets that isn't a valid tag is kept as is, e.g.
+// "[INFO]". To display text that would otherwise be a tag, insert an opening
+// bracket before the closing one, "[red[]" displays as "[red]".
+func ParseMarkup(s string) *RichTextString {
+	rts := NewRichTextString(ColorDefault)
+	cur := Options{}
+	var text strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '[' {
+			text.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i+1:], ']')
+		if end < 0 {
+			text.WriteByte(s[i])
+			i++
+			continue
+		}
+		tag := s[i+1 : i+1+end]
+		next := i + 1 + end + 1
+
+		if escaped := strings.TrimSuffix(tag, "["); escaped != tag && escaped != "" && !strings.Contains(escaped, "[") {
+			text.WriteString("[" + escaped + "]")
+			i = next
+			continue
+		}
+
+		opts, ok := applyTag(cur, tag)
+		if !ok {
+			text.WriteByte(s[i])
+			i++
+			continue
+		}
+		if opts != cur {
+			if text.Len() > 0 {
+				rts.AddText(text.String())
+				text.Reset()
+			}
+			cur = opts
+			o := cur
+			rts.AddOpt(&o)
+		}
+		i = next
+	}
+	if text.Len() > 0 {
+		rts.AddText(text.String())
+	}
+	return rts
+}
+
+// markupColors maps color names usable in style tags to colors.
+var markupColors = map[string]Color{
+	"black":   ColorBlack,
+	"maroon":  ColorMaroon,
+	"green":   ColorGreen,
+	"olive":   ColorOlive,
+	"navy":    ColorNavy,
+	"purple":  ColorPurple,
+	"teal":    ColorTeal,
+	"silver":  ColorSilver,
+	"gray":    ColorGray,
+	"grey":    ColorGray,
+	"red":     ColorRed,
+	"lime":    ColorLime,
+	"yellow":  ColorYellow,
+	"blue":    ColorBlue,
+	"fuchsia": ColorFuchsia,
+	"aqua":    ColorAqua,
+	"white":   ColorWhite,
+	"magenta": ColorMagenta,
+	"cyan":    ColorCyan,
+	"default": ColorDefault,
+}
+
+// markupColor parses a color in a style tag. Returns false if the color
+// isn't valid.
+func markupColor(s string) (Color, bool) {
+	if c, ok := markupColors[strings.ToLower(s)]; ok {
+		return c, true
+	}
+	if strings.HasPrefix(s, "#") && len(s) == 7 {
+		v, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		return ColorRGB24(int(v>>16), int(v>>8&0xff), int(v&0xff)), true
+	}
+	if num := strings.TrimPrefix(strings.ToLower(s), "color"); num != strings.ToLower(s) {
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 0 || n > 255 || num[0] == '+' {
+			return 0, false
+		}
+		return ColorNumber(n), true
+	}
+	return 0, false
+}
+
+// markupFlags maps flags in style tags to the attributes they set.
+var markupFlags = map[byte]func(*Options) *bool{
+	'b': func(o *Options) *bool { return &o.Bold },
+	'd': func(o *Options) *bool { return &o.Dim },
+	'i': func(o *Options) *bool { return &o.Italic },
+	'l': func(o *Options) *bool { return &o.Blink },
+	'r': func(o *Options) *bool { return &o.Inverse },
+	's': func(o *Options) *bool { return &o.Strikethrough },
+	'u': func(o *Options) *bool { return &o.Underline },
+}
+
+// applyTag returns the options after applying the style tag onto them.
+// Returns false if the text isn't a valid style tag.
+func applyTag(opts Options, tag string) (Options, bool) {
+	fields := strings.Split(tag, ":")
+	if tag == "" || len(fields) > 3 {
+		return opts, false
+	}
+
+	colors := []*Color{&opts.FgColor, &opts.BgColor}
+	for i, f := range fields {
+		if i >= len(colors) {
+			break
+		}
+		switch f {
+		case "":
+		case "-":
+			*colors[i] = ColorDefault
+		default:
+			c, ok := markupColor(f)
+			if !ok {
+				return opts, false
+			}
+			*colors[i] = c
+		}
+	}
+
+	if len(fields) < 3 {
+		return opts, true
+	}
+	if flags := fields[2]; flags == "-" {
+		for _, attr := range markupFlags {
+			*attr(&opts) = false
+		}
+	} else {
+		for i := 0; i < len(flags); i++ {
+			f := flags[i]
+			on := f >= 'a' && f <= 'z'
+			if !on {
+				f = f - 'A' + 'a'
+			}
+			attr, ok := markupFlags[f]
+			if !ok {
+				return opts, false
+			}
+			*attr(&opts) = on
+		}
+	}
+	return opts, true
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cell
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		desc string
+		text string
+		want []segment
+	}{
+		{
+			desc: "empty text",
+			text: "",
+		},
+		{
+			desc: "text without tags",
+			text: "hello",
+			want: []segment{
+				{text: "hello"},
+			},
+		},
+		{
+			desc: "foreground color and reset",
+			text: "[red]error[-] occurred",
+			want: []segment{
+				{text: "error", opts: Options{FgColor: ColorRed}},
+				{text: " occurred"},
+			},
+		},
+		{
+			desc: "foreground, background and flags",
+			text: "[red:blue:bu]a[::B]b[-:-:-]c",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorRed, BgColor: ColorBlue, Bold: true, Underline: true}},
+				{text: "b", opts: Options{FgColor: ColorRed, BgColor: ColorBlue, Underline: true}},
+				{text: "c"},
+			},
+		},
+		{
+			desc: "empty fields keep the current values",
+			text: "[red::b]a[:green]b[::i]c",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorRed, Bold: true}},
+				{text: "b", opts: Options{FgColor: ColorRed, BgColor: ColorGreen, Bold: true}},
+				{text: "c", opts: Options{FgColor: ColorRed, BgColor: ColorGreen, Bold: true, Italic: true}},
+			},
+		},
+		{
+			desc: "all the flags",
+			text: "[::bdilrsu]a[::-]b",
+			want: []segment{
+				{text: "a", opts: Options{Bold: true, Dim: true, Italic: true, Blink: true, Inverse: true, Strikethrough: true, Underline: true}},
+				{text: "b"},
+			},
+		},
+		{
+			desc: "numbered and 24 bit colors",
+			text: "[color196:#ff8700]a",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorNumber(196), BgColor: ColorRGB24(0xff, 0x87, 0x00)}},
+			},
+		},
+		{
+			desc: "color names are case insensitive",
+			text: "[Yellow]a",
+			want: []segment{
+				{text: "a", opts: Options{FgColor: ColorYellow}},
+			},
+		},
+		{
+			desc: "keeps text in brackets that isn't a tag",
+			text: "[INFO] [1] [] [a:b:c:d] [::x] [color256] [#ff] done",
+			want: []segment{
+				{text: "[INFO] [1] [] [a:b:c:d] [::x] [color256] [#ff] done"},
+			},
+		},
+		{
+			desc: "keeps unterminated brackets",
+			text: "a[red",
+			want: []segment{
+				{text: "a[red"},
+			},
+		},
+		{
+			desc: "tag after an opening bracket",
+			text: "[[red]a]",
+			want: []segment{
+				{text: "["},
+				{text: "a]", opts: Options{FgColor: ColorRed}},
+			},
+		},
+		{
+			desc: "escaped tag",
+			text: "[red[]a",
+			want: []segment{
+				{text: "[red]a"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := ParseMarkup(tc.text)
+			if diff := pretty.Compare(tc.want, segments(got)); diff != "" {
+				t.Errorf("ParseMarkup => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
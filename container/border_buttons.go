@@ -0,0 +1,97 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// border_buttons.go contains code that draws and tracks clicks on buttons
+// placed onto the container border.
+
+import (
+	"image"
+
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/button"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// borderButton is a button placed onto the top border of a container.
+type borderButton struct {
+	BorderButton
+
+	// fsm tracks mouse clicks on the button.
+	fsm *button.FSM
+}
+
+// newBorderButton returns a new borderButton.
+func newBorderButton(bb BorderButton) *borderButton {
+	return &borderButton{
+		BorderButton: bb,
+		fsm:          button.NewFSM(mouse.ButtonLeft, image.ZR),
+	}
+}
+
+// borderButtonAreas returns the areas of the border buttons that fit onto the
+// container's top border, in the same order as the buttons. Each button
+// occupies one cell and the buttons are separated by a single border cell.
+// Returns nil if the container has no border.
+func borderButtonAreas(c *Container) []image.Rectangle {
+	if !c.hasBorder() || len(c.opts.borderButtons) == 0 {
+		return nil
+	}
+
+	var areas []image.Rectangle
+	for i := range c.opts.borderButtons {
+		// One cell for the top right corner and one separator cell before
+		// each button.
+		x := c.area.Max.X - 2 - 2*i
+		// Leave at least the top left corner and one more cell of the border.
+		if x <= c.area.Min.X+1 {
+			break
+		}
+		areas = append(areas, image.Rect(x, c.area.Min.Y, x+1, c.area.Min.Y+1))
+	}
+	return areas
+}
+
+// borderButtonsReserve returns the number of cells on the top border occupied
+// by the border buttons.
+func borderButtonsReserve(c *Container) int {
+	return 2 * len(borderButtonAreas(c))
+}
+
+// borderButtonClicks processes the mouse event on behalf of the border
+// buttons in the container tree and returns the OnClick functions of all the
+// buttons that were clicked.
+// Caller must hold c.mu.
+func borderButtonClicks(c *Container, m *terminalapi.Mouse) []func() error {
+	var (
+		errStr  string
+		clicked []func() error
+	)
+	preOrder(c, &errStr, visitFunc(func(cur *Container) error {
+		areas := borderButtonAreas(cur)
+		for i, bb := range cur.opts.borderButtons {
+			if i < len(areas) {
+				bb.fsm.UpdateArea(areas[i])
+			} else {
+				bb.fsm.UpdateArea(image.ZR)
+			}
+			if click, _ := bb.fsm.Event(cur.localMouse(m, false)); click {
+				clicked = append(clicked, bb.OnClick)
+			}
+		}
+		return nil
+	}))
+	return clicked
+}
@@ -26,12 +26,16 @@ import (
 	"fmt"
 	"image"
 	"sync"
+	"sync/atomic"
 
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/linestyle"
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/damage"
 	"github.com/mum4k/termdash/private/event"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -53,6 +57,11 @@ type Container struct {
 	// All containers in the tree share the same tracker.
 	focusTracker *focusTracker
 
+	// damage tracks the content last set on the terminal, so that only the
+	// cells that changed get set when redrawing.
+	// All containers in the tree share the same tracker.
+	damage *damage.Tracker
+
 	// area is the area of the terminal this container has access to.
 	// Initialized the first time Draw is called.
 	area image.Rectangle
@@ -66,9 +75,50 @@ type Container struct {
 	// have changed.
 	clearNeeded bool
 
+	// redraw holds the func() that requests a redraw of the dashboard when
+	// the tree is marked dirty. Only used on the root container.
+	redraw atomic.Value
+	// drawing is set to one while the tree is being drawn. Only used on the
+	// root container, accessed atomically.
+	drawing int32
+	// dirtySet indicates if the widget in this container was already
+	// provided with the function that marks it dirty.
+	dirtySet bool
+	// widgetDirty is set to one when the widget in this container reported
+	// a change since it was last drawn, accessed atomically.
+	widgetDirty int32
+	// drawn is the widget as it was last drawn, nil if it must be drawn
+	// again regardless of the changes it reported.
+	drawn *drawnWidget
+	// drawAll is set to one when all the widgets must be drawn again, e.g.
+	// after MarkDirty was called by the application. Only used on the root
+	// container, accessed atomically.
+	drawAll int32
+	// dirty is set to one when anything in the container tree changed since
+	// the last Draw. Only used on the root container, accessed atomically.
+	dirty int32
+
+	// theme is the theme of the dashboard, nil if not set. Only set on the
+	// root container.
+	theme *theme.Theme
+
+	// modal is the modal displayed above the layout, nil if not displayed.
+	// Only set on the root container.
+	modal *modal
+	// layers are the layers displayed above the layout ordered by their
+	// z-index. Only set on the root container.
+	layers []*layer
+	// dragged is the container whose split is being dragged with the mouse,
+	// nil if none. Only set on the root container.
+	dragged *Container
+
 	// mu protects the container tree.
 	// All containers in the tree share the same lock.
 	mu *sync.Mutex
+	// batchMu is held while drawing the container tree and while the
+	// function provided to Batch runs.
+	// All containers in the tree share the same lock.
+	batchMu *sync.Mutex
 }
 
 // String represents the container metadata in a human readable format.
@@ -81,9 +131,12 @@ func (c *Container) String() string {
 // applies the provided options.
 func New(t terminalapi.Terminal, opts ...Option) (*Container, error) {
 	root := &Container{
-		term: t,
-		opts: newOptions( /* parent = */ nil),
-		mu:   &sync.Mutex{},
+		term:    t,
+		opts:    newOptions( /* parent = */ nil),
+		damage:  damage.New(),
+		dirty:   1, // Nothing was drawn yet.
+		mu:      &sync.Mutex{},
+		batchMu: &sync.Mutex{},
 	}
 
 	// Initially the root is focused.
@@ -103,8 +156,10 @@ func newChild(parent *Container, opts []Option) (*Container, error) {
 		parent:       parent,
 		term:         parent.term,
 		focusTracker: parent.focusTracker,
+		damage:       parent.damage,
 		opts:         newOptions(parent.opts),
 		mu:           parent.mu,
+		batchMu:      parent.batchMu,
 	}
 	if err := applyOptions(child, opts...); err != nil {
 		return nil, err
@@ -138,6 +193,26 @@ func (c *Container) usable() image.Rectangle {
 	return c.area
 }
 
+// tooSmall determines if the area is smaller than the minimum size of the
+// container set by MinSizeCells.
+func (c *Container) tooSmall(ar image.Rectangle) bool {
+	min := c.opts.minSize
+	return (min.X > 0 && ar.Dx() < min.X) || (min.Y > 0 && ar.Dy() < min.Y)
+}
+
+// constrain returns the top left part of the area that fits into the maximum
+// size of the container set by MaxSizeCells.
+func (c *Container) constrain(ar image.Rectangle) image.Rectangle {
+	max := c.opts.maxSize
+	if max.X > 0 && ar.Dx() > max.X {
+		ar.Max.X = ar.Min.X + max.X
+	}
+	if max.Y > 0 && ar.Dy() > max.Y {
+		ar.Max.Y = ar.Min.Y + max.Y
+	}
+	return ar
+}
+
 // widgetArea returns the area in the container that is available for the
 // widget's canvas. Takes the container border, widget's requested maximum size
 // and ratio and container's alignment into account.
@@ -147,7 +222,7 @@ func (c *Container) widgetArea() (image.Rectangle, error) {
 		return image.ZR, nil
 	}
 
-	padded, err := c.opts.padding.apply(c.usable())
+	padded, err := c.contentArea()
 	if err != nil {
 		return image.ZR, err
 	}
@@ -174,15 +249,26 @@ func (c *Container) widgetArea() (image.Rectangle, error) {
 // split splits the container's usable area into child areas.
 // Panics if the container isn't configured for a split.
 func (c *Container) split() (image.Rectangle, image.Rectangle, error) {
-	ar, err := c.opts.padding.apply(c.usable())
+	ar, err := c.contentArea()
 	if err != nil {
 		return image.ZR, image.ZR, err
 	}
 	if c.opts.splitFixed > DefaultSplitFixed {
+		cells := c.opts.splitFixed
+		if c.opts.splitFixedFromEnd {
+			size := ar.Dy()
+			if c.opts.split == splitTypeVertical {
+				size = ar.Dx()
+			}
+			cells = size - cells
+			if cells < 0 {
+				cells = 0
+			}
+		}
 		if c.opts.split == splitTypeVertical {
-			return area.VSplitCells(ar, c.opts.splitFixed)
+			return area.VSplitCells(ar, cells)
 		}
-		return area.HSplitCells(ar, c.opts.splitFixed)
+		return area.HSplitCells(ar, cells)
 	}
 
 	if c.opts.split == splitTypeVertical {
@@ -212,7 +298,10 @@ func (c *Container) createSecond(opts []Option) error {
 }
 
 // Draw draws this container and all of its sub containers.
+// Blocks while a function provided to Batch runs.
 func (c *Container) Draw() error {
+	c.batchMu.Lock()
+	defer c.batchMu.Unlock()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -220,6 +309,7 @@ func (c *Container) Draw() error {
 		if err := c.term.Clear(); err != nil {
 			return fmt.Errorf("term.Clear => error: %v", err)
 		}
+		c.damage.Invalidate()
 		c.clearNeeded = false
 	}
 
@@ -233,6 +323,111 @@ func (c *Container) Draw() error {
 	return drawTree(c)
 }
 
+// MarkDirty marks the container tree as changed, so that all of its widgets
+// get drawn again, including those that implement widgetapi.DirtyReporter and
+// didn't report a change. Widgets that implement widgetapi.DirtyReporter and
+// changes of the layout mark the tree automatically, this is only needed
+// after changes that termdash doesn't see, e.g. of a widget that doesn't
+// report them.
+// This method is thread-safe and never blocks.
+func (c *Container) MarkDirty() {
+	atomic.StoreInt32(&rootCont(c).drawAll, 1)
+	c.markTreeDirty()
+}
+
+// markWidgetDirty marks the widget in this container and the container tree
+// as changed. Provided to widgets that implement widgetapi.DirtyReporter.
+// This method is thread-safe and never blocks.
+func (c *Container) markWidgetDirty() {
+	atomic.StoreInt32(&c.widgetDirty, 1)
+	c.markTreeDirty()
+}
+
+// widgetChanged marks the widget in this container as changed without
+// requesting a redraw. Used after the widget received an input event, which
+// widgets don't need to report, since each is followed by a redraw.
+// This method is thread-safe and never blocks.
+func (c *Container) widgetChanged() {
+	atomic.StoreInt32(&c.widgetDirty, 1)
+}
+
+// markTreeDirty marks the container tree as changed, so that it gets drawn
+// again when using the termdash.OnDemandRedraw option.
+// This method is thread-safe and never blocks.
+func (c *Container) markTreeDirty() {
+	root := rootCont(c)
+	atomic.StoreInt32(&root.dirty, 1)
+	if atomic.LoadInt32(&root.drawing) == 1 {
+		// Changes made while drawing are drawn by the next periodic redraw,
+		// requesting a redraw now would redraw animations continuously.
+		return
+	}
+	if redraw, ok := root.redraw.Load().(func()); ok && redraw != nil {
+		redraw()
+	}
+}
+
+// Dirty determines if the container tree needs to be drawn again, i.e. if
+// anything changed since the last Draw or if any of the widgets don't
+// implement widgetapi.DirtyReporter.
+// This method is private to termdash, stability isn't guaranteed and changes
+// won't be backward compatible.
+func (c *Container) Dirty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root := rootCont(c)
+	if root.clearNeeded || atomic.LoadInt32(&root.dirty) == 1 {
+		return true
+	}
+	var (
+		dirty  bool
+		errStr string
+	)
+	visit := visitFunc(func(c *Container) error {
+		if !c.hasWidget() {
+			return nil
+		}
+		if _, ok := c.opts.widget.(widgetapi.DirtyReporter); !ok {
+			dirty = true
+		}
+		return nil
+	})
+	preOrder(root, &errStr, visit)
+	if root.modal != nil {
+		preOrder(root.modal.cont, &errStr, visit)
+	}
+	for _, l := range root.layers {
+		preOrder(l.cont, &errStr, visit)
+	}
+	return dirty
+}
+
+// Batch calls the provided function and prevents the container tree from
+// being drawn until it returns. Changes made to multiple widgets or to the
+// layout inside the function are then displayed together, instead of risking
+// a redraw that shows only some of them. Marks the container tree dirty once
+// the function returns.
+// The function must not call Draw or Batch, which would deadlock.
+// This method is thread-safe.
+func (c *Container) Batch(fn func()) {
+	c.batchMu.Lock()
+	fn()
+	c.batchMu.Unlock()
+
+	c.MarkDirty()
+}
+
+// Invalidate makes the next call to Draw set all the cells of the terminal.
+// Draw only sets the cells whose content changed since they were last set,
+// so Invalidate must be called after the terminal was cleared or modified by
+// anything other than the container.
+func (c *Container) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.damage.Invalidate()
+}
+
 // Update updates container with the specified id by setting the provided
 // options. This can be used to perform dynamic layout changes, i.e. anything
 // between replacing the widget in the container and completely changing the
@@ -247,6 +442,12 @@ func (c *Container) Update(id string, opts ...Option) error {
 	if err != nil {
 		return err
 	}
+	return c.update(target, opts...)
+}
+
+// update applies the options to the target container in the tree.
+// Caller must hold c.mu.
+func (c *Container) update(target *Container, opts ...Option) error {
 	c.clearNeeded = true
 
 	if err := applyOptions(target, opts...); err != nil {
@@ -257,9 +458,13 @@ func (c *Container) Update(id string, opts ...Option) error {
 	}
 
 	// The currently focused container might not be reachable anymore, because
-	// it was under the target. If that is so, move the focus up to the target.
-	if !c.focusTracker.reachableFrom(c) {
+	// it was under the target. If that is so, move the focus up to the target
+	// or to the modal if the target is in the layout below it.
+	if ers := eventRoots(c); !c.focusTracker.reachableFrom(ers...) {
 		c.focusTracker.setActive(target)
+		if !c.focusTracker.reachableFrom(ers...) {
+			c.focusTracker.setActive(ers[0])
+		}
 	}
 	return nil
 }
@@ -331,17 +536,43 @@ func (c *Container) processEvent(ev terminalapi.Event) error {
 // Also processes the event on behalf of the container (tracks keyboard focus).
 // Caller must hold c.mu.
 func (c *Container) prepareEvTargets(ev terminalapi.Event) (func() error, error) {
+	// While a modal is displayed, only the modal receives events.
 	switch e := ev.(type) {
 	case *terminalapi.Mouse:
+		// Only the top-most tree under the pointer receives the event.
+		er := mouseRoot(c, e.Position)
+		dragged, err := dragSplits(er, e)
+		if err != nil {
+			return nil, err
+		}
+		if dragged {
+			return func() error { return nil }, nil
+		}
 		c.updateFocusFromMouse(ev.(*terminalapi.Mouse))
+		scrollFromMouse(er, e)
 
-		targets, err := c.mouseEvTargets(e)
-		if err != nil {
+		var targets []*mouseEvTarget
+		for _, r := range eventRoots(c) {
+			ts, err := r.mouseEvTargets(e, r != er)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, ts...)
+		}
+		clicked := borderButtonClicks(er, e)
+		if err := tabClicks(er, e); err != nil {
 			return nil, err
 		}
 		return func() error {
+			for _, fn := range clicked {
+				if err := fn(); err != nil {
+					return err
+				}
+			}
 			for _, mt := range targets {
-				if err := mt.widget.Mouse(mt.ev, mt.meta); err != nil {
+				err := mt.widget.Mouse(mt.ev, mt.meta)
+				mt.cont.widgetChanged()
+				if err != nil {
 					return err
 				}
 			}
@@ -349,12 +580,34 @@ func (c *Container) prepareEvTargets(ev terminalapi.Event) (func() error, error)
 		}, nil
 
 	case *terminalapi.Keyboard:
+		focused := c.focusTracker.active()
 		c.updateFocusFromKeyboard(ev.(*terminalapi.Keyboard))
+		if active := c.focusTracker.active(); active != focused {
+			active.scrollIntoView()
+		}
+		tr := treeRoot(c.focusTracker.active())
+		tr.updateTabsFromKeyboard(e)
+		tr.updateScrollFromKeyboard(e)
 
 		targets := c.keyEvTargets()
 		return func() error {
 			for _, kt := range targets {
-				if err := kt.widget.Keyboard(e, kt.meta); err != nil {
+				err := kt.widget.Keyboard(e, kt.meta)
+				kt.cont.widgetChanged()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+
+	case *terminalapi.Paste:
+		targets := c.keyEvTargets()
+		return func() error {
+			for _, kt := range targets {
+				err := paste(kt.widget, e, kt.meta)
+				kt.cont.widgetChanged()
+				if err != nil {
 					return err
 				}
 			}
@@ -366,38 +619,70 @@ func (c *Container) prepareEvTargets(ev terminalapi.Event) (func() error, error)
 	}
 }
 
+// paste delivers the pasted text to the widget. Widgets that don't implement
+// widgetapi.Paster receive the text as a sequence of keyboard events.
+func paste(w widgetapi.Widget, p *terminalapi.Paste, meta *widgetapi.EventMeta) error {
+	if pw, ok := w.(widgetapi.Paster); ok {
+		return pw.Paste(p, meta)
+	}
+
+	runes := []rune(p.Text)
+	for i, r := range runes {
+		key := keyboard.Key(r)
+		switch r {
+		case '\r':
+			key = keyboard.KeyEnter
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				continue
+			}
+		case '\n':
+			key = keyboard.KeyEnter
+		case '\t':
+			key = keyboard.KeyTab
+		}
+		if err := w.Keyboard(&terminalapi.Keyboard{Key: key, Time: p.Time}, meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // keyEvTarget contains a widget that should receive an event and the metadata
 // for the event.
 type keyEvTarget struct {
+	// cont is the container of the widget.
+	cont *Container
 	// widget is the widget that should receive the keyboard event.
 	widget widgetapi.Widget
 	// meta is the metadata about the event.
 	meta *widgetapi.EventMeta
 }
 
-// newKeyEvTarget returns a new keyEvTarget.
-func newKeyEvTarget(w widgetapi.Widget, meta *widgetapi.EventMeta) *keyEvTarget {
+// newKeyEvTarget returns a new keyEvTarget for the widget in the container.
+// Caller must hold c.mu.
+func newKeyEvTarget(c *Container, meta *widgetapi.EventMeta) *keyEvTarget {
 	return &keyEvTarget{
-		widget: w,
+		cont:   c,
+		widget: c.opts.widget,
 		meta:   meta,
 	}
 }
 
-// keyEvTargets returns those widgets found in the container that should
-// receive this keyboard event.
+// keyEvTargets returns those widgets found in the trees that receive keyboard
+// events that should receive this keyboard event.
 // Caller must hold c.mu.
 func (c *Container) keyEvTargets() []*keyEvTarget {
 	var (
 		errStr  string
 		targets []*keyEvTarget
 		// If the currently focused widget set the ExclusiveKeyboardOnFocus
-		// option, this pointer is set to that widget.
-		exclusiveWidget widgetapi.Widget
+		// option, this pointer is set to its container.
+		exclusiveCont *Container
 	)
 
 	// All the targets that should receive this event.
 	// For now stable ordering (preOrder).
-	preOrder(c, &errStr, visitFunc(func(cur *Container) error {
+	visit := visitFunc(func(cur *Container) error {
 		if !cur.hasWidget() {
 			return nil
 		}
@@ -408,7 +693,7 @@ func (c *Container) keyEvTargets() []*keyEvTarget {
 		}
 		wOpt := cur.opts.widget.Options()
 		if focused && wOpt.ExclusiveKeyboardOnFocus {
-			exclusiveWidget = cur.opts.widget
+			exclusiveCont = cur
 		}
 
 		switch wOpt.WantKeyboard {
@@ -418,18 +703,21 @@ func (c *Container) keyEvTargets() []*keyEvTarget {
 
 		case widgetapi.KeyScopeFocused:
 			if focused {
-				targets = append(targets, newKeyEvTarget(cur.opts.widget, meta))
+				targets = append(targets, newKeyEvTarget(cur, meta))
 			}
 
 		case widgetapi.KeyScopeGlobal:
-			targets = append(targets, newKeyEvTarget(cur.opts.widget, meta))
+			targets = append(targets, newKeyEvTarget(cur, meta))
 		}
 		return nil
-	}))
+	})
+	for _, r := range eventRoots(c) {
+		preOrder(r, &errStr, visit)
+	}
 
-	if exclusiveWidget != nil {
+	if exclusiveCont != nil {
 		targets = []*keyEvTarget{
-			newKeyEvTarget(exclusiveWidget, &widgetapi.EventMeta{Focused: true}),
+			newKeyEvTarget(exclusiveCont, &widgetapi.EventMeta{Focused: true}),
 		}
 	}
 	return targets
@@ -438,6 +726,8 @@ func (c *Container) keyEvTargets() []*keyEvTarget {
 // mouseEvTarget contains a mouse event adjusted relative to the widget's area,
 // the widget that should receive it and metadata about the event.
 type mouseEvTarget struct {
+	// cont is the container of the widget.
+	cont *Container
 	// widget is the widget that should receive the mouse event.
 	widget widgetapi.Widget
 	// ev is the adjusted mouse event.
@@ -446,19 +736,24 @@ type mouseEvTarget struct {
 	meta *widgetapi.EventMeta
 }
 
-// newMouseEvTarget returns a new mouseEvTarget.
-func newMouseEvTarget(w widgetapi.Widget, wArea image.Rectangle, ev *terminalapi.Mouse, meta *widgetapi.EventMeta) *mouseEvTarget {
+// newMouseEvTarget returns a new mouseEvTarget for the widget in the
+// container.
+// Caller must hold c.mu.
+func newMouseEvTarget(c *Container, wArea image.Rectangle, ev *terminalapi.Mouse, meta *widgetapi.EventMeta) *mouseEvTarget {
 	return &mouseEvTarget{
-		widget: w,
+		cont:   c,
+		widget: c.opts.widget,
 		ev:     adjustMouseEv(ev, wArea),
 		meta:   meta,
 	}
 }
 
 // mouseEvTargets returns those widgets found in the container that should
-// receive this mouse event.
+// receive this mouse event. If occluded is true, the event landed on a tree
+// displayed above this container and only the widgets that want all the mouse
+// events receive it.
 // Caller must hold c.mu.
-func (c *Container) mouseEvTargets(m *terminalapi.Mouse) ([]*mouseEvTarget, error) {
+func (c *Container) mouseEvTargets(m *terminalapi.Mouse, occluded bool) ([]*mouseEvTarget, error) {
 	var (
 		errStr  string
 		widgets []*mouseEvTarget
@@ -472,6 +767,9 @@ func (c *Container) mouseEvTargets(m *terminalapi.Mouse) ([]*mouseEvTarget, erro
 		}
 
 		wOpts := cur.opts.widget.Options()
+		if occluded && wOpts.WantMouse != widgetapi.MouseScopeGlobal {
+			return nil
+		}
 		wa, err := cur.widgetArea()
 		if err != nil {
 			return err
@@ -480,6 +778,9 @@ func (c *Container) mouseEvTargets(m *terminalapi.Mouse) ([]*mouseEvTarget, erro
 		meta := &widgetapi.EventMeta{
 			Focused: cur.focusTracker.isActive(cur),
 		}
+		// The widget and its container can be in the content of containers
+		// with the Scrollable option.
+		wm := cur.localMouse(m, true)
 		switch wOpts.WantMouse {
 		case widgetapi.MouseScopeNone:
 			// Widget doesn't want any mouse events.
@@ -487,19 +788,19 @@ func (c *Container) mouseEvTargets(m *terminalapi.Mouse) ([]*mouseEvTarget, erro
 
 		case widgetapi.MouseScopeWidget:
 			// Only if the event falls inside of the widget's canvas.
-			if m.Position.In(wa) {
-				widgets = append(widgets, newMouseEvTarget(cur.opts.widget, wa, m, meta))
+			if wm.Position.In(wa) {
+				widgets = append(widgets, newMouseEvTarget(cur, wa, wm, meta))
 			}
 
 		case widgetapi.MouseScopeContainer:
 			// Only if the event falls inside the widget's parent container.
-			if m.Position.In(cur.area) {
-				widgets = append(widgets, newMouseEvTarget(cur.opts.widget, wa, m, meta))
+			if cur.localMouse(m, false).Position.In(cur.area) {
+				widgets = append(widgets, newMouseEvTarget(cur, wa, wm, meta))
 			}
 
 		case widgetapi.MouseScopeGlobal:
 			// Widget wants all mouse events.
-			widgets = append(widgets, newMouseEvTarget(cur.opts.widget, wa, m, meta))
+			widgets = append(widgets, newMouseEvTarget(cur, wa, wm, meta))
 		}
 		return nil
 	}))
@@ -526,6 +827,7 @@ func (c *Container) Subscribe(eds *event.DistributionSystem) {
 	want := []terminalapi.Event{
 		&terminalapi.Keyboard{},
 		&terminalapi.Mouse{},
+		&terminalapi.Paste{},
 	}
 	eds.Subscribe(want, func(ev terminalapi.Event) {
 		if err := c.processEvent(ev); err != nil {
@@ -534,20 +836,35 @@ func (c *Container) Subscribe(eds *event.DistributionSystem) {
 	}, event.MaxRepetitive(maxReps))
 }
 
+// SetRedrawFunc sets the function that requests a redraw of the dashboard.
+// It is called when the container tree is marked dirty outside of Draw.
+// This method is private to termdash, stability isn't guaranteed and changes
+// won't be backward compatible.
+func (c *Container) SetRedrawFunc(f func()) {
+	rootCont(c).redraw.Store(f)
+}
+
+// SetTheme sets the theme of the dashboard. The theme provides the border
+// colors of the containers that don't have them set explicitly and is passed
+// to the widgets when they are drawn.
+// This method is private to termdash, stability isn't guaranteed and changes
+// won't be backward compatible.
+func (c *Container) SetTheme(t *theme.Theme) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rootCont(c).theme = t
+}
+
 // adjustMouseEv adjusts the mouse event relative to the widget area.
 func adjustMouseEv(m *terminalapi.Mouse, wArea image.Rectangle) *terminalapi.Mouse {
 	// The sent mouse coordinate is relative to the widget canvas, i.e. zero
 	// based, even though the widget might not be in the top left corner on the
 	// terminal.
-	offset := wArea.Min
-	if m.Position.In(wArea) {
-		return &terminalapi.Mouse{
-			Position: m.Position.Sub(offset),
-			Button:   m.Button,
-		}
-	}
+	pos, _ := widgetapi.LocalMouse(wArea, m)
 	return &terminalapi.Mouse{
-		Position: image.Point{-1, -1},
+		Position: pos,
 		Button:   m.Button,
+		Time:     m.Time,
 	}
 }
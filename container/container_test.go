@@ -15,17 +15,21 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/linestyle"
 	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/testcanvas"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/draw/testdraw"
@@ -34,6 +38,7 @@ import (
 	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/private/fakewidget"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 	"github.com/mum4k/termdash/widgets/barchart"
 )
@@ -91,6 +96,30 @@ func TestNew(t *testing.T) {
 			},
 			wantContainerErr: true,
 		},
+		{
+			desc:     "fails on BorderButtons with a full-width rune",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, BorderButtons(BorderButton{Rune: '世', OnClick: func() error { return nil }}))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails on BorderButtons without the OnClick function",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, BorderButtons(BorderButton{Rune: 'x'}))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails on Responsive without the LayoutFn",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Responsive(nil))
+			},
+			wantContainerErr: true,
+		},
 		{
 			desc:     "fails on invalid option on the first vertical child container",
 			termSize: image.Point{10, 10},
@@ -155,6 +184,48 @@ func TestNew(t *testing.T) {
 			},
 			wantContainerErr: true,
 		},
+		{
+			desc:     "fails on negative MinSizeCells",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, MinSizeCells(-1, 0))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails on negative MaxSizeCells",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, MaxSizeCells(0, -1))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails when MinSizeCells is larger than MaxSizeCells",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, MaxSizeCells(5, 5), MinSizeCells(6, 0))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails when MaxSizeCells is smaller than MinSizeCells",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, MinSizeCells(0, 6), MaxSizeCells(5, 5))
+			},
+			wantContainerErr: true,
+		},
+		{
+			desc:     "fails on full-width BorderChars rune",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				chars := linestyle.ASCIIBorder
+				chars.Top = '世'
+				return New(ft, BorderChars(chars))
+			},
+			wantContainerErr: true,
+		},
 		{
 			desc:     "fails on MarginTopPercent too low",
 			termSize: image.Point{10, 10},
@@ -529,6 +600,25 @@ func TestNew(t *testing.T) {
 			},
 			wantContainerErr: true,
 		},
+		{
+			desc:     "fails on negative SplitFixedFromEnd",
+			termSize: image.Point{10, 20},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitHorizontal(
+						Top(
+							Border(linestyle.Light),
+						),
+						Bottom(
+							Border(linestyle.Light),
+						),
+						SplitFixedFromEnd(-1),
+					),
+				)
+			},
+			wantContainerErr: true,
+		},
 		{
 			desc:     "fails on KeyFocusGroups with a negative group",
 			termSize: image.Point{10, 20},
@@ -540,6 +630,17 @@ func TestNew(t *testing.T) {
 			},
 			wantContainerErr: true,
 		},
+		{
+			desc:     "fails on TabIndex with a negative index",
+			termSize: image.Point{10, 20},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					TabIndex(-1),
+				)
+			},
+			wantContainerErr: true,
+		},
 		{
 			desc:     "fails on KeyFocusGroupsNext with a negative group",
 			termSize: image.Point{10, 20},
@@ -890,6 +991,83 @@ func TestNew(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "vertical fixed split from the end",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitVertical(
+						Left(
+							Border(linestyle.Light),
+						),
+						Right(
+							Border(linestyle.Light),
+						),
+						SplitFixedFromEnd(4),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 16, 10))
+				testdraw.MustBorder(cvs, image.Rect(16, 0, 20, 10))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "horizontal fixed split from the end",
+			termSize: image.Point{10, 20},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitHorizontal(
+						Top(
+							Border(linestyle.Light),
+						),
+						Bottom(
+							Border(linestyle.Light),
+						),
+						SplitFixedFromEnd(4),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 10, 16))
+				testdraw.MustBorder(cvs, image.Rect(0, 16, 10, 20))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "fixed split from the end larger than the container",
+			termSize: image.Point{10, 20},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitHorizontal(
+						Top(
+							Border(linestyle.Light),
+						),
+						Bottom(
+							Border(linestyle.Light),
+						),
+						SplitFixedFromEnd(30),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 10, 20))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "vertical split, parent and children have borders",
 			termSize: image.Point{10, 10},
@@ -1003,6 +1181,54 @@ func TestNew(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "uses border colors from the theme",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(
+					ft,
+					Border(linestyle.Light),
+					SplitVertical(
+						Left(
+							Border(linestyle.Light),
+						),
+						Right(
+							Border(linestyle.Light),
+							BorderColor(cell.ColorRed),
+						),
+					),
+				)
+				if err != nil {
+					return nil, err
+				}
+				c.SetTheme(&theme.Theme{
+					Border:        cell.ColorGreen,
+					FocusedBorder: cell.ColorMagenta,
+				})
+				return c, nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(
+					cvs,
+					image.Rect(0, 0, 10, 10),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorMagenta)),
+				)
+				testdraw.MustBorder(
+					cvs,
+					image.Rect(1, 1, 5, 9),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorGreen)),
+				)
+				testdraw.MustBorder(
+					cvs,
+					image.Rect(5, 1, 9, 9),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorRed)),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "sets border title on root container of different color",
 			termSize: image.Point{10, 10},
@@ -1680,6 +1906,100 @@ func TestKeyboard(t *testing.T) {
 	}
 }
 
+// keyRecorder is a widget that records the keyboard events it receives.
+type keyRecorder struct {
+	mu   sync.Mutex
+	keys []keyboard.Key
+}
+
+func (*keyRecorder) Draw(*canvas.Canvas, *widgetapi.Meta) error { return nil }
+func (kr *keyRecorder) Keyboard(k *terminalapi.Keyboard, _ *widgetapi.EventMeta) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.keys = append(kr.keys, k.Key)
+	return nil
+}
+func (*keyRecorder) Mouse(*terminalapi.Mouse, *widgetapi.EventMeta) error { return nil }
+func (*keyRecorder) Options() widgetapi.Options {
+	return widgetapi.Options{WantKeyboard: widgetapi.KeyScopeFocused}
+}
+
+// pasteRecorder is a widget that records the pasted text it receives.
+type pasteRecorder struct {
+	keyRecorder
+	pasted []string
+}
+
+func (pr *pasteRecorder) Paste(p *terminalapi.Paste, _ *widgetapi.EventMeta) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.pasted = append(pr.pasted, p.Text)
+	return nil
+}
+
+func TestPaste(t *testing.T) {
+	tests := []struct {
+		desc       string
+		widget     widgetapi.Widget
+		text       string
+		wantKeys   []keyboard.Key
+		wantPasted []string
+	}{
+		{
+			desc:     "replays the text as keyboard events",
+			widget:   &keyRecorder{},
+			text:     "a\r\nb\tc\n",
+			wantKeys: []keyboard.Key{'a', keyboard.KeyEnter, 'b', keyboard.KeyTab, 'c', keyboard.KeyEnter},
+		},
+		{
+			desc:       "delivers the text to widgets that implement Paster",
+			widget:     &pasteRecorder{},
+			text:       "a\nb",
+			wantPasted: []string{"a\nb"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			ft, err := faketerm.New(image.Point{10, 10})
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			c, err := New(ft, PlaceWidget(tc.widget))
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			eds := event.NewDistributionSystem()
+			c.Subscribe(eds)
+			eds.Event(&terminalapi.Paste{Text: tc.text})
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), 1; got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			var kr *keyRecorder
+			var pasted []string
+			switch w := tc.widget.(type) {
+			case *keyRecorder:
+				kr = w
+			case *pasteRecorder:
+				kr = &w.keyRecorder
+				pasted = w.pasted
+			}
+			if diff := pretty.Compare(tc.wantKeys, kr.keys); diff != "" {
+				t.Errorf("Paste => unexpected keys diff (-want, +got):\n%s", diff)
+			}
+			if diff := pretty.Compare(tc.wantPasted, pasted); diff != "" {
+				t.Errorf("Paste => unexpected pasted diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestMouse(t *testing.T) {
 	tests := []struct {
 		desc      string
@@ -1717,6 +2037,67 @@ func TestMouse(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "click on a border button calls its OnClick function",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					BorderButtons(
+						BorderButton{Rune: 'x', OnClick: func() error { return errors.New("clicked x") }},
+					),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{8, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{8, 0}, Button: mouse.ButtonRelease},
+			},
+			// The returned error is reported as an additional event.
+			wantProcessed: 3,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustSetCell(cvs, image.Point{8, 0}, 'x', cell.FgColor(cell.ColorYellow))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "click outside of a border button doesn't call its OnClick function",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					BorderButtons(
+						BorderButton{Rune: 'x', OnClick: func() error { return errors.New("clicked x") }},
+					),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{7, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{7, 0}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustSetCell(cvs, image.Point{8, 0}, 'x', cell.FgColor(cell.ColorYellow))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "event not forwarded if container has no widget",
 			termSize: image.Point{10, 10},
@@ -3053,3 +3434,211 @@ func TestUpdate(t *testing.T) {
 	}
 
 }
+
+// dirtyWidget is a fakewidget.Mirror that implements
+// widgetapi.DirtyReporter.
+type dirtyWidget struct {
+	*fakewidget.Mirror
+
+	mu        sync.Mutex
+	markDirty func()
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (dw *dirtyWidget) SetDirtyFunc(markDirty func()) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.markDirty = markDirty
+}
+
+func TestDirty(t *testing.T) {
+	ft, err := faketerm.New(image.Point{40, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+
+	dw := &dirtyWidget{Mirror: fakewidget.New(widgetapi.Options{})}
+	cont, err := New(ft, ID("root"), SplitVertical(
+		Left(ID("left"), PlaceWidget(dw)),
+		Right(ID("right")),
+	))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		desc      string
+		action    func() error
+		wantDirty bool
+	}{
+		{
+			desc:      "dirty before the first draw",
+			action:    func() error { return nil },
+			wantDirty: true,
+		},
+		{
+			desc:   "clean after a draw",
+			action: cont.Draw,
+		},
+		{
+			desc: "dirty when the widget reports a change",
+			action: func() error {
+				dw.markDirty()
+				return nil
+			},
+			wantDirty: true,
+		},
+		{
+			desc:   "clean after drawing the change",
+			action: cont.Draw,
+		},
+		{
+			desc: "dirty when marked by the application",
+			action: func() error {
+				cont.MarkDirty()
+				return nil
+			},
+			wantDirty: true,
+		},
+		{
+			desc: "dirty after an update of the layout",
+			action: func() error {
+				if err := cont.Draw(); err != nil {
+					return err
+				}
+				return cont.Update("right", Border(linestyle.Light))
+			},
+			wantDirty: true,
+		},
+		{
+			desc: "always dirty with a widget that doesn't report changes",
+			action: func() error {
+				if err := cont.Update("right", PlaceWidget(fakewidget.New(widgetapi.Options{}))); err != nil {
+					return err
+				}
+				return cont.Draw()
+			},
+			wantDirty: true,
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.action(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.desc, err)
+		}
+		if got := cont.Dirty(); got != step.wantDirty {
+			t.Errorf("%s: Dirty => %v, want %v", step.desc, got, step.wantDirty)
+		}
+	}
+}
+
+// animatedWidget is a dirtyWidget that marks itself dirty each time it is
+// drawn, like an animation.
+type animatedWidget struct {
+	*dirtyWidget
+}
+
+// Draw implements widgetapi.Widget.Draw.
+func (aw *animatedWidget) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	aw.mu.Lock()
+	markDirty := aw.markDirty
+	aw.mu.Unlock()
+	if markDirty != nil {
+		markDirty()
+	}
+	return aw.dirtyWidget.Draw(cvs, meta)
+}
+
+func TestMarkDirtyRequestsRedraw(t *testing.T) {
+	ft, err := faketerm.New(image.Point{40, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+
+	dw := &dirtyWidget{Mirror: fakewidget.New(widgetapi.Options{})}
+	aw := &animatedWidget{&dirtyWidget{Mirror: fakewidget.New(widgetapi.Options{})}}
+	cont, err := New(ft, SplitVertical(
+		Left(PlaceWidget(dw)),
+		Right(PlaceWidget(aw)),
+	))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	// Nothing is requested without a redraw function.
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	dw.markDirty()
+
+	var requests int32
+	cont.SetRedrawFunc(func() { atomic.AddInt32(&requests, 1) })
+
+	// Changes reported while drawing, e.g. by the animation, don't request a
+	// redraw, the tree is drawn again by the next periodic redraw.
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(0); got != want {
+		t.Errorf("after Draw => requested %d redraws, want %d", got, want)
+	}
+	if !cont.Dirty() {
+		t.Errorf("after Draw => Dirty is false, want true for the animation")
+	}
+
+	dw.markDirty()
+	if got, want := atomic.LoadInt32(&requests), int32(1); got != want {
+		t.Errorf("widget change => requested %d redraws, want %d", got, want)
+	}
+	cont.MarkDirty()
+	if got, want := atomic.LoadInt32(&requests), int32(2); got != want {
+		t.Errorf("MarkDirty => requested %d redraws, want %d", got, want)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ft, err := faketerm.New(image.Point{10, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := New(ft, ID("root"))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	var requests int32
+	cont.SetRedrawFunc(func() { atomic.AddInt32(&requests, 1) })
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	batchDone := make(chan struct{})
+	go func() {
+		defer close(batchDone)
+		cont.Batch(func() {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	drawn := make(chan error)
+	go func() {
+		drawn <- cont.Draw()
+	}()
+	select {
+	case err := <-drawn:
+		t.Fatalf("Draw => returned %v while the batch was running, want it blocked", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-batchDone
+	if err := <-drawn; err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(1); got != want {
+		t.Errorf("Batch => requested %d redraws, want %d", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// debug.go contains code that draws the layout debugging overlay.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+)
+
+// DefaultDebugLayoutColor is the default color of the outlines drawn when the
+// DebugLayout option is provided.
+const DefaultDebugLayoutColor = cell.ColorMagenta
+
+// debugLabel returns the text that annotates the container in the layout
+// debugging overlay, i.e. its ID and size.
+func debugLabel(c *Container) string {
+	size := area.Size(c.area)
+	if c.opts.id == "" {
+		return fmt.Sprintf("%dx%d", size.X, size.Y)
+	}
+	return fmt.Sprintf("%s %dx%d", c.opts.id, size.X, size.Y)
+}
+
+// drawDebug outlines the container and annotates it with its ID and size.
+// Only the cells of the outline are drawn, the content of the container
+// remains visible.
+func drawDebug(c *Container) error {
+	if c.area.Dx() < 2 || c.area.Dy() < 2 {
+		return nil // No space for the outline.
+	}
+
+	cvs, err := canvas.New(c.area)
+	if err != nil {
+		return err
+	}
+	ar := cvs.Area()
+
+	cOpts := []cell.Option{cell.FgColor(c.opts.global.debugLayoutColor)}
+	if err := draw.Border(cvs, ar,
+		draw.BorderLineStyle(linestyle.Light),
+		draw.BorderTitle(debugLabel(c), draw.OverrunModeThreeDot, cOpts...),
+		draw.BorderCellOpts(cOpts...),
+	); err != nil {
+		return err
+	}
+
+	shift, clip, scrolled := c.view(false)
+	for _, p := range perimeter(ar) {
+		bc, err := cvs.Cell(p)
+		if err != nil {
+			return err
+		}
+		if bc.Rune == 0 {
+			continue // Cell following a full-width rune.
+		}
+		tp := p.Add(c.area.Min)
+		if scrolled {
+			if !tp.In(clip) {
+				continue
+			}
+			tp = tp.Sub(shift)
+		}
+		if err := c.damage.SetCell(tp, bc.Rune, bc.Opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// perimeter returns all the points on the edge of the area.
+func perimeter(ar image.Rectangle) []image.Point {
+	var points []image.Point
+	for x := ar.Min.X; x < ar.Max.X; x++ {
+		points = append(points, image.Point{x, ar.Min.Y}, image.Point{x, ar.Max.Y - 1})
+	}
+	for y := ar.Min.Y + 1; y < ar.Max.Y-1; y++ {
+		points = append(points, image.Point{ar.Min.X, y}, image.Point{ar.Max.X - 1, y})
+	}
+	return points
+}
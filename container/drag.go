@@ -0,0 +1,160 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// drag.go contains code that resizes draggable splits with the mouse.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// splitDrag stores the limits of a draggable split.
+type splitDrag struct {
+	// min and max limit the split percentage.
+	min int
+	max int
+}
+
+// SplitDraggable allows the user to resize the two sub containers at runtime
+// by grabbing the boundary between them with the left mouse button and
+// dragging it. The boundary consists of the last column (row) of the left
+// (top) container and the first column (row) of the right (bottom) container,
+// mouse events that grab or drag the boundary aren't delivered to widgets.
+//
+// While dragging, the split percentage is limited to the range
+// min <= p <= max, where 0 < min <= max < 100.
+// Cannot be combined with SplitFixed.
+func SplitDraggable(min, max int) SplitOption {
+	return splitOption(func(opts *options) error {
+		if min <= 0 || max >= 100 || min > max {
+			return fmt.Errorf("invalid SplitDraggable(min:%d, max:%d), must be in range 0 < min <= max < 100", min, max)
+		}
+		opts.splitDrag = &splitDrag{
+			min: min,
+			max: max,
+		}
+		return nil
+	})
+}
+
+// isDraggable determines if the container has a split that can be dragged.
+func (c *Container) isDraggable() bool {
+	return c.opts.splitDrag != nil && !c.isLeaf() && !c.isTabbed()
+}
+
+// grabbed determines if the point is on the boundary of the draggable split.
+func (c *Container) grabbed(p image.Point) (bool, error) {
+	first, second, err := c.split()
+	if err != nil {
+		return false, err
+	}
+	if c.opts.split == splitTypeVertical {
+		if p.Y < first.Min.Y || p.Y >= first.Max.Y {
+			return false, nil
+		}
+		return p.X == first.Max.X-1 || p.X == second.Min.X, nil
+	}
+	if p.X < first.Min.X || p.X >= first.Max.X {
+		return false, nil
+	}
+	return p.Y == first.Max.Y-1 || p.Y == second.Min.Y, nil
+}
+
+// dragTo moves the boundary of the draggable split so that the second sub
+// container starts at the point, within the limits of the split.
+func (c *Container) dragTo(p image.Point) error {
+	ar, err := c.contentArea()
+	if err != nil {
+		return err
+	}
+
+	var offset, size int
+	if c.opts.split == splitTypeVertical {
+		offset, size = p.X-ar.Min.X, ar.Dx()
+	} else {
+		offset, size = p.Y-ar.Min.Y, ar.Dy()
+	}
+	if size <= 0 {
+		return nil
+	}
+
+	// Round up so that the second container starts at the point.
+	perc := (offset*100 + size - 1) / size
+	if d := c.opts.splitDrag; perc < d.min {
+		perc = d.min
+	} else if perc > d.max {
+		perc = d.max
+	}
+	if perc != c.opts.splitPercent {
+		c.opts.splitPercent = perc
+		rootCont(c).clearNeeded = true
+	}
+	return nil
+}
+
+// dragSplits processes the mouse event on behalf of the draggable splits in
+// the container tree. Returns true if the event grabbed, dragged or released
+// a split, such events shouldn't be processed any further.
+// Caller must hold c.mu.
+func dragSplits(c *Container, m *terminalapi.Mouse) (bool, error) {
+	root := rootCont(c)
+	if d := root.dragged; d != nil {
+		if m.Button != mouse.ButtonLeft {
+			root.dragged = nil
+			return true, nil
+		}
+		p, _ := d.localPoint(m.Position, true)
+		return true, d.dragTo(p)
+	}
+	if m.Button != mouse.ButtonLeft {
+		return false, nil
+	}
+
+	var (
+		errStr string
+		target *Container
+	)
+	// Visits sub containers after their parents, so the innermost split wins.
+	preOrder(c, &errStr, visitFunc(func(cur *Container) error {
+		if !cur.isDraggable() {
+			return nil
+		}
+		p, ok := cur.localPoint(m.Position, true)
+		if !ok {
+			return nil
+		}
+		g, err := cur.grabbed(p)
+		if err != nil {
+			return err
+		}
+		if g {
+			target = cur
+		}
+		return nil
+	}))
+	if errStr != "" {
+		return false, errors.New(errStr)
+	}
+	if target == nil {
+		return false, nil
+	}
+	root.dragged = target
+	return true, nil
+}
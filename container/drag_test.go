@@ -0,0 +1,314 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustDrawBorders draws light borders around the provided areas.
+func mustDrawBorders(ft *faketerm.Terminal, areas ...image.Rectangle) {
+	cvs := testcanvas.MustNew(ft.Area())
+	for _, ar := range areas {
+		testdraw.MustBorder(cvs, ar)
+	}
+	testcanvas.MustApply(cvs, ft)
+}
+
+// drag returns mouse events that drag from the start to the end point.
+func drag(start, end image.Point) []terminalapi.Event {
+	return []terminalapi.Event{
+		&terminalapi.Mouse{Position: start, Button: mouse.ButtonLeft},
+		&terminalapi.Mouse{Position: end, Button: mouse.ButtonLeft},
+		&terminalapi.Mouse{Position: end, Button: mouse.ButtonRelease},
+	}
+}
+
+func TestSplitDraggable(t *testing.T) {
+	widgetOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeGlobal}
+
+	tests := []struct {
+		desc       string
+		termSize   image.Point
+		container  func(ft *faketerm.Terminal) (*Container, error)
+		events     []terminalapi.Event
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+	}{
+		{
+			desc:     "fails on min out of range",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(Left(), Right(), SplitDraggable(0, 50)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on max out of range",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(Left(), Right(), SplitDraggable(10, 100)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on min larger than max",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(Left(), Right(), SplitDraggable(60, 50)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails when combined with SplitFixed",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(Left(), Right(), SplitDraggable(10, 90), SplitFixed(5)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "drags the vertical split",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{10, 5}, image.Point{6, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 6, 10), image.Rect(6, 0, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "grabs the vertical split on the last column of the left container",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{9, 5}, image.Point{14, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 14, 10), image.Rect(14, 0, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "limits the split to the minimum",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+					SplitDraggable(20, 60),
+				))
+			},
+			events: drag(image.Point{10, 5}, image.Point{0, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 4, 10), image.Rect(4, 0, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "limits the split to the maximum",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+					SplitDraggable(20, 60),
+				))
+			},
+			events: drag(image.Point{10, 5}, image.Point{19, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 12, 10), image.Rect(12, 0, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "drags the horizontal split",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitHorizontal(
+					Top(Border(linestyle.Light)),
+					Bottom(Border(linestyle.Light)),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{5, 5}, image.Point{5, 7}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 20, 7), image.Rect(0, 7, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "doesn't drag splits that aren't draggable",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+				))
+			},
+			events: drag(image.Point{10, 5}, image.Point{6, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				// The click focuses the left container.
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 10, 10), draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+				testdraw.MustBorder(cvs, image.Rect(10, 0, 20, 10))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "doesn't drag when the press isn't on the boundary",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(Border(linestyle.Light)),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{3, 5}, image.Point{6, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				// The click focuses the left container.
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 10, 10), draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+				testdraw.MustBorder(cvs, image.Rect(10, 0, 20, 10))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "drags the innermost split",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(Border(linestyle.Light)),
+					Right(
+						SplitVertical(
+							Left(Border(linestyle.Light)),
+							Right(Border(linestyle.Light)),
+							SplitDraggable(10, 90),
+						),
+					),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{15, 5}, image.Point{17, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawBorders(ft, image.Rect(0, 0, 10, 10), image.Rect(10, 0, 17, 10), image.Rect(17, 0, 20, 10))
+				return ft
+			},
+		},
+		{
+			desc:     "widgets don't receive the mouse events that drag the split",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, SplitVertical(
+					Left(PlaceWidget(fakewidget.New(widgetOpts))),
+					Right(PlaceWidget(fakewidget.New(widgetOpts))),
+					SplitDraggable(10, 90),
+				))
+			},
+			events: drag(image.Point{10, 5}, image.Point{8, 5}),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 8, 10)), &widgetapi.Meta{}, widgetOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(8, 0, 20, 10)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if (err != nil) != tc.wantNewErr {
+				t.Fatalf("tc.container => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+			c.Subscribe(eds)
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
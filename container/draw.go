@@ -20,11 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"sync/atomic"
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -38,34 +40,171 @@ func drawTree(c *Container) error {
 	if err != nil {
 		return err
 	}
-	root.area = ar
+	root.area = root.constrain(ar)
+	root.damage.Resize(size)
+	// Changes made while drawing, e.g. by widgets that keep changing over
+	// time, make the tree dirty for the next Draw.
+	atomic.StoreInt32(&root.dirty, 0)
+	atomic.StoreInt32(&root.drawing, 1)
+	defer atomic.StoreInt32(&root.drawing, 0)
+	if atomic.SwapInt32(&root.drawAll, 0) == 1 {
+		forgetDrawn(root)
+	}
+
+	changed, err := applyLayouts(root)
+	if err != nil {
+		return err
+	}
+	if changed {
+		// The layout changed, remove anything drawn by the previous one.
+		if err := root.term.Clear(); err != nil {
+			return fmt.Errorf("term.Clear => error: %v", err)
+		}
+		root.damage.Invalidate()
+	}
 
 	preOrder(root, &errStr, visitFunc(func(c *Container) error {
-		first, second, err := c.split()
+		if err := setChildAreas(c); err != nil {
+			return err
+		}
+		return drawCont(c)
+	}))
+	if errStr != "" {
+		return errors.New(errStr)
+	}
+
+	if root.opts.global.debugLayout {
+		preOrder(root, &errStr, visitFunc(drawDebug))
+		if errStr != "" {
+			return errors.New(errStr)
+		}
+	}
+	if err := drawLayers(root); err != nil {
+		return err
+	}
+	if err := drawModal(root); err != nil {
+		return err
+	}
+	return root.damage.Flush(root.term)
+}
+
+// setChildAreas sets the areas of the sub containers of the container.
+// The sub containers of a container that is smaller than its minimum size get
+// zero areas.
+// When one of the sub containers of a split is smaller than its minimum size,
+// it collapses and the other one takes up the entire area of the split unless
+// it is too small for it as well.
+func setChildAreas(c *Container) error {
+	if c.tooSmall(c.area) {
+		for _, child := range []*Container{c.first, c.second} {
+			if child != nil {
+				child.area = image.ZR
+			}
+		}
+		return nil
+	}
+	if c.isScrollable() {
+		if err := c.updateViewport(); err != nil {
+			return err
+		}
+	}
+
+	if c.isTabbed() {
+		_, page, err := c.tabsAreas()
 		if err != nil {
 			return err
 		}
-		if c.first != nil {
-			ar, err := c.first.opts.margin.apply(first)
+		ar, err := c.first.opts.margin.apply(page)
+		if err != nil {
+			return err
+		}
+		c.first.area = c.first.constrain(ar)
+		return nil
+	}
+
+	first, second, err := c.split()
+	if err != nil {
+		return err
+	}
+	var firstAr, secondAr image.Rectangle
+	if c.first != nil {
+		if firstAr, err = c.first.opts.margin.apply(first); err != nil {
+			return err
+		}
+	}
+	if c.second != nil {
+		if secondAr, err = c.second.opts.margin.apply(second); err != nil {
+			return err
+		}
+	}
+
+	if c.first != nil && c.second != nil {
+		whole := first.Union(second)
+		switch {
+		case c.first.tooSmall(firstAr) && !c.second.tooSmall(secondAr):
+			ar, err := c.second.opts.margin.apply(whole)
 			if err != nil {
 				return err
 			}
-			c.first.area = ar
-		}
+			if !c.second.tooSmall(ar) {
+				firstAr, secondAr = image.ZR, ar
+			}
 
-		if c.second != nil {
-			ar, err := c.second.opts.margin.apply(second)
+		case c.second.tooSmall(secondAr) && !c.first.tooSmall(firstAr):
+			ar, err := c.first.opts.margin.apply(whole)
 			if err != nil {
 				return err
 			}
-			c.second.area = ar
+			if !c.first.tooSmall(ar) {
+				firstAr, secondAr = ar, image.ZR
+			}
 		}
-		return drawCont(c)
+	}
+
+	if c.first != nil {
+		c.first.area = c.first.constrain(firstAr)
+	}
+	if c.second != nil {
+		c.second.area = c.second.constrain(secondAr)
+	}
+	return nil
+}
+
+// applyLayouts applies the layouts selected by the LayoutFn of containers
+// that have the Responsive option whose size changed since the last time their
+// layout was selected. The area of the root container must be set.
+// Returns true if any of the layouts were applied.
+func applyLayouts(root *Container) (bool, error) {
+	var (
+		changed bool
+		errStr  string
+	)
+	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+		if fn := c.opts.layoutFn; fn != nil {
+			size := c.area.Size()
+			if last := c.opts.layoutSize; last == nil || *last != size {
+				if err := applyOptions(c, fn(size)...); err != nil {
+					return err
+				}
+				c.opts.layoutSize = &size
+				changed = true
+			}
+		}
+		return setChildAreas(c)
 	}))
 	if errStr != "" {
-		return errors.New(errStr)
+		return false, errors.New(errStr)
 	}
-	return nil
+	if !changed {
+		return false, nil
+	}
+
+	if err := validateOptions(root); err != nil {
+		return false, err
+	}
+	// The focused container might not exist in the new layout.
+	root.focusTracker.keepReachable(root)
+	return true, nil
 }
 
 // drawBorder draws the border around the container if requested.
@@ -86,14 +225,14 @@ func drawBorder(c *Container) error {
 
 	var cOpts, titleCOpts []cell.Option
 	if c.focusTracker.isActive(c) {
-		cOpts = append(cOpts, cell.FgColor(c.opts.inherited.focusedColor))
+		cOpts = append(cOpts, cell.FgColor(c.focusedColor()))
 		if c.opts.inherited.titleFocusedColor != nil {
 			titleCOpts = append(titleCOpts, cell.FgColor(*c.opts.inherited.titleFocusedColor))
 		} else {
 			titleCOpts = cOpts
 		}
 	} else {
-		cOpts = append(cOpts, cell.FgColor(c.opts.inherited.borderColor))
+		cOpts = append(cOpts, cell.FgColor(c.borderColor()))
 		if c.opts.inherited.titleColor != nil {
 			titleCOpts = append(titleCOpts, cell.FgColor(*c.opts.inherited.titleColor))
 		} else {
@@ -101,16 +240,49 @@ func drawBorder(c *Container) error {
 		}
 	}
 
-	if err := draw.Border(cvs, ar,
+	topCOpts := append(append([]cell.Option{}, titleCOpts...), c.opts.borderTitleCellOpts...)
+	bottomCOpts := append(append([]cell.Option{}, titleCOpts...), c.opts.borderBottomTitleCellOpts...)
+	bOpts := []draw.BorderOption{
 		draw.BorderLineStyle(c.opts.border),
-		draw.BorderTitle(c.opts.borderTitle, draw.OverrunModeThreeDot, titleCOpts...),
+		draw.BorderTitle(c.opts.borderTitle, draw.OverrunModeThreeDot, topCOpts...),
 		draw.RichBorderTitle(c.opts.richBorderTitle),
 		draw.BorderTitleAlign(c.opts.borderTitleHAlign),
+		draw.BorderTitleReserveRight(borderButtonsReserve(c)),
+		draw.BorderBottomTitle(c.opts.borderBottomTitle, draw.OverrunModeThreeDot, bottomCOpts...),
+		draw.BorderBottomTitleAlign(c.opts.borderBottomTitleHAlign),
 		draw.BorderCellOpts(cOpts...),
-	); err != nil {
+	}
+	if c.opts.borderChars != nil {
+		bOpts = append(bOpts, draw.BorderChars(*c.opts.borderChars))
+	}
+	if err := draw.Border(cvs, ar, bOpts...); err != nil {
 		return err
 	}
-	return cvs.Apply(c.term)
+
+	for i, bba := range borderButtonAreas(c) {
+		p := bba.Min.Sub(c.area.Min)
+		if _, err := cvs.SetCell(p, c.opts.borderButtons[i].Rune, titleCOpts...); err != nil {
+			return err
+		}
+	}
+	return c.applyCanvas(cvs, false)
+}
+
+// borderColor returns the color of the border when the container isn't
+// focused.
+func (c *Container) borderColor() cell.Color {
+	if t := rootCont(c).theme; t != nil && !c.opts.inherited.borderColorSet {
+		return t.Border
+	}
+	return c.opts.inherited.borderColor
+}
+
+// focusedColor returns the color of the border when the container is focused.
+func (c *Container) focusedColor() cell.Color {
+	if t := rootCont(c).theme; t != nil && !c.opts.inherited.focusedColorSet {
+		return t.FocusedBorder
+	}
+	return c.opts.inherited.focusedColor
 }
 
 // drawWidget requests the widget to draw on the canvas.
@@ -127,6 +299,13 @@ func drawWidget(c *Container) error {
 		return nil
 	}
 
+	dr, reports := c.opts.widget.(widgetapi.DirtyReporter)
+	if reports && !c.dirtySet {
+		dr.SetDirtyFunc(c.markWidgetDirty)
+		c.dirtySet = true
+		c.drawn = nil
+	}
+
 	needSize := image.Point{1, 1}
 	wOpts := c.opts.widget.Options()
 	if wOpts.MinimumSize.X > 0 && wOpts.MinimumSize.Y > 0 {
@@ -134,22 +313,72 @@ func drawWidget(c *Container) error {
 	}
 
 	if widgetArea.Dx() < needSize.X || widgetArea.Dy() < needSize.Y {
+		c.drawn = nil
 		return drawResize(c, c.usable())
 	}
 
-	cvs, err := canvas.New(widgetArea)
-	if err != nil {
-		return err
-	}
-
 	meta := &widgetapi.Meta{
 		Focused: c.focusTracker.isActive(c),
+		Theme:   rootCont(c).theme,
+		Area:    widgetArea,
+	}
+	if shift, _, ok := c.view(true); ok {
+		meta.Area = widgetArea.Sub(shift)
+	}
+	if pt, ok := c.term.(terminalapi.Passthrough); ok {
+		meta.Graphics = pt.Graphics()
 	}
 
+	// Widgets that didn't report any changes since they were last drawn with
+	// the same metadata aren't drawn again, their last canvas is reused.
+	changed := atomic.SwapInt32(&c.widgetDirty, 0) == 1
+	if d := c.drawn; reports && !changed && d != nil && d.meta == *meta && d.area == widgetArea {
+		return c.applyCanvas(d.cvs, true)
+	}
+
+	cvs, err := canvas.New(widgetArea)
+	if err != nil {
+		return err
+	}
 	if err := c.opts.widget.Draw(cvs, meta); err != nil {
+		c.drawn = nil
 		return err
 	}
-	return cvs.Apply(c.term)
+	if reports {
+		c.drawn = &drawnWidget{
+			cvs:  cvs,
+			area: widgetArea,
+			meta: *meta,
+		}
+	}
+	return c.applyCanvas(cvs, true)
+}
+
+// drawnWidget is a widget as it was last drawn.
+type drawnWidget struct {
+	// cvs is the canvas the widget drew on.
+	cvs *canvas.Canvas
+	// area is the area of the canvas on the terminal.
+	area image.Rectangle
+	// meta is the metadata the widget was drawn with.
+	meta widgetapi.Meta
+}
+
+// forgetDrawn makes all the widgets in the tree rooted at the container and
+// in its modal and layers draw again.
+func forgetDrawn(root *Container) {
+	var errStr string
+	visit := visitFunc(func(c *Container) error {
+		c.drawn = nil
+		return nil
+	})
+	preOrderAll(root, &errStr, visit)
+	if root.modal != nil {
+		preOrderAll(root.modal.cont, &errStr, visit)
+	}
+	for _, l := range root.layers {
+		preOrderAll(l.cont, &errStr, visit)
+	}
 }
 
 // drawResize draws an unicode character indicating that the size is too small to draw this container.
@@ -166,18 +395,38 @@ func drawResize(c *Container, area image.Rectangle) error {
 	if err := draw.ResizeNeeded(cvs); err != nil {
 		return err
 	}
-	return cvs.Apply(c.term)
+	return c.applyCanvas(cvs, false)
+}
+
+// drawViewport clears the viewport of a container with the Scrollable option,
+// so that parts of the content without a widget don't display what was
+// visible before scrolling.
+func drawViewport(c *Container) error {
+	if !c.isScrollable() || c.opts.scroll.viewport.Empty() {
+		return nil
+	}
+	cvs, err := canvas.New(c.opts.scroll.viewport)
+	if err != nil {
+		return err
+	}
+	return c.applyCanvas(cvs, false)
 }
 
 // drawCont draws the container and its widget.
 func drawCont(c *Container) error {
-	if us := c.usable(); us.Dx() <= 0 || us.Dy() <= 0 {
+	if us := c.usable(); us.Dx() <= 0 || us.Dy() <= 0 || c.tooSmall(c.area) {
 		return drawResize(c, c.area)
 	}
 
 	if err := drawBorder(c); err != nil {
 		return fmt.Errorf("unable to draw container border: %v", err)
 	}
+	if err := drawTabs(c); err != nil {
+		return fmt.Errorf("unable to draw the tab headers: %v", err)
+	}
+	if err := drawViewport(c); err != nil {
+		return fmt.Errorf("unable to draw the viewport: %v", err)
+	}
 
 	if err := drawWidget(c); err != nil {
 		return fmt.Errorf("unable to draw widget %T: %v", c.opts.widget, err)
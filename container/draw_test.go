@@ -21,11 +21,13 @@ import (
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/testcanvas"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/draw/testdraw"
 	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -64,6 +66,62 @@ func TestDrawWidget(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "draws widget with custom border characters",
+			termSize: image.Point{9, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					BorderChars(linestyle.ASCIIBorder),
+					PlaceWidget(fakewidget.New(widgetapi.Options{})),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				// Container border.
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderChars(linestyle.ASCIIBorder),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+
+				// Fake widget border.
+				testdraw.MustBorder(cvs, image.Rect(1, 1, 8, 4))
+				testdraw.MustText(cvs, "(7,3)", image.Point{2, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "Border replaces previously set custom border characters",
+			termSize: image.Point{9, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					BorderChars(linestyle.ASCIIBorder),
+					Border(linestyle.Light),
+					PlaceWidget(fakewidget.New(widgetapi.Options{})),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				// Container border.
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+
+				// Fake widget border.
+				testdraw.MustBorder(cvs, image.Rect(1, 1, 8, 4))
+				testdraw.MustText(cvs, "(7,3)", image.Point{2, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "absolute margin on root container",
 			termSize: image.Point{20, 10},
@@ -432,6 +490,51 @@ func TestDrawWidget(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "draws styled titles on the top and the bottom border",
+			termSize: image.Point{9, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					BorderTitle("ab"),
+					BorderTitleCellOpts(cell.Bold()),
+					BorderBottomTitle("cd"),
+					BorderBottomTitleAlignCenter(),
+					BorderBottomTitleCellOpts(cell.Underline()),
+					PlaceWidget(fakewidget.New(widgetapi.Options{})),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				// Container border.
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+					draw.BorderTitle(
+						"ab",
+						draw.OverrunModeThreeDot,
+						cell.FgColor(cell.ColorYellow),
+						cell.Bold(),
+					),
+					draw.BorderBottomTitle(
+						"cd",
+						draw.OverrunModeThreeDot,
+						cell.FgColor(cell.ColorYellow),
+						cell.Underline(),
+					),
+					draw.BorderBottomTitleAlign(align.HorizontalCenter),
+				)
+
+				// Fake widget border.
+				testdraw.MustBorder(cvs, image.Rect(1, 1, 8, 4))
+				testdraw.MustText(cvs, "(7,3)", image.Point{2, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "draws widget with container border and title aligned on the right",
 			termSize: image.Point{9, 5},
@@ -467,6 +570,74 @@ func TestDrawWidget(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "draws widget with container border, title and border buttons",
+			termSize: image.Point{9, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					BorderTitle("ab"),
+					BorderTitleAlignRight(),
+					BorderButtons(
+						BorderButton{Rune: 'x', OnClick: func() error { return nil }},
+						BorderButton{Rune: 'v', OnClick: func() error { return nil }},
+					),
+					PlaceWidget(fakewidget.New(widgetapi.Options{})),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				// Container border.
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+					draw.BorderTitle(
+						"ab",
+						draw.OverrunModeThreeDot,
+						cell.FgColor(cell.ColorYellow),
+					),
+					draw.BorderTitleAlign(align.HorizontalRight),
+					draw.BorderTitleReserveRight(4),
+				)
+				testcanvas.MustSetCell(cvs, image.Point{7, 0}, 'x', cell.FgColor(cell.ColorYellow))
+				testcanvas.MustSetCell(cvs, image.Point{5, 0}, 'v', cell.FgColor(cell.ColorYellow))
+
+				// Fake widget border.
+				testdraw.MustBorder(cvs, image.Rect(1, 1, 8, 4))
+				testdraw.MustText(cvs, "(7,3)", image.Point{2, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "border buttons that don't fit aren't drawn",
+			termSize: image.Point{5, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					BorderButtons(
+						BorderButton{Rune: 'x', OnClick: func() error { return nil }},
+						BorderButton{Rune: 'v', OnClick: func() error { return nil }},
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustSetCell(cvs, image.Point{3, 0}, 'x', cell.FgColor(cell.ColorYellow))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "draws widget with container border and title that is trimmed",
 			termSize: image.Point{9, 5},
@@ -611,6 +782,104 @@ func TestDrawWidget(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "container smaller than its MinSizeCells draws the resize placeholder instead of its content",
+			termSize: image.Point{10, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					Border(linestyle.Light),
+					MinSizeCells(20, 5),
+					SplitVertical(
+						Left(Border(linestyle.Light)),
+						Right(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "⇄", image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "container smaller than its MinSizeCells collapses and its sibling takes up the area",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitVertical(
+						Left(
+							Border(linestyle.Light),
+							MinSizeCells(15, 0),
+						),
+						Right(
+							Border(linestyle.Double),
+						),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 20, 10), draw.BorderLineStyle(linestyle.Double))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "containers smaller than their MinSizeCells on both sides of a split draw the resize placeholder",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitVertical(
+						Left(
+							Border(linestyle.Light),
+							MinSizeCells(15, 0),
+						),
+						Right(
+							Border(linestyle.Light),
+							MinSizeCells(0, 15),
+						),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "⇄", image.Point{0, 0})
+				testdraw.MustText(cvs, "⇄", image.Point{10, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "container is limited to its MaxSizeCells",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitHorizontal(
+						Top(
+							Border(linestyle.Light),
+							MaxSizeCells(8, 4),
+						),
+						Bottom(),
+						SplitPercent(70),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 0, 8, 4))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "widget's canvas is limited to the requested maximum size",
 			termSize: image.Point{22, 22},
@@ -888,6 +1157,72 @@ func TestDrawWidget(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "draws the layout debugging overlay",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					DebugLayout(),
+					SplitVertical(
+						Left(ID("a")),
+						Right(),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				cOpts := []cell.Option{cell.FgColor(DefaultDebugLayoutColor)}
+				testdraw.MustBorder(
+					cvs,
+					image.Rect(0, 0, 5, 4),
+					draw.BorderTitle("a 5x4", draw.OverrunModeThreeDot, cOpts...),
+					draw.BorderCellOpts(cOpts...),
+				)
+				testdraw.MustBorder(
+					cvs,
+					image.Rect(5, 0, 10, 4),
+					draw.BorderTitle("5x4", draw.OverrunModeThreeDot, cOpts...),
+					draw.BorderCellOpts(cOpts...),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "layout debugging overlay keeps the content visible",
+			termSize: image.Point{9, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					DebugLayout(),
+					DebugLayoutColor(cell.ColorRed),
+					PaddingTop(1),
+					PaddingBottom(1),
+					PaddingLeft(1),
+					PaddingRight(1),
+					PlaceWidget(fakewidget.New(widgetapi.Options{})),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				cOpts := []cell.Option{cell.FgColor(cell.ColorRed)}
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderTitle("9x5", draw.OverrunModeThreeDot, cOpts...),
+					draw.BorderCellOpts(cOpts...),
+				)
+
+				// Fake widget border.
+				testdraw.MustBorder(cvs, image.Rect(1, 1, 8, 4))
+				testdraw.MustText(cvs, "(7,3)", image.Point{2, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:     "vertical top align for the widget",
 			termSize: image.Point{22, 22},
@@ -1183,3 +1518,354 @@ func TestDrawHandlesTerminalResize(t *testing.T) {
 		})
 	}
 }
+
+func TestDrawResponsiveLayout(t *testing.T) {
+	got, err := faketerm.New(image.Point{60, 10})
+	if err != nil {
+		t.Errorf("faketerm.New => unexpected error: %v", err)
+	}
+
+	var calls int
+	cont, err := New(
+		got,
+		Responsive(func(size image.Point) []Option {
+			calls++
+			if size.X >= 40 {
+				return []Option{
+					SplitVertical(
+						Left(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+						Right(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					),
+				}
+			}
+			return []Option{
+				SplitHorizontal(
+					Top(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					Bottom(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+				),
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	// The following tests aren't hermetic, they all access the same container
+	// and fake terminal in order to retain state between resizes.
+	tests := []struct {
+		desc      string
+		resize    *image.Point // if not nil, the fake terminal will be resized.
+		want      func(size image.Point) *faketerm.Terminal
+		wantCalls int
+	}{
+		{
+			desc: "selects the layout on the initial draw",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 30, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(30, 0, 60, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+			wantCalls: 1,
+		},
+		{
+			desc: "doesn't select the layout again when the size didn't change",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 30, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(30, 0, 60, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+			wantCalls: 1,
+		},
+		{
+			desc:   "selects a different layout after a resize",
+			resize: &image.Point{30, 10},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 30, 5)), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 5, 30, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.resize != nil {
+				if err := got.Resize(*tc.resize); err != nil {
+					t.Fatalf("Resize => unexpected error: %v", err)
+				}
+			}
+			if err := cont.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(got.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("LayoutFn called %d times, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+// countingTerm counts the cells set on the fake terminal.
+type countingTerm struct {
+	*faketerm.Terminal
+	set int
+}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+func (ct *countingTerm) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	ct.set++
+	return ct.Terminal.SetCell(p, r, opts...)
+}
+
+func TestDrawSetsOnlyChangedCells(t *testing.T) {
+	size := image.Point{10, 5}
+	ct := &countingTerm{Terminal: faketerm.MustNew(size)}
+	c, err := New(
+		ct,
+		Border(linestyle.Light),
+		PlaceWidget(fakewidget.New(widgetapi.Options{})),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		desc    string
+		before  func()
+		wantSet int
+	}{
+		{
+			desc:    "the first draw sets all the cells",
+			wantSet: size.X * size.Y,
+		},
+		{
+			desc: "an unchanged frame sets no cells",
+		},
+		{
+			desc:    "sets all the cells after invalidation",
+			before:  c.Invalidate,
+			wantSet: size.X * size.Y,
+		},
+		{
+			desc: "sets all the cells after the terminal was resized",
+			before: func() {
+				if err := ct.Resize(image.Point{12, 5}); err != nil {
+					t.Fatalf("Resize => unexpected error: %v", err)
+				}
+			},
+			wantSet: 12 * 5,
+		},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		ct.set = 0
+		if err := c.Draw(); err != nil {
+			t.Fatalf("%s: Draw => unexpected error: %v", step.desc, err)
+		}
+		if ct.set != step.wantSet {
+			t.Errorf("%s: Draw set %d cells, want %d", step.desc, ct.set, step.wantSet)
+		}
+	}
+}
+
+// countingWidget is a widget that counts its Draw calls and fills its canvas
+// with a rune.
+type countingWidget struct {
+	r     rune
+	draws int
+}
+
+// Draw implements widgetapi.Widget.Draw.
+func (cw *countingWidget) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	cw.draws++
+	return cvs.SetAreaCells(cvs.Area(), cw.r)
+}
+
+// Keyboard implements widgetapi.Widget.Keyboard.
+func (*countingWidget) Keyboard(*terminalapi.Keyboard, *widgetapi.EventMeta) error { return nil }
+
+// Mouse implements widgetapi.Widget.Mouse.
+func (*countingWidget) Mouse(*terminalapi.Mouse, *widgetapi.EventMeta) error { return nil }
+
+// Options implements widgetapi.Widget.Options.
+func (*countingWidget) Options() widgetapi.Options {
+	return widgetapi.Options{WantKeyboard: widgetapi.KeyScopeGlobal}
+}
+
+// reportingWidget is a countingWidget that reports its changes.
+type reportingWidget struct {
+	countingWidget
+	markDirty func()
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (rw *reportingWidget) SetDirtyFunc(markDirty func()) {
+	rw.markDirty = markDirty
+}
+
+func TestDrawSkipsUnchangedWidgets(t *testing.T) {
+	ft := faketerm.MustNew(image.Point{20, 5})
+	reporting := &reportingWidget{countingWidget: countingWidget{r: 'r'}}
+	plain := &countingWidget{r: 'p'}
+	c, err := New(
+		ft,
+		SplitVertical(
+			Left(
+				Border(linestyle.Light),
+				PlaceWidget(reporting),
+			),
+			Right(PlaceWidget(plain)),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		desc          string
+		before        func()
+		wantReporting int
+	}{
+		{
+			desc:          "the first draw draws all widgets",
+			wantReporting: 1,
+		},
+		{
+			desc:          "doesn't draw an unchanged reporting widget",
+			wantReporting: 1,
+		},
+		{
+			desc:          "draws the widget after it reported a change",
+			before:        func() { reporting.markDirty() },
+			wantReporting: 2,
+		},
+		{
+			desc: "draws the widget after it received a keyboard event",
+			before: func() {
+				if err := c.processEvent(&terminalapi.Keyboard{Key: 'a'}); err != nil {
+					t.Fatalf("processEvent => unexpected error: %v", err)
+				}
+			},
+			wantReporting: 3,
+		},
+		{
+			desc:          "draws all widgets after the container was marked dirty",
+			before:        c.MarkDirty,
+			wantReporting: 4,
+		},
+		{
+			desc: "draws the widget after its area changed",
+			before: func() {
+				if err := ft.Resize(image.Point{30, 5}); err != nil {
+					t.Fatalf("Resize => unexpected error: %v", err)
+				}
+			},
+			wantReporting: 5,
+		},
+	}
+	for i, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		if err := c.Draw(); err != nil {
+			t.Fatalf("%s: Draw => unexpected error: %v", step.desc, err)
+		}
+		if got := reporting.draws; got != step.wantReporting {
+			t.Errorf("%s: reporting widget drawn %d times, want %d", step.desc, got, step.wantReporting)
+		}
+		if got, want := plain.draws, i+1; got != want {
+			t.Errorf("%s: plain widget drawn %d times, want %d", step.desc, got, want)
+		}
+		if got := ft.BackBuffer()[1][1].Rune; got != 'r' {
+			t.Errorf("%s: reporting widget's area shows %q, want %q", step.desc, got, 'r')
+		}
+	}
+}
+
+// metaWidget is a widget that remembers the metadata of the last Draw.
+type metaWidget struct {
+	meta *widgetapi.Meta
+}
+
+// Draw implements widgetapi.Widget.Draw.
+func (mw *metaWidget) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	mw.meta = meta
+	return nil
+}
+
+// Keyboard implements widgetapi.Widget.Keyboard.
+func (*metaWidget) Keyboard(*terminalapi.Keyboard, *widgetapi.EventMeta) error { return nil }
+
+// Mouse implements widgetapi.Widget.Mouse.
+func (*metaWidget) Mouse(*terminalapi.Mouse, *widgetapi.EventMeta) error { return nil }
+
+// Options implements widgetapi.Widget.Options.
+func (*metaWidget) Options() widgetapi.Options { return widgetapi.Options{} }
+
+func TestDrawMetaArea(t *testing.T) {
+	tests := []struct {
+		desc      string
+		container func(ft *faketerm.Terminal, w widgetapi.Widget) (*Container, error)
+		// scroll if not nil scrolls the root container to the offset.
+		scroll *image.Point
+		want   image.Rectangle
+	}{
+		{
+			desc: "widget in a split",
+			container: func(ft *faketerm.Terminal, w widgetapi.Widget) (*Container, error) {
+				return New(ft, SplitVertical(Left(), Right(PlaceWidget(w))))
+			},
+			want: image.Rect(10, 0, 20, 10),
+		},
+		{
+			desc: "widget inside a border",
+			container: func(ft *faketerm.Terminal, w widgetapi.Widget) (*Container, error) {
+				return New(ft, Border(linestyle.Light), PlaceWidget(w))
+			},
+			want: image.Rect(1, 1, 19, 9),
+		},
+		{
+			desc: "widget in scrolled content",
+			container: func(ft *faketerm.Terminal, w widgetapi.Widget) (*Container, error) {
+				return New(ft, Scrollable(0, 20), SplitHorizontal(Top(), Bottom(PlaceWidget(w))))
+			},
+			scroll: &image.Point{0, 5},
+			want:   image.Rect(0, 5, 20, 15),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			ft, err := faketerm.New(image.Point{20, 10})
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			w := &metaWidget{}
+			c, err := tc.container(ft, w)
+			if err != nil {
+				t.Fatalf("tc.container => unexpected error: %v", err)
+			}
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if tc.scroll != nil {
+				c.scrollTo(*tc.scroll)
+				if err := c.Draw(); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+			if got := w.meta.Area; got != tc.want {
+				t.Errorf("Draw => Meta.Area %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
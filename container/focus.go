@@ -18,6 +18,7 @@ package container
 
 import (
 	"image"
+	"sort"
 
 	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/button"
@@ -26,14 +27,16 @@ import (
 
 // pointCont finds the top-most (on the screen) container whose area contains
 // the given point. Returns nil if none of the containers in the tree contain
-// this point.
+// this point. Only the containers in the modal are considered while a modal is
+// displayed and only the containers in the top-most layer that contains the
+// point are considered if there is one.
 func pointCont(c *Container, p image.Point) *Container {
 	var (
 		errStr string
 		cont   *Container
 	)
-	postOrder(rootCont(c), &errStr, visitFunc(func(c *Container) error {
-		if p.In(c.area) && cont == nil {
+	postOrder(mouseRoot(c, p), &errStr, visitFunc(func(c *Container) error {
+		if lp, ok := c.localPoint(p, false); ok && lp.In(c.area) && cont == nil {
 			cont = c
 		}
 		return nil
@@ -83,50 +86,94 @@ func (ft *focusTracker) setActive(c *Container) {
 	ft.container = c
 }
 
+// focusOrder returns all the containers in the tree in the order in which the
+// keyboard focus visits them. Containers configured with the TabIndex option
+// come first ordered by their index, the remaining containers follow in the
+// order of a DFS (Depth-first search) traversal. Containers with equal index
+// are also ordered by the traversal. Non-leaf containers are ordered by the
+// lowest index in their subtree, so that moving the focus from them visits
+// the containers with the lowest index first.
+func (ft *focusTracker) focusOrder() []*Container {
+	var (
+		errStr string
+		conts  []*Container
+	)
+	indexes := map[*Container]*int{}
+	roots := eventRoots(ft.container)
+	index := visitFunc(func(c *Container) error {
+		if c.isLeaf() {
+			indexes[c] = c.opts.tabIndex
+			return nil
+		}
+		for _, child := range []*Container{c.first, c.second} {
+			if ci := indexes[child]; ci != nil && (indexes[c] == nil || *ci < *indexes[c]) {
+				indexes[c] = ci
+			}
+		}
+		return nil
+	})
+	for _, r := range roots {
+		postOrder(r, &errStr, index)
+		preOrder(r, &errStr, visitFunc(func(c *Container) error {
+			conts = append(conts, c)
+			return nil
+		}))
+	}
+	sort.SliceStable(conts, func(i, j int) bool {
+		ti, tj := indexes[conts[i]], indexes[conts[j]]
+		switch {
+		case ti == nil:
+			return false
+		case tj == nil:
+			return true
+		default:
+			return *ti < *tj
+		}
+	})
+	return conts
+}
+
+// eligible asserts whether the container can receive the keyboard focus when
+// it is being moved. If group is not nil, only containers with a matching
+// focus group number are eligible.
+func eligible(c *Container, group *FocusGroup) bool {
+	if !c.isLeaf() {
+		return false
+	}
+	if group == nil {
+		return !c.opts.keyFocusSkip
+	}
+	return c.inFocusGroup(*group)
+}
+
 // next moves focus to the next container.
 // If group is not nil, focus will only move between containers with a matching
 // focus group number.
 func (ft *focusTracker) next(group *FocusGroup) {
 	var (
-		errStr    string
 		firstCont *Container
 		nextCont  *Container
 		focusNext bool
 	)
-	preOrder(rootCont(ft.container), &errStr, visitFunc(func(c *Container) error {
-		if nextCont != nil {
-			// Already found the next container, nothing to do.
-			return nil
-		}
-
-		if firstCont == nil && c.isLeaf() {
+	for _, c := range ft.focusOrder() {
+		if firstCont == nil && eligible(c, group) {
 			// Remember the first eligible container in case we "wrap" over,
 			// i.e. finish the iteration before finding the next container.
-			switch {
-			case group == nil && !c.opts.keyFocusSkip:
-				fallthrough
-			case group != nil && c.inFocusGroup(*group):
-				firstCont = c
-			}
+			firstCont = c
 		}
 
 		if ft.container == c {
 			// Visiting the currently focused container, going to focus the
 			// next one.
 			focusNext = true
-			return nil
+			continue
 		}
 
-		if focusNext && c.isLeaf() {
-			switch {
-			case group == nil && !c.opts.keyFocusSkip:
-				fallthrough
-			case group != nil && c.inFocusGroup(*group):
-				nextCont = c
-			}
+		if focusNext && eligible(c, group) {
+			nextCont = c
+			break
 		}
-		return nil
-	}))
+	}
 
 	if nextCont == nil && firstCont != nil {
 		// If the traversal finishes without finding the next container, move
@@ -142,31 +189,24 @@ func (ft *focusTracker) next(group *FocusGroup) {
 // focus group number.
 func (ft *focusTracker) previous(group *FocusGroup) {
 	var (
-		errStr      string
 		prevCont    *Container
 		lastCont    *Container
 		visitedCurr bool
 	)
-	preOrder(rootCont(ft.container), &errStr, visitFunc(func(c *Container) error {
+	for _, c := range ft.focusOrder() {
 		if ft.container == c {
 			visitedCurr = true
 		}
 
-		if c.isLeaf() {
-			switch {
-			case group == nil && !c.opts.keyFocusSkip:
-				fallthrough
-			case group != nil && c.inFocusGroup(*group):
-				if !visitedCurr {
-					// Remember the last eligible container closest to the one
-					// currently focused.
-					prevCont = c
-				}
-				lastCont = c
+		if eligible(c, group) {
+			if !visitedCurr {
+				// Remember the last eligible container closest to the one
+				// currently focused.
+				prevCont = c
 			}
+			lastCont = c
 		}
-		return nil
-	}))
+	}
 
 	if prevCont != nil {
 		ft.setActive(prevCont)
@@ -197,17 +237,27 @@ func (ft *focusTracker) updateArea(ar image.Rectangle) {
 }
 
 // reachableFrom asserts whether the currently focused container is reachable
-// from the provided node in the tree.
-func (ft *focusTracker) reachableFrom(node *Container) bool {
+// from any of the provided nodes in the tree.
+func (ft *focusTracker) reachableFrom(nodes ...*Container) bool {
 	var (
 		errStr    string
 		reachable bool
 	)
-	preOrder(node, &errStr, visitFunc(func(c *Container) error {
-		if c == ft.container {
-			reachable = true
-		}
-		return nil
-	}))
+	for _, node := range nodes {
+		preOrder(node, &errStr, visitFunc(func(c *Container) error {
+			if c == ft.container {
+				reachable = true
+			}
+			return nil
+		}))
+	}
 	return reachable
 }
+
+// keepReachable moves the focus to the top of the tree that receives the
+// keyboard events if the currently focused container isn't reachable anymore.
+func (ft *focusTracker) keepReachable(c *Container) {
+	if ers := eventRoots(c); !ft.reachableFrom(ers...) {
+		ft.setActive(ers[0])
+	}
+}
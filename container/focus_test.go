@@ -1412,6 +1412,168 @@ func TestFocusTrackerNextAndPrevious(t *testing.T) {
 			wantFocused:   contLocB,
 			wantProcessed: 2,
 		},
+		{
+			desc:     "TabIndex determines the order for keyNext",
+			contSize: contSize5,
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New( // contLocA
+					ft,
+					SplitVertical(
+						Left( // contLocB
+							SplitVertical(
+								Left( // contLocD
+									TabIndex(2),
+								),
+								Right( // contLocE
+									TabIndex(1),
+								),
+							),
+						),
+						Right( // contLocC
+							TabIndex(0),
+						),
+					),
+					KeyFocusNext(keyNext),
+					KeyFocusPrevious(keyPrevious),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyNext}, // focuses contLocC
+				{Key: keyNext}, // focuses contLocE
+				{Key: keyNext}, // focuses contLocD
+			},
+			wantFocused:   contLocD,
+			wantProcessed: 3,
+		},
+		{
+			desc:     "keyNext wraps over to the container with the lowest TabIndex",
+			contSize: contSize5,
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New( // contLocA
+					ft,
+					SplitVertical(
+						Left( // contLocB
+							SplitVertical(
+								Left( // contLocD
+									TabIndex(2),
+								),
+								Right( // contLocE
+									TabIndex(1),
+								),
+							),
+						),
+						Right( // contLocC
+							TabIndex(0),
+						),
+					),
+					KeyFocusNext(keyNext),
+					KeyFocusPrevious(keyPrevious),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyNext}, // focuses contLocC
+				{Key: keyNext}, // focuses contLocE
+				{Key: keyNext}, // focuses contLocD
+				{Key: keyNext}, // focuses contLocC
+			},
+			wantFocused:   contLocC,
+			wantProcessed: 4,
+		},
+		{
+			desc:     "containers without TabIndex follow the indexed ones in layout order",
+			contSize: contSize5,
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New( // contLocA
+					ft,
+					SplitVertical(
+						Left( // contLocB
+							SplitVertical(
+								Left(),  // contLocD
+								Right(), // contLocE
+							),
+						),
+						Right( // contLocC
+							TabIndex(0),
+						),
+					),
+					KeyFocusNext(keyNext),
+					KeyFocusPrevious(keyPrevious),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyNext}, // focuses contLocC
+				{Key: keyNext}, // focuses contLocD
+				{Key: keyNext}, // focuses contLocE
+			},
+			wantFocused:   contLocE,
+			wantProcessed: 3,
+		},
+		{
+			desc:     "containers with duplicate TabIndex are visited in layout order",
+			contSize: contSize5,
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New( // contLocA
+					ft,
+					SplitVertical(
+						Left( // contLocB
+							SplitVertical(
+								Left( // contLocD
+									TabIndex(1),
+								),
+								Right( // contLocE
+									TabIndex(0),
+								),
+							),
+						),
+						Right( // contLocC
+							TabIndex(1),
+						),
+					),
+					KeyFocusNext(keyNext),
+					KeyFocusPrevious(keyPrevious),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyNext}, // focuses contLocE
+				{Key: keyNext}, // focuses contLocD
+				{Key: keyNext}, // focuses contLocC
+			},
+			wantFocused:   contLocC,
+			wantProcessed: 3,
+		},
+		{
+			desc:     "TabIndex determines the order for keyPrevious",
+			contSize: contSize5,
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New( // contLocA
+					ft,
+					SplitVertical(
+						Left( // contLocB
+							SplitVertical(
+								Left( // contLocD
+									TabIndex(2),
+								),
+								Right( // contLocE
+									TabIndex(1),
+								),
+							),
+						),
+						Right( // contLocC
+							TabIndex(0),
+						),
+					),
+					KeyFocusNext(keyNext),
+					KeyFocusPrevious(keyPrevious),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyPrevious}, // focuses contLocD
+				{Key: keyPrevious}, // focuses contLocE
+				{Key: keyPrevious}, // focuses contLocC
+			},
+			wantFocused:   contLocC,
+			wantProcessed: 3,
+		},
 		{
 			desc:     "KeyFocusGroups with no arguments removes all groups",
 			contSize: contSize5,
@@ -1446,6 +1608,44 @@ func TestFocusTrackerNextAndPrevious(t *testing.T) {
 			wantFocused:   contLocC,
 			wantProcessed: 2,
 		},
+		{
+			desc: "KeyFocusTab moves the focus forward on Tab",
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitVertical(
+						Left(),
+						Right(),
+					),
+					KeyFocusTab(),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyTab},
+				{Key: keyboard.KeyTab},
+			},
+			wantFocused:   contLocC,
+			wantProcessed: 2,
+		},
+		{
+			desc: "KeyFocusTab moves the focus backward on Shift-Tab",
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(
+					ft,
+					SplitVertical(
+						Left(),
+						Right(),
+					),
+					KeyFocusTab(),
+				)
+			},
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyBacktab},
+				{Key: keyboard.KeyBacktab},
+			},
+			wantFocused:   contLocB,
+			wantProcessed: 2,
+		},
 	}
 
 	for _, tc := range tests {
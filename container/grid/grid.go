@@ -45,7 +45,7 @@ func (b *Builder) Add(subElements ...Element) {
 // Build builds the grid layout and returns the corresponding container
 // options.
 func (b *Builder) Build() ([]container.Option, error) {
-	if err := validate(b.elems /* fixedSizeParent = */, false); err != nil {
+	if err := validate(b.elems); err != nil {
 		return nil, err
 	}
 	return build(b.elems, 100, 100), nil
@@ -58,12 +58,12 @@ func (b *Builder) Build() ([]container.Option, error) {
 //   Each individual width or height is in the range 0 < v < 100.
 //   The sum of all widths is <= 100.
 //   The sum of all heights is <= 100.
-// Argument fixedSizeParent indicates if any of the parent elements uses fixed
-// size splitType.
-func validate(elems []Element, fixedSizeParent bool) error {
+//   Fixed size Rows and Columns are placed before or after all the relative
+//   size Rows and Columns, not between them.
+func validate(elems []Element) error {
 	heightPercSum := 0
 	widthPercSum := 0
-	for _, elem := range elems {
+	for i, elem := range elems {
 		switch e := elem.(type) {
 		case *row:
 			if e.splitType == splitTypeRelative {
@@ -73,12 +73,11 @@ func validate(elems []Element, fixedSizeParent bool) error {
 			}
 			heightPercSum += e.heightPerc
 
-			if fixedSizeParent && e.splitType == splitTypeRelative {
-				return fmt.Errorf("row %v cannot use relative height when one of its parent elements uses fixed height", e)
+			if e.splitType == splitTypeFixed && isRelativeBetween(elems, i) {
+				return fmt.Errorf("row %v with fixed height must be placed before or after all rows with relative height", e)
 			}
 
-			isFixed := fixedSizeParent || e.splitType == splitTypeFixed
-			if err := validate(e.subElem, isFixed); err != nil {
+			if err := validate(e.subElem); err != nil {
 				return err
 			}
 
@@ -90,12 +89,11 @@ func validate(elems []Element, fixedSizeParent bool) error {
 			}
 			widthPercSum += e.widthPerc
 
-			if fixedSizeParent && e.splitType == splitTypeRelative {
-				return fmt.Errorf("column %v cannot use relative width when one of its parent elements uses fixed height", e)
+			if e.splitType == splitTypeFixed && isRelativeBetween(elems, i) {
+				return fmt.Errorf("column %v with fixed width must be placed before or after all columns with relative width", e)
 			}
 
-			isFixed := fixedSizeParent || e.splitType == splitTypeFixed
-			if err := validate(e.subElem, isFixed); err != nil {
+			if err := validate(e.subElem); err != nil {
 				return err
 			}
 
@@ -112,16 +110,79 @@ func validate(elems []Element, fixedSizeParent bool) error {
 	return nil
 }
 
+// isRelativeBetween determines if the element at index i has elements with
+// relative size both before and after it.
+func isRelativeBetween(elems []Element, i int) bool {
+	before := false
+	for _, e := range elems[:i] {
+		if isRelative(e) {
+			before = true
+			break
+		}
+	}
+	if !before {
+		return false
+	}
+
+	for _, e := range elems[i+1:] {
+		if isRelative(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRelative determines if the element is a Row or a Column with relative
+// size.
+func isRelative(elem Element) bool {
+	switch e := elem.(type) {
+	case *row:
+		return e.splitType == splitTypeRelative
+	case *col:
+		return e.splitType == splitTypeRelative
+	}
+	return false
+}
+
 // build recursively builds the container options according to the elements
 // that were added to the builder.
 // The parentHeightPerc and parentWidthPerc percent indicate the relative size
 // of the element we are building now in the parent element. See innerPerc()
 // for more details.
+// Fixed size elements that follow all the relative size elements are split
+// off from the end first, so that the percentages of the relative size
+// elements apply only to the remaining space.
 func build(elems []Element, parentHeightPerc, parentWidthPerc int) []container.Option {
 	if len(elems) == 0 {
 		return nil
 	}
 
+	if last := len(elems) - 1; last > 0 && isRelative(elems[0]) {
+		switch e := elems[last].(type) {
+		case *row:
+			if e.splitType == splitTypeFixed {
+				return []container.Option{
+					container.SplitHorizontal(
+						container.Top(build(elems[:last], parentHeightPerc, parentWidthPerc)...),
+						container.Bottom(append(e.cOpts, build(e.subElem, 100, parentWidthPerc)...)...),
+						container.SplitFixedFromEnd(e.heightFixed),
+					),
+				}
+			}
+
+		case *col:
+			if e.splitType == splitTypeFixed {
+				return []container.Option{
+					container.SplitVertical(
+						container.Left(build(elems[:last], parentHeightPerc, parentWidthPerc)...),
+						container.Right(append(e.cOpts, build(e.subElem, parentHeightPerc, 100)...)...),
+						container.SplitFixedFromEnd(e.widthFixed),
+					),
+				}
+			}
+		}
+	}
+
 	elem := elems[0]
 	elems = elems[1:]
 
@@ -310,7 +371,9 @@ func (w *widget) String() string {
 func (widget) isElement() {}
 
 // RowHeightPerc creates a row of the specified relative height.
-// The height is supplied as height percentage of the parent element.
+// The height is supplied as height percentage of the parent element. If the
+// same level also contains rows of fixed height, the percentage applies to the
+// space that remains after the fixed rows were placed.
 // The sum of all heights at the same level cannot be larger than 100%. If it
 // is less that 100%, the last element stretches to the edge of the screen.
 // The subElements can be either a single Widget or any combination of Rows and
@@ -332,7 +395,8 @@ func RowHeightPerc(heightPerc int, subElements ...Element) Element {
 // to the edge of the screen.
 // The subElements can be either a single Widget or any combination of Rows and
 // Columns.
-// A row with fixed height cannot contain any sub-elements with relative size.
+// Fixed height rows can be combined with relative height rows at the same
+// level, as long as they are placed before or after all the relative ones.
 func RowHeightFixed(heightCells int, subElements ...Element) Element {
 	return &row{
 		splitType:   splitTypeFixed,
@@ -364,7 +428,9 @@ func RowHeightFixedWithOpts(heightCells int, cOpts []container.Option, subElemen
 }
 
 // ColWidthPerc creates a column of the specified relative width.
-// The width is supplied as width percentage of the parent element.
+// The width is supplied as width percentage of the parent element. If the
+// same level also contains columns of fixed width, the percentage applies to
+// the space that remains after the fixed columns were placed.
 // The sum of all widths at the same level cannot be larger than 100%. If it
 // is less that 100%, the last element stretches to the edge of the screen.
 // The subElements can be either a single Widget or any combination of Rows and
@@ -386,7 +452,8 @@ func ColWidthPerc(widthPerc int, subElements ...Element) Element {
 // to the edge of the screen.
 // The subElements can be either a single Widget or any combination of Rows and
 // Columns.
-// A column with fixed width cannot contain any sub-elements with relative size.
+// Fixed width columns can be combined with relative width columns at the same
+// level, as long as they are placed before or after all the relative ones.
 func ColWidthFixed(widthCells int, subElements ...Element) Element {
 	return &col{
 		splitType:  splitTypeFixed,
@@ -235,29 +235,31 @@ func TestBuilder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			desc:     "fails when Row heightPerc used under Row heightFixed",
+			desc:     "fails when Row heightFixed is between Rows with heightPerc",
 			termSize: image.Point{10, 10},
 			builder: func() *Builder {
 				b := New()
 				b.Add(
-					RowHeightFixed(
-						5,
-						RowHeightPerc(10),
-					),
+					RowHeightPerc(50),
+					RowHeightFixed(2),
+					RowHeightPerc(50),
 				)
 				return b
 			}(),
 			wantErr: true,
 		},
 		{
-			desc:     "fails when Row heightPerc used under Col widthFixed",
+			desc:     "fails when Row heightFixed is between Rows with heightPerc at sub level",
 			termSize: image.Point{10, 10},
 			builder: func() *Builder {
 				b := New()
 				b.Add(
 					ColWidthFixed(
 						5,
-						RowHeightPerc(10),
+						RowHeightFixed(1),
+						RowHeightPerc(50),
+						RowHeightFixed(2),
+						RowHeightPerc(50),
 					),
 				)
 				return b
@@ -319,29 +321,30 @@ func TestBuilder(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			desc:     "fails when Col widthPerc used under Col widthFixed",
+			desc:     "fails when Col widthFixed is between Cols with widthPerc",
 			termSize: image.Point{10, 10},
 			builder: func() *Builder {
 				b := New()
 				b.Add(
-					ColWidthFixed(
-						5,
-						ColWidthPerc(10),
-					),
+					ColWidthPerc(50),
+					ColWidthFixed(2),
+					ColWidthPerc(50),
 				)
 				return b
 			}(),
 			wantErr: true,
 		},
 		{
-			desc:     "fails when Col widthPerc used under Row heightFixed",
+			desc:     "fails when Col widthFixed is between Cols with widthPerc at sub level",
 			termSize: image.Point{10, 10},
 			builder: func() *Builder {
 				b := New()
 				b.Add(
 					RowHeightFixed(
 						5,
-						ColWidthPerc(10),
+						ColWidthPerc(50),
+						ColWidthFixed(2),
+						ColWidthPerc(50),
 					),
 				)
 				return b
@@ -578,6 +581,109 @@ func TestBuilder(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:     "fixed header row, remaining rows split by percentage",
+			termSize: image.Point{10, 13},
+			builder: func() *Builder {
+				b := New()
+				b.Add(
+					RowHeightFixed(3, Widget(mirror())),
+					RowHeightPerc(70, Widget(mirror())),
+					RowHeightPerc(30, Widget(mirror())),
+				)
+				return b
+			}(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				for _, ar := range []image.Rectangle{
+					image.Rect(0, 0, 10, 3),
+					image.Rect(0, 3, 10, 10),
+					image.Rect(0, 10, 10, 13),
+				} {
+					fakewidget.MustDraw(ft, testcanvas.MustNew(ar), &widgetapi.Meta{}, widgetapi.Options{})
+				}
+				return ft
+			},
+		},
+		{
+			desc:     "fixed header and footer rows, remaining rows split by percentage",
+			termSize: image.Point{10, 15},
+			builder: func() *Builder {
+				b := New()
+				b.Add(
+					RowHeightFixed(3, Widget(mirror())),
+					RowHeightPerc(70, Widget(mirror())),
+					RowHeightPerc(30, Widget(mirror())),
+					RowHeightFixed(2, Widget(mirror())),
+				)
+				return b
+			}(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				for _, ar := range []image.Rectangle{
+					image.Rect(0, 0, 10, 3),
+					image.Rect(0, 3, 10, 10),
+					image.Rect(0, 10, 10, 13),
+					image.Rect(0, 13, 10, 15),
+				} {
+					fakewidget.MustDraw(ft, testcanvas.MustNew(ar), &widgetapi.Meta{}, widgetapi.Options{})
+				}
+				return ft
+			},
+		},
+		{
+			desc:     "fixed columns on both sides, remaining columns split by percentage",
+			termSize: image.Point{48, 5},
+			builder: func() *Builder {
+				b := New()
+				b.Add(
+					ColWidthFixed(8, Widget(mirror())),
+					ColWidthPerc(50, Widget(mirror())),
+					ColWidthPerc(50, Widget(mirror())),
+					ColWidthFixed(8, Widget(mirror())),
+				)
+				return b
+			}(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				for _, ar := range []image.Rectangle{
+					image.Rect(0, 0, 8, 5),
+					image.Rect(8, 0, 24, 5),
+					image.Rect(24, 0, 40, 5),
+					image.Rect(40, 0, 48, 5),
+				} {
+					fakewidget.MustDraw(ft, testcanvas.MustNew(ar), &widgetapi.Meta{}, widgetapi.Options{})
+				}
+				return ft
+			},
+		},
+		{
+			desc:     "relative columns inside of a fixed row",
+			termSize: image.Point{40, 10},
+			builder: func() *Builder {
+				b := New()
+				b.Add(
+					RowHeightFixed(
+						4,
+						ColWidthPerc(25, Widget(mirror())),
+						ColWidthPerc(75, Widget(mirror())),
+					),
+					RowHeightPerc(50, Widget(mirror())),
+				)
+				return b
+			}(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				for _, ar := range []image.Rectangle{
+					image.Rect(0, 0, 10, 4),
+					image.Rect(10, 0, 40, 4),
+					image.Rect(0, 4, 40, 10),
+				} {
+					fakewidget.MustDraw(ft, testcanvas.MustNew(ar), &widgetapi.Meta{}, widgetapi.Options{})
+				}
+				return ft
+			},
+		},
 		{
 			desc:     "two equal columns",
 			termSize: image.Point{20, 10},
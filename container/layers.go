@@ -0,0 +1,219 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// layers.go contains code that displays floating layers above the layout.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sort"
+)
+
+// layer is a container displayed above the layout of the root container in a
+// fixed area of the terminal.
+type layer struct {
+	// cont is the container displayed as the layer.
+	cont *Container
+	// ar is the requested area of the layer on the terminal.
+	ar image.Rectangle
+	// z is the z-index of the layer.
+	z int
+}
+
+// visible returns the part of the area of the layer that is on the terminal.
+func (l *layer) visible(termSize image.Point) image.Rectangle {
+	return l.ar.Intersect(image.Rect(0, 0, termSize.X, termSize.Y))
+}
+
+// ShowLayer displays a new container with the provided options as a floating
+// layer in the specified area of the terminal above the layout of the
+// dashboard, e.g. a popup, a tooltip or a HUD element. The part of the area
+// outside of the terminal isn't displayed.
+//
+// Layers are drawn in the order of their z-index, layers with a higher
+// z-index are drawn above the ones with a lower z-index and layers with equal
+// z-index are drawn in the order in which they were shown. Each layer hides
+// the parts of the layout and of the layers below it. The modal displayed by
+// ShowModal is always drawn above all the layers.
+//
+// Mouse events are only delivered to the top-most layer under the mouse
+// pointer, the layout receives only the mouse events outside of all the
+// layers. Widgets with widgetapi.MouseScopeGlobal receive all the mouse
+// events. The containers in the layers can be focused and the keyboard focus
+// visits them after the layout in the order in which the layers are drawn.
+//
+// The id identifies the layer and is set as the ID of its container, so the
+// layer can be changed by Container.Update. The id must be unique across the
+// layout, the modal and the layers. Showing a layer with the id of a layer
+// that is already displayed replaces it.
+func (c *Container) ShowLayer(id string, z int, ar image.Rectangle, opts ...Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id == "" {
+		return errors.New("the layer ID must not be empty")
+	}
+	if ar.Empty() {
+		return fmt.Errorf("invalid layer area %v, must not be empty", ar)
+	}
+
+	root := rootCont(c)
+	cont, err := newChild(root, append(append([]Option{}, opts...), ID(id)))
+	if err != nil {
+		return err
+	}
+
+	prev := root.layers
+	layers := append(removeLayer(prev, id), &layer{
+		cont: cont,
+		ar:   ar,
+		z:    z,
+	})
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].z < layers[j].z
+	})
+	root.layers = layers
+	if err := validateOptions(root); err != nil {
+		root.layers = prev
+		return err
+	}
+
+	root.clearNeeded = true
+	root.dragged = nil
+	c.focusTracker.keepReachable(root)
+	return nil
+}
+
+// HideLayer removes the layer with the specified id displayed by ShowLayer.
+// If the layer contained the focused container, the focus moves to the root
+// container.
+// Does nothing if no such layer is displayed.
+func (c *Container) HideLayer(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root := rootCont(c)
+	if !layerShown(root, id) {
+		return
+	}
+	root.layers = removeLayer(root.layers, id)
+	root.clearNeeded = true
+	root.dragged = nil
+	c.focusTracker.keepReachable(root)
+}
+
+// LayerShown asserts whether the layer with the specified id is displayed.
+func (c *Container) LayerShown(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return layerShown(rootCont(c), id)
+}
+
+// layerShown asserts whether the root container displays the layer with the
+// specified id.
+func layerShown(root *Container, id string) bool {
+	for _, l := range root.layers {
+		if l.cont.opts.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeLayer returns a copy of the layers without the layer with the
+// specified id.
+func removeLayer(layers []*layer, id string) []*layer {
+	var res []*layer
+	for _, l := range layers {
+		if l.cont.opts.id != id {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// isFloating asserts whether the container is displayed above the layout of
+// the root container, i.e. it is the modal or a layer.
+func isFloating(c *Container) bool {
+	p := c.parent
+	if p == nil {
+		return false
+	}
+	if p.modal != nil && p.modal.cont == c {
+		return true
+	}
+	for _, l := range p.layers {
+		if l.cont == c {
+			return true
+		}
+	}
+	return false
+}
+
+// treeRoot returns the container at the top of the tree that contains the
+// container, i.e. the modal, a layer or the root container.
+func treeRoot(c *Container) *Container {
+	for c.parent != nil && !isFloating(c) {
+		c = c.parent
+	}
+	return c
+}
+
+// eventRoots returns the containers at the top of the trees that receive the
+// keyboard events in the order in which the focus visits them. This is only
+// the modal if one is displayed, the root container followed by the layers
+// otherwise.
+func eventRoots(c *Container) []*Container {
+	root := rootCont(c)
+	if root.modal != nil {
+		return []*Container{root.modal.cont}
+	}
+	res := []*Container{root}
+	for _, l := range root.layers {
+		res = append(res, l.cont)
+	}
+	return res
+}
+
+// mouseRoot returns the container at the top of the tree that receives the
+// mouse events at the point. This is the modal if one is displayed, the
+// top-most layer that contains the point or the root container otherwise.
+func mouseRoot(c *Container, p image.Point) *Container {
+	root := rootCont(c)
+	if root.modal != nil {
+		return root.modal.cont
+	}
+	size := root.term.Size()
+	for i := len(root.layers) - 1; i >= 0; i-- {
+		if l := root.layers[i]; p.In(l.visible(size)) {
+			return l.cont
+		}
+	}
+	return root
+}
+
+// drawLayers draws the layers displayed above the layout of the root
+// container.
+func drawLayers(root *Container) error {
+	size := root.term.Size()
+	for _, l := range root.layers {
+		if err := drawFloating(root, l.cont, l.visible(size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestLayers(t *testing.T) {
+	globalOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeGlobal}
+	widgetOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeWidget}
+
+	tests := []struct {
+		desc      string
+		termSize  image.Point
+		container func(ft *faketerm.Terminal) (*Container, error)
+		// layers displays the layers on the container.
+		layers      func(c *Container) error
+		events      []terminalapi.Event
+		want        func(size image.Point) *faketerm.Terminal
+		wantShowErr bool
+	}{
+		{
+			desc:     "fails on an empty layer ID",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("", 0, image.Rect(0, 0, 10, 5))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "fails on an empty layer area",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("layer", 0, image.Rect(5, 5, 5, 10))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "fails on invalid layer options",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("layer", 0, image.Rect(0, 0, 10, 5), MarginTopPercent(-1))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "fails on an ID that is already used in the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, ID("id"))
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("id", 0, image.Rect(0, 0, 10, 5))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "draws the layers above the layout in the order of their z-index",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, PlaceWidget(fakewidget.New(globalOpts)))
+			},
+			layers: func(c *Container) error {
+				if err := c.ShowLayer("top", 2, image.Rect(4, 2, 14, 7), Border(linestyle.Light)); err != nil {
+					return err
+				}
+				return c.ShowLayer("bottom", 1, image.Rect(0, 0, 10, 5), Border(linestyle.Light))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{Focused: true}, globalOpts)
+
+				for _, ar := range []image.Rectangle{
+					image.Rect(0, 0, 10, 5),
+					image.Rect(4, 2, 14, 7),
+				} {
+					cvs := testcanvas.MustNew(ar)
+					testdraw.MustBorder(cvs, cvs.Area())
+					testcanvas.MustApply(cvs, ft)
+				}
+				return ft
+			},
+		},
+		{
+			desc:     "layers with equal z-index are drawn in the order they were shown",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				if err := c.ShowLayer("bottom", 0, image.Rect(0, 0, 10, 5), Border(linestyle.Light)); err != nil {
+					return err
+				}
+				return c.ShowLayer("top", 0, image.Rect(4, 2, 14, 7), Border(linestyle.Double))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(image.Rect(0, 0, 10, 5))
+				testdraw.MustBorder(cvs, cvs.Area())
+				testcanvas.MustApply(cvs, ft)
+
+				cvs = testcanvas.MustNew(image.Rect(4, 2, 14, 7))
+				testdraw.MustBorder(cvs, cvs.Area(), draw.BorderLineStyle(linestyle.Double))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "limits the layer to the terminal",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("layer", 0, image.Rect(15, 7, 25, 12), Border(linestyle.Light))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(15, 7, 20, 10))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "the modal is drawn above the layers",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			layers: func(c *Container) error {
+				if err := c.ShowModal(image.Point{10, 4}, Border(linestyle.Double)); err != nil {
+					return err
+				}
+				return c.ShowLayer("layer", 100, image.Rect(0, 0, 20, 10), Border(linestyle.Light))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, cvs.Area())
+				testcanvas.MustApply(cvs, ft)
+
+				cvs = testcanvas.MustNew(image.Rect(5, 3, 15, 7))
+				testdraw.MustBorder(
+					cvs,
+					cvs.Area(),
+					draw.BorderLineStyle(linestyle.Double),
+					draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "mouse events go to the top-most layer under the pointer",
+			termSize: image.Point{30, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, PlaceWidget(fakewidget.New(widgetOpts)))
+			},
+			layers: func(c *Container) error {
+				if err := c.ShowLayer("top", 1, image.Rect(0, 0, 22, 5), PlaceWidget(fakewidget.New(widgetOpts))); err != nil {
+					return err
+				}
+				return c.ShowLayer("bottom", 0, image.Rect(0, 0, 26, 7), PlaceWidget(fakewidget.New(widgetOpts)))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{2, 2}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{2, 2}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, widgetOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 26, 7)), &widgetapi.Meta{}, widgetOpts)
+				fakewidget.MustDraw(
+					ft,
+					testcanvas.MustNew(image.Rect(0, 0, 22, 5)),
+					&widgetapi.Meta{Focused: true},
+					widgetOpts,
+					&fakewidget.Event{
+						Ev:   &terminalapi.Mouse{Position: image.Point{2, 2}, Button: mouse.ButtonLeft},
+						Meta: &widgetapi.EventMeta{},
+					},
+					&fakewidget.Event{
+						Ev:   &terminalapi.Mouse{Position: image.Point{2, 2}, Button: mouse.ButtonRelease},
+						Meta: &widgetapi.EventMeta{Focused: true},
+					},
+				)
+				return ft
+			},
+		},
+		{
+			desc:     "widgets below the layers that want all mouse events receive them",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, PlaceWidget(fakewidget.New(globalOpts)))
+			},
+			layers: func(c *Container) error {
+				return c.ShowLayer("layer", 0, image.Rect(10, 0, 20, 5), Border(linestyle.Light))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{12, 2}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(
+					ft,
+					testcanvas.MustNew(ft.Area()),
+					&widgetapi.Meta{Focused: true},
+					globalOpts,
+					&fakewidget.Event{
+						Ev:   &terminalapi.Mouse{Position: image.Point{12, 2}, Button: mouse.ButtonLeft},
+						Meta: &widgetapi.EventMeta{Focused: true},
+					},
+				)
+				cvs := testcanvas.MustNew(image.Rect(10, 0, 20, 5))
+				testdraw.MustBorder(cvs, cvs.Area())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if err != nil {
+				t.Fatalf("tc.container => unexpected error: %v", err)
+			}
+			err = tc.layers(c)
+			if (err != nil) != tc.wantShowErr {
+				t.Fatalf("ShowLayer => unexpected error: %v, wantShowErr: %v", err, tc.wantShowErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+			c.Subscribe(eds)
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestHideLayer(t *testing.T) {
+	ft, err := faketerm.New(image.Point{20, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	c, err := New(
+		ft,
+		SplitVertical(
+			Left(ID("left")),
+			Right(),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	c.focusTracker.setActive(c.first)
+
+	if err := c.ShowLayer("layer", 0, image.Rect(0, 0, 10, 5)); err != nil {
+		t.Fatalf("ShowLayer => unexpected error: %v", err)
+	}
+	if !c.LayerShown("layer") {
+		t.Errorf("LayerShown => false, want true")
+	}
+	// Unlike the modal, layers don't capture the focus.
+	if got, want := c.focusTracker.active(), c.first; got != want {
+		t.Errorf("ShowLayer => focused %v, want %v", got, want)
+	}
+
+	// Containers in the layer can be updated by their ID.
+	if err := c.Update("layer", Border(linestyle.Light)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	layerCont := c.layers[0].cont
+	if got, want := layerCont.opts.border, linestyle.Light; got != want {
+		t.Errorf("Update => layer border %v, want %v", got, want)
+	}
+
+	// The keyboard focus visits the layers after the layout.
+	c.focusTracker.next(nil)
+	if got, want := c.focusTracker.active(), c.second; got != want {
+		t.Errorf("next => focused %v, want %v", got, want)
+	}
+	c.focusTracker.next(nil)
+	if got, want := c.focusTracker.active(), layerCont; got != want {
+		t.Errorf("next => focused %v, want the layer %v", got, want)
+	}
+	// Updating the layout below the layer doesn't take the focus away from it.
+	if err := c.Update("left", Border(linestyle.Light)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	if got, want := c.focusTracker.active(), layerCont; got != want {
+		t.Errorf("Update => focused %v, want the layer %v", got, want)
+	}
+
+	// Replacing the focused layer moves the focus to the root container.
+	if err := c.ShowLayer("layer", 0, image.Rect(0, 0, 10, 5)); err != nil {
+		t.Fatalf("ShowLayer => unexpected error: %v", err)
+	}
+	if got, want := len(c.layers), 1; got != want {
+		t.Errorf("ShowLayer => %d layers, want %d", got, want)
+	}
+	if got, want := c.focusTracker.active(), c; got != want {
+		t.Errorf("ShowLayer => focused %v, want the root %v", got, want)
+	}
+
+	c.focusTracker.setActive(c.layers[0].cont)
+	c.HideLayer("layer")
+	if c.LayerShown("layer") {
+		t.Errorf("LayerShown => true, want false")
+	}
+	if got, want := c.focusTracker.active(), c; got != want {
+		t.Errorf("HideLayer => focused %v, want the root %v", got, want)
+	}
+	// Hiding a layer that isn't displayed does nothing.
+	c.HideLayer("layer")
+}
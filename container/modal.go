@@ -0,0 +1,174 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// modal.go contains code that displays a floating modal container above the
+// layout.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/canvas"
+)
+
+// modal is a container displayed above the layout of the root container.
+type modal struct {
+	// cont is the container displayed as the modal.
+	cont *Container
+	// size is the requested size of the modal in cells.
+	size image.Point
+	// prevFocus is the container that was focused before the modal was
+	// displayed.
+	prevFocus *Container
+}
+
+// ShowModal displays a new container with the provided options as a floating
+// modal centered above the layout of the dashboard. The size of the modal is
+// specified in cells and is limited to the size of the terminal.
+//
+// While the modal is displayed, it captures the keyboard focus and only the
+// containers and widgets inside the modal receive keyboard and mouse events.
+// The modal is displayed until HideModal is called, e.g. from a button
+// placed inside the modal. Showing a modal while another one is displayed
+// replaces it.
+//
+// The containers inside the modal can be found by their ID, e.g. by
+// Container.Update, the IDs must be unique across the layout and the modal.
+func (c *Container) ShowModal(size image.Point, opts ...Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("invalid modal size %v, both dimensions must be positive", size)
+	}
+
+	root := rootCont(c)
+	cont, err := newChild(root, opts)
+	if err != nil {
+		return err
+	}
+
+	prev := root.modal
+	prevFocus := c.focusTracker.active()
+	if prev != nil {
+		prevFocus = prev.prevFocus
+	}
+	root.modal = &modal{
+		cont:      cont,
+		size:      size,
+		prevFocus: prevFocus,
+	}
+	if err := validateOptions(root); err != nil {
+		root.modal = prev
+		return err
+	}
+
+	root.clearNeeded = true
+	root.dragged = nil
+	c.focusTracker.setActive(cont)
+	return nil
+}
+
+// HideModal removes the modal displayed by ShowModal and returns the focus to
+// the container that was focused before the modal was displayed.
+// Does nothing if no modal is displayed.
+func (c *Container) HideModal() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root := rootCont(c)
+	m := root.modal
+	if m == nil {
+		return
+	}
+	root.modal = nil
+	root.clearNeeded = true
+
+	c.focusTracker.setActive(m.prevFocus)
+	c.focusTracker.keepReachable(root)
+}
+
+// ModalShown asserts whether a modal is displayed.
+func (c *Container) ModalShown() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return rootCont(c).modal != nil
+}
+
+// drawModal draws the modal displayed above the layout of the root container.
+func drawModal(root *Container) error {
+	m := root.modal
+	if m == nil {
+		return nil
+	}
+
+	size := m.size
+	termSize := root.term.Size()
+	if size.X > termSize.X {
+		size.X = termSize.X
+	}
+	if size.Y > termSize.Y {
+		size.Y = termSize.Y
+	}
+	ar, err := alignfor.Rectangle(
+		image.Rect(0, 0, termSize.X, termSize.Y),
+		image.Rect(0, 0, size.X, size.Y),
+		align.HorizontalCenter,
+		align.VerticalMiddle,
+	)
+	if err != nil {
+		return err
+	}
+	return drawFloating(root, m.cont, ar)
+}
+
+// drawFloating draws the container displayed above the layout of the root
+// container in the area. Does nothing if the area is empty.
+func drawFloating(root, cont *Container, ar image.Rectangle) error {
+	if ar.Empty() {
+		cont.area = image.ZR
+		return nil
+	}
+
+	// Clear the area under the container, so the layout doesn't show through.
+	cvs, err := canvas.New(ar)
+	if err != nil {
+		return err
+	}
+	if err := cvs.ApplyTracked(root.term, root.damage); err != nil {
+		return err
+	}
+
+	cont.area, err = cont.opts.margin.apply(ar)
+	if err != nil {
+		return err
+	}
+
+	var errStr string
+	preOrder(cont, &errStr, visitFunc(func(c *Container) error {
+		if err := setChildAreas(c); err != nil {
+			return err
+		}
+		return drawCont(c)
+	}))
+	if errStr != "" {
+		return errors.New(errStr)
+	}
+	return nil
+}
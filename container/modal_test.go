@@ -0,0 +1,258 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestModal(t *testing.T) {
+	globalOpts := widgetapi.Options{WantKeyboard: widgetapi.KeyScopeGlobal, WantMouse: widgetapi.MouseScopeGlobal}
+	focusedOpts := widgetapi.Options{WantKeyboard: widgetapi.KeyScopeFocused, WantMouse: widgetapi.MouseScopeWidget}
+
+	tests := []struct {
+		desc      string
+		termSize  image.Point
+		container func(ft *faketerm.Terminal) (*Container, error)
+		// modal displays the modal on the container.
+		modal       func(c *Container) error
+		events      []terminalapi.Event
+		want        func(size image.Point) *faketerm.Terminal
+		wantShowErr bool
+	}{
+		{
+			desc:     "fails on a modal size that isn't positive",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{0, 4})
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "fails on invalid modal options",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{10, 4}, ID(""))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "fails on an ID that is already used in the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, ID("id"))
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{10, 4}, ID("id"))
+			},
+			wantShowErr: true,
+		},
+		{
+			desc:     "draws the modal centered above the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, PlaceWidget(fakewidget.New(globalOpts)))
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{10, 4}, Border(linestyle.Light))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, globalOpts)
+
+				cvs := testcanvas.MustNew(image.Rect(5, 3, 15, 7))
+				testdraw.MustBorder(cvs, cvs.Area(), draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "limits the modal to the size of the terminal",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft)
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{30, 4}, Border(linestyle.Light))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, image.Rect(0, 3, 20, 7), draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "only the modal receives events",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, PlaceWidget(fakewidget.New(globalOpts)))
+			},
+			modal: func(c *Container) error {
+				return c.ShowModal(image.Point{14, 6}, PlaceWidget(fakewidget.New(focusedOpts)))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				// A click outside of the modal.
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, globalOpts)
+				fakewidget.MustDraw(
+					ft,
+					testcanvas.MustNew(image.Rect(3, 2, 17, 8)),
+					&widgetapi.Meta{Focused: true},
+					focusedOpts,
+					&fakewidget.Event{
+						Ev:   &terminalapi.Keyboard{Key: keyboard.KeyEnter},
+						Meta: &widgetapi.EventMeta{Focused: true},
+					},
+				)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if err != nil {
+				t.Fatalf("tc.container => unexpected error: %v", err)
+			}
+			err = tc.modal(c)
+			if (err != nil) != tc.wantShowErr {
+				t.Fatalf("ShowModal => unexpected error: %v, wantShowErr: %v", err, tc.wantShowErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+			c.Subscribe(eds)
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestHideModal(t *testing.T) {
+	ft, err := faketerm.New(image.Point{20, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	c, err := New(
+		ft,
+		SplitVertical(
+			Left(ID("left")),
+			Right(),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	c.focusTracker.setActive(c.first)
+
+	if err := c.ShowModal(image.Point{10, 4}, ID("modal")); err != nil {
+		t.Fatalf("ShowModal => unexpected error: %v", err)
+	}
+	if !c.ModalShown() {
+		t.Errorf("ModalShown => false, want true")
+	}
+	if got, want := c.focusTracker.active(), c.modal.cont; got != want {
+		t.Errorf("ShowModal => focused %v, want the modal %v", got, want)
+	}
+
+	// Containers in the modal can be updated by their ID.
+	if err := c.Update("modal", Border(linestyle.Light)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	if got, want := c.modal.cont.opts.border, linestyle.Light; got != want {
+		t.Errorf("Update => modal border %v, want %v", got, want)
+	}
+	// Updating the layout below the modal doesn't take the focus away from the modal.
+	if err := c.Update("left", Border(linestyle.Light)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	if got, want := c.focusTracker.active(), c.modal.cont; got != want {
+		t.Errorf("Update => focused %v, want the modal %v", got, want)
+	}
+
+	c.HideModal()
+	if c.ModalShown() {
+		t.Errorf("ModalShown => true, want false")
+	}
+	if got, want := c.focusTracker.active(), c.first; got != want {
+		t.Errorf("HideModal => focused %v, want the previously focused %v", got, want)
+	}
+	// Hiding without a modal does nothing.
+	c.HideModal()
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/linestyle"
 	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -60,6 +61,9 @@ func validateSplits(c *Container) error {
 			c.opts.splitPercent,
 		)
 	}
+	if c.opts.splitFixed > DefaultSplitFixed && c.opts.splitDrag != nil {
+		return errors.New("SplitDraggable cannot be combined with SplitFixed")
+	}
 
 	return nil
 }
@@ -68,13 +72,16 @@ func validateSplits(c *Container) error {
 func validateOptions(c *Container) error {
 	var errStr string
 	seenID := map[string]bool{}
-	preOrder(c, &errStr, func(c *Container) error {
+	preOrderAll(c, &errStr, func(c *Container) error {
 		if err := validateIds(c, seenID); err != nil {
 			return err
 		}
 		if err := validateSplits(c); err != nil {
 			return err
 		}
+		if c.isScrollable() && c.isTabbed() {
+			return errors.New("Scrollable cannot be combined with Tabs")
+		}
 
 		return nil
 	})
@@ -111,21 +118,45 @@ type options struct {
 	split        splitType
 	splitPercent int
 	splitFixed   int
+	// splitFixedFromEnd indicates that splitFixed applies to the second
+	// container instead of the first one.
+	splitFixedFromEnd bool
+	// splitDrag is set if the split can be dragged with the mouse.
+	splitDrag *splitDrag
 
 	// widget is the widget in the container.
 	// A container can have either two sub containers (left and right) or a
 	// widget. But not both.
 	widget widgetapi.Widget
 
+	// scroll is set if the container has the Scrollable option.
+	scroll *scroll
+
+	// tabs are the pages of a container with the Tabs option, nil otherwise.
+	// The sub container displaying the active page is the first sub
+	// container.
+	tabs *tabs
+
 	// Alignment of the widget if present.
 	hAlign align.Horizontal
 	vAlign align.Vertical
 
 	// border is the border around the container.
-	border            linestyle.LineStyle
+	border linestyle.LineStyle
+	// borderChars are custom characters of the border, if set they are used
+	// instead of the line style.
+	borderChars       *linestyle.BorderChars
 	borderTitle       string
 	richBorderTitle   *cell.RichTextString
 	borderTitleHAlign align.Horizontal
+	// borderTitleCellOpts are additional cell options of the title.
+	borderTitleCellOpts []cell.Option
+	// borderBottomTitle is the secondary title on the bottom border.
+	borderBottomTitle         string
+	borderBottomTitleHAlign   align.Horizontal
+	borderBottomTitleCellOpts []cell.Option
+	// borderButtons are clickable glyphs drawn on the top border.
+	borderButtons []*borderButton
 
 	// padding is a space reserved between the outer edge of the container and
 	// its content (the widget or other sub-containers).
@@ -134,11 +165,27 @@ type options struct {
 	// margin is a space reserved on the outside of the container.
 	margin margin
 
+	// minSize is the minimum size of the container in cells, a zero value
+	// in either dimension means no constraint.
+	minSize image.Point
+	// maxSize is the maximum size of the container in cells, a zero value
+	// in either dimension means no constraint.
+	maxSize image.Point
+
 	// keyFocusSkip asserts whether this container should be skipped when focus
 	// is being moved using either of KeyFocusNext or KeyFocusPrevious.
 	keyFocusSkip bool
 	// keyFocusGroups are the focus groups this container belongs to.
 	keyFocusGroups []FocusGroup
+	// tabIndex when set determines the position of this container in the
+	// order in which the keyboard focus visits the containers.
+	tabIndex *int
+
+	// layoutFn selects the layout of the container based on its size.
+	layoutFn LayoutFn
+	// layoutSize is the size of the container the last time its layout was
+	// selected by the layoutFn, nil if it wasn't selected yet.
+	layoutSize *image.Point
 }
 
 // margin stores the configured margin for the container.
@@ -195,6 +242,10 @@ type inherited struct {
 	borderColor cell.Color
 	// focusedColor is the color used for the border when focused.
 	focusedColor cell.Color
+	// borderColorSet and focusedColorSet indicate if the colors were set
+	// explicitly, otherwise the colors of the theme take precedence.
+	borderColorSet  bool
+	focusedColorSet bool
 	// titleColor is the color used for the title.
 	titleColor *cell.Color
 	// titleFocusedColor is the color used for the title when focused.
@@ -234,6 +285,11 @@ type globalOptions struct {
 	// container within a focus group to the focus groups they should work on
 	// in the order they were configured.
 	keyFocusGroupsPrevious map[keyboard.Key]focusGroups
+
+	// debugLayout indicates whether the layout debugging overlay is drawn.
+	debugLayout bool
+	// debugLayoutColor is the color of the layout debugging overlay.
+	debugLayoutColor cell.Color
 }
 
 // newOptions returns a new options instance with the default values.
@@ -244,6 +300,7 @@ func newOptions(parent *options) *options {
 		global: &globalOptions{
 			keyFocusGroupsNext:     map[keyboard.Key]focusGroups{},
 			keyFocusGroupsPrevious: map[keyboard.Key]focusGroups{},
+			debugLayoutColor:       DefaultDebugLayoutColor,
 		},
 		inherited: inherited{
 			focusedColor: cell.ColorYellow,
@@ -324,6 +381,25 @@ func SplitFixed(cells int) SplitOption {
 	})
 }
 
+// SplitFixedFromEnd is like SplitFixed, but the fixed size is applied to the
+// second container and the first container takes up the remaining space.
+// When using SplitVertical, the provided size is applied to the new right
+// container. When using SplitHorizontal, the provided size is applied to the
+// new bottom container.
+// The provided value must be a positive number in the range 0 <= cells.
+// Only one of SplitFixedFromEnd(), SplitFixed() and SplitPercent() can be
+// specified per container.
+func SplitFixedFromEnd(cells int) SplitOption {
+	return splitOption(func(opts *options) error {
+		if cells < 0 {
+			return fmt.Errorf("invalid fixed value %d, must be in range %d <= cells", cells, 0)
+		}
+		opts.splitFixed = cells
+		opts.splitFixedFromEnd = true
+		return nil
+	})
+}
+
 // SplitVertical splits the container along the vertical axis into two sub
 // containers. The use of this option removes any widget placed at this
 // container, containers with sub containers cannot contain widgets.
@@ -331,6 +407,7 @@ func SplitVertical(l LeftOption, r RightOption, opts ...SplitOption) Option {
 	return option(func(c *Container) error {
 		c.opts.split = splitTypeVertical
 		c.opts.widget = nil
+		c.opts.tabs = nil
 		for _, opt := range opts {
 			if err := opt.setSplit(c.opts); err != nil {
 				return err
@@ -351,6 +428,7 @@ func SplitHorizontal(t TopOption, b BottomOption, opts ...SplitOption) Option {
 	return option(func(c *Container) error {
 		c.opts.split = splitTypeHorizontal
 		c.opts.widget = nil
+		c.opts.tabs = nil
 		for _, opt := range opts {
 			if err := opt.setSplit(c.opts); err != nil {
 				return err
@@ -385,20 +463,48 @@ func ID(id string) Option {
 func Clear() Option {
 	return option(func(c *Container) error {
 		c.opts.widget = nil
+		c.opts.tabs = nil
 		c.first = nil
 		c.second = nil
 		return nil
 	})
 }
 
+// LayoutFn returns the options that define the layout of a container with
+// the Responsive option. The size is the current size of the container in
+// cells.
+type LayoutFn func(size image.Point) []Option
+
+// Responsive makes the layout of the container depend on its size, e.g. to
+// place two sub containers side by side on a wide terminal and above each
+// other on a narrow one.
+// The provided function is called with the size of the container before it
+// is first drawn and again each time its size changes, e.g. when the terminal
+// is resized. The returned options are applied to the container as if they
+// were provided to Update, they would typically split the container or place
+// a widget into it.
+func Responsive(fn LayoutFn) Option {
+	return option(func(c *Container) error {
+		if fn == nil {
+			return errors.New("the LayoutFn provided to Responsive cannot be nil")
+		}
+		c.opts.layoutFn = fn
+		c.opts.layoutSize = nil
+		return nil
+	})
+}
+
 // PlaceWidget places the provided widget into the container.
 // The use of this option removes any sub containers. Containers with sub
 // containers cannot have widgets.
 func PlaceWidget(w widgetapi.Widget) Option {
 	return option(func(c *Container) error {
 		c.opts.widget = w
+		c.dirtySet = false
+		c.drawn = nil
 		c.first = nil
 		c.second = nil
+		c.opts.tabs = nil
 		return nil
 	})
 }
@@ -675,6 +781,55 @@ func PaddingLeftPercent(perc int) Option {
 	})
 }
 
+// MinSizeCells sets the minimum width and height of the container in cells.
+// A zero value means that the dimension isn't constrained.
+// If the container is a part of a split and it gets less space than its
+// minimum size, it collapses and its sibling takes up the entire area of the
+// split. If there is no such sibling or the sibling is also too small, the
+// container draws a placeholder indicating that a resize is needed instead of
+// its border, widget or sub containers.
+// The values must be zero or positive integers.
+func MinSizeCells(w, h int) Option {
+	return option(func(c *Container) error {
+		if min := 0; w < min || h < min {
+			return fmt.Errorf("invalid MinSizeCells(%d, %d), must be in range %d <= value", w, h, min)
+		}
+		if err := validateSizeCells(image.Point{w, h}, c.opts.maxSize); err != nil {
+			return err
+		}
+		c.opts.minSize = image.Point{w, h}
+		return nil
+	})
+}
+
+// MaxSizeCells sets the maximum width and height of the container in cells.
+// A zero value means that the dimension isn't constrained.
+// If the container gets more space than its maximum size, it only occupies
+// the top left part of the space up to its maximum size.
+// The values must be zero or positive integers and must not be smaller than
+// the values provided to MinSizeCells.
+func MaxSizeCells(w, h int) Option {
+	return option(func(c *Container) error {
+		if min := 0; w < min || h < min {
+			return fmt.Errorf("invalid MaxSizeCells(%d, %d), must be in range %d <= value", w, h, min)
+		}
+		if err := validateSizeCells(c.opts.minSize, image.Point{w, h}); err != nil {
+			return err
+		}
+		c.opts.maxSize = image.Point{w, h}
+		return nil
+	})
+}
+
+// validateSizeCells validates that the minimum size isn't larger than the
+// maximum size in any of the constrained dimensions.
+func validateSizeCells(min, max image.Point) error {
+	if (max.X > 0 && min.X > max.X) || (max.Y > 0 && min.Y > max.Y) {
+		return fmt.Errorf("MinSizeCells(%d, %d) cannot be larger than MaxSizeCells(%d, %d)", min.X, min.Y, max.X, max.Y)
+	}
+	return nil
+}
+
 // AlignHorizontal sets the horizontal alignment for the widget placed in the
 // container. Has no effect if the container contains no widget.
 // Defaults to alignment in the center.
@@ -696,9 +851,34 @@ func AlignVertical(v align.Vertical) Option {
 }
 
 // Border configures the container to have a border of the specified style.
+// Replaces any custom characters set by BorderChars.
 func Border(ls linestyle.LineStyle) Option {
 	return option(func(c *Container) error {
 		c.opts.border = ls
+		c.opts.borderChars = nil
+		return nil
+	})
+}
+
+// BorderChars configures the container to have a border drawn with the
+// provided custom characters, e.g. linestyle.ASCIIBorder for terminals that
+// cannot display the box-drawing characters. All the characters must be
+// half-width runes that occupy exactly one cell.
+// Replaces the line style set by Border.
+func BorderChars(chars linestyle.BorderChars) Option {
+	return option(func(c *Container) error {
+		for _, r := range []rune{
+			chars.TopLeft, chars.TopRight, chars.BottomLeft, chars.BottomRight,
+			chars.Top, chars.Bottom, chars.Left, chars.Right,
+		} {
+			if rw := runewidth.RuneWidth(r); rw != 1 {
+				return fmt.Errorf("invalid BorderChars rune %q, it occupies %d cells, must be a half-width rune occupying exactly one cell", r, rw)
+			}
+		}
+		c.opts.borderChars = &chars
+		if c.opts.border == linestyle.None {
+			c.opts.border = linestyle.Light
+		}
 		return nil
 	})
 }
@@ -742,11 +922,106 @@ func BorderTitleAlignRight() Option {
 	})
 }
 
+// BorderTitleCellOpts sets additional cell options on the border title, e.g.
+// cell.Bold(). The options are applied on top of the colors set by TitleColor
+// and TitleFocusedColor.
+func BorderTitleCellOpts(opts ...cell.Option) Option {
+	return option(func(c *Container) error {
+		c.opts.borderTitleCellOpts = opts
+		return nil
+	})
+}
+
+// BorderBottomTitle sets a secondary text title within the bottom border.
+// The title has the same colors as the title on the top border.
+func BorderBottomTitle(title string) Option {
+	return option(func(c *Container) error {
+		c.opts.borderBottomTitle = title
+		return nil
+	})
+}
+
+// BorderBottomTitleAlignLeft aligns the bottom border title on the left.
+func BorderBottomTitleAlignLeft() Option {
+	return option(func(c *Container) error {
+		c.opts.borderBottomTitleHAlign = align.HorizontalLeft
+		return nil
+	})
+}
+
+// BorderBottomTitleAlignCenter aligns the bottom border title in the center.
+func BorderBottomTitleAlignCenter() Option {
+	return option(func(c *Container) error {
+		c.opts.borderBottomTitleHAlign = align.HorizontalCenter
+		return nil
+	})
+}
+
+// BorderBottomTitleAlignRight aligns the bottom border title on the right.
+func BorderBottomTitleAlignRight() Option {
+	return option(func(c *Container) error {
+		c.opts.borderBottomTitleHAlign = align.HorizontalRight
+		return nil
+	})
+}
+
+// BorderBottomTitleCellOpts sets additional cell options on the bottom border
+// title, e.g. cell.Bold(). The options are applied on top of the colors set by
+// TitleColor and TitleFocusedColor.
+func BorderBottomTitleCellOpts(opts ...cell.Option) Option {
+	return option(func(c *Container) error {
+		c.opts.borderBottomTitleCellOpts = opts
+		return nil
+	})
+}
+
+// BorderButton is a clickable glyph that can be placed onto the top border of
+// a container, e.g. a close '✕' or a collapse '▾' button.
+type BorderButton struct {
+	// Rune is the glyph drawn on the border. Must be a half-width rune that
+	// occupies exactly one cell.
+	Rune rune
+
+	// OnClick is called when the glyph is clicked with the left mouse button.
+	// The function is called without holding the container lock, so it is
+	// allowed to call Container.Update.
+	// Any error returned by the function is reported to the error handler.
+	OnClick func() error
+}
+
+// BorderButtons places clickable glyphs at the right end of the container's
+// top border. The buttons are drawn from the right to the left in the order
+// they were provided. The border title is never drawn over the buttons.
+//
+// Buttons are only drawn if the container has a border and only as many
+// buttons are drawn as fit onto the border. If the terminal doesn't deliver
+// mouse events, the buttons are drawn as static glyphs.
+//
+// Each call replaces any buttons set previously, calling this option without
+// any buttons removes them.
+func BorderButtons(buttons ...BorderButton) Option {
+	return option(func(c *Container) error {
+		var bbs []*borderButton
+		for _, b := range buttons {
+			if rw := runewidth.RuneWidth(b.Rune); rw != 1 {
+				return fmt.Errorf("invalid BorderButton rune %q, it occupies %d cells, must be a half-width rune occupying exactly one cell", b.Rune, rw)
+			}
+			if b.OnClick == nil {
+				return fmt.Errorf("invalid BorderButton %q, the OnClick function must not be nil", b.Rune)
+			}
+			bbs = append(bbs, newBorderButton(b))
+		}
+		c.opts.borderButtons = bbs
+		return nil
+	})
+}
+
 // BorderColor sets the color of the border around the container.
 // This option is inherited to sub containers created by container splits.
 func BorderColor(color cell.Color) Option {
 	return option(func(c *Container) error {
 		c.opts.inherited.borderColor = color
+		c.opts.inherited.borderColorSet = true
 		return nil
 	})
 }
@@ -757,6 +1032,7 @@ func BorderColor(color cell.Color) Option {
 func FocusedColor(color cell.Color) Option {
 	return option(func(c *Container) error {
 		c.opts.inherited.focusedColor = color
+		c.opts.inherited.focusedColorSet = true
 		return nil
 	})
 }
@@ -780,6 +1056,33 @@ func TitleFocusedColor(color cell.Color) Option {
 	})
 }
 
+// DebugLayout enables an overlay that helps debugging of the layout. The
+// overlay outlines every container with a thin line and annotates it with the
+// container's ID (if set) and its size in cells. The overlay is drawn on top
+// of the containers and their widgets after everything else. Containers that
+// share edges with their parents draw over the parent's outline.
+//
+// This option is global and applies to all created containers.
+// The overlay is disabled by default.
+func DebugLayout() Option {
+	return option(func(c *Container) error {
+		c.opts.global.debugLayout = true
+		return nil
+	})
+}
+
+// DebugLayoutColor sets the color of the overlay enabled by the DebugLayout
+// option.
+//
+// This option is global and applies to all created containers.
+// Defaults to DefaultDebugLayoutColor.
+func DebugLayoutColor(color cell.Color) Option {
+	return option(func(c *Container) error {
+		c.opts.global.debugLayoutColor = color
+		return nil
+	})
+}
+
 // splitType identifies how a container is split.
 type splitType int
 
@@ -938,6 +1241,22 @@ func KeyFocusPrevious(key keyboard.Key) Option {
 	})
 }
 
+// KeyFocusTab configures Tab as the KeyFocusNext key and Shift-Tab
+// (keyboard.KeyBacktab) as the KeyFocusPrevious key, which is the focus
+// traversal most users expect. The focused container is indicated by the
+// color of its border, see FocusedColor.
+//
+// Note that the termbox backend doesn't report Shift-Tab, so only Tab moves the
+// focus there. This option is global and applies to all created containers.
+func KeyFocusTab() Option {
+	return option(func(c *Container) error {
+		next, previous := keyboard.KeyTab, keyboard.KeyBacktab
+		c.opts.global.keyFocusNext = &next
+		c.opts.global.keyFocusPrevious = &previous
+		return nil
+	})
+}
+
 // KeyFocusSkip indicates that this container should never receive the keyboard
 // focus when KeyFocusNext or KeyFocusPrevious is pressed.
 //
@@ -951,6 +1270,27 @@ func KeyFocusSkip() Option {
 	})
 }
 
+// TabIndex sets the position of this container in the order in which the
+// keyboard focus visits the containers when it is moved by either of
+// (KeyFocusNext, KeyFocusPrevious, KeyFocusGroupsNext, KeyFocusGroupsPrevious).
+// Containers with lower index are visited first. Containers without an index
+// are visited after all the containers that have one, in the DFS
+// (Depth-first search) order. Containers that share the same index are also
+// visited in the DFS order. The index must be a zero or a positive number.
+//
+// Only has effect on containers that can receive the keyboard focus, i.e. the
+// leaf containers.
+// If not specified, the container is visited in the DFS order.
+func TabIndex(n int) Option {
+	return option(func(c *Container) error {
+		if min := 0; n < min {
+			return fmt.Errorf("invalid TabIndex %d, must be %d <= index", n, min)
+		}
+		c.opts.tabIndex = &n
+		return nil
+	})
+}
+
 // FocusGroup represents a group of containers that can have the keyboard focus
 // moved between them sharing the same keyboard key.
 type FocusGroup int
@@ -0,0 +1,156 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// reconcile.go contains code that preserves the state of containers when their
+// options are replaced by Reconcile.
+
+import (
+	"image"
+	"reflect"
+
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// contState is the state of a container with an ID preserved by Reconcile.
+type contState struct {
+	// widget is the widget placed in the container, nil if none.
+	widget widgetapi.Widget
+
+	// activeTab is the label of the displayed tab page, empty if the
+	// container doesn't have tabs.
+	activeTab string
+
+	// splitPercent is the split percentage of a split that can be dragged
+	// with the mouse, nil if the split can't be dragged.
+	splitPercent *int
+
+	// scrollOffset is the position of the viewport of a container with the
+	// Scrollable option, nil if the container isn't scrollable.
+	scrollOffset *image.Point
+}
+
+// saveStates returns the states of the containers with IDs in the subtree
+// rooted at the container, including pages of tabs that aren't displayed.
+func saveStates(c *Container) map[string]*contState {
+	states := map[string]*contState{}
+	var errStr string
+	preOrderAll(c, &errStr, visitFunc(func(c *Container) error {
+		if c.opts.id == "" {
+			return nil
+		}
+		st := &contState{
+			widget: c.opts.widget,
+		}
+		if c.isTabbed() {
+			t := c.opts.tabs
+			st.activeTab = t.pages[t.active].label
+		}
+		if c.opts.splitDrag != nil {
+			perc := c.opts.splitPercent
+			st.splitPercent = &perc
+		}
+		if c.isScrollable() {
+			offset := c.opts.scroll.offset
+			st.scrollOffset = &offset
+		}
+		states[c.opts.id] = st
+		return nil
+	}))
+	return states
+}
+
+// restoreStates restores the states of the containers in the subtree rooted at
+// the container whose IDs match the saved states.
+func restoreStates(c *Container, states map[string]*contState) {
+	var errStr string
+	preOrderAll(c, &errStr, visitFunc(func(c *Container) error {
+		st, ok := states[c.opts.id]
+		if !ok || c.opts.id == "" {
+			return nil
+		}
+
+		// Only keep the existing widget if it is of the same type as the
+		// newly placed one, otherwise the new one is meant to replace it.
+		if w := c.opts.widget; w != nil && st.widget != nil && reflect.TypeOf(w) == reflect.TypeOf(st.widget) {
+			// The widget receives the function that marks it dirty again
+			// when drawn in this container.
+			c.opts.widget = st.widget
+		}
+
+		if c.isTabbed() && st.activeTab != "" {
+			for i, p := range c.opts.tabs.pages {
+				if p.label == st.activeTab {
+					c.opts.tabs.active = i
+					c.first = p.cont
+					break
+				}
+			}
+		}
+
+		if c.opts.splitDrag != nil && st.splitPercent != nil {
+			c.opts.splitPercent = *st.splitPercent
+		}
+		if c.isScrollable() && st.scrollOffset != nil {
+			c.opts.scroll.offset = *st.scrollOffset
+		}
+		return nil
+	}))
+}
+
+// Reconcile is like Update, but preserves the state of the containers in the
+// updated subtree whose IDs match containers that existed before the update.
+// This allows rebuilding the options of a dynamic layout, e.g. when panels
+// are added or removed, without losing the state the user sees.
+//
+// For containers with matching IDs:
+//   - The existing widget instance is kept if the new options place a widget
+//     of the same type, so that its state (e.g. a scroll position or a zoom)
+//     is preserved. The newly placed widget instance is discarded, provide
+//     new data to the existing widget through its own API.
+//   - The tab page with the same label remains displayed.
+//   - Splits that can be dragged with the mouse keep their current position.
+//   - Scrollable containers keep their scroll position.
+//
+// The keyboard focus stays on the container with the same ID as the one
+// focused before the update, if such container still exists.
+func (c *Container) Reconcile(id string, opts ...Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target, err := findID(c, id)
+	if err != nil {
+		return err
+	}
+	states := saveStates(target)
+	focusedID := c.focusTracker.active().opts.id
+
+	if err := c.update(target, opts...); err != nil {
+		return err
+	}
+	restoreStates(target, states)
+
+	if _, ok := states[focusedID]; ok && focusedID != "" {
+		if focused, err := findID(c, focusedID); err == nil {
+			fallback := c.focusTracker.active()
+			c.focusTracker.setActive(focused)
+			if !c.focusTracker.reachableFrom(eventRoots(c)...) {
+				// E.g. on a tab page that isn't displayed.
+				c.focusTracker.setActive(fallback)
+			}
+		}
+	}
+	return nil
+}
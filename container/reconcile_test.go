@@ -0,0 +1,166 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustFind returns the container with the ID or fails the test.
+func mustFind(t *testing.T, c *Container, id string) *Container {
+	t.Helper()
+	found, err := findID(c, id)
+	if err != nil {
+		t.Fatalf("findID(%q) => unexpected error: %v", id, err)
+	}
+	return found
+}
+
+func TestReconcile(t *testing.T) {
+	ft, err := faketerm.New(image.Point{40, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+
+	keep := fakewidget.New(widgetapi.Options{})
+	replace := fakewidget.New(widgetapi.Options{})
+	cont, err := New(
+		ft,
+		ID("root"),
+		SplitVertical(
+			Left(ID("keep"), Scrollable(0, 30), PlaceWidget(keep)),
+			Right(ID("right"),
+				SplitHorizontal(
+					Top(ID("replace"), PlaceWidget(replace)),
+					Bottom(ID("tabs"), Tabs(
+						Tab("a", ID("a")),
+						Tab("b", ID("b")),
+					)),
+				),
+			),
+			SplitDraggable(10, 90),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	// State changed by the user.
+	mustFind(t, cont, "root").opts.splitPercent = 30
+	mustFind(t, cont, "keep").opts.scroll.offset = image.Point{0, 5}
+	mustFind(t, cont, "tabs").selectTab(1)
+	cont.focusTracker.setActive(mustFind(t, cont, "b"))
+
+	newKeep := fakewidget.New(widgetapi.Options{})
+	newReplace := &dirtyWidget{Mirror: fakewidget.New(widgetapi.Options{})}
+	added := fakewidget.New(widgetapi.Options{})
+	if err := cont.Reconcile("root",
+		SplitVertical(
+			Left(ID("keep"), Scrollable(0, 30), PlaceWidget(newKeep)),
+			Right(ID("right"),
+				SplitHorizontal(
+					Top(ID("replace"), PlaceWidget(newReplace)),
+					Bottom(ID("tabs"), Tabs(
+						Tab("new", ID("added"), PlaceWidget(added)),
+						Tab("a", ID("a")),
+						Tab("b", ID("b")),
+					)),
+				),
+			),
+			SplitDraggable(10, 90),
+		),
+	); err != nil {
+		t.Fatalf("Reconcile => unexpected error: %v", err)
+	}
+
+	if got := mustFind(t, cont, "keep").opts.widget; got != keep {
+		t.Errorf("Reconcile => widget %p in container keep, want the existing %p", got, keep)
+	}
+	if got := mustFind(t, cont, "replace").opts.widget; got != newReplace {
+		t.Errorf("Reconcile => widget %p in container replace, want the new one of a different type %p", got, newReplace)
+	}
+	if got := mustFind(t, cont, "added").opts.widget; got != added {
+		t.Errorf("Reconcile => widget %p in container added, want the new one %p", got, added)
+	}
+	if got, want := mustFind(t, cont, "root").opts.splitPercent, 30; got != want {
+		t.Errorf("Reconcile => split percent %d, want the dragged %d", got, want)
+	}
+	if got, want := mustFind(t, cont, "keep").opts.scroll.offset, (image.Point{0, 5}); got != want {
+		t.Errorf("Reconcile => scroll offset %v, want the scrolled %v", got, want)
+	}
+	tabs := mustFind(t, cont, "tabs")
+	if got, want := tabs.opts.tabs.active, 2; got != want {
+		t.Errorf("Reconcile => active tab %d, want %d", got, want)
+	}
+	if got, want := tabs.first, mustFind(t, cont, "b"); got != want {
+		t.Errorf("Reconcile => displayed page %v, want %v", got, want)
+	}
+	if got, want := cont.focusTracker.active(), mustFind(t, cont, "b"); got != want {
+		t.Errorf("Reconcile => focused %v, want %v", got, want)
+	}
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	// Update doesn't preserve the state.
+	if err := cont.Update("keep", PlaceWidget(newKeep)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	if got := mustFind(t, cont, "keep").opts.widget; got != newKeep {
+		t.Errorf("Update => widget %p in container keep, want the new one %p", got, newKeep)
+	}
+}
+
+func TestReconcileFocusFallback(t *testing.T) {
+	ft, err := faketerm.New(image.Point{40, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := New(
+		ft,
+		ID("root"),
+		SplitVertical(
+			Left(ID("left")),
+			Right(ID("right")),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	cont.focusTracker.setActive(mustFind(t, cont, "right"))
+
+	// The focused container moves to a tab page that isn't displayed.
+	if err := cont.Reconcile("root", Tabs(
+		Tab("a", ID("left")),
+		Tab("b", ID("right")),
+	)); err != nil {
+		t.Fatalf("Reconcile => unexpected error: %v", err)
+	}
+	if got, want := cont.focusTracker.active(), mustFind(t, cont, "root"); got != want {
+		t.Errorf("Reconcile => focused %v, want %v", got, want)
+	}
+
+	if err := cont.Reconcile("missing"); err == nil {
+		t.Errorf("Reconcile => expected an error for a missing ID")
+	}
+}
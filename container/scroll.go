@@ -0,0 +1,391 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// scroll.go contains code that manages containers with the Scrollable option.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// scroll stores the content size and the state of a container with the
+// Scrollable option.
+type scroll struct {
+	// size is the size of the content, zero values mean the size of the
+	// viewport.
+	size image.Point
+	// offset is the position of the top left corner of the viewport in the
+	// content.
+	offset image.Point
+	// viewport is the area of the container that displays the content, as of
+	// the last time the areas of the sub containers were set.
+	viewport image.Rectangle
+
+	keyUp       *keyboard.Key
+	keyDown     *keyboard.Key
+	keyLeft     *keyboard.Key
+	keyRight    *keyboard.Key
+	keyPageUp   *keyboard.Key
+	keyPageDown *keyboard.Key
+}
+
+// ScrollOption is used to provide options to the Scrollable option.
+type ScrollOption interface {
+	// setScroll sets the provided option.
+	setScroll(*scroll) error
+}
+
+// scrollOption implements ScrollOption.
+type scrollOption func(*scroll) error
+
+// setScroll implements ScrollOption.setScroll.
+func (so scrollOption) setScroll(s *scroll) error {
+	return so(s)
+}
+
+// Scrollable makes the container hold content of the specified width and
+// height in cells, which can be larger than the container. The widget or the
+// sub containers are laid out in the content and clipped to the area of the
+// container, i.e. the viewport. A zero width or height means that the content
+// has the size of the viewport in that dimension. The content is never
+// smaller than the viewport.
+//
+// The mouse wheel scrolls the content when the pointer is over the container,
+// unless the pointer is over a widget that wants mouse events. The keys
+// configured via ScrollKeys and ScrollPageKeys scroll the content while the
+// focus is in this container or in any of its sub containers. Moving the
+// keyboard focus to a sub container scrolls it into the view.
+// Cannot be combined with Tabs.
+func Scrollable(width, height int, opts ...ScrollOption) Option {
+	return option(func(c *Container) error {
+		if min := 0; width < min || height < min {
+			return fmt.Errorf("invalid Scrollable(%d, %d), must be in range %d <= value", width, height, min)
+		}
+		s := &scroll{
+			size: image.Point{width, height},
+		}
+		if old := c.opts.scroll; old != nil {
+			// Keep the position when the option is applied again, e.g. by
+			// Container.Update.
+			s.offset = old.offset
+		}
+		for _, opt := range opts {
+			if err := opt.setScroll(s); err != nil {
+				return err
+			}
+		}
+
+		seen := map[keyboard.Key]bool{}
+		for _, k := range []*keyboard.Key{s.keyUp, s.keyDown, s.keyLeft, s.keyRight, s.keyPageUp, s.keyPageDown} {
+			if k == nil {
+				continue
+			}
+			if seen[*k] {
+				return fmt.Errorf("invalid scroll keys, key %v is used more than once", *k)
+			}
+			seen[*k] = true
+		}
+		c.opts.scroll = s
+		return nil
+	})
+}
+
+// ScrollKeys configures the keyboard keys that scroll the content by one cell
+// in each direction. The keys must be unique and are also delivered to the
+// focused widget.
+// Defaults to no keys.
+func ScrollKeys(up, down, left, right keyboard.Key) ScrollOption {
+	return scrollOption(func(s *scroll) error {
+		s.keyUp = &up
+		s.keyDown = &down
+		s.keyLeft = &left
+		s.keyRight = &right
+		return nil
+	})
+}
+
+// ScrollPageKeys configures the keyboard keys that scroll the content up and
+// down by the height of the viewport. The keys must be unique and are also
+// delivered to the focused widget.
+// Defaults to no keys.
+func ScrollPageKeys(up, down keyboard.Key) ScrollOption {
+	return scrollOption(func(s *scroll) error {
+		s.keyPageUp = &up
+		s.keyPageDown = &down
+		return nil
+	})
+}
+
+// isScrollable determines if the container has the Scrollable option.
+func (c *Container) isScrollable() bool {
+	return c.opts.scroll != nil
+}
+
+// contentArea returns the area in the container that is available for its
+// widget or sub containers. For containers with the Scrollable option, this is
+// the area of the content, otherwise the usable area without the padding.
+func (c *Container) contentArea() (image.Rectangle, error) {
+	ar, err := c.opts.padding.apply(c.usable())
+	if err != nil {
+		return image.ZR, err
+	}
+	if !c.isScrollable() {
+		return ar, nil
+	}
+
+	size := c.opts.scroll.size
+	if size.X < ar.Dx() {
+		size.X = ar.Dx()
+	}
+	if size.Y < ar.Dy() {
+		size.Y = ar.Dy()
+	}
+	return image.Rectangle{Min: ar.Min, Max: ar.Min.Add(size)}, nil
+}
+
+// updateViewport records the viewport of a container with the Scrollable
+// option and limits the offset so that the viewport stays within the
+// content.
+func (c *Container) updateViewport() error {
+	vp, err := c.opts.padding.apply(c.usable())
+	if err != nil {
+		return err
+	}
+	c.opts.scroll.viewport = vp
+	c.scrollTo(c.opts.scroll.offset)
+	return nil
+}
+
+// scrollTo moves the viewport to the offset, limited so that the viewport
+// stays within the content.
+func (c *Container) scrollTo(offset image.Point) {
+	s := c.opts.scroll
+	content, err := c.contentArea()
+	if err != nil {
+		return
+	}
+	max := content.Size().Sub(s.viewport.Size())
+	if offset.X > max.X {
+		offset.X = max.X
+	}
+	if offset.Y > max.Y {
+		offset.Y = max.Y
+	}
+	if offset.X < 0 {
+		offset.X = 0
+	}
+	if offset.Y < 0 {
+		offset.Y = 0
+	}
+	if offset != s.offset {
+		s.offset = offset
+		c.MarkDirty()
+	}
+}
+
+// scrolledBy returns the containers with the Scrollable option whose content
+// contains the areas of this container, ordered from the outermost one. If
+// inner is true, this container is included when it has the Scrollable
+// option, i.e. the returned containers contain its content and not just its
+// area.
+func (c *Container) scrolledBy(inner bool) []*Container {
+	var res []*Container
+	cur := c.parent
+	if inner {
+		cur = c
+	}
+	for ; cur != nil; cur = cur.parent {
+		if cur.isScrollable() {
+			res = append([]*Container{cur}, res...)
+		}
+		if isFloating(cur) {
+			break // Displayed above the content of the root.
+		}
+	}
+	return res
+}
+
+// view returns the part of the coordinate space of this container that is
+// visible on the terminal and the shift that converts points in this space
+// into points on the terminal, i.e. the point p is displayed at p.Sub(shift).
+// Returns false if this container isn't in the content of any container with
+// the Scrollable option, the coordinate space is then the terminal itself.
+// If inner is true, returns the view of the content of this container.
+func (c *Container) view(inner bool) (image.Point, image.Rectangle, bool) {
+	scrolled := c.scrolledBy(inner)
+	if len(scrolled) == 0 {
+		return image.ZP, image.ZR, false
+	}
+
+	var shift image.Point
+	clip := scrolled[0].opts.scroll.viewport
+	for i, s := range scrolled {
+		if i > 0 {
+			clip = clip.Intersect(s.opts.scroll.viewport)
+		}
+		clip = clip.Add(s.opts.scroll.offset)
+		shift = shift.Add(s.opts.scroll.offset)
+	}
+	return shift, clip, true
+}
+
+// applyCanvas applies the canvas drawn in the coordinate space of this
+// container onto the terminal. If inner is true, the canvas was drawn in the
+// content of this container.
+func (c *Container) applyCanvas(cvs *canvas.Canvas, inner bool) error {
+	shift, clip, ok := c.view(inner)
+	if !ok {
+		return cvs.ApplyTracked(c.term, c.damage)
+	}
+	return cvs.ApplyTrackedView(c.term, c.damage, shift, clip)
+}
+
+// localPoint converts the point on the terminal into the coordinate space of
+// this container. If inner is true, converts the point into the content of
+// this container. Returns false if the point isn't visible in the content,
+// i.e. if it falls outside of the viewport of any container with the
+// Scrollable option.
+func (c *Container) localPoint(p image.Point, inner bool) (image.Point, bool) {
+	visible := true
+	for _, s := range c.scrolledBy(inner) {
+		if !p.In(s.opts.scroll.viewport) {
+			visible = false
+		}
+		p = p.Add(s.opts.scroll.offset)
+	}
+	return p, visible
+}
+
+// localMouse returns a copy of the mouse event with the position converted
+// into the coordinate space of this container, see localPoint. Positions that
+// aren't visible are converted into a point outside of any area.
+func (c *Container) localMouse(m *terminalapi.Mouse, inner bool) *terminalapi.Mouse {
+	p, ok := c.localPoint(m.Position, inner)
+	if !ok {
+		p = image.Point{-1, -1}
+	}
+	return &terminalapi.Mouse{
+		Position: p,
+		Button:   m.Button,
+		Time:     m.Time,
+	}
+}
+
+// scrollIntoView scrolls the containers with the Scrollable option that
+// contain this container so that as much of its area as possible is visible.
+// Prefers the top left corner of the area if it doesn't fit.
+func (c *Container) scrollIntoView() {
+	ar := c.area
+	for cur := c.parent; cur != nil; cur = cur.parent {
+		if !cur.isScrollable() {
+			continue
+		}
+		s := cur.opts.scroll
+		visible := s.viewport.Add(s.offset)
+		offset := s.offset
+		switch {
+		case ar.Min.X < visible.Min.X || ar.Dx() > visible.Dx():
+			offset.X += ar.Min.X - visible.Min.X
+		case ar.Max.X > visible.Max.X:
+			offset.X += ar.Max.X - visible.Max.X
+		}
+		switch {
+		case ar.Min.Y < visible.Min.Y || ar.Dy() > visible.Dy():
+			offset.Y += ar.Min.Y - visible.Min.Y
+		case ar.Max.Y > visible.Max.Y:
+			offset.Y += ar.Max.Y - visible.Max.Y
+		}
+		cur.scrollTo(offset)
+		ar = ar.Sub(s.offset).Intersect(s.viewport)
+	}
+}
+
+// updateScrollFromKeyboard processes the keyboard event and scrolls the
+// content of the closest container with the Scrollable option that contains
+// the focused container and has the key configured. Only considers containers
+// between the focused container and this container.
+// Caller must hold c.mu.
+func (c *Container) updateScrollFromKeyboard(k *terminalapi.Keyboard) {
+	for cur := c.focusTracker.active(); cur != nil; cur = cur.parent {
+		if s := cur.opts.scroll; s != nil {
+			var by image.Point
+			switch {
+			case s.keyUp != nil && *s.keyUp == k.Key:
+				by = image.Point{0, -1}
+			case s.keyDown != nil && *s.keyDown == k.Key:
+				by = image.Point{0, 1}
+			case s.keyLeft != nil && *s.keyLeft == k.Key:
+				by = image.Point{-1, 0}
+			case s.keyRight != nil && *s.keyRight == k.Key:
+				by = image.Point{1, 0}
+			case s.keyPageUp != nil && *s.keyPageUp == k.Key:
+				by = image.Point{0, -s.viewport.Dy()}
+			case s.keyPageDown != nil && *s.keyPageDown == k.Key:
+				by = image.Point{0, s.viewport.Dy()}
+			}
+			if by != image.ZP {
+				cur.scrollTo(s.offset.Add(by))
+				return
+			}
+		}
+		if cur == c {
+			return
+		}
+	}
+}
+
+// scrollFromMouse processes the mouse wheel event and scrolls the content of
+// the innermost container with the Scrollable option under the pointer,
+// unless the pointer is over a widget that wants mouse events.
+// Caller must hold c.mu.
+func scrollFromMouse(c *Container, m *terminalapi.Mouse) {
+	var by image.Point
+	switch m.Button {
+	case mouse.ButtonWheelUp:
+		by = image.Point{0, -1}
+	case mouse.ButtonWheelDown:
+		by = image.Point{0, 1}
+	case mouse.ButtonWheelLeft:
+		by = image.Point{-1, 0}
+	case mouse.ButtonWheelRight:
+		by = image.Point{1, 0}
+	default:
+		return
+	}
+
+	target := pointCont(c, m.Position)
+	if target == nil {
+		return
+	}
+	if target.hasWidget() && target.opts.widget.Options().WantMouse != widgetapi.MouseScopeNone {
+		return
+	}
+	for cur := target; cur != nil; cur = cur.parent {
+		if !cur.isScrollable() {
+			continue
+		}
+		if p, ok := cur.localPoint(m.Position, false); ok && p.In(cur.opts.scroll.viewport) {
+			cur.scrollTo(cur.opts.scroll.offset.Add(by))
+			return
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustScrolled returns a terminal of the size that displays the part of the
+// content starting at the offset.
+func mustScrolled(size, offset image.Point, content *faketerm.Terminal) *faketerm.Terminal {
+	ft := faketerm.MustNew(size)
+	buf := content.BackBuffer()
+	for x := 0; x < size.X; x++ {
+		for y := 0; y < size.Y; y++ {
+			c := buf[x+offset.X][y+offset.Y]
+			if err := ft.SetCell(image.Point{x, y}, c.Rune, c.Opts); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return ft
+}
+
+// twoBoxes returns a terminal of the content size with the borders of the top
+// and the bottom halves drawn, the bottom one optionally focused.
+func twoBoxes(contentSize image.Point, bottomFocused bool) *faketerm.Terminal {
+	ft := faketerm.MustNew(contentSize)
+	cvs := testcanvas.MustNew(ft.Area())
+	half := contentSize.Y / 2
+	testdraw.MustBorder(cvs, image.Rect(0, 0, contentSize.X, half))
+	var opts []draw.BorderOption
+	if bottomFocused {
+		opts = append(opts, draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+	}
+	testdraw.MustBorder(cvs, image.Rect(0, half, contentSize.X, contentSize.Y), opts...)
+	testcanvas.MustApply(cvs, ft)
+	return ft
+}
+
+func TestScrollable(t *testing.T) {
+	widgetOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeWidget}
+	scrollKeys := ScrollKeys(keyboard.KeyArrowUp, keyboard.KeyArrowDown, keyboard.KeyArrowLeft, keyboard.KeyArrowRight)
+	twoRows := SplitHorizontal(
+		Top(Border(linestyle.Light)),
+		Bottom(Border(linestyle.Light)),
+	)
+
+	tests := []struct {
+		desc       string
+		termSize   image.Point
+		container  func(ft *faketerm.Terminal) (*Container, error)
+		events     []terminalapi.Event
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+	}{
+		{
+			desc:     "fails on a negative content size",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(-1, 0))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on keys that aren't unique",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 8,
+					scrollKeys,
+					ScrollPageKeys(keyboard.KeyPgUp, keyboard.KeyArrowDown),
+				))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails when combined with Tabs",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 8), Tabs(Tab("a")))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "displays the top of the content",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 8), twoRows)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{0, 0}, twoBoxes(image.Point{10, 8}, false))
+			},
+		},
+		{
+			desc:     "content is never smaller than the viewport",
+			termSize: image.Point{10, 8},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(5, 4), twoRows)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return twoBoxes(size, false)
+			},
+		},
+		{
+			desc:     "keys scroll the content",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(12, 8, scrollKeys), twoRows)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{1, 2}, twoBoxes(image.Point{12, 8}, false))
+			},
+		},
+		{
+			desc:     "page keys scroll the content by the height of the viewport",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 12, ScrollPageKeys(keyboard.KeyPgUp, keyboard.KeyPgDn)), twoRows)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{0, 4}, twoBoxes(image.Point{10, 12}, false))
+			},
+		},
+		{
+			desc:     "scrolling stops at the end of the content",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 8, ScrollPageKeys(keyboard.KeyPgUp, keyboard.KeyPgDn)), twoRows)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{0, 4}, twoBoxes(image.Point{10, 8}, false))
+			},
+		},
+		{
+			desc:     "mouse wheel scrolls the content",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 8), twoRows)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonWheelDown},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{0, 1}, twoBoxes(image.Point{10, 8}, false))
+			},
+		},
+		{
+			desc:     "mouse wheel over a widget that wants mouse events is delivered to the widget",
+			termSize: image.Point{30, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Scrollable(0, 12), PlaceWidget(fakewidget.New(widgetOpts)))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonWheelDown},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				content := faketerm.MustNew(image.Point{30, 12})
+				fakewidget.MustDraw(content, testcanvas.MustNew(content.Area()), &widgetapi.Meta{Focused: true}, widgetOpts,
+					&fakewidget.Event{
+						Ev:   &terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonWheelDown},
+						Meta: &widgetapi.EventMeta{Focused: true},
+					},
+				)
+				return mustScrolled(size, image.Point{0, 0}, content)
+			},
+		},
+		{
+			desc:     "mouse events are delivered in the coordinates of the scrolled content",
+			termSize: image.Point{30, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft,
+					Scrollable(0, 12, ScrollPageKeys(keyboard.KeyPgUp, keyboard.KeyPgDn)),
+					PlaceWidget(fakewidget.New(widgetOpts)),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				content := faketerm.MustNew(image.Point{30, 12})
+				fakewidget.MustDraw(content, testcanvas.MustNew(content.Area()), &widgetapi.Meta{Focused: true}, widgetOpts,
+					&fakewidget.Event{
+						Ev:   &terminalapi.Mouse{Position: image.Point{1, 7}, Button: mouse.ButtonLeft},
+						Meta: &widgetapi.EventMeta{Focused: true},
+					},
+				)
+				return mustScrolled(size, image.Point{0, 6}, content)
+			},
+		},
+		{
+			desc:     "moving the keyboard focus scrolls the focused container into the view",
+			termSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft,
+					KeyFocusNext(keyboard.KeyTab),
+					Scrollable(0, 8),
+					twoRows,
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustScrolled(size, image.Point{0, 4}, twoBoxes(image.Point{10, 8}, true))
+			},
+		},
+		{
+			desc:     "content is clipped to the viewport inside of the border",
+			termSize: image.Point{10, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(
+							Border(linestyle.Double),
+							Scrollable(0, 8, ScrollPageKeys(keyboard.KeyPgUp, keyboard.KeyPgDn)),
+							twoRows,
+						),
+						Bottom(),
+						SplitFixed(6),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, ft.Area(), draw.BorderLineStyle(linestyle.Double))
+				testcanvas.MustApply(cvs, ft)
+
+				view := mustScrolled(image.Point{8, 4}, image.Point{0, 0}, twoBoxes(image.Point{8, 8}, false))
+				buf := view.BackBuffer()
+				for x := range buf {
+					for y := range buf[x] {
+						c := buf[x][y]
+						if err := ft.SetCell(image.Point{x + 1, y + 1}, c.Rune, c.Opts); err != nil {
+							panic(err)
+						}
+					}
+				}
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if (err != nil) != tc.wantNewErr {
+				t.Fatalf("tc.container => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+			c.Subscribe(eds)
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+				// The events depend on the areas from the previous draw.
+				if err := testevent.WaitFor(5*time.Second, func() error {
+					if got, want := eds.Processed(), 1; got < want {
+						return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+					}
+					return nil
+				}); err != nil {
+					t.Fatalf("testevent.WaitFor => %v", err)
+				}
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
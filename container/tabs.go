@@ -0,0 +1,326 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// tabs.go contains code that manages containers with tabbed pages.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/button"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// tabPage is one page of a container with the Tabs option.
+type tabPage struct {
+	// label is displayed in the tab header.
+	label string
+	// opts are the options of the container that displays the page.
+	opts []Option
+	// cont is the container that displays the page.
+	cont *Container
+	// fsm tracks mouse clicks on the tab header.
+	fsm *button.FSM
+}
+
+// tabs stores the pages and the state of a container with the Tabs option.
+type tabs struct {
+	pages []*tabPage
+	// active is the index of the displayed page.
+	active int
+
+	keyNext          *keyboard.Key
+	keyPrevious      *keyboard.Key
+	activeCellOpts   []cell.Option
+	inactiveCellOpts []cell.Option
+}
+
+// TabsOption is used to provide options to the Tabs option.
+type TabsOption interface {
+	// setTabs sets the provided option.
+	setTabs(*tabs) error
+}
+
+// tabsOption implements TabsOption.
+type tabsOption func(*tabs) error
+
+// setTabs implements TabsOption.setTabs.
+func (to tabsOption) setTabs(t *tabs) error {
+	return to(t)
+}
+
+// Tabs displays one of several pages in the container with a row of tab
+// headers at the top, which show the labels of the pages. Clicking on a tab
+// header with the left mouse button displays its page, the keys configured
+// via TabKeys switch the pages while the focus is in this container.
+//
+// The pages are created from the Tab options and keep their state, including
+// the state of their widgets, while they aren't displayed. Containers on the
+// pages that aren't displayed don't receive any events and can still be
+// found by their ID, e.g. by Container.Update. The use of this option
+// removes any widget or sub containers of the container.
+func Tabs(opts ...TabsOption) Option {
+	return option(func(c *Container) error {
+		t := &tabs{
+			activeCellOpts: []cell.Option{cell.Inverse()},
+		}
+		for _, opt := range opts {
+			if err := opt.setTabs(t); err != nil {
+				return err
+			}
+		}
+		if len(t.pages) == 0 {
+			return errors.New("the Tabs option requires at least one Tab")
+		}
+		if t.active < 0 || t.active >= len(t.pages) {
+			return fmt.Errorf("invalid ActiveTab %d, must be in range 0 <= index < %d", t.active, len(t.pages))
+		}
+		if t.keyNext != nil && *t.keyNext == *t.keyPrevious {
+			return fmt.Errorf("invalid TabKeys(next:%v, previous:%v), the keys must be unique", *t.keyNext, *t.keyPrevious)
+		}
+
+		for _, p := range t.pages {
+			child, err := newChild(c, p.opts)
+			if err != nil {
+				return err
+			}
+			p.cont = child
+		}
+		c.opts.widget = nil
+		c.opts.tabs = t
+		c.first = t.pages[t.active].cont
+		c.second = nil
+		return nil
+	})
+}
+
+// Tab adds a page to the Tabs option. The label is displayed in the tab
+// header, the options are applied to the container that displays the page.
+// The label must not be empty.
+func Tab(label string, opts ...Option) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		if label == "" {
+			return errors.New("the Tab label cannot be an empty string")
+		}
+		t.pages = append(t.pages, &tabPage{
+			label: label,
+			opts:  opts,
+			fsm:   button.NewFSM(mouse.ButtonLeft, image.ZR),
+		})
+		return nil
+	})
+}
+
+// ActiveTab sets the index of the page that is displayed initially.
+// Defaults to the first page.
+func ActiveTab(index int) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.active = index
+		return nil
+	})
+}
+
+// TabKeys configures the keyboard keys that display the next and the
+// previous page. The keys only work while the focus is in the container with
+// the Tabs option or in any of its sub containers. The keys must be unique
+// and are also delivered to the focused widget.
+// Defaults to no keys, i.e. the pages can only be switched with the mouse.
+func TabKeys(next, previous keyboard.Key) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.keyNext = &next
+		t.keyPrevious = &previous
+		return nil
+	})
+}
+
+// TabCellOpts sets the cell options on the tab headers of the displayed
+// page and of the other pages.
+// Defaults to inverse colors for the displayed page and to the default cell
+// options for the other pages.
+func TabCellOpts(active, inactive []cell.Option) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.activeCellOpts = active
+		t.inactiveCellOpts = inactive
+		return nil
+	})
+}
+
+// isTabbed determines if the container has the Tabs option.
+func (c *Container) isTabbed() bool {
+	return c.opts.tabs != nil
+}
+
+// tabsAreas returns the areas of the tab headers and the area for the
+// displayed page of a container with the Tabs option.
+func (c *Container) tabsAreas() (image.Rectangle, image.Rectangle, error) {
+	ar, err := c.opts.padding.apply(c.usable())
+	if err != nil {
+		return image.ZR, image.ZR, err
+	}
+	if ar.Dy() < 1 {
+		return image.ZR, ar, nil
+	}
+	header := image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+1)
+	page := image.Rect(ar.Min.X, ar.Min.Y+1, ar.Max.X, ar.Max.Y)
+	return header, page, nil
+}
+
+// tabHeaderAreas returns the areas of the tab headers that fit into the header
+// row, in the same order as the pages. Each header contains the label
+// surrounded by spaces, the headers are separated by a single cell.
+func tabHeaderAreas(c *Container) ([]image.Rectangle, error) {
+	header, _, err := c.tabsAreas()
+	if err != nil {
+		return nil, err
+	}
+
+	var areas []image.Rectangle
+	x := header.Min.X
+	for _, p := range c.opts.tabs.pages {
+		end := x + runewidth.StringWidth(p.label) + 2
+		if end > header.Max.X {
+			break
+		}
+		areas = append(areas, image.Rect(x, header.Min.Y, end, header.Max.Y))
+		x = end + 1
+	}
+	return areas, nil
+}
+
+// drawTabs draws the tab headers of a container with the Tabs option.
+func drawTabs(c *Container) error {
+	if !c.isTabbed() {
+		return nil
+	}
+	header, _, err := c.tabsAreas()
+	if err != nil {
+		return err
+	}
+	if header.Empty() {
+		return nil
+	}
+	areas, err := tabHeaderAreas(c)
+	if err != nil {
+		return err
+	}
+
+	cvs, err := canvas.New(header)
+	if err != nil {
+		return err
+	}
+	t := c.opts.tabs
+	for i, ar := range areas {
+		cOpts := t.inactiveCellOpts
+		if i == t.active {
+			cOpts = t.activeCellOpts
+		}
+		start := ar.Min.Sub(header.Min)
+		if err := draw.Text(cvs, " "+t.pages[i].label+" ", start, draw.TextCellOpts(cOpts...)); err != nil {
+			return err
+		}
+		if sep := image.Pt(ar.Max.X-header.Min.X, 0); i < len(areas)-1 {
+			if _, err := cvs.SetCell(sep, '│', cell.FgColor(c.borderColor())); err != nil {
+				return err
+			}
+		}
+	}
+	return c.applyCanvas(cvs, false)
+}
+
+// selectTab displays the page with the index. Moves the focus to the
+// container if the focused container was on the previously displayed page.
+// Caller must hold c.mu.
+func (c *Container) selectTab(index int) {
+	t := c.opts.tabs
+	if index == t.active {
+		return
+	}
+	t.active = index
+	c.first = t.pages[index].cont
+
+	rootCont(c).clearNeeded = true
+	if !c.focusTracker.reachableFrom(eventRoots(c)...) {
+		c.focusTracker.setActive(c)
+	}
+}
+
+// tabClicks processes the mouse event on behalf of the tab headers in the
+// container tree and displays the pages whose headers were clicked.
+// Caller must hold c.mu.
+func tabClicks(c *Container, m *terminalapi.Mouse) error {
+	var (
+		errStr  string
+		clicked = map[*Container]int{}
+	)
+	preOrder(c, &errStr, visitFunc(func(cur *Container) error {
+		if !cur.isTabbed() {
+			return nil
+		}
+		areas, err := tabHeaderAreas(cur)
+		if err != nil {
+			return err
+		}
+		for i, p := range cur.opts.tabs.pages {
+			if i < len(areas) {
+				p.fsm.UpdateArea(areas[i])
+			} else {
+				p.fsm.UpdateArea(image.ZR)
+			}
+			if click, _ := p.fsm.Event(cur.localMouse(m, false)); click {
+				clicked[cur] = i
+			}
+		}
+		return nil
+	}))
+	if errStr != "" {
+		return errors.New(errStr)
+	}
+	// Switch after the traversal, since switching changes the tree.
+	for cur, i := range clicked {
+		cur.selectTab(i)
+	}
+	return nil
+}
+
+// updateTabsFromKeyboard processes the keyboard event and switches the pages
+// of the closest container with the Tabs option that contains the focused
+// container and has the key configured via TabKeys. Only considers containers
+// between the focused container and this container.
+// Caller must hold c.mu.
+func (c *Container) updateTabsFromKeyboard(k *terminalapi.Keyboard) {
+	for cur := c.focusTracker.active(); cur != nil; cur = cur.parent {
+		if t := cur.opts.tabs; t != nil {
+			switch {
+			case t.keyNext != nil && *t.keyNext == k.Key:
+				cur.selectTab((t.active + 1) % len(t.pages))
+				return
+			case t.keyPrevious != nil && *t.keyPrevious == k.Key:
+				cur.selectTab((t.active - 1 + len(t.pages)) % len(t.pages))
+				return
+			}
+		}
+		if cur == c {
+			return
+		}
+	}
+}
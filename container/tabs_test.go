@@ -0,0 +1,355 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustDrawTabHeaders draws the tab headers with the default cell options
+// starting at the provided point.
+func mustDrawTabHeaders(cvs *canvas.Canvas, start image.Point, active int, labels ...string) {
+	x := start.X
+	for i, l := range labels {
+		var opts []cell.Option
+		if i == active {
+			opts = append(opts, cell.Inverse())
+		}
+		text := " " + l + " "
+		testdraw.MustText(cvs, text, image.Point{x, start.Y}, draw.TextCellOpts(opts...))
+		x += len(text)
+		if i < len(labels)-1 {
+			testcanvas.MustSetCell(cvs, image.Point{x, start.Y}, '│', cell.FgColor(cell.ColorDefault))
+			x++
+		}
+	}
+}
+
+func TestTabs(t *testing.T) {
+	widgetOpts := widgetapi.Options{WantKeyboard: widgetapi.KeyScopeFocused, WantMouse: widgetapi.MouseScopeWidget}
+
+	tests := []struct {
+		desc       string
+		termSize   image.Point
+		container  func(ft *faketerm.Terminal) (*Container, error)
+		events     []terminalapi.Event
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+	}{
+		{
+			desc:     "fails without any Tab",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs())
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on an empty label",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(Tab("")))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on ActiveTab out of range",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(Tab("a"), ActiveTab(1)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on TabKeys that aren't unique",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(Tab("a"), TabKeys(keyboard.KeyTab, keyboard.KeyTab)))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "fails on duplicate IDs on different pages",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", ID("id")),
+					Tab("b", ID("id")),
+				))
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:     "draws the tab headers and the active page",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", PlaceWidget(fakewidget.New(widgetOpts))),
+					Tab("bc", PlaceWidget(fakewidget.New(widgetOpts))),
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 0, "a", "bc")
+				testcanvas.MustApply(cvs, ft)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 6)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "draws the initially active page",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", PlaceWidget(fakewidget.New(widgetOpts))),
+					Tab("bc", Border(linestyle.Light)),
+					ActiveTab(1),
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 1, "a", "bc")
+				testdraw.MustBorder(cvs, image.Rect(0, 1, 20, 6))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "draws the tab headers inside the border",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft,
+					Border(linestyle.Light),
+					Tabs(
+						Tab("a", PlaceWidget(fakewidget.New(widgetOpts))),
+						Tab("bc"),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustBorder(cvs, ft.Area(), draw.BorderCellOpts(cell.FgColor(cell.ColorYellow)))
+				mustDrawTabHeaders(cvs, image.Point{1, 1}, 0, "a", "bc")
+				testcanvas.MustApply(cvs, ft)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(1, 2, 19, 5)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "omits tab headers that don't fit",
+			termSize: image.Point{8, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a"),
+					Tab("bcdef"),
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 0, "a")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "clicking on a tab header displays its page",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", Border(linestyle.Light)),
+					Tab("bc", PlaceWidget(fakewidget.New(widgetOpts))),
+				))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 1, "a", "bc")
+				testcanvas.MustApply(cvs, ft)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 6)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "clicking outside of the tab headers doesn't switch pages",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", PlaceWidget(fakewidget.New(widgetOpts))),
+					Tab("bc", Border(linestyle.Light)),
+				))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{15, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{15, 0}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 0, "a", "bc")
+				testcanvas.MustApply(cvs, ft)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 6)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "keyboard keys switch pages",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", Border(linestyle.Light)),
+					Tab("bc", Border(linestyle.Light)),
+					Tab("d", PlaceWidget(fakewidget.New(widgetOpts))),
+					TabKeys(keyboard.KeyArrowRight, keyboard.KeyArrowLeft),
+				))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 2, "a", "bc", "d")
+				testcanvas.MustApply(cvs, ft)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 6)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "switching pages moves the focus off the hidden page",
+			termSize: image.Point{20, 6},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					Tab("a", PlaceWidget(fakewidget.New(widgetOpts))),
+					Tab("bc", PlaceWidget(fakewidget.New(widgetOpts))),
+					TabKeys(keyboard.KeyArrowRight, keyboard.KeyArrowLeft),
+				))
+			},
+			events: []terminalapi.Event{
+				// Focus the widget on the first page.
+				&terminalapi.Mouse{Position: image.Point{10, 3}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{10, 3}, Button: mouse.ButtonRelease},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTabHeaders(cvs, image.Point{0, 0}, 1, "a", "bc")
+				testcanvas.MustApply(cvs, ft)
+				// The widget on the displayed page isn't focused and didn't
+				// receive the key.
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 6)), &widgetapi.Meta{}, widgetOpts)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if (err != nil) != tc.wantNewErr {
+				t.Fatalf("tc.container => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+			c.Subscribe(eds)
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestTabsUpdate(t *testing.T) {
+	ft, err := faketerm.New(image.Point{20, 6})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	c, err := New(ft, Tabs(
+		Tab("a", ID("first")),
+		Tab("b", ID("second")),
+	))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	// The second page isn't displayed, but is still found by its ID.
+	if err := c.Update("second", Border(linestyle.Light)); err != nil {
+		t.Fatalf("Update => unexpected error: %v", err)
+	}
+	c.opts.tabs.pages[1].cont.mu.Lock()
+	defer c.opts.tabs.pages[1].cont.mu.Unlock()
+	if c.opts.tabs.pages[1].cont.opts.border != linestyle.Light {
+		t.Errorf("Update => didn't update the container on the hidden page")
+	}
+}
@@ -62,6 +62,35 @@ func postOrder(c *Container, errStr *string, visit visitFunc) {
 	}
 }
 
+// preOrderAll performs pre-order DFS traversal on the container tree like
+// preOrder, but also visits the pages of containers with the Tabs option that
+// aren't displayed and the modal and the layers displayed above the root
+// container.
+func preOrderAll(c *Container, errStr *string, visit visitFunc) {
+	if c == nil || *errStr != "" {
+		return
+	}
+
+	if err := visit(c); err != nil {
+		*errStr = err.Error()
+		return
+	}
+	if c.modal != nil {
+		defer preOrderAll(c.modal.cont, errStr, visit)
+	}
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		defer preOrderAll(c.layers[i].cont, errStr, visit)
+	}
+	if c.isTabbed() {
+		for _, p := range c.opts.tabs.pages {
+			preOrderAll(p.cont, errStr, visit)
+		}
+		return
+	}
+	preOrderAll(c.first, errStr, visit)
+	preOrderAll(c.second, errStr, visit)
+}
+
 // findID finds container with the provided ID.
 // Returns an error of there is no container with the specified ID.
 func findID(root *Container, id string) (*Container, error) {
@@ -73,7 +102,7 @@ func findID(root *Container, id string) (*Container, error) {
 		errStr string
 		cont   *Container
 	)
-	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+	preOrderAll(root, &errStr, visitFunc(func(c *Container) error {
 		if c.opts.id == id {
 			cont = c
 		}
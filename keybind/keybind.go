@@ -0,0 +1,346 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keybind implements a registry of named actions bound to keyboard
+// keys.
+//
+// Applications register actions with their default key sequences and route
+// keyboard events to the registry, which calls the handler of the matching
+// action. The bindings can be queried and overridden at runtime, e.g. to let
+// users remap the keys from a configuration file.
+//
+// A key sequence consists of one or more keys that must be pressed one after
+// another, e.g. 'g' followed by 'g'.
+package keybind
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// Sequence is a sequence of keys pressed one after another.
+type Sequence []keyboard.Key
+
+// Keys returns a sequence of the provided keys.
+func Keys(keys ...keyboard.Key) Sequence {
+	return Sequence(keys)
+}
+
+// String implements fmt.Stringer.
+// The keys are separated by spaces, the format is accepted by ParseSequence.
+func (s Sequence) String() string {
+	var names []string
+	for _, k := range s {
+		if k == keyboard.KeySpace {
+			names = append(names, "KeySpace")
+			continue
+		}
+		names = append(names, k.String())
+	}
+	return strings.Join(names, " ")
+}
+
+// equal asserts whether the two sequences consist of the same keys.
+func (s Sequence) equal(other Sequence) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i, k := range s {
+		if other[i] != k {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPrefix asserts whether the sequence starts with the prefix.
+func (s Sequence) hasPrefix(prefix Sequence) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)].equal(prefix)
+}
+
+// copySeqs returns a deep copy of the sequences.
+func copySeqs(seqs []Sequence) []Sequence {
+	if seqs == nil {
+		return nil
+	}
+	res := make([]Sequence, len(seqs))
+	for i, s := range seqs {
+		res[i] = append(Sequence(nil), s...)
+	}
+	return res
+}
+
+// keyNames maps the names of the special keys to their values.
+var keyNames = func() map[string]keyboard.Key {
+	names := map[string]keyboard.Key{
+		"KeySpace": keyboard.KeySpace,
+	}
+	for k := keyboard.KeyF1; k >= keyboard.KeyBacktab; k-- {
+		names[k.String()] = k
+	}
+	return names
+}()
+
+// ParseSequence parses a sequence from its string representation as returned
+// by Sequence.String. The keys are separated by spaces, each key is either a
+// single printable character or the name of a special key, e.g. "KeyCtrlS" or
+// "KeySpace".
+func ParseSequence(s string) (Sequence, error) {
+	var seq Sequence
+	for _, f := range strings.Fields(s) {
+		if k, ok := keyNames[f]; ok {
+			seq = append(seq, k)
+			continue
+		}
+		if r := []rune(f); len(r) == 1 {
+			seq = append(seq, keyboard.Key(r[0]))
+			continue
+		}
+		return nil, fmt.Errorf("invalid key %q in sequence %q", f, s)
+	}
+	if len(seq) == 0 {
+		return nil, fmt.Errorf("the sequence %q doesn't contain any keys", s)
+	}
+	return seq, nil
+}
+
+// Handler is called when the key sequence of an action was pressed.
+// The argument is the last keyboard event of the sequence.
+type Handler func(k *terminalapi.Keyboard)
+
+// action is a registered action.
+type action struct {
+	// description is the human readable description of the action.
+	description string
+	// defaults are the default bindings of the action.
+	defaults []Sequence
+	// bindings are the current bindings of the action.
+	bindings []Sequence
+	// handler is called when the action is triggered.
+	handler Handler
+}
+
+// Registry stores named actions and their key bindings and routes keyboard
+// events to the handlers of the actions.
+//
+// This object is thread-safe.
+type Registry struct {
+	// actions are the registered actions by their names.
+	actions map[string]*action
+	// names are the names of the actions in the order of their registration.
+	names []string
+	// pending are the keys of a sequence pressed so far.
+	pending Sequence
+
+	// mu protects the Registry.
+	mu sync.Mutex
+}
+
+// New returns a new empty Registry.
+func New() *Registry {
+	return &Registry{
+		actions: map[string]*action{},
+	}
+}
+
+// Register registers an action with the provided name, human readable
+// description and default key sequences. The name must be unique and the
+// sequences cannot conflict with the bindings of other actions, i.e. no
+// sequence can be equal to or a prefix of another bound sequence.
+func (r *Registry) Register(name, description string, h Handler, defaults ...Sequence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name == "" {
+		return errors.New("the action name cannot be empty")
+	}
+	if _, ok := r.actions[name]; ok {
+		return fmt.Errorf("the action %q is already registered", name)
+	}
+	if h == nil {
+		return fmt.Errorf("the action %q requires a non-nil handler", name)
+	}
+	if err := r.validate(name, defaults); err != nil {
+		return err
+	}
+
+	r.actions[name] = &action{
+		description: description,
+		defaults:    copySeqs(defaults),
+		bindings:    copySeqs(defaults),
+		handler:     h,
+	}
+	r.names = append(r.names, name)
+	return nil
+}
+
+// Bind replaces the bindings of the named action with the provided sequences.
+// Binding no sequences unbinds the action. The sequences cannot conflict with
+// the bindings of other actions.
+func (r *Registry) Bind(name string, seqs ...Sequence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.actions[name]
+	if !ok {
+		return fmt.Errorf("the action %q isn't registered", name)
+	}
+	if err := r.validate(name, seqs); err != nil {
+		return err
+	}
+	a.bindings = copySeqs(seqs)
+	r.pending = nil
+	return nil
+}
+
+// Reset restores the default bindings of the named action.
+func (r *Registry) Reset(name string) error {
+	r.mu.Lock()
+	a, ok := r.actions[name]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("the action %q isn't registered", name)
+	}
+	return r.Bind(name, a.defaults...)
+}
+
+// Bindings returns the current bindings of the named action.
+func (r *Registry) Bindings(name string) ([]Sequence, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.actions[name]
+	if !ok {
+		return nil, fmt.Errorf("the action %q isn't registered", name)
+	}
+	return copySeqs(a.bindings), nil
+}
+
+// Description returns the description of the named action.
+func (r *Registry) Description(name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.actions[name]
+	if !ok {
+		return "", fmt.Errorf("the action %q isn't registered", name)
+	}
+	return a.description, nil
+}
+
+// Actions returns the names of the registered actions in the order of their
+// registration.
+func (r *Registry) Actions() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.names...)
+}
+
+// Lookup returns the name of the action bound to the sequence.
+// Returns false if the sequence isn't bound to any action.
+func (r *Registry) Lookup(seq Sequence) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range r.names {
+		for _, b := range r.actions[name].bindings {
+			if b.equal(seq) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Handle processes the keyboard event and calls the handler of the action
+// whose sequence was completed by it. Returns true if the key was consumed,
+// i.e. it completed a sequence or started or continued one. A key that
+// doesn't continue the pending sequence discards it and is then matched on
+// its own.
+//
+// Can be used with termdash.KeyboardSubscriber to route all the keyboard
+// events to the registry.
+func (r *Registry) Handle(k *terminalapi.Keyboard) bool {
+	r.mu.Lock()
+	h, consumed := r.match(k.Key)
+	if !consumed && len(r.pending) > 0 {
+		r.pending = nil
+		h, consumed = r.match(k.Key)
+	}
+	r.mu.Unlock()
+
+	// Called without holding the lock so that handlers can modify the
+	// registry.
+	if h != nil {
+		h(k)
+	}
+	return consumed
+}
+
+// match appends the key to the pending sequence and returns the handler of
+// the action whose sequence was completed. Returns false if the key doesn't
+// match any sequence, the pending sequence is left unchanged in that case.
+// Caller must hold r.mu.
+func (r *Registry) match(key keyboard.Key) (Handler, bool) {
+	seq := append(append(Sequence(nil), r.pending...), key)
+	prefix := false
+	for _, name := range r.names {
+		a := r.actions[name]
+		for _, b := range a.bindings {
+			switch {
+			case b.equal(seq):
+				r.pending = nil
+				return a.handler, true
+			case b.hasPrefix(seq):
+				prefix = true
+			}
+		}
+	}
+	if prefix {
+		r.pending = seq
+		return nil, true
+	}
+	return nil, false
+}
+
+// validate validates the sequences to be bound to the named action.
+// Caller must hold r.mu.
+func (r *Registry) validate(name string, seqs []Sequence) error {
+	for i, s := range seqs {
+		if len(s) == 0 {
+			return fmt.Errorf("the action %q cannot be bound to an empty sequence", name)
+		}
+		for _, other := range seqs[:i] {
+			if s.hasPrefix(other) || other.hasPrefix(s) {
+				return fmt.Errorf("the sequences %q and %q of action %q conflict", other, s, name)
+			}
+		}
+		for _, otherName := range r.names {
+			if otherName == name {
+				continue
+			}
+			for _, b := range r.actions[otherName].bindings {
+				if s.hasPrefix(b) || b.hasPrefix(s) {
+					return fmt.Errorf("the sequence %q of action %q conflicts with the sequence %q of action %q", s, name, b, otherName)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keybind
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		desc    string
+		s       string
+		want    Sequence
+		wantErr bool
+	}{
+		{
+			desc:    "fails on an empty string",
+			s:       "  ",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on an unknown key name",
+			s:       "KeyFoo",
+			wantErr: true,
+		},
+		{
+			desc: "parses a printable character",
+			s:    "q",
+			want: Keys('q'),
+		},
+		{
+			desc: "parses special keys",
+			s:    "KeyCtrlX KeyCtrlS",
+			want: Keys(keyboard.KeyCtrlX, keyboard.KeyCtrlS),
+		},
+		{
+			desc: "parses the space",
+			s:    "KeySpace KeyBacktab",
+			want: Keys(keyboard.KeySpace, keyboard.KeyBacktab),
+		},
+		{
+			desc: "parses unicode characters",
+			s:    "g ž",
+			want: Keys('g', 'ž'),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := ParseSequence(tc.s)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSequence => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("ParseSequence => unexpected diff (-want, +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			// The string representation parses back to the same sequence.
+			again, err := ParseSequence(got.String())
+			if err != nil {
+				t.Fatalf("ParseSequence(%q) => unexpected error: %v", got.String(), err)
+			}
+			if diff := pretty.Compare(got, again); diff != "" {
+				t.Errorf("ParseSequence(%q) => unexpected diff (-want, +got):\n%s", got.String(), diff)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	noop := func(*terminalapi.Keyboard) {}
+
+	tests := []struct {
+		desc     string
+		existing map[string][]Sequence
+		name     string
+		handler  Handler
+		defaults []Sequence
+		wantErr  bool
+	}{
+		{
+			desc:    "fails on an empty name",
+			handler: noop,
+			wantErr: true,
+		},
+		{
+			desc:    "fails on a nil handler",
+			name:    "quit",
+			wantErr: true,
+		},
+		{
+			desc:     "fails on a duplicate name",
+			existing: map[string][]Sequence{"quit": nil},
+			name:     "quit",
+			handler:  noop,
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on an empty sequence",
+			name:     "quit",
+			handler:  noop,
+			defaults: []Sequence{Keys()},
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on sequences of the action that conflict",
+			name:     "top",
+			handler:  noop,
+			defaults: []Sequence{Keys('g'), Keys('g', 'g')},
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on a sequence bound to another action",
+			existing: map[string][]Sequence{"quit": {Keys('q')}},
+			name:     "exit",
+			handler:  noop,
+			defaults: []Sequence{Keys('q')},
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on a sequence that is a prefix of another action",
+			existing: map[string][]Sequence{"top": {Keys('g', 'g')}},
+			name:     "go",
+			handler:  noop,
+			defaults: []Sequence{Keys('g')},
+			wantErr:  true,
+		},
+		{
+			desc:     "registers an action",
+			existing: map[string][]Sequence{"top": {Keys('g', 'g')}},
+			name:     "bottom",
+			handler:  noop,
+			defaults: []Sequence{Keys('G'), Keys('g', 'e')},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := New()
+			for name, seqs := range tc.existing {
+				if err := r.Register(name, "", noop, seqs...); err != nil {
+					t.Fatalf("Register(%q) => unexpected error: %v", name, err)
+				}
+			}
+
+			err := r.Register(tc.name, "description", tc.handler, tc.defaults...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Register => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := r.Bindings(tc.name)
+			if err != nil {
+				t.Fatalf("Bindings => unexpected error: %v", err)
+			}
+			if diff := pretty.Compare(tc.defaults, got); diff != "" {
+				t.Errorf("Bindings => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		desc         string
+		keys         []keyboard.Key
+		wantConsumed []bool
+		wantCalled   []string
+	}{
+		{
+			desc:         "ignores unbound keys",
+			keys:         []keyboard.Key{'x'},
+			wantConsumed: []bool{false},
+		},
+		{
+			desc:         "calls the action bound to a single key",
+			keys:         []keyboard.Key{'q'},
+			wantConsumed: []bool{true},
+			wantCalled:   []string{"quit"},
+		},
+		{
+			desc:         "calls the action bound to a sequence",
+			keys:         []keyboard.Key{'g', 'g'},
+			wantConsumed: []bool{true, true},
+			wantCalled:   []string{"top"},
+		},
+		{
+			desc:         "calls the action bound to a sequence of special keys",
+			keys:         []keyboard.Key{keyboard.KeyCtrlX, keyboard.KeyCtrlS},
+			wantConsumed: []bool{true, true},
+			wantCalled:   []string{"save"},
+		},
+		{
+			desc:         "a key that doesn't continue the sequence discards it",
+			keys:         []keyboard.Key{'g', 'x', 'g', 'g'},
+			wantConsumed: []bool{true, false, true, true},
+			wantCalled:   []string{"top"},
+		},
+		{
+			desc:         "a key that doesn't continue the sequence is matched on its own",
+			keys:         []keyboard.Key{'g', 'q'},
+			wantConsumed: []bool{true, true},
+			wantCalled:   []string{"quit"},
+		},
+		{
+			desc:         "calls actions repeatedly",
+			keys:         []keyboard.Key{'q', 'g', 'g', 'q'},
+			wantConsumed: []bool{true, true, true, true},
+			wantCalled:   []string{"quit", "top", "quit"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var called []string
+			r := New()
+			for _, a := range []struct {
+				name string
+				seq  Sequence
+			}{
+				{"quit", Keys('q')},
+				{"top", Keys('g', 'g')},
+				{"save", Keys(keyboard.KeyCtrlX, keyboard.KeyCtrlS)},
+			} {
+				name := a.name
+				if err := r.Register(name, "", func(*terminalapi.Keyboard) {
+					called = append(called, name)
+				}, a.seq); err != nil {
+					t.Fatalf("Register(%q) => unexpected error: %v", name, err)
+				}
+			}
+
+			var consumed []bool
+			for _, k := range tc.keys {
+				consumed = append(consumed, r.Handle(&terminalapi.Keyboard{Key: k}))
+			}
+			if diff := pretty.Compare(tc.wantConsumed, consumed); diff != "" {
+				t.Errorf("Handle => unexpected consumed diff (-want, +got):\n%s", diff)
+			}
+			if diff := pretty.Compare(tc.wantCalled, called); diff != "" {
+				t.Errorf("Handle => unexpected called actions diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBindAndReset(t *testing.T) {
+	var called int
+	r := New()
+	if err := r.Register("quit", "Quits the application.", func(*terminalapi.Keyboard) { called++ }, Keys('q')); err != nil {
+		t.Fatalf("Register => unexpected error: %v", err)
+	}
+	if err := r.Register("help", "Shows the help.", func(*terminalapi.Keyboard) {}, Keys('?')); err != nil {
+		t.Fatalf("Register => unexpected error: %v", err)
+	}
+
+	if err := r.Bind("unknown", Keys('x')); err == nil {
+		t.Errorf("Bind(unknown) => got nil error, want an error")
+	}
+	if err := r.Bind("quit", Keys('?')); err == nil {
+		t.Errorf("Bind(quit, ?) => got nil error, want a conflict")
+	}
+
+	if err := r.Bind("quit", Keys(keyboard.KeyCtrlQ)); err != nil {
+		t.Fatalf("Bind => unexpected error: %v", err)
+	}
+	if r.Handle(&terminalapi.Keyboard{Key: 'q'}) {
+		t.Errorf("Handle(q) => consumed the key that is no longer bound")
+	}
+	r.Handle(&terminalapi.Keyboard{Key: keyboard.KeyCtrlQ})
+	if called != 1 {
+		t.Errorf("Handle(KeyCtrlQ) => called the handler %d times, want 1", called)
+	}
+	if got, ok := r.Lookup(Keys(keyboard.KeyCtrlQ)); !ok || got != "quit" {
+		t.Errorf("Lookup(KeyCtrlQ) => %q, %v, want %q, true", got, ok, "quit")
+	}
+
+	if err := r.Reset("quit"); err != nil {
+		t.Fatalf("Reset => unexpected error: %v", err)
+	}
+	got, err := r.Bindings("quit")
+	if err != nil {
+		t.Fatalf("Bindings => unexpected error: %v", err)
+	}
+	if diff := pretty.Compare([]Sequence{Keys('q')}, got); diff != "" {
+		t.Errorf("Bindings => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if _, ok := r.Lookup(Keys(keyboard.KeyCtrlQ)); ok {
+		t.Errorf("Lookup(KeyCtrlQ) => found an action after Reset")
+	}
+
+	if diff := pretty.Compare([]string{"quit", "help"}, r.Actions()); diff != "" {
+		t.Errorf("Actions => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if d, err := r.Description("help"); err != nil || d != "Shows the help." {
+		t.Errorf("Description => %q, %v, want %q, nil", d, err, "Shows the help.")
+	}
+}
+
+func TestCopiesSequences(t *testing.T) {
+	r := New()
+	defaults := []Sequence{Keys('q')}
+	if err := r.Register("quit", "Quits the application.", func(*terminalapi.Keyboard) {}, defaults...); err != nil {
+		t.Fatalf("Register => unexpected error: %v", err)
+	}
+	if err := r.Register("help", "Shows the help.", func(*terminalapi.Keyboard) {}, Keys('?')); err != nil {
+		t.Fatalf("Register => unexpected error: %v", err)
+	}
+	// Changing the sequence after registration would conflict with help.
+	defaults[0][0] = '?'
+
+	if name, ok := r.Lookup(Keys('q')); !ok || name != "quit" {
+		t.Errorf("Lookup(q) => %q, %v, want %q, true", name, ok, "quit")
+	}
+
+	seqs := []Sequence{Keys('x')}
+	if err := r.Bind("quit", seqs...); err != nil {
+		t.Fatalf("Bind => unexpected error: %v", err)
+	}
+	seqs[0][0] = '?'
+	got, err := r.Bindings("quit")
+	if err != nil {
+		t.Fatalf("Bindings => unexpected error: %v", err)
+	}
+	if diff := pretty.Compare([]Sequence{Keys('x')}, got); diff != "" {
+		t.Errorf("Bindings after changing the bound sequence => unexpected diff (-want, +got):\n%s", diff)
+	}
+
+	got[0][0] = '?'
+	got, err = r.Bindings("quit")
+	if err != nil {
+		t.Fatalf("Bindings => unexpected error: %v", err)
+	}
+	if diff := pretty.Compare([]Sequence{Keys('x')}, got); diff != "" {
+		t.Errorf("Bindings after changing the returned sequence => unexpected diff (-want, +got):\n%s", diff)
+	}
+
+	if err := r.Reset("quit"); err != nil {
+		t.Fatalf("Reset => unexpected error: %v", err)
+	}
+	if name, ok := r.Lookup(Keys('q')); !ok || name != "quit" {
+		t.Errorf("Lookup(q) after Reset => %q, %v, want %q, true", name, ok, "quit")
+	}
+}
@@ -89,6 +89,7 @@ var buttonNames = map[Key]string{
 	KeyCtrl7:      "KeyCtrl7",
 	KeySpace:      "KeySpace",
 	KeyBackspace2: "KeyBackspace2",
+	KeyBacktab:    "KeyBacktab",
 }
 
 // Printable characters, but worth having constants for them.
@@ -153,6 +154,9 @@ const (
 	KeyCtrl6
 	KeyCtrl7
 	KeyBackspace2
+	// KeyBacktab is the Tab key pressed together with Shift. Only reported by
+	// terminals that support it, e.g. the tcell backend.
+	KeyBacktab
 )
 
 // Keys declared as duplicates by termbox.
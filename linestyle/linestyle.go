@@ -49,3 +49,45 @@ const (
 	// Round is line style using the rounded corners '╭' characters.
 	Round
 )
+
+// BorderChars is a custom set of characters used to draw a border instead of
+// one of the line styles. All the characters must be half-width runes that
+// occupy exactly one cell.
+type BorderChars struct {
+	// TopLeft, TopRight, BottomLeft and BottomRight are the corners.
+	TopLeft     rune
+	TopRight    rune
+	BottomLeft  rune
+	BottomRight rune
+
+	// Top, Bottom, Left and Right are the edges between the corners.
+	Top    rune
+	Bottom rune
+	Left   rune
+	Right  rune
+}
+
+// ASCIIBorder is a border drawn only with ASCII characters, e.g. for legacy
+// terminals that cannot display the box-drawing characters.
+var ASCIIBorder = BorderChars{
+	TopLeft:     '+',
+	TopRight:    '+',
+	BottomLeft:  '+',
+	BottomRight: '+',
+	Top:         '-',
+	Bottom:      '-',
+	Left:        '|',
+	Right:       '|',
+}
+
+// HeavyBorder is a border drawn with the heavy '━' characters.
+var HeavyBorder = BorderChars{
+	TopLeft:     '┏',
+	TopRight:    '┓',
+	BottomLeft:  '┗',
+	BottomRight: '┛',
+	Top:         '━',
+	Bottom:      '━',
+	Left:        '┃',
+	Right:       '┃',
+}
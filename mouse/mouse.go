@@ -28,12 +28,14 @@ func (b Button) String() string {
 
 // buttonNames maps Button values to human readable names.
 var buttonNames = map[Button]string{
-	ButtonLeft:      "ButtonLeft",
-	ButtonRight:     "ButtonRight",
-	ButtonMiddle:    "ButtonMiddle",
-	ButtonRelease:   "ButtonRelease",
-	ButtonWheelUp:   "ButtonWheelUp",
-	ButtonWheelDown: "ButtonWheelDown",
+	ButtonLeft:       "ButtonLeft",
+	ButtonRight:      "ButtonRight",
+	ButtonMiddle:     "ButtonMiddle",
+	ButtonRelease:    "ButtonRelease",
+	ButtonWheelUp:    "ButtonWheelUp",
+	ButtonWheelDown:  "ButtonWheelDown",
+	ButtonWheelLeft:  "ButtonWheelLeft",
+	ButtonWheelRight: "ButtonWheelRight",
 }
 
 // Buttons recognized on the mouse.
@@ -45,4 +47,9 @@ const (
 	ButtonRelease
 	ButtonWheelUp
 	ButtonWheelDown
+	// ButtonWheelLeft and ButtonWheelRight are reported for horizontal
+	// scrolling (e.g. tilting the wheel or swiping on a touchpad) by
+	// terminals that support it, e.g. the tcell backend.
+	ButtonWheelLeft
+	ButtonWheelRight
 )
@@ -0,0 +1,105 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package areaflush helps terminal implementations flush only a part of their
+// back buffer.
+//
+// The terminal libraries only support flushing of the entire back buffer. To
+// flush an area, the cells outside of the area that were modified since the
+// last flush are temporarily reverted to their original content, so the flush
+// doesn't change them on the screen. Their new content is restored afterwards
+// and gets displayed by the next flush.
+package areaflush
+
+import (
+	"image"
+)
+
+// Content is the content of a cell as stored by the terminal implementation.
+type Content interface{}
+
+// GetFn returns the content of the cell at the point in the back buffer.
+type GetFn func(p image.Point) Content
+
+// SetFn sets the content of the cell at the point in the back buffer.
+type SetFn func(p image.Point, c Content)
+
+// Tracker tracks cells that were modified since they were last flushed.
+// The zero value isn't valid, use New to create instances.
+// This object is not thread-safe.
+type Tracker struct {
+	// before is the content the modified cells had when they were last
+	// flushed.
+	before map[image.Point]Content
+}
+
+// New returns a new Tracker.
+func New() *Tracker {
+	return &Tracker{
+		before: map[image.Point]Content{},
+	}
+}
+
+// Modify must be called before the cell at the point gets modified. The get
+// function is used to record the content of the cell, if this is the first
+// modification since it was last flushed.
+func (t *Tracker) Modify(p image.Point, get GetFn) {
+	if _, ok := t.before[p]; ok {
+		return
+	}
+	t.before[p] = get(p)
+}
+
+// ModifyArea is like Modify, but for all the cells in the area.
+func (t *Tracker) ModifyArea(ar image.Rectangle, get GetFn) {
+	for y := ar.Min.Y; y < ar.Max.Y; y++ {
+		for x := ar.Min.X; x < ar.Max.X; x++ {
+			t.Modify(image.Point{x, y}, get)
+		}
+	}
+}
+
+// Flushed must be called after the entire back buffer was flushed.
+func (t *Tracker) Flushed() {
+	t.before = map[image.Point]Content{}
+}
+
+// FlushArea flushes the back buffer using the provided flush function, making
+// sure that only the modified cells within the area change on the screen.
+// The get and set functions are used to access the back buffer.
+func (t *Tracker) FlushArea(ar image.Rectangle, get GetFn, set SetFn, flush func() error) error {
+	pending := map[image.Point]Content{}
+	for p, c := range t.before {
+		if p.In(ar) {
+			continue
+		}
+		pending[p] = get(p)
+		set(p, c)
+	}
+
+	err := flush()
+	for p, c := range pending {
+		set(p, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	for p := range t.before {
+		if p.In(ar) {
+			delete(t.before, p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package areaflush
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// fakeBuffers simulates the back and the front buffer of a terminal.
+type fakeBuffers struct {
+	back  map[image.Point]Content
+	front map[image.Point]Content
+}
+
+func (fb *fakeBuffers) get(p image.Point) Content {
+	return fb.back[p]
+}
+
+func (fb *fakeBuffers) set(p image.Point, c Content) {
+	fb.back[p] = c
+}
+
+func (fb *fakeBuffers) flush() error {
+	fb.front = map[image.Point]Content{}
+	for p, c := range fb.back {
+		fb.front[p] = c
+	}
+	return nil
+}
+
+func TestFlushArea(t *testing.T) {
+	tests := []struct {
+		desc string
+		// modify are the cells modified before the flushes.
+		modify map[image.Point]Content
+		// modifyArea is modified to the content "area" before the flushes.
+		modifyArea image.Rectangle
+		flushes    []image.Rectangle
+		flushErr   error
+		wantFront  map[image.Point]Content
+		wantBack   map[image.Point]Content
+		wantErr    bool
+	}{
+		{
+			desc: "flushes only cells within the area",
+			modify: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "out",
+			},
+			flushes: []image.Rectangle{image.Rect(0, 0, 2, 2)},
+			wantFront: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "orig",
+			},
+			wantBack: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "out",
+			},
+		},
+		{
+			desc: "cells outside stay pending until a later flush covers them",
+			modify: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "out",
+			},
+			flushes: []image.Rectangle{
+				image.Rect(0, 0, 2, 2),
+				image.Rect(4, 4, 6, 6),
+			},
+			wantFront: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "out",
+			},
+			wantBack: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "out",
+			},
+		},
+		{
+			desc: "flushed cells aren't reverted by later flushes",
+			modify: map[image.Point]Content{
+				{0, 0}: "in",
+			},
+			flushes: []image.Rectangle{
+				image.Rect(0, 0, 2, 2),
+				image.Rect(4, 4, 6, 6),
+			},
+			wantFront: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "orig",
+			},
+			wantBack: map[image.Point]Content{
+				{0, 0}: "in",
+				{5, 5}: "orig",
+			},
+		},
+		{
+			desc:       "tracks modified areas",
+			modifyArea: image.Rect(5, 5, 6, 6),
+			flushes:    []image.Rectangle{image.Rect(0, 0, 2, 2)},
+			wantFront: map[image.Point]Content{
+				{0, 0}: "orig",
+				{5, 5}: "orig",
+			},
+			wantBack: map[image.Point]Content{
+				{0, 0}: "orig",
+				{5, 5}: "area",
+			},
+		},
+		{
+			desc: "restores the back buffer when the flush fails",
+			modify: map[image.Point]Content{
+				{5, 5}: "out",
+			},
+			flushes:  []image.Rectangle{image.Rect(0, 0, 2, 2)},
+			flushErr: errors.New("flush failed"),
+			wantFront: map[image.Point]Content{
+				{0, 0}: "orig",
+				{5, 5}: "orig",
+			},
+			wantBack: map[image.Point]Content{
+				{0, 0}: "orig",
+				{5, 5}: "out",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			fb := &fakeBuffers{
+				back: map[image.Point]Content{
+					{0, 0}: "orig",
+					{5, 5}: "orig",
+				},
+			}
+			if err := fb.flush(); err != nil {
+				t.Fatalf("flush => unexpected error: %v", err)
+			}
+
+			tr := New()
+			for p, c := range tc.modify {
+				tr.Modify(p, fb.get)
+				fb.set(p, c)
+			}
+			if !tc.modifyArea.Empty() {
+				tr.ModifyArea(tc.modifyArea, fb.get)
+				for p := range fb.back {
+					if p.In(tc.modifyArea) {
+						fb.set(p, "area")
+					}
+				}
+			}
+
+			flush := fb.flush
+			if tc.flushErr != nil {
+				flush = func() error { return tc.flushErr }
+			}
+			var err error
+			for _, ar := range tc.flushes {
+				if err = tr.FlushArea(ar, fb.get, fb.set, flush); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tc.wantErr {
+				t.Errorf("FlushArea => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+
+			if diff := pretty.Compare(tc.wantFront, fb.front); diff != "" {
+				t.Errorf("FlushArea => unexpected front buffer, diff (-want, +got):\n%s", diff)
+			}
+			if diff := pretty.Compare(tc.wantBack, fb.back); diff != "" {
+				t.Errorf("FlushArea => unexpected back buffer, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFlushed(t *testing.T) {
+	fb := &fakeBuffers{
+		back: map[image.Point]Content{
+			{5, 5}: "orig",
+		},
+	}
+	tr := New()
+	tr.Modify(image.Point{5, 5}, fb.get)
+	fb.set(image.Point{5, 5}, "new")
+	tr.Flushed()
+
+	if err := tr.FlushArea(image.Rect(0, 0, 1, 1), fb.get, fb.set, fb.flush); err != nil {
+		t.Fatalf("FlushArea => unexpected error: %v", err)
+	}
+	want := map[image.Point]Content{
+		{5, 5}: "new",
+	}
+	if diff := pretty.Compare(want, fb.front); diff != "" {
+		t.Errorf("FlushArea => unexpected front buffer, diff (-want, +got):\n%s", diff)
+	}
+}
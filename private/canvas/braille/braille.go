@@ -273,6 +273,35 @@ func isBraille(r rune) bool {
 	return r >= brailleCharOffset && r <= brailleLastChar
 }
 
+// ToHalfBlock converts a braille pattern rune into a half block character that
+// approximates it with the resolution of 1x2 pixels per cell. The upper half
+// is set if any pixel in the top two rows of the pattern is set, the lower half
+// if any pixel in the bottom two rows is set. A braille pattern without any
+// pixels set is converted to a space. Runes that aren't braille patterns are
+// returned unchanged.
+func ToHalfBlock(r rune) rune {
+	if !isBraille(r) {
+		return r
+	}
+
+	const (
+		upperMask = 0x01 | 0x08 | 0x02 | 0x10
+		lowerMask = 0x04 | 0x20 | 0x40 | 0x80
+	)
+	upper := r&upperMask > 0
+	lower := r&lowerMask > 0
+	switch {
+	case upper && lower:
+		return '█'
+	case upper:
+		return '▀'
+	case lower:
+		return '▄'
+	default:
+		return ' '
+	}
+}
+
 // pixelSet returns true if the provided rune has the specified pixel set.
 func pixelSet(r rune, p image.Point) bool {
 	return r&pixelRunes[pixelPoint(p)] > 0
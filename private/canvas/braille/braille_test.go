@@ -990,3 +990,60 @@ func TestBraille(t *testing.T) {
 		})
 	}
 }
+
+func TestToHalfBlock(t *testing.T) {
+	tests := []struct {
+		desc string
+		r    rune
+		want rune
+	}{
+		{
+			desc: "non-braille rune is unchanged",
+			r:    'a',
+			want: 'a',
+		},
+		{
+			desc: "empty braille pattern",
+			r:    '⠀',
+			want: ' ',
+		},
+		{
+			desc: "pixel in the top row",
+			r:    '⠈',
+			want: '▀',
+		},
+		{
+			desc: "pixel in the second row",
+			r:    '⠂',
+			want: '▀',
+		},
+		{
+			desc: "pixel in the third row",
+			r:    '⠠',
+			want: '▄',
+		},
+		{
+			desc: "pixel in the bottom row",
+			r:    '⡀',
+			want: '▄',
+		},
+		{
+			desc: "pixels in both halves",
+			r:    '⢁',
+			want: '█',
+		},
+		{
+			desc: "all pixels",
+			r:    '⣿',
+			want: '█',
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := ToHalfBlock(tc.r); got != tc.want {
+				t.Errorf("ToHalfBlock(%q) => %q, want %q", tc.r, got, tc.want)
+			}
+		})
+	}
+}
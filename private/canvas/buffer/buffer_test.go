@@ -203,6 +203,20 @@ func TestCellApply(t *testing.T) {
 				cell.BgColor(cell.ColorBlack),
 			),
 		},
+		{
+			desc: "the last of conflicting options takes precedence",
+			cell: NewCell(0, cell.FgColor(cell.ColorCyan)),
+			opts: []cell.Option{
+				cell.FgColor(cell.ColorRed),
+				cell.BgColor(cell.ColorBlack),
+				cell.FgColor(cell.ColorGreen),
+			},
+			want: NewCell(
+				0,
+				cell.FgColor(cell.ColorGreen),
+				cell.BgColor(cell.ColorBlack),
+			),
+		},
 	}
 
 	for _, tc := range tests {
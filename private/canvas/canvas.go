@@ -22,6 +22,7 @@ import (
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/damage"
 	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 )
@@ -35,6 +36,17 @@ type Canvas struct {
 
 	// buffer is where the drawing happens.
 	buffer buffer.Buffer
+
+	// passthrough are the escape sequences set via SetPassthrough.
+	passthrough []*passthrough
+}
+
+// passthrough is an escape sequence written directly to the terminal.
+type passthrough struct {
+	// p is the cell the sequence is written at.
+	p image.Point
+	// seq is the escape sequence.
+	seq []byte
 }
 
 // New returns a new Canvas with a buffer for the provided area.
@@ -71,6 +83,7 @@ func (c *Canvas) Clear() error {
 		return err
 	}
 	c.buffer = b
+	c.passthrough = nil
 	return nil
 }
 
@@ -162,6 +175,31 @@ func (c *Canvas) SetAreaCellOpts(cellArea image.Rectangle, opts ...cell.Option)
 	return nil
 }
 
+// SetPassthrough sets an escape sequence that is written directly to the
+// terminal with the cursor placed at the specified cell when the canvas is
+// applied, e.g. to display an image using one of the graphics protocols.
+// The sequence is ignored by terminals that don't implement
+// terminalapi.Passthrough. Setting another sequence at the same cell
+// replaces the previous one.
+func (c *Canvas) SetPassthrough(p image.Point, seq []byte) error {
+	if ar := c.Area(); !p.In(ar) {
+		return fmt.Errorf("unable to set passthrough at point %v, it must fall within the canvas area %v", p, ar)
+	}
+	c.setPassthrough(p, seq)
+	return nil
+}
+
+// setPassthrough sets the sequence at the point without validating it.
+func (c *Canvas) setPassthrough(p image.Point, seq []byte) {
+	for _, pt := range c.passthrough {
+		if pt.p == p {
+			pt.seq = seq
+			return
+		}
+	}
+	c.passthrough = append(c.passthrough, &passthrough{p: p, seq: seq})
+}
+
 // setCellFunc is a function that sets cell content on a terminal or a canvas.
 type setCellFunc func(image.Point, rune, ...cell.Option) error
 
@@ -196,6 +234,49 @@ func (c *Canvas) copyTo(offset image.Point, dstSetCell setCellFunc) error {
 
 // Apply applies the canvas to the corresponding area of the terminal.
 func (c *Canvas) Apply(t terminalapi.Terminal) error {
+	return c.apply(t, t.SetCell)
+}
+
+// ApplyTracked is like Apply, but only sets the cells of the terminal whose
+// content differs from the content recorded by the tracker.
+func (c *Canvas) ApplyTracked(t terminalapi.Terminal, tr *damage.Tracker) error {
+	return c.apply(t, func(p image.Point, r rune, opts ...cell.Option) error {
+		return tr.SetCell(p, r, opts...)
+	})
+}
+
+// ApplyTrackedView is like ApplyTracked, but only applies the cells whose
+// points fall inside the view and moves them by the shift, i.e. the cell at
+// point p is set at point p.Sub(shift) on the terminal.
+// This allows to display a part of content that is larger than the terminal.
+func (c *Canvas) ApplyTrackedView(t terminalapi.Terminal, tr *damage.Tracker, shift image.Point, view image.Rectangle) error {
+	offset := c.area.Min
+	if err := c.copyTo(offset, func(p image.Point, r rune, opts ...cell.Option) error {
+		if !p.In(view) {
+			return nil
+		}
+		return tr.SetCell(p.Sub(shift), r, opts...)
+	}); err != nil {
+		return err
+	}
+
+	if ptt, ok := t.(terminalapi.Passthrough); ok {
+		for _, pt := range c.passthrough {
+			p := pt.p.Add(offset)
+			if !p.In(view) {
+				continue
+			}
+			if err := ptt.Passthrough(p.Sub(shift), pt.seq); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply applies the canvas to the terminal using the provided function to
+// set the cells.
+func (c *Canvas) apply(t terminalapi.Terminal, setCell setCellFunc) error {
 	// Note - the size of the terminal might have changed since we started
 	// drawing, since terminal windows are inherently racy (the user can resize
 	// them at any time).
@@ -208,7 +289,18 @@ func (c *Canvas) Apply(t terminalapi.Terminal) error {
 	// image.Point{0, 0} on the terminal.
 	// Depends on area assigned by the container.
 	offset := c.area.Min
-	return c.copyTo(offset, t.SetCell)
+	if err := c.copyTo(offset, setCell); err != nil {
+		return err
+	}
+
+	if ptt, ok := t.(terminalapi.Passthrough); ok {
+		for _, pt := range c.passthrough {
+			if err := ptt.Passthrough(pt.p.Add(offset), pt.seq); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // CopyTo copies the content of this canvas onto the destination canvas.
@@ -236,5 +328,11 @@ func (c *Canvas) CopyTo(dst *Canvas) error {
 	// canvas. Copying this sub-canvas back onto the parent accounts for this
 	// offset.
 	offset := c.area.Min
-	return c.copyTo(offset, fn)
+	if err := c.copyTo(offset, fn); err != nil {
+		return err
+	}
+	for _, pt := range c.passthrough {
+		dst.setPassthrough(pt.p.Add(offset), pt.seq)
+	}
+	return nil
 }
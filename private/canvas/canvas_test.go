@@ -22,7 +22,9 @@ import (
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/damage"
 	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
 )
 
 func TestNew(t *testing.T) {
@@ -903,6 +905,85 @@ func TestApplyFullWidthRunes(t *testing.T) {
 	}
 }
 
+func TestApplyTracked(t *testing.T) {
+	ar := image.Rect(1, 1, 3, 2)
+	c, err := New(ar)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if _, err := c.SetCell(image.Point{0, 0}, 'A', cell.FgColor(cell.ColorRed)); err != nil {
+		t.Fatalf("SetCell => unexpected error: %v", err)
+	}
+
+	ft, err := faketerm.New(image.Point{3, 3})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	tr := damage.New()
+	tr.Resize(ft.Size())
+	if err := c.ApplyTracked(ft, tr); err != nil {
+		t.Fatalf("ApplyTracked => unexpected error: %v", err)
+	}
+	if got := ft.BackBuffer()[1][1].Rune; got != 0 {
+		t.Errorf("ApplyTracked => set the cell before the tracker was flushed, got rune %q", got)
+	}
+	if err := tr.Flush(ft); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+
+	want, err := buffer.New(image.Point{3, 3})
+	if err != nil {
+		t.Fatalf("buffer.New => unexpected error: %v", err)
+	}
+	want[1][1].Rune = 'A'
+	want[1][1].Opts = cell.NewOptions(cell.FgColor(cell.ColorRed))
+
+	got := ft.BackBuffer()
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("faketerm.BackBuffer => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestApplyTrackedView(t *testing.T) {
+	ar := image.Rect(2, 2, 5, 3)
+	c, err := New(ar)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	for i, r := range "ABC" {
+		if _, err := c.SetCell(image.Point{i, 0}, r); err != nil {
+			t.Fatalf("SetCell => unexpected error: %v", err)
+		}
+	}
+
+	ft, err := faketerm.New(image.Point{4, 4})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	tr := damage.New()
+	tr.Resize(ft.Size())
+	// Only 'A' and 'B' fall into the view, they are moved by one cell to the
+	// left and to the top.
+	if err := c.ApplyTrackedView(ft, tr, image.Point{1, 1}, image.Rect(0, 0, 4, 4)); err != nil {
+		t.Fatalf("ApplyTrackedView => unexpected error: %v", err)
+	}
+	if err := tr.Flush(ft); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+
+	want, err := buffer.New(image.Point{4, 4})
+	if err != nil {
+		t.Fatalf("buffer.New => unexpected error: %v", err)
+	}
+	want[1][1].Rune = 'A'
+	want[2][1].Rune = 'B'
+
+	got := ft.BackBuffer()
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("faketerm.BackBuffer => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
 func TestCell(t *testing.T) {
 	tests := []struct {
 		desc    string
@@ -1154,3 +1235,123 @@ func TestCopyTo(t *testing.T) {
 		})
 	}
 }
+
+// passthroughTerm is a fake terminal that records the passthrough sequences.
+type passthroughTerm struct {
+	*faketerm.Terminal
+
+	// got are the recorded sequences mapped by the cells they were set at.
+	got map[image.Point]string
+}
+
+// Graphics implements terminalapi.Passthrough.Graphics.
+func (pt *passthroughTerm) Graphics() terminalapi.GraphicsProtocol {
+	return terminalapi.GraphicsKitty
+}
+
+// Passthrough implements terminalapi.Passthrough.Passthrough.
+func (pt *passthroughTerm) Passthrough(p image.Point, seq []byte) error {
+	pt.got[p] = string(seq)
+	return nil
+}
+
+func TestPassthrough(t *testing.T) {
+	tests := []struct {
+		desc    string
+		canvas  image.Rectangle
+		set     map[image.Point]string
+		wantErr bool
+		// copyTo when set, the canvas is copied onto a canvas of this area
+		// before it is applied.
+		copyTo image.Rectangle
+		clear  bool
+		want   map[image.Point]string
+	}{
+		{
+			desc:    "fails when the point falls outside of the canvas",
+			canvas:  image.Rect(0, 0, 2, 2),
+			set:     map[image.Point]string{{2, 0}: "seq"},
+			wantErr: true,
+		},
+		{
+			desc:   "applies sequences at offset of the canvas",
+			canvas: image.Rect(1, 1, 3, 3),
+			set: map[image.Point]string{
+				{0, 0}: "first",
+				{1, 1}: "second",
+			},
+			want: map[image.Point]string{
+				{1, 1}: "first",
+				{2, 2}: "second",
+			},
+		},
+		{
+			desc:   "copies sequences to another canvas",
+			canvas: image.Rect(1, 1, 3, 3),
+			set: map[image.Point]string{
+				{0, 0}: "first",
+			},
+			copyTo: image.Rect(0, 0, 4, 4),
+			want: map[image.Point]string{
+				{1, 1}: "first",
+			},
+		},
+		{
+			desc:   "clear removes the sequences",
+			canvas: image.Rect(0, 0, 2, 2),
+			set: map[image.Point]string{
+				{0, 0}: "first",
+			},
+			clear: true,
+			want:  map[image.Point]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := New(tc.canvas)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			for p, seq := range tc.set {
+				err := c.SetPassthrough(p, []byte(seq))
+				if (err != nil) != tc.wantErr {
+					t.Errorf("SetPassthrough => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+			if tc.clear {
+				if err := c.Clear(); err != nil {
+					t.Fatalf("Clear => unexpected error: %v", err)
+				}
+			}
+			if !tc.copyTo.Empty() {
+				dst, err := New(tc.copyTo)
+				if err != nil {
+					t.Fatalf("New => unexpected error: %v", err)
+				}
+				if err := c.CopyTo(dst); err != nil {
+					t.Fatalf("CopyTo => unexpected error: %v", err)
+				}
+				c = dst
+			}
+
+			ft, err := faketerm.New(image.Point{4, 4})
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			term := &passthroughTerm{
+				Terminal: ft,
+				got:      map[image.Point]string{},
+			}
+			if err := c.Apply(term); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := pretty.Compare(tc.want, term.got); diff != "" {
+				t.Errorf("Apply => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
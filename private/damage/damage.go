@@ -0,0 +1,138 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package damage composes frames drawn on the terminal, so that a redraw only
+// sets the cells whose content changed since the previous frame.
+//
+// Cells are first set on the back buffer of the tracker, cells that are drawn
+// over several times while composing a frame (e.g. a border and the widget
+// inside it) only retain their final content. Flush then sets the cells that
+// differ from the front buffer, i.e. from the content last set on the
+// terminal. Only the region containing cells set to a different content is
+// compared. The front buffer is only valid as long as nothing else modifies
+// the terminal, the tracker must be invalidated whenever the terminal gets
+// cleared.
+package damage
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// content is the content of a cell.
+type content struct {
+	// known indicates if the content is known. It isn't for cells that
+	// weren't set since the tracker got invalidated.
+	known bool
+	r     rune
+	opts  cell.Options
+}
+
+// tracked is a cell tracked by the tracker.
+type tracked struct {
+	// back is the content of the frame being composed.
+	back content
+	// front is the content last set on the terminal.
+	front content
+}
+
+// Tracker tracks the content of the cells of the terminal.
+// The zero value isn't valid, use New to create instances.
+// This object is not thread-safe.
+type Tracker struct {
+	// cells are the cells of the terminal indexed as cells[col][row].
+	cells [][]tracked
+	// damaged is the smallest area containing all the cells set to a content
+	// that differs from the front buffer since the last Flush.
+	damaged image.Rectangle
+}
+
+// New returns a new Tracker.
+func New() *Tracker {
+	return &Tracker{}
+}
+
+// Resize adjusts the tracker to the size of the terminal. Invalidates the
+// tracker if the size changed.
+func (t *Tracker) Resize(size image.Point) {
+	if len(t.cells) == size.X && (size.X == 0 || len(t.cells[0]) == size.Y) {
+		return
+	}
+	t.cells = make([][]tracked, size.X)
+	for col := range t.cells {
+		t.cells[col] = make([]tracked, size.Y)
+	}
+	t.damaged = image.ZR
+}
+
+// Invalidate forgets the content of all the cells. Must be called after the
+// terminal was cleared or modified without the tracker. The next Flush only
+// sets the cells that were set since the invalidation.
+func (t *Tracker) Invalidate() {
+	for col := range t.cells {
+		for row := range t.cells[col] {
+			t.cells[col][row] = tracked{}
+		}
+	}
+}
+
+// SetCell sets the cell in the frame being composed. The provided options
+// must fully describe the cell, as is the case for cells copied from a canvas.
+// Returns an error if the point falls outside of the tracked area.
+func (t *Tracker) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	if p.X < 0 || p.X >= len(t.cells) || p.Y < 0 || p.Y >= len(t.cells[p.X]) {
+		return fmt.Errorf("point %v falls outside of the tracked area %v", p, t.area())
+	}
+	c := &t.cells[p.X][p.Y]
+	c.back = content{
+		known: true,
+		r:     r,
+		opts:  *cell.NewOptions(opts...),
+	}
+	if c.back != c.front {
+		t.damaged = t.damaged.Union(image.Rect(p.X, p.Y, p.X+1, p.Y+1))
+	}
+	return nil
+}
+
+// area returns the tracked area.
+func (t *Tracker) area() image.Rectangle {
+	if len(t.cells) == 0 {
+		return image.ZR
+	}
+	return image.Rect(0, 0, len(t.cells), len(t.cells[0]))
+}
+
+// Flush sets the cells of the composed frame whose content differs from the
+// content last set on the terminal.
+func (t *Tracker) Flush(term terminalapi.Terminal) error {
+	for col := t.damaged.Min.X; col < t.damaged.Max.X; col++ {
+		for row := t.damaged.Min.Y; row < t.damaged.Max.Y; row++ {
+			c := &t.cells[col][row]
+			if !c.back.known || c.back == c.front {
+				continue
+			}
+			opts := c.back.opts
+			if err := term.SetCell(image.Point{col, row}, c.back.r, &opts); err != nil {
+				return err
+			}
+			c.front = c.back
+		}
+	}
+	t.damaged = image.ZR
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package damage
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/faketerm"
+)
+
+// countingTerm counts the cells set on the fake terminal.
+type countingTerm struct {
+	*faketerm.Terminal
+	set int
+}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+func (ct *countingTerm) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	ct.set++
+	return ct.Terminal.SetCell(p, r, opts...)
+}
+
+func TestTracker(t *testing.T) {
+	term := &countingTerm{Terminal: faketerm.MustNew(image.Point{3, 2})}
+	tr := New()
+	tr.Resize(term.Size())
+
+	red := cell.FgColor(cell.ColorRed)
+	steps := []struct {
+		desc    string
+		draw    func() error
+		wantSet int
+	}{
+		{
+			desc: "sets the cells of the first frame",
+			draw: func() error {
+				if err := tr.SetCell(image.Point{0, 0}, 'a', red); err != nil {
+					return err
+				}
+				return tr.SetCell(image.Point{1, 0}, 'b', red)
+			},
+			wantSet: 2,
+		},
+		{
+			desc: "skips cells with the same content",
+			draw: func() error {
+				if err := tr.SetCell(image.Point{0, 0}, 'a', red); err != nil {
+					return err
+				}
+				return tr.SetCell(image.Point{1, 0}, 'b', red)
+			},
+		},
+		{
+			desc: "skips cells that end up with the same content",
+			draw: func() error {
+				if err := tr.SetCell(image.Point{0, 0}, ' '); err != nil {
+					return err
+				}
+				return tr.SetCell(image.Point{0, 0}, 'a', red)
+			},
+		},
+		{
+			desc: "sets cells with a different rune",
+			draw: func() error {
+				return tr.SetCell(image.Point{0, 0}, 'c', red)
+			},
+			wantSet: 1,
+		},
+		{
+			desc: "sets cells with different options",
+			draw: func() error {
+				return tr.SetCell(image.Point{0, 0}, 'c', cell.FgColor(cell.ColorBlue))
+			},
+			wantSet: 1,
+		},
+		{
+			desc: "after invalidation sets only the cells drawn since",
+			draw: func() error {
+				tr.Invalidate()
+				return tr.SetCell(image.Point{0, 0}, 'c', cell.FgColor(cell.ColorBlue))
+			},
+			wantSet: 1,
+		},
+		{
+			desc: "keeps the content when the size doesn't change",
+			draw: func() error {
+				tr.Resize(image.Point{3, 2})
+				return tr.SetCell(image.Point{0, 0}, 'c', cell.FgColor(cell.ColorBlue))
+			},
+		},
+		{
+			desc: "sets the cells again after a resize",
+			draw: func() error {
+				tr.Resize(image.Point{4, 2})
+				return tr.SetCell(image.Point{0, 0}, 'c', cell.FgColor(cell.ColorBlue))
+			},
+			wantSet: 1,
+		},
+		{
+			desc: "rejects cells outside of the tracked area",
+			draw: func() error {
+				if err := tr.SetCell(image.Point{5, 5}, 'x'); err == nil {
+					t.Errorf("SetCell => expected an error")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, step := range steps {
+		term.set = 0
+		if err := step.draw(); err != nil {
+			t.Fatalf("%s: SetCell => unexpected error: %v", step.desc, err)
+		}
+		if err := tr.Flush(term); err != nil {
+			t.Fatalf("%s: Flush => unexpected error: %v", step.desc, err)
+		}
+		if term.set != step.wantSet {
+			t.Errorf("%s: set %d cells, want %d", step.desc, term.set, step.wantSet)
+		}
+	}
+
+	got := term.BackBuffer()[0][0]
+	if want := 'c'; got.Rune != want {
+		t.Errorf("BackBuffer[0][0].Rune => %q, want %q", got.Rune, want)
+	}
+	if want := cell.ColorBlue; got.Opts.FgColor != want {
+		t.Errorf("BackBuffer[0][0].Opts.FgColor => %v, want %v", got.Opts.FgColor, want)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dirty helps widgets implement widgetapi.DirtyReporter.
+package dirty
+
+import "sync"
+
+// Reporter reports changes of the content of a widget using the function
+// provided by the infrastructure.
+// The zero value is ready to use, Mark does nothing until a function is set.
+// This object is thread-safe.
+type Reporter struct {
+	mu        sync.Mutex
+	markDirty func()
+}
+
+// Set sets the function that marks the widget as dirty.
+// Widgets call this from their SetDirtyFunc method.
+func (r *Reporter) Set(markDirty func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.markDirty = markDirty
+}
+
+// Mark reports that the content of the widget changed.
+func (r *Reporter) Mark() {
+	r.mu.Lock()
+	f := r.markDirty
+	r.mu.Unlock()
+	if f != nil {
+		f()
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dirty
+
+import "testing"
+
+func TestReporter(t *testing.T) {
+	var r Reporter
+	// Does nothing without a function.
+	r.Mark()
+
+	var first, second int
+	r.Set(func() { first++ })
+	r.Mark()
+	r.Set(func() { second++ })
+	r.Mark()
+	r.Mark()
+
+	if got, want := first, 1; got != want {
+		t.Errorf("first function called %d times, want %d", got, want)
+	}
+	if got, want := second, 2; got != want {
+		t.Errorf("second function called %d times, want %d", got, want)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/mum4k/termdash/linestyle"
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/runewidth"
 )
 
 // BorderOption is used to provide options to Border().
@@ -37,11 +38,18 @@ type BorderOption interface {
 type borderOptions struct {
 	cellOpts      []cell.Option
 	lineStyle     linestyle.LineStyle
+	chars         *linestyle.BorderChars
 	title         string
 	richTitle     *cell.RichTextString
 	titleOM       OverrunMode
 	titleCellOpts []cell.Option
 	titleHAlign   align.Horizontal
+	titleReserve  int
+
+	bottomTitle         string
+	bottomTitleOM       OverrunMode
+	bottomTitleCellOpts []cell.Option
+	bottomTitleHAlign   align.Horizontal
 }
 
 // borderOption implements BorderOption.
@@ -62,6 +70,14 @@ func BorderLineStyle(ls linestyle.LineStyle) BorderOption {
 	})
 }
 
+// BorderChars sets custom characters used to draw the border. Takes
+// precedence over BorderLineStyle.
+func BorderChars(chars linestyle.BorderChars) BorderOption {
+	return borderOption(func(bOpts *borderOptions) {
+		bOpts.chars = &chars
+	})
+}
+
 // BorderCellOpts sets options on the cells that create the border.
 func BorderCellOpts(opts ...cell.Option) BorderOption {
 	return borderOption(func(bOpts *borderOptions) {
@@ -91,23 +107,84 @@ func BorderTitleAlign(h align.Horizontal) BorderOption {
 	})
 }
 
-// borderChar returns the correct border character from the parts for the use
-// at the specified point of the border. Returns -1 if no character should be at
-// this point.
-func borderChar(p image.Point, border image.Rectangle, parts map[linePart]rune) rune {
+// BorderBottomTitle sets a secondary title drawn on the bottom border.
+func BorderBottomTitle(title string, overrun OverrunMode, opts ...cell.Option) BorderOption {
+	return borderOption(func(bOpts *borderOptions) {
+		bOpts.bottomTitle = title
+		bOpts.bottomTitleOM = overrun
+		bOpts.bottomTitleCellOpts = opts
+	})
+}
+
+// BorderBottomTitleAlign configures the horizontal alignment for the title on
+// the bottom border.
+func BorderBottomTitleAlign(h align.Horizontal) BorderOption {
+	return borderOption(func(bOpts *borderOptions) {
+		bOpts.bottomTitleHAlign = h
+	})
+}
+
+// BorderTitleReserveRight reserves the specified number of cells at the right
+// end of the top border. The title is never drawn into the reserved cells,
+// which allows the caller to place other elements onto the border.
+func BorderTitleReserveRight(cells int) BorderOption {
+	return borderOption(func(bOpts *borderOptions) {
+		bOpts.titleReserve = cells
+	})
+}
+
+// borderChars returns the characters of the border, either the custom ones
+// or the ones of the line style.
+func borderChars(opt *borderOptions) (linestyle.BorderChars, error) {
+	if opt.chars != nil {
+		for _, r := range []rune{
+			opt.chars.TopLeft, opt.chars.TopRight, opt.chars.BottomLeft, opt.chars.BottomRight,
+			opt.chars.Top, opt.chars.Bottom, opt.chars.Left, opt.chars.Right,
+		} {
+			if rw := runewidth.RuneWidth(r); rw != 1 {
+				return linestyle.BorderChars{}, fmt.Errorf("invalid border character %q, it occupies %d cells, all border characters must be half-width runes that occupy exactly one cell", r, rw)
+			}
+		}
+		return *opt.chars, nil
+	}
+
+	parts, err := lineParts(opt.lineStyle)
+	if err != nil {
+		return linestyle.BorderChars{}, err
+	}
+	return linestyle.BorderChars{
+		TopLeft:     parts[topLeftCorner],
+		TopRight:    parts[topRightCorner],
+		BottomLeft:  parts[bottomLeftCorner],
+		BottomRight: parts[bottomRightCorner],
+		Top:         parts[hLine],
+		Bottom:      parts[hLine],
+		Left:        parts[vLine],
+		Right:       parts[vLine],
+	}, nil
+}
+
+// borderChar returns the correct border character for the use at the
+// specified point of the border. Returns -1 if no character should be at this
+// point.
+func borderChar(p image.Point, border image.Rectangle, chars linestyle.BorderChars) rune {
 	switch {
 	case p.X == border.Min.X && p.Y == border.Min.Y:
-		return parts[topLeftCorner]
+		return chars.TopLeft
 	case p.X == border.Max.X-1 && p.Y == border.Min.Y:
-		return parts[topRightCorner]
+		return chars.TopRight
 	case p.X == border.Min.X && p.Y == border.Max.Y-1:
-		return parts[bottomLeftCorner]
+		return chars.BottomLeft
 	case p.X == border.Max.X-1 && p.Y == border.Max.Y-1:
-		return parts[bottomRightCorner]
-	case p.X == border.Min.X || p.X == border.Max.X-1:
-		return parts[vLine]
-	case p.Y == border.Min.Y || p.Y == border.Max.Y-1:
-		return parts[hLine]
+		return chars.BottomRight
+	case p.X == border.Min.X:
+		return chars.Left
+	case p.X == border.Max.X-1:
+		return chars.Right
+	case p.Y == border.Min.Y:
+		return chars.Top
+	case p.Y == border.Max.Y-1:
+		return chars.Bottom
 	}
 	return -1
 }
@@ -118,14 +195,14 @@ func drawTitle(c *canvas.Canvas, border image.Rectangle, opt *borderOptions) err
 	// The title must not overwrite any of the corner runes on the border so we
 	// need the following minimum width.
 	const minForTitle = 3
-	if border.Dx() < minForTitle {
+	if border.Dx()-opt.titleReserve < minForTitle {
 		return nil
 	}
 
 	available := image.Rect(
 		border.Min.X+1, // One space for the top left corner char.
 		border.Min.Y,
-		border.Max.X-1, // One space for the top right corner char.
+		border.Max.X-1-opt.titleReserve, // One space for the top right corner char.
 		border.Min.Y+1,
 	)
 	start, err := alignfor.Text(available, opt.title, opt.titleHAlign, align.VerticalTop)
@@ -150,6 +227,32 @@ func drawTitle(c *canvas.Canvas, border image.Rectangle, opt *borderOptions) err
 	)
 }
 
+// drawBottomTitle draws a text title at the bottom of the border.
+func drawBottomTitle(c *canvas.Canvas, border image.Rectangle, opt *borderOptions) error {
+	// The title must not overwrite any of the corner runes on the border.
+	const minForTitle = 3
+	if border.Dx() < minForTitle {
+		return nil
+	}
+
+	available := image.Rect(
+		border.Min.X+1, // One space for the bottom left corner char.
+		border.Max.Y-1,
+		border.Max.X-1, // One space for the bottom right corner char.
+		border.Max.Y,
+	)
+	start, err := alignfor.Text(available, opt.bottomTitle, opt.bottomTitleHAlign, align.VerticalTop)
+	if err != nil {
+		return err
+	}
+	return Text(
+		c, opt.bottomTitle, start,
+		TextCellOpts(opt.bottomTitleCellOpts...),
+		TextOverrunMode(opt.bottomTitleOM),
+		TextMaxX(available.Max.X),
+	)
+}
+
 // Border draws a border on the canvas.
 func Border(c *canvas.Canvas, border image.Rectangle, opts ...BorderOption) error {
 	if ar := c.Area(); !border.In(ar) {
@@ -167,8 +270,11 @@ func Border(c *canvas.Canvas, border image.Rectangle, opts ...BorderOption) erro
 	for _, o := range opts {
 		o.set(opt)
 	}
+	if opt.titleReserve < 0 {
+		return fmt.Errorf("invalid BorderTitleReserveRight(%d), must be zero or a positive number", opt.titleReserve)
+	}
 
-	parts, err := lineParts(opt.lineStyle)
+	chars, err := borderChars(opt)
 	if err != nil {
 		return err
 	}
@@ -176,7 +282,7 @@ func Border(c *canvas.Canvas, border image.Rectangle, opts ...BorderOption) erro
 	for col := border.Min.X; col < border.Max.X; col++ {
 		for row := border.Min.Y; row < border.Max.Y; row++ {
 			p := image.Point{col, row}
-			r := borderChar(p, border, parts)
+			r := borderChar(p, border, chars)
 			if r == -1 {
 				continue
 			}
@@ -192,7 +298,12 @@ func Border(c *canvas.Canvas, border image.Rectangle, opts ...BorderOption) erro
 	}
 
 	if opt.title != "" || opt.richTitle != nil {
-		return drawTitle(c, border, opt)
+		if err := drawTitle(c, border, opt); err != nil {
+			return err
+		}
+	}
+	if opt.bottomTitle != "" {
+		return drawBottomTitle(c, border, opt)
 	}
 	return nil
 }
@@ -155,6 +155,61 @@ func TestBorder(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "draws border with custom characters",
+			canvas: image.Rect(0, 0, 3, 3),
+			border: image.Rect(0, 0, 3, 3),
+			opts: []BorderOption{
+				BorderLineStyle(linestyle.Double),
+				BorderChars(linestyle.BorderChars{
+					TopLeft:     '1',
+					TopRight:    '2',
+					BottomLeft:  '3',
+					BottomRight: '4',
+					Top:         't',
+					Bottom:      'b',
+					Left:        'l',
+					Right:       'r',
+				}),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '1')
+				testcanvas.MustSetCell(c, image.Point{1, 0}, 't')
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '2')
+				testcanvas.MustSetCell(c, image.Point{0, 1}, 'l')
+				testcanvas.MustSetCell(c, image.Point{2, 1}, 'r')
+				testcanvas.MustSetCell(c, image.Point{0, 2}, '3')
+				testcanvas.MustSetCell(c, image.Point{1, 2}, 'b')
+				testcanvas.MustSetCell(c, image.Point{2, 2}, '4')
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "fails on full-width custom border character",
+			canvas: image.Rect(0, 0, 3, 3),
+			border: image.Rect(0, 0, 3, 3),
+			opts: []BorderOption{
+				BorderChars(linestyle.BorderChars{
+					TopLeft:     '世',
+					TopRight:    '+',
+					BottomLeft:  '+',
+					BottomRight: '+',
+					Top:         '-',
+					Bottom:      '-',
+					Left:        '|',
+					Right:       '|',
+				}),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
 		{
 			desc:   "draws border in the canvas",
 			canvas: image.Rect(0, 0, 4, 4),
@@ -465,6 +520,96 @@ func TestBorder(t *testing.T) {
 				testcanvas.MustSetCell(c, image.Point{5, 2}, lineStyleChars[linestyle.Light][vLine])
 				testcanvas.MustSetCell(c, image.Point{5, 3}, lineStyleChars[linestyle.Light][bottomRightCorner])
 
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "fails on negative title reserve",
+			canvas: image.Rect(0, 0, 6, 4),
+			border: image.Rect(0, 0, 6, 4),
+			opts: []BorderOption{
+				BorderTitleReserveRight(-1),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "right aligned title respects the reserved cells",
+			canvas: image.Rect(0, 0, 6, 4),
+			border: image.Rect(0, 0, 6, 4),
+			opts: []BorderOption{
+				BorderTitle("ab", OverrunModeStrict),
+				BorderTitleAlign(align.HorizontalRight),
+				BorderTitleReserveRight(1),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, lineStyleChars[linestyle.Light][topLeftCorner])
+				testcanvas.MustSetCell(c, image.Point{0, 1}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{0, 2}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{0, 3}, lineStyleChars[linestyle.Light][bottomLeftCorner])
+
+				testcanvas.MustSetCell(c, image.Point{1, 0}, lineStyleChars[linestyle.Light][hLine])
+				testcanvas.MustSetCell(c, image.Point{1, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{2, 0}, 'a')
+				testcanvas.MustSetCell(c, image.Point{2, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{3, 0}, 'b')
+				testcanvas.MustSetCell(c, image.Point{3, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{4, 0}, lineStyleChars[linestyle.Light][hLine])
+				testcanvas.MustSetCell(c, image.Point{4, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{5, 0}, lineStyleChars[linestyle.Light][topRightCorner])
+				testcanvas.MustSetCell(c, image.Point{5, 1}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{5, 2}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{5, 3}, lineStyleChars[linestyle.Light][bottomRightCorner])
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws titles on the top and the bottom border",
+			canvas: image.Rect(0, 0, 6, 4),
+			border: image.Rect(0, 0, 6, 4),
+			opts: []BorderOption{
+				BorderTitle("ab", OverrunModeStrict),
+				BorderBottomTitle("cd", OverrunModeStrict, cell.Bold()),
+				BorderBottomTitleAlign(align.HorizontalRight),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, lineStyleChars[linestyle.Light][topLeftCorner])
+				testcanvas.MustSetCell(c, image.Point{0, 1}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{0, 2}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{0, 3}, lineStyleChars[linestyle.Light][bottomLeftCorner])
+
+				testcanvas.MustSetCell(c, image.Point{1, 0}, 'a')
+				testcanvas.MustSetCell(c, image.Point{1, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{2, 0}, 'b')
+				testcanvas.MustSetCell(c, image.Point{2, 3}, lineStyleChars[linestyle.Light][hLine])
+
+				testcanvas.MustSetCell(c, image.Point{3, 0}, lineStyleChars[linestyle.Light][hLine])
+				testcanvas.MustSetCell(c, image.Point{3, 3}, 'c', cell.Bold())
+
+				testcanvas.MustSetCell(c, image.Point{4, 0}, lineStyleChars[linestyle.Light][hLine])
+				testcanvas.MustSetCell(c, image.Point{4, 3}, 'd', cell.Bold())
+
+				testcanvas.MustSetCell(c, image.Point{5, 0}, lineStyleChars[linestyle.Light][topRightCorner])
+				testcanvas.MustSetCell(c, image.Point{5, 1}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{5, 2}, lineStyleChars[linestyle.Light][vLine])
+				testcanvas.MustSetCell(c, image.Point{5, 3}, lineStyleChars[linestyle.Light][bottomRightCorner])
+
 				testcanvas.MustApply(c, ft)
 				return ft
 			},
@@ -19,7 +19,6 @@ package draw
 import (
 	"fmt"
 	"image"
-	"strings"
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/canvas"
@@ -119,35 +118,22 @@ func TrimText(text string, maxCells int, om OverrunMode) (string, error) {
 	switch om {
 	case OverrunModeStrict:
 		return "", fmt.Errorf("the requested text %q takes %d cells to draw, space is available for only %d cells and overrun mode is %v", text, textCells, maxCells, om)
-	case OverrunModeTrim, OverrunModeThreeDot:
+	case OverrunModeThreeDot:
+		return Truncate(text, maxCells)
+	case OverrunModeTrim:
 	default:
 		return "", fmt.Errorf("unsupported overrun mode %d", om)
 	}
 
-	var b strings.Builder
-	cur := 0
-	for _, r := range text {
-		rw := runewidth.RuneWidth(r)
-		if cur+rw >= maxCells {
-			switch {
-			case om == OverrunModeTrim:
-				// Only write the rune if it still fits, i.e. don't cut
-				// full-width runes in half.
-				if cur+rw == maxCells {
-					b.WriteRune(r)
-				}
-			case om == OverrunModeThreeDot:
-				b.WriteRune('…')
-			}
-			break
-		}
-
-		b.WriteRune(r)
-		cur += rw
-	}
-	return b.String(), nil
+	// Only write runes that still fit, i.e. don't cut full-width runes in
+	// half.
+	return string(head([]rune(text), maxCells)), nil
 }
 
+// RichText is like Text, but the cell options change along the text as
+// specified by the RichTextString. The options of the RichTextString are
+// applied after the options provided via TextCellOpts, so they take
+// precedence on conflicts.
 func RichText(c *canvas.Canvas, text *cell.RichTextString, start image.Point, opts ...TextOption) error {
 	ar := c.Area()
 	if !start.In(ar) {
@@ -185,7 +171,11 @@ func RichText(c *canvas.Canvas, text *cell.RichTextString, start image.Point, op
 		richOpts := text.Opts(i)
 
 		if richOpts != nil {
-			cellOpts = append(opt.cellOpts, richOpts)
+			// Copy, appending to the provided options could overwrite the
+			// caller's backing array.
+			cellOpts = make([]cell.Option, 0, len(opt.cellOpts)+1)
+			cellOpts = append(cellOpts, opt.cellOpts...)
+			cellOpts = append(cellOpts, richOpts)
 			lastOpts = cellOpts
 		} else {
 			cellOpts = lastOpts
@@ -654,3 +654,78 @@ func TestResizeNeeded(t *testing.T) {
 		})
 	}
 }
+
+func TestRichText(t *testing.T) {
+	tests := []struct {
+		desc   string
+		canvas image.Rectangle
+		text   func() *cell.RichTextString
+		opts   []TextOption
+		want   func(size image.Point) *faketerm.Terminal
+	}{
+		{
+			desc:   "options of the rich text take precedence over TextCellOpts",
+			canvas: image.Rect(0, 0, 3, 1),
+			text: func() *cell.RichTextString {
+				return cell.NewRichTextString(cell.ColorRed).AddText("a").SetFgColor(cell.ColorGreen).AddText("bc")
+			},
+			opts: []TextOption{
+				TextCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b', cell.FgColor(cell.ColorGreen))
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, 'c', cell.FgColor(cell.ColorGreen))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cvs, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			if err := RichText(cvs, tc.text(), image.Point{0, 0}, tc.opts...); err != nil {
+				t.Fatalf("RichText => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(cvs.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := cvs.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(cvs.Size()), got); diff != "" {
+				t.Errorf("RichText => %v", diff)
+			}
+		})
+	}
+}
+
+func TestRichTextDoesNotModifyCellOpts(t *testing.T) {
+	cvs := testcanvas.MustNew(image.Rect(0, 0, 2, 1))
+	// The spare capacity must not be overwritten by the options of the rich
+	// text.
+	cellOpts := make([]cell.Option, 1, 2)
+	cellOpts[0] = cell.FgColor(cell.ColorBlue)
+	spare := cellOpts[:2]
+	spare[1] = cell.BgColor(cell.ColorYellow)
+
+	rts := cell.NewRichTextString(cell.ColorRed).AddText("ab")
+	if err := RichText(cvs, rts, image.Point{0, 0}, TextCellOpts(cellOpts...)); err != nil {
+		t.Fatalf("RichText => unexpected error: %v", err)
+	}
+
+	got := cell.NewOptions(spare[1])
+	want := cell.NewOptions(cell.BgColor(cell.ColorYellow))
+	if *got != *want {
+		t.Errorf("RichText modified the provided cell options, got %+v, want %+v", got, want)
+	}
+}
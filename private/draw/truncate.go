@@ -0,0 +1,198 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+// truncate.go contains code that shortens text to fit a width, marking the
+// removed part with an ellipsis.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// TruncateMode determines which part of the text is removed when it doesn't
+// fit.
+type TruncateMode int
+
+// String implements fmt.Stringer()
+func (tm TruncateMode) String() string {
+	if n, ok := truncateModeNames[tm]; ok {
+		return n
+	}
+	return "TruncateModeUnknown"
+}
+
+// truncateModeNames maps TruncateMode values to human readable names.
+var truncateModeNames = map[TruncateMode]string{
+	TruncateEnd:    "TruncateEnd",
+	TruncateStart:  "TruncateStart",
+	TruncateMiddle: "TruncateMiddle",
+}
+
+const (
+	// TruncateEnd removes the end of the text, e.g. "hello w…".
+	TruncateEnd TruncateMode = iota
+
+	// TruncateStart removes the start of the text, e.g. "…o world".
+	TruncateStart
+
+	// TruncateMiddle removes the middle of the text, e.g. "hell…rld".
+	TruncateMiddle
+)
+
+// DefaultEllipsis is the default value for the TruncateEllipsis option.
+const DefaultEllipsis = '…'
+
+// TruncateOption is used to provide options to Truncate and TruncatedText.
+type TruncateOption interface {
+	// set sets the provided option.
+	set(*truncateOptions)
+}
+
+// truncateOptions stores the provided options.
+type truncateOptions struct {
+	cellOpts []cell.Option
+	ellipsis rune
+	mode     TruncateMode
+}
+
+// newTruncateOptions returns truncate options with the default values set.
+func newTruncateOptions(opts ...TruncateOption) *truncateOptions {
+	to := &truncateOptions{
+		ellipsis: DefaultEllipsis,
+	}
+	for _, opt := range opts {
+		opt.set(to)
+	}
+	return to
+}
+
+// truncateOption implements TruncateOption.
+type truncateOption func(*truncateOptions)
+
+// set implements TruncateOption.set.
+func (to truncateOption) set(tOpts *truncateOptions) {
+	to(tOpts)
+}
+
+// TruncateEllipsis sets the rune that replaces the removed part of the text.
+// Defaults to DefaultEllipsis.
+func TruncateEllipsis(r rune) TruncateOption {
+	return truncateOption(func(tOpts *truncateOptions) {
+		tOpts.ellipsis = r
+	})
+}
+
+// TruncateAt sets which part of the text is removed when it doesn't fit.
+// Defaults to TruncateEnd.
+func TruncateAt(tm TruncateMode) TruncateOption {
+	return truncateOption(func(tOpts *truncateOptions) {
+		tOpts.mode = tm
+	})
+}
+
+// TruncateCellOpts sets options on the cells that contain the text.
+// Only used by TruncatedText.
+func TruncateCellOpts(opts ...cell.Option) TruncateOption {
+	return truncateOption(func(tOpts *truncateOptions) {
+		tOpts.cellOpts = opts
+	})
+}
+
+// Truncate shortens the text so that it fits the specified amount of cells,
+// replacing the removed part with the ellipsis rune. Returns the text
+// unchanged if it fits. Full-width runes are never cut in half, so the
+// returned text might occupy one cell less than maxCells.
+func Truncate(text string, maxCells int, opts ...TruncateOption) (string, error) {
+	to := newTruncateOptions(opts...)
+	if maxCells < 1 {
+		return "", fmt.Errorf("maxCells(%d) cannot be less than one", maxCells)
+	}
+	if _, ok := truncateModeNames[to.mode]; !ok {
+		return "", fmt.Errorf("unsupported truncate mode %v(%d)", to.mode, to.mode)
+	}
+	ew := runewidth.RuneWidth(to.ellipsis)
+	if ew < 1 {
+		return "", fmt.Errorf("invalid ellipsis rune %q, it must occupy at least one cell", to.ellipsis)
+	}
+
+	if runewidth.StringWidth(text) <= maxCells {
+		// Nothing to do if the text fits.
+		return text, nil
+	}
+	if ew > maxCells {
+		return "", fmt.Errorf("the ellipsis rune %q takes %d cells, space is available for only %d cells", to.ellipsis, ew, maxCells)
+	}
+
+	runes := []rune(text)
+	budget := maxCells - ew
+	switch to.mode {
+	case TruncateStart:
+		return string(to.ellipsis) + string(tail(runes, budget)), nil
+
+	case TruncateMiddle:
+		h := head(runes, (budget+1)/2)
+		t := tail(runes[len(h):], budget-runewidth.StringWidth(string(h)))
+		return string(h) + string(to.ellipsis) + string(t), nil
+
+	default:
+		return string(head(runes, budget)) + string(to.ellipsis), nil
+	}
+}
+
+// head returns the longest prefix of the runes that fits into the cells.
+func head(runes []rune, cells int) []rune {
+	cur := 0
+	for i, r := range runes {
+		rw := runewidth.RuneWidth(r)
+		if cur+rw > cells {
+			return runes[:i]
+		}
+		cur += rw
+	}
+	return runes
+}
+
+// tail returns the longest suffix of the runes that fits into the cells.
+func tail(runes []rune, cells int) []rune {
+	cur := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		rw := runewidth.RuneWidth(runes[i])
+		if cur+rw > cells {
+			return runes[i+1:]
+		}
+		cur += rw
+	}
+	return runes
+}
+
+// TruncatedText prints the text on the canvas starting at the specified
+// point, truncating it with an ellipsis so that it fits into the specified
+// width in cells. The width must not extend beyond the canvas.
+func TruncatedText(c *canvas.Canvas, text string, start image.Point, width int, opts ...TruncateOption) error {
+	to := newTruncateOptions(opts...)
+	if max := c.Area().Max.X; start.X+width > max {
+		return fmt.Errorf("the requested width %d starting at %v extends beyond the canvas that ends at X coordinate %d", width, start, max)
+	}
+	truncated, err := Truncate(text, width, opts...)
+	if err != nil {
+		return err
+	}
+	return Text(c, truncated, start, TextMaxX(start.X+width), TextCellOpts(to.cellOpts...))
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/faketerm"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		desc     string
+		text     string
+		maxCells int
+		opts     []TruncateOption
+		want     string
+		wantErr  bool
+	}{
+		{
+			desc:     "fails on zero max cells",
+			text:     "ab",
+			maxCells: 0,
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on unsupported mode",
+			text:     "ab",
+			maxCells: 1,
+			opts:     []TruncateOption{TruncateAt(TruncateMode(-1))},
+			wantErr:  true,
+		},
+		{
+			desc:     "fails on a zero width ellipsis",
+			text:     "ab",
+			maxCells: 1,
+			opts:     []TruncateOption{TruncateEllipsis(0)},
+			wantErr:  true,
+		},
+		{
+			desc:     "fails when a full-width ellipsis doesn't fit",
+			text:     "ab",
+			maxCells: 1,
+			opts:     []TruncateOption{TruncateEllipsis('世')},
+			wantErr:  true,
+		},
+		{
+			desc:     "text fits",
+			text:     "hello",
+			maxCells: 5,
+			want:     "hello",
+		},
+		{
+			desc:     "truncates the end by default",
+			text:     "hello world",
+			maxCells: 8,
+			want:     "hello w…",
+		},
+		{
+			desc:     "truncates the start",
+			text:     "hello world",
+			maxCells: 8,
+			opts:     []TruncateOption{TruncateAt(TruncateStart)},
+			want:     "…o world",
+		},
+		{
+			desc:     "truncates the middle",
+			text:     "hello world",
+			maxCells: 8,
+			opts:     []TruncateOption{TruncateAt(TruncateMiddle)},
+			want:     "hell…rld",
+		},
+		{
+			desc:     "custom ellipsis",
+			text:     "hello world",
+			maxCells: 8,
+			opts:     []TruncateOption{TruncateEllipsis('>')},
+			want:     "hello w>",
+		},
+		{
+			desc:     "only the ellipsis fits",
+			text:     "hello",
+			maxCells: 1,
+			opts:     []TruncateOption{TruncateAt(TruncateMiddle)},
+			want:     "…",
+		},
+		{
+			desc:     "doesn't cut full-width runes at the end",
+			text:     "世世世",
+			maxCells: 4,
+			want:     "世…",
+		},
+		{
+			desc:     "doesn't cut full-width runes at the start",
+			text:     "世世世",
+			maxCells: 4,
+			opts:     []TruncateOption{TruncateAt(TruncateStart)},
+			want:     "…世",
+		},
+		{
+			desc:     "doesn't cut full-width runes in the middle",
+			text:     "世世a世世",
+			maxCells: 6,
+			opts:     []TruncateOption{TruncateAt(TruncateMiddle)},
+			want:     "世…世",
+		},
+		{
+			desc:     "full-width ellipsis",
+			text:     "hello",
+			maxCells: 4,
+			opts:     []TruncateOption{TruncateEllipsis('世')},
+			want:     "he世",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Truncate(tc.text, tc.maxCells, tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Truncate => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got != tc.want {
+				t.Errorf("Truncate =>\n  got: %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTruncatedText(t *testing.T) {
+	tests := []struct {
+		desc    string
+		canvas  image.Rectangle
+		text    string
+		start   image.Point
+		width   int
+		opts    []TruncateOption
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc:    "fails when the width extends beyond the canvas",
+			canvas:  image.Rect(0, 0, 4, 1),
+			text:    "ab",
+			start:   image.Point{1, 0},
+			width:   4,
+			wantErr: true,
+		},
+		{
+			desc:   "draws text that fits",
+			canvas: image.Rect(0, 0, 4, 1),
+			text:   "ab",
+			width:  4,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(c, image.Point{1, 0}, 'b')
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws truncated text with cell options",
+			canvas: image.Rect(0, 0, 5, 1),
+			text:   "abcdef",
+			start:  image.Point{1, 0},
+			width:  3,
+			opts: []TruncateOption{
+				TruncateAt(TruncateStart),
+				TruncateCellOpts(cell.FgColor(cell.ColorRed)),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '…', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{2, 0}, 'e', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{3, 0}, 'f', cell.FgColor(cell.ColorRed))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			err = TruncatedText(c, tc.text, tc.start, tc.width, tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("TruncatedText => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("TruncatedText => %v", diff)
+			}
+		})
+	}
+}
@@ -169,6 +169,25 @@ func (t *Terminal) Flush() error {
 	return nil // nowhere to flush to.
 }
 
+// Frame implements terminalapi.FrameReader.Frame.
+// The fake terminal doesn't flush, so the frame is the back buffer.
+func (t *Terminal) Frame() ([][]terminalapi.FrameCell, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	frame := make([][]terminalapi.FrameCell, len(t.buffer))
+	for col := range t.buffer {
+		frame[col] = make([]terminalapi.FrameCell, len(t.buffer[col]))
+		for row, c := range t.buffer[col] {
+			frame[col][row] = terminalapi.FrameCell{
+				Rune: c.Rune,
+				Opts: *c.Opts,
+			}
+		}
+	}
+	return frame, nil
+}
+
 // SetCursor implements terminalapi.Terminal.SetCursor.
 func (t *Terminal) SetCursor(p image.Point) {
 	log.Fatal("unimplemented")
@@ -0,0 +1,247 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remotetty connects the tcell terminal to the byte stream of a
+// remote client, e.g. an SSH channel or a WebSocket.
+package remotetty
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"sync"
+
+	tcell "github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/terminfo"
+	tdtcell "github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// DefaultTerm is the terminal type used when the client's one isn't known.
+const DefaultTerm = "xterm-256color"
+
+// TTY is a tcell.Tty backed by the byte stream of a remote client.
+// Implements tcell.Tty.
+type TTY struct {
+	// ch is the byte stream.
+	ch io.ReadWriter
+	// data receives the chunks read from the channel.
+	data chan []byte
+	// eof gets closed when the client closes the stream.
+	eof chan struct{}
+	// stop gets closed when StopReading is called.
+	stop chan struct{}
+	// pumpOnce ensures the stream is read by only one goroutine.
+	pumpOnce sync.Once
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// pending is the part of the last chunk that wasn't read yet.
+	pending []byte
+	// drained gets closed when tcell drains the input, it unblocks Read.
+	drained chan struct{}
+	// size is the size of the client's terminal window.
+	size image.Point
+	// onResize is called when the size changes.
+	onResize func()
+}
+
+// New returns a new TTY for the stream. The size is the initial size of the
+// client's terminal window. The stream isn't read until tcell starts the
+// TTY, so it can be fully set up before the first read.
+// Call StopReading when the TTY isn't required anymore.
+func New(ch io.ReadWriter, size image.Point) *TTY {
+	return &TTY{
+		ch:      ch,
+		data:    make(chan []byte),
+		eof:     make(chan struct{}),
+		stop:    make(chan struct{}),
+		drained: make(chan struct{}),
+		size:    size,
+	}
+}
+
+// pump reads from the stream. Reading happens in a separate goroutine, so
+// that tcell can stop reading at any time by calling Drain.
+func (t *TTY) pump() {
+	defer close(t.eof)
+	for {
+		chunk := make([]byte, 128)
+		n, err := t.ch.Read(chunk)
+		if n > 0 {
+			select {
+			case t.data <- chunk[:n]:
+			case <-t.stop:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Start implements tcell.Tty.Start.
+// The client's terminal is already in the raw mode.
+func (t *TTY) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.drained = make(chan struct{})
+	t.pumpOnce.Do(func() {
+		go t.pump() // Stops when the stream gets closed or StopReading is called.
+	})
+	return nil
+}
+
+// Stop implements tcell.Tty.Stop.
+func (t *TTY) Stop() error {
+	return nil
+}
+
+// Drain implements tcell.Tty.Drain.
+func (t *TTY) Drain() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.drained:
+	default:
+		close(t.drained)
+	}
+	return nil
+}
+
+// NotifyResize implements tcell.Tty.NotifyResize.
+func (t *TTY) NotifyResize(cb func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onResize = cb
+}
+
+// WindowSize implements tcell.Tty.WindowSize.
+func (t *TTY) WindowSize() (int, int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.size.X, t.size.Y, nil
+}
+
+// Resize sets the new size of the client's terminal window.
+func (t *TTY) Resize(size image.Point) {
+	t.mu.Lock()
+	t.size = size
+	cb := t.onResize
+	t.mu.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
+
+// Read implements io.Reader.Read.
+// Blocks until the client sends data or tcell drains the input. Doesn't
+// report the end of the input when the client disconnects, since tcell treats
+// that as an error, see EOF instead.
+func (t *TTY) Read(p []byte) (int, error) {
+	t.mu.Lock()
+	if len(t.pending) > 0 {
+		n := copy(p, t.pending)
+		t.pending = t.pending[n:]
+		t.mu.Unlock()
+		return n, nil
+	}
+	drained := t.drained
+	t.mu.Unlock()
+
+	select {
+	case chunk := <-t.data:
+		n := copy(p, chunk)
+		t.mu.Lock()
+		t.pending = chunk[n:]
+		t.mu.Unlock()
+		return n, nil
+	case <-drained:
+		return 0, nil
+	}
+}
+
+// Write implements io.Writer.Write.
+func (t *TTY) Write(p []byte) (int, error) {
+	return t.ch.Write(p)
+}
+
+// Close implements io.Closer.Close.
+// The stream is owned by the server and isn't closed.
+func (t *TTY) Close() error {
+	return nil
+}
+
+// EOF returns a channel that gets closed when the client closes the stream,
+// i.e. disconnects.
+func (t *TTY) EOF() <-chan struct{} {
+	return t.eof
+}
+
+// StopReading stops reading from the stream. Must be called after the
+// terminal using the TTY was closed.
+func (t *TTY) StopReading() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.stop:
+	default:
+		close(t.stop)
+	}
+}
+
+// FollowResizes resizes the TTY to the sizes received from the channel until
+// it gets closed or StopReading is called.
+func (t *TTY) FollowResizes(resizes <-chan image.Point) {
+	for {
+		select {
+		case size, ok := <-resizes:
+			if !ok {
+				return
+			}
+			t.Resize(size)
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// NewTerminal returns a new tcell based terminal that draws on the TTY. The
+// term is the terminal type of the client, i.e. the value of its TERM
+// environment variable. Falls back to DefaultTerm if the type isn't known.
+// The options are applied to the tcell terminal after the ones this
+// function sets.
+func NewTerminal(t *TTY, term string, opts ...tdtcell.Option) (*tdtcell.Terminal, error) {
+	ti, err := terminfo.LookupTerminfo(term)
+	if err != nil {
+		if ti, err = terminfo.LookupTerminfo(DefaultTerm); err != nil {
+			return nil, fmt.Errorf("terminfo.LookupTerminfo(%q) => %v", DefaultTerm, err)
+		}
+	}
+
+	screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(t, ti)
+	if err != nil {
+		return nil, fmt.Errorf("tcell.NewTerminfoScreenFromTtyTerminfo => %v", err)
+	}
+	return tdtcell.New(append([]tdtcell.Option{
+		tdtcell.Screen(screen),
+		// The size of the client's window is reported by the client, there
+		// is no local TTY to poll.
+		tdtcell.ResizePollInterval(0),
+		tdtcell.PassthroughWriter(t),
+		// The environment of the server says nothing about the client.
+		tdtcell.GraphicsProtocol(terminalapi.GraphicsNone),
+	}, opts...)...)
+}
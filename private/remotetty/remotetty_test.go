@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotetty
+
+import (
+	"image"
+	"io"
+	"testing"
+	"time"
+)
+
+// stream is a fake stream that reports the reads.
+type stream struct {
+	reads chan struct{}
+}
+
+// Read implements io.Reader.Read.
+func (s *stream) Read(p []byte) (int, error) {
+	s.reads <- struct{}{}
+	return 0, io.EOF
+}
+
+// Write implements io.Writer.Write.
+func (s *stream) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func TestNewDoesNotRead(t *testing.T) {
+	s := &stream{reads: make(chan struct{}, 1)}
+	tty := New(s, image.Point{10, 3})
+	defer tty.StopReading()
+
+	select {
+	case <-s.reads:
+		t.Fatalf("New => read from the stream before Start")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := tty.Start(); err != nil {
+		t.Fatalf("Start => unexpected error: %v", err)
+	}
+	select {
+	case <-s.reads:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Start => didn't start reading from the stream")
+	}
+	select {
+	case <-tty.EOF():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("EOF => not closed after the stream ended")
+	}
+
+	// Starting again after a Stop doesn't read from the stream twice.
+	if err := tty.Stop(); err != nil {
+		t.Fatalf("Stop => unexpected error: %v", err)
+	}
+	if err := tty.Start(); err != nil {
+		t.Fatalf("Start => unexpected error: %v", err)
+	}
+	select {
+	case <-s.reads:
+		t.Fatalf("Start => read from the stream again")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
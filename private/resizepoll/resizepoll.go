@@ -0,0 +1,68 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resizepoll detects changes of the terminal size by polling.
+//
+// The terminal libraries learn about size changes from the SIGWINCH signal,
+// which isn't delivered reliably on all platforms. Polling the size acts as a
+// safety net, so the dashboard doesn't stay at the old size until the next
+// input event.
+package resizepoll
+
+import (
+	"image"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// TTYSize returns the current size of the terminal connected to the standard
+// output.
+func TTYSize() (image.Point, error) {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return image.ZP, err
+	}
+	return image.Point{w, h}, nil
+}
+
+// Poll compares the actual size of the terminal with the size known to the
+// terminal library once per interval and calls onChange with the actual size
+// whenever they differ. Errors and empty sizes returned by actual are ignored,
+// terminals report them transiently during a resize. Blocks until done gets
+// closed. Returns immediately if the interval isn't a positive duration.
+func Poll(done <-chan struct{}, interval time.Duration, known func() image.Point, actual func() (image.Point, error), onChange func(image.Point)) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		size, err := actual()
+		if err != nil || size.X <= 0 || size.Y <= 0 {
+			continue
+		}
+		if size != known() {
+			onChange(size)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resizepoll
+
+import (
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		desc     string
+		interval time.Duration
+		known    image.Point
+		// actual are the sizes returned by consecutive calls to actual, a nil
+		// entry results in an error.
+		actual []*image.Point
+		want   []image.Point
+	}{
+		{
+			desc:     "disabled by a zero interval",
+			interval: 0,
+			known:    image.Point{10, 10},
+			actual:   []*image.Point{{X: 20, Y: 20}},
+		},
+		{
+			desc:     "no change when the size matches",
+			interval: time.Millisecond,
+			known:    image.Point{10, 10},
+			actual:   []*image.Point{{X: 10, Y: 10}, {X: 10, Y: 10}},
+		},
+		{
+			desc:     "reports changed sizes",
+			interval: time.Millisecond,
+			known:    image.Point{10, 10},
+			actual:   []*image.Point{{X: 20, Y: 10}, {X: 20, Y: 10}, {X: 5, Y: 5}},
+			want:     []image.Point{{20, 10}, {5, 5}},
+		},
+		{
+			desc:     "ignores errors and empty sizes",
+			interval: time.Millisecond,
+			known:    image.Point{10, 10},
+			actual:   []*image.Point{nil, {X: 0, Y: 0}, {X: 0, Y: 10}, {X: 1, Y: 1}},
+			want:     []image.Point{{1, 1}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			done := make(chan struct{})
+			exited := make(chan struct{})
+			known := tc.known
+			calls := 0
+			actual := func() (image.Point, error) {
+				if calls == len(tc.actual) {
+					close(done) // All sizes were returned, stop polling.
+				}
+				if calls >= len(tc.actual) {
+					calls++
+					return image.Point{}, errors.New("no more sizes")
+				}
+				s := tc.actual[calls]
+				calls++
+				if s == nil {
+					return image.Point{}, errors.New("size unavailable")
+				}
+				return *s, nil
+			}
+
+			var got []image.Point
+			go func() {
+				defer close(exited)
+				Poll(done, tc.interval, func() image.Point { return known }, actual, func(size image.Point) {
+					known = size
+					got = append(got, size)
+				})
+			}()
+
+			select {
+			case <-exited:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Poll => didn't exit")
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Poll => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -16,7 +16,10 @@
 // gives different treatment to certain runes with ambiguous width.
 package runewidth
 
-import runewidth "github.com/mattn/go-runewidth"
+import (
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
 
 // Option is used to provide options.
 type Option interface {
@@ -81,15 +84,42 @@ func RuneWidth(r rune, opts ...Option) int {
 }
 
 // StringWidth is like RuneWidth, but returns the number of cells occupied by
-// all the runes in the string.
+// all the grapheme clusters in the string. See ClusterWidth.
 func StringWidth(s string, opts ...Option) int {
 	var width int
-	for _, r := range []rune(s) {
-		width += RuneWidth(r, opts...)
+	for _, c := range Clusters([]rune(s)) {
+		width += ClusterWidth(c, opts...)
 	}
 	return width
 }
 
+// Clusters splits the runes into grapheme clusters, i.e. the user-perceived
+// characters. A single cluster can consist of multiple runes, e.g. an emoji
+// with a skin tone modifier, emoji joined by the zero width joiner or a letter
+// followed by combining characters.
+// The returned clusters are sub-slices of the provided runes.
+func Clusters(runes []rune) [][]rune {
+	var res [][]rune
+	start := 0
+	g := uniseg.NewGraphemes(string(runes))
+	for g.Next() {
+		end := start + len(g.Runes())
+		res = append(res, runes[start:end])
+		start = end
+	}
+	return res
+}
+
+// ClusterWidth returns the number of cells needed to draw the grapheme
+// cluster. Cells in termdash hold a single rune, so only the first rune of
+// the cluster gets drawn and the remaining runes don't occupy any cells.
+func ClusterWidth(cluster []rune, opts ...Option) int {
+	if len(cluster) == 0 {
+		return 0
+	}
+	return RuneWidth(cluster[0], opts...)
+}
+
 // inTable determines if the rune falls within the table.
 // Copied from github.com/mattn/go-runewidth/blob/master/runewidth.go.
 func inTable(r rune, t table) bool {
@@ -168,6 +168,21 @@ func TestStringWidth(t *testing.T) {
 			eastAsian: true,
 			want:      4,
 		},
+		{
+			desc: "emoji with a skin tone modifier",
+			str:  "a👍🏽",
+			want: 3,
+		},
+		{
+			desc: "emoji joined by the zero width joiner",
+			str:  "👨‍👩‍👧",
+			want: 2,
+		},
+		{
+			desc: "letter followed by a combining character",
+			str:  "cafés",
+			want: 5,
+		},
 	}
 
 	for _, tc := range tests {
@@ -183,3 +198,118 @@ func TestStringWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestClusters(t *testing.T) {
+	tests := []struct {
+		desc string
+		str  string
+		want []string
+	}{
+		{
+			desc: "empty string",
+			str:  "",
+			want: nil,
+		},
+		{
+			desc: "ascii characters",
+			str:  "ab",
+			want: []string{"a", "b"},
+		},
+		{
+			desc: "full-width runes",
+			str:  "世界",
+			want: []string{"世", "界"},
+		},
+		{
+			desc: "emoji with a skin tone modifier",
+			str:  "a👍🏽b",
+			want: []string{"a", "👍🏽", "b"},
+		},
+		{
+			desc: "emoji joined by the zero width joiner",
+			str:  "👨‍👩‍👧a",
+			want: []string{"👨‍👩‍👧", "a"},
+		},
+		{
+			desc: "emoji with a variation selector",
+			str:  "❤️a",
+			want: []string{"❤️", "a"},
+		},
+		{
+			desc: "letter followed by combining characters",
+			str:  "éa",
+			want: []string{"é", "a"},
+		},
+		{
+			desc: "newlines are separate clusters",
+			str:  "a\nb",
+			want: []string{"a", "\n", "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got []string
+			for _, c := range Clusters([]rune(tc.str)) {
+				got = append(got, string(c))
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("Clusters(%q) => %q, want %q", tc.str, got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("Clusters(%q) => %q, want %q", tc.str, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterWidth(t *testing.T) {
+	tests := []struct {
+		desc    string
+		cluster string
+		opts    []Option
+		want    int
+	}{
+		{
+			desc:    "empty cluster",
+			cluster: "",
+			want:    0,
+		},
+		{
+			desc:    "half-width rune",
+			cluster: "a",
+			want:    1,
+		},
+		{
+			desc:    "letter followed by a combining character",
+			cluster: "é",
+			want:    1,
+		},
+		{
+			desc:    "emoji with a skin tone modifier",
+			cluster: "👍🏽",
+			want:    2,
+		},
+		{
+			desc:    "emoji joined by the zero width joiner",
+			cluster: "👨‍👩‍👧",
+			want:    2,
+		},
+		{
+			desc:    "override rune width with an option",
+			cluster: "\n",
+			opts:    []Option{CountAsWidth('\n', 1)},
+			want:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := ClusterWidth([]rune(tc.cluster), tc.opts...); got != tc.want {
+				t.Errorf("ClusterWidth(%q) => %v, want %v", tc.cluster, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+// font.go contains the embedded monospace bitmap font used for PNG images.
+
+import (
+	"strings"
+)
+
+const (
+	// glyphWidth is the width of the glyphs in font pixels.
+	glyphWidth = 5
+	// glyphHeight is the height of the glyphs in font pixels, including two
+	// rows for the descenders.
+	glyphHeight = 9
+	// glyphScale is the number of image pixels per font pixel.
+	glyphScale = 2
+	// glyphX and glyphY are the position of the glyph within the cell in
+	// image pixels.
+	glyphX = 1
+	glyphY = 3
+)
+
+// glyph is the bitmap of one character, indexed as [y][x].
+type glyph [glyphHeight][glyphWidth]bool
+
+// glyphRows are the bitmaps of the characters in the font. Each string
+// contains the rows of the glyph separated by spaces, where '#' is a set
+// pixel. Glyphs without descenders omit the last two rows.
+var glyphRows = map[rune]string{
+	'!':  "..#.. ..#.. ..#.. ..#.. ..#.. ..... ..#..",
+	'"':  ".#.#. .#.#. ..... ..... ..... ..... .....",
+	'#':  ".#.#. .#.#. ##### .#.#. ##### .#.#. .#.#.",
+	'$':  "..#.. .#### #.#.. .###. ..#.# ####. ..#..",
+	'%':  "##... ##..# ...#. ..#.. .#... #..## ...##",
+	'&':  ".##.. #..#. #.#.. .#... #.#.# #..#. .##.#",
+	'\'': "..#.. ..#.. ..... ..... ..... ..... .....",
+	'(':  "...#. ..#.. .#... .#... .#... ..#.. ...#.",
+	')':  ".#... ..#.. ...#. ...#. ...#. ..#.. .#...",
+	'*':  "..... ..#.. #.#.# .###. #.#.# ..#.. .....",
+	'+':  "..... ..#.. ..#.. ##### ..#.. ..#.. .....",
+	',':  "..... ..... ..... ..... .##.. ..#.. .#...",
+	'-':  "..... ..... ..... ##### ..... ..... .....",
+	'.':  "..... ..... ..... ..... ..... .##.. .##..",
+	'/':  "..... ....# ...#. ..#.. .#... #.... .....",
+	'0':  ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1':  "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2':  ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3':  "##### ...#. ..#.. ...#. ....# #...# .###.",
+	'4':  "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5':  "##### #.... ####. ....# ....# #...# .###.",
+	'6':  "..##. .#... #.... ####. #...# #...# .###.",
+	'7':  "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8':  ".###. #...# #...# .###. #...# #...# .###.",
+	'9':  ".###. #...# #...# .#### ....# ...#. .##..",
+	':':  "..... .##.. .##.. ..... .##.. .##.. .....",
+	';':  "..... .##.. .##.. ..... .##.. ..#.. .#...",
+	'<':  "...#. ..#.. .#... #.... .#... ..#.. ...#.",
+	'=':  "..... ..... ##### ..... ##### ..... .....",
+	'>':  ".#... ..#.. ...#. ....# ...#. ..#.. .#...",
+	'?':  ".###. #...# ....# ...#. ..#.. ..... ..#..",
+	'@':  ".###. #...# ....# .##.# #.#.# #.#.# .###.",
+	'A':  ".###. #...# #...# ##### #...# #...# #...#",
+	'B':  "####. #...# #...# ####. #...# #...# ####.",
+	'C':  ".###. #...# #.... #.... #.... #...# .###.",
+	'D':  "###.. #..#. #...# #...# #...# #..#. ###..",
+	'E':  "##### #.... #.... ####. #.... #.... #####",
+	'F':  "##### #.... #.... ####. #.... #.... #....",
+	'G':  ".###. #...# #.... #.### #...# #...# .####",
+	'H':  "#...# #...# #...# ##### #...# #...# #...#",
+	'I':  ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J':  "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K':  "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L':  "#.... #.... #.... #.... #.... #.... #####",
+	'M':  "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N':  "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O':  ".###. #...# #...# #...# #...# #...# .###.",
+	'P':  "####. #...# #...# ####. #.... #.... #....",
+	'Q':  ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R':  "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S':  ".#### #.... #.... .###. ....# ....# ####.",
+	'T':  "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U':  "#...# #...# #...# #...# #...# #...# .###.",
+	'V':  "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W':  "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X':  "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y':  "#...# #...# #...# .#.#. ..#.. ..#.. ..#..",
+	'Z':  "##### ....# ...#. ..#.. .#... #.... #####",
+	'[':  ".###. .#... .#... .#... .#... .#... .###.",
+	'\\': "..... #.... .#... ..#.. ...#. ....# .....",
+	']':  ".###. ...#. ...#. ...#. ...#. ...#. .###.",
+	'^':  "..#.. .#.#. #...# ..... ..... ..... .....",
+	'_':  "..... ..... ..... ..... ..... ..... #####",
+	'`':  ".#... ..#.. ..... ..... ..... ..... .....",
+	'a':  "..... ..... .###. ....# .#### #...# .####",
+	'b':  "#.... #.... #.##. ##..# #...# #...# ####.",
+	'c':  "..... ..... .###. #.... #.... #...# .###.",
+	'd':  "....# ....# .##.# #..## #...# #...# .####",
+	'e':  "..... ..... .###. #...# ##### #.... .###.",
+	'f':  "..##. .#..# .#... ###.. .#... .#... .#...",
+	'g':  "..... ..... .#### #...# #...# .#### ....# ....# .###.",
+	'h':  "#.... #.... #.##. ##..# #...# #...# #...#",
+	'i':  "..#.. ..... .##.. ..#.. ..#.. ..#.. .###.",
+	'j':  "...#. ..... ..##. ...#. ...#. ...#. ...#. #..#. .##..",
+	'k':  "#.... #.... #..#. #.#.. ##... #.#.. #..#.",
+	'l':  ".##.. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'm':  "..... ..... ##.#. #.#.# #.#.# #...# #...#",
+	'n':  "..... ..... #.##. ##..# #...# #...# #...#",
+	'o':  "..... ..... .###. #...# #...# #...# .###.",
+	'p':  "..... ..... ####. #...# #...# ####. #.... #.... #....",
+	'q':  "..... ..... .#### #...# #...# .#### ....# ....# ....#",
+	'r':  "..... ..... #.##. ##..# #.... #.... #....",
+	's':  "..... ..... .###. #.... .###. ....# ####.",
+	't':  ".#... .#... ###.. .#... .#... .#..# ..##.",
+	'u':  "..... ..... #...# #...# #...# #..## .##.#",
+	'v':  "..... ..... #...# #...# #...# .#.#. ..#..",
+	'w':  "..... ..... #...# #...# #.#.# #.#.# .#.#.",
+	'x':  "..... ..... #...# .#.#. ..#.. .#.#. #...#",
+	'y':  "..... ..... #...# #...# #...# .#### ....# ....# .###.",
+	'z':  "..... ..... ##### ...#. ..#.. .#... #####",
+	'{':  "...#. ..#.. ..#.. .#... ..#.. ..#.. ...#.",
+	'|':  "..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'}':  ".#... ..#.. ..#.. ...#. ..#.. ..#.. .#...",
+	'~':  "..... ..... .#... #.#.# ...#. ..... .....",
+	'…':  "..... ..... ..... ..... ..... ..... #.#.#",
+	'•':  "..... ..... .###. .###. .###. ..... .....",
+	'■':  "..... ##### ##### ##### ##### ##### .....",
+	'°':  ".##.. #..#. #..#. .##.. ..... ..... .....",
+	'←':  "..... ..#.. .#... ##### .#... ..#.. .....",
+	'↑':  "..#.. .###. #.#.# ..#.. ..#.. ..#.. .....",
+	'→':  "..... ..#.. ...#. ##### ...#. ..#.. .....",
+	'↓':  "..#.. ..#.. ..#.. #.#.# .###. ..#.. .....",
+	'▲':  "..... ..#.. ..#.. .###. .###. ##### .....",
+	'▼':  "..... ##### .###. .###. ..#.. ..#.. .....",
+	'◀':  "....# ...## ..### .#### ..### ...## ....#",
+	'▶':  "#.... ##... ###.. ####. ###.. ##... #....",
+	'✓':  "..... ....# ...#. #.#.. .#... ..... .....",
+}
+
+// font are the glyphs parsed from glyphRows.
+var font = parseFont(glyphRows)
+
+// parseFont parses the glyphs from their rows.
+func parseFont(rows map[rune]string) map[rune]*glyph {
+	f := make(map[rune]*glyph, len(rows))
+	for r, s := range rows {
+		var g glyph
+		for y, row := range strings.Fields(s) {
+			for x, c := range row {
+				g[y][x] = c == '#'
+			}
+		}
+		f[r] = &g
+	}
+	return f
+}
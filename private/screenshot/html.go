@@ -0,0 +1,159 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+// html.go exports the frame as an HTML document.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// htmlHeader starts the HTML document.
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>termdash</title>
+<style>
+pre { background-color: %s; color: %s; font-family: monospace; line-height: 1.2; margin: 0; display: inline-block; }
+a { color: inherit; }
+.blink { animation: blink 1s step-end infinite; }
+@keyframes blink { 50%% { opacity: 0; } }
+</style>
+</head>
+<body>
+<pre>`
+
+// htmlFooter ends the HTML document.
+const htmlFooter = `</pre>
+</body>
+</html>
+`
+
+// span is a run of consecutive cells on one row with the same appearance.
+type span struct {
+	// css is the inline style of the span, empty for the default style.
+	css string
+	// blink indicates if the text blinks.
+	blink bool
+	// link is the URL the text links to.
+	link string
+	text strings.Builder
+}
+
+// cellCSS returns the inline style of a cell with the options.
+func cellCSS(fc *terminalapi.FrameCell) string {
+	st := resolve(&fc.Opts)
+	var props []string
+	if st.fg != DefaultFg {
+		props = append(props, "color: "+hexColor(st.fg))
+	}
+	if st.bg != DefaultBg {
+		props = append(props, "background-color: "+hexColor(st.bg))
+	}
+	if st.bold {
+		props = append(props, "font-weight: bold")
+	}
+	if st.italic {
+		props = append(props, "font-style: italic")
+	}
+	var decorations []string
+	if fc.Opts.Underline {
+		decorations = append(decorations, "underline")
+	}
+	if fc.Opts.Strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		props = append(props, "text-decoration: "+strings.Join(decorations, " "))
+	}
+	return strings.Join(props, "; ")
+}
+
+// write writes the span as HTML.
+func (s *span) write(w io.Writer) {
+	var attrs []string
+	if s.css != "" {
+		attrs = append(attrs, fmt.Sprintf(`style="%s"`, s.css))
+	}
+	if s.blink {
+		attrs = append(attrs, `class="blink"`)
+	}
+
+	text := html.EscapeString(s.text.String())
+	switch {
+	case s.link != "":
+		attrs = append([]string{fmt.Sprintf(`href="%s"`, html.EscapeString(s.link))}, attrs...)
+		fmt.Fprintf(w, "<a %s>%s</a>", strings.Join(attrs, " "), text)
+	case len(attrs) > 0:
+		fmt.Fprintf(w, "<span %s>%s</span>", strings.Join(attrs, " "), text)
+	default:
+		fmt.Fprint(w, text)
+	}
+}
+
+// HTML writes the frame indexed as [x][y] as a standalone HTML document. The
+// text is in a preformatted block, runs of cells with the same colors and
+// attributes are styled spans. Hyperlinks set with cell.Link are links.
+func HTML(w io.Writer, frame [][]terminalapi.FrameCell) error {
+	sz := size(frame)
+	if sz.X == 0 || sz.Y == 0 {
+		return errors.New("cannot take a screenshot of an empty frame")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, htmlHeader, hexColor(DefaultBg), hexColor(DefaultFg))
+	for row := 0; row < sz.Y/CellHeight; row++ {
+		if row > 0 {
+			fmt.Fprint(bw, "\n")
+		}
+		var cur *span
+		for col := 0; col < len(frame); col++ {
+			fc := frame[col][row]
+			css := cellCSS(&fc)
+			if cur == nil || css != cur.css || fc.Opts.Blink != cur.blink || fc.Opts.Link != cur.link {
+				if cur != nil {
+					cur.write(bw)
+				}
+				cur = &span{
+					css:   css,
+					blink: fc.Opts.Blink,
+					link:  fc.Opts.Link,
+				}
+			}
+
+			r := fc.Rune
+			if r == 0 {
+				r = ' '
+			}
+			cur.text.WriteRune(r)
+			// The second cell of a full-width rune has no content of its own.
+			if runewidth.RuneWidth(r) == 2 {
+				col++
+			}
+		}
+		cur.write(bw)
+	}
+	fmt.Fprint(bw, htmlFooter)
+	return bw.Flush()
+}
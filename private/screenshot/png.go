@@ -0,0 +1,90 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+// png.go renders the frame into a PNG image.
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// PNG writes the frame indexed as [x][y] as a PNG image. Characters missing
+// from the embedded font are drawn as empty boxes.
+func PNG(w io.Writer, frame [][]terminalapi.FrameCell) error {
+	sz := size(frame)
+	if sz.X == 0 || sz.Y == 0 {
+		return errors.New("cannot take a screenshot of an empty frame")
+	}
+	img := image.NewRGBA(image.Rectangle{Max: sz})
+	render(frame, &pngSurface{img: img})
+	return png.Encode(w, img)
+}
+
+// pngSurface is a surface that draws into an image.
+// Implements surface.
+type pngSurface struct {
+	img *image.RGBA
+}
+
+// fill implements surface.fill.
+func (ps *pngSurface) fill(ar image.Rectangle, c color.RGBA) {
+	draw.Draw(ps.img, ar, image.NewUniform(c), image.ZP, draw.Src)
+}
+
+// glyph implements surface.glyph.
+func (ps *pngSurface) glyph(r rune, ar image.Rectangle, st *style) {
+	g, ok := font[r]
+	if !ok {
+		ps.tofu(ar, st)
+		return
+	}
+
+	// Full-width characters are centered in their two cells.
+	origin := ar.Min.Add(image.Point{glyphX + (ar.Dx()-CellWidth)/2, glyphY})
+	for y := range g {
+		shear := 0
+		if st.italic {
+			shear = (glyphHeight - 1 - y) / 3
+		}
+		for x, set := range g[y] {
+			if !set {
+				continue
+			}
+			min := origin.Add(image.Point{x*glyphScale + shear, y * glyphScale})
+			px := image.Rectangle{min, min.Add(image.Point{glyphScale, glyphScale})}
+			if st.bold {
+				px.Max.X++
+			}
+			ps.fill(px.Intersect(ar), st.fg)
+		}
+	}
+}
+
+// tofu draws the outline of a box in place of a character that is missing
+// from the font.
+func (ps *pngSurface) tofu(ar image.Rectangle, st *style) {
+	box := image.Rect(ar.Min.X+2, ar.Min.Y+glyphY, ar.Max.X-2, ar.Min.Y+glyphY+7*glyphScale)
+	ps.fill(image.Rect(box.Min.X, box.Min.Y, box.Max.X, box.Min.Y+1), st.fg)
+	ps.fill(image.Rect(box.Min.X, box.Max.Y-1, box.Max.X, box.Max.Y), st.fg)
+	ps.fill(image.Rect(box.Min.X, box.Min.Y, box.Min.X+1, box.Max.Y), st.fg)
+	ps.fill(image.Rect(box.Max.X-1, box.Min.Y, box.Max.X, box.Max.Y), st.fg)
+}
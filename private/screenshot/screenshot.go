@@ -0,0 +1,143 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package screenshot rasterizes the content of a terminal frame into images.
+package screenshot
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+const (
+	// CellWidth is the width of one terminal cell in pixels.
+	CellWidth = 12
+	// CellHeight is the height of one terminal cell in pixels.
+	CellHeight = 24
+)
+
+var (
+	// DefaultFg is the color used for cells with the foreground color set to
+	// cell.ColorDefault.
+	DefaultFg = color.RGBA{204, 204, 204, 255}
+	// DefaultBg is the color used for cells with the background color set to
+	// cell.ColorDefault.
+	DefaultBg = color.RGBA{0, 0, 0, 255}
+)
+
+// style is the resolved appearance of one cell.
+type style struct {
+	fg     color.RGBA
+	bg     color.RGBA
+	bold   bool
+	italic bool
+	link   string
+}
+
+// surface is the image the frame is rendered onto.
+type surface interface {
+	// fill fills the area with the color.
+	fill(ar image.Rectangle, c color.RGBA)
+	// glyph draws a rune that isn't one of the shapes drawn by the renderer
+	// itself into the area of one or two cells.
+	glyph(r rune, ar image.Rectangle, st *style)
+}
+
+// toRGBA converts the terminal color, returns def for cell.ColorDefault.
+func toRGBA(c cell.Color, def color.RGBA) color.RGBA {
+	r, g, b, ok := c.RGB()
+	if !ok {
+		return def
+	}
+	return color.RGBA{uint8(r), uint8(g), uint8(b), 255}
+}
+
+// blend mixes the two colors, frac is the fraction of the color a.
+func blend(a, b color.RGBA, frac float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(frac*float64(x) + (1-frac)*float64(y) + 0.5)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// resolve determines the appearance of a cell with the options.
+func resolve(opts *cell.Options) *style {
+	st := &style{
+		fg:     toRGBA(opts.FgColor, DefaultFg),
+		bg:     toRGBA(opts.BgColor, DefaultBg),
+		bold:   opts.Bold,
+		italic: opts.Italic,
+		link:   opts.Link,
+	}
+	if opts.Inverse {
+		st.fg, st.bg = st.bg, st.fg
+	}
+	if opts.Dim {
+		st.fg = blend(st.fg, st.bg, 0.5)
+	}
+	return st
+}
+
+// size returns the size of the rendered frame in pixels.
+func size(frame [][]terminalapi.FrameCell) image.Point {
+	if len(frame) == 0 {
+		return image.ZP
+	}
+	return image.Point{len(frame) * CellWidth, len(frame[0]) * CellHeight}
+}
+
+// cellArea returns the area of the cell in pixels.
+func cellArea(col, row, width int) image.Rectangle {
+	return image.Rect(col*CellWidth, row*CellHeight, (col+width)*CellWidth, (row+1)*CellHeight)
+}
+
+// render draws the frame indexed as [x][y] onto the surface.
+func render(frame [][]terminalapi.FrameCell, s surface) {
+	s.fill(image.Rectangle{Max: size(frame)}, DefaultBg)
+	rows := size(frame).Y / CellHeight
+	for row := 0; row < rows; row++ {
+		for col := range frame {
+			if st := resolve(&frame[col][row].Opts); st.bg != DefaultBg {
+				s.fill(cellArea(col, row, 1), st.bg)
+			}
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < len(frame); col++ {
+			fc := frame[col][row]
+			st := resolve(&fc.Opts)
+			width := 1
+			if rw := runewidth.RuneWidth(fc.Rune); rw == 2 && col+1 < len(frame) {
+				width = 2
+			}
+			ar := cellArea(col, row, width)
+			if fc.Rune != 0 && fc.Rune != ' ' && !drawShape(s, fc.Rune, ar, st) {
+				s.glyph(fc.Rune, ar, st)
+			}
+			if fc.Opts.Underline {
+				s.fill(image.Rect(ar.Min.X, ar.Min.Y+21, ar.Max.X, ar.Min.Y+23), st.fg)
+			}
+			if fc.Opts.Strikethrough {
+				s.fill(image.Rect(ar.Min.X, ar.Min.Y+11, ar.Max.X, ar.Min.Y+13), st.fg)
+			}
+			// The second cell of a full-width rune has no content of its own.
+			col += width - 1
+		}
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// newFrame returns a frame with the runes on a single row, all with the
+// options.
+func newFrame(runes string, opts ...cell.Option) [][]terminalapi.FrameCell {
+	var frame [][]terminalapi.FrameCell
+	for _, r := range runes {
+		frame = append(frame, []terminalapi.FrameCell{
+			{Rune: r, Opts: *cell.NewOptions(opts...)},
+		})
+	}
+	return frame
+}
+
+func TestFont(t *testing.T) {
+	for r, s := range glyphRows {
+		rows := strings.Fields(s)
+		if len(rows) != glyphHeight && len(rows) != glyphHeight-2 {
+			t.Errorf("glyph %q has %d rows, want %d or %d", r, len(rows), glyphHeight, glyphHeight-2)
+		}
+		for _, row := range rows {
+			if len(row) != glyphWidth || strings.Trim(row, ".#") != "" {
+				t.Errorf("glyph %q has invalid row %q, want %d of '.' or '#'", r, row, glyphWidth)
+			}
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	tests := []struct {
+		desc  string
+		frame [][]terminalapi.FrameCell
+		// want are the colors of the specified pixels.
+		want     map[image.Point]color.RGBA
+		wantSize image.Point
+		wantErr  bool
+	}{
+		{
+			desc:    "fails on an empty frame",
+			wantErr: true,
+		},
+		{
+			desc:     "draws glyphs and the background",
+			frame:    newFrame("A ", cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue)),
+			wantSize: image.Point{2 * CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{0, 0}: blue,
+				// The top of 'A' starts in its second column.
+				{glyphX + glyphScale, glyphY}: red,
+				{glyphX, glyphY}:              blue,
+				{CellWidth + 5, 10}:           blue,
+			},
+		},
+		{
+			desc:     "uses the default colors",
+			frame:    newFrame("I"),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{0, 0}:                        DefaultBg,
+				{glyphX + glyphScale, glyphY}: DefaultFg,
+			},
+		},
+		{
+			desc:     "inverse swaps the colors",
+			frame:    newFrame("I", cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue), cell.Inverse()),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{0, 0}:                        red,
+				{glyphX + glyphScale, glyphY}: blue,
+			},
+		},
+		{
+			desc:     "dim blends the foreground with the background",
+			frame:    newFrame("I", cell.FgColor(cell.ColorRed), cell.Dim()),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{glyphX + glyphScale, glyphY}: {128, 0, 0, 255},
+			},
+		},
+		{
+			desc:     "draws underline and strikethrough",
+			frame:    newFrame(" ", cell.Underline(), cell.Strikethrough()),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{0, 21}: DefaultFg,
+				{0, 11}: DefaultFg,
+				{0, 15}: DefaultBg,
+			},
+		},
+		{
+			desc:     "draws missing characters as boxes",
+			frame:    newFrame("λ"),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{2, glyphY}:     DefaultFg,
+				{2, glyphY + 5}: DefaultFg,
+				{5, glyphY + 5}: DefaultBg,
+			},
+		},
+		{
+			desc: "full-width runes span two cells",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: '世'}},
+				{{Rune: 0}},
+			},
+			wantSize: image.Point{2 * CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{2, glyphY}:                   DefaultFg,
+				{2*CellWidth - 3, glyphY}:     DefaultFg,
+				{CellWidth, glyphY}:           DefaultFg,
+				{CellWidth, glyphY + 5}:       DefaultBg,
+				{2*CellWidth - 1, glyphY}:     DefaultBg,
+				{2*CellWidth - 3, glyphY + 5}: DefaultFg,
+			},
+		},
+		{
+			desc:     "draws blocks",
+			frame:    newFrame("▀▐▒"),
+			wantSize: image.Point{3 * CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{0, 0}:               DefaultFg,
+				{0, CellHeight - 1}:  DefaultBg,
+				{CellWidth, 0}:       DefaultBg,
+				{2*CellWidth - 1, 0}: DefaultFg,
+				{2 * CellWidth, 0}:   {102, 102, 102, 255},
+			},
+		},
+		{
+			desc:     "draws braille",
+			frame:    newFrame("⢁"),
+			wantSize: image.Point{CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				// Dot 1.
+				{2, 2}: DefaultFg,
+				// Dot 8.
+				{7, 20}: DefaultFg,
+				// Dot 4.
+				{7, 2}: DefaultBg,
+			},
+		},
+		{
+			desc:     "joins box drawing characters",
+			frame:    newFrame("┌─┐"),
+			wantSize: image.Point{3 * CellWidth, CellHeight},
+			want: map[image.Point]color.RGBA{
+				{CellWidth / 2, CellHeight / 2}:       DefaultFg,
+				{CellWidth / 2, CellHeight - 1}:       DefaultFg,
+				{CellWidth / 2, 0}:                    DefaultBg,
+				{0, CellHeight / 2}:                   DefaultBg,
+				{CellWidth, CellHeight / 2}:           DefaultFg,
+				{3*CellWidth - 1, CellHeight / 2}:     DefaultBg,
+				{5 * CellWidth / 2, CellHeight / 2}:   DefaultFg,
+				{5 * CellWidth / 2, CellHeight/2 + 4}: DefaultFg,
+				{5 * CellWidth / 2, CellHeight/2 - 4}: DefaultBg,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var b bytes.Buffer
+			err := PNG(&b, tc.frame)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("PNG => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			img, err := png.Decode(&b)
+			if err != nil {
+				t.Fatalf("png.Decode => unexpected error: %v", err)
+			}
+			if got := img.Bounds().Size(); got != tc.wantSize {
+				t.Errorf("PNG => image size %v, want %v", got, tc.wantSize)
+			}
+			got := map[image.Point]color.RGBA{}
+			for p := range tc.want {
+				got[p] = color.RGBAModel.Convert(img.At(p.X, p.Y)).(color.RGBA)
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("PNG => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSVG(t *testing.T) {
+	tests := []struct {
+		desc    string
+		frame   [][]terminalapi.FrameCell
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "fails on an empty frame",
+			wantErr: true,
+		},
+		{
+			desc: "writes text, merged backgrounds and shapes",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: '<', Opts: cell.Options{BgColor: cell.ColorBlue, Bold: true}}},
+				{{Rune: ' ', Opts: cell.Options{BgColor: cell.ColorBlue}}},
+				{{Rune: '▌', Opts: cell.Options{FgColor: cell.ColorRed}}},
+				{{Rune: 'a', Opts: cell.Options{Italic: true, Link: "https://a.b/?c&d"}}},
+			},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="24" viewBox="0 0 48 24">
+<g font-family="monospace" font-size="20" text-anchor="middle">
+<rect x="0" y="0" width="48" height="24" fill="#000000"/>
+<rect x="0" y="0" width="24" height="24" fill="#0000ff"/>
+<text x="6" y="18" fill="#cccccc" font-weight="bold">&lt;</text>
+<rect x="24" y="0" width="6" height="24" fill="#ff0000"/>
+<a href="https://a.b/?c&amp;d"><text x="42" y="18" fill="#cccccc" font-style="italic">a</text></a>
+</g>
+</svg>
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var b bytes.Buffer
+			err := SVG(&b, tc.frame)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("SVG => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := pretty.Compare(tc.want, b.String()); diff != "" {
+				t.Errorf("SVG => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		desc  string
+		frame [][]terminalapi.FrameCell
+		// want is the content of the preformatted block.
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "fails on an empty frame",
+			wantErr: true,
+		},
+		{
+			desc: "writes plain text without spans",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: 'a'}, {Rune: '<'}},
+				{{Rune: 0}, {Rune: '&'}},
+			},
+			want: "a \n&lt;&amp;",
+		},
+		{
+			desc: "merges cells with the same style into spans",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: 'a', Opts: cell.Options{FgColor: cell.ColorRed, Bold: true}}},
+				{{Rune: 'b', Opts: cell.Options{FgColor: cell.ColorRed, Bold: true}}},
+				{{Rune: 'c', Opts: cell.Options{BgColor: cell.ColorBlue, Underline: true, Strikethrough: true}}},
+				{{Rune: 'd', Opts: cell.Options{Italic: true, Blink: true}}},
+				{{Rune: 'e', Opts: cell.Options{Inverse: true}}},
+			},
+			want: `<span style="color: #ff0000; font-weight: bold">ab</span>` +
+				`<span style="background-color: #0000ff; text-decoration: underline line-through">c</span>` +
+				`<span style="font-style: italic" class="blink">d</span>` +
+				`<span style="color: #000000; background-color: #cccccc">e</span>`,
+		},
+		{
+			desc: "writes links",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: 'a', Opts: cell.Options{Link: "https://a.b/?c&d"}}},
+				{{Rune: 'b', Opts: cell.Options{Link: "https://a.b/?c&d"}}},
+				{{Rune: 'c'}},
+			},
+			want: `<a href="https://a.b/?c&amp;d">ab</a>c`,
+		},
+		{
+			desc: "skips the second cell of full-width runes",
+			frame: [][]terminalapi.FrameCell{
+				{{Rune: '世'}},
+				{{Rune: 0}},
+				{{Rune: 'a'}},
+			},
+			want: "世a",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var b bytes.Buffer
+			err := HTML(&b, tc.frame)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("HTML => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := b.String()
+			if !strings.HasPrefix(got, "<!DOCTYPE html>") || !strings.HasSuffix(got, "</html>\n") {
+				t.Errorf("HTML => %q isn't a complete document", got)
+			}
+			start := strings.Index(got, "<pre>") + len("<pre>")
+			end := strings.Index(got, "</pre>")
+			if diff := pretty.Compare(tc.want, got[start:end]); diff != "" {
+				t.Errorf("HTML => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+// shapes.go draws the box drawing, block and braille characters as filled
+// rectangles, so they connect seamlessly across cells.
+
+import (
+	"image"
+)
+
+// Weights of the lines in the box drawing characters.
+const (
+	lineNone = iota
+	lineLight
+	lineHeavy
+	lineDouble
+)
+
+// boxLines are the weights of the lines of the box drawing characters going
+// from the center of the cell up, right, down and left.
+var boxLines = map[rune][4]int{
+	'─': {lineNone, lineLight, lineNone, lineLight},
+	'│': {lineLight, lineNone, lineLight, lineNone},
+	'┌': {lineNone, lineLight, lineLight, lineNone},
+	'┐': {lineNone, lineNone, lineLight, lineLight},
+	'└': {lineLight, lineLight, lineNone, lineNone},
+	'┘': {lineLight, lineNone, lineNone, lineLight},
+	'├': {lineLight, lineLight, lineLight, lineNone},
+	'┤': {lineLight, lineNone, lineLight, lineLight},
+	'┬': {lineNone, lineLight, lineLight, lineLight},
+	'┴': {lineLight, lineLight, lineNone, lineLight},
+	'┼': {lineLight, lineLight, lineLight, lineLight},
+	'╭': {lineNone, lineLight, lineLight, lineNone},
+	'╮': {lineNone, lineNone, lineLight, lineLight},
+	'╯': {lineLight, lineNone, lineNone, lineLight},
+	'╰': {lineLight, lineLight, lineNone, lineNone},
+	'╴': {lineNone, lineNone, lineNone, lineLight},
+	'╵': {lineLight, lineNone, lineNone, lineNone},
+	'╶': {lineNone, lineLight, lineNone, lineNone},
+	'╷': {lineNone, lineNone, lineLight, lineNone},
+
+	'━': {lineNone, lineHeavy, lineNone, lineHeavy},
+	'┃': {lineHeavy, lineNone, lineHeavy, lineNone},
+	'┏': {lineNone, lineHeavy, lineHeavy, lineNone},
+	'┓': {lineNone, lineNone, lineHeavy, lineHeavy},
+	'┗': {lineHeavy, lineHeavy, lineNone, lineNone},
+	'┛': {lineHeavy, lineNone, lineNone, lineHeavy},
+	'┣': {lineHeavy, lineHeavy, lineHeavy, lineNone},
+	'┫': {lineHeavy, lineNone, lineHeavy, lineHeavy},
+	'┳': {lineNone, lineHeavy, lineHeavy, lineHeavy},
+	'┻': {lineHeavy, lineHeavy, lineNone, lineHeavy},
+	'╋': {lineHeavy, lineHeavy, lineHeavy, lineHeavy},
+
+	'═': {lineNone, lineDouble, lineNone, lineDouble},
+	'║': {lineDouble, lineNone, lineDouble, lineNone},
+	'╔': {lineNone, lineDouble, lineDouble, lineNone},
+	'╗': {lineNone, lineNone, lineDouble, lineDouble},
+	'╚': {lineDouble, lineDouble, lineNone, lineNone},
+	'╝': {lineDouble, lineNone, lineNone, lineDouble},
+	'╠': {lineDouble, lineDouble, lineDouble, lineNone},
+	'╣': {lineDouble, lineNone, lineDouble, lineDouble},
+	'╦': {lineNone, lineDouble, lineDouble, lineDouble},
+	'╩': {lineDouble, lineDouble, lineNone, lineDouble},
+	'╬': {lineDouble, lineDouble, lineDouble, lineDouble},
+}
+
+// strokes returns the offsets from the center of the cell of the strokes
+// that form a line of the weight. Each stroke is returned as the half-open
+// range [from, to).
+func strokes(weight int) [][2]int {
+	switch weight {
+	case lineLight:
+		return [][2]int{{-1, 1}}
+	case lineHeavy:
+		return [][2]int{{-2, 2}}
+	case lineDouble:
+		return [][2]int{{-3, -1}, {1, 3}}
+	default:
+		return nil
+	}
+}
+
+// extent returns how far from the center of the cell the strokes of a line
+// of the weight reach.
+func extent(weight int) int {
+	max := 0
+	for _, sr := range strokes(weight) {
+		if sr[1] > max {
+			max = sr[1]
+		}
+	}
+	return max
+}
+
+// drawBox draws a box drawing character.
+func drawBox(s surface, lines [4]int, ar image.Rectangle, st *style) {
+	c := image.Point{ar.Min.X + CellWidth/2, ar.Min.Y + CellHeight/2}
+	// Vertical lines reach into the horizontal ones and vice versa so that
+	// they join without gaps.
+	vExt := extent(lines[1])
+	if e := extent(lines[3]); e > vExt {
+		vExt = e
+	}
+	hExt := extent(lines[0])
+	if e := extent(lines[2]); e > hExt {
+		hExt = e
+	}
+	for dir, weight := range lines {
+		for _, sr := range strokes(weight) {
+			var r image.Rectangle
+			switch dir {
+			case 0: // Up.
+				r = image.Rect(c.X+sr[0], ar.Min.Y, c.X+sr[1], c.Y+vExt)
+			case 1: // Right.
+				r = image.Rect(c.X-hExt, c.Y+sr[0], ar.Max.X, c.Y+sr[1])
+			case 2: // Down.
+				r = image.Rect(c.X+sr[0], c.Y-vExt, c.X+sr[1], ar.Max.Y)
+			case 3: // Left.
+				r = image.Rect(ar.Min.X, c.Y+sr[0], c.X+hExt, c.Y+sr[1])
+			}
+			s.fill(r, st.fg)
+		}
+	}
+}
+
+// quadrants are the quadrants of the cell filled by the block characters
+// U+2596 to U+259F as bits, upper left, upper right, lower left, lower
+// right.
+var quadrants = [...]int{
+	0x4,             // ▖
+	0x8,             // ▗
+	0x1,             // ▘
+	0x1 | 0x4 | 0x8, // ▙
+	0x1 | 0x8,       // ▚
+	0x1 | 0x2 | 0x4, // ▛
+	0x1 | 0x2 | 0x8, // ▜
+	0x2,             // ▝
+	0x2 | 0x4,       // ▞
+	0x2 | 0x4 | 0x8, // ▟
+}
+
+// drawBlock draws a block element from the range U+2580 to U+259F.
+func drawBlock(s surface, r rune, ar image.Rectangle, st *style) {
+	eighthH := func(n int) int { return n * CellHeight / 8 }
+	eighthW := func(n int) int { return n * CellWidth / 8 }
+	switch {
+	case r == '▀':
+		s.fill(image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+CellHeight/2), st.fg)
+	case r >= '▁' && r <= '█':
+		s.fill(image.Rect(ar.Min.X, ar.Max.Y-eighthH(int(r-'▁')+1), ar.Max.X, ar.Max.Y), st.fg)
+	case r >= '▉' && r <= '▏':
+		s.fill(image.Rect(ar.Min.X, ar.Min.Y, ar.Min.X+eighthW(int('▏'-r)+1), ar.Max.Y), st.fg)
+	case r == '▐':
+		s.fill(image.Rect(ar.Min.X+CellWidth/2, ar.Min.Y, ar.Max.X, ar.Max.Y), st.fg)
+	case r >= '░' && r <= '▓':
+		s.fill(ar, blend(st.fg, st.bg, float64(r-'░'+1)/4))
+	case r == '▔':
+		s.fill(image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+eighthH(1)), st.fg)
+	case r == '▕':
+		s.fill(image.Rect(ar.Max.X-eighthW(1), ar.Min.Y, ar.Max.X, ar.Max.Y), st.fg)
+	default:
+		q := quadrants[r-'▖']
+		hw, hh := CellWidth/2, CellHeight/2
+		for i := 0; i < 4; i++ {
+			if q&(1<<uint(i)) == 0 {
+				continue
+			}
+			min := ar.Min.Add(image.Point{i % 2 * hw, i / 2 * hh})
+			s.fill(image.Rectangle{min, min.Add(image.Point{hw, hh})}, st.fg)
+		}
+	}
+}
+
+// brailleDots are the positions of the eight braille dots as column and row
+// in the order of the bits in the braille pattern.
+var brailleDots = [8]image.Point{
+	{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}, {0, 3}, {1, 3},
+}
+
+// drawBraille draws a braille pattern from the range U+2800 to U+28FF.
+func drawBraille(s surface, r rune, ar image.Rectangle, st *style) {
+	bits := int(r - '⠀')
+	for i, d := range brailleDots {
+		if bits&(1<<uint(i)) == 0 {
+			continue
+		}
+		min := ar.Min.Add(image.Point{2 + d.X*5, 2 + d.Y*6})
+		s.fill(image.Rectangle{min, min.Add(image.Point{3, 3})}, st.fg)
+	}
+}
+
+// drawShape draws the rune if it is one of the shapes drawn as rectangles.
+// Returns false if the rune isn't a shape.
+func drawShape(s surface, r rune, ar image.Rectangle, st *style) bool {
+	switch {
+	case r >= '▀' && r <= '▟':
+		drawBlock(s, r, ar, st)
+	case r >= '⠀' && r <= '⣿':
+		drawBraille(s, r, ar, st)
+	default:
+		lines, ok := boxLines[r]
+		if !ok {
+			return false
+		}
+		drawBox(s, lines, ar, st)
+	}
+	return true
+}
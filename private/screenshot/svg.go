@@ -0,0 +1,122 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package screenshot
+
+// svg.go renders the frame into an SVG image.
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+const (
+	// svgFontSize is the size of the font used for text in SVG images, the
+	// usual monospace fonts have an advance of 0.6em, i.e. CellWidth.
+	svgFontSize = 20
+	// svgBaseline is the position of the baseline of the text within the
+	// cell.
+	svgBaseline = 18
+)
+
+// SVG writes the frame indexed as [x][y] as an SVG image. The text is
+// rendered by the viewer using its monospace font, the box drawing, block
+// and braille characters are drawn as shapes.
+func SVG(w io.Writer, frame [][]terminalapi.FrameCell) error {
+	sz := size(frame)
+	if sz.X == 0 || sz.Y == 0 {
+		return errors.New("cannot take a screenshot of an empty frame")
+	}
+
+	ss := &svgSurface{w: bufio.NewWriter(w)}
+	fmt.Fprintf(ss.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", sz.X, sz.Y, sz.X, sz.Y)
+	fmt.Fprintf(ss.w, `<g font-family="monospace" font-size="%d" text-anchor="middle">`+"\n", svgFontSize)
+	render(frame, ss)
+	ss.flushRect()
+	fmt.Fprint(ss.w, "</g>\n</svg>\n")
+	return ss.w.Flush()
+}
+
+// svgSurface is a surface that writes SVG elements.
+// Implements surface.
+type svgSurface struct {
+	w *bufio.Writer
+
+	// rect is a rectangle that wasn't written yet, since it might be
+	// extended by the next fill.
+	rect      image.Rectangle
+	rectColor color.RGBA
+}
+
+// hexColor formats the color for SVG.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// flushRect writes the pending rectangle, if any.
+func (ss *svgSurface) flushRect() {
+	if ss.rect.Empty() {
+		return
+	}
+	r := ss.rect
+	fmt.Fprintf(ss.w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.Min.X, r.Min.Y, r.Dx(), r.Dy(), hexColor(ss.rectColor))
+	ss.rect = image.ZR
+}
+
+// fill implements surface.fill.
+// Consecutive fills of adjacent areas on the same row with the same color are
+// merged into one rectangle.
+func (ss *svgSurface) fill(ar image.Rectangle, c color.RGBA) {
+	if ar.Empty() {
+		return
+	}
+	if !ss.rect.Empty() && c == ss.rectColor && ar.Min.X == ss.rect.Max.X && ar.Min.Y == ss.rect.Min.Y && ar.Max.Y == ss.rect.Max.Y {
+		ss.rect.Max.X = ar.Max.X
+		return
+	}
+	ss.flushRect()
+	ss.rect = ar
+	ss.rectColor = c
+}
+
+// glyph implements surface.glyph.
+func (ss *svgSurface) glyph(r rune, ar image.Rectangle, st *style) {
+	ss.flushRect()
+	if st.link != "" {
+		fmt.Fprint(ss.w, `<a href="`)
+		xml.EscapeText(ss.w, []byte(st.link))
+		fmt.Fprint(ss.w, `">`)
+	}
+	fmt.Fprintf(ss.w, `<text x="%d" y="%d" fill="%s"`, ar.Min.X+ar.Dx()/2, ar.Min.Y+svgBaseline, hexColor(st.fg))
+	if st.bold {
+		fmt.Fprint(ss.w, ` font-weight="bold"`)
+	}
+	if st.italic {
+		fmt.Fprint(ss.w, ` font-style="italic"`)
+	}
+	fmt.Fprint(ss.w, `>`)
+	xml.EscapeText(ss.w, []byte(string(r)))
+	fmt.Fprint(ss.w, `</text>`)
+	if st.link != "" {
+		fmt.Fprint(ss.w, `</a>`)
+	}
+	fmt.Fprint(ss.w, "\n")
+}
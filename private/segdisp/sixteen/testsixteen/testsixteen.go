@@ -29,6 +29,13 @@ func MustSetCharacter(d *sixteen.Display, c rune) {
 	}
 }
 
+// MustSetSegment sets the segment on the display or panics.
+func MustSetSegment(d *sixteen.Display, s sixteen.Segment) {
+	if err := d.SetSegment(s); err != nil {
+		panic(fmt.Errorf("sixteen.Display.SetSegment => unexpected error: %v", err))
+	}
+}
+
 // MustDraw draws the display onto the canvas or panics.
 func MustDraw(d *sixteen.Display, cvs *canvas.Canvas, opts ...sixteen.Option) {
 	if err := d.Draw(cvs, opts...); err != nil {
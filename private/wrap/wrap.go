@@ -90,18 +90,73 @@ func ValidCells(cells []*buffer.Cell) error {
 	return ValidText(b.String())
 }
 
+// Option is used to provide options to Cells.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	indent      int
+	alignIndent bool
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// HangingIndent indents all the continuation lines created by wrapping by the
+// specified number of cells. Lines started by a newline character aren't
+// indented. The value must be zero or positive, defaults to zero.
+//
+// The indentation is reduced if needed so that at least two cells of each
+// continuation line remain available for the text.
+func HangingIndent(cells int) Option {
+	return option(func(opts *options) {
+		opts.indent = cells
+	})
+}
+
+// HangingIndentAligned aligns the continuation lines created by wrapping with
+// the first non-space rune of the original line. When combined with
+// HangingIndent, the continuation lines are indented further by the specified
+// number of cells.
+func HangingIndentAligned() Option {
+	return option(func(opts *options) {
+		opts.alignIndent = true
+	})
+}
+
 // Cells returns the cells wrapped into individual lines according to the
 // specified width and wrapping mode.
 //
+// Continuation lines indented by the HangingIndent or HangingIndentAligned
+// options start with cells containing the space character. If the text that
+// continues on such line has the same cell options as the text before the
+// wrap, the indentation cells get these cell options too, so that styling like
+// background colors or hyperlinks isn't interrupted by the indentation.
+//
 // This function consumes any cells that contain newline characters and uses
 // them to start new lines.
 //
 // If the mode is AtWords, this function also drops cells with leading space
 // character before a word at which the wrap occurs.
-func Cells(cells []*buffer.Cell, width int, m Mode) ([][]*buffer.Cell, error) {
+func Cells(cells []*buffer.Cell, width int, m Mode, opts ...Option) ([][]*buffer.Cell, error) {
 	if err := ValidCells(cells); err != nil {
 		return nil, err
 	}
+	opt := &options{}
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if opt.indent < 0 {
+		return nil, fmt.Errorf("invalid hanging indent %d, must be a zero or a positive number", opt.indent)
+	}
 	switch m {
 	case Never:
 	case AtRunes:
@@ -113,9 +168,10 @@ func Cells(cells []*buffer.Cell, width int, m Mode) ([][]*buffer.Cell, error) {
 		return nil, nil
 	}
 
-	cs := newCellScanner(cells, width, m)
+	cs := newCellScanner(cells, width, m, opt)
 	for state := scanCellRunes; state != nil; state = state(cs) {
 	}
+	cs.styleIndents()
 	return cs.lines, nil
 }
 
@@ -161,14 +217,116 @@ type cellScanner struct {
 
 	// line is the current line.
 	line []*buffer.Cell
+
+	// opts are the provided options.
+	opts *options
+
+	// lineIndent is the number of cells the current line was indented by.
+	lineIndent int
+
+	// contIndent is the number of cells the continuation lines of the
+	// current line will be indented by.
+	contIndent int
+
+	// indented are the indexes of the indented continuation lines in lines
+	// mapped to the number of cells they are indented by.
+	indented map[int]int
+
+	// clusterLen maps indexes of cells that start a grapheme cluster to the
+	// number of cells in the cluster. Cells that continue a cluster map to
+	// zero. Lines are never wrapped inside of a cluster.
+	clusterLen []int
 }
 
 // newCellScanner returns a scanner of the provided cells.
-func newCellScanner(cells []*buffer.Cell, width int, m Mode) *cellScanner {
-	return &cellScanner{
-		cells: cells,
-		width: width,
-		mode:  m,
+func newCellScanner(cells []*buffer.Cell, width int, m Mode, opts *options) *cellScanner {
+	cs := &cellScanner{
+		cells:      cells,
+		width:      width,
+		mode:       m,
+		opts:       opts,
+		clusterLen: make([]int, len(cells)),
+	}
+
+	runes := make([]rune, len(cells))
+	for i, c := range cells {
+		runes[i] = c.Rune
+	}
+	idx := 0
+	for _, c := range runewidth.Clusters(runes) {
+		cs.clusterLen[idx] = len(c)
+		idx += len(c)
+	}
+	cs.contIndent = cs.continuationIndent()
+	return cs
+}
+
+// cellWidth returns the number of cells on the canvas taken by the cell at
+// the specified index. Cells that continue a grapheme cluster don't take any,
+// the width of the cluster is accounted for on its first cell.
+func (cs *cellScanner) cellWidth(idx int) int {
+	if cs.clusterLen[idx] == 0 {
+		return 0
+	}
+	return runewidth.RuneWidth(cs.cells[idx].Rune)
+}
+
+// continuationIndent returns the number of cells the continuation lines of
+// the line starting at the next cell should be indented by.
+func (cs *cellScanner) continuationIndent() int {
+	indent := cs.opts.indent
+	if cs.opts.alignIndent {
+		for i := cs.nextIdx; i < len(cs.cells) && cs.cells[i].Rune == ' '; i++ {
+			indent++
+		}
+	}
+
+	// Leave space for at least one full-width rune on the line.
+	if max := cs.width - 2; indent > max {
+		indent = max
+	}
+	if indent < 0 {
+		return 0
+	}
+	return indent
+}
+
+// newContinuationLine finishes the current line and starts a new one that
+// continues it, indenting it as needed.
+func (cs *cellScanner) newContinuationLine() {
+	cs.lines = append(cs.lines, cs.line)
+	cs.line = nil
+	for i := 0; i < cs.contIndent; i++ {
+		cs.line = append(cs.line, buffer.NewCell(' '))
+	}
+	if cs.contIndent > 0 {
+		if cs.indented == nil {
+			cs.indented = map[int]int{}
+		}
+		cs.indented[len(cs.lines)] = cs.contIndent
+	}
+	cs.posX = cs.contIndent
+	cs.lineIndent = cs.contIndent
+}
+
+// styleIndents sets the cell options of the indentation cells on continuation
+// lines where the text before and after the wrap has the same cell options.
+func (cs *cellScanner) styleIndents() {
+	for idx, indent := range cs.indented {
+		if idx == 0 || idx >= len(cs.lines) {
+			continue
+		}
+		prev, line := cs.lines[idx-1], cs.lines[idx]
+		if len(prev) == 0 || len(line) <= indent {
+			continue
+		}
+		last, first := prev[len(prev)-1], line[indent]
+		if *last.Opts != *first.Opts {
+			continue
+		}
+		for i := 0; i < indent; i++ {
+			line[i] = buffer.NewCell(' ', first.Opts)
+		}
 	}
 }
 
@@ -208,11 +366,11 @@ func (cs *cellScanner) wordCells() []*buffer.Cell {
 // wordWidth returns the width of the current word in cells when printed on the
 // terminal.
 func (cs *cellScanner) wordWidth() int {
-	var b strings.Builder
-	for _, wc := range cs.wordCells() {
-		b.WriteRune(wc.Rune)
+	var width int
+	for i := cs.wordStartIdx; i < cs.wordEndIdx; i++ {
+		width += cs.cellWidth(i)
 	}
-	return runewidth.StringWidth(b.String())
+	return width
 }
 
 // isWordStart determines if the scanner is at the beginning of a word.
@@ -249,7 +407,8 @@ func scanCellRunes(cs *cellScanner) cellScannerState {
 			return newLineForLineBreak
 		}
 
-		if cs.mode == Never {
+		if cs.mode == Never || cs.clusterLen[cs.nextIdx-1] == 0 {
+			// Cells that continue a grapheme cluster stay with it.
 			return runeToCurrentLine
 		}
 
@@ -261,7 +420,7 @@ func scanCellRunes(cs *cellScanner) cellScannerState {
 			return markWordStart
 		}
 
-		if runeWrapNeeded(r, cs.posX, cs.width) {
+		if wrapNeeded(cs.cellWidth(cs.nextIdx-1), cs.posX, cs.width) {
 			return newLineForAtRunes
 		}
 
@@ -273,7 +432,7 @@ func scanCellRunes(cs *cellScanner) cellScannerState {
 func runeToCurrentLine(cs *cellScanner) cellScannerState {
 	cell := cs.peekPrev()
 	// Move horizontally within the line for each scanned cell.
-	cs.posX += runewidth.RuneWidth(cell.Rune)
+	cs.posX += cs.cellWidth(cs.nextIdx - 1)
 
 	// Copy the cell into the current line.
 	cs.line = append(cs.line, cell)
@@ -285,6 +444,8 @@ func newLineForLineBreak(cs *cellScanner) cellScannerState {
 	cs.lines = append(cs.lines, cs.line)
 	cs.posX = 0
 	cs.line = nil
+	cs.lineIndent = 0
+	cs.contIndent = cs.continuationIndent()
 	return scanCellRunes
 }
 
@@ -292,9 +453,9 @@ func newLineForLineBreak(cs *cellScanner) cellScannerState {
 func newLineForAtRunes(cs *cellScanner) cellScannerState {
 	// The character on which we wrapped will be printed and is the start of
 	// new line.
-	cs.lines = append(cs.lines, cs.line)
-	cs.posX = runewidth.RuneWidth(cs.peekPrev().Rune)
-	cs.line = []*buffer.Cell{cs.peekPrev()}
+	cs.newContinuationLine()
+	cs.posX += cs.cellWidth(cs.nextIdx - 1)
+	cs.line = append(cs.line, cs.peekPrev())
 	return scanCellRunes
 }
 
@@ -347,10 +508,8 @@ func wordToCurrentLine(cs *cellScanner) cellScannerState {
 // wrapWord wraps the word onto the next line or lines.
 func wrapWord(cs *cellScanner) cellScannerState {
 	// Edge-case - the word starts the line and immediately doesn't fit.
-	if cs.posX > 0 {
-		cs.lines = append(cs.lines, cs.line)
-		cs.posX = 0
-		cs.line = nil
+	if len(cs.line) > cs.lineIndent {
+		cs.newContinuationLine()
 	}
 
 	for i, wc := range cs.wordCells() {
@@ -359,19 +518,25 @@ func wrapWord(cs *cellScanner) cellScannerState {
 			continue
 		}
 
-		if !runeWrapNeeded(wc.Rune, cs.posX, cs.width) {
-			cs.posX += runewidth.RuneWidth(wc.Rune)
+		cw := cs.cellWidth(cs.wordStartIdx + i)
+		if cs.clusterLen[cs.wordStartIdx+i] == 0 || !wrapNeeded(cw, cs.posX, cs.width) {
+			cs.posX += cw
 			cs.line = append(cs.line, wc)
 			continue
 		}
 
 		// Replace the last placed rune with a dash indicating we wrapped the
-		// word. Only do this for half-width runes.
+		// word. Only do this for half-width runes that aren't a part of a
+		// longer grapheme cluster.
 		lastIdx := len(cs.line) - 1
 		last := cs.line[lastIdx]
 		lastRW := runewidth.RuneWidth(last.Rune)
-		if cs.width > 1 && lastRW == 1 {
+		prevIdx := cs.wordStartIdx + i - 1
+		if cs.width > 1 && lastRW == 1 && (prevIdx < 0 || cs.clusterLen[prevIdx] == 1) {
 			cs.line[lastIdx] = buffer.NewCell('-', last.Opts)
+			// The dash ends the line even if the cluster that didn't fit was
+			// wider than the rune it replaced.
+			cs.posX = cs.width
 			// Reset the scanner's position back to start scanning at the first
 			// rune of this word that wasn't placed.
 			cs.nextIdx = cs.wordStartIdx + i - 1
@@ -401,9 +566,9 @@ func isWordCell(c *buffer.Cell) bool {
 	return false
 }
 
-// runeWrapNeeded returns true if wrapping is needed for the rune at the horizontal
-// position on the canvas that has the specified width.
-func runeWrapNeeded(r rune, posX, width int) bool {
-	rw := runewidth.RuneWidth(r)
-	return posX > width-rw
+// wrapNeeded returns true if wrapping is needed for a grapheme cluster that
+// takes cw cells at the horizontal position on the canvas that has the
+// specified width.
+func wrapNeeded(cw, posX, width int) bool {
+	return posX > width-cw
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/runewidth"
 )
 
 func TestValidTextAndCells(t *testing.T) {
@@ -132,6 +133,7 @@ func TestCells(t *testing.T) {
 		// width is the width of the canvas.
 		width   int
 		mode    Mode
+		opts    []Option
 		want    [][]*buffer.Cell
 		wantErr bool
 	}{
@@ -153,6 +155,13 @@ func TestCells(t *testing.T) {
 			mode:    Mode(-1),
 			wantErr: true,
 		},
+		{
+			desc:    "fails with negative hanging indent",
+			cells:   buffer.NewCells("hello"),
+			width:   1,
+			opts:    []Option{HangingIndent(-1)},
+			wantErr: true,
+		},
 		{
 			desc:  "zero canvas width",
 			cells: buffer.NewCells("hello"),
@@ -660,12 +669,193 @@ func TestCells(t *testing.T) {
 				buffer.NewCells("bc", cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue)),
 			},
 		},
+		{
+			desc:  "hanging indent at runes",
+			cells: buffer.NewCells("abcdefgh\nijklm"),
+			width: 4,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndent(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abcd"),
+				buffer.NewCells("  ef"),
+				buffer.NewCells("  gh"),
+				buffer.NewCells("ijkl"),
+				buffer.NewCells("  m"),
+			},
+		},
+		{
+			desc:  "hanging indent at words",
+			cells: buffer.NewCells("aaa bbb ccc ddd"),
+			width: 8,
+			mode:  AtWords,
+			opts:  []Option{HangingIndent(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("aaa bbb"),
+				buffer.NewCells("  ccc"),
+				buffer.NewCells("  ddd"),
+			},
+		},
+		{
+			desc:  "hanging indent at words with a word longer than the remaining width",
+			cells: buffer.NewCells("a bcdef"),
+			width: 5,
+			mode:  AtWords,
+			opts:  []Option{HangingIndent(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a"),
+				buffer.NewCells("  bc-"),
+				buffer.NewCells("  def"),
+			},
+		},
+		{
+			desc:  "hanging indent is reduced on narrow canvas",
+			cells: buffer.NewCells("abcdef"),
+			width: 3,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndent(5)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abc"),
+				buffer.NewCells(" de"),
+				buffer.NewCells(" f"),
+			},
+		},
+		{
+			desc:  "hanging indent aligned to the first non-space rune",
+			cells: buffer.NewCells("  abcdef\nghijkl"),
+			width: 5,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndentAligned()},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("  abc"),
+				buffer.NewCells("  def"),
+				buffer.NewCells("ghijk"),
+				buffer.NewCells("l"),
+			},
+		},
+		{
+			desc:  "hanging indent aligned and indented further",
+			cells: buffer.NewCells(" abcdef"),
+			width: 5,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndentAligned(), HangingIndent(1)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells(" abcd"),
+				buffer.NewCells("  ef"),
+			},
+		},
+		{
+			desc: "hanging indent continues the style of the wrapped text",
+			cells: append(
+				buffer.NewCells("ab"),
+				buffer.NewCells("cdef", &cell.Options{BgColor: cell.ColorRed, Link: "https://example.com"})...,
+			),
+			width: 4,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndent(2)},
+			want: [][]*buffer.Cell{
+				append(
+					buffer.NewCells("ab"),
+					buffer.NewCells("cd", &cell.Options{BgColor: cell.ColorRed, Link: "https://example.com"})...,
+				),
+				buffer.NewCells("  ef", &cell.Options{BgColor: cell.ColorRed, Link: "https://example.com"}),
+			},
+		},
+		{
+			desc: "hanging indent isn't styled when the style changes at the wrap",
+			cells: append(
+				buffer.NewCells("abcd", cell.BgColor(cell.ColorRed)),
+				buffer.NewCells("ef", cell.BgColor(cell.ColorBlue))...,
+			),
+			width: 4,
+			mode:  AtRunes,
+			opts:  []Option{HangingIndent(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abcd", cell.BgColor(cell.ColorRed)),
+				append(
+					buffer.NewCells("  "),
+					buffer.NewCells("ef", cell.BgColor(cell.ColorBlue))...,
+				),
+			},
+		},
+		{
+			desc:  "hanging indent continues the style of wrapped words",
+			cells: buffer.NewCells("aaa bbb", cell.FgColor(cell.ColorGreen)),
+			width: 4,
+			mode:  AtWords,
+			opts:  []Option{HangingIndent(1)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("aaa", cell.FgColor(cell.ColorGreen)),
+				buffer.NewCells(" bbb", cell.FgColor(cell.ColorGreen)),
+			},
+		},
+		{
+			desc:  "hanging indent doesn't apply when not wrapping",
+			cells: buffer.NewCells("abcdef"),
+			width: 3,
+			mode:  Never,
+			opts:  []Option{HangingIndent(1)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abcdef"),
+			},
+		},
+		{
+			desc:  "wraps at rune boundaries, doesn't split emoji with modifiers",
+			cells: buffer.NewCells("ab👍🏽cd"),
+			width: 3,
+			mode:  AtRunes,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("ab"),
+				buffer.NewCells("👍🏽c"),
+				buffer.NewCells("d"),
+			},
+		},
+		{
+			desc:  "wraps at rune boundaries, doesn't split zero width joiner sequences",
+			cells: buffer.NewCells("a👨‍👩‍👧b"),
+			width: 3,
+			mode:  AtRunes,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a👨‍👩‍👧"),
+				buffer.NewCells("b"),
+			},
+		},
+		{
+			desc:  "wraps at rune boundaries, keeps combining characters with their base",
+			cells: buffer.NewCells("cafés"),
+			width: 4,
+			mode:  AtRunes,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("café"),
+				buffer.NewCells("s"),
+			},
+		},
+		{
+			desc:  "wraps at word boundaries, measures words by grapheme clusters",
+			cells: buffer.NewCells("a 👍🏽👍🏽 b"),
+			width: 6,
+			mode:  AtWords,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a 👍🏽👍🏽"),
+				buffer.NewCells("b"),
+			},
+		},
+		{
+			desc:  "wraps long words, doesn't split emoji with modifiers",
+			cells: buffer.NewCells("ab👍🏽cd"),
+			width: 3,
+			mode:  AtWords,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a-"),
+				buffer.NewCells("b👍🏽"),
+				buffer.NewCells("cd"),
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			t.Logf(fmt.Sprintf("Mode: %v", tc.mode))
-			got, err := Cells(tc.cells, tc.width, tc.mode)
+			got, err := Cells(tc.cells, tc.width, tc.mode, tc.opts...)
 			if (err != nil) != tc.wantErr {
 				t.Errorf("Cells => unexpected error %v, wantErr %v", err, tc.wantErr)
 			}
@@ -680,7 +870,7 @@ func TestCells(t *testing.T) {
 
 }
 
-func TestRuneWrapNeeded(t *testing.T) {
+func TestWrapNeeded(t *testing.T) {
 	tests := []struct {
 		desc  string
 		r     rune
@@ -734,9 +924,9 @@ func TestRuneWrapNeeded(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			got := runeWrapNeeded(tc.r, tc.posX, tc.width)
+			got := wrapNeeded(runewidth.RuneWidth(tc.r), tc.posX, tc.width)
 			if got != tc.want {
-				t.Errorf("runeWrapNeeded => got %v, want %v", got, tc.want)
+				t.Errorf("wrapNeeded => got %v, want %v", got, tc.want)
 			}
 		})
 	}
@@ -0,0 +1,91 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termdash
+
+// screenshot.go contains code that takes screenshots of the terminal.
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mum4k/termdash/private/screenshot"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// ScreenshotFormat is the image format of a screenshot.
+type ScreenshotFormat int
+
+// String implements fmt.Stringer()
+func (sf ScreenshotFormat) String() string {
+	if n, ok := screenshotFormatNames[sf]; ok {
+		return n
+	}
+	return "ScreenshotFormatUnknown"
+}
+
+// screenshotFormatNames maps ScreenshotFormat values to human readable names.
+var screenshotFormatNames = map[ScreenshotFormat]string{
+	ScreenshotPNG:  "ScreenshotPNG",
+	ScreenshotSVG:  "ScreenshotSVG",
+	ScreenshotHTML: "ScreenshotHTML",
+}
+
+// Supported screenshot formats.
+const (
+	// ScreenshotPNG is a PNG image with the text drawn using an embedded
+	// monospace bitmap font. Characters missing from the font are drawn as
+	// empty boxes.
+	ScreenshotPNG ScreenshotFormat = iota
+
+	// ScreenshotSVG is an SVG image with the text rendered by the viewer
+	// using its monospace font. Hyperlinks set with cell.Link remain
+	// clickable.
+	ScreenshotSVG
+
+	// ScreenshotHTML is a standalone HTML document with the text in a
+	// preformatted block and the colors and attributes set on styled spans.
+	// Useful for archiving or emailing snapshots of the dashboard.
+	ScreenshotHTML
+)
+
+// Screenshot rasterizes the content last flushed to the terminal, including
+// the colors and the text attributes, and writes it as an image in the
+// specified format. Every terminal cell is drawn as 12x24 pixels in the image
+// formats.
+//
+// The terminal must implement terminalapi.FrameReader, e.g. the tcell
+// terminal created with the tcell.FrameCapture option.
+// Safe to call while termdash is running.
+func Screenshot(t terminalapi.Terminal, w io.Writer, f ScreenshotFormat) error {
+	fr, ok := t.(terminalapi.FrameReader)
+	if !ok {
+		return fmt.Errorf("the terminal %T doesn't support reading the frame, it must implement terminalapi.FrameReader", t)
+	}
+	frame, err := fr.Frame()
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case ScreenshotPNG:
+		return screenshot.PNG(w, frame)
+	case ScreenshotSVG:
+		return screenshot.SVG(w, frame)
+	case ScreenshotHTML:
+		return screenshot.HTML(w, frame)
+	default:
+		return fmt.Errorf("unsupported screenshot format %v", f)
+	}
+}
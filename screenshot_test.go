@@ -0,0 +1,117 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termdash
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// noFrameTerminal is a terminal that doesn't implement
+// terminalapi.FrameReader.
+type noFrameTerminal struct {
+	terminalapi.Terminal
+}
+
+func TestScreenshot(t *testing.T) {
+	tests := []struct {
+		desc   string
+		format ScreenshotFormat
+		// noFrame hides the FrameReader implementation of the terminal.
+		noFrame bool
+		// check verifies the written image.
+		check   func(t *testing.T, b *bytes.Buffer)
+		wantErr bool
+	}{
+		{
+			desc:    "fails when the terminal cannot read the frame",
+			format:  ScreenshotPNG,
+			noFrame: true,
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unsupported format",
+			format:  ScreenshotFormat(-1),
+			wantErr: true,
+		},
+		{
+			desc:   "writes PNG",
+			format: ScreenshotPNG,
+			check: func(t *testing.T, b *bytes.Buffer) {
+				img, err := png.Decode(b)
+				if err != nil {
+					t.Fatalf("png.Decode => unexpected error: %v", err)
+				}
+				if got, want := img.Bounds().Size(), (image.Point{3 * 12, 2 * 24}); got != want {
+					t.Errorf("Screenshot => image size %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			desc:   "writes SVG",
+			format: ScreenshotSVG,
+			check: func(t *testing.T, b *bytes.Buffer) {
+				got := b.String()
+				for _, want := range []string{`width="36" height="48"`, `fill="#ff0000">a</text>`} {
+					if !strings.Contains(got, want) {
+						t.Errorf("Screenshot => %q doesn't contain %q", got, want)
+					}
+				}
+			},
+		},
+		{
+			desc:   "writes HTML",
+			format: ScreenshotHTML,
+			check: func(t *testing.T, b *bytes.Buffer) {
+				if got, want := b.String(), `<span style="color: #ff0000">a</span>`; !strings.Contains(got, want) {
+					t.Errorf("Screenshot => %q doesn't contain %q", got, want)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			ft, err := faketerm.New(image.Point{3, 2})
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := ft.SetCell(image.Point{1, 1}, 'a', cell.FgColor(cell.ColorRed)); err != nil {
+				t.Fatalf("SetCell => unexpected error: %v", err)
+			}
+			var term terminalapi.Terminal = ft
+			if tc.noFrame {
+				term = &noFrameTerminal{ft}
+			}
+
+			var b bytes.Buffer
+			err = Screenshot(term, &b, tc.format)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Screenshot => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tc.check(t, &b)
+		})
+	}
+}
@@ -18,6 +18,7 @@ Package termdash implements a terminal based dashboard.
 While running, the terminal dashboard performs the following:
   - Periodic redrawing of the canvas and all the widgets.
   - Event based redrawing of the widgets (i.e. on Keyboard or Mouse events).
+  - Redrawing when widgets report changes, see the OnDemandRedraw option.
   - Forwards input events to widgets and optional subscribers.
   - Handles terminal resize events.
 */
@@ -31,8 +32,10 @@ import (
 	"time"
 
 	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/private/event"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 )
 
 // DefaultRedrawInterval is the default for the RedrawInterval option.
@@ -61,6 +64,24 @@ func RedrawInterval(t time.Duration) Option {
 	})
 }
 
+// OnDemandRedraw skips the periodic redraws while nothing on the dashboard
+// changed. The dashboard is always redrawn as soon as something changes.
+// Changes are reported by widgets that implement widgetapi.DirtyReporter (all
+// the widgets included with termdash do), by updates of the container layout
+// and by calls to Container.MarkDirty. Input events and terminal resizes
+// always cause a redraw. A dashboard that contains any widget that doesn't
+// implement widgetapi.DirtyReporter is redrawn periodically as if this option
+// wasn't provided.
+// The RedrawInterval option still sets how often widgets that keep changing
+// over time, e.g. animations, are drawn.
+// When using the Controller, which doesn't redraw periodically or on reported
+// changes, this option redraws the dashboard whenever changes are reported.
+func OnDemandRedraw() Option {
+	return option(func(td *termdash) {
+		td.onDemand = true
+	})
+}
+
 // ErrorHandler is used to provide a function that will be called with all
 // errors that occur while the dashboard is running. If not provided, any
 // errors panic the application.
@@ -89,6 +110,40 @@ func MouseSubscriber(f func(*terminalapi.Mouse)) Option {
 	})
 }
 
+// QuitKeys configures keys that make Run return when pressed. This is useful
+// to quit the dashboard on e.g. 'q', 'Q' or Ctrl-C without the need to
+// register a KeyboardSubscriber that cancels the context.
+// The keys are still forwarded to the container and any subscribers.
+// Providing this option without any keys disables the built-in quit, which is
+// also the default behavior, leaving shutdown fully up to the application.
+// This option is ignored when using the Controller.
+//
+// The quit keys aren't part of any keybind.Registry and take precedence over
+// it. They quit even when they are bound to an action or start a bound
+// sequence, can't be listed or remapped via the registry and aren't validated
+// against its bindings. Applications that want the quit keys remappable along
+// with their other key bindings don't provide this option and register a quit
+// action whose handler cancels the context provided to Run instead.
+func QuitKeys(keys ...keyboard.Key) Option {
+	return option(func(td *termdash) {
+		td.quitKeys = map[keyboard.Key]bool{}
+		for _, k := range keys {
+			td.quitKeys[k] = true
+		}
+	})
+}
+
+// Theme sets the theme of the dashboard. The theme provides the default
+// colors of the container borders and of the widgets, colors explicitly set
+// via options of the containers or the widgets still take precedence.
+// Defaults to no theme, i.e. the containers and widgets use their own
+// defaults.
+func Theme(t *theme.Theme) Option {
+	return option(func(td *termdash) {
+		td.theme = t
+	})
+}
+
 // withEDS indicates that termdash should run with the provided event
 // distribution system instead of creating one.
 // Useful for tests.
@@ -101,7 +156,8 @@ func withEDS(eds *event.DistributionSystem) Option {
 // Run runs the terminal dashboard with the provided container on the terminal.
 // Redraws the terminal periodically. If you prefer a manual redraw, use the
 // Controller instead.
-// Blocks until the context expires.
+// Blocks until the context expires or until one of the keys provided via the
+// QuitKeys option is pressed.
 func Run(ctx context.Context, t terminalapi.Terminal, c *container.Container, opts ...Option) error {
 	td := newTermdash(t, c, opts...)
 
@@ -118,21 +174,32 @@ func Run(ctx context.Context, t terminalapi.Terminal, c *container.Container, op
 type Controller struct {
 	td     *termdash
 	cancel context.CancelFunc
+	// redrawExitCh gets closed when the goroutine processing the redraw
+	// requests exits.
+	redrawExitCh chan struct{}
 }
 
 // NewController initializes termdash and returns an instance of the controller.
 // Periodic redrawing is disabled when using the controller, the RedrawInterval
-// option is ignored.
+// option is ignored. Changes reported by the widgets only cause a redraw when
+// the OnDemandRedraw option is provided.
 // Close the controller when it isn't needed anymore.
 func NewController(t terminalapi.Terminal, c *container.Container, opts ...Option) (*Controller, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	ctrl := &Controller{
-		td:     newTermdash(t, c, opts...),
-		cancel: cancel,
+		td:           newTermdash(t, c, opts...),
+		cancel:       cancel,
+		redrawExitCh: make(chan struct{}),
 	}
 
 	// stops when Close() is called.
 	go ctrl.td.processEvents(ctx)
+	go func() {
+		defer close(ctrl.redrawExitCh)
+		if ctrl.td.onDemand {
+			ctrl.td.processRedrawRequests(ctx)
+		}
+	}()
 	if err := ctrl.td.periodicRedraw(); err != nil {
 		return nil, err
 	}
@@ -150,10 +217,26 @@ func (c *Controller) Redraw() error {
 	return c.td.redraw()
 }
 
+// Batch calls the provided function and redraws the terminal once it returns.
+// The terminal isn't redrawn while the function runs, so changes made to
+// multiple widgets inside it are displayed together in a single frame.
+// The function must not call Redraw or Batch, which would deadlock.
+func (c *Controller) Batch(fn func()) error {
+	if c.td == nil {
+		return errors.New("the termdash instance is no longer running, this controller is now invalid")
+	}
+
+	c.td.mu.Lock()
+	defer c.td.mu.Unlock()
+	fn()
+	return c.td.redraw()
+}
+
 // Close closes the Controller and its termdash instance.
 func (c *Controller) Close() {
 	c.cancel()
 	c.td.stop()
+	<-c.redrawExitCh
 	c.td = nil
 }
 
@@ -174,6 +257,14 @@ type termdash struct {
 	closeCh chan struct{}
 	// exitCh gets closed when the event collecting goroutine actually exits.
 	exitCh chan struct{}
+	// quitCh gets closed when one of the quit keys is pressed.
+	quitCh chan struct{}
+	// quitOnce ensures quitCh is only closed once.
+	quitOnce sync.Once
+	// redrawCh receives the redraw requests made when the container is marked
+	// dirty. It is buffered, so requests made before a pending one is
+	// processed are coalesced.
+	redrawCh chan struct{}
 
 	// clearNeeded indicates if the terminal needs to be cleared next time
 	// we're drawing it. Terminal needs to be cleared if its sized changed.
@@ -184,9 +275,12 @@ type termdash struct {
 
 	// Options.
 	redrawInterval     time.Duration
+	onDemand           bool
 	errorHandler       func(error)
 	mouseSubscriber    func(*terminalapi.Mouse)
 	keyboardSubscriber func(*terminalapi.Keyboard)
+	quitKeys           map[keyboard.Key]bool
+	theme              *theme.Theme
 }
 
 // newTermdash creates a new termdash.
@@ -197,6 +291,8 @@ func newTermdash(t terminalapi.Terminal, c *container.Container, opts ...Option)
 		eds:            event.NewDistributionSystem(),
 		closeCh:        make(chan struct{}),
 		exitCh:         make(chan struct{}),
+		quitCh:         make(chan struct{}),
+		redrawCh:       make(chan struct{}, 1),
 		redrawInterval: DefaultRedrawInterval,
 	}
 
@@ -205,6 +301,10 @@ func newTermdash(t terminalapi.Terminal, c *container.Container, opts ...Option)
 	}
 	td.subscribers()
 	c.Subscribe(td.eds)
+	if td.theme != nil {
+		c.SetTheme(td.theme)
+	}
+	c.SetRedrawFunc(td.requestRedraw)
 	return td
 }
 
@@ -216,20 +316,35 @@ func (td *termdash) subscribers() {
 	})
 
 	// Handles terminal resize events.
+	// The screen is redrawn right away instead of waiting for the next
+	// periodic redraw, which might be far away or disabled. At most one
+	// repetitive event is queued, so rapid consecutive resizes coalesce while
+	// the last size is always drawn.
 	td.eds.Subscribe([]terminalapi.Event{&terminalapi.Resize{}}, func(terminalapi.Event) {
 		td.setClearNeeded()
-	})
+		td.evRedraw()
+	}, event.MaxRepetitive(1))
 
-	// Redraws the screen on Keyboard and Mouse events.
+	// Redraws the screen on Keyboard, Mouse and Paste events.
 	// These events very likely change the content of the widgets (e.g. zooming
 	// a LineChart) so a redraw is needed to make that visible.
 	td.eds.Subscribe([]terminalapi.Event{
 		&terminalapi.Keyboard{},
 		&terminalapi.Mouse{},
+		&terminalapi.Paste{},
 	}, func(terminalapi.Event) {
 		td.evRedraw()
 	}, event.MaxRepetitive(0)) // No repetitive events that cause terminal redraw.
 
+	// Quits the dashboard when one of the quit keys is pressed.
+	if len(td.quitKeys) > 0 {
+		td.eds.Subscribe([]terminalapi.Event{&terminalapi.Keyboard{}}, func(ev terminalapi.Event) {
+			if td.quitKeys[ev.(*terminalapi.Keyboard).Key] {
+				td.quitOnce.Do(func() { close(td.quitCh) })
+			}
+		})
+	}
+
 	// Keyboard and Mouse subscribers specified via options.
 	if td.keyboardSubscriber != nil {
 		td.eds.Subscribe([]terminalapi.Event{&terminalapi.Keyboard{}}, func(ev terminalapi.Event) {
@@ -268,6 +383,7 @@ func (td *termdash) redraw() error {
 		if err := td.term.Clear(); err != nil {
 			return fmt.Errorf("term.Clear => error: %v", err)
 		}
+		td.container.Invalidate()
 		td.clearNeeded = false
 	}
 
@@ -294,6 +410,40 @@ func (td *termdash) evRedraw() error {
 	return td.redraw()
 }
 
+// requestRedraw requests a redraw of the container and its widgets.
+// Called when the container is marked dirty. Never blocks.
+func (td *termdash) requestRedraw() {
+	select {
+	case td.redrawCh <- struct{}{}:
+	default: // A redraw is already pending.
+	}
+}
+
+// processRedrawRequests redraws the container and its widgets when the
+// container is marked dirty. Used by the Controller, which doesn't run the
+// main loop.
+// Blocks until the context expires.
+func (td *termdash) processRedrawRequests(ctx context.Context) {
+	for {
+		select {
+		case <-td.redrawCh:
+			if err := td.periodicRedraw(); err != nil {
+				td.handleError(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dirty determines if the dashboard changed since it was last drawn.
+func (td *termdash) dirty() bool {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	return td.clearNeeded || td.container.Dirty()
+}
+
 // periodicRedraw is called once each RedrawInterval.
 func (td *termdash) periodicRedraw() error {
 	td.mu.Lock()
@@ -341,6 +491,14 @@ func (td *termdash) start(ctx context.Context) error {
 	for {
 		select {
 		case <-redrawTimer.C:
+			if td.onDemand && !td.dirty() {
+				continue
+			}
+			if err := td.periodicRedraw(); err != nil {
+				return err
+			}
+
+		case <-td.redrawCh:
 			if err := td.periodicRedraw(); err != nil {
 				return err
 			}
@@ -350,6 +508,9 @@ func (td *termdash) start(ctx context.Context) error {
 
 		case <-td.closeCh:
 			return nil
+
+		case <-td.quitCh:
+			return nil
 		}
 	}
 }
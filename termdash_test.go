@@ -16,8 +16,10 @@ package termdash
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"image"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -26,17 +28,20 @@ import (
 	"github.com/mum4k/termdash/container"
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/testcanvas"
 	"github.com/mum4k/termdash/private/event"
 	"github.com/mum4k/termdash/private/event/eventqueue"
 	"github.com/mum4k/termdash/private/event/testevent"
 	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/private/fakewidget"
+	"github.com/mum4k/termdash/terminal/playback"
 	"github.com/mum4k/termdash/terminal/tcell"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 	"github.com/mum4k/termdash/widgets/barchart"
 	"github.com/mum4k/termdash/widgets/gauge"
+	"github.com/mum4k/termdash/widgets/textinput"
 )
 
 // Example shows how to setup and run termdash with periodic redraw.
@@ -469,6 +474,44 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRunQuitKeys(t *testing.T) {
+	t.Parallel()
+
+	eq := eventqueue.New()
+	eq.Push(&terminalapi.Keyboard{Key: 'a'})
+	eq.Push(&terminalapi.Keyboard{Key: 'q'})
+
+	ft, err := faketerm.New(image.Point{60, 10}, faketerm.WithEventQueue(eq))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(
+		ft,
+		container.PlaceWidget(fakewidget.New(widgetapi.Options{})),
+	)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+
+	// The context outlives the test, Run must return due to the quit key.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, ft, cont, QuitKeys('q', 'Q', keyboard.KeyCtrlC))
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("Run => unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run didn't return after a quit key was pressed")
+	}
+}
+
 func TestController(t *testing.T) {
 	t.Parallel()
 
@@ -601,6 +644,28 @@ func TestController(t *testing.T) {
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(image.Point{70, 10})
 
+				fakewidget.MustDraw(
+					ft,
+					testcanvas.MustNew(ft.Area()),
+					&widgetapi.Meta{Focused: true},
+					widgetapi.Options{},
+				)
+				return ft
+			},
+		},
+		{
+			desc: "redraws on resize without a request to redraw",
+			size: image.Point{60, 10},
+			events: []terminalapi.Event{
+				&terminalapi.Resize{Size: image.Point{70, 10}},
+			},
+			wantProcessed: 1,
+			controls: func(ctrl *Controller) error {
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(image.Point{70, 10})
+
 				fakewidget.MustDraw(
 					ft,
 					testcanvas.MustNew(ft.Area()),
@@ -676,3 +741,308 @@ func TestController(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerBatch(t *testing.T) {
+	size := image.Point{60, 10}
+	got, err := faketerm.New(size, faketerm.WithEventQueue(eventqueue.New()))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	mi := fakewidget.New(widgetapi.Options{})
+	cont, err := container.New(got, container.PlaceWidget(mi))
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	ctrl, err := NewController(got, cont)
+	if err != nil {
+		t.Fatalf("NewController => unexpected error: %v", err)
+	}
+
+	if err := ctrl.Batch(func() {
+		mi.Text("hello")
+	}); err != nil {
+		t.Fatalf("Batch => unexpected error: %v", err)
+	}
+
+	want := faketerm.MustNew(size)
+	mirror := fakewidget.New(widgetapi.Options{})
+	mirror.Text("hello")
+	fakewidget.MustDrawWithMirror(
+		mirror,
+		want,
+		testcanvas.MustNew(want.Area()),
+		&widgetapi.Meta{Focused: true},
+	)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Batch => %v", diff)
+	}
+
+	ctrl.Close()
+	if err := ctrl.Batch(func() {}); err == nil {
+		t.Errorf("Batch => expected an error after Close")
+	}
+}
+
+func TestRedrawRequests(t *testing.T) {
+	want := func(size image.Point) *faketerm.Terminal {
+		ft := faketerm.MustNew(size)
+
+		mirror := fakewidget.New(widgetapi.Options{})
+		mirror.Text("hello")
+		fakewidget.MustDrawWithMirror(
+			mirror,
+			ft,
+			testcanvas.MustNew(ft.Area()),
+			&widgetapi.Meta{Focused: true},
+		)
+		return ft
+	}
+
+	tests := []struct {
+		desc string
+		// run starts termdash and returns a function that stops it.
+		run func(t terminalapi.Terminal, c *container.Container) (func() error, error)
+	}{
+		{
+			desc: "redraws changes without waiting for the periodic redraw",
+			run: func(t terminalapi.Terminal, c *container.Container) (func() error, error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				errCh := make(chan error)
+				go func() {
+					// Periodic redraw effectively disabled.
+					errCh <- Run(ctx, t, c, RedrawInterval(time.Hour), OnDemandRedraw())
+				}()
+				return func() error {
+					cancel()
+					return <-errCh
+				}, nil
+			},
+		},
+		{
+			desc: "redraws changes promptly without the OnDemandRedraw option",
+			run: func(t terminalapi.Terminal, c *container.Container) (func() error, error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				errCh := make(chan error)
+				go func() {
+					// Periodic redraw effectively disabled.
+					errCh <- Run(ctx, t, c, RedrawInterval(time.Hour))
+				}()
+				return func() error {
+					cancel()
+					return <-errCh
+				}, nil
+			},
+		},
+		{
+			desc: "redraws changes via the controller",
+			run: func(t terminalapi.Terminal, c *container.Container) (func() error, error) {
+				ctrl, err := NewController(t, c, OnDemandRedraw())
+				if err != nil {
+					return nil, err
+				}
+				return func() error {
+					ctrl.Close()
+					return nil
+				}, nil
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			size := image.Point{60, 10}
+			got, err := faketerm.New(size, faketerm.WithEventQueue(eventqueue.New()))
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			dm := &dirtyMirror{
+				Mirror: fakewidget.New(widgetapi.Options{}),
+			}
+			cont, err := container.New(got, container.PlaceWidget(dm))
+			if err != nil {
+				t.Fatalf("container.New => unexpected error: %v", err)
+			}
+
+			stop, err := tc.run(got, cont)
+			if err != nil {
+				t.Fatalf("run => unexpected error: %v", err)
+			}
+
+			// Wait until the widget receives the function.
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				dm.mu.Lock()
+				defer dm.mu.Unlock()
+				if dm.markDirty == nil {
+					return errors.New("the function wasn't provided")
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			dm.Text("hello")
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if diff := faketerm.Diff(want(size), got); diff != "" {
+					return fmt.Errorf("the dashboard wasn't redrawn: %v", diff)
+				}
+				return nil
+			}); err != nil {
+				t.Errorf("testevent.WaitFor => %v", err)
+			}
+
+			if err := stop(); err != nil {
+				t.Errorf("stop => unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// dirtyMirror is a fakewidget.Mirror that reports changes of its text and
+// counts how many times it was drawn.
+type dirtyMirror struct {
+	*fakewidget.Mirror
+
+	mu        sync.Mutex
+	markDirty func()
+	draws     int
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (dm *dirtyMirror) SetDirtyFunc(markDirty func()) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.markDirty = markDirty
+}
+
+// Draw implements widgetapi.Widget.Draw.
+func (dm *dirtyMirror) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.draws++
+	return dm.Mirror.Draw(cvs, meta)
+}
+
+// Text sets the text of the mirror and reports the change.
+func (dm *dirtyMirror) Text(txt string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.Mirror.Text(txt)
+	if dm.markDirty != nil {
+		dm.markDirty()
+	}
+}
+
+// drawCount returns the number of times the mirror was drawn.
+func (dm *dirtyMirror) drawCount() int {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	return dm.draws
+}
+
+func TestOnDemandRedraw(t *testing.T) {
+	size := image.Point{60, 10}
+	got, err := faketerm.New(size, faketerm.WithEventQueue(eventqueue.New()))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+
+	dm := &dirtyMirror{
+		Mirror: fakewidget.New(widgetapi.Options{}),
+	}
+	cont, err := container.New(got, container.PlaceWidget(dm))
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, got, cont, RedrawInterval(5*time.Millisecond), OnDemandRedraw())
+	}()
+
+	// The initial draw provides the function.
+	if err := testevent.WaitFor(5*time.Second, func() error {
+		dm.mu.Lock()
+		defer dm.mu.Unlock()
+		if dm.markDirty == nil {
+			return errors.New("the function wasn't provided")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("testevent.WaitFor => %v", err)
+	}
+
+	// An idle dashboard isn't redrawn.
+	before := dm.drawCount()
+	time.Sleep(50 * time.Millisecond)
+	if after := dm.drawCount(); after != before {
+		t.Errorf("the idle dashboard was redrawn %d times, want none", after-before)
+	}
+
+	// Changes get drawn.
+	dm.Text("hello")
+	want := faketerm.MustNew(size)
+	mirror := fakewidget.New(widgetapi.Options{})
+	mirror.Text("hello")
+	fakewidget.MustDrawWithMirror(
+		mirror,
+		want,
+		testcanvas.MustNew(want.Area()),
+		&widgetapi.Meta{Focused: true},
+	)
+	if err := testevent.WaitFor(5*time.Second, func() error {
+		if diff := faketerm.Diff(want, got); diff != "" {
+			return fmt.Errorf("the change wasn't drawn: %v", diff)
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("testevent.WaitFor => %v", err)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("Run => unexpected error: %v", err)
+	}
+}
+
+func TestRunPlayback(t *testing.T) {
+	events, err := playback.Parse(strings.NewReader(`
+key h
+key i
+paste "!"
+key KeyBackspace2
+`))
+	if err != nil {
+		t.Fatalf("playback.Parse => unexpected error: %v", err)
+	}
+	term := playback.New(image.Point{6, 1}, events, playback.Settle(50*time.Millisecond))
+
+	ti, err := textinput.New()
+	if err != nil {
+		t.Fatalf("textinput.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(term, container.PlaceWidget(ti))
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		<-term.Done()
+		cancel()
+	}()
+	if err := Run(ctx, term, cont, RedrawInterval(time.Hour)); err != nil {
+		t.Fatalf("Run => unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, f := range term.Frames() {
+		got = append(got, strings.TrimRight(f.String(), " "))
+	}
+	want := []string{"", "h", "hi", "hi!", "hi"}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Frames => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package headless implements a terminal that draws into memory instead of a
+// TTY. Allows running dashboards in CI, cron jobs or on servers without a
+// terminal, e.g. in order to generate textual snapshots or screenshots.
+package headless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/event/eventqueue"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// Terminal is a terminal of a fixed size that keeps its content in memory.
+// Input events are provided by calling Inject and Resize.
+//
+// This object is thread-safe.
+// Implements terminalapi.Terminal and terminalapi.FrameReader.
+type Terminal struct {
+	// events is a queue of input events.
+	events *eventqueue.Unbound
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// back is the back buffer.
+	back [][]terminalapi.FrameCell
+	// front is the last flushed frame.
+	front [][]terminalapi.FrameCell
+	// cursor is the position of the cursor.
+	cursor image.Point
+	// cursorVisible indicates if the cursor is visible.
+	cursorVisible bool
+	// closed indicates if Close was called.
+	closed bool
+}
+
+// validateSize validates the size of the terminal.
+func validateSize(size image.Point) error {
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("invalid terminal size %v, both dimensions must be positive", size)
+	}
+	return nil
+}
+
+// New returns a new headless Terminal of the specified size in cells.
+// Call Close() when the terminal isn't required anymore.
+func New(size image.Point) (*Terminal, error) {
+	if err := validateSize(size); err != nil {
+		return nil, err
+	}
+	return &Terminal{
+		events: eventqueue.New(),
+		back:   newFrame(size, &cell.Options{}),
+		front:  newFrame(size, &cell.Options{}),
+	}, nil
+}
+
+// newFrame returns a frame of the specified size with all the cells empty.
+func newFrame(size image.Point, opts *cell.Options) [][]terminalapi.FrameCell {
+	frame := make([][]terminalapi.FrameCell, size.X)
+	for col := range frame {
+		frame[col] = make([]terminalapi.FrameCell, size.Y)
+		for row := range frame[col] {
+			frame[col][row] = terminalapi.FrameCell{
+				Rune: ' ',
+				Opts: *opts,
+			}
+		}
+	}
+	return frame
+}
+
+// copyFrame returns a copy of the frame.
+func copyFrame(frame [][]terminalapi.FrameCell) [][]terminalapi.FrameCell {
+	cp := make([][]terminalapi.FrameCell, len(frame))
+	for col := range frame {
+		cp[col] = make([]terminalapi.FrameCell, len(frame[col]))
+		copy(cp[col], frame[col])
+	}
+	return cp
+}
+
+// frameSize returns the size of the frame.
+func frameSize(frame [][]terminalapi.FrameCell) image.Point {
+	if len(frame) == 0 {
+		return image.ZP
+	}
+	return image.Point{len(frame), len(frame[0])}
+}
+
+// Inject queues an input event, e.g. a terminalapi.Keyboard event, that
+// will be returned by Event.
+func (t *Terminal) Inject(ev terminalapi.Event) {
+	t.events.Push(ev)
+}
+
+// Resize resizes the terminal and queues a terminalapi.Resize event, the
+// same as a real terminal does when its window changes. The back buffer is
+// cleared.
+func (t *Terminal) Resize(size image.Point) error {
+	if err := validateSize(size); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.back = newFrame(size, &cell.Options{})
+	t.mu.Unlock()
+	t.events.Push(&terminalapi.Resize{Size: size})
+	return nil
+}
+
+// Cursor returns the position of the cursor and whether it is visible.
+func (t *Terminal) Cursor() (image.Point, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cursor, t.cursorVisible
+}
+
+// String returns the runes of the frame last flushed to the terminal, one
+// line per row of cells. Trailing spaces are trimmed from the lines and the
+// cell options are ignored. Suitable for textual snapshots.
+// Implements fmt.Stringer.
+func (t *Terminal) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var b strings.Builder
+	for row := 0; row < frameSize(t.front).Y; row++ {
+		var line strings.Builder
+		for col := 0; col < len(t.front); col++ {
+			r := t.front[col][row].Rune
+			if r == 0 {
+				r = ' '
+			}
+			line.WriteRune(r)
+			// The second cell of a full-width rune has no content of its own.
+			if runewidth.RuneWidth(r) == 2 {
+				col++
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// Frame implements terminalapi.FrameReader.Frame.
+func (t *Terminal) Frame() ([][]terminalapi.FrameCell, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return copyFrame(t.front), nil
+}
+
+// Size implements terminalapi.Terminal.Size.
+func (t *Terminal) Size() image.Point {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return frameSize(t.back)
+}
+
+// Clear implements terminalapi.Terminal.Clear.
+func (t *Terminal) Clear(opts ...cell.Option) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.back = newFrame(frameSize(t.back), cell.NewOptions(opts...))
+	return nil
+}
+
+// Flush implements terminalapi.Terminal.Flush.
+func (t *Terminal) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return errors.New("the terminal is closed")
+	}
+	t.front = copyFrame(t.back)
+	return nil
+}
+
+// SetCursor implements terminalapi.Terminal.SetCursor.
+func (t *Terminal) SetCursor(p image.Point) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cursor = p
+	t.cursorVisible = true
+}
+
+// HideCursor implements terminalapi.Terminal.HideCursor.
+func (t *Terminal) HideCursor() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cursorVisible = false
+}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+// Cells outside of the terminal are ignored, same as real terminals do when
+// the dashboard draws while the terminal is being resized.
+func (t *Terminal) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p.X < 0 || p.X >= len(t.back) || p.Y < 0 || p.Y >= len(t.back[p.X]) {
+		return nil
+	}
+	t.back[p.X][p.Y] = terminalapi.FrameCell{
+		Rune: r,
+		Opts: *cell.NewOptions(opts...),
+	}
+	return nil
+}
+
+// Event implements terminalapi.Terminal.Event.
+func (t *Terminal) Event(ctx context.Context) terminalapi.Event {
+	ev := t.events.Pull(ctx)
+	if ev == nil {
+		return nil
+	}
+	return ev
+}
+
+// Close closes the terminal, should be called when the terminal isn't
+// required anymore.
+// Implements terminalapi.Terminal.Close.
+func (t *Terminal) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.closed = true
+	t.events.Close()
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package headless
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc    string
+		size    image.Point
+		wantErr bool
+	}{
+		{
+			desc: "valid size",
+			size: image.Point{3, 2},
+		},
+		{
+			desc:    "fails on zero width",
+			size:    image.Point{0, 2},
+			wantErr: true,
+		},
+		{
+			desc:    "fails on negative height",
+			size:    image.Point{3, -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			term, err := New(tc.size)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer term.Close()
+			if got := term.Size(); got != tc.size {
+				t.Errorf("Size => %v, want %v", got, tc.size)
+			}
+		})
+	}
+}
+
+func TestDrawAndRead(t *testing.T) {
+	term, err := New(image.Point{4, 2})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	defer term.Close()
+
+	for i, r := range "a世" {
+		if err := term.SetCell(image.Point{i, 0}, r, cell.FgColor(cell.ColorRed)); err != nil {
+			t.Fatalf("SetCell => unexpected error: %v", err)
+		}
+	}
+	// Cells outside of the terminal are ignored.
+	if err := term.SetCell(image.Point{4, 0}, 'x'); err != nil {
+		t.Fatalf("SetCell => unexpected error: %v", err)
+	}
+	if got, want := term.String(), "\n\n"; got != want {
+		t.Errorf("String before Flush => %q, want %q", got, want)
+	}
+
+	if err := term.Flush(); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+	if got, want := term.String(), "a世\n\n"; got != want {
+		t.Errorf("String => %q, want %q", got, want)
+	}
+	frame, err := term.Frame()
+	if err != nil {
+		t.Fatalf("Frame => unexpected error: %v", err)
+	}
+	if got, want := frame[0][0].Opts.FgColor, cell.ColorRed; got != want {
+		t.Errorf("Frame => cell {0,0} has foreground color %v, want %v", got, want)
+	}
+
+	if err := term.Clear(cell.BgColor(cell.ColorBlue)); err != nil {
+		t.Fatalf("Clear => unexpected error: %v", err)
+	}
+	if err := term.Flush(); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+	if got, want := term.String(), "\n\n"; got != want {
+		t.Errorf("String after Clear => %q, want %q", got, want)
+	}
+
+	term.SetCursor(image.Point{1, 1})
+	if p, visible := term.Cursor(); p != (image.Point{1, 1}) || !visible {
+		t.Errorf("Cursor => %v, %v, want %v, true", p, visible, image.Point{1, 1})
+	}
+	term.HideCursor()
+	if _, visible := term.Cursor(); visible {
+		t.Errorf("Cursor => visible, want hidden")
+	}
+
+	term.Close()
+	if err := term.Flush(); err == nil {
+		t.Errorf("Flush after Close => got nil error, want an error")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	term, err := New(image.Point{4, 2})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	defer term.Close()
+
+	term.Inject(&terminalapi.Keyboard{Key: keyboard.KeyEnter})
+	if err := term.Resize(image.Point{2, 1}); err != nil {
+		t.Fatalf("Resize => unexpected error: %v", err)
+	}
+	if err := term.Resize(image.Point{0, 1}); err == nil {
+		t.Errorf("Resize => got nil error, want an error")
+	}
+	if got, want := term.Size(), (image.Point{2, 1}); got != want {
+		t.Errorf("Size => %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got []terminalapi.Event
+	for i := 0; i < 2; i++ {
+		got = append(got, term.Event(ctx))
+	}
+	want := []terminalapi.Event{
+		&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+		&terminalapi.Resize{Size: image.Point{2, 1}},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Event => unexpected diff (-want, +got):\n%s", diff)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if ev := term.Event(ctx); ev != nil {
+		t.Errorf("Event => %v, want nil after the context expired", ev)
+	}
+}
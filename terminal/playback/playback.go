@@ -0,0 +1,333 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package playback implements a terminal that replays a recorded sequence of
+// input events against the real draw pipeline and captures the frame drawn
+// in response to each of them. Useful for end-to-end tests of entire
+// dashboards that compare the frames with golden files.
+package playback
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*Terminal)
+}
+
+// option implements Option.
+type option func(*Terminal)
+
+// set implements Option.set.
+func (o option) set(t *Terminal) {
+	o(t)
+}
+
+// DefaultSettle is the default value for the Settle option.
+const DefaultSettle = 50 * time.Millisecond
+
+// Settle sets how long the terminal waits without any flush before it
+// considers the frame drawn in response to an event final and delivers the
+// next event. Increase this if widgets update asynchronously after processing
+// the events.
+// Defaults to DefaultSettle.
+func Settle(d time.Duration) Option {
+	return option(func(t *Terminal) {
+		t.settle = d
+	})
+}
+
+// DefaultTimeout is the default value for the Timeout option.
+const DefaultTimeout = 5 * time.Second
+
+// Timeout sets how long the terminal waits for the first flush after an
+// event, before it captures the unchanged frame and delivers the next event.
+// Defaults to DefaultTimeout.
+func Timeout(d time.Duration) Option {
+	return option(func(t *Terminal) {
+		t.timeout = d
+	})
+}
+
+// Frame is a frame captured after an event was processed.
+type Frame struct {
+	// Event is the event the frame was drawn in response to, nil for the
+	// frame drawn before the first event.
+	Event terminalapi.Event
+	// Cells are the cells of the frame indexed as [x][y].
+	Cells [][]terminalapi.FrameCell
+}
+
+// String returns the runes in the frame, one line per row of cells. The
+// attributes of the cells are ignored. Suitable for golden files.
+func (f *Frame) String() string {
+	var b strings.Builder
+	rows := frameSize(f.Cells).Y
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			b.WriteRune('\n')
+		}
+		for col := 0; col < len(f.Cells); col++ {
+			r := f.Cells[col][row].Rune
+			if r == 0 {
+				r = ' '
+			}
+			b.WriteRune(r)
+			// The second cell of a full-width rune has no content of its own.
+			if runewidth.RuneWidth(r) == 2 {
+				col++
+			}
+		}
+	}
+	return b.String()
+}
+
+// Terminal is a terminal that replays events. Every event is delivered only
+// after the frame drawn in response to the previous event was flushed and no
+// further flush followed for the duration of the Settle option. The frame is
+// then captured.
+//
+// This object is thread-safe.
+// Implements terminalapi.Terminal and terminalapi.FrameReader.
+type Terminal struct {
+	// events are the events to replay.
+	events []terminalapi.Event
+	// done gets closed after the frame drawn in response to the last event
+	// was captured.
+	done chan struct{}
+
+	// Options.
+	settle  time.Duration
+	timeout time.Duration
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// back is the back buffer.
+	back [][]terminalapi.FrameCell
+	// front is the last flushed frame.
+	front [][]terminalapi.FrameCell
+	// flushed indicates if there was a flush since the last event was
+	// delivered.
+	flushed bool
+	// lastFlush is the time of the last flush.
+	lastFlush time.Time
+	// delivered is when the last event was delivered, or when the terminal
+	// was created.
+	delivered time.Time
+	// next is the index of the next event to deliver.
+	next int
+	// finished indicates that all the events were replayed.
+	finished bool
+	// frames are the captured frames.
+	frames []*Frame
+}
+
+// New returns a new playback Terminal of the specified size that replays the
+// events.
+func New(size image.Point, events []terminalapi.Event, opts ...Option) *Terminal {
+	t := &Terminal{
+		events:    events,
+		done:      make(chan struct{}),
+		settle:    DefaultSettle,
+		timeout:   DefaultTimeout,
+		back:      newFrame(size, &cell.Options{}),
+		front:     newFrame(size, &cell.Options{}),
+		delivered: time.Now(),
+	}
+	for _, opt := range opts {
+		opt.set(t)
+	}
+	return t
+}
+
+// newFrame returns a frame of the specified size with all the cells empty.
+func newFrame(size image.Point, opts *cell.Options) [][]terminalapi.FrameCell {
+	frame := make([][]terminalapi.FrameCell, size.X)
+	for col := range frame {
+		frame[col] = make([]terminalapi.FrameCell, size.Y)
+		for row := range frame[col] {
+			frame[col][row] = terminalapi.FrameCell{
+				Rune: ' ',
+				Opts: *opts,
+			}
+		}
+	}
+	return frame
+}
+
+// copyFrame returns a copy of the frame.
+func copyFrame(frame [][]terminalapi.FrameCell) [][]terminalapi.FrameCell {
+	cp := make([][]terminalapi.FrameCell, len(frame))
+	for col := range frame {
+		cp[col] = make([]terminalapi.FrameCell, len(frame[col]))
+		copy(cp[col], frame[col])
+	}
+	return cp
+}
+
+// frameSize returns the size of the frame.
+func frameSize(frame [][]terminalapi.FrameCell) image.Point {
+	if len(frame) == 0 {
+		return image.ZP
+	}
+	return image.Point{len(frame), len(frame[0])}
+}
+
+// Done returns a channel that gets closed once all the events were replayed
+// and the frame drawn in response to the last one was captured.
+func (t *Terminal) Done() <-chan struct{} {
+	return t.done
+}
+
+// Frames returns the frames captured so far. The first frame is the one
+// drawn before the first event, followed by one frame per replayed event.
+func (t *Terminal) Frames() []*Frame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	frames := make([]*Frame, len(t.frames))
+	copy(frames, t.frames)
+	return frames
+}
+
+// Size implements terminalapi.Terminal.Size.
+func (t *Terminal) Size() image.Point {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return frameSize(t.back)
+}
+
+// Clear implements terminalapi.Terminal.Clear.
+func (t *Terminal) Clear(opts ...cell.Option) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.back = newFrame(frameSize(t.back), cell.NewOptions(opts...))
+	return nil
+}
+
+// Flush implements terminalapi.Terminal.Flush.
+func (t *Terminal) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.front = copyFrame(t.back)
+	t.flushed = true
+	t.lastFlush = time.Now()
+	return nil
+}
+
+// Frame implements terminalapi.FrameReader.Frame.
+func (t *Terminal) Frame() ([][]terminalapi.FrameCell, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return copyFrame(t.front), nil
+}
+
+// SetCursor implements terminalapi.Terminal.SetCursor.
+// The cursor isn't captured.
+func (t *Terminal) SetCursor(p image.Point) {}
+
+// HideCursor implements terminalapi.Terminal.HideCursor.
+// The cursor isn't captured.
+func (t *Terminal) HideCursor() {}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+func (t *Terminal) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p.X < 0 || p.X >= len(t.back) || p.Y < 0 || p.Y >= len(t.back[p.X]) {
+		return fmt.Errorf("cell %v falls outside of the terminal size %v", p, frameSize(t.back))
+	}
+	t.back[p.X][p.Y] = terminalapi.FrameCell{
+		Rune: r,
+		Opts: *cell.NewOptions(opts...),
+	}
+	return nil
+}
+
+// settled determines if the frame drawn in response to the last event is
+// final.
+func (t *Terminal) settled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.flushed && now.Sub(t.lastFlush) >= t.settle {
+		return true
+	}
+	return now.Sub(t.delivered) >= t.timeout
+}
+
+// Event implements terminalapi.Terminal.Event.
+// Returns the next event once the frame drawn in response to the previous one
+// settles. Blocks until the context expires after all the events were
+// replayed.
+func (t *Terminal) Event(ctx context.Context) terminalapi.Event {
+	poll := t.settle / 5
+	if poll <= 0 {
+		poll = time.Millisecond
+	}
+	for !t.settled() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(poll):
+		}
+	}
+
+	t.mu.Lock()
+	if !t.finished {
+		var prev terminalapi.Event
+		if t.next > 0 {
+			prev = t.events[t.next-1]
+		}
+		t.frames = append(t.frames, &Frame{
+			Event: prev,
+			Cells: copyFrame(t.front),
+		})
+	}
+	if t.next == len(t.events) {
+		if !t.finished {
+			t.finished = true
+			close(t.done)
+		}
+		t.mu.Unlock()
+		<-ctx.Done()
+		return nil
+	}
+
+	ev := t.events[t.next]
+	t.next++
+	t.flushed = false
+	t.delivered = time.Now()
+	if res, ok := ev.(*terminalapi.Resize); ok {
+		t.back = newFrame(res.Size, &cell.Options{})
+	}
+	t.mu.Unlock()
+	return ev
+}
+
+// Close implements terminalapi.Terminal.Close.
+func (t *Terminal) Close() {}
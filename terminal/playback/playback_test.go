@@ -0,0 +1,151 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package playback
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// drawKeys simulates a dashboard that draws the pressed keys onto the
+// terminal, until the context expires.
+func drawKeys(ctx context.Context, t *Terminal) error {
+	var keys []rune
+	draw := func() error {
+		if err := t.Clear(); err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if err := t.SetCell(image.Point{i, 0}, k, cell.FgColor(cell.ColorRed)); err != nil {
+				return err
+			}
+		}
+		return t.Flush()
+	}
+
+	if err := draw(); err != nil {
+		return err
+	}
+	for {
+		ev := t.Event(ctx)
+		if ev == nil {
+			return nil
+		}
+		if k, ok := ev.(*terminalapi.Keyboard); ok {
+			keys = append(keys, rune(k.Key))
+		}
+		// Draw twice, only the last flush is captured.
+		if err := draw(); err != nil {
+			return err
+		}
+		if err := draw(); err != nil {
+			return err
+		}
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	events := []terminalapi.Event{
+		&terminalapi.Keyboard{Key: 'a'},
+		&terminalapi.Resize{Size: image.Point{2, 1}},
+		&terminalapi.Keyboard{Key: 'b'},
+	}
+	term := New(image.Point{3, 2}, events, Settle(5*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- drawKeys(ctx, term) }()
+
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		t.Fatalf("Done => not closed before the timeout")
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatalf("drawKeys => unexpected error: %v", err)
+	}
+
+	var got []string
+	var gotEvents []terminalapi.Event
+	for _, f := range term.Frames() {
+		got = append(got, f.String())
+		gotEvents = append(gotEvents, f.Event)
+	}
+	want := []string{
+		"   \n   ",
+		"a  \n   ",
+		"a ",
+		"ab",
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Frames => unexpected diff (-want, +got):\n%s", diff)
+	}
+	wantEvents := append([]terminalapi.Event{nil}, events...)
+	if diff := pretty.Compare(wantEvents, gotEvents); diff != "" {
+		t.Errorf("Frames => unexpected events diff (-want, +got):\n%s", diff)
+	}
+
+	frame, err := term.Frame()
+	if err != nil {
+		t.Fatalf("Frame => unexpected error: %v", err)
+	}
+	if got, want := frame[0][0].Opts.FgColor, cell.ColorRed; got != want {
+		t.Errorf("Frame => cell {0,0} has foreground color %v, want %v", got, want)
+	}
+}
+
+func TestTerminalTimeout(t *testing.T) {
+	term := New(image.Point{1, 1}, []terminalapi.Event{
+		&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+	}, Timeout(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Nothing ever flushes, the events are delivered after the timeout.
+	if got := term.Event(ctx); got == nil {
+		t.Fatalf("Event => got nil, want the keyboard event")
+	}
+	go term.Event(ctx)
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		t.Fatalf("Done => not closed before the timeout")
+	}
+	if got, want := len(term.Frames()), 2; got != want {
+		t.Errorf("Frames => got %d frames, want %d", got, want)
+	}
+}
+
+func TestFrameString(t *testing.T) {
+	f := &Frame{
+		Cells: [][]terminalapi.FrameCell{
+			{{Rune: '世'}, {Rune: 'a'}},
+			{{Rune: 0}, {Rune: 0}},
+			{{Rune: 'b'}, {Rune: 'c'}},
+		},
+	}
+	if got, want := f.String(), "世b\na c"; got != want {
+		t.Errorf("String => %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package playback
+
+// script.go parses the recorded events from text.
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// keyNames maps the names of the keyboard keys to the keys.
+var keyNames = func() map[string]keyboard.Key {
+	names := map[string]keyboard.Key{
+		"KeySpace": keyboard.KeySpace,
+	}
+	// The special keys have consecutive negative values.
+	for k := keyboard.Key(-1); k.String() != "KeyUnknown"; k-- {
+		names[k.String()] = k
+	}
+	return names
+}()
+
+// buttonNames maps the names of the mouse buttons to the buttons.
+var buttonNames = func() map[string]mouse.Button {
+	names := map[string]mouse.Button{}
+	for b := mouse.ButtonLeft; b.String() != "ButtonUnknown"; b++ {
+		names[b.String()] = b
+	}
+	return names
+}()
+
+// Parse parses events from a script with one event per line. Empty lines and
+// lines starting with '#' are ignored. The supported events are:
+//
+//	key <rune or key name>   e.g. "key a" or "key KeyEnter"
+//	mouse <x> <y> <button>   e.g. "mouse 3 1 ButtonLeft"
+//	resize <width> <height>  e.g. "resize 80 24"
+//	paste <quoted text>      e.g. `paste "hello\nworld"`
+//
+// The names of the keys and buttons are the names of their constants in the
+// keyboard and mouse packages.
+func Parse(r io.Reader) ([]terminalapi.Event, error) {
+	var events []terminalapi.Event
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		ev, err := parseEvent(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// parseInts parses the fields as integers.
+func parseInts(fields []string) ([]int, error) {
+	var ints []int
+	for _, f := range fields {
+		i, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", f)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+// parseEvent parses one line of the script.
+func parseEvent(text string) (terminalapi.Event, error) {
+	fields := strings.Fields(text)
+	switch kind, args := fields[0], fields[1:]; kind {
+	case "key":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("key expects one argument, got %q", text)
+		}
+		if k, ok := keyNames[args[0]]; ok {
+			return &terminalapi.Keyboard{Key: k}, nil
+		}
+		if runes := []rune(args[0]); len(runes) == 1 {
+			return &terminalapi.Keyboard{Key: keyboard.Key(runes[0])}, nil
+		}
+		return nil, fmt.Errorf("unknown key %q", args[0])
+
+	case "mouse":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("mouse expects three arguments, got %q", text)
+		}
+		pos, err := parseInts(args[:2])
+		if err != nil {
+			return nil, err
+		}
+		b, ok := buttonNames[args[2]]
+		if !ok {
+			return nil, fmt.Errorf("unknown mouse button %q", args[2])
+		}
+		return &terminalapi.Mouse{Position: image.Point{pos[0], pos[1]}, Button: b}, nil
+
+	case "resize":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("resize expects two arguments, got %q", text)
+		}
+		size, err := parseInts(args)
+		if err != nil {
+			return nil, err
+		}
+		if size[0] <= 0 || size[1] <= 0 {
+			return nil, fmt.Errorf("invalid size %dx%d, both dimensions must be positive", size[0], size[1])
+		}
+		return &terminalapi.Resize{Size: image.Point{size[0], size[1]}}, nil
+
+	case "paste":
+		quoted := strings.TrimSpace(strings.TrimPrefix(text, kind))
+		t, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("paste expects a quoted text, got %q", quoted)
+		}
+		return &terminalapi.Paste{Text: t}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown event %q", kind)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package playback
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc    string
+		script  string
+		want    []terminalapi.Event
+		wantErr bool
+	}{
+		{
+			desc: "parses all the events and skips comments",
+			script: `
+# Type and submit.
+key a
+key KeySpace
+key KeyEnter
+key KeyCtrlC
+mouse 3 1 ButtonLeft
+mouse 0 0 ButtonWheelRight
+resize 80 24
+paste "hello\nworld"
+`,
+			want: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'a'},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyCtrlC},
+				&terminalapi.Mouse{Position: image.Point{3, 1}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonWheelRight},
+				&terminalapi.Resize{Size: image.Point{80, 24}},
+				&terminalapi.Paste{Text: "hello\nworld"},
+			},
+		},
+		{
+			desc:    "fails on unknown event",
+			script:  "scroll 1",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unknown key",
+			script:  "key KeyNope",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unknown button",
+			script:  "mouse 1 1 ButtonNope",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on invalid position",
+			script:  "mouse a 1 ButtonLeft",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on invalid size",
+			script:  "resize 0 24",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unquoted paste",
+			script:  "paste hello",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tc.script))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Parse => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Parse => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+// ansi.go converts frames into the escape sequences a terminal would receive
+// to display them.
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// colorSGR returns the SGR parameters that set the color, base is 38 for the
+// foreground and 48 for the background color.
+// Returns an empty string for cell.ColorDefault.
+func colorSGR(c cell.Color, base int) string {
+	if c == cell.ColorDefault {
+		return ""
+	}
+	if r, g, b, ok := c.RGB24(); ok {
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, r, g, b)
+	}
+	// Subtract one, because cell.ColorBlack has value one instead of zero.
+	// Zero is used for cell.ColorDefault instead.
+	return fmt.Sprintf("%d;5;%d", base, (c-1)%256)
+}
+
+// sgr returns the escape sequence that resets the attributes and sets the
+// ones in the options.
+func sgr(opts *cell.Options) string {
+	params := []string{"0"}
+	for _, a := range []struct {
+		set   bool
+		param int
+	}{
+		{opts.Bold, 1},
+		{opts.Dim, 2},
+		{opts.Italic, 3},
+		{opts.Underline, 4},
+		{opts.Blink, 5},
+		{opts.Inverse, 7},
+		{opts.Strikethrough, 9},
+	} {
+		if a.set {
+			params = append(params, strconv.Itoa(a.param))
+		}
+	}
+	if fg := colorSGR(opts.FgColor, 38); fg != "" {
+		params = append(params, fg)
+	}
+	if bg := colorSGR(opts.BgColor, 48); bg != "" {
+		params = append(params, bg)
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// sameStyle determines if the two options display the same way. Links aren't
+// recorded.
+func sameStyle(a, b *cell.Options) bool {
+	a2, b2 := *a, *b
+	a2.Link, b2.Link = "", ""
+	return a2 == b2
+}
+
+// encoder produces the escape sequences that update the terminal from one
+// frame to another.
+type encoder struct {
+	buf bytes.Buffer
+	// pos is the position of the cursor, or a negative point if unknown.
+	pos image.Point
+	// style are the active attributes, nil if unknown.
+	style *cell.Options
+}
+
+// newEncoder returns an encoder with unknown state of the terminal.
+func newEncoder() *encoder {
+	return &encoder{
+		pos: image.Point{-1, -1},
+	}
+}
+
+// moveTo moves the cursor to the cell.
+func (e *encoder) moveTo(p image.Point) {
+	if p == e.pos {
+		return
+	}
+	fmt.Fprintf(&e.buf, "\x1b[%d;%dH", p.Y+1, p.X+1)
+	e.pos = p
+}
+
+// diff writes the sequences that draw the cells of the frame that differ
+// from the previous frame. Draws the entire frame, if prev is nil.
+func (e *encoder) diff(prev, frame [][]terminalapi.FrameCell) {
+	if prev == nil {
+		e.style = &cell.Options{}
+		e.buf.WriteString("\x1b[0m\x1b[2J")
+	}
+	rows := 0
+	if len(frame) > 0 {
+		rows = len(frame[0])
+	}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < len(frame); col++ {
+			fc := &frame[col][row]
+			r := fc.Rune
+			if r == 0 {
+				r = ' '
+			}
+			width := runewidth.RuneWidth(r)
+			if prev != nil && prev[col][row] == *fc && (width < 2 || col+1 >= len(frame) || prev[col+1][row] == frame[col+1][row]) {
+				continue
+			}
+			if prev == nil && r == ' ' && sameStyle(&fc.Opts, &cell.Options{}) {
+				// The screen was just cleared.
+				continue
+			}
+
+			e.moveTo(image.Point{col, row})
+			if e.style == nil || !sameStyle(e.style, &fc.Opts) {
+				e.buf.WriteString(sgr(&fc.Opts))
+				o := fc.Opts
+				e.style = &o
+			}
+			e.buf.WriteRune(r)
+			e.pos.X += width
+			if width == 2 {
+				// The second cell of a full-width rune has no content of its
+				// own.
+				col++
+			}
+		}
+	}
+}
+
+// cursor writes the sequences that position or hide the cursor.
+func (e *encoder) cursor(p image.Point, hidden bool) {
+	if hidden {
+		e.buf.WriteString("\x1b[?25l")
+		return
+	}
+	e.moveTo(p)
+	e.buf.WriteString("\x1b[?25h")
+}
@@ -0,0 +1,339 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recorder implements a terminal that records the frames flushed to
+// another terminal as an asciicast v2 file, which can be replayed with
+// asciinema.
+//
+// The format is described at
+// https://docs.asciinema.org/manual/asciicast/v2/.
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*Recorder)
+}
+
+// option implements Option.
+type option func(*Recorder)
+
+// set implements Option.set.
+func (o option) set(r *Recorder) {
+	o(r)
+}
+
+// Title sets the title of the recording stored in the asciicast header.
+// Defaults to no title.
+func Title(title string) Option {
+	return option(func(r *Recorder) {
+		r.title = title
+	})
+}
+
+// Paused creates the recorder paused, the recording starts when Resume is
+// called.
+// Defaults to recording from the start.
+func Paused() Option {
+	return option(func(r *Recorder) {
+		r.paused = true
+	})
+}
+
+// Recorder wraps a terminal and records every frame flushed to it together
+// with its timing. The recording can be paused and resumed at runtime, the
+// time spent paused is cut from the recording.
+//
+// The frames are recorded as the escape sequences of a terminal in the
+// terminalapi.ColorMode256 mode. Hyperlinks aren't recorded. The optional
+// interfaces of the wrapped terminal, e.g. terminalapi.Passthrough, aren't
+// exposed, so the widgets draw everything with characters.
+//
+// This object is thread-safe.
+// Implements terminalapi.Terminal.
+type Recorder struct {
+	// term is the wrapped terminal.
+	term terminalapi.Terminal
+	// w is where the recording is written.
+	w io.Writer
+	// now returns the current time, can be overridden from tests.
+	now func() time.Time
+
+	// Options.
+	title  string
+	paused bool
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// back mirrors the back buffer of the terminal.
+	back [][]terminalapi.FrameCell
+	// cursor is the position of the cursor.
+	cursor image.Point
+	// cursorHidden indicates if the cursor is hidden.
+	cursorHidden bool
+
+	// start is when the recording started, zero if it didn't start yet.
+	start time.Time
+	// pausedAt is when the recording was last paused.
+	pausedAt time.Time
+	// pausedFor is the total time the recording was paused.
+	pausedFor time.Duration
+	// recorded is the last recorded frame, nil if the next frame must be
+	// recorded in full.
+	recorded [][]terminalapi.FrameCell
+	// recordedSize is the size of the terminal in the recording.
+	recordedSize image.Point
+	// enc tracks the state of the recorded terminal.
+	enc *encoder
+}
+
+// New returns a new Recorder that records the frames flushed to the terminal
+// into the writer. The writer isn't closed by the recorder.
+func New(t terminalapi.Terminal, w io.Writer, opts ...Option) *Recorder {
+	r := &Recorder{
+		term: t,
+		w:    w,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt.set(r)
+	}
+	r.back = newFrame(t.Size(), &cell.Options{})
+	if !r.paused {
+		r.resume()
+	}
+	return r
+}
+
+// newFrame returns a frame of the specified size with all the cells empty.
+func newFrame(size image.Point, opts *cell.Options) [][]terminalapi.FrameCell {
+	frame := make([][]terminalapi.FrameCell, size.X)
+	for col := range frame {
+		frame[col] = make([]terminalapi.FrameCell, size.Y)
+		for row := range frame[col] {
+			frame[col][row] = terminalapi.FrameCell{
+				Rune: ' ',
+				Opts: *opts,
+			}
+		}
+	}
+	return frame
+}
+
+// copyFrame returns a copy of the frame.
+func copyFrame(frame [][]terminalapi.FrameCell) [][]terminalapi.FrameCell {
+	cp := make([][]terminalapi.FrameCell, len(frame))
+	for col := range frame {
+		cp[col] = make([]terminalapi.FrameCell, len(frame[col]))
+		copy(cp[col], frame[col])
+	}
+	return cp
+}
+
+// frameSize returns the size of the frame.
+func frameSize(frame [][]terminalapi.FrameCell) image.Point {
+	if len(frame) == 0 {
+		return image.ZP
+	}
+	return image.Point{len(frame), len(frame[0])}
+}
+
+// Pause pauses the recording. Frames flushed while paused aren't recorded.
+func (r *Recorder) Pause() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.paused {
+		return
+	}
+	r.paused = true
+	r.pausedAt = r.now()
+}
+
+// Resume resumes a paused recording. The next flushed frame is recorded in
+// full.
+func (r *Recorder) Resume() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.paused {
+		return
+	}
+	r.resume()
+}
+
+// resume starts or resumes the recording.
+// The caller must hold mu.
+func (r *Recorder) resume() {
+	now := r.now()
+	if r.start.IsZero() {
+		r.start = now
+	} else {
+		r.pausedFor += now.Sub(r.pausedAt)
+	}
+	r.paused = false
+	r.recorded = nil
+}
+
+// Recording determines if the recorder is currently recording.
+func (r *Recorder) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.paused
+}
+
+// Size implements terminalapi.Terminal.Size.
+func (r *Recorder) Size() image.Point {
+	return r.term.Size()
+}
+
+// Clear implements terminalapi.Terminal.Clear.
+func (r *Recorder) Clear(opts ...cell.Option) error {
+	r.mu.Lock()
+	r.back = newFrame(r.term.Size(), cell.NewOptions(opts...))
+	r.mu.Unlock()
+	return r.term.Clear(opts...)
+}
+
+// Flush implements terminalapi.Terminal.Flush.
+// Returns an error if the frame couldn't be recorded.
+func (r *Recorder) Flush() error {
+	if err := r.term.Flush(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.paused {
+		return nil
+	}
+	return r.record()
+}
+
+// header is the header of an asciicast v2 file.
+type header struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// writeEvent writes one event of the asciicast file.
+func (r *Recorder) writeEvent(kind, data string) error {
+	elapsed := r.now().Sub(r.start) - r.pausedFor
+	d, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.w, "[%s, %q, %s]\n", strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64), kind, d)
+	return err
+}
+
+// record writes the back buffer as the next frame of the recording.
+// The caller must hold mu.
+func (r *Recorder) record() error {
+	size := frameSize(r.back)
+	if r.enc == nil {
+		// This is the first recorded frame.
+		h, err := json.Marshal(&header{
+			Version:   2,
+			Width:     size.X,
+			Height:    size.Y,
+			Timestamp: r.start.Unix(),
+			Title:     r.title,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(r.w, "%s\n", h); err != nil {
+			return err
+		}
+	} else if size != r.recordedSize {
+		if err := r.writeEvent("r", fmt.Sprintf("%dx%d", size.X, size.Y)); err != nil {
+			return err
+		}
+		r.recorded = nil
+	}
+	if r.recorded == nil {
+		r.enc = newEncoder()
+	}
+
+	r.enc.diff(r.recorded, r.back)
+	r.enc.cursor(r.cursor, r.cursorHidden)
+	r.recorded = copyFrame(r.back)
+	r.recordedSize = size
+	out := r.enc.buf.String()
+	r.enc.buf.Reset()
+	return r.writeEvent("o", out)
+}
+
+// SetCursor implements terminalapi.Terminal.SetCursor.
+func (r *Recorder) SetCursor(p image.Point) {
+	r.mu.Lock()
+	r.cursor = p
+	r.cursorHidden = false
+	r.mu.Unlock()
+	r.term.SetCursor(p)
+}
+
+// HideCursor implements terminalapi.Terminal.HideCursor.
+func (r *Recorder) HideCursor() {
+	r.mu.Lock()
+	r.cursorHidden = true
+	r.mu.Unlock()
+	r.term.HideCursor()
+}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+func (r *Recorder) SetCell(p image.Point, c rune, opts ...cell.Option) error {
+	if err := r.term.SetCell(p, c, opts...); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p.X >= 0 && p.X < len(r.back) && p.Y >= 0 && p.Y < len(r.back[p.X]) {
+		r.back[p.X][p.Y] = terminalapi.FrameCell{
+			Rune: c,
+			Opts: *cell.NewOptions(opts...),
+		}
+	}
+	return nil
+}
+
+// Event implements terminalapi.Terminal.Event.
+func (r *Recorder) Event(ctx context.Context) terminalapi.Event {
+	return r.term.Event(ctx)
+}
+
+// Close implements terminalapi.Terminal.Close.
+// Closes the wrapped terminal.
+func (r *Recorder) Close() {
+	r.term.Close()
+}
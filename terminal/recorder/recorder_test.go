@@ -0,0 +1,230 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/faketerm"
+)
+
+// fakeClock is a clock advanced manually.
+type fakeClock struct {
+	now time.Time
+}
+
+// advance moves the clock forward.
+func (fc *fakeClock) advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+// event returns one encoded event of the asciicast file.
+func event(elapsed float64, kind, data string) string {
+	d, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("[%.6f, %q, %s]\n", elapsed, kind, d)
+}
+
+// cursorTerm is a fake terminal that ignores the cursor.
+type cursorTerm struct {
+	*faketerm.Terminal
+}
+
+// SetCursor implements terminalapi.Terminal.SetCursor.
+func (cursorTerm) SetCursor(image.Point) {}
+
+// HideCursor implements terminalapi.Terminal.HideCursor.
+func (cursorTerm) HideCursor() {}
+
+// errWriter is a writer that always fails.
+type errWriter struct{}
+
+// Write implements io.Writer.Write.
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestRecorder(t *testing.T) {
+	start := time.Unix(1000, 0)
+	tests := []struct {
+		desc string
+		opts []Option
+		// do draws onto the recorder.
+		do      func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error
+		want    []string
+		wantErr bool
+	}{
+		{
+			desc: "records the header and the first frame",
+			opts: []Option{Title("demo")},
+			do: func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error {
+				clock.advance(1500 * time.Millisecond)
+				if err := r.SetCell(image.Point{0, 0}, 'a', cell.FgColor(cell.ColorRed), cell.Bold()); err != nil {
+					return err
+				}
+				return r.Flush()
+			},
+			want: []string{
+				`{"version":2,"width":3,"height":2,"timestamp":1000,"title":"demo"}` + "\n",
+				event(1.5, "o", "\x1b[0m\x1b[2J\x1b[1;1H\x1b[0;1;38;5;9ma\x1b[1;1H\x1b[?25h"),
+			},
+		},
+		{
+			desc: "records only the changed cells",
+			do: func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error {
+				if err := r.SetCell(image.Point{0, 0}, 'a'); err != nil {
+					return err
+				}
+				r.HideCursor()
+				if err := r.Flush(); err != nil {
+					return err
+				}
+				clock.advance(time.Second)
+				if err := r.SetCell(image.Point{0, 0}, 'a'); err != nil {
+					return err
+				}
+				if err := r.SetCell(image.Point{2, 1}, 'b', cell.BgColor(cell.ColorNumber(200))); err != nil {
+					return err
+				}
+				return r.Flush()
+			},
+			want: []string{
+				`{"version":2,"width":3,"height":2,"timestamp":1000}` + "\n",
+				event(0, "o", "\x1b[0m\x1b[2J\x1b[1;1Ha\x1b[?25l"),
+				event(1, "o", "\x1b[2;3H\x1b[0;48;5;200mb\x1b[?25l"),
+			},
+		},
+		{
+			desc: "records 24 bit colors",
+			do: func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error {
+				if err := r.SetCell(image.Point{1, 0}, 'a', cell.FgColor(cell.ColorRGB24(1, 2, 3)), cell.Italic(), cell.Underline()); err != nil {
+					return err
+				}
+				r.SetCursor(image.Point{2, 1})
+				return r.Flush()
+			},
+			want: []string{
+				`{"version":2,"width":3,"height":2,"timestamp":1000}` + "\n",
+				event(0, "o", "\x1b[0m\x1b[2J\x1b[1;2H\x1b[0;3;4;38;2;1;2;3ma\x1b[2;3H\x1b[?25h"),
+			},
+		},
+		{
+			desc: "doesn't record while paused and cuts the paused time",
+			opts: []Option{Paused()},
+			do: func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error {
+				if r.Recording() {
+					return errors.New("Recording => true, want false")
+				}
+				if err := r.SetCell(image.Point{0, 0}, 'a'); err != nil {
+					return err
+				}
+				if err := r.Flush(); err != nil {
+					return err
+				}
+				clock.advance(time.Second)
+				r.Resume()
+				clock.advance(time.Second)
+				r.Pause()
+				if err := r.SetCell(image.Point{1, 0}, 'b'); err != nil {
+					return err
+				}
+				if err := r.Flush(); err != nil {
+					return err
+				}
+				clock.advance(time.Minute)
+				r.Resume()
+				clock.advance(time.Second)
+				r.HideCursor()
+				return r.Flush()
+			},
+			want: []string{
+				`{"version":2,"width":3,"height":2,"timestamp":1001}` + "\n",
+				// The frame is recorded in full after resuming.
+				event(2, "o", "\x1b[0m\x1b[2J\x1b[1;1Hab\x1b[?25l"),
+			},
+		},
+		{
+			desc: "records resizes",
+			do: func(r *Recorder, ft *faketerm.Terminal, clock *fakeClock) error {
+				if err := r.Flush(); err != nil {
+					return err
+				}
+				if err := ft.Resize(image.Point{2, 1}); err != nil {
+					return err
+				}
+				if err := r.Clear(); err != nil {
+					return err
+				}
+				if err := r.SetCell(image.Point{1, 0}, 'a'); err != nil {
+					return err
+				}
+				r.HideCursor()
+				return r.Flush()
+			},
+			want: []string{
+				`{"version":2,"width":3,"height":2,"timestamp":1000}` + "\n",
+				event(0, "o", "\x1b[0m\x1b[2J\x1b[1;1H\x1b[?25h"),
+				event(0, "r", "2x1"),
+				event(0, "o", "\x1b[0m\x1b[2J\x1b[1;2Ha\x1b[?25l"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			ft, err := faketerm.New(image.Point{3, 2})
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			clock := &fakeClock{now: start}
+			var b strings.Builder
+			r := New(cursorTerm{ft}, &b, append(tc.opts, option(func(r *Recorder) {
+				r.now = func() time.Time { return clock.now }
+			}))...)
+
+			err = tc.do(r, ft, clock)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("do => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := pretty.Compare(strings.Join(tc.want, ""), b.String()); diff != "" {
+				t.Errorf("Recorder => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecorderWriteError(t *testing.T) {
+	ft, err := faketerm.New(image.Point{3, 2})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	r := New(cursorTerm{ft}, errWriter{})
+	if err := r.Flush(); err == nil {
+		t.Errorf("Flush => got nil error, want an error")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package sshterm implements a terminal that displays a dashboard to a client
+connected over SSH. The terminal is bound to the channel of an SSH session
+that requested a PTY and follows the size of the client's window.
+
+The package doesn't depend on any SSH server library. Create one terminal and
+one dashboard per session in the session handler, e.g. with
+github.com/gliderlabs/ssh:
+
+	ssh.Handle(func(s ssh.Session) {
+		pty, windows, ok := s.Pty()
+		if !ok {
+			io.WriteString(s, "a PTY is required\n")
+			return
+		}
+		resizes := make(chan image.Point)
+		go func() {
+			defer close(resizes)
+			for w := range windows {
+				resizes <- image.Point{w.Width, w.Height}
+			}
+		}()
+
+		t, err := sshterm.New(s, &sshterm.PTY{
+			Term:    pty.Term,
+			Size:    image.Point{pty.Window.Width, pty.Window.Height},
+			Resizes: resizes,
+		})
+		if err != nil {
+			return
+		}
+		defer t.Close()
+
+		ctx, cancel := context.WithCancel(s.Context())
+		defer cancel()
+		go func() {
+			<-t.Done()
+			cancel()
+		}()
+		c, err := newDashboard(t) // Builds the container and the widgets.
+		if err != nil {
+			return
+		}
+		termdash.Run(ctx, t, c, termdash.QuitKeys(keyboard.KeyCtrlC))
+	})
+*/
+package sshterm
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+
+	"github.com/mum4k/termdash/private/remotetty"
+	tdtcell "github.com/mum4k/termdash/terminal/tcell"
+)
+
+// DefaultTerm is the terminal type used when the client requests one that
+// isn't known.
+const DefaultTerm = remotetty.DefaultTerm
+
+// PTY describes the pseudo-terminal requested by the SSH client.
+type PTY struct {
+	// Term is the terminal type of the client, i.e. the value of its TERM
+	// environment variable.
+	Term string
+	// Size is the initial size of the client's terminal window in cells.
+	Size image.Point
+	// Resizes receives the new size of the client's terminal window on every
+	// window change request. Can be nil if the size never changes.
+	Resizes <-chan image.Point
+}
+
+// Terminal is a tcell based terminal that draws on the channel of an SSH
+// session.
+// Implements terminalapi.Terminal.
+type Terminal struct {
+	*tdtcell.Terminal
+
+	tty *remotetty.TTY
+	// closeOnce ensures the terminal is closed only once.
+	closeOnce sync.Once
+}
+
+// New returns a new Terminal that draws on the SSH channel. The options are
+// applied to the underlying tcell terminal, e.g. tcell.ColorMode.
+// Call Close() when the terminal isn't required anymore, the caller remains
+// responsible for closing the channel.
+func New(ch io.ReadWriter, pty *PTY, opts ...tdtcell.Option) (*Terminal, error) {
+	if pty == nil {
+		return nil, errors.New("the PTY must be provided, dashboards can only be served to sessions that requested a PTY")
+	}
+	if pty.Size.X <= 0 || pty.Size.Y <= 0 {
+		return nil, fmt.Errorf("invalid window size %v, both dimensions must be positive", pty.Size)
+	}
+
+	tty := remotetty.New(ch, pty.Size)
+	t, err := remotetty.NewTerminal(tty, pty.Term, opts...)
+	if err != nil {
+		tty.StopReading()
+		return nil, err
+	}
+
+	st := &Terminal{
+		Terminal: t,
+		tty:      tty,
+	}
+	if pty.Resizes != nil {
+		go tty.FollowResizes(pty.Resizes) // Stops when Close() is called.
+	}
+	return st, nil
+}
+
+// Done returns a channel that gets closed when the client closes the
+// channel, i.e. disconnects. The dashboard should be stopped then.
+func (t *Terminal) Done() <-chan struct{} {
+	return t.tty.EOF()
+}
+
+// Close closes the terminal, should be called when the terminal isn't
+// required anymore to return the client's screen to a sane state.
+// Implements terminalapi.Terminal.Close.
+func (t *Terminal) Close() {
+	t.closeOnce.Do(func() {
+		t.Terminal.Close()
+		t.tty.StopReading()
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshterm
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// fakeChannel is an SSH channel connected to a fake client.
+type fakeChannel struct {
+	// in is read by the server, written by the client.
+	in      *io.PipeReader
+	clientW *io.PipeWriter
+
+	mu  sync.Mutex
+	out bytes.Buffer
+}
+
+// newFakeChannel returns a new fake channel.
+func newFakeChannel() *fakeChannel {
+	r, w := io.Pipe()
+	return &fakeChannel{
+		in:      r,
+		clientW: w,
+	}
+}
+
+// Read implements io.Reader.Read.
+func (fc *fakeChannel) Read(p []byte) (int, error) {
+	return fc.in.Read(p)
+}
+
+// Write implements io.Writer.Write.
+func (fc *fakeChannel) Write(p []byte) (int, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.out.Write(p)
+}
+
+// output returns everything the server wrote so far.
+func (fc *fakeChannel) output() string {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.out.String()
+}
+
+// waitFor polls the condition until it is true or the timeout expires.
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc    string
+		pty     *PTY
+		wantErr bool
+	}{
+		{
+			desc:    "fails without a PTY",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on invalid window size",
+			pty:     &PTY{Term: "xterm", Size: image.Point{0, 10}},
+			wantErr: true,
+		},
+		{
+			desc: "falls back to the default terminal type",
+			pty:  &PTY{Term: "unknown-terminal", Size: image.Point{10, 3}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			ch := newFakeChannel()
+			defer ch.clientW.Close()
+			term, err := New(ch, tc.pty)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			term.Close()
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	ch := newFakeChannel()
+	resizes := make(chan image.Point)
+	term, err := New(ch, &PTY{
+		Term:    "xterm-256color",
+		Size:    image.Point{10, 3},
+		Resizes: resizes,
+	})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	defer term.Close()
+
+	if got, want := term.Size(), (image.Point{10, 3}); got != want {
+		t.Errorf("Size => %v, want %v", got, want)
+	}
+
+	// Draws on the channel.
+	if err := term.SetCell(image.Point{0, 0}, 'x'); err != nil {
+		t.Fatalf("SetCell => unexpected error: %v", err)
+	}
+	if err := term.Flush(); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+	waitFor(t, "the drawn cell", func() bool {
+		return strings.Contains(ch.output(), "x")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Reads the input of the client.
+	if _, err := ch.clientW.Write([]byte("a")); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+	var got terminalapi.Event
+	for got == nil {
+		ev := term.Event(ctx)
+		if ev == nil {
+			t.Fatalf("Event => timed out waiting for the keyboard event")
+		}
+		if _, ok := ev.(*terminalapi.Keyboard); ok {
+			got = ev
+		}
+	}
+	if k := got.(*terminalapi.Keyboard).Key; k != keyboard.Key('a') {
+		t.Errorf("Event => key %v, want %v", k, keyboard.Key('a'))
+	}
+
+	// Follows the window changes.
+	resizes <- image.Point{20, 5}
+	waitFor(t, "the resize", func() bool {
+		return term.Size() == image.Point{20, 5}
+	})
+
+	// Reports the disconnect.
+	select {
+	case <-term.Done():
+		t.Fatalf("Done => closed before the client disconnected")
+	default:
+	}
+	ch.clientW.Close()
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		t.Fatalf("Done => not closed after the client disconnected")
+	}
+}
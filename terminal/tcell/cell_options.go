@@ -25,6 +25,9 @@ func cellColor(c cell.Color) tcell.Color {
 	if c == cell.ColorDefault {
 		return tcell.ColorDefault
 	}
+	if r, g, b, ok := c.RGB24(); ok {
+		return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+	}
 	// Subtract one, because cell.ColorBlack has value one instead of zero.
 	// Zero is used for cell.ColorDefault instead.
 	return tcell.Color(c-1) + tcell.ColorValid
@@ -35,6 +38,16 @@ func colorToMode(c cell.Color, colorMode terminalapi.ColorMode) cell.Color {
 	if c == cell.ColorDefault {
 		return c
 	}
+	if _, _, _, ok := c.RGB24(); ok {
+		if colorMode == terminalapi.ColorMode256 {
+			// Tcell falls back to the nearest color if the terminal doesn't
+			// support 24 bit colors.
+			return c
+		}
+		// The other color modes expect colors relative to their range,
+		// which doesn't apply to 24 bit colors.
+		return cell.Nearest256(c)
+	}
 	switch colorMode {
 	case terminalapi.ColorModeNormal:
 		c %= 16 + 1 // Add one for cell.ColorDefault.
@@ -71,6 +84,7 @@ func cellOptsToStyle(opts *cell.Options, colorMode terminalapi.ColorMode) tcell.
 		StrikeThrough(opts.Strikethrough).
 		Reverse(opts.Inverse).
 		Blink(opts.Blink).
-		Dim(opts.Dim)
+		Dim(opts.Dim).
+		Url(opts.Link)
 	return st
 }
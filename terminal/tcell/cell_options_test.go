@@ -181,6 +181,17 @@ func TestCellOptsToStyle(t *testing.T) {
 				FgColor: cell.ColorRGB24(0, 0, 0),
 				BgColor: cell.ColorRGB24(255, 255, 255),
 			},
+			want: tcell.StyleDefault.
+				Foreground(tcell.NewRGBColor(0, 0, 0)).
+				Background(tcell.NewRGBColor(255, 255, 255)),
+		},
+		{
+			desc:      "ColorModeNormal: RGB24 colors fall back to the nearest of the 256 colors",
+			colorMode: terminalapi.ColorModeNormal,
+			opts: cell.Options{
+				FgColor: cell.ColorRGB24(0, 0, 0),
+				BgColor: cell.ColorRGB24(255, 255, 255),
+			},
 			want: tcell.StyleDefault.
 				Foreground(tcell.Color16).
 				Background(tcell.Color231),
@@ -330,6 +341,11 @@ func TestCellOptsToStyle(t *testing.T) {
 			opts:      cell.Options{Dim: true},
 			want:      tcell.StyleDefault.Dim(true),
 		},
+		{
+			colorMode: terminalapi.ColorModeNormal,
+			opts:      cell.Options{Link: "https://example.com"},
+			want:      tcell.StyleDefault.Url("https://example.com"),
+		},
 	}
 
 	for _, tc := range tests {
@@ -16,6 +16,8 @@ package tcell
 
 import (
 	"image"
+	"strings"
+	"time"
 
 	tcell "github.com/gdamore/tcell/v2"
 	"github.com/mum4k/termdash/keyboard"
@@ -77,6 +79,7 @@ var tcellToTd = map[tcell.Key]keyboard.Key{
 	tcell.KeyCtrlZ:          keyboard.KeyCtrlZ,
 	tcell.KeyBackspace:      keyboard.KeyBackspace,
 	tcell.KeyTab:            keyboard.KeyTab,
+	tcell.KeyBacktab:        keyboard.KeyBacktab,
 	tcell.KeyEscape:         keyboard.KeyEsc,
 	tcell.KeyCtrlBackslash:  keyboard.KeyCtrlBackslash,
 	tcell.KeyCtrlRightSq:    keyboard.KeyCtrlRsqBracket,
@@ -121,10 +124,15 @@ func convMouse(event *tcell.EventMouse) terminalapi.Event {
 	}
 
 	// Get wheel events
-	if tcellBtn&tcell.WheelUp != 0 {
+	switch {
+	case tcellBtn&tcell.WheelUp != 0:
 		button = mouse.ButtonWheelUp
-	} else if tcellBtn&tcell.WheelDown != 0 {
+	case tcellBtn&tcell.WheelDown != 0:
 		button = mouse.ButtonWheelDown
+	case tcellBtn&tcell.WheelLeft != 0:
+		button = mouse.ButtonWheelLeft
+	case tcellBtn&tcell.WheelRight != 0:
+		button = mouse.ButtonWheelRight
 	}
 
 	// Return wheel event if found
@@ -195,3 +203,58 @@ func toTermdashEvents(event tcell.Event) []terminalapi.Event {
 		}
 	}
 }
+
+// pasteBuffer collects the keys received between the start and the end of a
+// bracketed paste.
+type pasteBuffer struct {
+	// text is the text pasted so far, nil outside of a bracketed paste.
+	text *strings.Builder
+}
+
+// process processes the tcell event on behalf of the bracketed paste.
+// Returns true if the event was consumed, the returned events should then be
+// delivered instead of it.
+func (pb *pasteBuffer) process(event tcell.Event) ([]terminalapi.Event, bool) {
+	switch e := event.(type) {
+	case *tcell.EventPaste:
+		if e.Start() {
+			pb.text = &strings.Builder{}
+			return nil, true
+		}
+		if pb.text == nil {
+			return nil, true
+		}
+		p := &terminalapi.Paste{
+			Text: pb.text.String(),
+			Time: e.When(),
+		}
+		pb.text = nil
+		return []terminalapi.Event{p}, true
+
+	case *tcell.EventKey:
+		if pb.text == nil {
+			return nil, false
+		}
+		switch e.Key() {
+		case tcell.KeyRune:
+			pb.text.WriteRune(e.Rune())
+		case tcell.KeyEnter, tcell.KeyLF:
+			pb.text.WriteRune('\n')
+		case tcell.KeyTab:
+			pb.text.WriteRune('\t')
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// setTime sets the time the terminal received the event on the keyboard and
+// mouse events.
+func setTime(ev terminalapi.Event, t time.Time) {
+	switch e := ev.(type) {
+	case *terminalapi.Keyboard:
+		e.Time = t
+	case *terminalapi.Mouse:
+		e.Time = t
+	}
+}
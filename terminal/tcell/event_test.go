@@ -122,6 +122,9 @@ func TestMouseButtons(t *testing.T) {
 		{btnMask: tcell.ButtonNone, want: []mouse.Button{mouse.ButtonRelease}},
 		{btnMask: tcell.WheelUp, want: []mouse.Button{mouse.ButtonWheelUp}},
 		{btnMask: tcell.WheelDown, want: []mouse.Button{mouse.ButtonWheelDown}},
+		{btnMask: tcell.WheelLeft, want: []mouse.Button{mouse.ButtonWheelLeft}},
+		{btnMask: tcell.WheelRight, want: []mouse.Button{mouse.ButtonWheelRight}},
+		{btnMask: tcell.WheelUp | tcell.Button1, want: []mouse.Button{mouse.ButtonWheelUp}},
 		{btnMask: tcell.Button1 | tcell.Button2, want: nil},
 	}
 
@@ -220,6 +223,7 @@ func TestKeyboardKeys(t *testing.T) {
 		{key: tcell.KeyTab, want: keyboard.KeyTab},
 		{key: tcell.KeyTab, want: keyboard.KeyCtrlI},
 		{key: tcell.KeyCtrlI, want: keyboard.KeyTab},
+		{key: tcell.KeyBacktab, want: keyboard.KeyBacktab},
 		{key: tcell.KeyCtrlJ, want: keyboard.KeyCtrlJ},
 		{key: tcell.KeyCtrlK, want: keyboard.KeyCtrlK},
 		{key: tcell.KeyCtrlL, want: keyboard.KeyCtrlL},
@@ -279,3 +283,112 @@ func TestKeyboardKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTime(t *testing.T) {
+	when := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		desc string
+		ev   terminalapi.Event
+		want terminalapi.Event
+	}{
+		{
+			desc: "sets time on keyboard events",
+			ev:   &terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			want: &terminalapi.Keyboard{Key: keyboard.KeyEnter, Time: when},
+		},
+		{
+			desc: "sets time on mouse events",
+			ev:   &terminalapi.Mouse{Position: image.Point{1, 2}, Button: mouse.ButtonLeft},
+			want: &terminalapi.Mouse{Position: image.Point{1, 2}, Button: mouse.ButtonLeft, Time: when},
+		},
+		{
+			desc: "ignores other events",
+			ev:   &terminalapi.Resize{Size: image.Point{1, 2}},
+			want: &terminalapi.Resize{Size: image.Point{1, 2}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			setTime(tc.ev, when)
+			if diff := pretty.Compare(tc.want, tc.ev); diff != "" {
+				t.Errorf("setTime => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPasteBuffer(t *testing.T) {
+	tests := []struct {
+		desc   string
+		events []tcell.Event
+		// wantConsumed indicates for each event if it was consumed.
+		wantConsumed []bool
+		// wantPasted are the texts of the produced Paste events.
+		wantPasted []string
+	}{
+		{
+			desc: "doesn't consume keys outside of a paste",
+			events: []tcell.Event{
+				tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone),
+				tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone),
+			},
+			wantConsumed: []bool{false, false},
+		},
+		{
+			desc: "collects the pasted keys",
+			events: []tcell.Event{
+				tcell.NewEventPaste(true),
+				tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone),
+				tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone),
+				tcell.NewEventPaste(false),
+				tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+			},
+			wantConsumed: []bool{true, true, true, true, true, true, true, false},
+			wantPasted:   []string{"a\nb\tc"},
+		},
+		{
+			desc: "ignores the end of a paste without a start",
+			events: []tcell.Event{
+				tcell.NewEventPaste(false),
+			},
+			wantConsumed: []bool{true},
+		},
+		{
+			desc: "doesn't consume other events during a paste",
+			events: []tcell.Event{
+				tcell.NewEventPaste(true),
+				tcell.NewEventResize(10, 10),
+				tcell.NewEventPaste(false),
+			},
+			wantConsumed: []bool{true, false, true},
+			wantPasted:   []string{""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			pb := &pasteBuffer{}
+			var (
+				consumed []bool
+				pasted   []string
+			)
+			for _, ev := range tc.events {
+				evs, ok := pb.process(ev)
+				consumed = append(consumed, ok)
+				for _, e := range evs {
+					pasted = append(pasted, e.(*terminalapi.Paste).Text)
+				}
+			}
+			if diff := pretty.Compare(tc.wantConsumed, consumed); diff != "" {
+				t.Errorf("process => unexpected consumed diff (-want, +got):\n%s", diff)
+			}
+			if diff := pretty.Compare(tc.wantPasted, pasted); diff != "" {
+				t.Errorf("process => unexpected pasted diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// frame.go contains code that records the cells drawn onto the terminal so
+// that the last flushed frame can be read back.
+
+import (
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// frameCapture records the cells drawn onto the back buffer and keeps a copy
+// of the last flushed frame.
+type frameCapture struct {
+	// back mirrors the back buffer of tcell.
+	back [][]terminalapi.FrameCell
+
+	// mu protects front.
+	mu sync.Mutex
+	// front is the last flushed frame.
+	front [][]terminalapi.FrameCell
+}
+
+// newFrame returns a frame of the specified size with all the cells empty.
+func newFrame(size image.Point, opts *cell.Options) [][]terminalapi.FrameCell {
+	frame := make([][]terminalapi.FrameCell, size.X)
+	for col := range frame {
+		frame[col] = make([]terminalapi.FrameCell, size.Y)
+		for row := range frame[col] {
+			frame[col][row] = terminalapi.FrameCell{
+				Rune: ' ',
+				Opts: *opts,
+			}
+		}
+	}
+	return frame
+}
+
+// clear empties the back buffer and resizes it to the specified size.
+func (fc *frameCapture) clear(size image.Point, opts *cell.Options) {
+	fc.back = newFrame(size, opts)
+}
+
+// set records the content of one cell of the back buffer.
+// Cells outside of the back buffer are ignored, same as tcell does.
+func (fc *frameCapture) set(p image.Point, r rune, opts *cell.Options) {
+	if p.X < 0 || p.X >= len(fc.back) || p.Y < 0 || p.Y >= len(fc.back[p.X]) {
+		return
+	}
+	fc.back[p.X][p.Y] = terminalapi.FrameCell{
+		Rune: r,
+		Opts: *opts,
+	}
+}
+
+// flush copies the cells of the back buffer within the area into the last
+// flushed frame.
+func (fc *frameCapture) flush(ar image.Rectangle) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if size := frameSize(fc.back); size != frameSize(fc.front) {
+		fc.front = make([][]terminalapi.FrameCell, size.X)
+		for col := range fc.front {
+			fc.front[col] = make([]terminalapi.FrameCell, size.Y)
+		}
+		// The size changed, the whole frame was redrawn.
+		ar = image.Rectangle{Max: size}
+	}
+	ar = ar.Intersect(image.Rectangle{Max: frameSize(fc.back)})
+	for col := ar.Min.X; col < ar.Max.X; col++ {
+		for row := ar.Min.Y; row < ar.Max.Y; row++ {
+			fc.front[col][row] = fc.back[col][row]
+		}
+	}
+}
+
+// frameSize returns the size of the frame.
+func frameSize(frame [][]terminalapi.FrameCell) image.Point {
+	if len(frame) == 0 {
+		return image.ZP
+	}
+	return image.Point{len(frame), len(frame[0])}
+}
+
+// frame returns a copy of the last flushed frame.
+func (fc *frameCapture) frame() [][]terminalapi.FrameCell {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	frame := make([][]terminalapi.FrameCell, len(fc.front))
+	for col := range fc.front {
+		frame[col] = make([]terminalapi.FrameCell, len(fc.front[col]))
+		copy(frame[col], fc.front[col])
+	}
+	return frame
+}
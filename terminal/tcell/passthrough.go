@@ -0,0 +1,126 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// passthrough.go contains code that writes escape sequences directly to the
+// terminal.
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// passthrough is an escape sequence written directly to the terminal.
+type passthrough struct {
+	// p is the cell the sequence is written at.
+	p image.Point
+	// seq is the escape sequence.
+	seq []byte
+}
+
+// kittyDeleteAll is the kitty graphics protocol sequence that deletes all
+// the visible images.
+const kittyDeleteAll = "\x1b_Ga=d,q=2\x1b\\"
+
+// detectGraphics determines the graphics protocol supported by the terminal
+// from the environment variables, getenv returns their values.
+func detectGraphics(getenv func(string) string) terminalapi.GraphicsProtocol {
+	term, prog := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty",
+		prog == "WezTerm", prog == "ghostty":
+		return terminalapi.GraphicsKitty
+
+	case prog == "iTerm.app", term == "foot", term == "foot-extra", term == "mlterm",
+		strings.Contains(term, "sixel"):
+		return terminalapi.GraphicsSixel
+	}
+	return terminalapi.GraphicsNone
+}
+
+// Graphics implements terminalapi.Passthrough.Graphics.
+func (t *Terminal) Graphics() terminalapi.GraphicsProtocol {
+	return t.graphics
+}
+
+// Passthrough implements terminalapi.Passthrough.Passthrough.
+func (t *Terminal) Passthrough(p image.Point, seq []byte) error {
+	if size := t.Size(); !p.In(image.Rect(0, 0, size.X, size.Y)) {
+		return fmt.Errorf("unable to write passthrough at point %v, it must fall within the terminal of size %v", p, size)
+	}
+	for _, pt := range t.pending {
+		if pt.p == p {
+			pt.seq = seq
+			return nil
+		}
+	}
+	t.pending = append(t.pending, &passthrough{p: p, seq: seq})
+	return nil
+}
+
+// passthroughChanged determines if the pending sequences differ from those
+// written when the terminal was last flushed.
+func (t *Terminal) passthroughChanged() bool {
+	if len(t.pending) == 0 && len(t.written) == 0 {
+		return false
+	}
+	if t.Size() != t.writtenSize || len(t.pending) != len(t.written) {
+		return true
+	}
+	for i, pt := range t.pending {
+		if pt.p != t.written[i].p || !bytes.Equal(pt.seq, t.written[i].seq) {
+			return true
+		}
+	}
+	return false
+}
+
+// flushPassthrough flushes the back buffer and writes the pending sequences
+// if they changed since the last flush.
+// Previously written images are removed by redrawing the entire screen or
+// deleting them in the case of the kitty graphics protocol.
+func (t *Terminal) flushPassthrough() error {
+	if !t.passthroughChanged() {
+		t.screen.Show()
+		t.pending = nil
+		return nil
+	}
+
+	t.screen.Sync()
+	var b bytes.Buffer
+	if t.graphics == terminalapi.GraphicsKitty && len(t.written) > 0 {
+		b.WriteString(kittyDeleteAll)
+	}
+	for _, pt := range t.pending {
+		// Save the cursor, move it to the cell, write and restore the cursor.
+		fmt.Fprintf(&b, "\x1b7\x1b[%d;%dH", pt.p.Y+1, pt.p.X+1)
+		b.Write(pt.seq)
+		b.WriteString("\x1b8")
+	}
+	t.written = t.pending
+	t.writtenSize = t.Size()
+	t.pending = nil
+	if b.Len() == 0 {
+		return nil
+	}
+	if _, err := t.passthroughOut.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("failed to write the passthrough sequences: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	tcell "github.com/gdamore/tcell/v2"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		desc string
+		env  map[string]string
+		want terminalapi.GraphicsProtocol
+	}{
+		{
+			desc: "no graphics in an unknown terminal",
+			env:  map[string]string{"TERM": "xterm-256color"},
+			want: terminalapi.GraphicsNone,
+		},
+		{
+			desc: "kitty by the window ID",
+			env:  map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"},
+			want: terminalapi.GraphicsKitty,
+		},
+		{
+			desc: "kitty by the terminal name",
+			env:  map[string]string{"TERM": "xterm-kitty"},
+			want: terminalapi.GraphicsKitty,
+		},
+		{
+			desc: "kitty in WezTerm",
+			env:  map[string]string{"TERM_PROGRAM": "WezTerm"},
+			want: terminalapi.GraphicsKitty,
+		},
+		{
+			desc: "sixel in iTerm2",
+			env:  map[string]string{"TERM_PROGRAM": "iTerm.app"},
+			want: terminalapi.GraphicsSixel,
+		},
+		{
+			desc: "sixel in foot",
+			env:  map[string]string{"TERM": "foot"},
+			want: terminalapi.GraphicsSixel,
+		},
+		{
+			desc: "sixel by the terminal name",
+			env:  map[string]string{"TERM": "xterm-sixel"},
+			want: terminalapi.GraphicsSixel,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			getenv := func(key string) string {
+				return tc.env[key]
+			}
+			if got := detectGraphics(getenv); got != tc.want {
+				t.Errorf("detectGraphics => %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPassthrough(t *testing.T) {
+	tests := []struct {
+		desc     string
+		graphics terminalapi.GraphicsProtocol
+		// frames are the sequences provided before each flush.
+		frames []map[image.Point]string
+		// want is the output written on each flush.
+		want    []string
+		wantErr bool
+	}{
+		{
+			desc:     "fails when the point falls outside of the terminal",
+			graphics: terminalapi.GraphicsSixel,
+			frames: []map[image.Point]string{
+				{{4, 0}: "img"},
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "writes nothing without sequences",
+			graphics: terminalapi.GraphicsSixel,
+			frames: []map[image.Point]string{
+				{},
+			},
+			want: []string{""},
+		},
+		{
+			desc:     "writes the sequence at the cell",
+			graphics: terminalapi.GraphicsSixel,
+			frames: []map[image.Point]string{
+				{{1, 1}: "img"},
+			},
+			want: []string{"\x1b7\x1b[2;2Himg\x1b8"},
+		},
+		{
+			desc:     "writes unchanged sequences only once",
+			graphics: terminalapi.GraphicsSixel,
+			frames: []map[image.Point]string{
+				{{1, 1}: "img"},
+				{{1, 1}: "img"},
+			},
+			want: []string{"\x1b7\x1b[2;2Himg\x1b8", ""},
+		},
+		{
+			desc:     "writes the sequences again when they change",
+			graphics: terminalapi.GraphicsSixel,
+			frames: []map[image.Point]string{
+				{{1, 1}: "img"},
+				{{0, 0}: "img"},
+			},
+			want: []string{"\x1b7\x1b[2;2Himg\x1b8", "\x1b7\x1b[1;1Himg\x1b8"},
+		},
+		{
+			desc:     "deletes previous kitty images",
+			graphics: terminalapi.GraphicsKitty,
+			frames: []map[image.Point]string{
+				{{1, 1}: "img"},
+				{},
+			},
+			want: []string{"\x1b7\x1b[2;2Himg\x1b8", kittyDeleteAll},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sim := tcell.NewSimulationScreen("")
+			tcellNewScreen = func() (tcell.Screen, error) { return sim, nil }
+			if err := sim.Init(); err != nil {
+				t.Fatalf("Init => unexpected error: %v", err)
+			}
+			defer sim.Fini()
+			sim.SetSize(4, 2)
+
+			var out bytes.Buffer
+			term, err := newTerminal(GraphicsProtocol(tc.graphics), PassthroughWriter(&out))
+			if err != nil {
+				t.Fatalf("newTerminal => unexpected error:\n%v", err)
+			}
+			if got := term.Graphics(); got != tc.graphics {
+				t.Errorf("Graphics => %v, want %v", got, tc.graphics)
+			}
+
+			for i, frame := range tc.frames {
+				for p, seq := range frame {
+					err := term.Passthrough(p, []byte(seq))
+					if (err != nil) != tc.wantErr {
+						t.Errorf("Passthrough => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+					}
+					if err != nil {
+						return
+					}
+				}
+
+				out.Reset()
+				if err := term.Flush(); err != nil {
+					t.Fatalf("Flush => unexpected error: %v", err)
+				}
+				if got := out.String(); got != tc.want[i] {
+					t.Errorf("Flush #%d => wrote %q, want %q", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -16,13 +16,19 @@ package tcell
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"image"
+	"io"
+	"os"
+	"time"
 
 	tcell "github.com/gdamore/tcell/v2"
 	"github.com/gdamore/tcell/v2/encoding"
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/areaflush"
 	"github.com/mum4k/termdash/private/event/eventqueue"
+	"github.com/mum4k/termdash/private/resizepoll"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 )
 
@@ -62,6 +68,110 @@ func ClearStyle(fg, bg cell.Color) Option {
 	})
 }
 
+// Monochrome forces the terminal to convert all the colors to the shade of
+// grey with the same luminance before they are displayed, see
+// cell.Grayscale. Useful for screenshots and for terminals with limited
+// colors. The terminal must be in the terminalapi.ColorMode256 mode.
+// Defaults to displaying the colors unchanged.
+func Monochrome() Option {
+	return option(func(t *Terminal) {
+		t.monochrome = true
+	})
+}
+
+// DefaultResizePollInterval is the default value for the ResizePollInterval
+// option.
+const DefaultResizePollInterval = 500 * time.Millisecond
+
+// ResizePollInterval sets how often the terminal compares its size with the
+// actual size of the terminal window and emits a resize event if they differ.
+// This makes resizes reliable on platforms where the SIGWINCH signal is lost
+// or delayed. A zero or a negative duration disables the polling.
+// Defaults to DefaultResizePollInterval.
+func ResizePollInterval(d time.Duration) Option {
+	return option(func(t *Terminal) {
+		t.resizePoll = d
+	})
+}
+
+// DefaultColors sets the colors used for cells that have the foreground or
+// the background color set to cell.ColorDefault, i.e. for all the cells that
+// weren't styled otherwise, including the empty areas of the screen after it
+// is cleared. This allows applications to enforce a consistent backdrop
+// regardless of the theme of the terminal. Providing cell.ColorDefault keeps
+// the color of the terminal theme.
+// Defaults to cell.ColorDefault for both the foreground and the background.
+func DefaultColors(fg, bg cell.Color) Option {
+	return option(func(t *Terminal) {
+		t.defaultFg = fg
+		t.defaultBg = bg
+	})
+}
+
+// BracketedPaste enables the bracketed paste mode of the terminal, if
+// supported. Text pasted into the terminal is then delivered as a single
+// terminalapi.Paste event instead of a sequence of keyboard events.
+// Defaults to the bracketed paste mode being disabled.
+func BracketedPaste() Option {
+	return option(func(t *Terminal) {
+		t.paste = &pasteBuffer{}
+	})
+}
+
+// AreaFlush enables the FlushArea method, which flushes only a part of the
+// back buffer. This requires the terminal to track the cells modified since
+// they were last flushed, which adds some overhead to every drawn cell.
+// Defaults to FlushArea returning an error.
+func AreaFlush() Option {
+	return option(func(t *Terminal) {
+		t.tracker = areaflush.New()
+	})
+}
+
+// FrameCapture enables the Frame method, which returns the content of the
+// frame last flushed to the terminal, see terminalapi.FrameReader. This
+// requires the terminal to keep a copy of every drawn cell, which adds some
+// overhead to every drawn cell and to every flush.
+// Defaults to Frame returning an error.
+func FrameCapture() Option {
+	return option(func(t *Terminal) {
+		t.capture = &frameCapture{}
+	})
+}
+
+// Screen sets the tcell screen the terminal draws on, e.g. a screen created
+// by tcell.NewTerminfoScreenFromTty for a terminal other than the controlling
+// one. Combine with ResizePollInterval(0), since the polling measures the
+// controlling terminal, and with PassthroughWriter.
+// Defaults to the screen of the controlling terminal created by
+// tcell.NewScreen.
+func Screen(s tcell.Screen) Option {
+	return option(func(t *Terminal) {
+		t.screen = s
+	})
+}
+
+// GraphicsProtocol sets the graphics protocol the terminal uses to display
+// images, see terminalapi.Passthrough. Providing terminalapi.GraphicsNone
+// disables displaying images.
+// Defaults to the protocol detected from the environment variables set by
+// terminals known to support it, or to terminalapi.GraphicsNone.
+func GraphicsProtocol(gp terminalapi.GraphicsProtocol) Option {
+	return option(func(t *Terminal) {
+		t.graphics = gp
+	})
+}
+
+// PassthroughWriter sets where the escape sequences provided via the
+// Passthrough method are written. Must be the output of the same terminal
+// tcell draws on.
+// Defaults to os.Stdout.
+func PassthroughWriter(w io.Writer) Option {
+	return option(func(t *Terminal) {
+		t.passthroughOut = w
+	})
+}
+
 // Terminal provides input and output to a real terminal. Wraps the
 // gdamore/tcell terminal implementation. This object is not thread-safe.
 // Implements terminalapi.Terminal.
@@ -78,6 +188,36 @@ type Terminal struct {
 	// Options.
 	colorMode  terminalapi.ColorMode
 	clearStyle *cell.Options
+	monochrome bool
+	resizePoll time.Duration
+	defaultFg  cell.Color
+	defaultBg  cell.Color
+	// tracker is nil unless the AreaFlush option was provided.
+	tracker *areaflush.Tracker
+	// paste is nil unless the BracketedPaste option was provided.
+	paste *pasteBuffer
+	// capture is nil unless the FrameCapture option was provided.
+	capture *frameCapture
+	// graphics is the graphics protocol used to display images.
+	graphics terminalapi.GraphicsProtocol
+	// passthroughOut is where the passthrough sequences are written.
+	passthroughOut io.Writer
+
+	// pending are the passthrough sequences that will be written on the
+	// next flush.
+	pending []*passthrough
+	// written are the passthrough sequences written on the last flush.
+	written []*passthrough
+	// writtenSize is the size of the terminal when the written sequences
+	// were written.
+	writtenSize image.Point
+}
+
+// content is the content of a cell in the tcell back buffer.
+type content struct {
+	mainc rune
+	combc []rune
+	style tcell.Style
 }
 
 // tcellNewScreen can be overridden from tests.
@@ -85,24 +225,28 @@ var tcellNewScreen = tcell.NewScreen
 
 // newTerminal creates the terminal and applies the options.
 func newTerminal(opts ...Option) (*Terminal, error) {
-	screen, err := tcellNewScreen()
-	if err != nil {
-		return nil, fmt.Errorf("tcell.NewScreen => %v", err)
-	}
-
 	t := &Terminal{
-		events:    eventqueue.New(),
-		done:      make(chan struct{}),
-		colorMode: DefaultColorMode,
+		events:     eventqueue.New(),
+		done:       make(chan struct{}),
+		colorMode:  DefaultColorMode,
+		resizePoll: DefaultResizePollInterval,
 		clearStyle: &cell.Options{
 			FgColor: cell.ColorDefault,
 			BgColor: cell.ColorDefault,
 		},
-		screen: screen,
+		graphics:       detectGraphics(os.Getenv),
+		passthroughOut: os.Stdout,
 	}
 	for _, opt := range opts {
 		opt.set(t)
 	}
+	if t.screen == nil {
+		screen, err := tcellNewScreen()
+		if err != nil {
+			return nil, fmt.Errorf("tcell.NewScreen => %v", err)
+		}
+		t.screen = screen
+	}
 
 	return t, nil
 }
@@ -121,11 +265,18 @@ func New(opts ...Option) (*Terminal, error) {
 		return nil, err
 	}
 
-	clearStyle := cellOptsToStyle(t.clearStyle, t.colorMode)
+	clearStyle := cellOptsToStyle(t.toMonochrome(t.withDefaults(t.clearStyle)), t.colorMode)
 	t.screen.EnableMouse()
+	if t.paste != nil {
+		t.screen.EnablePaste()
+	}
 	t.screen.SetStyle(clearStyle)
+	if t.capture != nil {
+		t.capture.clear(t.Size(), t.toMonochrome(t.withDefaults(t.clearStyle)))
+	}
 
 	go t.pollEvents() // Stops when Close() is called.
+	go t.pollSize()   // Stops when Close() is called.
 	return t, nil
 }
 
@@ -140,18 +291,73 @@ func (t *Terminal) Size() image.Point {
 
 // Clear implements terminalapi.Terminal.Clear.
 func (t *Terminal) Clear(opts ...cell.Option) error {
-	o := cell.NewOptions(opts...)
+	o := t.toMonochrome(t.withDefaults(cell.NewOptions(opts...)))
 	st := cellOptsToStyle(o, t.colorMode)
+	if t.tracker != nil {
+		w, h := t.screen.Size()
+		t.tracker.ModifyArea(image.Rect(0, 0, w, h), t.getContent)
+	}
+	if t.capture != nil {
+		t.capture.clear(t.Size(), o)
+	}
 	t.screen.Fill(' ', st)
 	return nil
 }
 
 // Flush implements terminalapi.Terminal.Flush.
 func (t *Terminal) Flush() error {
-	t.screen.Show()
+	if err := t.flushPassthrough(); err != nil {
+		return err
+	}
+	if t.tracker != nil {
+		t.tracker.Flushed()
+	}
+	if t.capture != nil {
+		t.capture.flush(image.Rectangle{Max: t.Size()})
+	}
 	return nil
 }
 
+// FlushArea implements terminalapi.AreaFlusher.FlushArea.
+// Requires the AreaFlush option.
+func (t *Terminal) FlushArea(ar image.Rectangle) error {
+	if t.tracker == nil {
+		return errors.New("flushing of an area requires the AreaFlush option")
+	}
+	return t.tracker.FlushArea(ar, t.getContent, t.setContent, func() error {
+		t.screen.Show()
+		if t.capture != nil {
+			t.capture.flush(ar)
+		}
+		return nil
+	})
+}
+
+// Frame implements terminalapi.FrameReader.Frame.
+// Requires the FrameCapture option.
+func (t *Terminal) Frame() ([][]terminalapi.FrameCell, error) {
+	if t.capture == nil {
+		return nil, errors.New("reading the frame requires the FrameCapture option")
+	}
+	return t.capture.frame(), nil
+}
+
+// getContent returns the content of the cell in the back buffer.
+func (t *Terminal) getContent(p image.Point) areaflush.Content {
+	mainc, combc, style, _ := t.screen.GetContent(p.X, p.Y)
+	return &content{
+		mainc: mainc,
+		combc: combc,
+		style: style,
+	}
+}
+
+// setContent sets the content of the cell in the back buffer.
+func (t *Terminal) setContent(p image.Point, c areaflush.Content) {
+	cont := c.(*content)
+	t.screen.SetContent(p.X, p.Y, cont.mainc, cont.combc, cont.style)
+}
+
 // SetCursor implements terminalapi.Terminal.SetCursor.
 func (t *Terminal) SetCursor(p image.Point) {
 	t.screen.ShowCursor(p.X, p.Y)
@@ -164,12 +370,47 @@ func (t *Terminal) HideCursor() {
 
 // SetCell implements terminalapi.Terminal.SetCell.
 func (t *Terminal) SetCell(p image.Point, r rune, opts ...cell.Option) error {
-	o := cell.NewOptions(opts...)
+	o := t.toMonochrome(t.withDefaults(cell.NewOptions(opts...)))
 	st := cellOptsToStyle(o, t.colorMode)
+	if t.tracker != nil {
+		t.tracker.Modify(p, t.getContent)
+	}
+	if t.capture != nil {
+		t.capture.set(p, r, o)
+	}
 	t.screen.SetContent(p.X, p.Y, r, nil, st)
 	return nil
 }
 
+// withDefaults replaces cell.ColorDefault in the cell options with the colors
+// provided via the DefaultColors option.
+func (t *Terminal) withDefaults(o *cell.Options) *cell.Options {
+	if (o.FgColor != cell.ColorDefault || t.defaultFg == cell.ColorDefault) &&
+		(o.BgColor != cell.ColorDefault || t.defaultBg == cell.ColorDefault) {
+		return o
+	}
+	d := *o
+	if d.FgColor == cell.ColorDefault {
+		d.FgColor = t.defaultFg
+	}
+	if d.BgColor == cell.ColorDefault {
+		d.BgColor = t.defaultBg
+	}
+	return &d
+}
+
+// toMonochrome converts the colors in the cell options to grayscale if the
+// Monochrome option was provided.
+func (t *Terminal) toMonochrome(o *cell.Options) *cell.Options {
+	if !t.monochrome {
+		return o
+	}
+	mono := *o
+	mono.FgColor = cell.Grayscale(o.FgColor)
+	mono.BgColor = cell.Grayscale(o.BgColor)
+	return &mono
+}
+
 // pollEvents polls and enqueues the input events.
 func (t *Terminal) pollEvents() {
 	for {
@@ -179,13 +420,35 @@ func (t *Terminal) pollEvents() {
 		default:
 		}
 
-		events := toTermdashEvents(t.screen.PollEvent())
-		for _, ev := range events {
+		tev := t.screen.PollEvent()
+		if t.paste != nil {
+			if evs, ok := t.paste.process(tev); ok {
+				for _, ev := range evs {
+					t.events.Push(ev)
+				}
+				continue
+			}
+		}
+		var when time.Time
+		if tev != nil {
+			when = tev.When()
+		}
+		for _, ev := range toTermdashEvents(tev) {
+			setTime(ev, when)
 			t.events.Push(ev)
 		}
 	}
 }
 
+// pollSize polls the size of the terminal and makes tcell resize its screen
+// when the size changes, in case tcell missed the SIGWINCH signal. The resize
+// event is then delivered by tcell.
+func (t *Terminal) pollSize() {
+	resizepoll.Poll(t.done, t.resizePoll, t.Size, resizepoll.TTYSize, func(image.Point) {
+		t.screen.Sync()
+	})
+}
+
 // Event implements terminalapi.Terminal.Event.
 func (t *Terminal) Event(ctx context.Context) terminalapi.Event {
 	ev := t.events.Pull(ctx)
@@ -200,5 +463,9 @@ func (t *Terminal) Event(ctx context.Context) terminalapi.Event {
 // Implements terminalapi.Terminal.Close.
 func (t *Terminal) Close() {
 	close(t.done)
+	if t.graphics == terminalapi.GraphicsKitty && len(t.written) > 0 {
+		// Images of the kitty graphics protocol remain until deleted.
+		t.passthroughOut.Write([]byte(kittyDeleteAll))
+	}
 	t.screen.Fini()
 }
@@ -15,7 +15,9 @@
 package tcell
 
 import (
+	"image"
 	"testing"
+	"time"
 
 	tcell "github.com/gdamore/tcell/v2"
 	"github.com/kylelemons/godebug/pretty"
@@ -32,7 +34,8 @@ func TestNewTerminalColorMode(t *testing.T) {
 		{
 			desc: "default options",
 			want: &Terminal{
-				colorMode: terminalapi.ColorMode256,
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: DefaultResizePollInterval,
 			},
 		},
 		{
@@ -41,7 +44,29 @@ func TestNewTerminalColorMode(t *testing.T) {
 				ColorMode(terminalapi.ColorModeNormal),
 			},
 			want: &Terminal{
-				colorMode: terminalapi.ColorModeNormal,
+				colorMode:  terminalapi.ColorModeNormal,
+				resizePoll: DefaultResizePollInterval,
+			},
+		},
+		{
+			desc: "sets monochrome",
+			opts: []Option{
+				Monochrome(),
+			},
+			want: &Terminal{
+				colorMode:  terminalapi.ColorMode256,
+				monochrome: true,
+				resizePoll: DefaultResizePollInterval,
+			},
+		},
+		{
+			desc: "sets resize poll interval",
+			opts: []Option{
+				ResizePollInterval(time.Second),
+			},
+			want: &Terminal{
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: time.Second,
 			},
 		},
 	}
@@ -59,6 +84,9 @@ func TestNewTerminalColorMode(t *testing.T) {
 			got.screen = nil
 			got.events = nil
 			got.done = nil
+			got.passthroughOut = nil
+			// Detected from the environment.
+			got.graphics = terminalapi.GraphicsNone
 			got.clearStyle = nil
 
 			if diff := pretty.Compare(tc.want, got); diff != "" {
@@ -77,7 +105,8 @@ func TestNewTerminalClearStyle(t *testing.T) {
 		{
 			desc: "default options",
 			want: &Terminal{
-				colorMode: terminalapi.ColorMode256,
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: DefaultResizePollInterval,
 				clearStyle: &cell.Options{
 					FgColor: cell.ColorDefault,
 					BgColor: cell.ColorDefault,
@@ -90,7 +119,8 @@ func TestNewTerminalClearStyle(t *testing.T) {
 				ClearStyle(cell.ColorRed, cell.ColorBlue),
 			},
 			want: &Terminal{
-				colorMode: terminalapi.ColorMode256,
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: DefaultResizePollInterval,
 				clearStyle: &cell.Options{
 					FgColor: cell.ColorRed,
 					BgColor: cell.ColorBlue,
@@ -112,6 +142,9 @@ func TestNewTerminalClearStyle(t *testing.T) {
 			got.screen = nil
 			got.events = nil
 			got.done = nil
+			got.passthroughOut = nil
+			// Detected from the environment.
+			got.graphics = terminalapi.GraphicsNone
 
 			if diff := pretty.Compare(tc.want, got); diff != "" {
 				t.Errorf("newTerminal => unexpected diff (-want, +got):\n%s", diff)
@@ -119,3 +152,326 @@ func TestNewTerminalClearStyle(t *testing.T) {
 		})
 	}
 }
+
+func TestToMonochrome(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		co   *cell.Options
+		want *cell.Options
+	}{
+		{
+			desc: "colors unchanged by default",
+			co: &cell.Options{
+				FgColor: cell.ColorRed,
+				BgColor: cell.ColorBlue,
+				Bold:    true,
+			},
+			want: &cell.Options{
+				FgColor: cell.ColorRed,
+				BgColor: cell.ColorBlue,
+				Bold:    true,
+			},
+		},
+		{
+			desc: "converts colors to grayscale",
+			opts: []Option{
+				Monochrome(),
+			},
+			co: &cell.Options{
+				FgColor: cell.ColorRed,
+				BgColor: cell.ColorDefault,
+				Bold:    true,
+			},
+			want: &cell.Options{
+				FgColor: cell.Grayscale(cell.ColorRed),
+				BgColor: cell.ColorDefault,
+				Bold:    true,
+			},
+		},
+	}
+
+	tcellNewScreen = func() (tcell.Screen, error) { return nil, nil }
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			term, err := newTerminal(tc.opts...)
+			if err != nil {
+				t.Fatalf("newTerminal => unexpected error:\n%v", err)
+			}
+
+			got := term.toMonochrome(tc.co)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("toMonochrome => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		co   *cell.Options
+		want *cell.Options
+	}{
+		{
+			desc: "keeps the options when no default colors are set",
+			co:   &cell.Options{},
+			want: &cell.Options{},
+		},
+		{
+			desc: "replaces the default colors",
+			opts: []Option{DefaultColors(cell.ColorWhite, cell.ColorBlue)},
+			co:   &cell.Options{Bold: true},
+			want: &cell.Options{
+				FgColor: cell.ColorWhite,
+				BgColor: cell.ColorBlue,
+				Bold:    true,
+			},
+		},
+		{
+			desc: "keeps explicitly set colors",
+			opts: []Option{DefaultColors(cell.ColorWhite, cell.ColorBlue)},
+			co: &cell.Options{
+				FgColor: cell.ColorRed,
+			},
+			want: &cell.Options{
+				FgColor: cell.ColorRed,
+				BgColor: cell.ColorBlue,
+			},
+		},
+		{
+			desc: "only background set",
+			opts: []Option{DefaultColors(cell.ColorDefault, cell.ColorBlue)},
+			co:   &cell.Options{},
+			want: &cell.Options{
+				BgColor: cell.ColorBlue,
+			},
+		},
+	}
+
+	tcellNewScreen = func() (tcell.Screen, error) { return nil, nil }
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			term, err := newTerminal(tc.opts...)
+			if err != nil {
+				t.Fatalf("newTerminal => unexpected error:\n%v", err)
+			}
+
+			got := term.withDefaults(tc.co)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("withDefaults => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFlushArea(t *testing.T) {
+	tests := []struct {
+		desc    string
+		opts    []Option
+		ar      image.Rectangle
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "fails without the AreaFlush option",
+			ar:      image.Rect(0, 0, 2, 1),
+			wantErr: true,
+		},
+		{
+			desc: "flushes only cells within the area",
+			opts: []Option{AreaFlush()},
+			ar:   image.Rect(0, 0, 2, 1),
+			want: "ab  ",
+		},
+		{
+			desc: "flushes everything when the area covers the screen",
+			opts: []Option{AreaFlush()},
+			ar:   image.Rect(0, 0, 4, 1),
+			want: "abcd",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sim := tcell.NewSimulationScreen("")
+			tcellNewScreen = func() (tcell.Screen, error) { return sim, nil }
+			if err := sim.Init(); err != nil {
+				t.Fatalf("Init => unexpected error: %v", err)
+			}
+			defer sim.Fini()
+			sim.SetSize(4, 1)
+
+			term, err := newTerminal(tc.opts...)
+			if err != nil {
+				t.Fatalf("newTerminal => unexpected error:\n%v", err)
+			}
+			if err := term.Clear(); err != nil {
+				t.Fatalf("Clear => unexpected error: %v", err)
+			}
+			if err := term.Flush(); err != nil {
+				t.Fatalf("Flush => unexpected error: %v", err)
+			}
+			for i, r := range "abcd" {
+				if err := term.SetCell(image.Point{i, 0}, r); err != nil {
+					t.Fatalf("SetCell => unexpected error: %v", err)
+				}
+			}
+
+			err = term.FlushArea(tc.ar)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("FlushArea => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			cells, _, _ := sim.GetContents()
+			var got []rune
+			for _, c := range cells {
+				got = append(got, c.Runes...)
+			}
+			if string(got) != tc.want {
+				t.Errorf("FlushArea => screen shows %q, want %q", string(got), tc.want)
+			}
+
+			// The remaining cells are displayed by the next flush.
+			if err := term.Flush(); err != nil {
+				t.Fatalf("Flush => unexpected error: %v", err)
+			}
+			cells, _, _ = sim.GetContents()
+			got = nil
+			for _, c := range cells {
+				got = append(got, c.Runes...)
+			}
+			if want := "abcd"; string(got) != want {
+				t.Errorf("Flush => screen shows %q, want %q", string(got), want)
+			}
+		})
+	}
+}
+
+// frameRunes returns the runes in the frame row by row.
+func frameRunes(frame [][]terminalapi.FrameCell) string {
+	var b []rune
+	for row := 0; len(frame) > 0 && row < len(frame[0]); row++ {
+		for col := range frame {
+			b = append(b, frame[col][row].Rune)
+		}
+	}
+	return string(b)
+}
+
+func TestFrame(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		// cellOpts are the options of the drawn cells.
+		cellOpts []cell.Option
+		// flushArea if not empty, flushes only this area instead of the
+		// whole screen.
+		flushArea image.Rectangle
+		want      string
+		wantFg    cell.Color
+		wantErr   bool
+	}{
+		{
+			desc:    "fails without the FrameCapture option",
+			wantErr: true,
+		},
+		{
+			desc:     "returns the flushed frame",
+			opts:     []Option{FrameCapture()},
+			cellOpts: []cell.Option{cell.FgColor(cell.ColorRed)},
+			want:     "ab  ",
+			wantFg:   cell.ColorRed,
+		},
+		{
+			desc:   "returns the frame with default colors applied",
+			opts:   []Option{FrameCapture(), DefaultColors(cell.ColorBlue, cell.ColorDefault)},
+			want:   "ab  ",
+			wantFg: cell.ColorBlue,
+		},
+		{
+			desc:      "returns only the flushed area",
+			opts:      []Option{FrameCapture(), AreaFlush()},
+			cellOpts:  []cell.Option{cell.FgColor(cell.ColorRed)},
+			flushArea: image.Rect(0, 0, 1, 1),
+			want:      "a   ",
+			wantFg:    cell.ColorRed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sim := tcell.NewSimulationScreen("")
+			tcellNewScreen = func() (tcell.Screen, error) { return sim, nil }
+			if err := sim.Init(); err != nil {
+				t.Fatalf("Init => unexpected error: %v", err)
+			}
+			defer sim.Fini()
+			sim.SetSize(4, 1)
+
+			term, err := newTerminal(tc.opts...)
+			if err != nil {
+				t.Fatalf("newTerminal => unexpected error:\n%v", err)
+			}
+			if err := term.Clear(); err != nil {
+				t.Fatalf("Clear => unexpected error: %v", err)
+			}
+			if err := term.Flush(); err != nil {
+				t.Fatalf("Flush => unexpected error: %v", err)
+			}
+
+			for i, r := range "ab" {
+				if err := term.SetCell(image.Point{i, 0}, r, tc.cellOpts...); err != nil {
+					t.Fatalf("SetCell => unexpected error: %v", err)
+				}
+			}
+			if tc.flushArea.Empty() {
+				err = term.Flush()
+			} else {
+				err = term.FlushArea(tc.flushArea)
+			}
+			if err != nil {
+				t.Fatalf("Flush => unexpected error: %v", err)
+			}
+			// Cells that weren't flushed aren't in the frame.
+			if err := term.SetCell(image.Point{3, 0}, 'z'); err != nil {
+				t.Fatalf("SetCell => unexpected error: %v", err)
+			}
+
+			frame, err := term.Frame()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Frame => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := frameRunes(frame); got != tc.want {
+				t.Errorf("Frame => runes %q, want %q", got, tc.want)
+			}
+			if got := frame[0][0].Opts.FgColor; got != tc.wantFg {
+				t.Errorf("Frame => cell {0,0} has foreground color %v, want %v", got, tc.wantFg)
+			}
+		})
+	}
+}
+
+func TestScreen(t *testing.T) {
+	sim := tcell.NewSimulationScreen("")
+	tcellNewScreen = func() (tcell.Screen, error) {
+		t.Fatalf("tcellNewScreen => unexpectedly called when the Screen option was provided")
+		return nil, nil
+	}
+	defer func() { tcellNewScreen = tcell.NewScreen }()
+
+	term, err := newTerminal(Screen(sim))
+	if err != nil {
+		t.Fatalf("newTerminal => unexpected error: %v", err)
+	}
+	if term.screen != sim {
+		t.Errorf("newTerminal => the terminal doesn't use the provided screen")
+	}
+}
@@ -39,7 +39,9 @@ func cellColor(c cell.Color) tbx.Attribute {
 	case cell.ColorWhite:
 		return tbx.Attribute(cell.ColorSilver)
 	default:
-		return tbx.Attribute(c)
+		// Termbox doesn't support 24 bit colors in the output modes used by
+		// termdash.
+		return tbx.Attribute(cell.Nearest256(c))
 	}
 }
 
@@ -37,6 +37,7 @@ func TestCellColor(t *testing.T) {
 		{cell.ColorCyan, tbx.ColorCyan},
 		{cell.ColorWhite, tbx.ColorWhite},
 		{cell.Color(42), tbx.Attribute(42)},
+		{cell.ColorRGB24(255, 255, 255), tbx.Attribute(232)},
 	}
 
 	for _, tc := range tests {
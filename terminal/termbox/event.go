@@ -18,6 +18,7 @@ package termbox
 
 import (
 	"image"
+	"time"
 
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
@@ -177,3 +178,14 @@ func toTermdashEvents(tbxEv tbx.Event) []terminalapi.Event {
 		}
 	}
 }
+
+// setTime sets the time the terminal received the event on the keyboard and
+// mouse events.
+func setTime(ev terminalapi.Event, t time.Time) {
+	switch e := ev.(type) {
+	case *terminalapi.Keyboard:
+		e.Time = t
+	case *terminalapi.Mouse:
+		e.Time = t
+	}
+}
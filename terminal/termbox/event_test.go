@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"image"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/keyboard"
@@ -322,3 +323,37 @@ func TestKeyboardKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTime(t *testing.T) {
+	when := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		desc string
+		ev   terminalapi.Event
+		want terminalapi.Event
+	}{
+		{
+			desc: "sets time on keyboard events",
+			ev:   &terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			want: &terminalapi.Keyboard{Key: keyboard.KeyEnter, Time: when},
+		},
+		{
+			desc: "sets time on mouse events",
+			ev:   &terminalapi.Mouse{Position: image.Point{1, 2}, Button: mouse.ButtonLeft},
+			want: &terminalapi.Mouse{Position: image.Point{1, 2}, Button: mouse.ButtonLeft, Time: when},
+		},
+		{
+			desc: "ignores other events",
+			ev:   &terminalapi.Resize{Size: image.Point{1, 2}},
+			want: &terminalapi.Resize{Size: image.Point{1, 2}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			setTime(tc.ev, when)
+			if diff := pretty.Compare(tc.want, tc.ev); diff != "" {
+				t.Errorf("setTime => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
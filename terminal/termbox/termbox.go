@@ -18,10 +18,14 @@ package termbox
 
 import (
 	"context"
+	"errors"
 	"image"
+	"time"
 
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/areaflush"
 	"github.com/mum4k/termdash/private/event/eventqueue"
+	"github.com/mum4k/termdash/private/resizepoll"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	tbx "github.com/nsf/termbox-go"
 )
@@ -51,6 +55,56 @@ func ColorMode(cm terminalapi.ColorMode) Option {
 	})
 }
 
+// Monochrome forces the terminal to convert all the colors to the shade of
+// grey with the same luminance before they are displayed, see
+// cell.Grayscale. Useful for screenshots and for terminals with limited
+// colors. The terminal must be in the terminalapi.ColorMode256 mode.
+// Defaults to displaying the colors unchanged.
+func Monochrome() Option {
+	return option(func(t *Terminal) {
+		t.monochrome = true
+	})
+}
+
+// DefaultResizePollInterval is the default value for the ResizePollInterval
+// option.
+const DefaultResizePollInterval = 500 * time.Millisecond
+
+// ResizePollInterval sets how often the terminal compares its size with the
+// actual size of the terminal window and emits a resize event if they differ.
+// This makes resizes reliable on platforms where the SIGWINCH signal is lost
+// or delayed. A zero or a negative duration disables the polling.
+// Defaults to DefaultResizePollInterval.
+func ResizePollInterval(d time.Duration) Option {
+	return option(func(t *Terminal) {
+		t.resizePoll = d
+	})
+}
+
+// DefaultColors sets the colors used for cells that have the foreground or
+// the background color set to cell.ColorDefault, i.e. for all the cells that
+// weren't styled otherwise, including the empty areas of the screen after it
+// is cleared. This allows applications to enforce a consistent backdrop
+// regardless of the theme of the terminal. Providing cell.ColorDefault keeps
+// the color of the terminal theme.
+// Defaults to cell.ColorDefault for both the foreground and the background.
+func DefaultColors(fg, bg cell.Color) Option {
+	return option(func(t *Terminal) {
+		t.defaultFg = fg
+		t.defaultBg = bg
+	})
+}
+
+// AreaFlush enables the FlushArea method, which flushes only a part of the
+// back buffer. This requires the terminal to track the cells modified since
+// they were last flushed, which adds some overhead to every drawn cell.
+// Defaults to FlushArea returning an error.
+func AreaFlush() Option {
+	return option(func(t *Terminal) {
+		t.tracker = areaflush.New()
+	})
+}
+
 // Terminal provides input and output to a real terminal. Wraps the
 // nsf/termbox-go terminal implementation. This object is not thread-safe.
 //
@@ -65,15 +119,22 @@ type Terminal struct {
 	done chan struct{}
 
 	// Options.
-	colorMode terminalapi.ColorMode
+	colorMode  terminalapi.ColorMode
+	monochrome bool
+	resizePoll time.Duration
+	defaultFg  cell.Color
+	defaultBg  cell.Color
+	// tracker is nil unless the AreaFlush option was provided.
+	tracker *areaflush.Tracker
 }
 
 // newTerminal creates the terminal and applies the options.
 func newTerminal(opts ...Option) *Terminal {
 	t := &Terminal{
-		events:    eventqueue.New(),
-		done:      make(chan struct{}),
-		colorMode: DefaultColorMode,
+		events:     eventqueue.New(),
+		done:       make(chan struct{}),
+		colorMode:  DefaultColorMode,
+		resizePoll: DefaultResizePollInterval,
 	}
 	for _, opt := range opts {
 		opt.set(t)
@@ -97,6 +158,7 @@ func New(opts ...Option) (*Terminal, error) {
 	tbx.SetOutputMode(om)
 
 	go t.pollEvents() // Stops when Close() is called.
+	go t.pollSize()   // Stops when Close() is called.
 	return t, nil
 }
 
@@ -108,17 +170,51 @@ func (t *Terminal) Size() image.Point {
 
 // Clear implements terminalapi.Terminal.Clear.
 func (t *Terminal) Clear(opts ...cell.Option) error {
-	o := cell.NewOptions(opts...)
+	o := t.toMonochrome(t.withDefaults(cell.NewOptions(opts...)))
 	fg, err := cellOptsToFg(o)
 	if err != nil {
 		return err
 	}
+	if t.tracker != nil {
+		w, h := tbx.Size()
+		t.tracker.ModifyArea(image.Rect(0, 0, w, h), getContent)
+	}
 	return tbx.Clear(fg, cellOptsToBg(o))
 }
 
 // Flush implements terminalapi.Terminal.Flush.
 func (t *Terminal) Flush() error {
-	return tbx.Flush()
+	if err := tbx.Flush(); err != nil {
+		return err
+	}
+	if t.tracker != nil {
+		t.tracker.Flushed()
+	}
+	return nil
+}
+
+// FlushArea implements terminalapi.AreaFlusher.FlushArea.
+// Requires the AreaFlush option.
+func (t *Terminal) FlushArea(ar image.Rectangle) error {
+	if t.tracker == nil {
+		return errors.New("flushing of an area requires the AreaFlush option")
+	}
+	return t.tracker.FlushArea(ar, getContent, setContent, tbx.Flush)
+}
+
+// getContent returns the content of the cell in the back buffer.
+func getContent(p image.Point) areaflush.Content {
+	w, h := tbx.Size()
+	if !p.In(image.Rect(0, 0, w, h)) {
+		return tbx.Cell{}
+	}
+	return tbx.CellBuffer()[p.Y*w+p.X]
+}
+
+// setContent sets the content of the cell in the back buffer.
+func setContent(p image.Point, c areaflush.Content) {
+	cont := c.(tbx.Cell)
+	tbx.SetCell(p.X, p.Y, cont.Ch, cont.Fg, cont.Bg)
 }
 
 // SetCursor implements terminalapi.Terminal.SetCursor.
@@ -133,15 +229,47 @@ func (t *Terminal) HideCursor() {
 
 // SetCell implements terminalapi.Terminal.SetCell.
 func (t *Terminal) SetCell(p image.Point, r rune, opts ...cell.Option) error {
-	o := cell.NewOptions(opts...)
+	o := t.toMonochrome(t.withDefaults(cell.NewOptions(opts...)))
 	fg, err := cellOptsToFg(o)
 	if err != nil {
 		return err
 	}
+	if t.tracker != nil {
+		t.tracker.Modify(p, getContent)
+	}
 	tbx.SetCell(p.X, p.Y, r, fg, cellOptsToBg(o))
 	return nil
 }
 
+// withDefaults replaces cell.ColorDefault in the cell options with the colors
+// provided via the DefaultColors option.
+func (t *Terminal) withDefaults(o *cell.Options) *cell.Options {
+	if (o.FgColor != cell.ColorDefault || t.defaultFg == cell.ColorDefault) &&
+		(o.BgColor != cell.ColorDefault || t.defaultBg == cell.ColorDefault) {
+		return o
+	}
+	d := *o
+	if d.FgColor == cell.ColorDefault {
+		d.FgColor = t.defaultFg
+	}
+	if d.BgColor == cell.ColorDefault {
+		d.BgColor = t.defaultBg
+	}
+	return &d
+}
+
+// toMonochrome converts the colors in the cell options to grayscale if the
+// Monochrome option was provided.
+func (t *Terminal) toMonochrome(o *cell.Options) *cell.Options {
+	if !t.monochrome {
+		return o
+	}
+	mono := *o
+	mono.FgColor = cell.Grayscale(o.FgColor)
+	mono.BgColor = cell.Grayscale(o.BgColor)
+	return &mono
+}
+
 // pollEvents polls and enqueues the input events.
 func (t *Terminal) pollEvents() {
 	for {
@@ -152,12 +280,25 @@ func (t *Terminal) pollEvents() {
 		}
 
 		events := toTermdashEvents(tbx.PollEvent())
+		now := time.Now()
 		for _, ev := range events {
+			setTime(ev, now)
 			t.events.Push(ev)
 		}
 	}
 }
 
+// pollSize polls the size of the terminal and enqueues a resize event when it
+// changes, in case termbox missed the SIGWINCH signal.
+func (t *Terminal) pollSize() {
+	last := t.Size()
+	known := func() image.Point { return last }
+	resizepoll.Poll(t.done, t.resizePoll, known, resizepoll.TTYSize, func(size image.Point) {
+		last = size
+		t.events.Push(&terminalapi.Resize{Size: size})
+	})
+}
+
 // Event implements terminalapi.Terminal.Event.
 func (t *Terminal) Event(ctx context.Context) terminalapi.Event {
 	ev := t.events.Pull(ctx)
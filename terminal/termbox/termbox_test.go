@@ -16,6 +16,7 @@ package termbox
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/terminal/terminalapi"
@@ -30,7 +31,8 @@ func TestNewTerminal(t *testing.T) {
 		{
 			desc: "default options",
 			want: &Terminal{
-				colorMode: terminalapi.ColorMode256,
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: DefaultResizePollInterval,
 			},
 		},
 		{
@@ -39,7 +41,29 @@ func TestNewTerminal(t *testing.T) {
 				ColorMode(terminalapi.ColorModeNormal),
 			},
 			want: &Terminal{
-				colorMode: terminalapi.ColorModeNormal,
+				colorMode:  terminalapi.ColorModeNormal,
+				resizePoll: DefaultResizePollInterval,
+			},
+		},
+		{
+			desc: "sets monochrome",
+			opts: []Option{
+				Monochrome(),
+			},
+			want: &Terminal{
+				colorMode:  terminalapi.ColorMode256,
+				monochrome: true,
+				resizePoll: DefaultResizePollInterval,
+			},
+		},
+		{
+			desc: "sets resize poll interval",
+			opts: []Option{
+				ResizePollInterval(time.Second),
+			},
+			want: &Terminal{
+				colorMode:  terminalapi.ColorMode256,
+				resizePoll: time.Second,
 			},
 		},
 	}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"time"
 
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
@@ -35,6 +36,9 @@ type Event interface {
 type Keyboard struct {
 	// Key is the pressed key.
 	Key keyboard.Key
+	// Time is the time the terminal received the event.
+	// Can be zero if the event didn't originate from a terminal.
+	Time time.Time
 }
 
 func (*Keyboard) isEvent() {}
@@ -66,6 +70,9 @@ type Mouse struct {
 	Position image.Point
 	// Button identifies the pressed button if any.
 	Button mouse.Button
+	// Time is the time the terminal received the event.
+	// Can be zero if the event didn't originate from a terminal.
+	Time time.Time
 }
 
 func (*Mouse) isEvent() {}
@@ -75,6 +82,24 @@ func (m Mouse) String() string {
 	return fmt.Sprintf("Mouse{Position: %v, Button: %v}", m.Position, m.Button)
 }
 
+// Paste is the event used when text was pasted into a terminal with the
+// bracketed paste mode enabled, e.g. by the tcell.BracketedPaste option.
+// Implements terminalapi.Event.
+type Paste struct {
+	// Text is the pasted text. Line breaks are represented as '\n'.
+	Text string
+	// Time is the time the terminal received the end of the pasted text.
+	// Can be zero if the event didn't originate from a terminal.
+	Time time.Time
+}
+
+func (*Paste) isEvent() {}
+
+// String implements fmt.Stringer.
+func (p Paste) String() string {
+	return fmt.Sprintf("Paste{Text: %q}", p.Text)
+}
+
 // Error is an event indicating an error while processing input.
 type Error string
 
@@ -0,0 +1,68 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminalapi
+
+// graphics.go defines the graphics protocols terminals use to display images.
+
+import "image"
+
+// GraphicsProtocol is a protocol that terminals use to display images.
+type GraphicsProtocol int
+
+// String implements fmt.Stringer()
+func (gp GraphicsProtocol) String() string {
+	if n, ok := graphicsProtocolNames[gp]; ok {
+		return n
+	}
+	return "GraphicsProtocolUnknown"
+}
+
+// graphicsProtocolNames maps GraphicsProtocol values to human readable names.
+var graphicsProtocolNames = map[GraphicsProtocol]string{
+	GraphicsNone:  "GraphicsNone",
+	GraphicsSixel: "GraphicsSixel",
+	GraphicsKitty: "GraphicsKitty",
+}
+
+// Supported graphics protocols.
+const (
+	// GraphicsNone indicates that the terminal can only display characters.
+	GraphicsNone GraphicsProtocol = iota
+
+	// GraphicsSixel is the DEC Sixel graphics protocol.
+	// https://vt100.net/docs/vt3xx-gp/chapter14.html
+	GraphicsSixel
+
+	// GraphicsKitty is the graphics protocol of the kitty terminal.
+	// https://sw.kovidgoyal.net/kitty/graphics-protocol/
+	GraphicsKitty
+)
+
+// Passthrough is implemented by terminals that can write escape sequences
+// directly to the underlying terminal, e.g. to display images using one of
+// the graphics protocols.
+type Passthrough interface {
+	// Graphics returns the graphics protocol supported by the terminal or
+	// GraphicsNone if the terminal cannot display images.
+	Graphics() GraphicsProtocol
+
+	// Passthrough writes the escape sequence to the terminal with the cursor
+	// placed at the specified cell. The sequences are written when the
+	// terminal is flushed, after the content of the cells.
+	// The terminal only writes the sequences again if they changed since the
+	// last flush, so callers should provide all the sequences before each
+	// flush.
+	Passthrough(p image.Point, seq []byte) error
+}
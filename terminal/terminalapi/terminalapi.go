@@ -54,3 +54,32 @@ type Terminal interface {
 	// the terminal isn't required anymore to return the screen to a sane state.
 	Close()
 }
+
+// AreaFlusher is implemented by terminals that can flush only a part of the
+// internal back buffer. This allows applications that embed termdash to
+// compose its output with their own rendering.
+type AreaFlusher interface {
+	// FlushArea flushes the cells of the internal back buffer that fall
+	// within the area to the terminal. Cells outside of the area that were
+	// modified since they were last flushed remain pending until the next
+	// call to Flush or FlushArea that covers them.
+	FlushArea(ar image.Rectangle) error
+}
+
+// FrameCell is the content of a single cell of a frame.
+type FrameCell struct {
+	// Rune is the rune in the cell.
+	Rune rune
+	// Opts are the options of the cell.
+	Opts cell.Options
+}
+
+// FrameReader is implemented by terminals that can return the content of the
+// frame last flushed to the terminal, e.g. in order to take a screenshot.
+type FrameReader interface {
+	// Frame returns the cells of the frame last flushed to the terminal
+	// indexed as [x][y]. Full-width runes occupy two cells, the content of
+	// the second cell is undefined.
+	// Safe to call concurrently with drawing onto the terminal.
+	Frame() ([][]FrameCell, error)
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wsterm
+
+// page.go serves the xterm.js frontend.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Versions of the xterm.js packages loaded by the page.
+const (
+	xtermVersion    = "5.3.0"
+	xtermFitVersion = "0.8.0"
+)
+
+// pageTmpl is the HTML page with the frontend. Formatted with the versions of
+// xterm.js and xterm-addon-fit and the JSON encoded path of the WebSocket.
+const pageTmpl = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>termdash</title>
+<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/xterm@%[1]s/css/xterm.css">
+<script src="https://cdn.jsdelivr.net/npm/xterm@%[1]s/lib/xterm.js"></script>
+<script src="https://cdn.jsdelivr.net/npm/xterm-addon-fit@%[2]s/lib/xterm-addon-fit.js"></script>
+<style>
+html, body, #terminal { margin: 0; width: 100%%; height: 100%%; background: #000; overflow: hidden; }
+</style>
+</head>
+<body>
+<div id="terminal"></div>
+<script>
+const term = new Terminal();
+const fit = new FitAddon.FitAddon();
+term.loadAddon(fit);
+term.open(document.getElementById("terminal"));
+fit.fit();
+
+const proto = location.protocol === "https:" ? "wss:" : "ws:";
+const url = proto + "//" + location.host + %[3]s + "?cols=" + term.cols + "&rows=" + term.rows;
+const ws = new WebSocket(url);
+ws.binaryType = "arraybuffer";
+const enc = new TextEncoder();
+
+function send(type, data) {
+  if (ws.readyState !== WebSocket.OPEN) {
+    return;
+  }
+  const payload = typeof data === "string" ? enc.encode(data) : data;
+  const msg = new Uint8Array(payload.length + 1);
+  msg[0] = type.charCodeAt(0);
+  msg.set(payload, 1);
+  ws.send(msg);
+}
+
+ws.onmessage = (e) => term.write(new Uint8Array(e.data));
+ws.onclose = () => term.write("\r\n[disconnected]\r\n");
+term.onData((data) => send("0", data));
+term.onBinary((data) => send("0", Uint8Array.from(data, (c) => c.charCodeAt(0))));
+term.onResize((size) => send("1", JSON.stringify({cols: size.cols, rows: size.rows})));
+window.addEventListener("resize", () => fit.fit());
+term.focus();
+</script>
+</body>
+</html>
+`
+
+// Page returns an http.Handler that serves the xterm.js frontend. The page
+// connects to the WebSocket served by Handler at the provided path on the
+// same host. The xterm.js library is loaded from the jsdelivr CDN.
+func Page(wsPath string) http.Handler {
+	// Marshaling a string cannot fail. The result is also safe to embed in
+	// the script, since json escapes '<', '>' and '&'.
+	path, _ := json.Marshal(wsPath)
+	page := fmt.Sprintf(pageTmpl, xtermVersion, xtermFitVersion, path)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+}
@@ -0,0 +1,264 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wsterm
+
+// websocket.go implements the server side of the WebSocket protocol as
+// defined in RFC 6455, limited to what the terminal needs.
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// acceptGUID is appended to the key of the client when computing the accept
+// key of the handshake.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize is the largest message accepted from the client.
+const maxMessageSize = 1 << 20
+
+// Opcodes of the WebSocket frames.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// acceptKey computes the value of the Sec-WebSocket-Accept header.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains determines if the comma separated values of the header
+// contain the token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin determines if the request either has no Origin header or
+// originates from the host it was sent to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// conn is a server side WebSocket connection.
+type conn struct {
+	c  net.Conn
+	rw *bufio.ReadWriter
+
+	// wmu serializes the writes of frames.
+	wmu sync.Mutex
+}
+
+// upgrade performs the WebSocket handshake and takes over the connection.
+// Replies with an HTTP error and returns an error if the request isn't a
+// valid WebSocket handshake.
+func upgrade(w http.ResponseWriter, r *http.Request, checkOrigin func(*http.Request) bool) (*conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "the WebSocket handshake must use GET", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("unsupported method %q", r.Method)
+	case !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket"):
+		http.Error(w, "expected a WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("the request isn't a WebSocket handshake")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("unsupported WebSocket version %q", r.Header.Get("Sec-WebSocket-Version"))
+	case key == "":
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	case !checkOrigin(r):
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("origin %q not allowed", r.Header.Get("Origin"))
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "the server doesn't support WebSocket", http.StatusInternalServerError)
+		return nil, errors.New("the http.ResponseWriter doesn't implement http.Hijacker")
+	}
+	c, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("Hijack => %v", err)
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := rw.Flush(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return &conn{c: c, rw: rw}, nil
+}
+
+// writeFrame writes one unfragmented frame. Frames sent by the server aren't
+// masked.
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	hdr := []byte{0x80 | op} // FIN.
+	switch n := len(payload); {
+	case n <= 125:
+		hdr = append(hdr, byte(n))
+	case n <= 0xffff:
+		hdr = append(hdr, 126, byte(n>>8), byte(n))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		hdr = append(append(hdr, 127), ext[:]...)
+	}
+	if _, err := c.rw.Write(hdr); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readFrame reads one frame sent by the client and unmasks its payload.
+func (c *conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.rw, hdr[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = hdr[0]&0x80 != 0
+	op = hdr[0] & 0x0f
+	if hdr[1]&0x80 == 0 {
+		return false, 0, nil, errors.New("the client sent an unmasked frame")
+	}
+
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxMessageSize {
+		return false, 0, nil, fmt.Errorf("frame of %d bytes exceeds the maximum of %d bytes", n, maxMessageSize)
+	}
+	if op >= opClose && (!fin || n > 125) {
+		return false, 0, nil, errors.New("invalid control frame")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// readMessage reads the next text or binary message, reassembling fragmented
+// messages and answering the control frames. Returns io.EOF when the client
+// closes the connection.
+func (c *conn) readMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opClose:
+			// Echo the status code, if any.
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			c.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opText, opBinary:
+			if started {
+				return nil, errors.New("new message started before the previous one finished")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, errors.New("continuation frame without a message")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported opcode %#x", op)
+		}
+
+		if len(msg)+len(payload) > maxMessageSize {
+			return nil, fmt.Errorf("message exceeds the maximum of %d bytes", maxMessageSize)
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// close closes the connection, attempting to tell the client first.
+func (c *conn) close() error {
+	c.writeFrame(opClose, []byte{0x03, 0xe8}) // 1000, normal closure.
+	return c.c.Close()
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package wsterm implements a terminal that displays a dashboard in a browser.
+The terminal byte stream is carried over a WebSocket to an xterm.js frontend,
+which sends the keyboard and mouse input and the window size back.
+
+Serve the page with the frontend and the WebSocket endpoint, one terminal and
+one dashboard are created for each connection:
+
+	http.Handle("/", wsterm.Page("/ws"))
+	http.Handle("/ws", wsterm.Handler(func(ctx context.Context, t *wsterm.Terminal) {
+		c, err := newDashboard(t) // Builds the container and the widgets.
+		if err != nil {
+			return
+		}
+		termdash.Run(ctx, t, c)
+	}))
+	log.Fatal(http.ListenAndServe(":8080", nil))
+
+The protocol between the frontend and the server:
+  - The server sends binary messages with the output of the terminal.
+  - The client sends messages starting with '0' followed by the input, i.e.
+    the data reported by the onData event of xterm.js.
+  - The client sends messages starting with '1' followed by the new window
+    size as JSON, e.g. {"cols":80,"rows":24}.
+  - The initial window size is provided as the "cols" and "rows" query
+    parameters of the WebSocket URL.
+*/
+package wsterm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/mum4k/termdash/private/remotetty"
+	tdtcell "github.com/mum4k/termdash/terminal/tcell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	checkOrigin func(*http.Request) bool
+	termOpts    []tdtcell.Option
+	defaultSize image.Point
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		checkOrigin: sameOrigin,
+		defaultSize: DefaultSize,
+	}
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// CheckOrigin sets the function that decides if a WebSocket connection from
+// the origin of the request is allowed. Anyone allowed to connect controls
+// the dashboard.
+// Defaults to allowing only requests without the Origin header or from the
+// same host.
+func CheckOrigin(f func(r *http.Request) bool) Option {
+	return option(func(opts *options) {
+		opts.checkOrigin = f
+	})
+}
+
+// TerminalOptions sets options applied to the tcell terminal created for
+// each connection, e.g. tcell.ColorMode.
+// Defaults to no options.
+func TerminalOptions(termOpts ...tdtcell.Option) Option {
+	return option(func(opts *options) {
+		opts.termOpts = termOpts
+	})
+}
+
+// DefaultSize is the default value for the InitialSize option.
+var DefaultSize = image.Point{80, 24}
+
+// InitialSize sets the size of the terminal used when the client doesn't
+// provide the "cols" and "rows" query parameters.
+// Defaults to DefaultSize.
+func InitialSize(size image.Point) Option {
+	return option(func(opts *options) {
+		opts.defaultSize = size
+	})
+}
+
+// Message types sent by the client.
+const (
+	msgInput  = '0'
+	msgResize = '1'
+)
+
+// resizeMsg is the payload of a resize message.
+type resizeMsg struct {
+	Cols int `json:"cols"`
+	Rows int `json:"rows"`
+}
+
+// stream is the byte stream of the terminal carried by the WebSocket.
+// Implements io.ReadWriter.
+type stream struct {
+	c *conn
+	// resize is called when the client reports a new window size.
+	resize func(image.Point)
+	// pending is the input that wasn't read yet.
+	pending []byte
+}
+
+// Read implements io.Reader.Read.
+func (s *stream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		msg, err := s.c.readMessage()
+		if err != nil {
+			return 0, err
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		switch msg[0] {
+		case msgInput:
+			s.pending = msg[1:]
+		case msgResize:
+			var rm resizeMsg
+			if err := json.Unmarshal(msg[1:], &rm); err != nil || rm.Cols <= 0 || rm.Rows <= 0 {
+				// Ignore invalid sizes instead of disconnecting.
+				continue
+			}
+			s.resize(image.Point{rm.Cols, rm.Rows})
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Write implements io.Writer.Write.
+func (s *stream) Write(p []byte) (int, error) {
+	if err := s.c.writeFrame(opBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Terminal is a tcell based terminal that draws on a WebSocket connection.
+// Implements terminalapi.Terminal.
+type Terminal struct {
+	*tdtcell.Terminal
+
+	tty *remotetty.TTY
+	// closeOnce ensures the terminal is closed only once.
+	closeOnce sync.Once
+}
+
+// Done returns a channel that gets closed when the client disconnects.
+func (t *Terminal) Done() <-chan struct{} {
+	return t.tty.EOF()
+}
+
+// Close closes the terminal.
+// Implements terminalapi.Terminal.Close.
+func (t *Terminal) Close() {
+	t.closeOnce.Do(func() {
+		t.Terminal.Close()
+		t.tty.StopReading()
+	})
+}
+
+// querySize returns the window size from the query parameters of the
+// request, or def if they are missing or invalid.
+func querySize(r *http.Request, def image.Point) image.Point {
+	cols, err := strconv.Atoi(r.URL.Query().Get("cols"))
+	if err != nil || cols <= 0 {
+		return def
+	}
+	rows, err := strconv.Atoi(r.URL.Query().Get("rows"))
+	if err != nil || rows <= 0 {
+		return def
+	}
+	return image.Point{cols, rows}
+}
+
+// Handler returns an http.Handler that accepts WebSocket connections from
+// the frontend and calls serve with a new terminal for each of them. The
+// context passed to serve is derived from the context of the request and gets
+// canceled when the client disconnects. The terminal and the connection are
+// closed when serve returns.
+func Handler(serve func(ctx context.Context, t *Terminal), opts ...Option) http.Handler {
+	o := newOptions()
+	for _, opt := range opts {
+		opt.set(o)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrade(w, r, o.checkOrigin)
+		if err != nil {
+			return
+		}
+		defer c.close()
+
+		s := &stream{c: c}
+		tty := remotetty.New(s, querySize(r, o.defaultSize))
+		// The stream is only read once the terminal starts the TTY.
+		s.resize = tty.Resize
+		tt, err := remotetty.NewTerminal(tty, remotetty.DefaultTerm, o.termOpts...)
+		if err != nil {
+			tty.StopReading()
+			fmt.Fprintf(s, "\r\nfailed to create the terminal: %v\r\n", err)
+			return
+		}
+		t := &Terminal{
+			Terminal: tt,
+			tty:      tty,
+		}
+		defer t.Close()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-t.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		serve(ctx, t)
+	})
+}
@@ -0,0 +1,409 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wsterm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// client is a minimal WebSocket client.
+type client struct {
+	c  net.Conn
+	br *bufio.Reader
+
+	mu     sync.Mutex
+	out    bytes.Buffer
+	pongs  [][]byte
+	closed bool
+}
+
+// dial performs the handshake with the server and starts reading the frames
+// it sends.
+func dial(t *testing.T, srv *httptest.Server, query string) *client {
+	t.Helper()
+	c, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial => unexpected error: %v", err)
+	}
+	fmt.Fprintf(c, "GET /?%s HTTP/1.1\r\nHost: %s\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n", query, srv.Listener.Addr())
+	br := bufio.NewReader(c)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse => unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake => status %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Fatalf("handshake => Sec-WebSocket-Accept %q, want %q", got, want)
+	}
+
+	cl := &client{c: c, br: br}
+	go cl.readLoop()
+	return cl
+}
+
+// readLoop reads the frames sent by the server until the connection closes.
+func (cl *client) readLoop() {
+	for {
+		var hdr [2]byte
+		if _, err := io.ReadFull(cl.br, hdr[:]); err != nil {
+			break
+		}
+		n := uint64(hdr[1] & 0x7f)
+		switch n {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(cl.br, ext[:]); err != nil {
+				break
+			}
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(cl.br, ext[:]); err != nil {
+				break
+			}
+			n = binary.BigEndian.Uint64(ext[:])
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(cl.br, payload); err != nil {
+			break
+		}
+
+		cl.mu.Lock()
+		switch hdr[0] & 0x0f {
+		case opBinary:
+			cl.out.Write(payload)
+		case opPong:
+			cl.pongs = append(cl.pongs, payload)
+		case opClose:
+			cl.closed = true
+		}
+		cl.mu.Unlock()
+	}
+	cl.mu.Lock()
+	cl.closed = true
+	cl.mu.Unlock()
+}
+
+// send sends a masked frame to the server.
+func (cl *client) send(t *testing.T, op byte, payload []byte) {
+	t.Helper()
+	if len(payload) > 125 {
+		t.Fatalf("send => payload of %d bytes too long for the test client", len(payload))
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | op, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := cl.c.Write(frame); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+}
+
+// output returns everything the server sent so far.
+func (cl *client) output() string {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.out.String()
+}
+
+// waitFor polls the condition until it is true or the timeout expires.
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	tests := []struct {
+		desc       string
+		header     map[string]string
+		opts       []Option
+		wantStatus int
+	}{
+		{
+			desc:       "rejects requests that aren't a WebSocket handshake",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "rejects unsupported versions",
+			header: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "8",
+				"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+			},
+			wantStatus: http.StatusUpgradeRequired,
+		},
+		{
+			desc: "rejects a missing key",
+			header: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "13",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "rejects other origins by default",
+			header: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "13",
+				"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+				"Origin":                "http://evil.example.com",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			desc: "applies the CheckOrigin option",
+			header: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "13",
+				"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+			},
+			opts: []Option{
+				CheckOrigin(func(*http.Request) bool { return false }),
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			srv := httptest.NewServer(Handler(func(context.Context, *Terminal) {
+				t.Errorf("Handler => unexpectedly called serve")
+			}, tc.opts...))
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if err != nil {
+				t.Fatalf("NewRequest => unexpected error: %v", err)
+			}
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do => unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("Do => status %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestQuerySize(t *testing.T) {
+	tests := []struct {
+		desc  string
+		query string
+		want  image.Point
+	}{
+		{
+			desc: "defaults without parameters",
+			want: image.Point{80, 24},
+		},
+		{
+			desc:  "uses the parameters",
+			query: "cols=100&rows=30",
+			want:  image.Point{100, 30},
+		},
+		{
+			desc:  "defaults on invalid parameters",
+			query: "cols=100&rows=-1",
+			want:  image.Point{80, 24},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			if got := querySize(r, DefaultSize); got != tc.want {
+				t.Errorf("querySize => %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	terms := make(chan *Terminal, 1)
+	served := make(chan struct{})
+	srv := httptest.NewServer(Handler(func(sctx context.Context, term *Terminal) {
+		defer close(served)
+		terms <- term
+		<-sctx.Done()
+	}))
+	defer srv.Close()
+
+	cl := dial(t, srv, "cols=10&rows=3")
+	defer cl.c.Close()
+	var term *Terminal
+	select {
+	case term = <-terms:
+	case <-ctx.Done():
+		t.Fatalf("Handler => serve not called")
+	}
+
+	if got, want := term.Size(), (image.Point{10, 3}); got != want {
+		t.Errorf("Size => %v, want %v", got, want)
+	}
+
+	// Draws on the WebSocket.
+	if err := term.SetCell(image.Point{0, 0}, 'x'); err != nil {
+		t.Fatalf("SetCell => unexpected error: %v", err)
+	}
+	if err := term.Flush(); err != nil {
+		t.Fatalf("Flush => unexpected error: %v", err)
+	}
+	waitFor(t, "the drawn cell", func() bool {
+		return strings.Contains(cl.output(), "x")
+	})
+
+	// Reads the input of the client.
+	cl.send(t, opBinary, []byte("0a"))
+	var got terminalapi.Event
+	for got == nil {
+		ev := term.Event(ctx)
+		if ev == nil {
+			t.Fatalf("Event => timed out waiting for the keyboard event")
+		}
+		if _, ok := ev.(*terminalapi.Keyboard); ok {
+			got = ev
+		}
+	}
+	if k := got.(*terminalapi.Keyboard).Key; k != keyboard.Key('a') {
+		t.Errorf("Event => key %v, want %v", k, keyboard.Key('a'))
+	}
+
+	// Follows the window changes, ignoring invalid ones.
+	cl.send(t, opBinary, []byte(`1{"cols":0,"rows":5}`))
+	cl.send(t, opBinary, []byte(`1{"cols":20,"rows":5}`))
+	waitFor(t, "the resize", func() bool {
+		return term.Size() == image.Point{20, 5}
+	})
+
+	// Answers pings.
+	cl.send(t, opPing, []byte("hi"))
+	waitFor(t, "the pong", func() bool {
+		cl.mu.Lock()
+		defer cl.mu.Unlock()
+		return len(cl.pongs) == 1 && string(cl.pongs[0]) == "hi"
+	})
+
+	// Reports the disconnect and cancels the context of serve.
+	select {
+	case <-term.Done():
+		t.Fatalf("Done => closed before the client disconnected")
+	default:
+	}
+	cl.send(t, opClose, nil)
+	select {
+	case <-term.Done():
+	case <-ctx.Done():
+		t.Fatalf("Done => not closed after the client disconnected")
+	}
+	select {
+	case <-served:
+	case <-ctx.Done():
+		t.Fatalf("Handler => serve didn't return after the client disconnected")
+	}
+}
+
+func TestEarlyResize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	terms := make(chan *Terminal, 1)
+	srv := httptest.NewServer(Handler(func(sctx context.Context, term *Terminal) {
+		terms <- term
+		<-sctx.Done()
+	}))
+	defer srv.Close()
+
+	// The resize is sent before the terminal is created.
+	cl := dial(t, srv, "cols=10&rows=3")
+	defer cl.c.Close()
+	cl.send(t, opBinary, []byte(`1{"cols":20,"rows":5}`))
+
+	var term *Terminal
+	select {
+	case term = <-terms:
+	case <-ctx.Done():
+		t.Fatalf("Handler => serve not called")
+	}
+	waitFor(t, "the resize", func() bool {
+		return term.Size() == image.Point{20, 5}
+	})
+	cl.send(t, opClose, nil)
+}
+
+func TestPage(t *testing.T) {
+	srv := httptest.NewServer(Page("/ws</script>"))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get => unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll => unexpected error: %v", err)
+	}
+
+	if got, want := resp.Header.Get("Content-Type"), "text/html; charset=utf-8"; got != want {
+		t.Errorf("Content-Type => %q, want %q", got, want)
+	}
+	for _, want := range []string{
+		"xterm@" + xtermVersion,
+		"xterm-addon-fit@" + xtermFitVersion,
+		`"/ws\u003c/script\u003e"`,
+		"width: 100%;",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Page => missing %q in:\n%s", want, body)
+		}
+	}
+}
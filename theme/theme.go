@@ -0,0 +1,118 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package theme defines palettes of colors used across the dashboard.
+//
+// A theme provides the default colors for the container borders and for the
+// widgets. Colors explicitly set via options of the containers or the
+// widgets always take precedence over the theme.
+package theme
+
+import "github.com/mum4k/termdash/cell"
+
+// Theme is a named palette of colors.
+//
+// Themes don't change the background color of the terminal, palettes for
+// light and dark backgrounds are meant to be used on terminals with a
+// matching background color.
+type Theme struct {
+	// Name is the human readable name of the theme.
+	Name string
+
+	// Primary is the color of the main data, e.g. the bars of a BarChart or
+	// the filled part of a Gauge.
+	Primary cell.Color
+	// Accent is the color used to highlight secondary information, e.g. the
+	// values displayed on the bars of a BarChart.
+	Accent cell.Color
+	// Label is the color of labels, e.g. the labels on the axes of a
+	// LineChart or under the bars of a BarChart.
+	Label cell.Color
+	// Axis is the color of the axes of charts.
+	Axis cell.Color
+	// Border is the color of the container borders.
+	Border cell.Color
+	// FocusedBorder is the color of the border of the focused container.
+	FocusedBorder cell.Color
+	// Success is the color indicating a positive change, e.g. an increasing
+	// trend of a Gauge.
+	Success cell.Color
+	// Error is the color indicating a problem or a negative change, e.g. a
+	// decreasing trend of a Gauge.
+	Error cell.Color
+}
+
+// Default returns a theme close to the colors termdash uses when no theme is
+// provided. Unlike without a theme, all the widgets draw their main data in
+// the same color.
+func Default() *Theme {
+	return &Theme{
+		Name:          "Default",
+		Primary:       cell.ColorGreen,
+		Accent:        cell.ColorYellow,
+		Label:         cell.ColorGreen,
+		Axis:          cell.ColorDefault,
+		Border:        cell.ColorDefault,
+		FocusedBorder: cell.ColorYellow,
+		Success:       cell.ColorGreen,
+		Error:         cell.ColorRed,
+	}
+}
+
+// Solarized colors, see https://ethanschoonover.com/solarized/.
+var (
+	solarizedBase01  = cell.ColorRGB24(0x58, 0x6e, 0x75)
+	solarizedBase00  = cell.ColorRGB24(0x65, 0x7b, 0x83)
+	solarizedBase0   = cell.ColorRGB24(0x83, 0x94, 0x96)
+	solarizedBase1   = cell.ColorRGB24(0x93, 0xa1, 0xa1)
+	solarizedYellow  = cell.ColorRGB24(0xb5, 0x89, 0x00)
+	solarizedOrange  = cell.ColorRGB24(0xcb, 0x4b, 0x16)
+	solarizedRed     = cell.ColorRGB24(0xdc, 0x32, 0x2f)
+	solarizedBlue    = cell.ColorRGB24(0x26, 0x8b, 0xd2)
+	solarizedCyan    = cell.ColorRGB24(0x2a, 0xa1, 0x98)
+	solarizedGreen   = cell.ColorRGB24(0x85, 0x99, 0x00)
+	solarizedMagenta = cell.ColorRGB24(0xd3, 0x36, 0x82)
+)
+
+// SolarizedDark returns the Solarized palette for terminals with a dark
+// background.
+func SolarizedDark() *Theme {
+	return &Theme{
+		Name:          "SolarizedDark",
+		Primary:       solarizedBlue,
+		Accent:        solarizedYellow,
+		Label:         solarizedBase1,
+		Axis:          solarizedBase01,
+		Border:        solarizedBase01,
+		FocusedBorder: solarizedCyan,
+		Success:       solarizedGreen,
+		Error:         solarizedRed,
+	}
+}
+
+// SolarizedLight returns the Solarized palette for terminals with a light
+// background.
+func SolarizedLight() *Theme {
+	return &Theme{
+		Name:          "SolarizedLight",
+		Primary:       solarizedBlue,
+		Accent:        solarizedOrange,
+		Label:         solarizedBase00,
+		Axis:          solarizedBase0,
+		Border:        solarizedBase0,
+		FocusedBorder: solarizedMagenta,
+		Success:       solarizedGreen,
+		Error:         solarizedRed,
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgetapi
+
+// mouse.go contains helpers for widgets that process mouse events.
+
+import (
+	"image"
+
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// LocalMouse translates the position of the mouse event into coordinates
+// relative to the provided area, i.e. the point at ar.Min becomes
+// image.Point{0, 0}.
+//
+// The area is in the same coordinate system as the mouse event. This is useful
+// when the widget divides its canvas into sub-areas (e.g. the space within a
+// border or padding) and needs to know where in the sub-area the event landed.
+//
+// Returns the translated point and a bool indicating whether the event falls
+// within the area. If the event falls outside the area, the returned point is
+// image.Point{-1, -1}.
+func LocalMouse(ar image.Rectangle, m *terminalapi.Mouse) (image.Point, bool) {
+	if !m.Position.In(ar) {
+		return image.Point{-1, -1}, false
+	}
+	return m.Position.Sub(ar.Min), true
+}
+
+// RuneAtColumn returns the index of the rune in the provided text that
+// occupies the specified cell column when the text is drawn starting at column
+// zero. Full-width runes occupy two cells, so both of their cells map to the
+// same rune index.
+//
+// The returned index counts runes, not bytes. Returns false if the column
+// falls outside of the drawn text.
+func RuneAtColumn(text string, col int) (int, bool) {
+	if col < 0 {
+		return -1, false
+	}
+
+	cur := 0
+	idx := 0
+	for _, r := range text {
+		rw := runewidth.RuneWidth(r)
+		if col >= cur && col < cur+rw {
+			return idx, true
+		}
+		cur += rw
+		idx++
+	}
+	return -1, false
+}
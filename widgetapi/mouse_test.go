@@ -0,0 +1,141 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package widgetapi
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestLocalMouse(t *testing.T) {
+	tests := []struct {
+		desc   string
+		ar     image.Rectangle
+		m      *terminalapi.Mouse
+		want   image.Point
+		wantIn bool
+	}{
+		{
+			desc:   "zero based area",
+			ar:     image.Rect(0, 0, 3, 3),
+			m:      &terminalapi.Mouse{Position: image.Point{1, 2}, Button: mouse.ButtonLeft},
+			want:   image.Point{1, 2},
+			wantIn: true,
+		},
+		{
+			desc:   "area with an offset",
+			ar:     image.Rect(2, 3, 5, 6),
+			m:      &terminalapi.Mouse{Position: image.Point{2, 5}, Button: mouse.ButtonLeft},
+			want:   image.Point{0, 2},
+			wantIn: true,
+		},
+		{
+			desc: "event left of the area",
+			ar:   image.Rect(2, 3, 5, 6),
+			m:    &terminalapi.Mouse{Position: image.Point{1, 3}, Button: mouse.ButtonLeft},
+			want: image.Point{-1, -1},
+		},
+		{
+			desc: "event at the exclusive maximum of the area",
+			ar:   image.Rect(2, 3, 5, 6),
+			m:    &terminalapi.Mouse{Position: image.Point{5, 6}, Button: mouse.ButtonLeft},
+			want: image.Point{-1, -1},
+		},
+		{
+			desc: "event already outside of the canvas",
+			ar:   image.Rect(0, 0, 3, 3),
+			m:    &terminalapi.Mouse{Position: image.Point{-1, -1}, Button: mouse.ButtonLeft},
+			want: image.Point{-1, -1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotIn := LocalMouse(tc.ar, tc.m)
+			if got != tc.want || gotIn != tc.wantIn {
+				t.Errorf("LocalMouse => (%v, %v), want (%v, %v)", got, gotIn, tc.want, tc.wantIn)
+			}
+		})
+	}
+}
+
+func TestRuneAtColumn(t *testing.T) {
+	tests := []struct {
+		desc   string
+		text   string
+		col    int
+		want   int
+		wantOK bool
+	}{
+		{
+			desc: "empty text",
+			text: "",
+			col:  0,
+			want: -1,
+		},
+		{
+			desc: "negative column",
+			text: "abc",
+			col:  -1,
+			want: -1,
+		},
+		{
+			desc:   "half-width runes",
+			text:   "abc",
+			col:    2,
+			want:   2,
+			wantOK: true,
+		},
+		{
+			desc: "column after the text",
+			text: "abc",
+			col:  3,
+			want: -1,
+		},
+		{
+			desc:   "first cell of a full-width rune",
+			text:   "a世b",
+			col:    1,
+			want:   1,
+			wantOK: true,
+		},
+		{
+			desc:   "second cell of a full-width rune",
+			text:   "a世b",
+			col:    2,
+			want:   1,
+			wantOK: true,
+		},
+		{
+			desc:   "rune after a full-width rune",
+			text:   "a世b",
+			col:    3,
+			want:   2,
+			wantOK: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotOK := RuneAtColumn(tc.text, tc.col)
+			if got != tc.want || gotOK != tc.wantOK {
+				t.Errorf("RuneAtColumn => (%v, %v), want (%v, %v)", got, gotOK, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
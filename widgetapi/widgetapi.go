@@ -20,6 +20,7 @@ import (
 
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 )
 
 // KeyScope indicates the scope at which the widget wants to receive keyboard
@@ -150,6 +151,25 @@ type Options struct {
 type Meta struct {
 	// Focused asserts whether the widget's container is focused.
 	Focused bool
+
+	// Theme is the theme of the dashboard or nil if no theme was provided.
+	// Widgets should use its colors instead of their defaults for anything
+	// the user didn't explicitly configure.
+	Theme *theme.Theme
+
+	// Graphics is the graphics protocol the terminal uses to display images
+	// or terminalapi.GraphicsNone if it cannot display them. Widgets can
+	// display images by setting escape sequences of this protocol on the
+	// canvas, see Canvas.SetPassthrough.
+	Graphics terminalapi.GraphicsProtocol
+
+	// Area is the area of the terminal the canvas is displayed at, i.e. the
+	// point image.Point{0, 0} of the canvas is displayed at Area.Min. Widgets
+	// can use it to position content displayed above the layout next to
+	// themselves, see container.ShowLayer. Parts of the area aren't visible if
+	// the widget is in the content of a container with the Scrollable option
+	// that is scrolled.
+	Area image.Rectangle
 }
 
 // EventMeta provides additional metadata about events to widgets.
@@ -200,3 +220,53 @@ type Widget interface {
 	// Draw.
 	Options() Options
 }
+
+// Paster is an optional interface implemented by widgets that accept pasted
+// text as a whole, e.g. to insert it into a text field in a single edit.
+//
+// Pasted text is delivered to the widgets that would receive a keyboard
+// event at the same time. Widgets that don't implement this interface receive
+// the pasted text as a sequence of keyboard events instead, one for each
+// rune, with line breaks delivered as keyboard.KeyEnter.
+type Paster interface {
+	Widget
+
+	// Paste is called with the pasted text.
+	//
+	// The argument meta is guaranteed to be valid (i.e. non-nil).
+	Paste(p *terminalapi.Paste, meta *EventMeta) error
+}
+
+// DirtyReporter is an optional interface implemented by widgets that report
+// when their content changes, e.g. after the application provided new data or
+// when an animation advances. This allows the infrastructure to redraw
+// promptly the dashboards that changed, without waiting for the next periodic
+// redraw, and with the termdash.OnDemandRedraw option to skip redrawing the
+// ones that didn't. Widgets that don't implement this interface are assumed
+// to change all the time.
+//
+// The infrastructure provides the function before the widget is drawn for the
+// first time after it was placed into a container. The function can be
+// provided again, in which case the widget must only use the most recently
+// provided one.
+//
+// The widget calls the function whenever its content changes. The function
+// is thread-safe, never blocks and can be called while the widget holds its
+// own locks. It doesn't draw the widget, the Draw method is called later from
+// a different goroutine, and multiple changes reported before the redraw
+// result in a single redraw. A widget whose content keeps changing over time
+// calls the function from within the Draw method, the next frame is then drawn
+// after the redraw interval instead of immediately. Changes caused by
+// keyboard, mouse or paste events don't need to be reported, since each such
+// event is followed by a redraw.
+//
+// The Draw method of such widget isn't called again until the widget reports
+// a change, receives an event, its area, focus or theme changes or the
+// application marks the container dirty. Its content drawn last is displayed
+// instead.
+type DirtyReporter interface {
+	Widget
+
+	// SetDirtyFunc provides the function that marks the widget as dirty.
+	SetDirtyFunc(markDirty func())
+}
@@ -25,11 +25,14 @@ import (
 
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -43,12 +46,28 @@ type BarChart struct {
 	// values are the values provided on a call to Values(). These are the
 	// individual bars that will be drawn.
 	values []int
+	// series are the series provided on a call to SeriesValues(). Empty when
+	// the values were provided by a call to Values().
+	series []Series
 	// max is the maximum value of a bar. A bar having this value takes all the
 	// vertical space.
 	max int
 
 	// lastWidth is the width of the canvas as of the last time when Draw was called.
 	lastWidth int
+	// lastHeight is the height of the canvas as of the last time when Draw
+	// was called.
+	lastHeight int
+
+	// offset is the index of the leftmost displayed bar when the bars are
+	// scrolled, see the Scrollable option.
+	offset int
+	// visible is the number of bars that fit the canvas as of the last time
+	// when Draw was called. Only populated with the Scrollable option.
+	visible int
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
 
 	// mu protects the BarChart.
 	mu sync.Mutex
@@ -66,9 +85,16 @@ func New(opts ...Option) (*BarChart, error) {
 	if err := opt.validate(); err != nil {
 		return nil, err
 	}
-	return &BarChart{
+
+	bc := &BarChart{
 		opts: opt,
-	}, nil
+	}
+	if iv := opt.initial; iv != nil {
+		if err := bc.Values(iv.values, iv.max); err != nil {
+			return nil, err
+		}
+	}
+	return bc, nil
 }
 
 // Draw draws the BarChart widget onto the canvas.
@@ -78,6 +104,7 @@ func (bc *BarChart) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	defer bc.mu.Unlock()
 
 	bc.lastWidth = cvs.Area().Dx()
+	bc.lastHeight = cvs.Area().Dy()
 	needAr, err := area.FromSize(bc.minSize())
 	if err != nil {
 		return err
@@ -86,37 +113,111 @@ func (bc *BarChart) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return draw.ResizeNeeded(cvs)
 	}
 
-	for i, v := range bc.values {
-		r, err := bc.barRect(cvs, i, v)
-		if err != nil {
-			return err
+	var th *theme.Theme
+	if meta != nil {
+		th = meta.Theme
+	}
+	if bc.opts.horizontal {
+		return bc.drawHorizontal(cvs, th)
+	}
+
+	if err := bc.drawGrid(cvs); err != nil {
+		return err
+	}
+	if err := bc.drawLegend(cvs, th); err != nil {
+		return err
+	}
+	first, last := 0, len(bc.values)
+	if bc.opts.scrollable {
+		bc.visible = valueCapacity(float64(bc.opts.barWidth), float64(bc.opts.barGap), float64(bc.lastWidth))
+		bc.clampOffset()
+		first = bc.offset
+		if end := first + bc.visible; end < last {
+			last = end
 		}
+	}
 
-		if r.Dy() > 0 { // Value might be so small so that the rectangle is zero.
-			if err := draw.Rectangle(cvs, r,
-				draw.RectCellOpts(cell.BgColor(bc.barColor(i))),
-				draw.RectChar(bc.opts.barChar),
-			); err != nil {
-				return err
-			}
+	for i := first; i < last; i++ {
+		if err := bc.drawBar(cvs, i, th); err != nil {
+			return err
 		}
 
-		if bc.opts.showValues {
-			if err := bc.drawText(cvs, i, fmt.Sprint(bc.values[i]), bc.valColor(i), insideBar); err != nil {
+		l, c := bc.label(i, th)
+		switch {
+		case l == "":
+		case bc.opts.rotateLabels:
+			if err := bc.drawRotatedLabel(cvs, i, l, c); err != nil {
 				return err
 			}
-		}
-
-		l, c := bc.label(i)
-		if l != "" {
+		default:
 			if err := bc.drawText(cvs, i, l, c, underBar); err != nil {
 				return err
 			}
 		}
 	}
+
+	if bc.opts.scrollable {
+		return bc.drawScrollRunes(cvs, first, last)
+	}
 	return nil
 }
 
+// drawBar draws the i-th bar and its value if requested by the options.
+func (bc *BarChart) drawBar(cvs *canvas.Canvas, i int, th *theme.Theme) error {
+	if len(bc.series) > 0 {
+		return bc.drawSeriesBar(cvs, i, th)
+	}
+
+	r, err := bc.barRect(cvs, i, bc.values[i])
+	if err != nil {
+		return err
+	}
+	if err := bc.drawSegment(cvs, r, bc.barColor(i, th)); err != nil {
+		return err
+	}
+
+	if bc.opts.showValues {
+		return bc.drawText(cvs, i, fmt.Sprint(bc.values[i]), bc.valColor(i, th), insideBar)
+	}
+	return nil
+}
+
+// drawScrollRunes draws the runes that indicate more bars are available to the
+// left or to the right of the displayed bars, which are the bars with indexes
+// in the range first <= i < last.
+func (bc *BarChart) drawScrollRunes(cvs *canvas.Canvas, first, last int) error {
+	ar := cvs.Area()
+	if first > 0 {
+		if _, err := cvs.SetCell(ar.Min, bc.opts.scrollLeftRune); err != nil {
+			return err
+		}
+	}
+	if last < len(bc.values) {
+		if _, err := cvs.SetCell(image.Point{ar.Max.X - 1, ar.Min.Y}, bc.opts.scrollRightRune); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clampOffset ensures the scroll offset doesn't leave empty space after the
+// last bar.
+func (bc *BarChart) clampOffset() {
+	if max := len(bc.values) - bc.visible; bc.offset > max {
+		bc.offset = max
+	}
+	if bc.offset < 0 {
+		bc.offset = 0
+	}
+}
+
+// scroll scrolls the bars by the specified number of bars, a negative number
+// scrolls to the left.
+func (bc *BarChart) scroll(bars int) {
+	bc.offset += bars
+	bc.clampOffset()
+}
+
 // textLoc represents the location of the drawn text.
 type textLoc int
 
@@ -176,11 +277,7 @@ func (bc *BarChart) barWidth(cvs *canvas.Canvas) int {
 
 // barHeight determines the height of the i-th bar based on the value it is displaying.
 func (bc *BarChart) barHeight(cvs *canvas.Canvas, i, value int) int {
-	available := cvs.Area().Dy()
-	if len(bc.opts.labels) > 0 {
-		// One line for the bar labels.
-		available--
-	}
+	available := cvs.Area().Dy() - bc.labelRows(cvs) - bc.baselineRows() - bc.legendRows()
 
 	ratio := float32(value) / float32(bc.max)
 	return int(float32(available) * ratio)
@@ -190,52 +287,161 @@ func (bc *BarChart) barHeight(cvs *canvas.Canvas, i, value int) int {
 // displays the specified value.
 func (bc *BarChart) barRect(cvs *canvas.Canvas, i, value int) (image.Rectangle, error) {
 	bw := bc.barWidth(cvs)
-	minX := bw * i
-	if i > 0 {
-		minX += bc.opts.barGap * i
+	// The position of the bar on the canvas, bars before the offset are
+	// scrolled out of view.
+	pos := i - bc.offset
+	minX := bw * pos
+	if pos > 0 {
+		minX += bc.opts.barGap * pos
 	}
 	maxX := minX + bw
 
 	bh := bc.barHeight(cvs, i, value)
-	maxY := cvs.Area().Max.Y
-	if len(bc.opts.labels) > 0 {
-		// One line for the bar labels.
-		maxY--
-	}
+	maxY := bc.barsMaxY(cvs)
 	minY := maxY - bh
 	return image.Rect(minX, minY, maxX, maxY), nil
 }
 
+// barsMaxY returns the Y coordinate just under the bars, which is the row of
+// the zero baseline if the Baseline option was provided.
+func (bc *BarChart) barsMaxY(cvs *canvas.Canvas) int {
+	return cvs.Area().Max.Y - bc.labelRows(cvs) - bc.baselineRows()
+}
+
+// baselineRows determines the number of rows reserved for the zero baseline
+// under the bars.
+func (bc *BarChart) baselineRows() int {
+	if bc.opts.baseline {
+		return 1
+	}
+	return 0
+}
+
+// drawGrid draws the grid lines and the zero baseline if requested by the
+// options. Must be called before the bars are drawn, so that the bars cover
+// the grid lines.
+func (bc *BarChart) drawGrid(cvs *canvas.Canvas) error {
+	if len(bc.values) == 0 {
+		return nil
+	}
+
+	maxY := bc.barsMaxY(cvs)
+	if bc.opts.baseline {
+		if err := bc.drawGridLine(cvs, maxY, bc.opts.baselineChar); err != nil {
+			return err
+		}
+	}
+
+	if bc.opts.gridStep == 0 {
+		return nil
+	}
+	for v := bc.opts.gridStep; v <= bc.max; v += bc.opts.gridStep {
+		h := bc.barHeight(cvs, 0, v)
+		if h == 0 {
+			continue // The value is too small to be displayed.
+		}
+		if err := bc.drawGridLine(cvs, maxY-h, bc.opts.gridChar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawGridLine draws a horizontal grid line across the canvas on the row with
+// the specified Y coordinate.
+func (bc *BarChart) drawGridLine(cvs *canvas.Canvas, y int, r rune) error {
+	ar := cvs.Area()
+	for x := ar.Min.X; x < ar.Max.X; x++ {
+		if _, err := cvs.SetCell(image.Point{x, y}, r, bc.opts.gridCellOpts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelRows determines the number of rows reserved for the bar labels at the
+// bottom of the canvas.
+func (bc *BarChart) labelRows(cvs *canvas.Canvas) int {
+	if len(bc.opts.labels) == 0 {
+		return 0
+	}
+	if !bc.opts.rotateLabels {
+		return 1 // One line for the bar labels.
+	}
+
+	longest := 1
+	for _, l := range bc.opts.labels {
+		if rows := len([]rune(l)); rows > longest {
+			longest = rows
+		}
+	}
+	// Always leave at least one row for the bars.
+	if max := cvs.Area().Dy() - 1; longest > max {
+		return max
+	}
+	return longest
+}
+
+// drawRotatedLabel draws the provided label vertically under the i-th bar.
+func (bc *BarChart) drawRotatedLabel(cvs *canvas.Canvas, i int, label string, color cell.Color) error {
+	r, err := bc.barRect(cvs, i, bc.max)
+	if err != nil {
+		return err
+	}
+
+	start := image.Point{r.Min.X + (r.Dx()-1)/2, r.Max.Y + bc.baselineRows()}
+	return draw.VerticalText(cvs, label, start,
+		draw.VerticalTextCellOpts(cell.FgColor(color)),
+		draw.VerticalTextMaxY(cvs.Area().Max.Y),
+		draw.VerticalTextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
 // barColor safely determines the color for the i-th bar.
-// Colors are optional and don't have to be specified for all the bars.
-func (bc *BarChart) barColor(i int) cell.Color {
-	if len(bc.opts.barColors) > i {
+// Colors are optional and don't have to be specified for all the bars, the
+// theme provides the color of the remaining bars if not nil.
+func (bc *BarChart) barColor(i int, th *theme.Theme) cell.Color {
+	switch {
+	case len(bc.opts.barColors) > i:
 		return bc.opts.barColors[i]
+	case th != nil:
+		return th.Primary
+	default:
+		return DefaultBarColor
 	}
-	return DefaultBarColor
 }
 
 // valColor safely determines the color for the i-th value.
-// Colors are optional and don't have to be specified for all the values.
-func (bc *BarChart) valColor(i int) cell.Color {
-	if len(bc.opts.valueColors) > i {
+// Colors are optional and don't have to be specified for all the values, the
+// theme provides the color of the remaining values if not nil.
+func (bc *BarChart) valColor(i int, th *theme.Theme) cell.Color {
+	switch {
+	case len(bc.opts.valueColors) > i:
 		return bc.opts.valueColors[i]
+	case th != nil:
+		return th.Accent
+	default:
+		return DefaultValueColor
 	}
-	return DefaultValueColor
 }
 
 // label safely determines the label and its color for the i-th bar.
-// Labels are optional and don't have to be specified for all the bars.
-func (bc *BarChart) label(i int) (string, cell.Color) {
+// Labels are optional and don't have to be specified for all the bars, the
+// theme provides the color of the remaining labels if not nil.
+func (bc *BarChart) label(i int, th *theme.Theme) (string, cell.Color) {
 	var label string
 	if len(bc.opts.labels) > i {
 		label = bc.opts.labels[i]
 	}
 
-	if len(bc.opts.labelColors) > i {
+	switch {
+	case len(bc.opts.labelColors) > i:
 		return label, bc.opts.labelColors[i]
+	case th != nil:
+		return label, th.Label
+	default:
+		return label, DefaultLabelColor
 	}
-	return label, DefaultLabelColor
 }
 
 // ValueCapacity returns the number of values that can fit into the canvas.
@@ -252,6 +458,10 @@ func (bc *BarChart) ValueCapacity() int {
 	barWidth := float64(bc.minBarWidth())
 	gapWidth := float64(bc.opts.barGap)
 	lastWidth := float64(bc.lastWidth)
+	if bc.opts.horizontal {
+		// Horizontal bars are stacked vertically under the legend.
+		lastWidth = float64(bc.lastHeight - bc.legendRows())
+	}
 	return valueCapacity(barWidth, gapWidth, lastWidth)
 }
 
@@ -263,6 +473,7 @@ func (bc *BarChart) ValueCapacity() int {
 func (bc *BarChart) Values(values []int, max int, opts ...Option) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
+	defer bc.dirty.Mark()
 
 	// Copy to avoid external modifications. See #174.
 	v := make([]int, len(values))
@@ -275,18 +486,45 @@ func (bc *BarChart) Values(values []int, max int, opts ...Option) error {
 		opt.set(bc.opts)
 	}
 	bc.values = v
+	bc.series = nil
 	bc.max = max
 	return nil
 }
 
-// Keyboard input isn't supported on the BarChart widget.
-func (*BarChart) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
-	return errors.New("the BarChart widget doesn't support keyboard events")
+// Keyboard scrolls the bars if the Scrollable option was provided.
+// Implements widgetapi.Widget.Keyboard.
+func (bc *BarChart) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if !bc.opts.scrollable {
+		return errors.New("the BarChart widget doesn't support keyboard events without the Scrollable option")
+	}
+	switch k.Key {
+	case bc.opts.keyLeft:
+		bc.scroll(-1)
+	case bc.opts.keyRight:
+		bc.scroll(1)
+	}
+	return nil
 }
 
-// Mouse input isn't supported on the BarChart widget.
-func (*BarChart) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
-	return errors.New("the BarChart widget doesn't support mouse events")
+// Mouse scrolls the bars if the Scrollable option was provided.
+// Implements widgetapi.Widget.Mouse.
+func (bc *BarChart) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if !bc.opts.scrollable {
+		return errors.New("the BarChart widget doesn't support mouse events without the Scrollable option")
+	}
+	switch m.Button {
+	case bc.opts.mouseLeftButton, mouse.ButtonWheelLeft:
+		bc.scroll(-1)
+	case bc.opts.mouseRightButton, mouse.ButtonWheelRight:
+		bc.scroll(1)
+	}
+	return nil
 }
 
 // Options implements widgetapi.Widget.Options.
@@ -300,15 +538,28 @@ func (bc *BarChart) Options() widgetapi.Options {
 	// never update bc.lastWidth and the result of ValueCapacity().
 	// Draw will stil refuse to draw if the canvas is too small, but the user
 	// will have an option to send less values.
-	min.X = bc.minBarWidth()
+	if bc.opts.horizontal {
+		min.Y = bc.minBarWidth()
+	} else {
+		min.X = bc.minBarWidth()
+	}
 
+	ks, ms := widgetapi.KeyScopeNone, widgetapi.MouseScopeNone
+	if bc.opts.scrollable {
+		ks, ms = widgetapi.KeyScopeFocused, widgetapi.MouseScopeWidget
+	}
 	return widgetapi.Options{
 		MinimumSize:  min,
-		WantKeyboard: widgetapi.KeyScopeNone,
-		WantMouse:    widgetapi.MouseScopeNone,
+		WantKeyboard: ks,
+		WantMouse:    ms,
 	}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (bc *BarChart) SetDirtyFunc(markDirty func()) {
+	bc.dirty.Set(markDirty)
+}
+
 // minBarWidth determines the minimum possible width of a bar based on the
 // options.
 func (bc *BarChart) minBarWidth() int {
@@ -318,11 +569,19 @@ func (bc *BarChart) minBarWidth() int {
 	} else {
 		minBarWidth = bc.opts.barWidth
 	}
+	if bc.opts.groupedSeries && len(bc.series) > minBarWidth {
+		// At least one char for each of the grouped values.
+		minBarWidth = len(bc.series)
+	}
 	return minBarWidth
 }
 
 // minSize determines the minimum required size of the canvas.
 func (bc *BarChart) minSize() image.Point {
+	if bc.opts.horizontal {
+		return bc.hMinSize()
+	}
+
 	bars := len(bc.values)
 	if bars == 0 {
 		return image.Point{1, 1}
@@ -332,7 +591,12 @@ func (bc *BarChart) minSize() image.Point {
 	if len(bc.opts.labels) > 0 {
 		minHeight++ // One line for the labels.
 	}
+	minHeight += bc.baselineRows() + bc.legendRows()
 
+	if bc.opts.scrollable {
+		// The remaining bars are reachable by scrolling.
+		bars = 1
+	}
 	minWidth := bars*bc.minBarWidth() + (bars-1)*bc.opts.barGap
 	return image.Point{minWidth, minHeight}
 }
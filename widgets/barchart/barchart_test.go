@@ -20,11 +20,15 @@ import (
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/testcanvas"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/draw/testdraw"
 	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -188,6 +192,209 @@ func TestBarChart(t *testing.T) {
 			},
 			wantCapacity: 4,
 		},
+		{
+			desc: "fails on negative grid step",
+			opts: []Option{
+				GridLines(-1),
+			},
+			update: func(bc *BarChart) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 3, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "displays grid lines and the baseline behind the bars",
+			opts: []Option{
+				Char('o'),
+				GridLines(5),
+				Baseline(),
+				Labels([]string{"a", "b"}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{0, 2, 5, 10}, 10)
+			},
+			canvas: image.Rect(0, 0, 7, 12),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				gridOpts := draw.TextCellOpts(cell.FgColor(DefaultGridColor))
+				testdraw.MustText(c, "┄┄┄┄┄┄┄", image.Point{0, 0}, gridOpts)
+				testdraw.MustText(c, "┄┄┄┄┄┄┄", image.Point{0, 5}, gridOpts)
+				testdraw.MustText(c, "───────", image.Point{0, 10}, gridOpts)
+
+				testdraw.MustRectangle(c, image.Rect(2, 8, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(4, 5, 5, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(6, 0, 7, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+
+				testdraw.MustText(c, "a", image.Point{0, 11}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustText(c, "b", image.Point{2, 11}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 4,
+		},
+		{
+			desc: "grid lines use the provided cell options",
+			opts: []Option{
+				Char('o'),
+				GridLines(2),
+				GridCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{1}, 4)
+			},
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				gridOpts := draw.TextCellOpts(cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "┄┄┄", image.Point{0, 0}, gridOpts)
+				testdraw.MustText(c, "┄┄┄", image.Point{0, 2}, gridOpts)
+
+				testdraw.MustRectangle(c, image.Rect(0, 3, 3, 4),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "displays initial values set via option",
+			opts: []Option{
+				Char('o'),
+				InitialValues([]int{0, 2, 5, 10}, 10),
+			},
+			update: func(bc *BarChart) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 7, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(2, 8, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(4, 5, 5, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(6, 0, 7, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 4,
+		},
+		{
+			desc: "fails on invalid initial values",
+			opts: []Option{
+				InitialValues([]int{0, 11}, 10),
+			},
+			update: func(bc *BarChart) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 7, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "displays bars with rotated labels",
+			opts: []Option{
+				Char('o'),
+				BarGap(1),
+				RotateLabels(),
+				Labels([]string{
+					"ab",
+					"long",
+				}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{5, 10}, 10)
+			},
+			canvas: image.Rect(0, 0, 3, 14),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 5, 1, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(2, 0, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+
+				// Labels.
+				testdraw.MustVerticalText(c, "ab", image.Point{0, 10}, draw.VerticalTextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustVerticalText(c, "long", image.Point{2, 10}, draw.VerticalTextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "trims rotated labels that don't fit",
+			opts: []Option{
+				Char('o'),
+				RotateLabels(),
+				Labels([]string{
+					"long",
+				}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{10}, 10)
+			},
+			canvas: image.Rect(0, 0, 1, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 1, 1),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+
+				// Labels.
+				testdraw.MustVerticalText(c, "l…", image.Point{0, 1}, draw.VerticalTextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
 		{
 			desc: "displays bars with labels",
 			opts: []Option{
@@ -355,6 +562,61 @@ func TestBarChart(t *testing.T) {
 			},
 			wantCapacity: 4,
 		},
+		{
+			desc: "uses colors from the theme for bars without explicit colors",
+			opts: []Option{
+				Char('o'),
+				Labels([]string{
+					"1",
+					"2",
+				}),
+				ShowValues(),
+				BarColors([]cell.Color{cell.ColorBlue}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{5, 10}, 10)
+			},
+			canvas: image.Rect(0, 0, 3, 11),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorCyan,
+					Accent:  cell.ColorMagenta,
+					Label:   cell.ColorWhite,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 5, 1, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorBlue)),
+				)
+				testdraw.MustRectangle(c, image.Rect(2, 0, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorCyan)),
+				)
+				// Labels.
+				testdraw.MustText(c, "1", image.Point{0, 10}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorWhite),
+				))
+				testdraw.MustText(c, "2", image.Point{2, 10}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorWhite),
+				))
+				// Values.
+				testdraw.MustText(c, "5", image.Point{0, 9}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorMagenta),
+					cell.BgColor(cell.ColorBlue),
+				))
+				testdraw.MustText(c, "…", image.Point{2, 9}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorMagenta),
+					cell.BgColor(cell.ColorCyan),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
 		{
 			desc: "bars take as much width as available",
 			opts: []Option{
@@ -486,6 +748,29 @@ func TestBarChart(t *testing.T) {
 			},
 			wantCapacity: 2,
 		},
+		{
+			desc: "options provided to Values override options provided to New",
+			opts: []Option{
+				BarColors([]cell.Color{cell.ColorBlue}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{1}, 1,
+					BarColors([]cell.Color{cell.ColorRed}),
+					BarColors([]cell.Color{cell.ColorGreen}),
+				)
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 0, 1, 1),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
 		{
 			desc: "respects bar and label colors",
 			opts: []Option{
@@ -775,6 +1060,27 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeNone,
 			},
 		},
+		{
+			desc: "scrollable bar chart wants keyboard and mouse events",
+			create: func() (*BarChart, error) {
+				bc, err := New(
+					BarWidth(3),
+					Scrollable(),
+				)
+				if err != nil {
+					return nil, err
+				}
+				if err := bc.Values([]int{1, 2}, 3); err != nil {
+					return nil, err
+				}
+				return bc, nil
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -793,6 +1099,217 @@ func TestOptions(t *testing.T) {
 	}
 }
 
+func TestScroll(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		desc    string
+		opts    []Option
+		events  []*terminalapi.Keyboard
+		mouse   []*terminalapi.Mouse
+		canvas  image.Rectangle
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc:    "fails without the BarWidth option",
+			opts:    []Option{Scrollable()},
+			canvas:  image.Rect(0, 0, 3, 5),
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate scroll keys",
+			opts: []Option{
+				Scrollable(),
+				BarWidth(1),
+				ScrollKeys(keyboard.KeyEnter, keyboard.KeyEnter),
+			},
+			canvas:  image.Rect(0, 0, 3, 5),
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate scroll mouse buttons",
+			opts: []Option{
+				Scrollable(),
+				BarWidth(1),
+				ScrollMouseButtons(mouse.ButtonLeft, mouse.ButtonLeft),
+			},
+			canvas:  image.Rect(0, 0, 3, 5),
+			wantErr: true,
+		},
+		{
+			desc:   "draws the first bars and indicates more on the right",
+			canvas: image.Rect(0, 0, 3, 5),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 4, 1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(1, 3, 2, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(2, 2, 3, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustText(c, "⇨", image.Point{2, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls by one bar using the keyboard",
+			canvas: image.Rect(0, 0, 3, 5),
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyArrowRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 3, 1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(1, 2, 2, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(2, 1, 3, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustText(c, "⇦", image.Point{0, 0})
+				testdraw.MustText(c, "⇨", image.Point{2, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't scroll past the last bar",
+			opts:   []Option{ScrollRunes('<', '>')},
+			canvas: image.Rect(0, 0, 3, 5),
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyArrowRight},
+				{Key: keyboard.KeyArrowRight},
+				{Key: keyboard.KeyArrowRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 2, 1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(1, 1, 2, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(2, 0, 3, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustText(c, "<", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls back using the mouse",
+			canvas: image.Rect(0, 0, 3, 5),
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyArrowRight},
+			},
+			mouse: []*terminalapi.Mouse{
+				{Button: mouse.ButtonWheelUp},
+				{Button: mouse.ButtonWheelUp},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 4, 1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(1, 3, 2, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(2, 2, 3, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustText(c, "⇨", image.Point{2, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls back using the horizontal mouse wheel",
+			canvas: image.Rect(0, 0, 3, 5),
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyArrowRight},
+			},
+			mouse: []*terminalapi.Mouse{
+				{Button: mouse.ButtonWheelLeft},
+				{Button: mouse.ButtonWheelLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustRectangle(c, image.Rect(0, 4, 1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(1, 3, 2, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustRectangle(c, image.Rect(2, 2, 3, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				testdraw.MustText(c, "⇨", image.Point{2, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't indicate scrolling when all the bars fit",
+			canvas: image.Rect(0, 0, 5, 5),
+			events: []*terminalapi.Keyboard{
+				{Key: keyboard.KeyArrowRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				for i, v := range values {
+					testdraw.MustRectangle(c, image.Rect(i, 5-v, i+1, 5), draw.RectCellOpts(cell.BgColor(DefaultBarColor)))
+				}
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if !tc.wantErr {
+				opts = append([]Option{
+					Scrollable(),
+					BarWidth(1),
+					BarGap(0),
+				}, tc.opts...)
+			}
+			bc, err := New(opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := bc.Values(values, len(values)); err != nil {
+				t.Fatalf("Values => unexpected error: %v", err)
+			}
+
+			// The first draw determines how many bars fit.
+			if err := bc.Draw(testcanvas.MustNew(tc.canvas), &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				if err := bc.Keyboard(ev, &widgetapi.EventMeta{}); err != nil {
+					t.Fatalf("Keyboard => unexpected error: %v", err)
+				}
+			}
+			for _, ev := range tc.mouse {
+				if err := bc.Mouse(ev, &widgetapi.EventMeta{}); err != nil {
+					t.Fatalf("Mouse => unexpected error: %v", err)
+				}
+			}
+
+			c := testcanvas.MustNew(tc.canvas)
+			if err := bc.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			got := faketerm.MustNew(c.Size())
+			testcanvas.MustApply(c, got)
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestEventsWithoutScrolling(t *testing.T) {
+	bc, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := bc.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight}, &widgetapi.EventMeta{}); err == nil {
+		t.Errorf("Keyboard => got nil error, want an error")
+	}
+	if err := bc.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelUp}, &widgetapi.EventMeta{}); err == nil {
+		t.Errorf("Mouse => got nil error, want an error")
+	}
+}
+
 func TestValueCapacity(t *testing.T) {
 	tests := []struct {
 		desc                         string
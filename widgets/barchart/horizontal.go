@@ -0,0 +1,247 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barchart
+
+// horizontal.go contains code that draws the bars horizontally.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/theme"
+)
+
+const (
+	// hBaselineChar is the rune used to draw the zero baseline of
+	// horizontal bars.
+	hBaselineChar = '│'
+
+	// hGridChar is the rune used to draw the grid lines behind horizontal
+	// bars.
+	hGridChar = '┆'
+)
+
+// drawHorizontal draws the bars horizontally from left to right with the
+// labels on the left.
+func (bc *BarChart) drawHorizontal(cvs *canvas.Canvas, th *theme.Theme) error {
+	if err := bc.drawLegend(cvs, th); err != nil {
+		return err
+	}
+	if err := bc.hDrawGrid(cvs); err != nil {
+		return err
+	}
+
+	for i := range bc.values {
+		if err := bc.hDrawBar(cvs, i, th); err != nil {
+			return err
+		}
+
+		l, c := bc.label(i, th)
+		if l == "" {
+			continue
+		}
+		r := bc.hBarRect(cvs, i, bc.max)
+		start := image.Point{cvs.Area().Min.X, r.Min.Y + (r.Dy()-1)/2}
+		if err := draw.Text(cvs, l, start,
+			draw.TextCellOpts(cell.FgColor(c)),
+			draw.TextMaxX(bc.hBarsMinX(cvs)-bc.baselineCols()-1),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hDrawBar draws the i-th horizontal bar and its value if requested by the
+// options.
+func (bc *BarChart) hDrawBar(cvs *canvas.Canvas, i int, th *theme.Theme) error {
+	full := bc.hBarRect(cvs, i, bc.max)
+	if len(bc.series) == 0 {
+		r := bc.hBarRect(cvs, i, bc.values[i])
+		if err := bc.drawSegment(cvs, r, bc.barColor(i, th)); err != nil {
+			return err
+		}
+		if bc.opts.showValues {
+			return drawValueText(cvs, full, fmt.Sprint(bc.values[i]), bc.valColor(i, th), align.HorizontalLeft, align.VerticalMiddle)
+		}
+		return nil
+	}
+
+	if !bc.opts.groupedSeries {
+		sum := 0
+		for k := range bc.series {
+			prev := bc.barLength(cvs, sum)
+			sum += bc.seriesValue(k, i)
+			r := image.Rect(full.Min.X+prev, full.Min.Y, full.Min.X+bc.barLength(cvs, sum), full.Max.Y)
+			if err := bc.drawSegment(cvs, r, bc.seriesColor(k)); err != nil {
+				return err
+			}
+		}
+		if bc.opts.showValues {
+			return drawValueText(cvs, full, fmt.Sprint(bc.values[i]), bc.valColor(i, th), align.HorizontalLeft, align.VerticalMiddle)
+		}
+		return nil
+	}
+
+	rows := full.Dy() / len(bc.series)
+	for k := range bc.series {
+		minY := full.Min.Y + k*rows
+		group := image.Rect(full.Min.X, minY, full.Max.X, minY+rows)
+		v := bc.seriesValue(k, i)
+		r := image.Rect(group.Min.X, group.Min.Y, group.Min.X+bc.barLength(cvs, v), group.Max.Y)
+		if err := bc.drawSegment(cvs, r, bc.seriesColor(k)); err != nil {
+			return err
+		}
+		if bc.opts.showValues {
+			if err := drawValueText(cvs, group, fmt.Sprint(v), bc.valColor(i, th), align.HorizontalLeft, align.VerticalMiddle); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hBarThickness determines the number of rows occupied by a single horizontal
+// bar.
+func (bc *BarChart) hBarThickness(cvs *canvas.Canvas) int {
+	if len(bc.values) == 0 {
+		return 0
+	}
+	if bc.opts.barWidth >= 1 {
+		return bc.opts.barWidth
+	}
+
+	gaps := len(bc.values) - 1
+	rem := cvs.Area().Dy() - bc.legendRows() - gaps*bc.opts.barGap
+	return rem / len(bc.values)
+}
+
+// barLength determines the number of cells occupied by a horizontal bar
+// displaying the value.
+func (bc *BarChart) barLength(cvs *canvas.Canvas, value int) int {
+	available := cvs.Area().Max.X - bc.hBarsMinX(cvs)
+
+	ratio := float32(value) / float32(bc.max)
+	return int(float32(available) * ratio)
+}
+
+// hBarRect returns a rectangle that represents the i-th horizontal bar
+// displaying the value.
+func (bc *BarChart) hBarRect(cvs *canvas.Canvas, i, value int) image.Rectangle {
+	thick := bc.hBarThickness(cvs)
+	minX := bc.hBarsMinX(cvs)
+	minY := cvs.Area().Min.Y + bc.legendRows() + i*(thick+bc.opts.barGap)
+	return image.Rect(minX, minY, minX+bc.barLength(cvs, value), minY+thick)
+}
+
+// hBarsMinX returns the X coordinate where the horizontal bars start, which
+// is right of the labels and the zero baseline.
+func (bc *BarChart) hBarsMinX(cvs *canvas.Canvas) int {
+	return cvs.Area().Min.X + bc.labelCols(cvs) + bc.baselineCols()
+}
+
+// baselineCols determines the number of columns reserved for the zero
+// baseline left of the horizontal bars.
+func (bc *BarChart) baselineCols() int {
+	if bc.opts.baseline {
+		return 1
+	}
+	return 0
+}
+
+// labelCols determines the number of columns reserved for the labels left of
+// the horizontal bars, including one column that separates them from the
+// bars.
+func (bc *BarChart) labelCols(cvs *canvas.Canvas) int {
+	longest := 0
+	for _, l := range bc.opts.labels {
+		if w := runewidth.StringWidth(l); w > longest {
+			longest = w
+		}
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	// Always leave at least one column for the bars.
+	cols := longest + 1
+	if max := cvs.Area().Dx() - bc.baselineCols() - 1; cols > max {
+		return max
+	}
+	return cols
+}
+
+// hDrawGrid draws the vertical grid lines and the zero baseline of the
+// horizontal bars if requested by the options.
+func (bc *BarChart) hDrawGrid(cvs *canvas.Canvas) error {
+	if len(bc.values) == 0 {
+		return nil
+	}
+
+	minX := bc.hBarsMinX(cvs)
+	if bc.opts.baseline {
+		if err := bc.hDrawGridLine(cvs, minX-1, hBaselineChar); err != nil {
+			return err
+		}
+	}
+
+	if bc.opts.gridStep == 0 {
+		return nil
+	}
+	for v := bc.opts.gridStep; v <= bc.max; v += bc.opts.gridStep {
+		l := bc.barLength(cvs, v)
+		if l == 0 {
+			continue // The value is too small to be displayed.
+		}
+		if err := bc.hDrawGridLine(cvs, minX+l-1, hGridChar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hDrawGridLine draws a vertical grid line below the legend in the column
+// with the specified X coordinate.
+func (bc *BarChart) hDrawGridLine(cvs *canvas.Canvas, x int, r rune) error {
+	ar := cvs.Area()
+	for y := ar.Min.Y + bc.legendRows(); y < ar.Max.Y; y++ {
+		if _, err := cvs.SetCell(image.Point{x, y}, r, bc.opts.gridCellOpts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hMinSize determines the minimum required size of the canvas for horizontal
+// bars.
+func (bc *BarChart) hMinSize() image.Point {
+	bars := len(bc.values)
+	if bars == 0 {
+		return image.Point{1, 1}
+	}
+
+	minWidth := 1 + bc.baselineCols() // At least one character for the bar.
+	if len(bc.opts.labels) > 0 {
+		minWidth += 2 // At least one character of the labels and the separator.
+	}
+	minHeight := bars*bc.minBarWidth() + (bars-1)*bc.opts.barGap + bc.legendRows()
+	return image.Point{minWidth, minHeight}
+}
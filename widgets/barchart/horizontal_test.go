@@ -0,0 +1,284 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barchart
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+)
+
+func TestHorizontal(t *testing.T) {
+	tests := []struct {
+		desc         string
+		opts         []Option
+		update       func(*BarChart) error // update gets called before drawing of the widget.
+		canvas       image.Rectangle
+		want         func(size image.Point) *faketerm.Terminal
+		wantCapacity int
+		wantErr      bool
+	}{
+		{
+			desc: "fails with RotateLabels",
+			opts: []Option{
+				Horizontal(),
+				RotateLabels(),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails with Scrollable",
+			opts: []Option{
+				Horizontal(),
+				BarWidth(1),
+				Scrollable(),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "draws bars with labels on the left",
+			opts: []Option{
+				Horizontal(),
+				Char('o'),
+				Labels([]string{"a", "bcd"}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{2, 4}, 4)
+			},
+			canvas: image.Rect(0, 0, 8, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(4, 0, 6, 1),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustRectangle(c, image.Rect(4, 2, 8, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustText(c, "a", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustText(c, "bcd", image.Point{0, 2}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "truncates labels that don't fit",
+			opts: []Option{
+				Horizontal(),
+				Char('o'),
+				Labels([]string{"abcdef"}),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{1}, 1)
+			},
+			canvas: image.Rect(0, 0, 4, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(3, 0, 4, 1),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustText(c, "a…", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "draws values, the baseline and grid lines",
+			opts: []Option{
+				Horizontal(),
+				Char('o'),
+				ShowValues(),
+				Baseline(),
+				GridLines(2),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{2}, 4)
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				for y := 0; y < 2; y++ {
+					testcanvas.MustSetCell(c, image.Point{0, y}, '│', cell.FgColor(DefaultGridColor))
+					testcanvas.MustSetCell(c, image.Point{2, y}, '┆', cell.FgColor(DefaultGridColor))
+					testcanvas.MustSetCell(c, image.Point{5, y}, '┆', cell.FgColor(DefaultGridColor))
+				}
+				testdraw.MustRectangle(c, image.Rect(1, 0, 3, 2),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testdraw.MustText(c, "2", image.Point{1, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultValueColor),
+					cell.BgColor(DefaultBarColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "draws stacked series with a legend",
+			opts: []Option{
+				Horizontal(),
+				Char('o'),
+				Legend(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1}},
+					{Name: "b", Values: []int{2}},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 8, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "█", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultSeriesColors[0]),
+				))
+				testdraw.MustText(c, "a", image.Point{2, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustText(c, "█", image.Point{5, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultSeriesColors[1]),
+				))
+				testdraw.MustText(c, "b", image.Point{7, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustRectangle(c, image.Rect(0, 1, 2, 2),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+				)
+				testdraw.MustRectangle(c, image.Rect(2, 1, 6, 2),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[1])),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "draws grouped series on separate rows",
+			opts: []Option{
+				Horizontal(),
+				Char('o'),
+				GroupedSeries(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1}},
+					{Name: "b", Values: []int{2}},
+				}, 2)
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 2, 1),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+				)
+				testdraw.MustRectangle(c, image.Rect(0, 1, 4, 2),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[1])),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "requests a resize when the bars don't fit vertically",
+			opts: []Option{
+				Horizontal(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.Values([]int{1, 1, 1}, 1)
+			},
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			bc, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if err := tc.update(bc); err != nil {
+				t.Fatalf("update => unexpected error: %v", err)
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := bc.Draw(c, nil); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if gotCapacity := bc.ValueCapacity(); gotCapacity != tc.wantCapacity {
+				t.Errorf("ValueCapacity => %d, want %d", gotCapacity, tc.wantCapacity)
+			}
+		})
+	}
+}
@@ -17,9 +17,12 @@ package barchart
 // options.go contains configurable options for BarChart.
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/draw"
 )
 
@@ -47,6 +50,31 @@ type options struct {
 	labelColors []cell.Color
 	valueColors []cell.Color
 	labels      []string
+	// rotateLabels indicates that labels are drawn vertically.
+	rotateLabels bool
+	// initial are the values provided via the InitialValues option.
+	initial *initialValues
+
+	baseline     bool
+	baselineChar rune
+	gridStep     int
+	gridChar     rune
+	gridCellOpts []cell.Option
+
+	scrollable       bool
+	scrollLeftRune   rune
+	scrollRightRune  rune
+	keyLeft          keyboard.Key
+	keyRight         keyboard.Key
+	mouseLeftButton  mouse.Button
+	mouseRightButton mouse.Button
+
+	// groupedSeries indicates that series are drawn side by side.
+	groupedSeries bool
+	legend        bool
+
+	// horizontal indicates that the bars are drawn from left to right.
+	horizontal bool
 }
 
 // validate validates the provided options.
@@ -57,14 +85,46 @@ func (o *options) validate() error {
 	if got, min := o.barGap, 0; got < min {
 		return fmt.Errorf("invalid BarGap %d, must be %d <= BarGap", got, min)
 	}
+	if got, min := o.gridStep, 0; got < min {
+		return fmt.Errorf("invalid GridLines step %d, must be %d <= step", got, min)
+	}
+	if o.horizontal {
+		if o.rotateLabels {
+			return errors.New("the RotateLabels option cannot be combined with the Horizontal option")
+		}
+		if o.scrollable {
+			return errors.New("the Scrollable option cannot be combined with the Horizontal option")
+		}
+	}
+	if o.scrollable {
+		if o.barWidth < 1 {
+			return errors.New("the Scrollable option requires the BarWidth option")
+		}
+		if o.keyLeft == o.keyRight {
+			return fmt.Errorf("invalid ScrollKeys(left:%v, right:%v), the keys must be unique", o.keyLeft, o.keyRight)
+		}
+		if o.mouseLeftButton == o.mouseRightButton {
+			return fmt.Errorf("invalid ScrollMouseButtons(left:%v, right:%v), the buttons must be unique", o.mouseLeftButton, o.mouseRightButton)
+		}
+	}
 	return nil
 }
 
 // newOptions returns options with the default values set.
 func newOptions() *options {
 	return &options{
-		barChar: DefaultChar,
-		barGap:  DefaultBarGap,
+		barChar:      DefaultChar,
+		barGap:       DefaultBarGap,
+		baselineChar: DefaultBaselineChar,
+		gridChar:     DefaultGridChar,
+		gridCellOpts: []cell.Option{cell.FgColor(DefaultGridColor)},
+
+		scrollLeftRune:   DefaultScrollLeftRune,
+		scrollRightRune:  DefaultScrollRightRune,
+		keyLeft:          DefaultScrollKeyLeft,
+		keyRight:         DefaultScrollKeyRight,
+		mouseLeftButton:  DefaultScrollMouseButtonLeft,
+		mouseRightButton: DefaultScrollMouseButtonRight,
 	}
 }
 
@@ -114,7 +174,8 @@ const DefaultBarColor = cell.ColorRed
 // BarColors sets the colors of each of the bars.
 // Bars are created on a call to Values(), each value ends up in its own Bar.
 // The first supplied color applies to the bar displaying the first value.
-// Any bars that don't have a color specified use the DefaultBarColor.
+// Any bars that don't have a color specified use the Primary color of the
+// dashboard theme if set, otherwise the DefaultBarColor.
 func BarColors(colors []cell.Color) Option {
 	return option(func(opts *options) {
 		opts.barColors = colors
@@ -128,8 +189,8 @@ const DefaultLabelColor = cell.ColorGreen
 // LabelColors sets the colors of each of the labels under the bars.
 // Bars are created on a call to Values(), each value ends up in its own Bar.
 // The first supplied color applies to the label of the bar displaying the
-// first value. Any labels that don't have a color specified use the
-// DefaultLabelColor.
+// first value. Any labels that don't have a color specified use the Label
+// color of the dashboard theme if set, otherwise the DefaultLabelColor.
 func LabelColors(colors []cell.Color) Option {
 	return option(func(opts *options) {
 		opts.labelColors = colors
@@ -149,6 +210,18 @@ func Labels(labels []string) Option {
 	})
 }
 
+// RotateLabels draws the labels under the bars vertically, top to bottom.
+// This allows long labels to fit under narrow bars. The bar chart reserves
+// enough rows under the bars to fit the longest label, but always leaves at
+// least one row for the bars themselves. Labels that still don't fit are
+// trimmed.
+// The default behavior is to draw the labels horizontally on a single row.
+func RotateLabels() Option {
+	return option(func(opts *options) {
+		opts.rotateLabels = true
+	})
+}
+
 // DefaultValueColor is the default color of a bar value, unless specified
 // otherwise via the ValueColors option.
 const DefaultValueColor = cell.ColorYellow
@@ -156,9 +229,159 @@ const DefaultValueColor = cell.ColorYellow
 // ValueColors sets the colors of each of the values in the bars. Bars are
 // created on a call to Values(), each value ends up in its own Bar. The first
 // supplied color applies to the bar displaying the first value. Any values
-// that don't have a color specified use the DefaultValueColor.
+// that don't have a color specified use the Accent color of the dashboard
+// theme if set, otherwise the DefaultValueColor.
 func ValueColors(colors []cell.Color) Option {
 	return option(func(opts *options) {
 		opts.valueColors = colors
 	})
 }
+
+// initialValues are the values provided via the InitialValues option.
+type initialValues struct {
+	values []int
+	max    int
+}
+
+// InitialValues sets the values that the BarChart displays from the start, as
+// if Values was called right after New.
+// Only takes effect when provided to New.
+func InitialValues(values []int, max int) Option {
+	return option(func(opts *options) {
+		opts.initial = &initialValues{
+			values: append([]int(nil), values...),
+			max:    max,
+		}
+	})
+}
+
+// DefaultBaselineChar is the rune used to draw the zero baseline.
+const DefaultBaselineChar = '─'
+
+// Baseline reserves a row under the bars and draws the zero baseline in it,
+// which makes the bottom of the bars explicit even when all the values are
+// positive. The baseline uses the cell options provided via GridCellOpts.
+func Baseline() Option {
+	return option(func(opts *options) {
+		opts.baseline = true
+	})
+}
+
+// DefaultGridChar is the rune used to draw the grid lines.
+const DefaultGridChar = '┄'
+
+// GridLines draws horizontal grid lines behind the bars at regular value
+// intervals, i.e. at each multiple of the step up to the maximum value
+// provided to Values(). The grid lines help to estimate the values of the
+// bars. Lines that would fall on the same row as a value too small to be
+// displayed are skipped. Must be a positive or zero integer, zero disables
+// the grid lines which is the default.
+func GridLines(step int) Option {
+	return option(func(opts *options) {
+		opts.gridStep = step
+	})
+}
+
+// DefaultGridColor is the default color of the grid lines and the baseline,
+// unless specified otherwise via the GridCellOpts option.
+const DefaultGridColor = cell.ColorGray
+
+// GridCellOpts sets the cell options for the grid lines and the zero
+// baseline. Defaults to a muted foreground color, see DefaultGridColor.
+func GridCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.gridCellOpts = co
+	})
+}
+
+// Scrollable keeps the width of the bars set by the BarWidth option when
+// there are more bars than fit the canvas and allows the user to scroll
+// through the bars horizontally using the keyboard and the mouse. Runes at the
+// top corners of the canvas indicate that more bars are available on either
+// side, see ScrollRunes. Requires the BarWidth option.
+// Defaults to a BarChart that requires space for all the bars.
+func Scrollable() Option {
+	return option(func(opts *options) {
+		opts.scrollable = true
+	})
+}
+
+// The default runes that indicate more bars are available when scrolling.
+const (
+	DefaultScrollLeftRune  = '⇦'
+	DefaultScrollRightRune = '⇨'
+)
+
+// ScrollRunes sets the runes drawn at the top left and the top right corner
+// of the canvas when more bars are available to the left or to the right.
+// Only used with the Scrollable option.
+func ScrollRunes(left, right rune) Option {
+	return option(func(opts *options) {
+		opts.scrollLeftRune = left
+		opts.scrollRightRune = right
+	})
+}
+
+// The default keys for scrolling of the bars.
+const (
+	DefaultScrollKeyLeft  = keyboard.KeyArrowLeft
+	DefaultScrollKeyRight = keyboard.KeyArrowRight
+)
+
+// ScrollKeys configures the keyboard keys that scroll the bars by one bar.
+// The provided keys must be unique.
+// Only used with the Scrollable option.
+func ScrollKeys(left, right keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyLeft = left
+		opts.keyRight = right
+	})
+}
+
+// The default mouse buttons for scrolling of the bars.
+const (
+	DefaultScrollMouseButtonLeft  = mouse.ButtonWheelUp
+	DefaultScrollMouseButtonRight = mouse.ButtonWheelDown
+)
+
+// ScrollMouseButtons configures the mouse buttons that scroll the bars by one
+// bar. The provided buttons must be unique. The horizontal mouse wheel
+// (mouse.ButtonWheelLeft and mouse.ButtonWheelRight) scrolls the bars too.
+// Only used with the Scrollable option.
+func ScrollMouseButtons(left, right mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseLeftButton = left
+		opts.mouseRightButton = right
+	})
+}
+
+// GroupedSeries displays the values of the series provided via SeriesValues
+// side by side within each bar instead of stacking them on top of each other.
+// Each bar is split evenly between the series.
+func GroupedSeries() Option {
+	return option(func(opts *options) {
+		opts.groupedSeries = true
+	})
+}
+
+// Legend displays the names of the series provided via SeriesValues in their
+// colors on the top row of the canvas. Has no effect when the values were
+// provided via Values.
+func Legend() Option {
+	return option(func(opts *options) {
+		opts.legend = true
+	})
+}
+
+// Horizontal draws the bars from left to right, each bar on its own rows,
+// with the labels on the left of the bars. The labels take as many columns
+// as the longest label needs, so long labels aren't truncated unless the
+// canvas is too narrow. In this mode the BarWidth option sets the number of
+// rows of each bar and the BarGap option the number of rows between the bars,
+// the GridLines and the Baseline options draw vertical lines.
+// Cannot be combined with the RotateLabels or the Scrollable options.
+func Horizontal() Option {
+	return option(func(opts *options) {
+		opts.horizontal = true
+	})
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barchart
+
+// series.go contains code that displays multiple series of values.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/theme"
+)
+
+// Series is one series of values displayed by the BarChart, see
+// SeriesValues.
+type Series struct {
+	// Name identifies the series in the legend, see the Legend option.
+	Name string
+
+	// Values are the values of the series, the value at index i is displayed
+	// in the i-th bar. The series can have different lengths, missing values
+	// are treated as zero.
+	Values []int
+
+	// Color is the color of the series. Defaults to a color from
+	// DefaultSeriesColors based on the index of the series.
+	Color cell.Color
+}
+
+// DefaultSeriesColors are the colors of series that don't specify their
+// Color, the first series without a color uses the first color.
+var DefaultSeriesColors = []cell.Color{
+	cell.ColorRed,
+	cell.ColorBlue,
+	cell.ColorGreen,
+	cell.ColorYellow,
+	cell.ColorMagenta,
+	cell.ColorCyan,
+}
+
+// SeriesValues sets multiple series of values to be displayed by the
+// BarChart, replacing any values provided previously.
+//
+// The values with the same index in all the series are displayed in the same
+// bar, either stacked on top of each other in the order of the series or side
+// by side when the GroupedSeries option is provided. The values must not be
+// negative. When stacked, the sum of the values in one bar must be less or
+// equal the maximum value, otherwise each of the values must be. The labels,
+// the grid lines and the scrolling apply to the bars as with Values, the
+// ShowValues option displays the sum of a stacked bar or each of the grouped
+// values.
+// Provided options override values set when New() was called.
+func (bc *BarChart) SeriesValues(series []Series, max int, opts ...Option) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	defer bc.dirty.Mark()
+
+	// Copy to avoid external modifications. See #174.
+	s := make([]Series, len(series))
+	bars := 0
+	for i, ser := range series {
+		s[i] = ser
+		s[i].Values = append([]int(nil), ser.Values...)
+		if len(ser.Values) > bars {
+			bars = len(ser.Values)
+		}
+	}
+
+	o := *bc.opts
+	for _, opt := range opts {
+		opt.set(&o)
+	}
+
+	values := make([]int, bars)
+	for i := range values {
+		for k, ser := range s {
+			if i >= len(ser.Values) {
+				continue
+			}
+			v := ser.Values[i]
+			if v < 0 || (o.groupedSeries && v > max) {
+				return fmt.Errorf("invalid value %d in series %d at index %d, each value must be 0 <= value <= max", v, k, i)
+			}
+			if o.groupedSeries {
+				if v > values[i] {
+					values[i] = v
+				}
+			} else {
+				values[i] += v
+			}
+		}
+	}
+	if err := validateValues(values, max); err != nil {
+		return fmt.Errorf("invalid sums of the stacked series: %v", err)
+	}
+
+	*bc.opts = o
+	bc.series = s
+	bc.values = values
+	bc.max = max
+	return nil
+}
+
+// seriesValue returns the value of the k-th series in the i-th bar.
+func (bc *BarChart) seriesValue(k, i int) int {
+	if vals := bc.series[k].Values; i < len(vals) {
+		return vals[i]
+	}
+	return 0
+}
+
+// seriesColor returns the color of the k-th series.
+func (bc *BarChart) seriesColor(k int) cell.Color {
+	if c := bc.series[k].Color; c != cell.ColorDefault {
+		return c
+	}
+	return DefaultSeriesColors[k%len(DefaultSeriesColors)]
+}
+
+// drawSeriesBar draws the values of all the series in the i-th bar.
+func (bc *BarChart) drawSeriesBar(cvs *canvas.Canvas, i int, th *theme.Theme) error {
+	full, err := bc.barRect(cvs, i, bc.max)
+	if err != nil {
+		return err
+	}
+
+	if !bc.opts.groupedSeries {
+		// Heights of the segments are determined from the cumulative sums,
+		// so that rounding doesn't change the height of the entire bar.
+		sum := 0
+		for k := range bc.series {
+			prev := bc.barHeight(cvs, i, sum)
+			sum += bc.seriesValue(k, i)
+			h := bc.barHeight(cvs, i, sum)
+			r := image.Rect(full.Min.X, full.Max.Y-h, full.Max.X, full.Max.Y-prev)
+			if err := bc.drawSegment(cvs, r, bc.seriesColor(k)); err != nil {
+				return err
+			}
+		}
+		if bc.opts.showValues {
+			return bc.drawText(cvs, i, fmt.Sprint(bc.values[i]), bc.valColor(i, th), insideBar)
+		}
+		return nil
+	}
+
+	for k := range bc.series {
+		r := groupedRect(full, k, len(bc.series))
+		v := bc.seriesValue(k, i)
+		r.Min.Y = r.Max.Y - bc.barHeight(cvs, i, v)
+		if err := bc.drawSegment(cvs, r, bc.seriesColor(k)); err != nil {
+			return err
+		}
+		if bc.opts.showValues {
+			if err := drawValueText(cvs, r, fmt.Sprint(v), bc.valColor(i, th), align.HorizontalCenter, align.VerticalBottom); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// groupedRect returns the part of the bar area occupied by the k-th of n
+// grouped values. The width is split evenly, any remaining cells are left
+// empty on the right.
+func groupedRect(bar image.Rectangle, k, n int) image.Rectangle {
+	w := bar.Dx() / n
+	minX := bar.Min.X + k*w
+	return image.Rect(minX, bar.Min.Y, minX+w, bar.Max.Y)
+}
+
+// drawSegment draws a rectangle representing a value in the color.
+func (bc *BarChart) drawSegment(cvs *canvas.Canvas, r image.Rectangle, color cell.Color) error {
+	if r.Dx() <= 0 || r.Dy() <= 0 { // Value might be so small so that the rectangle is zero.
+		return nil
+	}
+	return draw.Rectangle(cvs, r,
+		draw.RectCellOpts(cell.BgColor(color)),
+		draw.RectChar(bc.opts.barChar),
+	)
+}
+
+// drawValueText draws the value within the rectangle using the alignment.
+func drawValueText(cvs *canvas.Canvas, r image.Rectangle, text string, color cell.Color, h align.Horizontal, v align.Vertical) error {
+	if r.Dx() <= 0 || r.Dy() <= 0 {
+		return nil
+	}
+	start, err := alignfor.Text(r, text, h, v)
+	if err != nil {
+		return err
+	}
+	return draw.Text(cvs, text, start,
+		draw.TextCellOpts(cell.FgColor(color)),
+		draw.TextMaxX(r.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// legendRows determines the number of rows reserved for the legend at the
+// top of the canvas.
+func (bc *BarChart) legendRows() int {
+	if bc.opts.legend && len(bc.series) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// drawLegend draws the legend of the series on the top row of the canvas.
+// Each series is represented by a square in its color followed by its name,
+// series that don't fit are omitted.
+func (bc *BarChart) drawLegend(cvs *canvas.Canvas, th *theme.Theme) error {
+	if bc.legendRows() == 0 {
+		return nil
+	}
+	ar := cvs.Area()
+	labelColor := DefaultLabelColor
+	if th != nil {
+		labelColor = th.Label
+	}
+	x := ar.Min.X
+	for k, ser := range bc.series {
+		if k > 0 {
+			x += legendSpacing
+		}
+		width := 2 + runewidth.StringWidth(ser.Name)
+		if x+width > ar.Max.X {
+			break
+		}
+		if _, err := cvs.SetCell(image.Point{x, ar.Min.Y}, legendRune, cell.FgColor(bc.seriesColor(k))); err != nil {
+			return err
+		}
+		if err := draw.Text(cvs, ser.Name, image.Point{x + 2, ar.Min.Y}, draw.TextCellOpts(cell.FgColor(labelColor))); err != nil {
+			return err
+		}
+		x += width
+	}
+	return nil
+}
+
+const (
+	// legendRune is the rune that represents the color of a series in the
+	// legend.
+	legendRune = '█'
+
+	// legendSpacing is the number of empty cells between the series in the
+	// legend.
+	legendSpacing = 2
+)
@@ -0,0 +1,344 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package barchart
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+)
+
+func TestSeries(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		update        func(*BarChart) error // update gets called before drawing of the widget.
+		canvas        image.Rectangle
+		want          func(size image.Point) *faketerm.Terminal
+		wantUpdateErr bool
+		wantDrawErr   bool
+	}{
+		{
+			desc: "fails on a negative value",
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1, -1}},
+				}, 10)
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails when stacked values exceed the maximum",
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1, 6}},
+					{Name: "b", Values: []int{1, 5}},
+				}, 10)
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails when a grouped value exceeds the maximum",
+			opts: []Option{
+				GroupedSeries(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1, 11}},
+				}, 10)
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "draws stacked series",
+			opts: []Option{
+				Char('o'),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1, 2}},
+					{Name: "b", Values: []int{2, 1}, Color: cell.ColorYellow},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				for _, r := range []image.Rectangle{
+					image.Rect(0, 3, 1, 4),
+					image.Rect(2, 2, 3, 4),
+				} {
+					testdraw.MustRectangle(c, r,
+						draw.RectChar('o'),
+						draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+					)
+				}
+				for _, r := range []image.Rectangle{
+					image.Rect(0, 1, 1, 3),
+					image.Rect(2, 1, 3, 2),
+				} {
+					testdraw.MustRectangle(c, r,
+						draw.RectChar('o'),
+						draw.RectCellOpts(cell.BgColor(cell.ColorYellow)),
+					)
+				}
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "series can have different lengths",
+			opts: []Option{
+				Char('o'),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1}},
+					{Name: "b", Values: []int{2, 4}},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 3, 1, 4),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+				)
+				for _, r := range []image.Rectangle{
+					image.Rect(0, 1, 1, 3),
+					image.Rect(2, 0, 3, 4),
+				} {
+					testdraw.MustRectangle(c, r,
+						draw.RectChar('o'),
+						draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[1])),
+					)
+				}
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the sum of stacked series",
+			opts: []Option{
+				Char('o'),
+				ShowValues(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1}},
+					{Name: "b", Values: []int{2}},
+				}, 3)
+			},
+			canvas: image.Rect(0, 0, 1, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 2, 1, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+				)
+				testdraw.MustRectangle(c, image.Rect(0, 0, 1, 2),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[1])),
+				)
+				testdraw.MustText(c, "3", image.Point{0, 2}, draw.TextCellOpts(
+					cell.FgColor(DefaultValueColor),
+					cell.BgColor(DefaultSeriesColors[0]),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws grouped series with values and a legend",
+			opts: []Option{
+				Char('o'),
+				GroupedSeries(),
+				ShowValues(),
+				Legend(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{2}},
+					{Name: "b", Values: []int{4}},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 8, 5),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "█", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultSeriesColors[0]),
+				))
+				testdraw.MustText(c, "a", image.Point{2, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testdraw.MustText(c, "█", image.Point{5, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultSeriesColors[1]),
+				))
+				testdraw.MustText(c, "b", image.Point{7, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+
+				testdraw.MustRectangle(c, image.Rect(0, 3, 4, 5),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[0])),
+				)
+				testdraw.MustText(c, "2", image.Point{1, 4}, draw.TextCellOpts(
+					cell.FgColor(DefaultValueColor),
+					cell.BgColor(DefaultSeriesColors[0]),
+				))
+				testdraw.MustRectangle(c, image.Rect(4, 1, 8, 5),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultSeriesColors[1])),
+				)
+				testdraw.MustText(c, "4", image.Point{5, 4}, draw.TextCellOpts(
+					cell.FgColor(DefaultValueColor),
+					cell.BgColor(DefaultSeriesColors[1]),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "omits series that don't fit the legend",
+			opts: []Option{
+				Char('o'),
+				Legend(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{0}},
+					{Name: "b", Values: []int{0}},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "█", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultSeriesColors[0]),
+				))
+				testdraw.MustText(c, "a", image.Point{2, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultLabelColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "grouped series require one cell per series",
+			opts: []Option{
+				GroupedSeries(),
+			},
+			update: func(bc *BarChart) error {
+				return bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1, 1}},
+					{Name: "b", Values: []int{1, 1}},
+				}, 4)
+			},
+			canvas: image.Rect(0, 0, 4, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "values replace the series",
+			opts: []Option{
+				Char('o'),
+				Legend(),
+			},
+			update: func(bc *BarChart) error {
+				if err := bc.SeriesValues([]Series{
+					{Name: "a", Values: []int{1}},
+				}, 4); err != nil {
+					return err
+				}
+				return bc.Values([]int{2}, 4)
+			},
+			canvas: image.Rect(0, 0, 1, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 2, 1, 4),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(DefaultBarColor)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			bc, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			err = tc.update(bc)
+			if (err != nil) != tc.wantUpdateErr {
+				t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = bc.Draw(c, nil)
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/mum4k/termdash/private/attrrange"
 	"github.com/mum4k/termdash/private/button"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
@@ -90,6 +91,9 @@ type Button struct {
 	// callback gets called on each button press.
 	callback CallbackFn
 
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the widget.
 	mu sync.Mutex
 
@@ -98,6 +102,8 @@ type Button struct {
 }
 
 // New returns a new Button that will display the provided text.
+// The text can contain newline characters, each line of the text is centered
+// inside the button.
 // Each press of the button will invoke the callback function.
 // The callback function can be nil in which case pressing the button is a
 // no-op.
@@ -156,6 +162,7 @@ func NewFromChunks(chunks []*TextChunk, cFn CallbackFn, opts ...Option) (*Button
 func (b *Button) SetCallback(cFn CallbackFn) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	defer b.dirty.Mark()
 	b.callback = cFn
 }
 
@@ -182,6 +189,9 @@ func (b *Button) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		since := timeSince(*b.keyTriggerTime)
 		if since > b.opts.keyUpDelay {
 			b.state = button.Up
+		} else {
+			// The button needs to be drawn again when it gets released.
+			b.dirty.Mark()
 		}
 	}
 
@@ -218,29 +228,61 @@ func (b *Button) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 }
 
 // drawText draws the text inside the button.
+// Each line of text is centered horizontally, the block of all the lines is
+// centered vertically.
 func (b *Button) drawText(cvs *canvas.Canvas, meta *widgetapi.Meta, buttonAr image.Rectangle) error {
 	pad := b.opts.textHorizontalPadding
 	textAr := image.Rect(buttonAr.Min.X+pad, buttonAr.Min.Y, buttonAr.Dx()-pad, buttonAr.Max.Y)
-	start, err := alignfor.Text(textAr, b.text.String(), align.HorizontalCenter, align.VerticalMiddle)
+
+	lines := strings.Split(b.text.String(), "\n")
+	y := textAr.Min.Y
+	if gap := textAr.Dy() - len(lines); gap > 0 {
+		y += gap / 2
+	}
+
+	var pos int // Byte position of the current line in the text.
+	for i, line := range lines {
+		lineStart := pos
+		pos += len(line) + 1 // Add one for the newline character.
+		if i > 0 && y >= textAr.Max.Y {
+			// Lines that don't fit are dropped. The first line is always
+			// drawn, which reports an error if the button is too small.
+			break
+		}
+		if line != "" {
+			if err := b.drawLine(cvs, meta, buttonAr, image.Rect(textAr.Min.X, y, textAr.Max.X, y+1), line, lineStart); err != nil {
+				return err
+			}
+		}
+		y++
+	}
+	return nil
+}
+
+// drawLine draws a single line of the text inside the button, centering it
+// within the provided line area. The lineStart is the byte position of the
+// line within the text.
+func (b *Button) drawLine(cvs *canvas.Canvas, meta *widgetapi.Meta, buttonAr, lineAr image.Rectangle, line string, lineStart int) error {
+	start, err := alignfor.Text(lineAr, line, align.HorizontalCenter, align.VerticalTop)
 	if err != nil {
 		return err
 	}
 
 	maxCells := buttonAr.Max.X - start.X
-	trimmed, err := draw.TrimText(b.text.String(), maxCells, draw.OverrunModeThreeDot)
+	trimmed, err := draw.TrimText(line, maxCells, draw.OverrunModeThreeDot)
 	if err != nil {
 		return err
 	}
 
-	optRange, err := b.tOptsTracker.ForPosition(0) // Text options for the current byte.
+	optRange, err := b.tOptsTracker.ForPosition(lineStart) // Text options for the current byte.
 	if err != nil {
 		return err
 	}
 
 	cur := start
 	for i, r := range trimmed {
-		if i >= optRange.High { // Get the next write options.
-			or, err := b.tOptsTracker.ForPosition(i)
+		if lineStart+i >= optRange.High { // Get the next write options.
+			or, err := b.tOptsTracker.ForPosition(lineStart + i)
 			if err != nil {
 				return err
 			}
@@ -353,3 +395,8 @@ func (b *Button) Options() widgetapi.Options {
 		WantMouse:    widgetapi.MouseScopeGlobal,
 	}
 }
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (b *Button) SetDirtyFunc(markDirty func()) {
+	b.dirty.Set(markDirty)
+}
@@ -305,6 +305,70 @@ func TestButton(t *testing.T) {
 			},
 			wantCallback: &callbackTracker{},
 		},
+		{
+			desc:     "draws multi-line text centering each line",
+			callback: &callbackTracker{},
+			text:     "hi\nworld",
+			canvas:   image.Rect(0, 0, 8, 5),
+			meta:     &widgetapi.Meta{Focused: false},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				// Shadow.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(1, 1, 8, 5), 's', cell.BgColor(cell.ColorNumber(240)))
+
+				// Button.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 0, 7, 4), 'x', cell.BgColor(cell.ColorNumber(117)))
+
+				// Text.
+				testdraw.MustText(cvs, "hi", image.Point{2, 1},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorNumber(117))),
+				)
+				testdraw.MustText(cvs, "world", image.Point{1, 2},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorNumber(117))),
+				)
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCallback: &callbackTracker{},
+		},
+		{
+			desc:     "drops lines of multi-line text that don't fit",
+			callback: &callbackTracker{},
+			text:     "hi\nworld",
+			opts: []Option{
+				Height(1),
+			},
+			canvas: image.Rect(0, 0, 8, 2),
+			meta:   &widgetapi.Meta{Focused: false},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				// Shadow.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(1, 1, 8, 2), 's', cell.BgColor(cell.ColorNumber(240)))
+
+				// Button.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 0, 7, 1), 'x', cell.BgColor(cell.ColorNumber(117)))
+
+				// Text.
+				testdraw.MustText(cvs, "hi", image.Point{2, 0},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorNumber(117))),
+				)
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCallback: &callbackTracker{},
+		},
 		{
 			desc:     "draws button without a shadow in up state",
 			callback: &callbackTracker{},
@@ -1626,6 +1690,16 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
+		{
+			desc: "width and height are based on the longest line and the number of lines of multi-line text",
+			text: "hi\nworld",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{8, 5},
+				MaximumSize:  image.Point{8, 5},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
 		{
 			desc: "width supports full-width unicode characters",
 			text: "■㈱の世界①",
@@ -18,6 +18,7 @@ package button
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mum4k/termdash/cell"
@@ -92,7 +93,7 @@ func newOptions(text string) *options {
 		textColor:             cell.ColorBlack,
 		textHorizontalPadding: DefaultTextHorizontalPadding,
 		shadowColor:           cell.ColorNumber(240),
-		height:                DefaultHeight,
+		height:                heightFor(text),
 		width:                 widthFor(text),
 		keyUpDelay:            DefaultKeyUpDelay,
 		focusedKeys:           map[keyboard.Key]bool{},
@@ -143,7 +144,8 @@ const DefaultHeight = 3
 
 // Height sets the height of the button in cells.
 // Must be a positive non-zero integer.
-// Defaults to DefaultHeight.
+// Defaults to DefaultHeight for single line text labels. Labels with multiple
+// lines increase the default height by one cell for each additional line.
 func Height(cells int) Option {
 	return option(func(opts *options) {
 		opts.height = cells
@@ -162,6 +164,7 @@ func Width(cells int) Option {
 }
 
 // WidthFor sets the width of the button as if it was displaying the provided text.
+// The width of text with multiple lines is the width of its longest line.
 // Useful when displaying multiple buttons with the intention to set all of
 // their sizes equal to the one with the longest text.
 func WidthFor(text string) Option {
@@ -252,6 +255,18 @@ func TextHorizontalPadding(p int) Option {
 }
 
 // widthFor returns the required width for the specified text.
+// This is the width of the longest line if the text has multiple lines.
 func widthFor(text string) int {
-	return runewidth.StringWidth(text)
+	var width int
+	for _, line := range strings.Split(text, "\n") {
+		if w := runewidth.StringWidth(line); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// heightFor returns the default height for the specified text.
+func heightFor(text string) int {
+	return DefaultHeight + strings.Count(text, "\n")
 }
@@ -0,0 +1,376 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package candlestick contains a widget that displays open, high, low and
+// close values as a candlestick chart.
+package candlestick
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
+	"github.com/mum4k/termdash/widgetapi"
+	"github.com/mum4k/termdash/widgets/internal/axes"
+)
+
+const (
+	// candleCells is the width of one candle including the gap after it.
+	candleCells = 2
+
+	// xAxisHeight is the height of the X axis and its labels.
+	xAxisHeight = 2
+
+	// xLabelSpacing is the minimum number of empty cells between the labels
+	// on the X axis.
+	xLabelSpacing = 2
+)
+
+// Candle are the values of one period, e.g. one minute of trading.
+type Candle struct {
+	// Time is the start of the period, displayed in the labels on the X axis.
+	Time time.Time
+
+	// Open is the first value in the period.
+	Open float64
+	// High is the highest value in the period.
+	High float64
+	// Low is the lowest value in the period.
+	Low float64
+	// Close is the last value in the period.
+	Close float64
+}
+
+// validate validates the candle.
+func (c *Candle) validate() error {
+	for _, v := range []float64{c.Open, c.High, c.Low, c.Close} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid values %+v, all the values must be finite numbers", c)
+		}
+	}
+	if c.High < math.Max(c.Open, c.Close) {
+		return fmt.Errorf("invalid High %v, must be at least the Open(%v) and the Close(%v)", c.High, c.Open, c.Close)
+	}
+	if c.Low > math.Min(c.Open, c.Close) {
+		return fmt.Errorf("invalid Low %v, must be at most the Open(%v) and the Close(%v)", c.Low, c.Open, c.Close)
+	}
+	return nil
+}
+
+// Candlestick displays candles with the open, high, low and close values of
+// consecutive periods. Each candle has a body that spans the open and the
+// close values and a wick that spans the low and the high values. The body
+// has the up color if the candle closed at or above its open value and the
+// down color otherwise.
+//
+// Each candle occupies two cells, when there are more candles than fit the
+// width, only the most recent ones are displayed.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Candlestick struct {
+	// candles are the candles in chronological order.
+	candles []Candle
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the Candlestick.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Candlestick widget.
+func New(opts ...Option) (*Candlestick, error) {
+	opt := newOptions(opts...)
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Candlestick{
+		opts: opt,
+	}, nil
+}
+
+// SetCandles replaces all the candles. The candles must be in chronological
+// order.
+func (cs *Candlestick) SetCandles(candles []Candle) error {
+	for i := range candles {
+		if err := candles[i].validate(); err != nil {
+			return fmt.Errorf("invalid candle %d: %v", i, err)
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	defer cs.dirty.Mark()
+	// Copy to avoid external modifications.
+	cs.candles = append([]Candle(nil), candles...)
+	return nil
+}
+
+// AddCandle adds a candle after all the existing candles.
+func (cs *Candlestick) AddCandle(c Candle) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	defer cs.dirty.Mark()
+	cs.candles = append(cs.candles, c)
+	return nil
+}
+
+// Reset removes all the candles.
+func (cs *Candlestick) Reset() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	defer cs.dirty.Mark()
+	cs.candles = nil
+}
+
+// minMax returns the lowest and the highest value among the candles.
+// Returns zeroes if there are no candles.
+func minMax(candles []Candle) (float64, float64) {
+	if len(candles) == 0 {
+		return 0, 0
+	}
+	min, max := candles[0].Low, candles[0].High
+	for _, c := range candles[1:] {
+		min = math.Min(min, c.Low)
+		max = math.Max(max, c.High)
+	}
+	return min, max
+}
+
+// visible returns the candles that fit the width of the graph in cells.
+// The caller must hold cs.mu.
+func (cs *Candlestick) visible(graphWidth int) []Candle {
+	if fit := graphWidth / candleCells; len(cs.candles) > fit {
+		return cs.candles[len(cs.candles)-fit:]
+	}
+	return cs.candles
+}
+
+// Draw draws the candles onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (cs *Candlestick) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	needAr, err := area.FromSize(cs.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	// The width of the Y axis depends on the values of the visible candles
+	// and the visible candles depend on the remaining width. Start with all
+	// the candles and recalculate the Y axis for the ones that fit. If the
+	// labels of the recalculated axis are wider, drop the oldest candles
+	// that no longer fit, their values are within the scale either way.
+	min, max := minMax(cs.candles)
+	yd, err := cs.yDetails(cvs, min, max)
+	if err != nil {
+		return err
+	}
+	graphAr := image.Rect(yd.Start.X+1, yd.Start.Y, cvs.Area().Max.X, yd.End.Y)
+	candles := cs.visible(graphAr.Dx())
+	if len(candles) < len(cs.candles) {
+		min, max = minMax(candles)
+		if yd, err = cs.yDetails(cvs, min, max); err != nil {
+			return err
+		}
+		graphAr = image.Rect(yd.Start.X+1, yd.Start.Y, cvs.Area().Max.X, yd.End.Y)
+		if fit := graphAr.Dx() / candleCells; len(candles) > fit {
+			candles = candles[len(candles)-fit:]
+		}
+	}
+
+	if err := cs.drawCandles(cvs, graphAr, candles, yd); err != nil {
+		return err
+	}
+	var th *theme.Theme
+	if meta != nil {
+		th = meta.Theme
+	}
+	return cs.drawAxes(cvs, graphAr, candles, yd, th)
+}
+
+// yDetails returns the details of the Y axis for the range of values.
+func (cs *Candlestick) yDetails(cvs *canvas.Canvas, min, max float64) (*axes.YDetails, error) {
+	yd, err := axes.NewYDetails(cvs.Area(), &axes.YProperties{
+		Min:            min,
+		Max:            max,
+		ReqXHeight:     xAxisHeight,
+		ScaleMode:      axes.YScaleModeAdaptive,
+		ValueFormatter: cs.opts.yAxisValueFormatter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("NewYDetails => %v", err)
+	}
+	return yd, nil
+}
+
+// drawCandles draws the candles onto the graph area. Each candle is drawn
+// three pixels wide, the wick is in the middle pixel and one pixel is left
+// empty as a gap before the next candle.
+func (cs *Candlestick) drawCandles(cvs *canvas.Canvas, graphAr image.Rectangle, candles []Candle, yd *axes.YDetails) error {
+	bc, err := braille.New(graphAr)
+	if err != nil {
+		return fmt.Errorf("braille.New => %v", err)
+	}
+
+	for i, c := range candles {
+		color := cs.opts.upColor
+		if c.Close < c.Open {
+			color = cs.opts.downColor
+		}
+
+		var ys []int
+		for _, v := range []float64{c.High, c.Low, c.Open, c.Close} {
+			y, err := yd.Scale.ValueToPixel(v)
+			if err != nil {
+				return fmt.Errorf("failure for candle %d on scale %v, yd.Scale.ValueToPixel(%v) => %v", i, yd.Scale, v, err)
+			}
+			ys = append(ys, y)
+		}
+		high, low, open, close := ys[0], ys[1], ys[2], ys[3]
+		left := i * candleCells * braille.ColMult
+
+		// Y coordinates grow down, so the high value has the lowest one.
+		for y := high; y <= low; y++ {
+			if err := bc.SetPixel(image.Point{left + 1, y}, cell.FgColor(color)); err != nil {
+				return err
+			}
+		}
+		top, bottom := close, open
+		if top > bottom {
+			top, bottom = bottom, top
+		}
+		for y := top; y <= bottom; y++ {
+			for x := left; x < left+3; x++ {
+				if err := bc.SetPixel(image.Point{x, y}, cell.FgColor(color)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// drawAxes draws the X,Y axes and their labels.
+func (cs *Candlestick) drawAxes(cvs *canvas.Canvas, graphAr image.Rectangle, candles []Candle, yd *axes.YDetails, th *theme.Theme) error {
+	axesCellOpts := cs.opts.axesCellOpts
+	xLabelCellOpts := cs.opts.xLabelCellOpts
+	yLabelCellOpts := cs.opts.yLabelCellOpts
+	if th != nil {
+		// The cell options provided by the user take precedence over the
+		// theme, since the last cell option wins.
+		axesCellOpts = append([]cell.Option{cell.FgColor(th.Axis)}, axesCellOpts...)
+		xLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, xLabelCellOpts...)
+		yLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, yLabelCellOpts...)
+	}
+
+	lines := []draw.HVLine{
+		{Start: yd.Start, End: yd.End},
+		{Start: yd.End, End: image.Point{graphAr.Max.X - 1, yd.End.Y}},
+	}
+	if err := draw.HVLines(cvs, lines, draw.HVLineCellOpts(axesCellOpts...)); err != nil {
+		return fmt.Errorf("failed to draw the axes: %v", err)
+	}
+
+	for _, l := range yd.Labels {
+		if err := draw.Text(cvs, l.Value.Text(), l.Pos,
+			draw.TextMaxX(yd.Start.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(yLabelCellOpts...),
+		); err != nil {
+			return fmt.Errorf("failed to draw the Y labels: %v", err)
+		}
+	}
+
+	// The time labels start under the first candle and are placed under the
+	// following candles while they fit without overlapping.
+	next := graphAr.Min.X
+	for i, c := range candles {
+		x := graphAr.Min.X + i*candleCells
+		if x < next {
+			continue
+		}
+		label := c.Time.Format(cs.opts.timeFormat)
+		width := runewidth.StringWidth(label)
+		if x+width > graphAr.Max.X {
+			break
+		}
+		if err := draw.Text(cvs, label, image.Point{x, graphAr.Max.Y + 1}, draw.TextCellOpts(xLabelCellOpts...)); err != nil {
+			return fmt.Errorf("failed to draw the X labels: %v", err)
+		}
+		next = x + width + xLabelSpacing
+	}
+	return nil
+}
+
+// Keyboard input isn't supported on the Candlestick widget.
+func (*Candlestick) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the Candlestick widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Candlestick widget.
+func (*Candlestick) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the Candlestick widget doesn't support mouse events")
+}
+
+// minSize determines the minimum required size to draw the candlestick chart.
+func (cs *Candlestick) minSize() image.Point {
+	// At the very least we need:
+	// - n cells width for the Y axis and its labels as reported by it.
+	// - the width of one candle.
+	min, max := minMax(cs.candles)
+	reqWidth := axes.RequiredWidth(min, max) + candleCells
+
+	// And for the height:
+	// - the X axis and its labels.
+	// - at least 2 cell height for the graph.
+	return image.Point{reqWidth, xAxisHeight + 2}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (cs *Candlestick) Options() widgetapi.Options {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize: cs.minSize(),
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (cs *Candlestick) SetDirtyFunc(markDirty func()) {
+	cs.dirty.Set(markDirty)
+}
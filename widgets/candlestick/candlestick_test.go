@@ -0,0 +1,315 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package candlestick
+
+import (
+	"image"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/theme"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// start is the time of the first candle in the tests.
+var start = time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC)
+
+// twoCandles returns an up candle followed by a down candle.
+func twoCandles() []Candle {
+	return []Candle{
+		{Time: start, Open: 2, High: 4, Low: 0, Close: 3},
+		{Time: start.Add(time.Minute), Open: 3, High: 3, Low: 1, Close: 1},
+	}
+}
+
+// mustAxes draws the Y axis at column x and the X axis at row y up to the
+// column maxX.
+func mustAxes(c *canvas.Canvas, x, y, maxX int, opts ...cell.Option) {
+	testdraw.MustHVLines(c, []draw.HVLine{
+		{Start: image.Point{x, 0}, End: image.Point{x, y}},
+		{Start: image.Point{x, y}, End: image.Point{maxX, y}},
+	}, draw.HVLineCellOpts(opts...))
+}
+
+// mustCandle draws the pixels of the candle at the index with the wick and
+// the body between the specified Y coordinates.
+func mustCandle(bc *braille.Canvas, index, high, low, top, bottom int, color cell.Color) {
+	left := index * candleCells * braille.ColMult
+	for y := high; y <= low; y++ {
+		testbraille.MustSetPixel(bc, image.Point{left + 1, y}, cell.FgColor(color))
+	}
+	for y := top; y <= bottom; y++ {
+		for x := left; x < left+3; x++ {
+			testbraille.MustSetPixel(bc, image.Point{x, y}, cell.FgColor(color))
+		}
+	}
+}
+
+func TestCandlestick(t *testing.T) {
+	tests := []struct {
+		desc         string
+		opts         []Option
+		canvas       image.Rectangle
+		writes       func(*Candlestick) error
+		meta         *widgetapi.Meta
+		want         func(size image.Point) *faketerm.Terminal
+		wantNewErr   bool
+		wantWriteErr bool
+	}{
+		{
+			desc:       "fails on an empty time format",
+			opts:       []Option{TimeFormat("")},
+			canvas:     image.Rect(0, 0, 10, 6),
+			wantNewErr: true,
+		},
+		{
+			desc:   "fails on a NaN value",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(cs *Candlestick) error {
+				return cs.AddCandle(Candle{Open: math.NaN()})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "fails when high is below close",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(cs *Candlestick) error {
+				return cs.SetCandles([]Candle{{Open: 1, High: 2, Low: 1, Close: 3}})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "fails when low is above open",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(cs *Candlestick) error {
+				return cs.SetCandles([]Candle{{Open: 1, High: 3, Low: 2, Close: 3}})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "draws resize needed character when canvas is too small",
+			canvas: image.Rect(0, 0, 2, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the axes without any candles",
+			canvas: image.Rect(0, 0, 10, 5),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 1, 3, 9)
+				testdraw.MustText(c, "0", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws up and down candles",
+			canvas: image.Rect(0, 0, 14, 6),
+			writes: func(cs *Candlestick) error {
+				return cs.SetCandles(twoCandles())
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 13)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "09:30", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 14, 4))
+				mustCandle(bc, 0, 0, 15, 4, 8, DefaultUpColor)
+				mustCandle(bc, 1, 4, 11, 4, 11, DefaultDownColor)
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws candles in custom colors with a custom time format",
+			opts: []Option{
+				UpColor(cell.ColorBlue),
+				DownColor(cell.ColorYellow),
+				TimeFormat("4"),
+			},
+			canvas: image.Rect(0, 0, 14, 6),
+			writes: func(cs *Candlestick) error {
+				for _, c := range twoCandles() {
+					if err := cs.AddCandle(c); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 13)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "30", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 14, 4))
+				mustCandle(bc, 0, 0, 15, 4, 8, cell.ColorBlue)
+				mustCandle(bc, 1, 4, 11, 4, 11, cell.ColorYellow)
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws only the most recent candles that fit",
+			canvas: image.Rect(0, 0, 7, 6),
+			writes: func(cs *Candlestick) error {
+				return cs.SetCandles(twoCandles())
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 6)
+				testdraw.MustText(c, "2.68", image.Point{0, 0})
+				testdraw.MustText(c, "1", image.Point{3, 3})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 7, 4))
+				mustCandle(bc, 0, 1, 15, 1, 15, DefaultDownColor)
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "reset removes the candles",
+			canvas: image.Rect(0, 0, 10, 5),
+			writes: func(cs *Candlestick) error {
+				if err := cs.SetCandles(twoCandles()); err != nil {
+					return err
+				}
+				cs.Reset()
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 1, 3, 9)
+				testdraw.MustText(c, "0", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "uses colors from the theme",
+			canvas: image.Rect(0, 0, 10, 5),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Axis:  cell.ColorBlue,
+					Label: cell.ColorGreen,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 1, 3, 9, cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "0", image.Point{0, 2}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cs, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.writes != nil {
+				err := tc.writes(cs)
+				if (err != nil) != tc.wantWriteErr {
+					t.Errorf("writes => unexpected error: %v, wantWriteErr: %v", err, tc.wantWriteErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			meta := tc.meta
+			if meta == nil {
+				meta = &widgetapi.Meta{}
+			}
+			if err := cs.Draw(c, meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	cs, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := cs.SetCandles(twoCandles()); err != nil {
+		t.Fatalf("SetCandles => unexpected error: %v", err)
+	}
+
+	got := cs.Options()
+	want := widgetapi.Options{
+		MinimumSize: image.Point{4, 4},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary candlestickdemo displays a Candlestick widget with random prices.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/candlestick"
+)
+
+// nextCandle returns a random candle that opens at the close of the previous
+// one.
+func nextCandle(prev candlestick.Candle, period time.Duration) candlestick.Candle {
+	open := prev.Close
+	close := open + (rand.Float64()-0.5)*4
+	return candlestick.Candle{
+		Time:  prev.Time.Add(period),
+		Open:  open,
+		High:  math.Max(open, close) + rand.Float64()*2,
+		Low:   math.Min(open, close) - rand.Float64()*2,
+		Close: close,
+	}
+}
+
+// playCandlestick periodically adds a new candle.
+// Exits when the context expires.
+func playCandlestick(ctx context.Context, cs *candlestick.Candlestick, last candlestick.Candle, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			last = nextCandle(last, time.Minute)
+			if err := cs.AddCandle(last); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cs, err := candlestick.New()
+	if err != nil {
+		panic(err)
+	}
+
+	last := candlestick.Candle{
+		Time:  time.Now().Truncate(time.Minute).Add(-100 * time.Minute),
+		Close: 100,
+	}
+	for i := 0; i < 100; i++ {
+		last = nextCandle(last, time.Minute)
+		if err := cs.AddCandle(last); err != nil {
+			panic(err)
+		}
+	}
+	go playCandlestick(ctx, cs, last, time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(cs),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
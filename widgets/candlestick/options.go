@@ -0,0 +1,138 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package candlestick
+
+// options.go contains configurable options for Candlestick.
+
+import (
+	"errors"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options to New().
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	upColor             cell.Color
+	downColor           cell.Color
+	axesCellOpts        []cell.Option
+	xLabelCellOpts      []cell.Option
+	yLabelCellOpts      []cell.Option
+	timeFormat          string
+	yAxisValueFormatter ValueFormatter
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.timeFormat == "" {
+		return errors.New("the TimeFormat cannot be empty")
+	}
+	return nil
+}
+
+// newOptions returns a new options instance.
+func newOptions(opts ...Option) *options {
+	opt := &options{
+		upColor:    DefaultUpColor,
+		downColor:  DefaultDownColor,
+		timeFormat: DefaultTimeFormat,
+	}
+	for _, o := range opts {
+		o.set(opt)
+	}
+	return opt
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// The default colors of the candles.
+const (
+	DefaultUpColor   = cell.ColorGreen
+	DefaultDownColor = cell.ColorRed
+)
+
+// UpColor sets the color of candles that closed at or above their open value.
+// Defaults to DefaultUpColor.
+func UpColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.upColor = c
+	})
+}
+
+// DownColor sets the color of candles that closed below their open value.
+// Defaults to DefaultDownColor.
+func DownColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.downColor = c
+	})
+}
+
+// AxesCellOpts set the cell options for the X and Y axes.
+// These take precedence over the Axis color of the dashboard theme.
+func AxesCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.axesCellOpts = co
+	})
+}
+
+// XLabelCellOpts set the cell options for the time labels on the X axis.
+// These take precedence over the Label color of the dashboard theme.
+func XLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.xLabelCellOpts = co
+	})
+}
+
+// YLabelCellOpts set the cell options for the labels on the Y axis.
+// These take precedence over the Label color of the dashboard theme.
+func YLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.yLabelCellOpts = co
+	})
+}
+
+// DefaultTimeFormat is the default value for the TimeFormat option.
+const DefaultTimeFormat = "15:04"
+
+// TimeFormat sets the layout used to format the times of the candles into the
+// labels on the X axis, see time.Time.Format.
+// Defaults to DefaultTimeFormat.
+func TimeFormat(layout string) Option {
+	return option(func(opts *options) {
+		opts.timeFormat = layout
+	})
+}
+
+// ValueFormatter formats a value on the Y axis into the text of its label.
+type ValueFormatter func(value float64) string
+
+// YAxisValueFormatter sets the formatter of the labels on the Y axis.
+// By default the values are rounded to two non-zero decimal places.
+func YAxisValueFormatter(vf ValueFormatter) Option {
+	return option(func(opts *options) {
+		opts.yAxisValueFormatter = vf
+	})
+}
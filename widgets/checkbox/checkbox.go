@@ -0,0 +1,200 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkbox implements a widget that toggles a boolean state.
+package checkbox
+
+import (
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Checkbox displays a glyph representing a checked or unchecked state
+// followed by a label.
+//
+// The state is toggled by pressing Space or Enter while the checkbox is
+// focused or by clicking on it.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Checkbox struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// label is displayed after the glyph.
+	label string
+	// checked is the current state.
+	checked bool
+	// pressed indicates that the left mouse button is pressed.
+	pressed bool
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new Checkbox with the provided label.
+func New(label string, opts ...Option) (*Checkbox, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Checkbox{
+		label:   label,
+		checked: opt.checked,
+		opts:    opt,
+	}, nil
+}
+
+// Checked returns the current state of the checkbox.
+func (cb *Checkbox) Checked() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.checked
+}
+
+// SetChecked sets the state of the checkbox. Doesn't call the ChangeFn.
+func (cb *Checkbox) SetChecked(checked bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	defer cb.dirty.Mark()
+	cb.checked = checked
+}
+
+// Draw draws the Checkbox widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (cb *Checkbox) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	ar := cvs.Area()
+	glyph, gOpts := cb.opts.uncheckedGlyph, cb.opts.uncheckedCellOpts
+	if cb.checked {
+		glyph, gOpts = cb.opts.checkedGlyph, cb.opts.checkedCellOpts
+	}
+	gw := runewidth.StringWidth(glyph)
+	if ar.Dx() < gw || ar.Dy() < 1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if err := draw.Text(cvs, glyph, ar.Min, draw.TextCellOpts(gOpts...)); err != nil {
+		return err
+	}
+	if cb.label == "" || ar.Dx() < gw+2 {
+		return nil
+	}
+
+	lOpts := cb.opts.labelCellOpts
+	if meta.Focused {
+		lOpts = append(append([]cell.Option(nil), lOpts...), cb.opts.focusedCellOpts...)
+	}
+	return draw.Text(
+		cvs, cb.label, image.Point{ar.Min.X + gw + 1, ar.Min.Y},
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextMaxX(ar.Max.X),
+		draw.TextCellOpts(lOpts...),
+	)
+}
+
+// toggle toggles the state and returns the new one.
+func (cb *Checkbox) toggle() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.checked = !cb.checked
+	return cb.checked
+}
+
+// changed calls the ChangeFn if one was provided.
+func (cb *Checkbox) changed(checked bool) error {
+	if cb.opts.onChange == nil {
+		return nil
+	}
+	// Mutex must be released when calling the callback.
+	return cb.opts.onChange(checked)
+}
+
+// Keyboard toggles the state on Space or Enter.
+// Implements widgetapi.Widget.Keyboard.
+func (cb *Checkbox) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	if k.Key != keyboard.KeySpace && k.Key != keyboard.KeyEnter {
+		return nil
+	}
+	return cb.changed(cb.toggle())
+}
+
+// press records the state of the left mouse button and returns true when it
+// was just pressed.
+func (cb *Checkbox) press(m *terminalapi.Mouse) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		if cb.pressed {
+			return false
+		}
+		cb.pressed = true
+		return true
+	case mouse.ButtonRelease:
+		cb.pressed = false
+	}
+	return false
+}
+
+// Mouse toggles the state when the checkbox is clicked.
+// Implements widgetapi.Widget.Mouse.
+func (cb *Checkbox) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	if !cb.press(m) {
+		return nil
+	}
+	return cb.changed(cb.toggle())
+}
+
+// Options implements widgetapi.Widget.Options.
+func (cb *Checkbox) Options() widgetapi.Options {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	gw := runewidth.StringWidth(cb.opts.checkedGlyph)
+	width := gw
+	if cb.label != "" {
+		width += 1 + runewidth.StringWidth(cb.label)
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{gw, 1},
+		MaximumSize:  image.Point{width, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (cb *Checkbox) SetDirtyFunc(markDirty func()) {
+	cb.dirty.Set(markDirty)
+}
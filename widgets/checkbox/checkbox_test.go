@@ -0,0 +1,286 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkbox
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// changeTracker tracks calls to the ChangeFn.
+type changeTracker struct {
+	// calls are the states the ChangeFn was called with.
+	calls []bool
+	// wantErr when set to true, makes the ChangeFn return an error.
+	wantErr bool
+}
+
+// change implements ChangeFn.
+func (ct *changeTracker) change(checked bool) error {
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, checked)
+	return nil
+}
+
+func TestCheckbox(t *testing.T) {
+	tests := []struct {
+		desc         string
+		label        string
+		opts         []Option
+		canvas       image.Rectangle
+		meta         *widgetapi.Meta
+		events       []terminalapi.Event
+		tracker      *changeTracker
+		want         func(size image.Point) *faketerm.Terminal
+		wantChecked  bool
+		wantCalls    []bool
+		wantNewErr   bool
+		wantEventErr bool
+	}{
+		{
+			desc:       "fails on glyphs of different widths",
+			opts:       []Option{Glyphs("[x]", "[]")},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on empty glyphs",
+			opts:       []Option{Glyphs("", "")},
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws an unchecked checkbox",
+			label:  "Enabled",
+			canvas: image.Rect(0, 0, 12, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "Enabled", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:  "draws a checked checkbox with custom glyphs and cell options",
+			label: "Enabled",
+			opts: []Option{
+				Checked(),
+				Glyphs("☑", "☐"),
+				CheckedCellOpts(cell.FgColor(cell.ColorGreen)),
+				LabelCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 12, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "☑", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testdraw.MustText(cvs, "Enabled", image.Point{2, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantChecked: true,
+		},
+		{
+			desc:   "highlights the label when focused and truncates it",
+			label:  "Enabled",
+			canvas: image.Rect(0, 0, 8, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "Ena…", image.Point{4, 0}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "requests resize when the glyph doesn't fit",
+			label:  "Enabled",
+			canvas: image.Rect(0, 0, 2, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "toggles using the keyboard",
+			label:  "A",
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[x]", image.Point{0, 0})
+				testdraw.MustText(cvs, "A", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantChecked: true,
+			wantCalls:   []bool{true, false, true},
+		},
+		{
+			desc:   "toggles once per mouse click",
+			label:  "A",
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonRelease},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[x]", image.Point{0, 0})
+				testdraw.MustText(cvs, "A", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantChecked: true,
+			wantCalls:   []bool{true},
+		},
+		{
+			desc:   "returns the error from the ChangeFn",
+			label:  "A",
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			tracker:      &changeTracker{wantErr: true},
+			wantEventErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.tracker != nil {
+				opts = append(opts, OnChange(tc.tracker.change))
+			}
+			cb, err := New(tc.label, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = cb.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = cb.Mouse(e, &widgetapi.EventMeta{})
+				}
+				if err != nil {
+					break
+				}
+			}
+			if (err != nil) != tc.wantEventErr {
+				t.Errorf("event => unexpected error: %v, wantEventErr: %v", err, tc.wantEventErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := cb.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if got := cb.Checked(); got != tc.wantChecked {
+				t.Errorf("Checked => %v, want %v", got, tc.wantChecked)
+			}
+			if tc.tracker != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.tracker.calls); diff != "" {
+					t.Errorf("ChangeFn calls => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSetChecked(t *testing.T) {
+	cb, err := New("A")
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	cb.SetChecked(true)
+	if !cb.Checked() {
+		t.Errorf("Checked => false after SetChecked(true), want true")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	cb, err := New("Enabled")
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := cb.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{3, 1},
+		MaximumSize:  image.Point{11, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
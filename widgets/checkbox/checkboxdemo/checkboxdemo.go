@@ -0,0 +1,112 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary checkboxdemo displays settings toggled with Checkbox widgets.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/checkbox"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	// onChange returns a checkbox.ChangeFn that logs the new state.
+	onChange := func(name string) checkbox.ChangeFn {
+		return func(checked bool) error {
+			return log.Write(fmt.Sprintf("%s: %v\n", name, checked))
+		}
+	}
+
+	notifications, err := checkbox.New("Notifications",
+		checkbox.Checked(),
+		checkbox.CheckedCellOpts(cell.FgColor(cell.ColorGreen)),
+		checkbox.OnChange(onChange("Notifications")),
+	)
+	if err != nil {
+		panic(err)
+	}
+	sounds, err := checkbox.New("Sounds",
+		checkbox.Glyphs("☑", "☐"),
+		checkbox.OnChange(onChange("Sounds")),
+	)
+	if err != nil {
+		panic(err)
+	}
+	updates, err := checkbox.New("Automatic updates",
+		checkbox.OnChange(onChange("Automatic updates")),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.SplitHorizontal(
+					container.Top(container.PlaceWidget(notifications)),
+					container.Bottom(
+						container.SplitHorizontal(
+							container.Top(container.PlaceWidget(sounds)),
+							container.Bottom(container.PlaceWidget(updates)),
+						),
+					),
+					container.SplitPercent(33),
+				),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Changes"),
+				container.PlaceWidget(log),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkbox
+
+// options.go contains configurable options for Checkbox.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	checked           bool
+	checkedGlyph      string
+	uncheckedGlyph    string
+	checkedCellOpts   []cell.Option
+	uncheckedCellOpts []cell.Option
+	labelCellOpts     []cell.Option
+	focusedCellOpts   []cell.Option
+	onChange          ChangeFn
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		checkedGlyph:    DefaultCheckedGlyph,
+		uncheckedGlyph:  DefaultUncheckedGlyph,
+		focusedCellOpts: []cell.Option{cell.Inverse()},
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.checkedGlyph == "" || o.uncheckedGlyph == "" {
+		return fmt.Errorf("invalid glyphs %q and %q, they cannot be empty", o.checkedGlyph, o.uncheckedGlyph)
+	}
+	if cw, uw := runewidth.StringWidth(o.checkedGlyph), runewidth.StringWidth(o.uncheckedGlyph); cw != uw {
+		return fmt.Errorf("invalid glyphs %q and %q, they must occupy the same number of cells, got %d and %d", o.checkedGlyph, o.uncheckedGlyph, cw, uw)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// Checked makes the checkbox initially checked.
+func Checked() Option {
+	return option(func(opts *options) {
+		opts.checked = true
+	})
+}
+
+// The default glyphs that represent the state of the checkbox.
+const (
+	DefaultCheckedGlyph   = "[x]"
+	DefaultUncheckedGlyph = "[ ]"
+)
+
+// Glyphs sets the text displayed in front of the label when the checkbox is
+// checked and unchecked. Both glyphs must occupy the same number of cells.
+// Defaults to DefaultCheckedGlyph and DefaultUncheckedGlyph.
+func Glyphs(checked, unchecked string) Option {
+	return option(func(opts *options) {
+		opts.checkedGlyph = checked
+		opts.uncheckedGlyph = unchecked
+	})
+}
+
+// CheckedCellOpts sets the cell options of the glyph of a checked checkbox.
+// Defaults to the default cell options.
+func CheckedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.checkedCellOpts = co
+	})
+}
+
+// UncheckedCellOpts sets the cell options of the glyph of an unchecked
+// checkbox.
+// Defaults to the default cell options.
+func UncheckedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.uncheckedCellOpts = co
+	})
+}
+
+// LabelCellOpts sets the cell options of the label.
+// Defaults to the default cell options.
+func LabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.labelCellOpts = co
+	})
+}
+
+// FocusedCellOpts sets the cell options applied on top of the label when the
+// checkbox is focused.
+// Defaults to inverse colors.
+func FocusedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.focusedCellOpts = co
+	})
+}
+
+// ChangeFn is called when the user checks or unchecks the checkbox. The
+// argument is the new state.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Checkbox isn't locked while the function
+// executes, so it can read from or modify the Checkbox.
+type ChangeFn func(checked bool) error
+
+// OnChange sets the function that is called when the user changes the state
+// of the checkbox.
+func OnChange(fn ChangeFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
@@ -57,6 +57,31 @@ func startEndAngles(current, total, startAngle, direction int) (start, end int)
 	return startAngle, end
 }
 
+// segmentAngles returns the starting and the ending angle of the arc that
+// represents a segment which spans from the before value to the after value
+// out of the total, given the desired start angle and direction of the donut.
+// Returns equal angles if the segment is too small to be drawn.
+func segmentAngles(before, after, total, startAngle, direction int) (start, end int) {
+	const fullCircle = 360
+	if after-before == total {
+		return 0, fullCircle
+	}
+
+	angleAt := func(v int) int {
+		a := startAngle + int(math.Round(float64(direction)*float64(fullCircle)*float64(v)/float64(total)))
+		return ((a % fullCircle) + fullCircle) % fullCircle
+	}
+	from, to := angleAt(before), angleAt(after)
+	if direction < 0 {
+		// Arcs are drawn counter-clockwise.
+		from, to = to, from
+	}
+	if to == 0 && from != 0 {
+		to = fullCircle
+	}
+	return from, to
+}
+
 // midAndRadius given an area of a braille canvas, determines the mid point in
 // pixels and radius to draw the largest circle that fits.
 // The circle's mid point is always positioned on the {0,1} pixel in the chosen
@@ -347,6 +347,89 @@ func TestStartEndAngles(t *testing.T) {
 	}
 }
 
+func TestSegmentAngles(t *testing.T) {
+	tests := []struct {
+		desc       string
+		before     int
+		after      int
+		total      int
+		startAngle int
+		direction  int
+		wantStart  int
+		wantEnd    int
+	}{
+		{
+			desc:       "single segment is a full circle",
+			before:     0,
+			after:      10,
+			total:      10,
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  0,
+			wantEnd:    360,
+		},
+		{
+			desc:       "first quarter, clockwise",
+			before:     0,
+			after:      1,
+			total:      4,
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  0,
+			wantEnd:    90,
+		},
+		{
+			desc:       "second quarter, clockwise",
+			before:     1,
+			after:      2,
+			total:      4,
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  270,
+			wantEnd:    360,
+		},
+		{
+			desc:       "first quarter, counter-clockwise",
+			before:     0,
+			after:      1,
+			total:      4,
+			startAngle: 90,
+			direction:  1,
+			wantStart:  90,
+			wantEnd:    180,
+		},
+		{
+			desc:       "last quarter crosses zero, counter-clockwise",
+			before:     3,
+			after:      4,
+			total:      4,
+			startAngle: 45,
+			direction:  1,
+			wantStart:  315,
+			wantEnd:    45,
+		},
+		{
+			desc:       "segment too small to draw",
+			before:     0,
+			after:      1,
+			total:      1000,
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  90,
+			wantEnd:    90,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotStart, gotEnd := segmentAngles(tc.before, tc.after, tc.total, tc.startAngle, tc.direction)
+			if gotStart != tc.wantStart || gotEnd != tc.wantEnd {
+				t.Errorf("segmentAngles => (%d, %d), want (%d, %d)", gotStart, gotEnd, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
+
 func TestMidAndRadius(t *testing.T) {
 	tests := []struct {
 		desc      string
@@ -24,10 +24,12 @@ import (
 	"sync"
 
 	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/terminal/terminalapi"
@@ -49,17 +51,23 @@ func (pt progressType) String() string {
 var progressTypeNames = map[progressType]string{
 	progressTypePercent:  "progressTypePercent",
 	progressTypeAbsolute: "progressTypeAbsolute",
+	progressTypeSegments: "progressTypeSegments",
 }
 
 const (
 	progressTypePercent = iota
 	progressTypeAbsolute
+	progressTypeSegments
 )
 
 // Donut displays the progress of an operation by filling a partial circle and
 // eventually by completing a full circle. The circle can have a "hole" in the
 // middle, which is where the name comes from.
 //
+// Alternatively the Donut displays a breakdown of multiple values as
+// proportionally sized arcs that together complete the full circle, see
+// Segments.
+//
 // Implements widgetapi.Widget. This object is thread-safe.
 type Donut struct {
 	// pt indicates how current and total are interpreted.
@@ -68,8 +76,14 @@ type Donut struct {
 	current int
 	// total is the value that represents completion.
 	// For progressTypePercent, this is 100, for progressTypeAbsolute this is
-	// the total provided by the caller.
+	// the total provided by the caller. For progressTypeSegments, this is the
+	// sum of the segments.
 	total int
+	// segments are the values of the segments for progressTypeSegments.
+	segments []int
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the Donut.
 	mu sync.Mutex
 
@@ -86,9 +100,29 @@ func New(opts ...Option) (*Donut, error) {
 	if err := opt.validate(); err != nil {
 		return nil, err
 	}
-	return &Donut{
+
+	d := &Donut{
 		opts: opt,
-	}, nil
+	}
+	if err := d.setInitial(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// setInitial sets the progress provided via the InitialPercent or the
+// InitialAbsolute option.
+func (d *Donut) setInitial() error {
+	ip := d.opts.initial
+	if ip == nil {
+		return nil
+	}
+	switch ip.pt {
+	case progressTypeAbsolute:
+		return d.Absolute(ip.done, ip.total)
+	default:
+		return d.Percent(ip.done)
+	}
 }
 
 // Absolute sets the progress in absolute numbers, e.g. 7 out of 10.
@@ -98,6 +132,7 @@ func New(opts ...Option) (*Donut, error) {
 func (d *Donut) Absolute(done, total int, opts ...Option) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	defer d.dirty.Mark()
 
 	if done < 0 || total < 1 || done > total {
 		return fmt.Errorf("invalid progress, done(%d) must be <= total(%d), done must be zero or positive "+
@@ -123,6 +158,7 @@ func (d *Donut) Absolute(done, total int, opts ...Option) error {
 func (d *Donut) Percent(p int, opts ...Option) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	defer d.dirty.Mark()
 
 	if p < 0 || p > 100 {
 		return fmt.Errorf("invalid percentage, p(%d) must be 0 <= p <= 100", p)
@@ -141,6 +177,46 @@ func (d *Donut) Percent(p int, opts ...Option) error {
 	return nil
 }
 
+// Segments sets values that are displayed as proportionally sized arcs of the
+// donut, each drawn in its own color, see the SegmentColors option. The arcs
+// follow each other in the order of the values and together form a full
+// circle. All values must be zero or positive and at least one value must be
+// positive.
+// The text progress isn't displayed in the middle of the donut when it shows
+// segments, use the ShowLegend option to display the share of each segment.
+// Provided options override values set when New() was called.
+func (d *Donut) Segments(values []int, opts ...Option) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	defer d.dirty.Mark()
+
+	var sum int
+	for i, v := range values {
+		if v < 0 {
+			return fmt.Errorf("invalid segment value %d at index %d, must be zero or positive", v, i)
+		}
+		sum += v
+	}
+	if sum == 0 {
+		return fmt.Errorf("invalid segments %v, at least one value must be positive", values)
+	}
+
+	for _, opt := range opts {
+		opt.set(d.opts)
+	}
+	if err := d.opts.validate(); err != nil {
+		return err
+	}
+
+	d.pt = progressTypeSegments
+	// Copy to avoid external modifications.
+	d.segments = make([]int, len(values))
+	copy(d.segments, values)
+	d.current = sum
+	d.total = sum
+	return nil
+}
+
 // progressText returns the textual representation of the current progress.
 func (d *Donut) progressText() string {
 	switch d.pt {
@@ -171,7 +247,7 @@ func (d *Donut) drawText(cvs *canvas.Canvas, mid image.Point, holeR int) error {
 	cells, first := availableCells(mid, holeR)
 	t := d.progressText()
 	needCells := runewidth.StringWidth(t)
-	if cells < needCells {
+	if needCells == 0 || cells < needCells {
 		return nil
 	}
 
@@ -200,6 +276,112 @@ func (d *Donut) drawLabel(cvs *canvas.Canvas, labelAr image.Rectangle) error {
 	)
 }
 
+// segmentColor determines the color of the i-th segment.
+// Colors are optional and don't have to be specified for all the segments,
+// the remaining segments cycle through the DefaultSegmentColors.
+func (d *Donut) segmentColor(i int) cell.Color {
+	if len(d.opts.segmentColors) > i {
+		return d.opts.segmentColors[i]
+	}
+	return DefaultSegmentColors[i%len(DefaultSegmentColors)]
+}
+
+// drawProgress draws the arc representing the progress.
+// The mid point and the radius are in pixels on the braille canvas.
+func (d *Donut) drawProgress(bc *braille.Canvas, mid image.Point, r int) error {
+	startA, endA := startEndAngles(d.current, d.total, d.opts.startAngle, d.opts.direction)
+	if err := draw.BrailleCircle(bc, mid, r,
+		draw.BrailleCircleFilled(),
+		draw.BrailleCircleArcOnly(startA, endA),
+		draw.BrailleCircleCellOpts(d.opts.cellOpts...),
+	); err != nil {
+		return fmt.Errorf("failed to draw the outer circle: %v", err)
+	}
+	return nil
+}
+
+// drawSegments draws one arc for each of the segments.
+// The mid point and the radius are in pixels on the braille canvas.
+// Neighboring segments can share braille cells, in which case the cell takes
+// the color of the later segment.
+func (d *Donut) drawSegments(bc *braille.Canvas, mid image.Point, r int) error {
+	var before int
+	for i, v := range d.segments {
+		if v == 0 {
+			continue
+		}
+		startA, endA := segmentAngles(before, before+v, d.total, d.opts.startAngle, d.opts.direction)
+		before += v
+		if startA == endA {
+			// The segment is too small to be visible.
+			continue
+		}
+
+		cOpts := append([]cell.Option{cell.FgColor(d.segmentColor(i))}, d.opts.cellOpts...)
+		if err := draw.BrailleCircle(bc, mid, r,
+			draw.BrailleCircleFilled(),
+			draw.BrailleCircleArcOnly(startA, endA),
+			draw.BrailleCircleCellOpts(cOpts...),
+		); err != nil {
+			return fmt.Errorf("failed to draw segment %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// legendWidth returns the width of the legend in cells.
+func (d *Donut) legendWidth() int {
+	var labelW int
+	for i := range d.segments {
+		if len(d.opts.segmentLabels) > i {
+			if w := runewidth.StringWidth(d.opts.segmentLabels[i]); w > labelW {
+				labelW = w
+			}
+		}
+	}
+	// The marker and a space, the label and a space, the percentage.
+	return 2 + labelW + 1 + len("100%")
+}
+
+// drawLegend draws the legend that lists the segments in the area.
+func (d *Donut) drawLegend(cvs *canvas.Canvas, legendAr image.Rectangle) error {
+	y := legendAr.Min.Y
+	if rows := len(d.segments); rows < legendAr.Dy() {
+		y += (legendAr.Dy() - rows) / 2
+	}
+
+	labelW := legendAr.Dx() - 2 - 1 - len("100%")
+	for i, v := range d.segments {
+		if y >= legendAr.Max.Y {
+			break
+		}
+		x := legendAr.Min.X
+		if _, err := cvs.SetCell(image.Point{x, y}, legendMarker, cell.FgColor(d.segmentColor(i))); err != nil {
+			return err
+		}
+		x += 2
+
+		if len(d.opts.segmentLabels) > i && labelW > 0 {
+			if err := draw.Text(
+				cvs, d.opts.segmentLabels[i], image.Point{x, y},
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextMaxX(x+labelW),
+				draw.TextCellOpts(d.opts.legendCellOpts...),
+			); err != nil {
+				return err
+			}
+		}
+		x += labelW + 1
+
+		pct := int(math.Round(float64(v) * 100 / float64(d.total)))
+		if err := draw.Text(cvs, fmt.Sprintf("%3d%%", pct), image.Point{x, y}, draw.TextCellOpts(d.opts.legendCellOpts...)); err != nil {
+			return err
+		}
+		y++
+	}
+	return nil
+}
+
 // Draw draws the Donut widget onto the canvas.
 // Implements widgetapi.Widget.Draw.
 func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
@@ -212,7 +394,7 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return nil
 	}
 
-	var donutAr, labelAr image.Rectangle
+	var donutAr, labelAr, legendAr image.Rectangle
 	if len(d.opts.label) > 0 {
 		d, l, err := donutAndLabel(cvs.Area())
 		if err != nil {
@@ -225,6 +407,15 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		donutAr = cvs.Area()
 	}
 
+	if d.pt == progressTypeSegments && d.opts.showLegend {
+		lw := d.legendWidth()
+		if donutAr.Dx() < minSize.X+1+lw {
+			return draw.ResizeNeeded(cvs)
+		}
+		legendAr = image.Rect(donutAr.Max.X-lw, donutAr.Min.Y, donutAr.Max.X, donutAr.Max.Y)
+		donutAr.Max.X = legendAr.Min.X - 1
+	}
+
 	if donutAr.Dx() < minSize.X || donutAr.Dy() < minSize.Y {
 		// Reserving area for the label might have resulted in donutAr being
 		// too small.
@@ -237,12 +428,13 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	mid, r := midAndRadius(bc.Area())
-	if err := draw.BrailleCircle(bc, mid, r,
-		draw.BrailleCircleFilled(),
-		draw.BrailleCircleArcOnly(startA, endA),
-		draw.BrailleCircleCellOpts(d.opts.cellOpts...),
-	); err != nil {
-		return fmt.Errorf("failed to draw the outer circle: %v", err)
+	if d.pt == progressTypeSegments {
+		err = d.drawSegments(bc, mid, r)
+	} else {
+		err = d.drawProgress(bc, mid, r)
+	}
+	if err != nil {
+		return err
 	}
 
 	holeR := d.holeRadius(r)
@@ -269,6 +461,11 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 			return err
 		}
 	}
+	if !legendAr.Empty() {
+		if err := d.drawLegend(cvs, legendAr); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -285,8 +482,23 @@ func (*Donut) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
 // minSize is the smallest area we can draw donut on.
 var minSize = image.Point{3, 3}
 
+// legendMarker is the rune that marks the color of a segment in the legend.
+const legendMarker = '■'
+
 // Options implements widgetapi.Widget.Options.
 func (d *Donut) Options() widgetapi.Options {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.opts.showLegend {
+		// The legend takes space on the right of the donut, the ratio would
+		// only make the donut smaller.
+		return widgetapi.Options{
+			MinimumSize:  image.Point{minSize.X + 1 + d.legendWidth(), minSize.Y},
+			WantKeyboard: widgetapi.KeyScopeNone,
+			WantMouse:    widgetapi.MouseScopeNone,
+		}
+	}
 	return widgetapi.Options{
 		// We are drawing a circle, ensure equal ratio of rows and columns.
 		// This is adjusted for the inequality of the braille canvas.
@@ -299,6 +511,11 @@ func (d *Donut) Options() widgetapi.Options {
 	}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (d *Donut) SetDirtyFunc(markDirty func()) {
+	d.dirty.Set(markDirty)
+}
+
 // donutAndLabel splits the canvas area into an area for the donut and an
 // area under the donut for the text label.
 func donutAndLabel(cvsAr image.Rectangle) (donAr, labelAr image.Rectangle, err error) {
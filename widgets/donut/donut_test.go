@@ -51,6 +51,22 @@ func TestDonut(t *testing.T) {
 			canvas:     image.Rect(0, 0, 3, 3),
 			wantNewErr: true,
 		},
+		{
+			desc: "New fails on invalid initial percent",
+			opts: []Option{
+				InitialPercent(101),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on invalid initial absolute progress",
+			opts: []Option{
+				InitialAbsolute(1, 0),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
 		{
 			desc: "New fails on too large donut hole percent",
 			opts: []Option{
@@ -819,6 +835,123 @@ func TestDonut(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "Segments fails on negative value",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(d *Donut) error {
+				return d.Segments([]int{1, -1})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Segments fails when no value is positive",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(d *Donut) error {
+				return d.Segments([]int{0, 0})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Segments fails on too small start angle",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(d *Donut) error {
+				return d.Segments([]int{1}, StartAngle(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "displays segments, clockwise",
+			canvas: image.Rect(0, 0, 7, 7),
+			update: func(d *Donut) error {
+				return d.Segments([]int{1, 1}, HolePercent(80))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				bc := testbraille.MustNew(c.Area())
+
+				testdraw.MustBrailleCircle(bc, image.Point{6, 13}, 6,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleArcOnly(270, 90),
+					draw.BrailleCircleCellOpts(cell.FgColor(cell.ColorBlue)),
+				)
+				testdraw.MustBrailleCircle(bc, image.Point{6, 13}, 6,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleArcOnly(90, 270),
+					draw.BrailleCircleCellOpts(cell.FgColor(cell.ColorGreen)),
+				)
+				testdraw.MustBrailleCircle(bc, image.Point{6, 13}, 5,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleClearPixels(),
+				)
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "displays segments with a legend",
+			opts: []Option{
+				SegmentColors([]cell.Color{cell.ColorRed}),
+				SegmentLabels([]string{"a", "", "bb"}),
+				ShowLegend(),
+				LegendCellOpts(cell.FgColor(cell.ColorWhite)),
+			},
+			canvas: image.Rect(0, 0, 20, 7),
+			update: func(d *Donut) error {
+				return d.Segments([]int{1, 0, 1}, CounterClockwise())
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				bc := testbraille.MustNew(image.Rect(0, 0, 10, 7))
+
+				testdraw.MustBrailleCircle(bc, image.Point{10, 13}, 9,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleArcOnly(90, 270),
+					draw.BrailleCircleCellOpts(cell.FgColor(cell.ColorRed)),
+				)
+				testdraw.MustBrailleCircle(bc, image.Point{10, 13}, 9,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleArcOnly(270, 90),
+					draw.BrailleCircleCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testdraw.MustBrailleCircle(bc, image.Point{10, 13}, 3,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleClearPixels(),
+				)
+				testbraille.MustCopyTo(bc, c)
+
+				legendOpts := draw.TextCellOpts(cell.FgColor(cell.ColorWhite))
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '■', cell.FgColor(cell.ColorRed))
+				testdraw.MustText(c, "a", image.Point{13, 2}, legendOpts)
+				testdraw.MustText(c, " 50%", image.Point{16, 2}, legendOpts)
+				testcanvas.MustSetCell(c, image.Point{11, 3}, '■', cell.FgColor(cell.ColorGreen))
+				testdraw.MustText(c, "  0%", image.Point{16, 3}, legendOpts)
+				testcanvas.MustSetCell(c, image.Point{11, 4}, '■', cell.FgColor(cell.ColorYellow))
+				testdraw.MustText(c, "bb", image.Point{13, 4}, legendOpts)
+				testdraw.MustText(c, " 50%", image.Point{16, 4}, legendOpts)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "legend makes the canvas too small",
+			opts:   []Option{ShowLegend()},
+			canvas: image.Rect(0, 0, 7, 7),
+			update: func(d *Donut) error {
+				return d.Segments([]int{1})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -916,3 +1049,23 @@ func TestOptions(t *testing.T) {
 	}
 
 }
+
+func TestOptionsWithLegend(t *testing.T) {
+	d, err := New(ShowLegend(), SegmentLabels([]string{"abc"}))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := d.Segments([]int{1}); err != nil {
+		t.Fatalf("Segments => unexpected error: %v", err)
+	}
+
+	got := d.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{14, 3},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/mum4k/termdash"
@@ -78,6 +79,30 @@ func playDonut(ctx context.Context, d *donut.Donut, start, step int, delay time.
 	}
 }
 
+// playSegments periodically changes the values of the segments on the donut
+// once every delay. Exits when the context expires.
+func playSegments(ctx context.Context, d *donut.Donut, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			values := []int{
+				20 + rand.Intn(30),
+				10 + rand.Intn(20),
+				5 + rand.Intn(15),
+				rand.Intn(10),
+			}
+			if err := d.Segments(values); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func main() {
 	t, err := tcell.New()
 	if err != nil {
@@ -113,23 +138,41 @@ func main() {
 	}
 	go playDonut(ctx, red, 75, 1, 2*time.Second, playTypeAbsolute)
 
+	usage, err := donut.New(
+		donut.SegmentLabels([]string{"Documents", "Photos", "Music", "Other"}),
+		donut.ShowLegend(),
+		donut.Label("disk usage"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := usage.Segments([]int{40, 25, 15, 5}); err != nil {
+		panic(err)
+	}
+	go playSegments(ctx, usage, 3*time.Second)
+
 	c, err := container.New(
 		t,
 		container.Border(linestyle.Light),
 		container.BorderTitle("PRESS Q TO QUIT"),
-		container.SplitVertical(
-			container.Left(
-				container.SplitVertical(
-					container.Left(container.PlaceWidget(green)),
-					container.Right(container.PlaceWidget(blue)),
-				),
-			),
-			container.Right(
+		container.SplitHorizontal(
+			container.Top(
 				container.SplitVertical(
-					container.Left(container.PlaceWidget(yellow)),
-					container.Right(container.PlaceWidget(red)),
+					container.Left(
+						container.SplitVertical(
+							container.Left(container.PlaceWidget(green)),
+							container.Right(container.PlaceWidget(blue)),
+						),
+					),
+					container.Right(
+						container.SplitVertical(
+							container.Left(container.PlaceWidget(yellow)),
+							container.Right(container.PlaceWidget(red)),
+						),
+					),
 				),
 			),
+			container.Bottom(container.PlaceWidget(usage)),
 		),
 	)
 	if err != nil {
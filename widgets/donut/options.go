@@ -54,6 +54,12 @@ type options struct {
 	// The direction in which the donut completes as progress increases.
 	// Positive for counter-clockwise, negative for clockwise.
 	direction int
+	initial   *initialProgress
+
+	segmentColors  []cell.Color
+	segmentLabels  []string
+	showLegend     bool
+	legendCellOpts []cell.Option
 }
 
 // validate validates the provided options.
@@ -181,3 +187,91 @@ func LabelAlign(la align.Horizontal) Option {
 		opts.labelAlign = la
 	})
 }
+
+// initialProgress is the progress provided via the InitialPercent or the
+// InitialAbsolute option.
+type initialProgress struct {
+	pt    progressType
+	done  int
+	total int
+}
+
+// InitialPercent sets the progress in percents that the Donut displays from
+// the start, as if Percent was called right after New.
+// Only takes effect when provided to New.
+func InitialPercent(p int) Option {
+	return option(func(opts *options) {
+		opts.initial = &initialProgress{
+			pt:   progressTypePercent,
+			done: p,
+		}
+	})
+}
+
+// InitialAbsolute sets the progress in absolute numbers that the Donut
+// displays from the start, as if Absolute was called right after New.
+// Only takes effect when provided to New.
+func InitialAbsolute(done, total int) Option {
+	return option(func(opts *options) {
+		opts.initial = &initialProgress{
+			pt:    progressTypeAbsolute,
+			done:  done,
+			total: total,
+		}
+	})
+}
+
+// DefaultSegmentColors are the colors of the segments, unless specified
+// otherwise via the SegmentColors option. Segments cycle through these colors.
+var DefaultSegmentColors = []cell.Color{
+	cell.ColorBlue,
+	cell.ColorGreen,
+	cell.ColorYellow,
+	cell.ColorRed,
+	cell.ColorMagenta,
+	cell.ColorCyan,
+}
+
+// SegmentColors sets the colors of each of the segments.
+// Segments are created on a call to Segments(), each value ends up in its own
+// segment. The first supplied color applies to the segment displaying the
+// first value. Any segments that don't have a color specified use the
+// DefaultSegmentColors.
+func SegmentColors(colors []cell.Color) Option {
+	return option(func(opts *options) {
+		// Copy to avoid external modifications.
+		opts.segmentColors = make([]cell.Color, len(colors))
+		copy(opts.segmentColors, colors)
+	})
+}
+
+// SegmentLabels sets the labels of each of the segments displayed in the
+// legend.
+// The first supplied label applies to the segment displaying the first value.
+// If not specified, the corresponding segment (or all the segments) don't
+// have a label.
+func SegmentLabels(labels []string) Option {
+	return option(func(opts *options) {
+		// Copy to avoid external modifications.
+		opts.segmentLabels = make([]string, len(labels))
+		copy(opts.segmentLabels, labels)
+	})
+}
+
+// ShowLegend displays a legend on the right of the donut. The legend has one
+// row per segment with a marker in the color of the segment, the segment
+// label and the share of the segment in percents.
+// Only takes effect when the Donut displays segments, see Segments.
+func ShowLegend() Option {
+	return option(func(opts *options) {
+		opts.showLegend = true
+	})
+}
+
+// LegendCellOpts sets cell options on cells that contain the labels and the
+// percentages in the legend.
+func LegendCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.legendCellOpts = cOpts
+	})
+}
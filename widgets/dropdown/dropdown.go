@@ -0,0 +1,432 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dropdown implements a widget that selects one of several choices
+// from a list that opens on demand.
+package dropdown
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// The arrows displayed at the end of the field when the list is closed and
+// open.
+const (
+	closedArrow = '▼'
+	openArrow   = '▲'
+)
+
+// minWidth is the minimum width of the widget in cells, one for the text and
+// two for the space and the arrow.
+const minWidth = 3
+
+// Dropdown displays the selected choice and a list of all the choices when
+// opened.
+//
+// The list is opened by pressing Enter, Space or the Down arrow key while the
+// dropdown is focused or by clicking on it. Typing while the list is open
+// filters the choices to those that contain the typed text. The Up and Down
+// arrow keys highlight a choice, Enter selects it and Esc closes the list.
+// Clicking on a choice selects it.
+//
+// The open list is drawn over the rows below the selected choice, so the
+// widget requests the space for it via its maximum height, see ListRows.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Dropdown struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// choices are the choices the user selects from.
+	choices []string
+	// selected is the index of the selected choice.
+	selected int
+
+	// open indicates that the list is displayed.
+	open bool
+	// filter is the text typed while the list is open.
+	filter []rune
+	// matches are the indexes of the choices that match the filter.
+	matches []int
+	// highlighted is the index into matches of the highlighted choice.
+	highlighted int
+	// first is the index into matches of the first displayed choice.
+	first int
+
+	// listAr is the area occupied by the list the last time Draw was called.
+	listAr image.Rectangle
+	// pressed indicates that the left mouse button is pressed.
+	pressed bool
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new Dropdown with the provided choices.
+func New(choices []string, opts ...Option) (*Dropdown, error) {
+	if len(choices) == 0 {
+		return nil, errors.New("a dropdown requires at least one choice")
+	}
+	for _, c := range choices {
+		if err := wrap.ValidText(c); err != nil {
+			return nil, fmt.Errorf("invalid choice %q: %v", c, err)
+		}
+		if strings.ContainsRune(c, '\n') {
+			return nil, fmt.Errorf("invalid choice %q: newline characters aren't allowed", c)
+		}
+	}
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(len(choices)); err != nil {
+		return nil, err
+	}
+	return &Dropdown{
+		choices:  append([]string(nil), choices...),
+		selected: opt.selected,
+		opts:     opt,
+	}, nil
+}
+
+// Selected returns the index and the text of the selected choice.
+func (d *Dropdown) Selected() (int, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.selected, d.choices[d.selected]
+}
+
+// Select selects the choice at the index. Doesn't call the ChangeFn.
+func (d *Dropdown) Select(index int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	defer d.dirty.Mark()
+
+	if min, max := 0, len(d.choices)-1; index < min || index > max {
+		return fmt.Errorf("invalid index %d, must be value in range %d <= value <= %d", index, min, max)
+	}
+	d.selected = index
+	return nil
+}
+
+// IsOpen asserts whether the list of choices is displayed.
+func (d *Dropdown) IsOpen() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.open
+}
+
+// openList opens the list with the provided filter.
+// Caller must hold d.mu.
+func (d *Dropdown) openList(filter []rune) {
+	d.open = true
+	d.setFilter(filter)
+}
+
+// closeList closes the list and clears the filter.
+// Caller must hold d.mu.
+func (d *Dropdown) closeList() {
+	d.open = false
+	d.filter = nil
+	d.matches = nil
+}
+
+// setFilter updates the filter and the matching choices. Highlights the
+// selected choice if it matches, otherwise the first match.
+// Caller must hold d.mu.
+func (d *Dropdown) setFilter(filter []rune) {
+	d.filter = filter
+	d.matches = nil
+	d.highlighted = 0
+	d.first = 0
+
+	f := strings.ToLower(string(filter))
+	for i, c := range d.choices {
+		if !strings.Contains(strings.ToLower(c), f) {
+			continue
+		}
+		if i == d.selected {
+			d.highlighted = len(d.matches)
+		}
+		d.matches = append(d.matches, i)
+	}
+}
+
+// choose selects the highlighted choice and closes the list.
+// Returns true if the selection changed.
+// Caller must hold d.mu.
+func (d *Dropdown) choose() bool {
+	if len(d.matches) == 0 {
+		return false
+	}
+	idx := d.matches[d.highlighted]
+	d.closeList()
+	if idx == d.selected {
+		return false
+	}
+	d.selected = idx
+	return true
+}
+
+// Draw draws the Dropdown widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (d *Dropdown) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ar := cvs.Area()
+	if ar.Dx() < minWidth || ar.Dy() < 1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if err := d.drawField(cvs, image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+1), meta.Focused); err != nil {
+		return err
+	}
+
+	d.listAr = image.ZR
+	if !d.open || !meta.Focused {
+		return nil
+	}
+	rows := d.opts.listRows
+	if avail := ar.Dy() - 1; rows > avail {
+		rows = avail
+	}
+	if rows > len(d.matches) {
+		rows = len(d.matches)
+	}
+	if rows <= 0 {
+		return nil
+	}
+
+	// Scroll so that the highlighted choice is visible.
+	if d.highlighted < d.first {
+		d.first = d.highlighted
+	}
+	if d.highlighted >= d.first+rows {
+		d.first = d.highlighted - rows + 1
+	}
+	// Don't leave empty rows at the bottom, e.g. after the canvas grew.
+	if last := len(d.matches) - rows; d.first > last {
+		d.first = last
+	}
+	if d.first < 0 {
+		d.first = 0
+	}
+
+	d.listAr = image.Rect(ar.Min.X, ar.Min.Y+1, ar.Max.X, ar.Min.Y+1+rows)
+	for i := 0; i < rows; i++ {
+		m := d.first + i
+		cOpts := d.opts.listCellOpts
+		if m == d.highlighted {
+			cOpts = d.opts.highlightedCellOpts
+		}
+		rowAr := image.Rect(d.listAr.Min.X, d.listAr.Min.Y+i, d.listAr.Max.X, d.listAr.Min.Y+i+1)
+		if err := cvs.SetAreaCells(rowAr, ' ', cOpts...); err != nil {
+			return err
+		}
+		if err := draw.Text(
+			cvs, d.choices[d.matches[m]], rowAr.Min,
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextMaxX(rowAr.Max.X),
+			draw.TextCellOpts(cOpts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawField draws the selected choice or the filter when the list is open
+// followed by an arrow.
+func (d *Dropdown) drawField(cvs *canvas.Canvas, fieldAr image.Rectangle, focused bool) error {
+	cOpts := d.opts.fieldCellOpts
+	if focused && !d.open {
+		cOpts = append(append([]cell.Option(nil), cOpts...), d.opts.focusedCellOpts...)
+	}
+	if err := cvs.SetAreaCells(fieldAr, ' ', cOpts...); err != nil {
+		return err
+	}
+
+	text, arrow := d.choices[d.selected], closedArrow
+	if d.open && focused {
+		arrow = openArrow
+		if len(d.filter) > 0 {
+			text = string(d.filter)
+		}
+	}
+	if err := draw.Text(
+		cvs, text, fieldAr.Min,
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextMaxX(fieldAr.Max.X-2),
+		draw.TextCellOpts(cOpts...),
+	); err != nil {
+		return err
+	}
+	_, err := cvs.SetCell(image.Point{fieldAr.Max.X - 1, fieldAr.Min.Y}, arrow, cOpts...)
+	return err
+}
+
+// changed calls the ChangeFn if the selection changed and one was provided.
+func (d *Dropdown) changed(changed bool, index int, choice string) error {
+	if !changed || d.opts.onChange == nil {
+		return nil
+	}
+	// Mutex must be released when calling the callback.
+	return d.opts.onChange(index, choice)
+}
+
+// keyboard processes the keyboard event.
+// Returns true if the selection changed and the selected choice.
+func (d *Dropdown) keyboard(k *terminalapi.Keyboard) (bool, int, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.open {
+		switch k.Key {
+		case keyboard.KeyEnter, keyboard.KeySpace, keyboard.KeyArrowDown:
+			d.openList(nil)
+		default:
+			if k.Key > 0 && wrap.ValidText(string(k.Key)) == nil {
+				d.openList([]rune{rune(k.Key)})
+			}
+		}
+		return false, 0, ""
+	}
+
+	switch k.Key {
+	case keyboard.KeyArrowUp:
+		if d.highlighted > 0 {
+			d.highlighted--
+		}
+	case keyboard.KeyArrowDown:
+		if d.highlighted < len(d.matches)-1 {
+			d.highlighted++
+		}
+	case keyboard.KeyEnter:
+		if d.choose() {
+			return true, d.selected, d.choices[d.selected]
+		}
+	case keyboard.KeyEsc:
+		d.closeList()
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if len(d.filter) > 0 {
+			d.setFilter(d.filter[:len(d.filter)-1])
+		}
+	default:
+		if k.Key > 0 && wrap.ValidText(string(k.Key)) == nil {
+			d.setFilter(append(d.filter, rune(k.Key)))
+		}
+	}
+	return false, 0, ""
+}
+
+// Keyboard opens the list, filters and selects choices.
+// Implements widgetapi.Widget.Keyboard.
+func (d *Dropdown) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return d.changed(d.keyboard(k))
+}
+
+// mouse processes the mouse event.
+// Returns true if the selection changed and the selected choice.
+func (d *Dropdown) mouse(m *terminalapi.Mouse) (bool, int, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		if d.pressed {
+			return false, 0, ""
+		}
+		d.pressed = true
+	case mouse.ButtonRelease:
+		d.pressed = false
+		return false, 0, ""
+	case mouse.ButtonWheelUp:
+		if d.open && d.highlighted > 0 {
+			d.highlighted--
+		}
+		return false, 0, ""
+	case mouse.ButtonWheelDown:
+		if d.open && d.highlighted < len(d.matches)-1 {
+			d.highlighted++
+		}
+		return false, 0, ""
+	default:
+		return false, 0, ""
+	}
+
+	switch {
+	case m.Position.Y == 0:
+		if d.open {
+			d.closeList()
+		} else {
+			d.openList(nil)
+		}
+
+	case d.open && m.Position.In(d.listAr):
+		d.highlighted = d.first + m.Position.Y - d.listAr.Min.Y
+		if d.choose() {
+			return true, d.selected, d.choices[d.selected]
+		}
+	}
+	return false, 0, ""
+}
+
+// Mouse opens the list and selects the clicked choice.
+// Implements widgetapi.Widget.Mouse.
+func (d *Dropdown) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return d.changed(d.mouse(m))
+}
+
+// Options implements widgetapi.Widget.Options.
+func (d *Dropdown) Options() widgetapi.Options {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	width := minWidth
+	for _, c := range d.choices {
+		if w := runewidth.StringWidth(c) + 2; w > width {
+			width = w
+		}
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{minWidth, 1},
+		MaximumSize:  image.Point{width, 1 + d.opts.listRows},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (d *Dropdown) SetDirtyFunc(markDirty func()) {
+	d.dirty.Set(markDirty)
+}
@@ -0,0 +1,428 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dropdown
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// changeTracker tracks calls to the ChangeFn.
+type changeTracker struct {
+	// calls are the choices the ChangeFn was called with.
+	calls []string
+	// wantErr when set to true, makes the ChangeFn return an error.
+	wantErr bool
+}
+
+// change implements ChangeFn.
+func (ct *changeTracker) change(index int, choice string) error {
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, choice)
+	return nil
+}
+
+// mustDrawRow draws a row filled with spaces and the text using the cell
+// options.
+func mustDrawRow(cvs *canvas.Canvas, y int, text string, cOpts ...cell.Option) {
+	ar := cvs.Area()
+	testcanvas.MustSetAreaCells(cvs, image.Rect(ar.Min.X, y, ar.Max.X, y+1), ' ', cOpts...)
+	testdraw.MustText(cvs, text, image.Point{0, y}, draw.TextCellOpts(cOpts...))
+}
+
+// mustDrawField draws the field with the text and the arrow.
+func mustDrawField(cvs *canvas.Canvas, text string, arrow rune, cOpts ...cell.Option) {
+	mustDrawRow(cvs, 0, text, cOpts...)
+	testcanvas.MustSetCell(cvs, image.Point{cvs.Area().Max.X - 1, 0}, arrow, cOpts...)
+}
+
+func TestDropdown(t *testing.T) {
+	choices := []string{"Apple", "Banana", "Cherry", "Grape"}
+
+	tests := []struct {
+		desc         string
+		choices      []string
+		opts         []Option
+		canvas       image.Rectangle
+		meta         *widgetapi.Meta
+		events       []terminalapi.Event
+		tracker      *changeTracker
+		want         func(size image.Point) *faketerm.Terminal
+		wantSelected int
+		wantOpen     bool
+		wantCalls    []string
+		wantNewErr   bool
+		wantEventErr bool
+	}{
+		{
+			desc:       "fails without choices",
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a choice with control characters",
+			choices:    []string{"a\nb"},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on selected index out of range",
+			choices:    choices,
+			opts:       []Option{Selected(-1)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on too few list rows",
+			choices:    choices,
+			opts:       []Option{ListRows(0)},
+			wantNewErr: true,
+		},
+		{
+			desc:    "draws the selected choice",
+			choices: choices,
+			opts:    []Option{Selected(1)},
+			canvas:  image.Rect(0, 0, 10, 3),
+			meta:    &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Banana", closedArrow)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:    "highlights the field when focused and truncates the choice",
+			choices: []string{"Watermelon"},
+			canvas:  image.Rect(0, 0, 8, 1),
+			meta:    &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Water…", closedArrow, cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:    "requests resize when the canvas is too small",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 2, 1),
+			meta:    &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:    "opens the list on Enter",
+			choices: choices,
+			opts:    []Option{Selected(1)},
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Banana", openArrow)
+				mustDrawRow(cvs, 1, "Apple")
+				mustDrawRow(cvs, 2, "Banana", cell.Inverse())
+				mustDrawRow(cvs, 3, "Cherry")
+				mustDrawRow(cvs, 4, "Grape")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+			wantOpen:     true,
+		},
+		{
+			desc:    "scrolls the list to the highlighted choice",
+			choices: choices,
+			opts:    []Option{ListRows(2)},
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Apple", openArrow)
+				mustDrawRow(cvs, 1, "Banana")
+				mustDrawRow(cvs, 2, "Cherry", cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantOpen: true,
+		},
+		{
+			desc:    "doesn't scroll past the last choice when the canvas grows",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Resize{Size: image.Point{10, 6}},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Resize{Size: image.Point{10, 2}},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Resize{Size: image.Point{10, 2}},
+				&terminalapi.Resize{Size: image.Point{10, 6}},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Apple", openArrow)
+				mustDrawRow(cvs, 1, "Apple")
+				mustDrawRow(cvs, 2, "Banana")
+				mustDrawRow(cvs, 3, "Cherry")
+				mustDrawRow(cvs, 4, "Grape", cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantOpen: true,
+		},
+		{
+			desc:    "filters the choices by the typed text",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'R'},
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyBackspace2},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "R", openArrow)
+				mustDrawRow(cvs, 1, "Cherry", cell.Inverse())
+				mustDrawRow(cvs, 2, "Grape")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantOpen: true,
+		},
+		{
+			desc:    "selects the highlighted choice on Enter",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'g'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Grape", closedArrow)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 3,
+			wantCalls:    []string{"Grape"},
+		},
+		{
+			desc:    "Esc closes the list without changing the selection",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Apple", closedArrow)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:    "selects the clicked choice",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{2, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{2, 0}, Button: mouse.ButtonRelease},
+				// Draw so that the position of the list is known.
+				nil,
+				&terminalapi.Mouse{Position: image.Point{2, 3}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{2, 3}, Button: mouse.ButtonRelease},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawField(cvs, "Cherry", closedArrow, cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 2,
+			wantCalls:    []string{"Cherry"},
+		},
+		{
+			desc:    "returns the error from the ChangeFn",
+			choices: choices,
+			canvas:  image.Rect(0, 0, 10, 6),
+			meta:    &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			tracker:      &changeTracker{wantErr: true},
+			wantEventErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.tracker != nil {
+				opts = append(opts, OnChange(tc.tracker.change))
+			}
+			d, err := New(tc.choices, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// draw draws the widget onto a new canvas of the current size.
+			cvsAr := tc.canvas
+			draw := func() *canvas.Canvas {
+				c, err := canvas.New(cvsAr)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := d.Draw(c, tc.meta); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				return c
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = d.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = d.Mouse(e, &widgetapi.EventMeta{})
+				case *terminalapi.Resize:
+					// Draws on a canvas of the new size.
+					cvsAr = image.Rect(0, 0, e.Size.X, e.Size.Y)
+					draw()
+				default:
+					draw()
+				}
+				if err != nil {
+					break
+				}
+			}
+			if (err != nil) != tc.wantEventErr {
+				t.Errorf("event => unexpected error: %v, wantEventErr: %v", err, tc.wantEventErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c := draw()
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if got, _ := d.Selected(); got != tc.wantSelected {
+				t.Errorf("Selected => %d, want %d", got, tc.wantSelected)
+			}
+			if got := d.IsOpen(); got != tc.wantOpen {
+				t.Errorf("IsOpen => %v, want %v", got, tc.wantOpen)
+			}
+			if tc.tracker != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.tracker.calls); diff != "" {
+					t.Errorf("ChangeFn calls => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	d, err := New([]string{"a", "b"})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := d.Select(2); err == nil {
+		t.Errorf("Select(2) => unexpected success, want an error")
+	}
+	if err := d.Select(1); err != nil {
+		t.Fatalf("Select(1) => unexpected error: %v", err)
+	}
+	if idx, choice := d.Selected(); idx != 1 || choice != "b" {
+		t.Errorf("Selected => %d, %q, want 1, \"b\"", idx, choice)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	d, err := New([]string{"Apple", "Banana"}, ListRows(3))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := d.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{3, 1},
+		MaximumSize:  image.Point{8, 4},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary dropdowndemo displays a Dropdown widget that selects a time zone.
+// Exist when Ctrl-Q is pressed.
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/dropdown"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// zones are the time zones the user selects from.
+var zones = []string{
+	"UTC",
+	"America/Los_Angeles",
+	"America/New_York",
+	"Europe/London",
+	"Europe/Prague",
+	"Asia/Kolkata",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+}
+
+// showTime displays the current time in the time zone.
+func showTime(t *text.Text, zone string) error {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return err
+	}
+	t.Reset()
+	return t.Write(time.Now().In(loc).Format(time.RFC1123))
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := showTime(clock, zones[0]); err != nil {
+		panic(err)
+	}
+
+	dd, err := dropdown.New(
+		zones,
+		dropdown.ListRows(6),
+		dropdown.OnChange(func(index int, choice string) error {
+			return showTime(clock, choice)
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS CTRL-Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Time zone"),
+				container.PlaceWidget(dd),
+				container.AlignVertical(align.VerticalTop),
+				container.AlignHorizontal(align.HorizontalLeft),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Current time"),
+				container.PlaceWidget(clock),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == keyboard.KeyCtrlQ {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dropdown
+
+// options.go contains configurable options for Dropdown.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	selected            int
+	listRows            int
+	fieldCellOpts       []cell.Option
+	focusedCellOpts     []cell.Option
+	listCellOpts        []cell.Option
+	highlightedCellOpts []cell.Option
+	onChange            ChangeFn
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		listRows:            DefaultListRows,
+		focusedCellOpts:     []cell.Option{cell.Inverse()},
+		highlightedCellOpts: []cell.Option{cell.Inverse()},
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate(choices int) error {
+	if min, max := 0, choices-1; o.selected < min || o.selected > max {
+		return fmt.Errorf("invalid Selected(%d), must be value in range %d <= value <= %d", o.selected, min, max)
+	}
+	if min := 1; o.listRows < min {
+		return fmt.Errorf("invalid ListRows(%d), must be value in range %d <= value", o.listRows, min)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// Selected sets the index of the initially selected choice.
+// Defaults to the first choice.
+func Selected(index int) Option {
+	return option(func(opts *options) {
+		opts.selected = index
+	})
+}
+
+// DefaultListRows is the default value for the ListRows option.
+const DefaultListRows = 5
+
+// ListRows sets the maximum number of choices displayed at once in the open
+// list, the list scrolls if there are more. The widget requests the space
+// for the list, i.e. its maximum height is one row for the selected choice
+// and this many rows for the list.
+// Defaults to DefaultListRows.
+func ListRows(rows int) Option {
+	return option(func(opts *options) {
+		opts.listRows = rows
+	})
+}
+
+// FieldCellOpts sets the cell options of the field that displays the selected
+// choice.
+// Defaults to the default cell options.
+func FieldCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.fieldCellOpts = co
+	})
+}
+
+// FocusedCellOpts sets the cell options applied on top of the field when the
+// dropdown is focused and closed.
+// Defaults to inverse colors.
+func FocusedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.focusedCellOpts = co
+	})
+}
+
+// ListCellOpts sets the cell options of the choices in the open list.
+// Defaults to the default cell options.
+func ListCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.listCellOpts = co
+	})
+}
+
+// HighlightedCellOpts sets the cell options of the highlighted choice in the
+// open list.
+// Defaults to inverse colors.
+func HighlightedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.highlightedCellOpts = co
+	})
+}
+
+// ChangeFn is called when the user selects a different choice. The arguments
+// are the index and the text of the selected choice.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Dropdown isn't locked while the
+// function executes, so it can read from or modify the Dropdown.
+type ChangeFn func(index int, choice string) error
+
+// OnChange sets the function that is called when the user selects a
+// different choice.
+func OnChange(fn ChangeFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+// field.go contains the fields of the form.
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/widgets/textinput"
+)
+
+// fieldKind identifies the kind of a field.
+type fieldKind int
+
+// The supported kinds of fields.
+const (
+	fieldKindText fieldKind = iota
+	fieldKindCheckbox
+	fieldKindSelect
+)
+
+// ValidateFn validates the value of a field. Returns an error describing the
+// problem if the value is invalid, the error is displayed below the field.
+//
+// The function is called while the Form is mutex locked, it must not attempt
+// to read from or modify the Form.
+type ValidateFn func(value string) error
+
+// FieldOption is used to provide options to a field.
+type FieldOption interface {
+	// set sets the provided option.
+	set(*fieldOptions)
+}
+
+// fieldOptions holds the provided field options.
+type fieldOptions struct {
+	validate     ValidateFn
+	defaultValue *string
+	inputOpts    []textinput.Option
+}
+
+// fieldOption implements FieldOption.
+type fieldOption func(*fieldOptions)
+
+// set implements FieldOption.set.
+func (fo fieldOption) set(opts *fieldOptions) {
+	fo(opts)
+}
+
+// Validate sets the function that validates the value of the field. The
+// field is validated when the focus leaves it and when the form is submitted.
+func Validate(fn ValidateFn) FieldOption {
+	return fieldOption(func(opts *fieldOptions) {
+		opts.validate = fn
+	})
+}
+
+// Default sets the initial value of the field. For a checkbox, the value must
+// be "true" or "false" and for a select, it must be one of the choices.
+func Default(value string) FieldOption {
+	return fieldOption(func(opts *fieldOptions) {
+		opts.defaultValue = &value
+	})
+}
+
+// InputOpts sets options of the textinput.TextInput used by a text field,
+// e.g. textinput.HideTextWith for passwords. Has no effect on other fields.
+func InputOpts(o ...textinput.Option) FieldOption {
+	return fieldOption(func(opts *fieldOptions) {
+		opts.inputOpts = o
+	})
+}
+
+// Field is a labeled input of a form.
+// A Field must not be added to more than one form.
+type Field struct {
+	kind    fieldKind
+	name    string
+	label   string
+	choices []string
+	opts    *fieldOptions
+
+	// input is the text input of a text field.
+	input *textinput.TextInput
+	// checked is the state of a checkbox.
+	checked bool
+	// choice is the index of the selected choice of a select.
+	choice int
+	// err is the result of the last validation.
+	err error
+}
+
+// newField returns a new field with the provided options.
+func newField(kind fieldKind, name, label string, choices []string, opts []FieldOption) *Field {
+	fo := &fieldOptions{}
+	for _, o := range opts {
+		o.set(fo)
+	}
+	return &Field{
+		kind:    kind,
+		name:    name,
+		label:   label,
+		choices: choices,
+		opts:    fo,
+	}
+}
+
+// Text returns a field that accepts a line of text.
+// The name identifies the value of the field, the label is displayed in front
+// of it.
+func Text(name, label string, opts ...FieldOption) *Field {
+	return newField(fieldKindText, name, label, nil, opts)
+}
+
+// Checkbox returns a field that is either checked or unchecked, the value is
+// "true" or "false". Toggled by pressing Space or by clicking on it.
+func Checkbox(name, label string, opts ...FieldOption) *Field {
+	return newField(fieldKindCheckbox, name, label, nil, opts)
+}
+
+// Select returns a field whose value is one of the choices, the first choice
+// is selected by default. Changed using the Left and Right arrow keys or by
+// clicking on it.
+func Select(name, label string, choices []string, opts ...FieldOption) *Field {
+	return newField(fieldKindSelect, name, label, choices, opts)
+}
+
+// init validates the field and sets its initial value.
+func (f *Field) init() error {
+	if f.name == "" {
+		return errors.New("the name of a field cannot be empty")
+	}
+
+	switch f.kind {
+	case fieldKindText:
+		opts := append([]textinput.Option(nil), f.opts.inputOpts...)
+		if dv := f.opts.defaultValue; dv != nil {
+			opts = append(opts, textinput.DefaultText(*dv))
+		}
+		ti, err := textinput.New(opts...)
+		if err != nil {
+			return fmt.Errorf("field %q: %v", f.name, err)
+		}
+		f.input = ti
+
+	case fieldKindCheckbox:
+		if dv := f.opts.defaultValue; dv != nil {
+			checked, err := strconv.ParseBool(*dv)
+			if err != nil {
+				return fmt.Errorf("field %q: invalid default value %q, must be true or false", f.name, *dv)
+			}
+			f.checked = checked
+		}
+
+	case fieldKindSelect:
+		if len(f.choices) == 0 {
+			return fmt.Errorf("field %q: a select requires at least one choice", f.name)
+		}
+		if dv := f.opts.defaultValue; dv != nil {
+			f.choice = -1
+			for i, c := range f.choices {
+				if c == *dv {
+					f.choice = i
+					break
+				}
+			}
+			if f.choice < 0 {
+				return fmt.Errorf("field %q: invalid default value %q, must be one of the choices %q", f.name, *dv, f.choices)
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the current value of the field.
+func (f *Field) value() string {
+	switch f.kind {
+	case fieldKindCheckbox:
+		return strconv.FormatBool(f.checked)
+	case fieldKindSelect:
+		return f.choices[f.choice]
+	default:
+		return f.input.Read()
+	}
+}
+
+// validate validates the current value of the field and records the result.
+// Returns true if the value is valid.
+func (f *Field) validate() bool {
+	f.err = nil
+	if f.opts.validate != nil {
+		f.err = f.opts.validate(f.value())
+	}
+	return f.err == nil
+}
+
+// text returns the text that represents the value of a checkbox or a select.
+func (f *Field) text() string {
+	switch f.kind {
+	case fieldKindCheckbox:
+		if f.checked {
+			return "[x]"
+		}
+		return "[ ]"
+	case fieldKindSelect:
+		return fmt.Sprintf("< %s >", f.choices[f.choice])
+	default:
+		return ""
+	}
+}
+
+// textWidth returns the width in cells of the widest text that can represent
+// the value of a checkbox or a select.
+func (f *Field) textWidth() int {
+	switch f.kind {
+	case fieldKindCheckbox:
+		return runewidth.StringWidth("[ ]")
+	case fieldKindSelect:
+		width := 0
+		for _, c := range f.choices {
+			if w := runewidth.StringWidth(fmt.Sprintf("< %s >", c)); w > width {
+				width = w
+			}
+		}
+		return width
+	default:
+		return 0
+	}
+}
+
+// toggle changes the value of a checkbox or moves a select to the next
+// (positive step) or the previous (negative step) choice.
+func (f *Field) toggle(step int) {
+	switch f.kind {
+	case fieldKindCheckbox:
+		f.checked = !f.checked
+	case fieldKindSelect:
+		n := len(f.choices)
+		f.choice = ((f.choice+step)%n + n) % n
+	}
+}
@@ -0,0 +1,468 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package form implements a widget that displays a form with labeled fields.
+package form
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// minInputWidth is the minimum width of the inputs in cells.
+const minInputWidth = 4
+
+// Form displays labeled fields followed by a submit button.
+//
+// Each field occupies one row with its label on the left. The focus moves
+// between the fields and the submit button when the user presses Tab and
+// Shift-Tab or Enter on a field or clicks on a field. The fields are validated
+// when the focus leaves them and when the form is submitted, validation errors
+// are displayed below the invalid fields.
+//
+// Implements widgetapi.Widget and widgetapi.Paster. This object is
+// thread-safe.
+type Form struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// fields are the fields of the form in the order they are displayed.
+	fields []*Field
+
+	// focused is the index of the focused field, len(fields) when the submit
+	// button is focused.
+	focused int
+
+	// rows are the rows the fields and the submit button occupied the last
+	// time Draw was called, indexed like focused.
+	rows []int
+
+	// pressed indicates that the left mouse button is pressed.
+	pressed bool
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new Form with the provided fields.
+func New(fields []*Field, opts ...Option) (*Form, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("a form requires at least one field")
+	}
+	names := map[string]bool{}
+	for _, f := range fields {
+		if err := f.init(); err != nil {
+			return nil, err
+		}
+		if names[f.name] {
+			return nil, fmt.Errorf("duplicate field name %q, the names must be unique", f.name)
+		}
+		names[f.name] = true
+	}
+	return &Form{
+		fields: fields,
+		opts:   opt,
+	}, nil
+}
+
+// Values returns the current values of all the fields keyed by their names.
+// Checkboxes have values "true" or "false".
+func (f *Form) Values() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.values()
+}
+
+// values implements Values.
+// Caller must hold f.mu.
+func (f *Form) values() map[string]string {
+	res := map[string]string{}
+	for _, fl := range f.fields {
+		res[fl.name] = fl.value()
+	}
+	return res
+}
+
+// inputX returns the column where the inputs start.
+func (f *Form) inputX() int {
+	width := 0
+	for _, fl := range f.fields {
+		if lw := runewidth.StringWidth(fl.label); lw > width {
+			width = lw
+		}
+	}
+	if width > 0 {
+		// Leave a space between the labels and the inputs.
+		width++
+	}
+	return width
+}
+
+// submitText returns the text of the submit button.
+func (f *Form) submitText() string {
+	return fmt.Sprintf("[ %s ]", f.opts.submitLabel)
+}
+
+// layout returns the rows of the fields and the submit button and the total
+// number of rows. Rows for validation errors are only included if the
+// showErrors is true.
+// Caller must hold f.mu.
+func (f *Form) layout(showErrors bool) ([]int, int) {
+	var rows []int
+	y := 0
+	for _, fl := range f.fields {
+		rows = append(rows, y)
+		y++
+		if showErrors && fl.err != nil {
+			y++
+		}
+	}
+	rows = append(rows, y)
+	return rows, y + 1
+}
+
+// setFocus moves the focus to the item at the index, validating the field
+// that is losing the focus.
+// Caller must hold f.mu.
+func (f *Form) setFocus(idx int) {
+	if idx == f.focused {
+		return
+	}
+	if f.focused < len(f.fields) {
+		f.fields[f.focused].validate()
+	}
+	f.focused = idx
+}
+
+// moveFocus moves the focus by the step, wrapping around at both ends.
+// Caller must hold f.mu.
+func (f *Form) moveFocus(step int) {
+	n := len(f.fields) + 1
+	f.setFocus(((f.focused+step)%n + n) % n)
+}
+
+// submit validates all the fields and returns true if they are all valid.
+// Otherwise focuses the first invalid field.
+// Caller must hold f.mu.
+func (f *Form) submit() bool {
+	firstInvalid := -1
+	for i, fl := range f.fields {
+		if !fl.validate() && firstInvalid < 0 {
+			firstInvalid = i
+		}
+	}
+	if firstInvalid >= 0 {
+		f.focused = firstInvalid
+		return false
+	}
+	return true
+}
+
+// Draw draws the Form widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (f *Form) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ar := cvs.Area()
+	min := f.minSize()
+	if ar.Dx() < min.X || ar.Dy() < min.Y {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	rows, total := f.layout(true)
+	showErrors := total <= ar.Dy()
+	if !showErrors {
+		rows, _ = f.layout(false)
+	}
+	f.rows = rows
+
+	inputX := f.inputX()
+	for i, fl := range f.fields {
+		y := rows[i]
+		focused := meta.Focused && i == f.focused
+		if fl.label != "" {
+			if err := draw.Text(
+				cvs, fl.label, image.Point{0, y},
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextMaxX(inputX),
+				draw.TextCellOpts(f.opts.labelCellOpts...),
+			); err != nil {
+				return err
+			}
+		}
+
+		if err := f.drawInput(cvs, fl, image.Rect(inputX, y, ar.Max.X, y+1), focused); err != nil {
+			return err
+		}
+
+		if showErrors && fl.err != nil {
+			if err := draw.Text(
+				cvs, fl.err.Error(), image.Point{inputX, y + 1},
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextMaxX(ar.Max.X),
+				draw.TextCellOpts(f.opts.errorCellOpts...),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	var sOpts []draw.TextOption
+	if meta.Focused && f.focused == len(f.fields) {
+		sOpts = append(sOpts, draw.TextCellOpts(f.opts.focusedCellOpts...))
+	}
+	sOpts = append(sOpts,
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextMaxX(ar.Max.X),
+	)
+	return draw.Text(cvs, f.submitText(), image.Point{inputX, rows[len(f.fields)]}, sOpts...)
+}
+
+// drawInput draws the input of the field into the area.
+func (f *Form) drawInput(cvs *canvas.Canvas, fl *Field, inputAr image.Rectangle, focused bool) error {
+	if fl.kind == fieldKindText {
+		// The text input draws onto its own canvas that is then copied to the
+		// position of the field.
+		sub, err := canvas.New(inputAr)
+		if err != nil {
+			return err
+		}
+		if err := fl.input.Draw(sub, &widgetapi.Meta{Focused: focused}); err != nil {
+			return err
+		}
+		return sub.CopyTo(cvs)
+	}
+
+	var tOpts []draw.TextOption
+	if focused {
+		tOpts = append(tOpts, draw.TextCellOpts(f.opts.focusedCellOpts...))
+	}
+	tOpts = append(tOpts,
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextMaxX(inputAr.Max.X),
+	)
+	return draw.Text(cvs, fl.text(), inputAr.Min, tOpts...)
+}
+
+// keyboard processes keyboard events.
+// Returns true and the values of the fields if the form was submitted.
+func (f *Form) keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) (bool, map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch k.Key {
+	case f.opts.keyNext:
+		f.moveFocus(1)
+		return false, nil, nil
+
+	case f.opts.keyPrevious:
+		f.moveFocus(-1)
+		return false, nil, nil
+
+	case keyboard.KeyEnter:
+		if f.focused == len(f.fields) {
+			if !f.submit() {
+				return false, nil, nil
+			}
+			return true, f.values(), nil
+		}
+		f.moveFocus(1)
+		return false, nil, nil
+	}
+
+	if f.focused == len(f.fields) {
+		return false, nil, nil
+	}
+	fl := f.fields[f.focused]
+	switch fl.kind {
+	case fieldKindText:
+		return false, nil, fl.input.Keyboard(k, meta)
+
+	case fieldKindCheckbox:
+		if k.Key == keyboard.KeySpace {
+			fl.toggle(1)
+			fl.validate()
+		}
+
+	case fieldKindSelect:
+		switch k.Key {
+		case keyboard.KeyArrowLeft:
+			fl.toggle(-1)
+			fl.validate()
+		case keyboard.KeyArrowRight, keyboard.KeySpace:
+			fl.toggle(1)
+			fl.validate()
+		}
+	}
+	return false, nil, nil
+}
+
+// Keyboard processes keyboard events.
+// Implements widgetapi.Widget.Keyboard.
+func (f *Form) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	submitted, values, err := f.keyboard(k, meta)
+	if err != nil {
+		return err
+	}
+	if submitted && f.opts.onSubmit != nil {
+		// Mutex must be released when calling the callback.
+		return f.opts.onSubmit(values)
+	}
+	return nil
+}
+
+// Paste inserts the pasted text into the focused text field.
+// Implements widgetapi.Paster.Paste.
+func (f *Form) Paste(p *terminalapi.Paste, meta *widgetapi.EventMeta) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.focused == len(f.fields) {
+		return nil
+	}
+	if fl := f.fields[f.focused]; fl.kind == fieldKindText {
+		return fl.input.Paste(p, meta)
+	}
+	return nil
+}
+
+// mouse processes mouse events.
+// Returns true and the values of the fields if the form was submitted.
+func (f *Form) mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) (bool, map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		if f.pressed {
+			return false, nil, nil
+		}
+		f.pressed = true
+	case mouse.ButtonRelease:
+		f.pressed = false
+		return false, nil, nil
+	default:
+		return false, nil, nil
+	}
+
+	idx := -1
+	for i, y := range f.rows {
+		if y == m.Position.Y {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return false, nil, nil
+	}
+	f.setFocus(idx)
+
+	inputX := f.inputX()
+	if m.Position.X < inputX {
+		return false, nil, nil
+	}
+	if idx == len(f.fields) {
+		if m.Position.X >= inputX+runewidth.StringWidth(f.submitText()) || !f.submit() {
+			return false, nil, nil
+		}
+		return true, f.values(), nil
+	}
+
+	fl := f.fields[idx]
+	switch fl.kind {
+	case fieldKindText:
+		rel := &terminalapi.Mouse{
+			Position: image.Point{m.Position.X - inputX, 0},
+			Button:   m.Button,
+		}
+		return false, nil, fl.input.Mouse(rel, meta)
+
+	default:
+		if m.Position.X < inputX+runewidth.StringWidth(fl.text()) {
+			fl.toggle(1)
+			fl.validate()
+		}
+	}
+	return false, nil, nil
+}
+
+// Mouse processes mouse events.
+// Implements widgetapi.Widget.Mouse.
+func (f *Form) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	submitted, values, err := f.mouse(m, meta)
+	if err != nil {
+		return err
+	}
+	if submitted && f.opts.onSubmit != nil {
+		// Mutex must be released when calling the callback.
+		return f.opts.onSubmit(values)
+	}
+	return nil
+}
+
+// minSize returns the minimum size of the form.
+func (f *Form) minSize() image.Point {
+	inputX := f.inputX()
+	width := inputX + minInputWidth
+	for _, fl := range f.fields {
+		if w := inputX + fl.textWidth(); w > width {
+			width = w
+		}
+	}
+	if w := inputX + runewidth.StringWidth(f.submitText()); w > width {
+		width = w
+	}
+	return image.Point{width, len(f.fields) + 1}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (f *Form) Options() widgetapi.Options {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize:              f.minSize(),
+		WantKeyboard:             widgetapi.KeyScopeFocused,
+		WantMouse:                widgetapi.MouseScopeWidget,
+		ExclusiveKeyboardOnFocus: f.opts.exclusiveKeyboardOnFocus,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (f *Form) SetDirtyFunc(markDirty func()) {
+	f.dirty.Set(markDirty)
+}
@@ -0,0 +1,443 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+	"github.com/mum4k/termdash/widgets/textinput"
+)
+
+// notEmpty is a ValidateFn that rejects empty values.
+func notEmpty(value string) error {
+	if value == "" {
+		return errors.New("required")
+	}
+	return nil
+}
+
+// testFields returns the fields used in the tests.
+func testFields() []*Field {
+	return []*Field{
+		Text("name", "Name", Validate(notEmpty)),
+		Checkbox("admin", "Admin"),
+		Select("role", "Role", []string{"dev", "ops"}),
+	}
+}
+
+// mustDrawField draws an empty text input field of the width at the point.
+func mustDrawField(cvs *canvas.Canvas, start image.Point, width int) {
+	testcanvas.MustSetAreaCells(
+		cvs,
+		image.Rect(start.X, start.Y, start.X+width, start.Y+1),
+		0,
+		cell.BgColor(cell.ColorNumber(textinput.DefaultFillColorNumber)),
+	)
+}
+
+// submitTracker tracks the values submitted by the form.
+type submitTracker struct {
+	values map[string]string
+}
+
+// submit implements SubmitFn.
+func (st *submitTracker) submit(values map[string]string) error {
+	st.values = values
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fields []*Field
+		opts   []Option
+	}{
+		{
+			desc: "fails without fields",
+		},
+		{
+			desc:   "fails on a field without a name",
+			fields: []*Field{Text("", "Name")},
+		},
+		{
+			desc:   "fails on duplicate names",
+			fields: []*Field{Text("name", "Name"), Checkbox("name", "Other")},
+		},
+		{
+			desc:   "fails on an invalid checkbox default",
+			fields: []*Field{Checkbox("admin", "Admin", Default("yes"))},
+		},
+		{
+			desc:   "fails on a select without choices",
+			fields: []*Field{Select("role", "Role", nil)},
+		},
+		{
+			desc:   "fails on a select default that isn't a choice",
+			fields: []*Field{Select("role", "Role", []string{"dev"}, Default("ops"))},
+		},
+		{
+			desc:   "fails on invalid input options",
+			fields: []*Field{Text("name", "Name", InputOpts(textinput.WidthPerc(0)))},
+		},
+		{
+			desc:   "fails on an empty submit label",
+			fields: testFields(),
+			opts:   []Option{SubmitLabel("")},
+		},
+		{
+			desc:   "fails on identical navigation keys",
+			fields: testFields(),
+			opts:   []Option{KeyNext(keyboard.KeyTab), KeyPrevious(keyboard.KeyTab)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := New(tc.fields, tc.opts...); err == nil {
+				t.Errorf("New => unexpected success, want an error")
+			}
+		})
+	}
+}
+
+func TestForm(t *testing.T) {
+	tests := []struct {
+		desc       string
+		fields     []*Field
+		canvas     image.Rectangle
+		meta       *widgetapi.Meta
+		events     []terminalapi.Event
+		want       func(size image.Point) *faketerm.Terminal
+		wantValues map[string]string
+		// wantSubmitted are the submitted values, nil if not submitted.
+		wantSubmitted map[string]string
+	}{
+		{
+			desc:   "draws the fields and the submit button",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[ ]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< dev >", image.Point{6, 2})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "", "admin": "false", "role": "dev"},
+		},
+		{
+			desc: "applies default values",
+			fields: []*Field{
+				Text("name", "Name", Default("ab")),
+				Checkbox("admin", "Admin", Default("true")),
+				Select("role", "Role", []string{"dev", "ops"}, Default("ops")),
+			},
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "ab", image.Point{6, 0})
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[x]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< ops >", image.Point{6, 2})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "ab", "admin": "true", "role": "ops"},
+		},
+		{
+			desc:   "fails when the canvas is too small",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 15, 4),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "", "admin": "false", "role": "dev"},
+		},
+		{
+			desc:   "edits the fields using the keyboard",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'a'},
+				&terminalapi.Paste{Text: "bc"},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "abc", image.Point{6, 0})
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[x]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< ops >", image.Point{6, 2}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "abc", "admin": "true", "role": "ops"},
+		},
+		{
+			desc:   "displays a validation error when the focus leaves the field",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 5),
+			meta:   &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "required", image.Point{6, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(cvs, "Admin", image.Point{0, 2})
+				testdraw.MustText(cvs, "[ ]", image.Point{6, 2}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(cvs, "Role", image.Point{0, 3})
+				testdraw.MustText(cvs, "< dev >", image.Point{6, 3})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 4})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "", "admin": "false", "role": "dev"},
+		},
+		{
+			desc:   "omits validation errors that don't fit",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[ ]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< dev >", image.Point{6, 2})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "", "admin": "false", "role": "dev"},
+		},
+		{
+			desc:   "submit of an invalid form focuses the invalid field",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 5),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyBacktab},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'x'},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "x", image.Point{6, 0})
+				testdraw.MustText(cvs, "required", image.Point{6, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(cvs, "Admin", image.Point{0, 2})
+				testdraw.MustText(cvs, "[ ]", image.Point{6, 2})
+				testdraw.MustText(cvs, "Role", image.Point{0, 3})
+				testdraw.MustText(cvs, "< dev >", image.Point{6, 3})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 4})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues: map[string]string{"name": "x", "admin": "false", "role": "dev"},
+		},
+		{
+			desc:   "submits a valid form with the keyboard",
+			fields: testFields(),
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "x", image.Point{6, 0})
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[ ]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< ops >", image.Point{6, 2})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues:    map[string]string{"name": "x", "admin": "false", "role": "ops"},
+			wantSubmitted: map[string]string{"name": "x", "admin": "false", "role": "ops"},
+		},
+		{
+			desc: "uses the mouse",
+			fields: []*Field{
+				Text("name", "Name", Default("x")),
+				Checkbox("admin", "Admin"),
+				Select("role", "Role", []string{"dev", "ops"}),
+			},
+			canvas: image.Rect(0, 0, 16, 4),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{7, 1}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{7, 1}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{7, 2}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{7, 2}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{7, 3}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{7, 3}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Name", image.Point{0, 0})
+				mustDrawField(cvs, image.Point{6, 0}, 10)
+				testdraw.MustText(cvs, "x", image.Point{6, 0})
+				testdraw.MustText(cvs, "Admin", image.Point{0, 1})
+				testdraw.MustText(cvs, "[x]", image.Point{6, 1})
+				testdraw.MustText(cvs, "Role", image.Point{0, 2})
+				testdraw.MustText(cvs, "< ops >", image.Point{6, 2})
+				testdraw.MustText(cvs, "[ Submit ]", image.Point{6, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValues:    map[string]string{"name": "x", "admin": "true", "role": "ops"},
+			wantSubmitted: map[string]string{"name": "x", "admin": "true", "role": "ops"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			st := &submitTracker{}
+			f, err := New(tc.fields, OnSubmit(st.submit))
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			// Draw once so that mouse events can be processed.
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := f.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = f.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = f.Mouse(e, &widgetapi.EventMeta{})
+				case *terminalapi.Paste:
+					err = f.Paste(e, &widgetapi.EventMeta{})
+				}
+				if err != nil {
+					t.Fatalf("event %v => unexpected error: %v", ev, err)
+				}
+			}
+
+			c, err = canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := f.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if diff := pretty.Compare(tc.wantValues, f.Values()); diff != "" {
+				t.Errorf("Values => unexpected diff (-want, +got):\n%s", diff)
+			}
+			if diff := pretty.Compare(tc.wantSubmitted, st.values); diff != "" {
+				t.Errorf("submitted values => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	f, err := New(testFields(), ExclusiveKeyboardOnFocus())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := f.Options()
+	want := widgetapi.Options{
+		MinimumSize:              image.Point{16, 4},
+		WantKeyboard:             widgetapi.KeyScopeFocused,
+		WantMouse:                widgetapi.MouseScopeWidget,
+		ExclusiveKeyboardOnFocus: true,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
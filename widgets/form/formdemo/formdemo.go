@@ -0,0 +1,120 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary formdemo displays a Form widget that creates a user account.
+// Exist when Esc is pressed.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/form"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/mum4k/termdash/widgets/textinput"
+)
+
+// required is a form.ValidateFn that rejects empty values.
+func required(value string) error {
+	if value == "" {
+		return errors.New("this field is required")
+	}
+	return nil
+}
+
+// minLength returns a form.ValidateFn that rejects values shorter than n.
+func minLength(n int) form.ValidateFn {
+	return func(value string) error {
+		if len(value) < n {
+			return fmt.Errorf("must be at least %d characters long", n)
+		}
+		return nil
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+
+	f, err := form.New(
+		[]*form.Field{
+			form.Text("user", "User name", form.Validate(required)),
+			form.Text("password", "Password",
+				form.Validate(minLength(8)),
+				form.InputOpts(textinput.HideTextWith('*')),
+			),
+			form.Select("shell", "Shell", []string{"bash", "zsh", "fish"}),
+			form.Checkbox("admin", "Administrator"),
+		},
+		form.OnSubmit(func(values map[string]string) error {
+			result.Reset()
+			return result.Write(fmt.Sprintf(
+				"Created user %q with shell %s, administrator: %s.\n",
+				values["user"], values["shell"], values["admin"],
+			))
+		}),
+		form.SubmitLabel("Create"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS ESC TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.PlaceWidget(f),
+				container.PaddingLeft(1),
+				container.PaddingTop(1),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Result"),
+				container.PlaceWidget(result),
+			),
+			container.SplitPercent(60),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == keyboard.KeyEsc {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+// options.go contains configurable options for Form.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	onSubmit                 SubmitFn
+	submitLabel              string
+	labelCellOpts            []cell.Option
+	errorCellOpts            []cell.Option
+	focusedCellOpts          []cell.Option
+	keyNext                  keyboard.Key
+	keyPrevious              keyboard.Key
+	exclusiveKeyboardOnFocus bool
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		submitLabel:     DefaultSubmitLabel,
+		errorCellOpts:   []cell.Option{cell.FgColor(cell.ColorRed)},
+		focusedCellOpts: []cell.Option{cell.Inverse()},
+		keyNext:         DefaultKeyNext,
+		keyPrevious:     DefaultKeyPrevious,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.submitLabel == "" {
+		return fmt.Errorf("invalid SubmitLabel, cannot be empty")
+	}
+	if o.keyNext == o.keyPrevious {
+		return fmt.Errorf("invalid KeyNext and KeyPrevious %s, the keys must be unique", o.keyNext)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// SubmitFn is called with the values of all the fields when the user submits
+// a valid form. The values are keyed by the names of the fields.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Form isn't locked while the function
+// executes, so it can read from or modify the Form.
+type SubmitFn func(values map[string]string) error
+
+// OnSubmit sets the function that is called when the user submits the form.
+// The form is submitted by pressing Enter on or clicking the submit button,
+// it is only submitted if all the fields pass validation.
+func OnSubmit(fn SubmitFn) Option {
+	return option(func(opts *options) {
+		opts.onSubmit = fn
+	})
+}
+
+// DefaultSubmitLabel is the default value for the SubmitLabel option.
+const DefaultSubmitLabel = "Submit"
+
+// SubmitLabel sets the text displayed on the submit button.
+// Defaults to DefaultSubmitLabel.
+func SubmitLabel(label string) Option {
+	return option(func(opts *options) {
+		opts.submitLabel = label
+	})
+}
+
+// LabelCellOpts sets the cell options for the labels of the fields.
+// Defaults to the default cell options.
+func LabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.labelCellOpts = co
+	})
+}
+
+// ErrorCellOpts sets the cell options for the validation errors displayed
+// below the fields.
+// Defaults to red text.
+func ErrorCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.errorCellOpts = co
+	})
+}
+
+// FocusedCellOpts sets the cell options for the focused checkbox, select or
+// the submit button. The focused text field displays its cursor instead.
+// Defaults to inverse colors.
+func FocusedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.focusedCellOpts = co
+	})
+}
+
+// The default keys that move the focus between the fields.
+const (
+	DefaultKeyNext     = keyboard.KeyTab
+	DefaultKeyPrevious = keyboard.KeyBacktab
+)
+
+// KeyNext sets the key that moves the focus to the next field.
+// Defaults to DefaultKeyNext.
+func KeyNext(k keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyNext = k
+	})
+}
+
+// KeyPrevious sets the key that moves the focus to the previous field.
+// Defaults to DefaultKeyPrevious.
+func KeyPrevious(k keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyPrevious = k
+	})
+}
+
+// ExclusiveKeyboardOnFocus when set ensures that when this widget is focused,
+// no other widget receives any keyboard events.
+func ExclusiveKeyboardOnFocus() Option {
+	return option(func(opts *options) {
+		opts.exclusiveKeyboardOnFocus = true
+	})
+}
@@ -27,9 +27,11 @@ import (
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -70,6 +72,23 @@ type Gauge struct {
 	// For progressTypePercent, this is 100, for progressTypeAbsolute this is
 	// the total provided by the caller.
 	total int
+
+	// updated indicates whether the progress was set at least once.
+	updated bool
+	// prevPT, prevCurrent and prevTotal are the progress values set on the
+	// previous update, valid only if hasPrev is true.
+	prevPT      progressType
+	prevCurrent int
+	prevTotal   int
+	hasPrev     bool
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// linked is the scale the gauge last reported its progress to, nil if
+	// none.
+	linked *Scale
+
 	// mu protects the Gauge.
 	mu sync.Mutex
 
@@ -87,9 +106,28 @@ func New(opts ...Option) (*Gauge, error) {
 		return nil, err
 	}
 
-	return &Gauge{
+	g := &Gauge{
 		opts: opt,
-	}, nil
+	}
+	if err := g.setInitial(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// setInitial sets the progress provided via the InitialPercent or the
+// InitialAbsolute option.
+func (g *Gauge) setInitial() error {
+	ip := g.opts.initial
+	if ip == nil {
+		return nil
+	}
+	switch ip.pt {
+	case progressTypeAbsolute:
+		return g.Absolute(ip.done, ip.total)
+	default:
+		return g.Percent(ip.done)
+	}
 }
 
 // Absolute sets the progress in absolute numbers, i.e. 7 out of 10.
@@ -109,9 +147,7 @@ func (g *Gauge) Absolute(done, total int, opts ...Option) error {
 		opt.set(g.opts)
 	}
 
-	g.pt = progressTypeAbsolute
-	g.current = done
-	g.total = total
+	g.setProgress(progressTypeAbsolute, done, total)
 	return nil
 }
 
@@ -130,16 +166,47 @@ func (g *Gauge) Percent(p int, opts ...Option) error {
 		opt.set(g.opts)
 	}
 
-	g.pt = progressTypePercent
-	g.current = p
-	g.total = 100
+	g.setProgress(progressTypePercent, p, 100)
 	return nil
 }
 
+// setProgress sets the current progress and remembers the previous one so
+// that the trend can be determined.
+func (g *Gauge) setProgress(pt progressType, current, total int) {
+	if g.updated {
+		g.prevPT = g.pt
+		g.prevCurrent = g.current
+		g.prevTotal = g.total
+		g.hasPrev = true
+	}
+	g.updated = true
+
+	g.pt = pt
+	g.current = current
+	g.total = total
+	g.dirty.Mark()
+	if g.linked != nil && g.linked != g.opts.scale {
+		g.linked.Unlink(g)
+	}
+	g.linked = g.opts.scale
+	if g.opts.scale != nil {
+		g.opts.scale.report(g, total)
+	}
+}
+
 // width determines the required width of the gauge drawn on the provided area
 // in order to represent the current progress.
 func (g *Gauge) width(ar image.Rectangle) int {
-	mult := float32(g.current) / float32(g.total)
+	total := g.total
+	if g.opts.scale != nil {
+		if max := g.opts.scale.Max(); max > 0 {
+			total = max
+		}
+	}
+	mult := float32(g.current) / float32(total)
+	if mult > 1 {
+		mult = 1
+	}
 	width := float32(ar.Dx()) * mult
 	return int(width)
 }
@@ -169,23 +236,162 @@ func (g *Gauge) progressText() string {
 	return fmt.Sprintf("%d/%d", g.current, g.total)
 }
 
+// Description returns a plain text description of the current progress that
+// is suitable for screen readers or logging, e.g. "Disk usage 82 percent" or
+// "Files copied 3 of 10". The description starts with the label provided via
+// the DescriptionLabel or the TextLabel option if any.
+func (g *Gauge) Description() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var progress string
+	if g.pt == progressTypePercent {
+		progress = fmt.Sprintf("%d percent", g.current)
+	} else {
+		progress = fmt.Sprintf("%d of %d", g.current, g.total)
+	}
+
+	label := g.opts.descLabel
+	if label == "" {
+		label = g.opts.textLabel
+	}
+	if label == "" {
+		return progress
+	}
+	return fmt.Sprintf("%s %s", label, progress)
+}
+
+// trend indicates the direction of change of the progress.
+type trend int
+
+const (
+	trendNone trend = iota
+	trendUp
+	trendDown
+	trendFlat
+)
+
+// trendRunes map trends to the runes that indicate them.
+var trendRunes = map[trend]rune{
+	trendUp:   '▲',
+	trendDown: '▼',
+	trendFlat: '▬',
+}
+
+// currentTrend compares the current progress to the previous one.
+func (g *Gauge) currentTrend() trend {
+	if !g.opts.showTrend || !g.hasPrev {
+		return trendNone
+	}
+
+	// Compare the fractions, since the two updates might have used different
+	// progress types or totals.
+	curr := g.current * g.prevTotal
+	prev := g.prevCurrent * g.total
+	switch {
+	case curr > prev:
+		return trendUp
+	case curr < prev:
+		return trendDown
+	default:
+		return trendFlat
+	}
+}
+
+// trendText returns the textual representation of the current trend, i.e. the
+// indicator and the optional delta.
+func (g *Gauge) trendText(t trend) string {
+	if t == trendNone {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteRune(trendRunes[t])
+	if g.opts.showTrendDelta && t != trendFlat && g.pt == g.prevPT && g.total == g.prevTotal {
+		b.WriteString(fmt.Sprintf("%+d", g.current-g.prevCurrent))
+		if g.pt == progressTypePercent {
+			b.WriteString("%")
+		}
+	}
+	return b.String()
+}
+
+// color returns the color of the gauge.
+func (g *Gauge) color(th *theme.Theme) cell.Color {
+	if c, ok := g.thresholdColor(); ok {
+		return c
+	}
+	if th != nil && !g.opts.colorSet {
+		return th.Primary
+	}
+	return g.opts.color
+}
+
+// thresholdColor returns the color of the highest threshold reached by the
+// current progress or false if no threshold was reached.
+func (g *Gauge) thresholdColor() (cell.Color, bool) {
+	if len(g.opts.thresholds) == 0 || g.total == 0 {
+		return 0, false
+	}
+	// Compare in integers to avoid rounding, i.e. current/total >= percent/100.
+	var (
+		color   cell.Color
+		reached bool
+	)
+	for _, t := range g.opts.thresholds {
+		if g.current*100 < t.Percent*g.total {
+			break
+		}
+		color = t.Color
+		reached = true
+	}
+	return color, reached
+}
+
+// trendColor returns the color of the trend text or false if the trend text
+// should use the color of the text progress.
+func (g *Gauge) trendColor(t trend, th *theme.Theme) (cell.Color, bool) {
+	useTheme := th != nil && !g.opts.trendColorsSet
+	switch {
+	case t == trendUp && useTheme:
+		return th.Success, true
+	case t == trendUp:
+		return g.opts.trendUpColor, true
+	case t == trendDown && useTheme:
+		return th.Error, true
+	case t == trendDown:
+		return g.opts.trendDownColor, true
+	default:
+		return cell.ColorDefault, false
+	}
+}
+
 // gaugeText returns full text to be displayed within the gauge, i.e. the
-// progress text and the optional label.
-func (g *Gauge) gaugeText() string {
+// progress text, the optional trend and the optional label.
+// Also returns the range of rune indexes in the text that contain the trend.
+func (g *Gauge) gaugeText() (text string, trendStart, trendEnd int) {
 	var b strings.Builder
 	b.WriteString(g.progressText())
+	if tt := g.trendText(g.currentTrend()); tt != "" {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		trendStart = len([]rune(b.String()))
+		b.WriteString(tt)
+		trendEnd = trendStart + len([]rune(tt))
+	}
 	if g.opts.textLabel != "" {
 		if b.Len() > 0 {
 			b.WriteString(" ")
 		}
 		b.WriteString(fmt.Sprintf("(%s)", g.opts.textLabel))
 	}
-	return b.String()
+	return b.String(), trendStart, trendEnd
 }
 
 // drawText draws the text enumerating the progress and the text label.
-func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
-	text := g.gaugeText()
+func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle, th *theme.Theme) error {
+	text, trendStart, trendEnd := g.gaugeText()
 	if text == "" {
 		return nil
 	}
@@ -201,7 +407,8 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 		return err
 	}
 
-	for _, r := range trimmed {
+	trendColor, hasTrendColor := g.trendColor(g.currentTrend(), th)
+	for i, r := range []rune(trimmed) {
 		if !cur.In(ar) {
 			break
 		}
@@ -220,7 +427,7 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 			)
 			if err := draw.Rectangle(cvs, fixup,
 				draw.RectChar(g.opts.gaugeChar),
-				draw.RectCellOpts(cell.BgColor(g.opts.color)),
+				draw.RectCellOpts(cell.BgColor(g.color(th))),
 			); err != nil {
 				return err
 			}
@@ -233,6 +440,9 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 		} else {
 			cellOpts = append(cellOpts, cell.FgColor(g.opts.emptyTextColor))
 		}
+		if hasTrendColor && i >= trendStart && i < trendEnd {
+			cellOpts = append(cellOpts, cell.FgColor(trendColor))
+		}
 
 		cells, err := cvs.SetCell(cur, r, cellOpts...)
 		if err != nil {
@@ -269,6 +479,10 @@ func (g *Gauge) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		}
 	}
 
+	var th *theme.Theme
+	if meta != nil {
+		th = meta.Theme
+	}
 	usable := g.usable(cvs)
 	progress := image.Rect(
 		usable.Min.X,
@@ -279,12 +493,12 @@ func (g *Gauge) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	if progress.Dx() > 0 {
 		if err := draw.Rectangle(cvs, progress,
 			draw.RectChar(g.opts.gaugeChar),
-			draw.RectCellOpts(cell.BgColor(g.opts.color)),
+			draw.RectCellOpts(cell.BgColor(g.color(th))),
 		); err != nil {
 			return err
 		}
 	}
-	return g.drawText(cvs, progress)
+	return g.drawText(cvs, progress, th)
 }
 
 // Keyboard input isn't supported on the Gauge widget.
@@ -330,3 +544,8 @@ func (g *Gauge) Options() widgetapi.Options {
 		WantMouse:    widgetapi.MouseScopeNone,
 	}
 }
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (g *Gauge) SetDirtyFunc(markDirty func()) {
+	g.dirty.Set(markDirty)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/mum4k/termdash/private/draw/testdraw"
 	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -45,10 +46,20 @@ type absoluteCall struct {
 	opts  []Option
 }
 
+// mustScale returns a new Scale with the provided maximum or panics.
+func mustScale(max int) *Scale {
+	s := NewScale()
+	if err := s.SetMax(max); err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func TestGauge(t *testing.T) {
 	tests := []struct {
 		desc          string
 		opts          []Option
+		prevPercent   *percentCall  // if set, the test case calls Gauge.Percent() before any other update.
 		percent       *percentCall  // if set, the test case calls Gauge.Percent().
 		absolute      *absoluteCall // if set the test case calls Gauge.Absolute().
 		canvas        image.Rectangle
@@ -100,6 +111,27 @@ func TestGauge(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "draws progress relative to the shared scale",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				SharedScale(mustScale(20)),
+			},
+			absolute: &absoluteCall{done: 5, total: 10},
+			canvas:   image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 2, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "sets gauge color",
 			opts: []Option{
@@ -121,6 +153,200 @@ func TestGauge(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "fails on threshold percent out of range",
+			opts: []Option{
+				Thresholds(Threshold{Percent: 101, Color: cell.ColorRed}),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on thresholds out of order",
+			opts: []Option{
+				Thresholds(
+					Threshold{Percent: 90, Color: cell.ColorRed},
+					Threshold{Percent: 70, Color: cell.ColorYellow},
+				),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "progress below thresholds uses the gauge color",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Color(cell.ColorBlue),
+				Thresholds(
+					Threshold{Percent: 70, Color: cell.ColorYellow},
+					Threshold{Percent: 90, Color: cell.ColorRed},
+				),
+			},
+			percent: &percentCall{p: 69},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 6, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorBlue)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "progress at a threshold uses its color",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Thresholds(
+					Threshold{Percent: 70, Color: cell.ColorYellow},
+					Threshold{Percent: 90, Color: cell.ColorRed},
+				),
+			},
+			percent: &percentCall{p: 70},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 7, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "progress above the highest threshold uses its color",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Thresholds(
+					Threshold{Percent: 70, Color: cell.ColorYellow},
+					Threshold{Percent: 90, Color: cell.ColorRed},
+				),
+			},
+			absolute: &absoluteCall{done: 9, total: 10},
+			canvas:   image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 9, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorRed)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "threshold color takes precedence over the theme",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Thresholds(Threshold{Percent: 50, Color: cell.ColorRed}),
+			},
+			percent: &percentCall{p: 50},
+			canvas:  image.Rect(0, 0, 10, 3),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorBlue,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 5, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorRed)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "uses colors from the theme",
+			opts: []Option{
+				Char('o'),
+				ShowTrend(),
+			},
+			prevPercent: &percentCall{p: 40},
+			percent:     &percentCall{p: 35},
+			canvas:      image.Rect(0, 0, 10, 3),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorBlue,
+					Error:   cell.ColorMagenta,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorBlue)),
+				)
+				testdraw.MustText(c, "3", image.Point{2, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "5% ", image.Point{3, 1})
+				testdraw.MustText(c, "▼", image.Point{6, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorMagenta)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "explicitly set colors take precedence over the theme",
+			opts: []Option{
+				Char('o'),
+				ShowTrend(),
+				Color(cell.ColorRed),
+				TrendColors(cell.ColorBlue, cell.ColorYellow),
+			},
+			prevPercent: &percentCall{p: 40},
+			percent:     &percentCall{p: 35},
+			canvas:      image.Rect(0, 0, 10, 3),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorBlue,
+					Error:   cell.ColorMagenta,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorRed)),
+				)
+				testdraw.MustText(c, "3", image.Point{2, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "5% ", image.Point{3, 1})
+				testdraw.MustText(c, "▼", image.Point{6, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "gauge showing percentage",
 			opts: []Option{
@@ -141,6 +367,140 @@ func TestGauge(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "gauge showing initial percentage set via option",
+			opts: []Option{
+				Char('o'),
+				InitialPercent(35),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "35%", image.Point{3, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "fails on invalid initial absolute progress",
+			opts: []Option{
+				InitialAbsolute(11, 10),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "doesn't show trend on the first update",
+			opts: []Option{
+				Char('o'),
+				ShowTrend(),
+			},
+			percent: &percentCall{p: 35},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "35%", image.Point{3, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "shows upward trend with delta",
+			opts: []Option{
+				Char('o'),
+				ShowTrendDelta(),
+			},
+			prevPercent: &percentCall{p: 30},
+			percent:     &percentCall{p: 35},
+			canvas:      image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "35", image.Point{1, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "% ", image.Point{3, 1})
+				testdraw.MustText(c, "▲+5%", image.Point{5, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "shows downward trend in custom color",
+			opts: []Option{
+				Char('o'),
+				ShowTrend(),
+				TrendColors(cell.ColorBlue, cell.ColorYellow),
+			},
+			prevPercent: &percentCall{p: 40},
+			percent:     &percentCall{p: 35},
+			canvas:      image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "3", image.Point{2, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "5% ", image.Point{3, 1})
+				testdraw.MustText(c, "▼", image.Point{6, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "shows flat trend without delta",
+			opts: []Option{
+				Char('o'),
+				ShowTrendDelta(),
+			},
+			prevPercent: &percentCall{p: 35},
+			percent:     &percentCall{p: 35},
+			canvas:      image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 3, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "3", image.Point{2, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "5% ▬", image.Point{3, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "fails when Percent is less than zero",
 			opts: []Option{
@@ -858,6 +1218,12 @@ func TestGauge(t *testing.T) {
 				t.Fatalf("canvas.New => unexpected error: %v", err)
 			}
 
+			if tc.prevPercent != nil {
+				if err := g.Percent(tc.prevPercent.p, tc.prevPercent.opts...); err != nil {
+					t.Fatalf("Percent => unexpected error: %v", err)
+				}
+			}
+
 			switch {
 			case tc.percent != nil:
 				err := g.Percent(tc.percent.p, tc.percent.opts...)
@@ -903,6 +1269,72 @@ func TestGauge(t *testing.T) {
 	}
 }
 
+func TestDescription(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []Option
+		progress func(*Gauge) error
+		want     string
+	}{
+		{
+			desc: "no progress reported",
+			want: "0 percent",
+		},
+		{
+			desc: "percent progress without a label",
+			progress: func(g *Gauge) error {
+				return g.Percent(82)
+			},
+			want: "82 percent",
+		},
+		{
+			desc: "absolute progress without a label",
+			progress: func(g *Gauge) error {
+				return g.Absolute(3, 10)
+			},
+			want: "3 of 10",
+		},
+		{
+			desc: "uses the text label",
+			opts: []Option{
+				TextLabel("Disk usage"),
+			},
+			progress: func(g *Gauge) error {
+				return g.Percent(82)
+			},
+			want: "Disk usage 82 percent",
+		},
+		{
+			desc: "description label takes precedence over the text label",
+			opts: []Option{
+				TextLabel("disk"),
+				DescriptionLabel("Disk usage"),
+			},
+			progress: func(g *Gauge) error {
+				return g.Percent(82)
+			},
+			want: "Disk usage 82 percent",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			g, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if tc.progress != nil {
+				if err := tc.progress(g); err != nil {
+					t.Fatalf("tc.progress => unexpected error: %v", err)
+				}
+			}
+			if got := g.Description(); got != tc.want {
+				t.Errorf("Description => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestKeyboard(t *testing.T) {
 	g, err := New()
 	if err != nil {
@@ -90,6 +90,10 @@ func main() {
 		gauge.Height(1),
 		gauge.Border(linestyle.Light),
 		gauge.BorderTitle("Percentage progress"),
+		gauge.Thresholds(
+			gauge.Threshold{Percent: 70, Color: cell.ColorYellow},
+			gauge.Threshold{Percent: 90, Color: cell.ColorRed},
+		),
 	)
 	if err != nil {
 		panic(err)
@@ -37,6 +37,7 @@ type options struct {
 	hideTextProgress bool
 	height           int
 	textLabel        string
+	descLabel        string
 	hTextAlign       align.Horizontal
 	vTextAlign       align.Vertical
 	color            cell.Color
@@ -47,6 +48,17 @@ type options struct {
 	borderCellOpts    []cell.Option
 	borderTitle       string
 	borderTitleHAlign align.Horizontal
+	showTrend         bool
+	showTrendDelta    bool
+	trendUpColor      cell.Color
+	trendDownColor    cell.Color
+	// colorSet and trendColorsSet indicate if the colors were set explicitly,
+	// otherwise the colors of the dashboard theme take precedence.
+	colorSet       bool
+	trendColorsSet bool
+	initial        *initialProgress
+	scale          *Scale
+	thresholds     []Threshold
 }
 
 // newOptions returns options with the default values set.
@@ -58,6 +70,8 @@ func newOptions() *options {
 		color:           DefaultColor,
 		filledTextColor: DefaultFilledTextColor,
 		emptyTextColor:  DefaultEmptyTextColor,
+		trendUpColor:    DefaultTrendUpColor,
+		trendDownColor:  DefaultTrendDownColor,
 	}
 }
 
@@ -66,6 +80,14 @@ func (o *options) validate() error {
 	if got, min := o.height, 0; got < min {
 		return fmt.Errorf("invalid Height %d, must be %d <= Height", got, min)
 	}
+	for i, t := range o.thresholds {
+		if min, max := 0, 100; t.Percent < min || t.Percent > max {
+			return fmt.Errorf("invalid Threshold percent %d, must be in range %d <= percent <= %d", t.Percent, min, max)
+		}
+		if i > 0 && t.Percent <= o.thresholds[i-1].Percent {
+			return fmt.Errorf("invalid Thresholds, percents must be in ascending order, got %d after %d", t.Percent, o.thresholds[i-1].Percent)
+		}
+	}
 	return nil
 }
 
@@ -123,13 +145,25 @@ func TextLabel(text string) Option {
 	})
 }
 
+// DescriptionLabel sets the label used by the Description method to describe
+// what the Gauge measures, e.g. "Disk usage". This label isn't drawn.
+// Defaults to the text provided via the TextLabel option.
+func DescriptionLabel(text string) Option {
+	return option(func(opts *options) {
+		opts.descLabel = text
+	})
+}
+
 // DefaultColor is the default value for the Color option.
 const DefaultColor = cell.ColorGreen
 
 // Color sets the color of the gauge.
+// Defaults to the Primary color of the dashboard theme if set, otherwise to
+// DefaultColor.
 func Color(c cell.Color) Option {
 	return option(func(opts *options) {
 		opts.color = c
+		opts.colorSet = true
 	})
 }
 
@@ -201,3 +235,123 @@ func BorderTitleAlign(h align.Horizontal) Option {
 		opts.borderTitleHAlign = h
 	})
 }
+
+// ShowTrend configures the Gauge to display a trend indicator next to the
+// text progress. The indicator compares the current progress to the one set on
+// the previous call to Percent() or Absolute(). It is an upwards arrow if the
+// progress increased, a downwards arrow if it decreased and a bar if it didn't
+// change. Nothing is displayed until the progress is updated at least twice.
+func ShowTrend() Option {
+	return option(func(opts *options) {
+		opts.showTrend = true
+	})
+}
+
+// ShowTrendDelta configures the Gauge to also display the difference between
+// the current and the previous progress next to the trend indicator, e.g.
+// "+5%" or "-2".
+// The difference is only displayed if both updates used the same progress
+// type and the same total. Providing this option also sets ShowTrend.
+func ShowTrendDelta() Option {
+	return option(func(opts *options) {
+		opts.showTrend = true
+		opts.showTrendDelta = true
+	})
+}
+
+// DefaultTrendUpColor is the default value for the up color of the TrendColors
+// option.
+const DefaultTrendUpColor = cell.ColorGreen
+
+// DefaultTrendDownColor is the default value for the down color of the
+// TrendColors option.
+const DefaultTrendDownColor = cell.ColorRed
+
+// TrendColors sets the color of the trend indicator when the progress
+// increases and when it decreases. An unchanged progress uses the color of
+// the text progress.
+// Defaults to the Success and Error colors of the dashboard theme if set,
+// otherwise to DefaultTrendUpColor and DefaultTrendDownColor.
+func TrendColors(up, down cell.Color) Option {
+	return option(func(opts *options) {
+		opts.trendUpColor = up
+		opts.trendDownColor = down
+		opts.trendColorsSet = true
+	})
+}
+
+// initialProgress is the progress provided via the InitialPercent or the
+// InitialAbsolute option.
+type initialProgress struct {
+	pt    progressType
+	done  int
+	total int
+}
+
+// InitialPercent sets the progress in percents that the Gauge displays from
+// the start, as if Percent was called right after New.
+// Only takes effect when provided to New.
+func InitialPercent(p int) Option {
+	return option(func(opts *options) {
+		opts.initial = &initialProgress{
+			pt:   progressTypePercent,
+			done: p,
+		}
+	})
+}
+
+// InitialAbsolute sets the progress in absolute numbers that the Gauge
+// displays from the start, as if Absolute was called right after New.
+// Only takes effect when provided to New.
+func InitialAbsolute(done, total int) Option {
+	return option(func(opts *options) {
+		opts.initial = &initialProgress{
+			pt:    progressTypeAbsolute,
+			done:  done,
+			total: total,
+		}
+	})
+}
+
+// SharedScale links the gauge to the provided scale shared with other gauges.
+// The gauge then draws its progress relative to the maximum of the scale
+// instead of to its own total, which makes the progress of all the gauges
+// linked to the scale comparable. The progress text isn't affected.
+// Progress set in percents has the total of 100.
+func SharedScale(s *Scale) Option {
+	return option(func(opts *options) {
+		opts.scale = s
+	})
+}
+
+// Threshold changes the color of the gauge once the progress reaches the
+// percentage.
+type Threshold struct {
+	// Percent is the progress in percents of the total at which the color
+	// applies, must be in range 0 <= Percent <= 100.
+	Percent int
+	// Color is the color of the gauge at or above the Percent.
+	Color cell.Color
+}
+
+// Thresholds sets color zones of the gauge, the gauge is drawn in the color of
+// the highest threshold the progress reached. Progress below all the
+// thresholds uses the color set via the Color option. The thresholds must be
+// provided in ascending order of their percents.
+// E.g. to draw the gauge green below 70%, yellow below 90% and red above:
+//
+//	gauge.Color(cell.ColorGreen),
+//	gauge.Thresholds(
+//	  gauge.Threshold{Percent: 70, Color: cell.ColorYellow},
+//	  gauge.Threshold{Percent: 90, Color: cell.ColorRed},
+//	),
+//
+// The progress is compared to the total of the gauge even if it is linked to
+// a SharedScale.
+func Thresholds(thresholds ...Threshold) Option {
+	return option(func(opts *options) {
+		// Copy to avoid external modifications.
+		opts.thresholds = make([]Threshold, len(thresholds))
+		copy(opts.thresholds, thresholds)
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gauge
+
+// scale.go contains code for a scale shared by multiple gauges.
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Scale is a scale shared by a group of gauges that makes their progress
+// comparable. Gauges linked to the same Scale via the SharedScale option draw
+// their progress relative to the maximum of the scale instead of to their own
+// total.
+//
+// The maximum is either set by calling SetMax or if not set, it is the largest
+// total among the progress set on the linked gauges.
+//
+// The scale references the linked gauges, call Unlink for gauges that are no
+// longer displayed, e.g. after they were replaced in the container.
+//
+// The zero value is a scale with its maximum determined automatically.
+// This object is thread-safe.
+type Scale struct {
+	// mu protects the Scale.
+	mu sync.Mutex
+
+	// fixedMax is the maximum set by calling SetMax, zero if not set.
+	fixedMax int
+
+	// totals are the last totals of the linked gauges, nil until the first
+	// gauge reports its progress.
+	totals map[*Gauge]int
+}
+
+// NewScale returns a new Scale with its maximum determined automatically.
+func NewScale() *Scale {
+	return &Scale{}
+}
+
+// SetMax sets the maximum of the scale. The value must be zero or a positive
+// integer, zero makes the maximum determined automatically again.
+func (s *Scale) SetMax(max int) error {
+	if max < 0 {
+		return fmt.Errorf("invalid scale maximum %d, must be zero or a positive integer", max)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixedMax = max
+	s.markDirty()
+	return nil
+}
+
+// Max returns the current maximum of the scale.
+// Returns zero if the maximum isn't set and no linked gauge has any progress.
+func (s *Scale) Max() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fixedMax > 0 {
+		return s.fixedMax
+	}
+	var max int
+	for _, t := range s.totals {
+		if t > max {
+			max = t
+		}
+	}
+	return max
+}
+
+// Unlink removes the gauge from the scale, so that its total no longer
+// affects the maximum and the scale releases the gauge. The gauge is linked
+// again if it reports progress while still using the SharedScale option with
+// this scale. Gauges that change the SharedScale option are unlinked from the
+// previous scale automatically.
+func (s *Scale) Unlink(g *Gauge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.totals[g]; !ok {
+		return
+	}
+	delete(s.totals, g)
+	s.markDirty()
+}
+
+// report records the total of the progress set on the gauge.
+func (s *Scale) report(g *Gauge, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.totals == nil {
+		s.totals = map[*Gauge]int{}
+	}
+	s.totals[g] = total
+	s.markDirty()
+}
+
+// markDirty reports a change of the content of all the linked gauges, since
+// the maximum of the scale might have changed.
+// The caller must hold s.mu.
+func (s *Scale) markDirty() {
+	for g := range s.totals {
+		g.dirty.Mark()
+	}
+}
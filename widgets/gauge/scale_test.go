@@ -0,0 +1,172 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gauge
+
+import "testing"
+
+func TestScale(t *testing.T) {
+	s := NewScale()
+	if got, want := s.Max(), 0; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+
+	g1, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	g2, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	if err := g1.Absolute(5, 30); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if err := g2.Absolute(10, 20); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 30; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+
+	// Replacing the progress replaces the total reported by the gauge.
+	if err := g1.Percent(50); err != nil {
+		t.Fatalf("Percent => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 100; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+
+	if err := s.SetMax(-1); err == nil {
+		t.Errorf("SetMax(-1) => expected an error")
+	}
+	if err := s.SetMax(200); err != nil {
+		t.Fatalf("SetMax => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 200; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+
+	// Zero makes the maximum automatic again.
+	if err := s.SetMax(0); err != nil {
+		t.Fatalf("SetMax => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 100; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+}
+
+func TestScaleMarksLinkedGaugesDirty(t *testing.T) {
+	s := NewScale()
+	g1, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	g2, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := g1.Absolute(1, 10); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if err := g2.Absolute(1, 10); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+
+	var marks1, marks2 int
+	g1.SetDirtyFunc(func() { marks1++ })
+	g2.SetDirtyFunc(func() { marks2++ })
+
+	// A new total of one gauge changes the scale of both.
+	if err := g1.Absolute(1, 20); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if marks1 == 0 || marks2 == 0 {
+		t.Errorf("Absolute => marked the gauges dirty %d and %d times, want both marked", marks1, marks2)
+	}
+
+	marks1, marks2 = 0, 0
+	if err := s.SetMax(50); err != nil {
+		t.Fatalf("SetMax => unexpected error: %v", err)
+	}
+	if marks1 == 0 || marks2 == 0 {
+		t.Errorf("SetMax => marked the gauges dirty %d and %d times, want both marked", marks1, marks2)
+	}
+}
+
+func TestScaleZeroValue(t *testing.T) {
+	var s Scale
+	g, err := New(SharedScale(&s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := g.Absolute(1, 10); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 10; got != want {
+		t.Errorf("Max => %d, want %d", got, want)
+	}
+}
+
+func TestScaleUnlink(t *testing.T) {
+	s := NewScale()
+	g1, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	g2, err := New(SharedScale(s))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := g1.Absolute(1, 30); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if err := g2.Absolute(1, 20); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+
+	var marks int
+	g2.SetDirtyFunc(func() { marks++ })
+	s.Unlink(g1)
+	if got, want := s.Max(), 20; got != want {
+		t.Errorf("after Unlink => Max %d, want %d", got, want)
+	}
+	if _, ok := s.totals[g1]; ok {
+		t.Errorf("after Unlink => the scale still references the gauge")
+	}
+	if marks == 0 {
+		t.Errorf("Unlink => didn't mark the remaining gauge dirty")
+	}
+
+	// Reporting progress links the gauge again.
+	if err := g1.Absolute(1, 40); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 40; got != want {
+		t.Errorf("after Absolute => Max %d, want %d", got, want)
+	}
+
+	// Switching to another scale unlinks the gauge from the previous one.
+	other := NewScale()
+	if err := g1.Absolute(1, 40, SharedScale(other)); err != nil {
+		t.Fatalf("Absolute => unexpected error: %v", err)
+	}
+	if got, want := s.Max(), 20; got != want {
+		t.Errorf("after switching scales => Max %d, want %d", got, want)
+	}
+	if got, want := other.Max(), 40; got != want {
+		t.Errorf("after switching scales => other Max %d, want %d", got, want)
+	}
+}
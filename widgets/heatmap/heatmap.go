@@ -22,6 +22,7 @@ import (
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 	"github.com/mum4k/termdash/widgets/heatmap/internal/axes"
@@ -57,6 +58,9 @@ type HeatMap struct {
 	// opts are the provided options.
 	opts *options
 
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the HeatMap widget.
 	mu sync.RWMutex
 }
@@ -82,11 +86,13 @@ func (hp *HeatMap) Values(xLabels []string, yLabels []string, values [][]float64
 // ClearXLabels clear the X labels.
 func (hp *HeatMap) ClearXLabels() {
 	hp.xLabels = nil
+	hp.dirty.Mark()
 }
 
 // ClearYLabels clear the Y labels.
 func (hp *HeatMap) ClearYLabels() {
 	hp.yLabels = nil
+	hp.dirty.Mark()
 }
 
 // ValueCapacity returns the number of values that can fit into the canvas.
@@ -144,6 +150,11 @@ func (hp *HeatMap) Options() widgetapi.Options {
 	return widgetapi.Options{}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (hp *HeatMap) SetDirtyFunc(markDirty func()) {
+	hp.dirty.Set(markDirty)
+}
+
 // getCellColor returns the color of the cell according to its value.
 // The larger the value, the darker the color.
 // The color range is in Xterm color, from 232 to 255.
@@ -0,0 +1,180 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+// graphics.go contains code that encodes pictures for the terminal graphics
+// protocols.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"sort"
+)
+
+// kittyChunkSize is the maximum size of the base64 encoded payload of a
+// single escape sequence of the kitty graphics protocol.
+const kittyChunkSize = 4096
+
+// encodeKitty encodes the pixels as a PNG image displayed using the kitty
+// graphics protocol and scaled to the specified number of cells.
+func encodeKitty(px [][]color.NRGBA, cells image.Point) ([]byte, error) {
+	var img bytes.Buffer
+	if err := png.Encode(&img, toNRGBA(px)); err != nil {
+		return nil, fmt.Errorf("png.Encode => %v", err)
+	}
+	payload := base64.StdEncoding.EncodeToString(img.Bytes())
+
+	var b bytes.Buffer
+	for start := 0; start < len(payload); start += kittyChunkSize {
+		end := start + kittyChunkSize
+		more := 1
+		if end >= len(payload) {
+			end = len(payload)
+			more = 0
+		}
+		if start == 0 {
+			// Transmit and display the PNG without moving the cursor.
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;", cells.X, cells.Y, more)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;", more)
+		}
+		b.WriteString(payload[start:end])
+		b.WriteString("\x1b\\")
+	}
+	return b.Bytes(), nil
+}
+
+// sixelLevels is the number of levels of each color component in the
+// palette used to encode sixel images.
+const sixelLevels = 6
+
+// encodeSixel encodes the pixels as a sixel image. The colors are reduced to
+// a palette of 216 colors, transparent pixels aren't drawn.
+func encodeSixel(px [][]color.NRGBA) []byte {
+	w := len(px)
+	h := 0
+	if w > 0 {
+		h = len(px[0])
+	}
+
+	// The palette index of each pixel or -1 for transparent pixels.
+	idx := make([][]int, w)
+	used := map[int]bool{}
+	for x := range px {
+		idx[x] = make([]int, h)
+		for y, c := range px[x] {
+			if !opaque(c) {
+				idx[x][y] = -1
+				continue
+			}
+			i := sixelLevel(c.R)*sixelLevels*sixelLevels + sixelLevel(c.G)*sixelLevels + sixelLevel(c.B)
+			idx[x][y] = i
+			used[i] = true
+		}
+	}
+
+	var b bytes.Buffer
+	// Pixels without color remain transparent, the aspect ratio is 1:1.
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	var colors []int
+	for i := range used {
+		colors = append(colors, i)
+	}
+	sort.Ints(colors)
+	for _, i := range colors {
+		r, g, bl := i/(sixelLevels*sixelLevels), i/sixelLevels%sixelLevels, i%sixelLevels
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, sixelPercent(r), sixelPercent(g), sixelPercent(bl))
+	}
+
+	// Each band of sixels covers six rows of pixels.
+	for band := 0; band < h; band += 6 {
+		first := true
+		for _, i := range colors {
+			sixels := make([]byte, w)
+			found := false
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if idx[x][band+dy] == i {
+						bits |= 1 << uint(dy)
+						found = true
+					}
+				}
+				sixels[x] = '?' + bits
+			}
+			if !found {
+				continue
+			}
+			if !first {
+				// Return to the start of the band for the next color.
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", i)
+			writeSixelRuns(&b, sixels)
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.Bytes()
+}
+
+// writeSixelRuns writes the sixels using run-length encoding for repeated
+// sixels.
+func writeSixelRuns(b *bytes.Buffer, sixels []byte) {
+	for i := 0; i < len(sixels); {
+		j := i
+		for j < len(sixels) && sixels[j] == sixels[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, sixels[i])
+		} else {
+			b.Write(sixels[i:j])
+		}
+		i = j
+	}
+}
+
+// sixelLevel returns the level of the color component in the sixel palette.
+func sixelLevel(v uint8) int {
+	return (int(v)*(sixelLevels-1) + 127) / 255
+}
+
+// sixelPercent converts the level of a color component in the sixel palette
+// to percents.
+func sixelPercent(level int) int {
+	return level * 100 / (sixelLevels - 1)
+}
+
+// toNRGBA converts the pixels indexed as [x][y] to an image.
+func toNRGBA(px [][]color.NRGBA) *image.NRGBA {
+	w := len(px)
+	h := 0
+	if w > 0 {
+		h = len(px[0])
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := range px {
+		for y, c := range px[x] {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
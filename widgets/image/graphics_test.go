@@ -0,0 +1,283 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// pixels converts the picture to pixels indexed as [x][y].
+func pixels(img image.Image) [][]color.NRGBA {
+	return downscale(img, img.Bounds().Size())
+}
+
+func TestEncodeSixel(t *testing.T) {
+	tests := []struct {
+		desc string
+		img  image.Image
+		want string
+	}{
+		{
+			desc: "single pixel",
+			img:  fill(image.Point{1, 1}, red),
+			want: "\x1bP0;1;0q\"1;1;1;1#180;2;100;0;0#180@-\x1b\\",
+		},
+		{
+			desc: "transparent pixels aren't drawn",
+			img: picture(
+				[]color.NRGBA{red, transparent},
+				[]color.NRGBA{transparent, red},
+			),
+			want: "\x1bP0;1;0q\"1;1;2;2#180;2;100;0;0#180@A-\x1b\\",
+		},
+		{
+			desc: "multiple colors in a band",
+			img:  picture([]color.NRGBA{red, blue}),
+			want: "\x1bP0;1;0q\"1;1;2;1#5;2;0;0;100#180;2;100;0;0#5?@$#180@?-\x1b\\",
+		},
+		{
+			desc: "multiple bands",
+			img:  fill(image.Point{1, 7}, red),
+			want: "\x1bP0;1;0q\"1;1;1;7#180;2;100;0;0#180~-#180@-\x1b\\",
+		},
+		{
+			desc: "repeated sixels are run-length encoded",
+			img:  fill(image.Point{5, 1}, red),
+			want: "\x1bP0;1;0q\"1;1;5;1#180;2;100;0;0#180!5@-\x1b\\",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := string(encodeSixel(pixels(tc.img))); got != tc.want {
+				t.Errorf("encodeSixel => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncodeKitty(t *testing.T) {
+	tests := []struct {
+		desc string
+		img  image.Image
+		// wantChunks is the expected number of escape sequences.
+		wantChunks int
+	}{
+		{
+			desc:       "small picture in a single sequence",
+			img:        fill(image.Point{2, 2}, red),
+			wantChunks: 1,
+		},
+		{
+			desc: "large picture split into chunks",
+			img: func() image.Image {
+				// Pseudo-random content that doesn't compress well.
+				img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+				seed := uint32(1)
+				for i := range img.Pix {
+					seed = seed*1103515245 + 12345
+					img.Pix[i] = uint8(seed >> 16)
+					if i%4 == 3 {
+						img.Pix[i] = 0xff // Opaque.
+					}
+				}
+				return img
+			}(),
+			wantChunks: 5,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cells := image.Point{3, 2}
+			got, err := encodeKitty(pixels(tc.img), cells)
+			if err != nil {
+				t.Fatalf("encodeKitty => unexpected error: %v", err)
+			}
+
+			seqs := strings.SplitAfter(string(got), "\x1b\\")
+			seqs = seqs[:len(seqs)-1] // Empty after the last terminator.
+			if len(seqs) < tc.wantChunks {
+				t.Fatalf("encodeKitty => got %d sequences, want at least %d", len(seqs), tc.wantChunks)
+			}
+
+			var payload strings.Builder
+			for i, seq := range seqs {
+				var prefix string
+				switch {
+				case i == 0 && len(seqs) == 1:
+					prefix = "\x1b_Ga=T,f=100,q=2,C=1,c=3,r=2,m=0;"
+				case i == 0:
+					prefix = "\x1b_Ga=T,f=100,q=2,C=1,c=3,r=2,m=1;"
+				case i == len(seqs)-1:
+					prefix = "\x1b_Gm=0;"
+				default:
+					prefix = "\x1b_Gm=1;"
+				}
+				if !strings.HasPrefix(seq, prefix) {
+					t.Fatalf("encodeKitty => sequence %d is %q, want prefix %q", i, seq, prefix)
+				}
+				chunk := strings.TrimSuffix(strings.TrimPrefix(seq, prefix), "\x1b\\")
+				if len(chunk) > kittyChunkSize {
+					t.Errorf("encodeKitty => sequence %d has payload of %d bytes, want at most %d", i, len(chunk), kittyChunkSize)
+				}
+				payload.WriteString(chunk)
+			}
+
+			data, err := base64.StdEncoding.DecodeString(payload.String())
+			if err != nil {
+				t.Fatalf("base64 => unexpected error: %v", err)
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("png.Decode => unexpected error: %v", err)
+			}
+			if got, want := img.Bounds().Size(), tc.img.Bounds().Size(); got != want {
+				t.Errorf("png.Decode => picture of size %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// passthroughTerm is a fake terminal that records the passthrough sequences.
+type passthroughTerm struct {
+	*faketerm.Terminal
+
+	// got are the recorded sequences mapped by the cells they were set at.
+	got map[image.Point]string
+}
+
+// Graphics implements terminalapi.Passthrough.Graphics.
+func (pt *passthroughTerm) Graphics() terminalapi.GraphicsProtocol {
+	return terminalapi.GraphicsSixel
+}
+
+// Passthrough implements terminalapi.Passthrough.Passthrough.
+func (pt *passthroughTerm) Passthrough(p image.Point, seq []byte) error {
+	pt.got[p] = string(seq)
+	return nil
+}
+
+func TestTerminalGraphics(t *testing.T) {
+	pic := fill(image.Point{2, 2}, red)
+	tests := []struct {
+		desc     string
+		opts     []Option
+		graphics terminalapi.GraphicsProtocol
+		want     map[image.Point]string
+		// wantCells indicates if the picture is drawn using cells instead.
+		wantCells bool
+	}{
+		{
+			desc:      "draws cells without the option",
+			graphics:  terminalapi.GraphicsSixel,
+			want:      map[image.Point]string{},
+			wantCells: true,
+		},
+		{
+			desc:      "falls back to cells when the terminal doesn't support graphics",
+			opts:      []Option{TerminalGraphics()},
+			graphics:  terminalapi.GraphicsNone,
+			want:      map[image.Point]string{},
+			wantCells: true,
+		},
+		{
+			desc:     "places sixel picture into the aligned area",
+			opts:     []Option{TerminalGraphics(), CellPixels(image.Point{1, 1})},
+			graphics: terminalapi.GraphicsSixel,
+			want: map[image.Point]string{
+				{0, 1}: string(encodeSixel(pixels(fill(image.Point{3, 3}, red)))),
+			},
+		},
+		{
+			desc:     "places kitty picture into the aligned area",
+			opts:     []Option{TerminalGraphics(), CellPixels(image.Point{1, 2})},
+			graphics: terminalapi.GraphicsKitty,
+			want: map[image.Point]string{
+				{0, 1}: func() string {
+					enc, err := encodeKitty(pixels(fill(image.Point{3, 3}, red)), image.Point{3, 2})
+					if err != nil {
+						panic(err)
+					}
+					return string(enc)
+				}(),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			i, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if err := i.Set(pic); err != nil {
+				t.Fatalf("Set => unexpected error: %v", err)
+			}
+
+			c, err := canvas.New(image.Rect(0, 0, 3, 5))
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := i.Draw(c, &widgetapi.Meta{Graphics: tc.graphics}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			ft, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			term := &passthroughTerm{
+				Terminal: ft,
+				got:      map[image.Point]string{},
+			}
+			if err := c.Apply(term); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if len(term.got) != len(tc.want) {
+				t.Fatalf("Apply => got passthrough at %d cells, want %d", len(term.got), len(tc.want))
+			}
+			for p, want := range tc.want {
+				if got := term.got[p]; got != want {
+					t.Errorf("Apply => passthrough at %v is %q, want %q", p, got, want)
+				}
+			}
+
+			var drawn bool
+			for _, col := range ft.BackBuffer() {
+				for _, cell := range col {
+					if cell.Rune != 0 {
+						drawn = true
+					}
+				}
+			}
+			if drawn != tc.wantCells {
+				t.Errorf("Draw => drew cells: %v, want %v", drawn, tc.wantCells)
+			}
+		})
+	}
+}
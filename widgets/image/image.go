@@ -0,0 +1,396 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package image is a widget that displays pictures.
+package image
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Image displays a picture, e.g. a logo, an avatar or a plot generated
+// elsewhere. The picture is downscaled to the size of the canvas and drawn
+// using colored half block or braille characters, see DisplayMode. Terminals
+// that support a graphics protocol can display the picture itself instead,
+// see TerminalGraphics.
+//
+// The colors are set using cell.ColorRGB24, terminals that don't support 24
+// bit colors display the nearest of the 256 terminal colors instead.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Image struct {
+	// img is the displayed picture.
+	img image.Image
+
+	// encoded caches the picture encoded for the terminal graphics protocol.
+	encoded []byte
+	// encodedKey identifies the parameters the picture was encoded with.
+	encodedKey encodedKey
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the Image.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Image.
+func New(opts ...Option) (*Image, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Image{
+		opts: opt,
+	}, nil
+}
+
+// Set sets the picture to display, replacing any picture set previously.
+// Provided options override values set when New() was called.
+func (i *Image) Set(img image.Image, opts ...Option) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	defer i.dirty.Mark()
+
+	if img == nil {
+		return errors.New("the image cannot be nil, use Clear to remove the picture")
+	}
+	for _, opt := range opts {
+		opt.set(i.opts)
+	}
+	if err := i.opts.validate(); err != nil {
+		return err
+	}
+	i.img = img
+	i.encoded = nil
+	return nil
+}
+
+// Clear removes the displayed picture.
+func (i *Image) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	defer i.dirty.Mark()
+	i.img = nil
+	i.encoded = nil
+}
+
+// Draw draws the Image widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (i *Image) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.img == nil || i.img.Bounds().Empty() {
+		return nil
+	}
+
+	gp := terminalapi.GraphicsNone
+	if i.opts.terminalGraphics {
+		gp = meta.Graphics
+	}
+
+	ar := cvs.Area()
+	mult := image.Point{1, 2}
+	switch {
+	case gp != terminalapi.GraphicsNone:
+		mult = i.opts.cellPixels
+	case i.opts.mode == ModeBraille:
+		mult = image.Point{braille.ColMult, braille.RowMult}
+	}
+	size := fit(i.img.Bounds().Size(), image.Point{ar.Dx() * mult.X, ar.Dy() * mult.Y}, i.opts.stretch)
+	cells := image.Rect(0, 0, (size.X+mult.X-1)/mult.X, (size.Y+mult.Y-1)/mult.Y)
+	picAr, err := alignfor.Rectangle(ar, cells, i.opts.hAlign, i.opts.vAlign)
+	if err != nil {
+		return err
+	}
+
+	if gp != terminalapi.GraphicsNone {
+		return i.drawGraphics(cvs, picAr, size, gp)
+	}
+
+	px := downscale(i.img, size)
+	if i.opts.mode == ModeBraille {
+		return i.drawBraille(cvs, picAr, px)
+	}
+	return drawHalfBlocks(cvs, picAr, px)
+}
+
+// encodedKey identifies the parameters a picture was encoded with.
+type encodedKey struct {
+	// size is the size of the picture in pixels.
+	size image.Point
+	// cells is the number of cells the picture occupies.
+	cells image.Point
+	// gp is the graphics protocol.
+	gp terminalapi.GraphicsProtocol
+}
+
+// drawGraphics sets the picture encoded for the graphics protocol of the
+// terminal on the canvas. The picture of the specified size in pixels
+// occupies the area.
+func (i *Image) drawGraphics(cvs *canvas.Canvas, picAr image.Rectangle, size image.Point, gp terminalapi.GraphicsProtocol) error {
+	key := encodedKey{
+		size:  size,
+		cells: picAr.Size(),
+		gp:    gp,
+	}
+	if i.encoded == nil || i.encodedKey != key {
+		px := downscale(i.img, size)
+		switch gp {
+		case terminalapi.GraphicsKitty:
+			enc, err := encodeKitty(px, key.cells)
+			if err != nil {
+				return err
+			}
+			i.encoded = enc
+		case terminalapi.GraphicsSixel:
+			i.encoded = encodeSixel(px)
+		default:
+			return fmt.Errorf("unsupported graphics protocol %v", gp)
+		}
+		i.encodedKey = key
+	}
+	return cvs.SetPassthrough(picAr.Min, i.encoded)
+}
+
+// drawHalfBlocks draws the pixels into the area of the canvas, two pixels
+// per cell.
+func drawHalfBlocks(cvs *canvas.Canvas, picAr image.Rectangle, px [][]color.NRGBA) error {
+	for x := 0; x < len(px); x++ {
+		for y := 0; y < len(px[x]); y += 2 {
+			top := px[x][y]
+			var bottom color.NRGBA
+			if y+1 < len(px[x]) {
+				bottom = px[x][y+1]
+			}
+
+			var (
+				r     rune
+				cOpts []cell.Option
+			)
+			switch {
+			case opaque(top) && opaque(bottom):
+				r = upperHalfBlock
+				cOpts = []cell.Option{cell.FgColor(toColor(top)), cell.BgColor(toColor(bottom))}
+			case opaque(top):
+				r = upperHalfBlock
+				cOpts = []cell.Option{cell.FgColor(toColor(top))}
+			case opaque(bottom):
+				r = lowerHalfBlock
+				cOpts = []cell.Option{cell.FgColor(toColor(bottom))}
+			default:
+				continue
+			}
+
+			p := image.Point{picAr.Min.X + x, picAr.Min.Y + y/2}
+			if _, err := cvs.SetCell(p, r, cOpts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drawBraille draws the pixels into the area of the canvas, eight pixels per
+// cell.
+func (i *Image) drawBraille(cvs *canvas.Canvas, picAr image.Rectangle, px [][]color.NRGBA) error {
+	bc, err := braille.New(picAr)
+	if err != nil {
+		return err
+	}
+
+	for cx := 0; cx < picAr.Dx(); cx++ {
+		for cy := 0; cy < picAr.Dy(); cy++ {
+			var (
+				sumR, sumG, sumB, dots int
+			)
+			for dx := 0; dx < braille.ColMult; dx++ {
+				for dy := 0; dy < braille.RowMult; dy++ {
+					x, y := cx*braille.ColMult+dx, cy*braille.RowMult+dy
+					if x >= len(px) || y >= len(px[x]) {
+						continue
+					}
+					c := px[x][y]
+					if !opaque(c) || brightness(c) < i.opts.brailleThreshold {
+						continue
+					}
+					if err := bc.SetPixel(image.Point{x, y}); err != nil {
+						return err
+					}
+					sumR += int(c.R)
+					sumG += int(c.G)
+					sumB += int(c.B)
+					dots++
+				}
+			}
+			if dots == 0 {
+				continue
+			}
+
+			avg := color.NRGBA{
+				R: uint8(sumR / dots),
+				G: uint8(sumG / dots),
+				B: uint8(sumB / dots),
+				A: 0xff,
+			}
+			if err := bc.SetCellOpts(image.Point{cx, cy}, cell.FgColor(toColor(avg))); err != nil {
+				return err
+			}
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// Keyboard input isn't supported on the Image widget.
+func (*Image) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the Image widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Image widget.
+func (*Image) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the Image widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (i *Image) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (i *Image) SetDirtyFunc(markDirty func()) {
+	i.dirty.Set(markDirty)
+}
+
+const (
+	// upperHalfBlock is the rune used to draw a pair of pixels, the upper one
+	// in the foreground color and the lower one in the background color.
+	upperHalfBlock = '▀'
+	// lowerHalfBlock is the rune used when only the lower pixel of the pair
+	// isn't transparent.
+	lowerHalfBlock = '▄'
+)
+
+// fit returns the size in pixels of the picture that has the src size when
+// scaled to fit the capacity. Keeps the aspect ratio of the picture unless
+// stretch is true.
+func fit(src, capacity image.Point, stretch bool) image.Point {
+	if stretch {
+		return capacity
+	}
+
+	w := capacity.X
+	h := int(math.Round(float64(src.Y) * float64(w) / float64(src.X)))
+	if h > capacity.Y {
+		h = capacity.Y
+		w = int(math.Round(float64(src.X) * float64(h) / float64(src.Y)))
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return image.Point{w, h}
+}
+
+// downscale resizes the picture to the specified size by averaging the
+// colors of the source pixels that fall into each of the resulting pixels.
+// The result is indexed as [x][y].
+func downscale(img image.Image, size image.Point) [][]color.NRGBA {
+	b := img.Bounds()
+	res := make([][]color.NRGBA, size.X)
+	for x := 0; x < size.X; x++ {
+		res[x] = make([]color.NRGBA, size.Y)
+		x0, x1 := span(b.Min.X, b.Dx(), x, size.X)
+		for y := 0; y < size.Y; y++ {
+			y0, y1 := span(b.Min.Y, b.Dy(), y, size.Y)
+
+			var sumR, sumG, sumB, sumA, n uint64
+			for sx := x0; sx < x1; sx++ {
+				for sy := y0; sy < y1; sy++ {
+					r, g, b, a := img.At(sx, sy).RGBA()
+					sumR += uint64(r)
+					sumG += uint64(g)
+					sumB += uint64(b)
+					sumA += uint64(a)
+					n++
+				}
+			}
+			if sumA == 0 {
+				continue
+			}
+			// The colors are alpha-premultiplied, dividing by the alpha
+			// averages only the visible pixels.
+			res[x][y] = color.NRGBA{
+				R: uint8(sumR * 0xff / sumA),
+				G: uint8(sumG * 0xff / sumA),
+				B: uint8(sumB * 0xff / sumA),
+				A: uint8(sumA / n >> 8),
+			}
+		}
+	}
+	return res
+}
+
+// span returns the range of source pixels [start, end) on one axis that fall
+// into the resulting pixel at index idx when scaling srcLen pixels starting
+// at min to dstLen pixels.
+func span(min, srcLen, idx, dstLen int) (int, int) {
+	start := min + idx*srcLen/dstLen
+	end := min + (idx+1)*srcLen/dstLen
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+// opaque asserts whether the pixel is drawn, pixels that are more than half
+// transparent aren't.
+func opaque(c color.NRGBA) bool {
+	return c.A >= 0x80
+}
+
+// brightness returns the perceived brightness of the pixel in range 0-255.
+func brightness(c color.NRGBA) int {
+	return int(math.Round(0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)))
+}
+
+// toColor converts the pixel to a cell color.
+func toColor(c color.NRGBA) cell.Color {
+	return cell.ColorRGB24(int(c.R), int(c.G), int(c.B))
+}
@@ -0,0 +1,344 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+var (
+	red         = color.NRGBA{R: 255, A: 255}
+	green       = color.NRGBA{G: 255, A: 255}
+	blue        = color.NRGBA{B: 255, A: 255}
+	white       = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	black       = color.NRGBA{A: 255}
+	transparent = color.NRGBA{}
+)
+
+// picture returns a picture with the provided rows of pixels.
+func picture(rows ...[]color.NRGBA) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// fill returns a picture of the specified size filled with the color.
+func fill(size image.Point, c color.NRGBA) image.Image {
+	var rows [][]color.NRGBA
+	for y := 0; y < size.Y; y++ {
+		var row []color.NRGBA
+		for x := 0; x < size.X; x++ {
+			row = append(row, c)
+		}
+		rows = append(rows, row)
+	}
+	return picture(rows...)
+}
+
+// rgb converts the pixel to a cell color.
+func rgb(c color.NRGBA) cell.Color {
+	return cell.ColorRGB24(int(c.R), int(c.G), int(c.B))
+}
+
+func TestImage(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		canvas        image.Rectangle
+		update        func(*Image) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails on unsupported display mode",
+			opts:       []Option{DisplayMode(Mode(-1))},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on braille threshold too large",
+			opts:       []Option{BrailleThreshold(256)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on negative braille threshold",
+			opts:       []Option{BrailleThreshold(-1)},
+			wantNewErr: true,
+		},
+		{
+			desc:   "fails to set a nil picture",
+			canvas: image.Rect(0, 0, 2, 1),
+			update: func(i *Image) error {
+				return i.Set(nil)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails on invalid options provided to Set",
+			canvas: image.Rect(0, 0, 2, 1),
+			update: func(i *Image) error {
+				return i.Set(fill(image.Point{1, 1}, red), BrailleThreshold(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws nothing without a picture",
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws two pixels in each cell with half blocks",
+			canvas: image.Rect(0, 0, 2, 1),
+			update: func(i *Image) error {
+				return i.Set(picture(
+					[]color.NRGBA{red, green},
+					[]color.NRGBA{blue, white},
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▀', cell.FgColor(rgb(red)), cell.BgColor(rgb(blue)))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▀', cell.FgColor(rgb(green)), cell.BgColor(rgb(white)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't draw transparent pixels with half blocks",
+			canvas: image.Rect(0, 0, 3, 1),
+			update: func(i *Image) error {
+				return i.Set(picture(
+					[]color.NRGBA{red, transparent, transparent},
+					[]color.NRGBA{transparent, green, transparent},
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▀', cell.FgColor(rgb(red)))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▄', cell.FgColor(rgb(green)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "averages the pixels when downscaling",
+			canvas: image.Rect(0, 0, 1, 1),
+			update: func(i *Image) error {
+				return i.Set(picture(
+					[]color.NRGBA{red, blue},
+					[]color.NRGBA{red, blue},
+					[]color.NRGBA{green, green},
+					[]color.NRGBA{green, green},
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▀',
+					cell.FgColor(cell.ColorRGB24(127, 0, 127)),
+					cell.BgColor(rgb(green)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "keeps the aspect ratio and centers the picture",
+			canvas: image.Rect(0, 0, 4, 2),
+			update: func(i *Image) error {
+				return i.Set(fill(image.Point{1, 2}, red))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(c, image.Rect(1, 0, 3, 2), '▀', cell.FgColor(rgb(red)), cell.BgColor(rgb(red)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "aligns the picture",
+			opts: []Option{
+				AlignHorizontal(align.HorizontalRight),
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			update: func(i *Image) error {
+				return i.Set(fill(image.Point{1, 2}, red))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '▀', cell.FgColor(rgb(red)), cell.BgColor(rgb(red)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "stretches the picture",
+			opts:   []Option{Stretch()},
+			canvas: image.Rect(0, 0, 2, 1),
+			update: func(i *Image) error {
+				return i.Set(fill(image.Point{1, 2}, red))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▀', cell.FgColor(rgb(red)), cell.BgColor(rgb(red)))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▀', cell.FgColor(rgb(red)), cell.BgColor(rgb(red)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws eight pixels in each cell with braille",
+			opts:   []Option{DisplayMode(ModeBraille)},
+			canvas: image.Rect(0, 0, 1, 1),
+			update: func(i *Image) error {
+				return i.Set(fill(image.Point{2, 4}, white))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				bc := testbraille.MustNew(c.Area())
+				for x := 0; x < 2; x++ {
+					for y := 0; y < 4; y++ {
+						testbraille.MustSetPixel(bc, image.Point{x, y})
+					}
+				}
+				testbraille.MustSetCellOpts(bc, image.Point{0, 0}, cell.FgColor(rgb(white)))
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "braille dots only for pixels above the threshold",
+			opts: []Option{
+				DisplayMode(ModeBraille),
+				BrailleThreshold(128),
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			update: func(i *Image) error {
+				return i.Set(picture(
+					[]color.NRGBA{white, red},
+					[]color.NRGBA{black, black},
+					[]color.NRGBA{black, transparent},
+					[]color.NRGBA{black, black},
+				))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				bc := testbraille.MustNew(c.Area())
+				testbraille.MustSetPixel(bc, image.Point{0, 0})
+				testbraille.MustSetCellOpts(bc, image.Point{0, 0}, cell.FgColor(rgb(white)))
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "clear removes the picture",
+			canvas: image.Rect(0, 0, 2, 1),
+			update: func(i *Image) error {
+				if err := i.Set(fill(image.Point{2, 2}, red)); err != nil {
+					return err
+				}
+				i.Clear()
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			i, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.update != nil {
+				err := tc.update(i)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := i.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	i, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := i.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary imagedemo displays a generated picture using the Image widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	timage "github.com/mum4k/termdash/widgets/image"
+)
+
+// picture generates a colorful disc on a transparent background.
+func picture(size int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	mid := float64(size) / 2
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			dx, dy := float64(x)-mid, float64(y)-mid
+			if math.Hypot(dx, dy) > mid {
+				continue
+			}
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(255 * x / size),
+				G: uint8(255 * y / size),
+				B: uint8(255 - 255*x/size),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pic := picture(256)
+	blocks, err := timage.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := blocks.Set(pic); err != nil {
+		panic(err)
+	}
+	dots, err := timage.New(timage.DisplayMode(timage.ModeBraille))
+	if err != nil {
+		panic(err)
+	}
+	if err := dots.Set(pic); err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Half blocks"),
+				container.PlaceWidget(blocks),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Braille"),
+				container.PlaceWidget(dots),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
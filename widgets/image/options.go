@@ -0,0 +1,172 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+// options.go contains configurable options for Image.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/align"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	mode             Mode
+	stretch          bool
+	brailleThreshold int
+	hAlign           align.Horizontal
+	vAlign           align.Vertical
+	terminalGraphics bool
+	cellPixels       image.Point
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		mode:       ModeHalfBlocks,
+		hAlign:     align.HorizontalCenter,
+		vAlign:     align.VerticalMiddle,
+		cellPixels: DefaultCellPixels,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if _, ok := modeNames[o.mode]; !ok {
+		return fmt.Errorf("unsupported DisplayMode %v(%d)", o.mode, o.mode)
+	}
+	if min, max := 0, 255; o.brailleThreshold < min || o.brailleThreshold > max {
+		return fmt.Errorf("invalid BrailleThreshold %d, must be in range %d <= value <= %d", o.brailleThreshold, min, max)
+	}
+	if o.cellPixels.X <= 0 || o.cellPixels.Y <= 0 {
+		return fmt.Errorf("invalid CellPixels %v, both dimensions must be positive", o.cellPixels)
+	}
+	return nil
+}
+
+// Mode determines how the pixels of the picture are drawn.
+type Mode int
+
+// String implements fmt.Stringer()
+func (m Mode) String() string {
+	if n, ok := modeNames[m]; ok {
+		return n
+	}
+	return "ModeUnknown"
+}
+
+// modeNames maps Mode values to human readable names.
+var modeNames = map[Mode]string{
+	ModeHalfBlocks: "ModeHalfBlocks",
+	ModeBraille:    "ModeBraille",
+}
+
+const (
+	// ModeHalfBlocks draws two pixels in each cell, one above the other, using
+	// the upper half block character '▀'. The foreground color of the cell is
+	// the color of the upper pixel and the background color is the color of
+	// the lower pixel.
+	ModeHalfBlocks Mode = iota
+
+	// ModeBraille draws eight pixels in each cell using the braille
+	// characters. A cell can only have one color, so the dots in the cell
+	// get the average color of the pixels they represent. Provides higher
+	// resolution at the cost of color accuracy, see also BrailleThreshold.
+	ModeBraille
+)
+
+// DisplayMode sets how the pixels of the picture are drawn.
+// Defaults to ModeHalfBlocks.
+func DisplayMode(m Mode) Option {
+	return option(func(opts *options) {
+		opts.mode = m
+	})
+}
+
+// Stretch makes the picture fill the entire canvas, ignoring its aspect
+// ratio. By default the picture is scaled to the largest size that fits the
+// canvas while keeping its aspect ratio.
+func Stretch() Option {
+	return option(func(opts *options) {
+		opts.stretch = true
+	})
+}
+
+// BrailleThreshold sets the minimum brightness of a pixel for its dot to be
+// drawn in the ModeBraille. The brightness is in the range 0-255, dots are
+// never drawn for transparent pixels.
+// Defaults to zero, i.e. dots are drawn for all the pixels that aren't
+// transparent.
+func BrailleThreshold(t int) Option {
+	return option(func(opts *options) {
+		opts.brailleThreshold = t
+	})
+}
+
+// AlignHorizontal sets the horizontal alignment of the picture when it is
+// narrower than the canvas. Defaults to align.HorizontalCenter.
+func AlignHorizontal(h align.Horizontal) Option {
+	return option(func(opts *options) {
+		opts.hAlign = h
+	})
+}
+
+// AlignVertical sets the vertical alignment of the picture when it is lower
+// than the canvas. Defaults to align.VerticalMiddle.
+func AlignVertical(v align.Vertical) Option {
+	return option(func(opts *options) {
+		opts.vAlign = v
+	})
+}
+
+// TerminalGraphics displays the picture using the graphics protocol of the
+// terminal, e.g. Sixel or the kitty graphics protocol, if the terminal
+// supports one, see terminalapi.Passthrough. Falls back to the DisplayMode
+// on terminals that cannot display images.
+// Defaults to always using the DisplayMode.
+func TerminalGraphics() Option {
+	return option(func(opts *options) {
+		opts.terminalGraphics = true
+	})
+}
+
+// DefaultCellPixels is the default value for the CellPixels option.
+var DefaultCellPixels = image.Point{10, 20}
+
+// CellPixels sets the size of a single cell of the terminal in pixels. Used
+// with the TerminalGraphics option to determine the resolution of the
+// picture and the number of cells it occupies.
+// Defaults to DefaultCellPixels.
+func CellPixels(size image.Point) Option {
+	return option(func(opts *options) {
+		opts.cellPixels = size
+	})
+}
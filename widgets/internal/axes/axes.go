@@ -18,6 +18,7 @@ package axes
 import (
 	"fmt"
 	"image"
+	"time"
 
 	"github.com/mum4k/termdash/private/runewidth"
 )
@@ -64,6 +65,19 @@ func RequiredWidth(minVal, maxVal float64) int {
 	}) + axisWidth
 }
 
+// LimitSize limits the size required by an axis and its labels, i.e. the
+// width of the Y axis or the height of the X axis, to the provided minimum
+// and maximum. A zero minimum or maximum means no limit.
+func LimitSize(req, min, max int) int {
+	if min > 0 && req < min {
+		return min
+	}
+	if max > 0 && req > max {
+		return max
+	}
+	return req
+}
+
 // YProperties are the properties of the Y axis.
 type YProperties struct {
 	// Min is the minimum value on the axis.
@@ -76,6 +90,17 @@ type YProperties struct {
 	ScaleMode YScaleMode
 	// ValueFormatter is the formatter used to format numeric values to string representation.
 	ValueFormatter func(float64) string
+	// Unit is appended to the labels, can be empty.
+	Unit string
+	// MinWidth is the minimum width of the Y axis and its labels, zero means
+	// no minimum.
+	MinWidth int
+	// MaxWidth is the maximum width of the Y axis and its labels, zero means
+	// no maximum. Labels that don't fit are trimmed.
+	MaxWidth int
+	// Right indicates that the Y axis is placed on the right side of the
+	// canvas with the labels to the right of the axis, aligned to the left.
+	Right bool
 }
 
 // NewYDetails retrieves details about the Y axis required to draw it on a
@@ -84,12 +109,15 @@ func NewYDetails(cvsAr image.Rectangle, yp *YProperties) (*YDetails, error) {
 	cvsWidth := cvsAr.Dx()
 	cvsHeight := cvsAr.Dy()
 	maxWidth := cvsWidth - 1 // Reserve one column for the line chart itself.
-	if req := RequiredWidth(yp.Min, yp.Max); maxWidth < req {
+	if req := LimitSize(RequiredWidth(yp.Min, yp.Max), yp.MinWidth, yp.MaxWidth); maxWidth < req {
 		return nil, fmt.Errorf("the available maxWidth %d is smaller than the reported required width %d", maxWidth, req)
 	}
+	if yp.MaxWidth > 0 && yp.MaxWidth < maxWidth {
+		maxWidth = yp.MaxWidth
+	}
 
 	graphHeight := cvsHeight - yp.ReqXHeight
-	scale, err := NewYScale(yp.Min, yp.Max, graphHeight, nonZeroDecimals, yp.ScaleMode, yp.ValueFormatter)
+	scale, err := NewYScale(yp.Min, yp.Max, graphHeight, nonZeroDecimals, yp.ScaleMode, withUnit(yp.ValueFormatter, yp.Unit))
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +132,7 @@ func NewYDetails(cvsAr image.Rectangle, yp *YProperties) (*YDetails, error) {
 	var width int
 	// Determine the largest label, which might be less than maxWidth.
 	// Such case would allow us to save more space for the line chart itself.
-	widest := longestLabel(labels)
+	widest := LimitSize(longestLabel(labels), yp.MinWidth-axisWidth, 0)
 	if widest < maxLabelWidth {
 		// Save the space and recalculate the labels, since they need to be realigned.
 		l, err := yLabels(scale, widest)
@@ -117,6 +145,20 @@ func NewYDetails(cvsAr image.Rectangle, yp *YProperties) (*YDetails, error) {
 		width = maxWidth
 	}
 
+	if yp.Right {
+		axisX := cvsWidth - width
+		for _, l := range labels {
+			l.Pos.X = axisX + axisWidth
+		}
+		return &YDetails{
+			Width:  width,
+			Start:  image.Point{axisX, 0},
+			End:    image.Point{axisX, graphHeight},
+			Scale:  scale,
+			Labels: labels,
+		}, nil
+	}
+
 	return &YDetails{
 		Width:  width,
 		Start:  image.Point{width - 1, 0},
@@ -176,6 +218,20 @@ type XProperties struct {
 	CustomLabels map[int]string
 	// LO is the desired orientation of labels under the X axis.
 	LO LabelOrientation
+	// MinHeight is the minimum height of the X axis and its labels, zero
+	// means no minimum.
+	MinHeight int
+	// MaxHeight is the maximum height of the X axis and its labels, zero
+	// means no maximum. Labels that don't fit are trimmed.
+	MaxHeight int
+	// TimeUnit when non-zero indicates that the values on the axis are points
+	// in time expressed as the number of TimeUnits since the Unix epoch. The
+	// labels then display the time at round intervals and CustomLabels are
+	// ignored.
+	TimeUnit time.Duration
+	// TimeLocation is the location used to display the time labels, nil means
+	// the local time.
+	TimeLocation *time.Location
 }
 
 // NewXDetails retrieves details about the X axis required to draw it on a canvas
@@ -187,7 +243,11 @@ type XProperties struct {
 func NewXDetails(cvsAr image.Rectangle, xp *XProperties) (*XDetails, error) {
 	cvsHeight := cvsAr.Dy()
 	maxHeight := cvsHeight - 1 // Reserve one row for the line chart itself.
-	reqHeight := RequiredHeight(xp.Max, xp.CustomLabels, xp.LO)
+	req := RequiredHeight(xp.Max, xp.CustomLabels, xp.LO)
+	if xp.TimeUnit > 0 {
+		req = RequiredTimeHeight(xp.LO)
+	}
+	reqHeight := LimitSize(req, xp.MinHeight, xp.MaxHeight)
 	if maxHeight < reqHeight {
 		return nil, fmt.Errorf("the available maxHeight %d is smaller than the reported required height %d", maxHeight, reqHeight)
 	}
@@ -205,7 +265,12 @@ func NewXDetails(cvsAr image.Rectangle, xp *XProperties) (*XDetails, error) {
 		xp.ReqYWidth + 1,
 		cvsAr.Dy() - reqHeight - 1,
 	}
-	labels, err := xLabels(scale, graphZero, xp.CustomLabels, xp.LO)
+	var labels []*Label
+	if xp.TimeUnit > 0 {
+		labels, err = timeLabels(scale, graphZero, xp.TimeUnit, xp.TimeLocation, xp.LO)
+	} else {
+		labels, err = xLabels(scale, graphZero, xp.CustomLabels, xp.LO)
+	}
 	if err != nil {
 		return nil, err
 	}
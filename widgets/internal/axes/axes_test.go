@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"image"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 )
@@ -196,6 +197,81 @@ func TestY(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "fails when the minimum width doesn't fit",
+			yp: &YProperties{
+				Min:        0,
+				Max:        3,
+				ReqXHeight: 2,
+				MinWidth:   5,
+			},
+			cvsAr:     image.Rect(0, 0, 5, 4),
+			wantWidth: 2,
+			wantErr:   true,
+		},
+		{
+			desc: "respects the minimum width",
+			yp: &YProperties{
+				Min:        0,
+				Max:        3,
+				ReqXHeight: 2,
+				MinWidth:   7,
+			},
+			cvsAr:     image.Rect(0, 0, 10, 4),
+			wantWidth: 2,
+			want: &YDetails{
+				Width: 7,
+				Start: image.Point{6, 0},
+				End:   image.Point{6, 2},
+				Scale: mustNewYScale(0, 3, 2, nonZeroDecimals, YScaleModeAnchored, nil),
+				Labels: []*Label{
+					{NewValue(0, nonZeroDecimals), image.Point{5, 1}},
+					{NewValue(1.72, nonZeroDecimals), image.Point{2, 0}},
+				},
+			},
+		},
+		{
+			desc: "respects the maximum width",
+			yp: &YProperties{
+				Min:        0,
+				Max:        3,
+				ReqXHeight: 2,
+				MaxWidth:   3,
+			},
+			cvsAr:     image.Rect(0, 0, 10, 4),
+			wantWidth: 2,
+			want: &YDetails{
+				Width: 3,
+				Start: image.Point{2, 0},
+				End:   image.Point{2, 2},
+				Scale: mustNewYScale(0, 3, 2, nonZeroDecimals, YScaleModeAnchored, nil),
+				Labels: []*Label{
+					{NewValue(0, nonZeroDecimals), image.Point{1, 1}},
+					{NewValue(1.72, nonZeroDecimals), image.Point{0, 0}},
+				},
+			},
+		},
+		{
+			desc: "places the axis on the right side",
+			yp: &YProperties{
+				Min:        0,
+				Max:        3,
+				ReqXHeight: 2,
+				Right:      true,
+			},
+			cvsAr:     image.Rect(0, 0, 7, 4),
+			wantWidth: 2,
+			want: &YDetails{
+				Width: 5,
+				Start: image.Point{2, 0},
+				End:   image.Point{2, 2},
+				Scale: mustNewYScale(0, 3, 2, nonZeroDecimals, YScaleModeAnchored, nil),
+				Labels: []*Label{
+					{NewValue(0, nonZeroDecimals), image.Point{3, 1}},
+					{NewValue(1.72, nonZeroDecimals), image.Point{3, 0}},
+				},
+			},
+		},
 		{
 			desc: "success for formatted labels scale",
 			yp: &YProperties{
@@ -279,6 +355,18 @@ func TestNewXDetails(t *testing.T) {
 			cvsAr:   image.Rect(0, 0, 3, 2),
 			wantErr: true,
 		},
+		{
+			desc: "fails when cvsAr isn't tall enough for vertical time labels",
+			xp: &XProperties{
+				Min:       0,
+				Max:       0,
+				ReqYWidth: 0,
+				LO:        LabelOrientationVertical,
+				TimeUnit:  time.Second,
+			},
+			cvsAr:   image.Rect(0, 0, 3, 9),
+			wantErr: true,
+		},
 		{
 			desc: "works with no data points",
 			xp: &XProperties{
@@ -304,6 +392,44 @@ func TestNewXDetails(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "respects the minimum height",
+			xp: &XProperties{
+				Min:       0,
+				Max:       0,
+				ReqYWidth: 0,
+				MinHeight: 4,
+			},
+			cvsAr: image.Rect(0, 0, 2, 6),
+			want: &XDetails{
+				Start: image.Point{0, 2},
+				End:   image.Point{1, 2},
+				Scale: mustNewXScale(0, 0, 1, nonZeroDecimals),
+				Labels: []*Label{
+					{
+						Value: NewValue(0, nonZeroDecimals),
+						Pos:   image.Point{1, 3},
+					},
+				},
+				Properties: &XProperties{
+					Min:       0,
+					Max:       0,
+					ReqYWidth: 0,
+					MinHeight: 4,
+				},
+			},
+		},
+		{
+			desc: "fails when the minimum height doesn't fit",
+			xp: &XProperties{
+				Min:       0,
+				Max:       0,
+				ReqYWidth: 0,
+				MinHeight: 4,
+			},
+			cvsAr:   image.Rect(0, 0, 2, 4),
+			wantErr: true,
+		},
 		{
 			desc: "works with no data points, vertical",
 			xp: &XProperties{
@@ -477,6 +603,49 @@ func TestNewXDetails(t *testing.T) {
 	}
 }
 
+func TestLimitSize(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  int
+		min  int
+		max  int
+		want int
+	}{
+		{
+			desc: "no limits",
+			req:  3,
+			want: 3,
+		},
+		{
+			desc: "within the limits",
+			req:  3,
+			min:  2,
+			max:  4,
+			want: 3,
+		},
+		{
+			desc: "raised to the minimum",
+			req:  3,
+			min:  5,
+			want: 5,
+		},
+		{
+			desc: "lowered to the maximum",
+			req:  3,
+			max:  2,
+			want: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := LimitSize(tc.req, tc.min, tc.max); got != tc.want {
+				t.Errorf("LimitSize(%d, %d, %d) => %d, want %d", tc.req, tc.min, tc.max, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestRequiredHeight(t *testing.T) {
 	tests := []struct {
 		desc             string
@@ -22,6 +22,7 @@ import (
 
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/canvas/braille"
 )
 
 // LabelOrientation represents the orientation of text labels.
@@ -75,6 +76,10 @@ func yLabels(scale *YScale, labelWidth int) ([]*Label, error) {
 		return nil, fmt.Errorf("cannot place labels in label area width %d, minimum is %d", labelWidth, min)
 	}
 
+	if decades := scale.LogDecades(); decades != nil {
+		return logYLabels(scale, decades, labelWidth)
+	}
+
 	var labels []*Label
 	const labelSpacing = 4
 	seen := map[string]bool{}
@@ -102,6 +107,43 @@ func yLabels(scale *YScale, labelWidth int) ([]*Label, error) {
 	return labels, nil
 }
 
+// logYLabels returns labels for a logarithmic Y scale, these are placed on the
+// rows that represent the powers of ten. Decades that would end up too close
+// to the previously placed label are skipped, the last decade is always
+// labeled.
+func logYLabels(scale *YScale, decades []float64, labelWidth int) ([]*Label, error) {
+	const minRowSpacing = 2
+	var labels []*Label
+	lastRow := -1
+	for i, d := range decades {
+		pixelY, err := scale.ValueToPixel(d)
+		if err != nil {
+			return nil, err
+		}
+		row := pixelY / braille.RowMult
+		isLast := i == len(decades)-1
+		if lastRow != -1 && lastRow-row < minRowSpacing {
+			if !isLast {
+				continue
+			}
+			// Make space for the last decade.
+			labels = labels[:len(labels)-1]
+		}
+
+		v := yScaleNewValue(d, scale.Min.NonZeroDecimals, scale.valueFormatter)
+		pos, err := alignfor.Text(rowLabelArea(row, labelWidth), v.Text(), align.HorizontalRight, align.VerticalMiddle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to align the label value: %v", err)
+		}
+		labels = append(labels, &Label{
+			Value: v,
+			Pos:   pos,
+		})
+		lastRow = row
+	}
+	return labels, nil
+}
+
 // rowLabelArea determines the area available for labels on the specified row.
 // The row is the Y coordinate of the row, Y coordinates grow down.
 func rowLabelArea(row int, labelWidth int) image.Rectangle {
@@ -29,6 +29,7 @@ func TestYLabels(t *testing.T) {
 		max         float64
 		graphHeight int
 		labelWidth  int
+		mode        YScaleMode
 		want        []*Label
 		wantErr     bool
 	}{
@@ -125,11 +126,37 @@ func TestYLabels(t *testing.T) {
 				{NewValue(4.16, nonZeroDecimals), image.Point{0, 1}},
 			},
 		},
+		{
+			desc:        "logarithmic scale places labels on powers of ten",
+			min:         1,
+			max:         1000,
+			graphHeight: 10,
+			labelWidth:  4,
+			mode:        YScaleModeLogarithmic,
+			want: []*Label{
+				{NewValue(1, nonZeroDecimals), image.Point{3, 9}},
+				{NewValue(10, nonZeroDecimals), image.Point{2, 6}},
+				{NewValue(100, nonZeroDecimals), image.Point{1, 3}},
+				{NewValue(1000, nonZeroDecimals), image.Point{0, 0}},
+			},
+		},
+		{
+			desc:        "logarithmic scale skips decades that don't fit",
+			min:         1,
+			max:         1000,
+			graphHeight: 4,
+			labelWidth:  4,
+			mode:        YScaleModeLogarithmic,
+			want: []*Label{
+				{NewValue(1, nonZeroDecimals), image.Point{3, 3}},
+				{NewValue(1000, nonZeroDecimals), image.Point{0, 0}},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			scale, err := NewYScale(tc.min, tc.max, tc.graphHeight, nonZeroDecimals, YScaleModeAnchored, nil)
+			scale, err := NewYScale(tc.min, tc.max, tc.graphHeight, nonZeroDecimals, tc.mode, nil)
 			if err != nil {
 				t.Fatalf("NewYScale => unexpected error: %v", err)
 			}
@@ -37,7 +37,8 @@ func (ysm YScaleMode) String() string {
 // yScaleModeNames maps YScaleMode values to human readable names.
 var yScaleModeNames = map[YScaleMode]string{
 	YScaleModeAnchored: "YScaleModeAnchored",
-	YScaleModeAdaptive: "YScaleModeAdaptive",
+	YScaleModeAdaptive:    "YScaleModeAdaptive",
+	YScaleModeLogarithmic: "YScaleModeLogarithmic",
 }
 
 const (
@@ -50,6 +51,11 @@ const (
 	// I.e. it starts at min for all-positive series and at max for
 	// all-negative series.
 	YScaleModeAdaptive
+
+	// YScaleModeLogarithmic is a mode where the Y scale is logarithmic with
+	// base ten. The scale starts and ends on a power of ten that encloses the
+	// min and max on the series. Only positive values can be represented.
+	YScaleModeLogarithmic
 )
 
 // YScale is the scale of the Y axis.
@@ -70,6 +76,13 @@ type YScale struct {
 	// valueFormatter is the value formatter used for the labels
 	// represented by the values on the scale.
 	valueFormatter func(float64) string
+
+	// mode is the mode the scale was created with.
+	mode YScaleMode
+	// logMin and logMax are the base ten exponents of Min and Max when the
+	// mode is YScaleModeLogarithmic. In this mode, Step is the step in the
+	// exponent between pixels.
+	logMin, logMax int
 }
 
 // String implements fmt.Stringer.
@@ -112,6 +125,9 @@ func NewYScale(min, max float64, graphHeight, nonZeroDecimals int, mode YScaleMo
 		if max < 0 && min == max {
 			max = 0
 		}
+	case YScaleModeLogarithmic:
+		return newLogYScale(min, max, graphHeight, nonZeroDecimals, valueFormatter), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported mode: %v(%d)", mode, mode)
 	}
@@ -141,6 +157,8 @@ func (ys *YScale) PixelToValue(y int) (float64, error) {
 		return ys.Min.Rounded, nil
 	case pos == ys.brailleHeight-1:
 		return ys.Max.Rounded, nil
+	case ys.mode == YScaleModeLogarithmic:
+		return math.Pow(10, float64(ys.logMin)+float64(pos)*ys.Step.Value), nil
 	default:
 
 		v := float64(pos) * ys.Step.Rounded
@@ -159,7 +177,15 @@ func (ys *YScale) PixelToValue(y int) (float64, error) {
 // most closely represents the value on the line chart according to the scale.
 // The value must be within the bounds provided to NewYScale. Y coordinates
 // grow down.
+// On a logarithmic scale, the value must be positive.
 func (ys *YScale) ValueToPixel(v float64) (int, error) {
+	if ys.mode == YScaleModeLogarithmic {
+		if v <= 0 {
+			return 0, fmt.Errorf("value %v cannot be represented on a logarithmic scale, must be positive", v)
+		}
+		pos := int(math.Round((log10(v) - float64(ys.logMin)) / ys.Step.Value))
+		return positionToY(pos, ys.brailleHeight)
+	}
 	if ys.Step.Rounded == 0 {
 		return 0, nil
 	}
@@ -175,6 +201,66 @@ func (ys *YScale) ValueToPixel(v float64) (int, error) {
 	return positionToY(pos, ys.brailleHeight)
 }
 
+// newLogYScale returns a logarithmic scale that starts and ends on the powers
+// of ten enclosing the min and max. Non-positive values cannot be represented
+// on the scale, so a non-positive min is replaced with a power of ten at or
+// below max and a non-positive max results in the scale of 1 to 10.
+func newLogYScale(min, max float64, graphHeight, nonZeroDecimals int, valueFormatter func(float64) string) *YScale {
+	if max <= 0 {
+		min, max = 1, 10
+	}
+	if min <= 0 {
+		min = math.Min(1, max)
+	}
+
+	logMin := int(math.Floor(log10(min)))
+	logMax := int(math.Ceil(log10(max)))
+	if logMax <= logMin {
+		// All values are the same power of ten, span at least one decade.
+		logMax = logMin + 1
+	}
+
+	brailleHeight := graphHeight * braille.RowMult
+	usablePixels := brailleHeight - 1 // One pixel reserved for the min value.
+	step := float64(logMax-logMin) / float64(usablePixels)
+	return &YScale{
+		Min:            yScaleNewValue(math.Pow(10, float64(logMin)), nonZeroDecimals, valueFormatter),
+		Max:            yScaleNewValue(math.Pow(10, float64(logMax)), nonZeroDecimals, valueFormatter),
+		Step:           NewValue(step, nonZeroDecimals),
+		GraphHeight:    graphHeight,
+		brailleHeight:  brailleHeight,
+		valueFormatter: valueFormatter,
+		mode:           YScaleModeLogarithmic,
+		logMin:         logMin,
+		logMax:         logMax,
+	}
+}
+
+// log10 returns the base ten logarithm of v, snapping results that are within
+// a rounding error of an integer to that integer so that exact powers of ten
+// aren't pushed into the neighbouring decade.
+func log10(v float64) float64 {
+	l := math.Log10(v)
+	if r := math.Round(l); math.Abs(l-r) < 1e-9 {
+		return r
+	}
+	return l
+}
+
+// LogDecades returns the values of the powers of ten between Min and Max
+// inclusive in an increasing order. Returns nil if the scale isn't
+// logarithmic.
+func (ys *YScale) LogDecades() []float64 {
+	if ys.mode != YScaleModeLogarithmic {
+		return nil
+	}
+	var decades []float64
+	for e := ys.logMin; e <= ys.logMax; e++ {
+		decades = append(decades, math.Pow(10, float64(e)))
+	}
+	return decades
+}
+
 // CellLabel given a Y coordinate of a cell on the canvas, determines value of
 // the label that should be next to it. The Y coordinate must be within the
 // graphHeight provided to NewYScale. Y coordinates grow down.
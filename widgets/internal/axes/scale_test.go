@@ -763,6 +763,83 @@ func TestYScale(t *testing.T) {
 				{0, NewValue(140, 2), false},
 			},
 		},
+		{
+			desc:            "logarithmic mode, min and max are powers of ten",
+			min:             1,
+			max:             1000,
+			graphHeight:     4,
+			nonZeroDecimals: 2,
+			mode:            YScaleModeLogarithmic,
+			pixelToValueTests: []pixelToValueTest{
+				{15, 1, false},
+				{10, 10, false},
+				{0, 1000, false},
+			},
+			valueToPixelTests: []valueToPixelTest{
+				{1, 15, false},
+				{10, 10, false},
+				{100, 5, false},
+				{1000, 0, false},
+				{0, 0, true},
+				{-1, 0, true},
+			},
+			cellLabelTests: []cellLabelTest{
+				{3, NewValue(1, 2), false},
+			},
+		},
+		{
+			desc:            "logarithmic mode, encloses min and max in powers of ten",
+			min:             0.05,
+			max:             500,
+			graphHeight:     4,
+			nonZeroDecimals: 2,
+			mode:            YScaleModeLogarithmic,
+			pixelToValueTests: []pixelToValueTest{
+				{15, 0.01, false},
+				{0, 1000, false},
+			},
+			valueToPixelTests: []valueToPixelTest{
+				{0.01, 15, false},
+				{1, 9, false},
+				{1000, 0, false},
+			},
+		},
+		{
+			desc:            "logarithmic mode, non-positive min is replaced",
+			min:             -10,
+			max:             500,
+			graphHeight:     4,
+			nonZeroDecimals: 2,
+			mode:            YScaleModeLogarithmic,
+			pixelToValueTests: []pixelToValueTest{
+				{15, 1, false},
+				{0, 1000, false},
+			},
+		},
+		{
+			desc:            "logarithmic mode, spans at least one decade",
+			min:             5,
+			max:             5,
+			graphHeight:     4,
+			nonZeroDecimals: 2,
+			mode:            YScaleModeLogarithmic,
+			pixelToValueTests: []pixelToValueTest{
+				{15, 1, false},
+				{0, 10, false},
+			},
+		},
+		{
+			desc:            "logarithmic mode, defaults when there are no positive values",
+			min:             -5,
+			max:             0,
+			graphHeight:     4,
+			nonZeroDecimals: 2,
+			mode:            YScaleModeLogarithmic,
+			pixelToValueTests: []pixelToValueTest{
+				{15, 1, false},
+				{0, 10, false},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,221 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package axes
+
+// time_label.go contains code that calculates labels of an X axis that
+// displays points in time.
+
+import (
+	"image"
+	"time"
+)
+
+// timeStep is an interval between two labels on a time axis.
+// Exactly one of d, days and months is set.
+type timeStep struct {
+	d      time.Duration
+	days   int
+	months int
+	// layout formats the labels, see time.Time.Format.
+	layout string
+}
+
+// approx returns the approximate duration of the step.
+func (ts timeStep) approx() time.Duration {
+	switch {
+	case ts.days > 0:
+		return time.Duration(ts.days) * 24 * time.Hour
+	case ts.months > 0:
+		return time.Duration(ts.months) * 30 * 24 * time.Hour
+	default:
+		return ts.d
+	}
+}
+
+// first returns the first point in time aligned to the step that isn't before
+// the provided time.
+func (ts timeStep) first(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch {
+	case ts.days > 0:
+		if midnight.Before(t) {
+			return midnight.AddDate(0, 0, 1)
+		}
+		return midnight
+
+	case ts.months > 0:
+		// Align to months that are multiples of the step since January.
+		m := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+		for m.Before(t) {
+			m = m.AddDate(0, ts.months, 0)
+		}
+		return m
+
+	default:
+		k := (t.Sub(midnight) + ts.d - 1) / ts.d
+		return midnight.Add(k * ts.d)
+	}
+}
+
+// next returns the point in time one step after the provided one.
+func (ts timeStep) next(t time.Time) time.Time {
+	switch {
+	case ts.days > 0:
+		return t.AddDate(0, 0, ts.days)
+	case ts.months > 0:
+		return t.AddDate(0, ts.months, 0)
+	default:
+		return t.Add(ts.d)
+	}
+}
+
+// label formats the label of the point in time. Labels of sub-day steps that
+// fall on midnight display the date instead, so that day boundaries are
+// visible.
+func (ts timeStep) label(t time.Time) string {
+	if ts.d > 0 && ts.d < 24*time.Hour && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(dayLayout)
+	}
+	return t.Format(ts.layout)
+}
+
+// Layouts of the time labels.
+const (
+	secondLayout = "15:04:05"
+	minuteLayout = "15:04"
+	dayLayout    = "Jan 02"
+	monthLayout  = "Jan 2006"
+	yearLayout   = "2006"
+)
+
+// timeSteps are the supported intervals between labels on a time axis in an
+// increasing order.
+var timeSteps = []timeStep{
+	{d: time.Second, layout: secondLayout},
+	{d: 2 * time.Second, layout: secondLayout},
+	{d: 5 * time.Second, layout: secondLayout},
+	{d: 10 * time.Second, layout: secondLayout},
+	{d: 15 * time.Second, layout: secondLayout},
+	{d: 30 * time.Second, layout: secondLayout},
+	{d: time.Minute, layout: minuteLayout},
+	{d: 2 * time.Minute, layout: minuteLayout},
+	{d: 5 * time.Minute, layout: minuteLayout},
+	{d: 10 * time.Minute, layout: minuteLayout},
+	{d: 15 * time.Minute, layout: minuteLayout},
+	{d: 30 * time.Minute, layout: minuteLayout},
+	{d: time.Hour, layout: minuteLayout},
+	{d: 2 * time.Hour, layout: minuteLayout},
+	{d: 3 * time.Hour, layout: minuteLayout},
+	{d: 6 * time.Hour, layout: minuteLayout},
+	{d: 12 * time.Hour, layout: minuteLayout},
+	{days: 1, layout: dayLayout},
+	{days: 2, layout: dayLayout},
+	{days: 7, layout: dayLayout},
+	{months: 1, layout: monthLayout},
+	{months: 3, layout: monthLayout},
+	{months: 6, layout: monthLayout},
+	{months: 12, layout: yearLayout},
+}
+
+// longestTimeLayout is the length of the longest time label.
+var longestTimeLayout = len(monthLayout)
+
+// RequiredTimeHeight calculates the minimum height required in order to draw
+// the X axis and its labels when the axis displays points in time, see
+// XProperties.TimeUnit.
+func RequiredTimeHeight(lo LabelOrientation) int {
+	if lo == LabelOrientationHorizontal {
+		return axisWidth + 1
+	}
+	return longestTimeLayout + axisWidth
+}
+
+// timeLabels returns labels that should be placed under an X axis whose
+// values are points in time expressed as the number of units since the Unix
+// epoch in the location.
+// The labels are placed at round intervals, the shortest interval is chosen
+// such that the labels don't overlap.
+// The graphZero is the (0, 0) point of the graph area on the canvas.
+// Labels are returned in an increasing value order.
+func timeLabels(scale *XScale, graphZero image.Point, unit time.Duration, loc *time.Location, lo LabelOrientation) ([]*Label, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	min, max := int(scale.Min.Value), int(scale.Max.Value)
+	toTime := func(v int) time.Time {
+		return time.Unix(0, int64(v)*int64(unit)).In(loc)
+	}
+	toValue := func(t time.Time) int {
+		return int(t.UnixNano() / int64(unit))
+	}
+
+	const minSpacing = 3
+	span := time.Duration(max-min) * unit
+	for _, ts := range timeSteps {
+		if ts.approx() < unit {
+			continue
+		}
+
+		labelLen := len(ts.layout)
+		if ts.d > 0 && ts.d < 24*time.Hour && len(dayLayout) > labelLen {
+			labelLen = len(dayLayout)
+		}
+		if lo == LabelOrientationVertical {
+			labelLen = 1
+		}
+		// Cells between two neighboring labels.
+		if span > 0 && float64(scale.GraphWidth)*float64(ts.approx())/float64(span) < float64(labelLen+minSpacing) {
+			continue
+		}
+
+		var res []*Label
+		nextFree := 0 // The first cell a label can start at.
+		for t := ts.first(toTime(min)); toValue(t) <= max; t = ts.next(t) {
+			cell, err := scale.ValueToCell(toValue(t))
+			if err != nil {
+				return nil, err
+			}
+			text := ts.label(t)
+			width := len(text)
+			if lo == LabelOrientationVertical {
+				width = 1
+			}
+			if cell < nextFree || cell+width > scale.GraphWidth {
+				continue
+			}
+			res = append(res, &Label{
+				Value: NewTextValue(text),
+				Pos:   image.Point{graphZero.X + cell, graphZero.Y + 2}, // First down is the axis, second the label.
+			})
+			nextFree = cell + width + 1
+		}
+		if len(res) > 0 {
+			return res, nil
+		}
+		break
+	}
+
+	// No round point in time is displayed, label the start of the axis.
+	text := toTime(min).Format(secondLayout)
+	if lo == LabelOrientationHorizontal && len(text) > scale.GraphWidth {
+		return nil, nil
+	}
+	return []*Label{
+		{
+			Value: NewTextValue(text),
+			Pos:   image.Point{graphZero.X, graphZero.Y + 2},
+		},
+	}, nil
+}
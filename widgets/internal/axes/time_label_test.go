@@ -0,0 +1,169 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package axes
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestTimeLabels(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc       string
+		min        time.Time
+		max        time.Time
+		unit       time.Duration
+		loc        *time.Location
+		graphWidth int
+		lo         LabelOrientation
+		// want are the labels formatted as "text@x".
+		want []string
+	}{
+		{
+			desc:       "labels an hour at quarter hour intervals",
+			min:        start,
+			max:        start.Add(time.Hour),
+			unit:       time.Second,
+			graphWidth: 40,
+			want:       []string{"10:00@0", "10:15@10", "10:30@19", "10:45@29"},
+		},
+		{
+			desc:       "labels midnight with the date",
+			min:        start.Add(12 * time.Hour),
+			max:        start.Add(26 * time.Hour),
+			unit:       time.Second,
+			graphWidth: 40,
+			want:       []string{"Jan 02@5", "06:00@22"},
+		},
+		{
+			desc:       "labels days",
+			min:        start,
+			max:        start.Add(10 * 24 * time.Hour),
+			unit:       time.Second,
+			graphWidth: 40,
+			want:       []string{"Jan 02@2", "Jan 09@30"},
+		},
+		{
+			desc:       "labels months",
+			min:        start,
+			max:        start.AddDate(1, 0, 0),
+			unit:       time.Second,
+			graphWidth: 40,
+			want:       []string{"Jul 2020@19"},
+		},
+		{
+			desc:       "labels seconds",
+			min:        start,
+			max:        start.Add(20 * time.Second),
+			unit:       time.Millisecond,
+			graphWidth: 40,
+			want:       []string{"10:00:00@0", "10:00:10@19"},
+		},
+		{
+			desc:       "labels in the provided location",
+			min:        start,
+			max:        start.Add(time.Hour),
+			unit:       time.Second,
+			loc:        time.FixedZone("UTC+2", 2*60*60),
+			graphWidth: 40,
+			want:       []string{"12:00@0", "12:15@10", "12:30@19", "12:45@29"},
+		},
+		{
+			desc:       "vertical labels are placed closer together",
+			min:        start,
+			max:        start.Add(time.Hour),
+			unit:       time.Second,
+			graphWidth: 40,
+			lo:         LabelOrientationVertical,
+			want:       []string{"10:00@0", "10:10@6", "10:20@13", "10:30@19", "10:40@26", "10:50@33", "11:00@39"},
+		},
+		{
+			desc:       "labels the start of the axis when no round time is displayed",
+			min:        start.Add(time.Second / 2),
+			max:        start.Add(time.Second / 2),
+			unit:       time.Millisecond,
+			graphWidth: 40,
+			want:       []string{"10:00:00@0"},
+		},
+		{
+			desc:       "no labels when the axis is too narrow",
+			min:        start.Add(time.Second / 2),
+			max:        start.Add(time.Second),
+			unit:       time.Millisecond,
+			graphWidth: 4,
+			want:       []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			min := int(tc.min.UnixNano() / int64(tc.unit))
+			max := int(tc.max.UnixNano() / int64(tc.unit))
+			scale, err := NewXScale(min, max, tc.graphWidth, nonZeroDecimals)
+			if err != nil {
+				t.Fatalf("NewXScale => unexpected error: %v", err)
+			}
+			loc := tc.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			labels, err := timeLabels(scale, image.Point{0, 0}, tc.unit, loc, tc.lo)
+			if err != nil {
+				t.Fatalf("timeLabels => unexpected error: %v", err)
+			}
+
+			got := []string{}
+			for _, l := range labels {
+				got = append(got, fmt.Sprintf("%s@%d", l.Value.Text(), l.Pos.X))
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("timeLabels => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequiredTimeHeight(t *testing.T) {
+	tests := []struct {
+		desc string
+		lo   LabelOrientation
+		want int
+	}{
+		{
+			desc: "horizontal labels",
+			lo:   LabelOrientationHorizontal,
+			want: 2,
+		},
+		{
+			desc: "vertical labels",
+			lo:   LabelOrientationVertical,
+			want: 9,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := RequiredTimeHeight(tc.lo); got != tc.want {
+				t.Errorf("RequiredTimeHeight => %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
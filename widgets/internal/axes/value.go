@@ -48,6 +48,21 @@ func ValueFormatter(formatter func(float64) string) ValueOption {
 	})
 }
 
+// withUnit returns a formatter that appends the unit to the values formatted
+// by the provided formatter, or formatted by the default format if the
+// formatter is nil. Returns the provided formatter if the unit is empty.
+func withUnit(formatter func(float64) string, unit string) func(float64) string {
+	if unit == "" {
+		return formatter
+	}
+	return func(v float64) string {
+		if formatter != nil {
+			return formatter(v) + unit
+		}
+		return NewValue(v, nonZeroDecimals).Text() + unit
+	}
+}
+
 // Value represents one value.
 type Value struct {
 	// Value is the original unmodified value.
@@ -0,0 +1,291 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keyvalue contains a widget that displays labeled values.
+package keyvalue
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// entry is one key and value pair displayed by the widget.
+type entry struct {
+	key   string
+	value string
+	opts  *setOptions
+}
+
+// KeyValue displays ordered pairs of keys and values, one pair per line, e.g.
+// a status panel:
+//
+//	Status : OK
+//	Uptime : 3d
+//	Version: 1.2
+//
+// The keys are aligned into a column as wide as the longest key. Values that
+// don't fit the width of the widget are truncated, or wrapped if the
+// WrapValues option is provided.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type KeyValue struct {
+	// entries are the displayed entries in the order they were added.
+	entries []*entry
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the KeyValue.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new KeyValue widget.
+func New(opts ...Option) (*KeyValue, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &KeyValue{
+		opts: opt,
+	}, nil
+}
+
+// Set sets the value of the entry with the specified key. An entry that
+// doesn't exist yet is added after all the existing entries, an existing entry
+// is updated in place. The provided options replace any options provided when
+// the entry was last set.
+func (kv *KeyValue) Set(key, value string, sOpts ...SetOption) error {
+	if key == "" {
+		return errors.New("the key cannot be empty")
+	}
+	for _, t := range []struct {
+		name string
+		text string
+	}{
+		{"key", key},
+		{"value", value},
+	} {
+		if t.text == "" {
+			continue
+		}
+		if err := wrap.ValidText(t.text); err != nil {
+			return fmt.Errorf("invalid %s %q: %v", t.name, t.text, err)
+		}
+		if strings.ContainsRune(t.text, '\n') {
+			return fmt.Errorf("invalid %s %q: newline characters aren't allowed", t.name, t.text)
+		}
+	}
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	defer kv.dirty.Mark()
+	e := &entry{
+		key:   key,
+		value: value,
+		opts:  newSetOptions(sOpts...),
+	}
+	if i := kv.find(key); i >= 0 {
+		kv.entries[i] = e
+	} else {
+		kv.entries = append(kv.entries, e)
+	}
+	return nil
+}
+
+// Remove removes the entry with the specified key.
+// Does nothing if such entry doesn't exist.
+func (kv *KeyValue) Remove(key string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	defer kv.dirty.Mark()
+	if i := kv.find(key); i >= 0 {
+		kv.entries = append(kv.entries[:i], kv.entries[i+1:]...)
+	}
+}
+
+// Reset removes all the entries.
+func (kv *KeyValue) Reset() {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	defer kv.dirty.Mark()
+	kv.entries = nil
+}
+
+// find returns the index of the entry with the key or -1 if it doesn't exist.
+func (kv *KeyValue) find(key string) int {
+	for i, e := range kv.entries {
+		if e.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Draw draws the KeyValue widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (kv *KeyValue) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	needAr, err := area.FromSize(kv.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	sepWidth := kv.opts.separatorWidth()
+	keyWidth := 0
+	for _, e := range kv.entries {
+		if w := runewidth.StringWidth(e.key); w > keyWidth {
+			keyWidth = w
+		}
+	}
+	// Leave at least one cell for the values.
+	if max := ar.Dx() - sepWidth - 1; keyWidth > max {
+		keyWidth = max
+	}
+	valueAr := image.Rect(ar.Min.X+keyWidth+sepWidth, ar.Min.Y, ar.Max.X, ar.Max.Y)
+
+	y := ar.Min.Y
+	for _, e := range kv.entries {
+		if y >= ar.Max.Y {
+			break
+		}
+		if err := kv.drawKey(cvs, e, image.Rect(ar.Min.X, y, ar.Min.X+keyWidth, y+1)); err != nil {
+			return err
+		}
+		if kv.opts.separator != "" {
+			if err := draw.Text(cvs, kv.opts.separator, image.Point{ar.Min.X + keyWidth, y}); err != nil {
+				return err
+			}
+		}
+
+		lines, err := kv.drawValue(cvs, e, image.Rect(valueAr.Min.X, y, valueAr.Max.X, valueAr.Max.Y))
+		if err != nil {
+			return err
+		}
+		y += lines
+	}
+	return nil
+}
+
+// drawKey draws the key of the entry aligned within the area.
+func (kv *KeyValue) drawKey(cvs *canvas.Canvas, e *entry, keyAr image.Rectangle) error {
+	cellOpts := kv.opts.keyCellOpts
+	if e.opts.keyCellOpts != nil {
+		cellOpts = e.opts.keyCellOpts
+	}
+	key, err := draw.Truncate(e.key, keyAr.Dx())
+	if err != nil {
+		return err
+	}
+	start, err := alignfor.Text(keyAr, key, kv.opts.keyAlign, align.VerticalTop)
+	if err != nil {
+		return err
+	}
+	return draw.Text(cvs, key, start, draw.TextMaxX(keyAr.Max.X), draw.TextCellOpts(cellOpts...))
+}
+
+// drawValue draws the value of the entry into the area, starting on its top
+// row. Returns the number of rows the value occupies.
+func (kv *KeyValue) drawValue(cvs *canvas.Canvas, e *entry, valueAr image.Rectangle) (int, error) {
+	cellOpts := kv.opts.valueCellOpts
+	if e.opts.valueCellOpts != nil {
+		cellOpts = e.opts.valueCellOpts
+	}
+	if !kv.opts.wrapValues {
+		if err := draw.TruncatedText(cvs, e.value, valueAr.Min, valueAr.Dx(), draw.TruncateCellOpts(cellOpts...)); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	lines, err := wrap.Cells(buffer.NewCells(e.value, cellOpts...), valueAr.Dx(), wrap.AtWords)
+	if err != nil {
+		return 0, err
+	}
+	if len(lines) == 0 {
+		return 1, nil
+	}
+	for i, line := range lines {
+		cur := image.Point{valueAr.Min.X, valueAr.Min.Y + i}
+		if cur.Y >= valueAr.Max.Y {
+			break
+		}
+		for _, c := range line {
+			cells, err := cvs.SetCell(cur, c.Rune, c.Opts)
+			if err != nil {
+				return 0, err
+			}
+			cur = image.Point{cur.X + cells, cur.Y}
+		}
+	}
+	return len(lines), nil
+}
+
+// minSize determines the minimum required size to draw the widget, i.e. one
+// cell for the key and one for the value.
+func (kv *KeyValue) minSize() image.Point {
+	return image.Point{kv.opts.separatorWidth() + 2, 1}
+}
+
+// Keyboard input isn't supported on the KeyValue widget.
+func (*KeyValue) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the KeyValue widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the KeyValue widget.
+func (*KeyValue) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the KeyValue widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (kv *KeyValue) Options() widgetapi.Options {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  kv.minSize(),
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (kv *KeyValue) SetDirtyFunc(markDirty func()) {
+	kv.dirty.Set(markDirty)
+}
@@ -0,0 +1,321 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// pair is a key and value pair set on the widget.
+type pair struct {
+	key   string
+	value string
+	opts  []SetOption
+}
+
+func TestKeyValue(t *testing.T) {
+	tests := []struct {
+		desc        string
+		opts        []Option
+		canvas      image.Rectangle
+		pairs       []pair
+		remove      []string
+		want        func(size image.Point) *faketerm.Terminal
+		wantNewErr  bool
+		wantSetErr  bool
+		wantDrawErr bool
+	}{
+		{
+			desc:       "fails on a separator with a newline",
+			opts:       []Option{Separator("\n")},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on an empty key",
+			canvas:     image.Rect(0, 0, 10, 3),
+			pairs:      []pair{{key: "", value: "v"}},
+			wantSetErr: true,
+		},
+		{
+			desc:       "fails on a value with a newline",
+			canvas:     image.Rect(0, 0, 10, 3),
+			pairs:      []pair{{key: "k", value: "a\nb"}},
+			wantSetErr: true,
+		},
+		{
+			desc:   "draws nothing without entries",
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws resize needed when the canvas is too small",
+			canvas: image.Rect(0, 0, 3, 1),
+			pairs:  []pair{{key: "k", value: "v"}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "aligns the keys",
+			canvas: image.Rect(0, 0, 14, 3),
+			pairs: []pair{
+				{key: "Status", value: "OK"},
+				{key: "Version", value: "1.2"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "Status", image.Point{0, 0})
+				testdraw.MustText(c, ": OK", image.Point{7, 0})
+				testdraw.MustText(c, "Version: 1.2", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "aligns keys to the right with a custom separator",
+			opts: []Option{
+				KeyAlign(align.HorizontalRight),
+				Separator(" = "),
+			},
+			canvas: image.Rect(0, 0, 14, 3),
+			pairs: []pair{
+				{key: "a", value: "1"},
+				{key: "bbb", value: "2"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a = 1", image.Point{2, 0})
+				testdraw.MustText(c, "bbb = 2", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "updates entries in place and removes entries",
+			canvas: image.Rect(0, 0, 10, 3),
+			pairs: []pair{
+				{key: "a", value: "1"},
+				{key: "b", value: "2"},
+				{key: "c", value: "3"},
+				{key: "a", value: "4"},
+			},
+			remove: []string{"b", "missing"},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a: 4", image.Point{0, 0})
+				testdraw.MustText(c, "c: 3", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "styles keys and values, entry options take precedence",
+			opts: []Option{
+				KeyCellOpts(cell.FgColor(cell.ColorRed)),
+				ValueCellOpts(cell.FgColor(cell.ColorGreen)),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			pairs: []pair{
+				{key: "a", value: "1"},
+				{key: "b", value: "2", opts: []SetOption{
+					SetKeyCellOpts(cell.FgColor(cell.ColorBlue)),
+					SetValueCellOpts(cell.FgColor(cell.ColorYellow)),
+				}},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, ": ", image.Point{1, 0})
+				testdraw.MustText(c, "1", image.Point{3, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testdraw.MustText(c, "b", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testdraw.MustText(c, ": ", image.Point{1, 1})
+				testdraw.MustText(c, "2", image.Point{3, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates long values",
+			canvas: image.Rect(0, 0, 8, 2),
+			pairs: []pair{
+				{key: "a", value: "hello world"},
+				{key: "b", value: "2"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a: hell…", image.Point{0, 0})
+				testdraw.MustText(c, "b: 2", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "wraps long values",
+			opts:   []Option{WrapValues()},
+			canvas: image.Rect(0, 0, 8, 4),
+			pairs: []pair{
+				{key: "a", value: "hello world"},
+				{key: "b", value: "2"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a: hello", image.Point{0, 0})
+				testdraw.MustText(c, "world", image.Point{3, 1})
+				testdraw.MustText(c, "b: 2", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates keys that don't leave space for values",
+			canvas: image.Rect(0, 0, 5, 1),
+			pairs: []pair{
+				{key: "status", value: "OK"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "s…: …", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws only the entries that fit",
+			canvas: image.Rect(0, 0, 10, 1),
+			pairs: []pair{
+				{key: "a", value: "1"},
+				{key: "b", value: "2"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a: 1", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			kv, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, p := range tc.pairs {
+				err := kv.Set(p.key, p.value, p.opts...)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("Set => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+			for _, k := range tc.remove {
+				kv.Remove(k)
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = kv.Draw(c, &widgetapi.Meta{})
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	kv, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := kv.Set("a", "1"); err != nil {
+		t.Fatalf("Set => unexpected error: %v", err)
+	}
+	kv.Reset()
+
+	c := testcanvas.MustNew(image.Rect(0, 0, 10, 2))
+	if err := kv.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got := faketerm.MustNew(c.Size())
+	testcanvas.MustApply(c, got)
+	if diff := faketerm.Diff(faketerm.MustNew(c.Size()), got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	kv, err := New(Separator(" = "))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := kv.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{5, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
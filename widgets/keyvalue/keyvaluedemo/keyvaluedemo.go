@@ -0,0 +1,109 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary keyvaluedemo displays a KeyValue widget with a status panel.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/keyvalue"
+)
+
+// playKeyValue periodically updates the uptime on the KeyValue widget.
+// Exits when the context expires.
+func playKeyValue(ctx context.Context, kv *keyvalue.KeyValue, delay time.Duration) {
+	start := time.Now()
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			uptime := time.Since(start).Round(time.Second)
+			if err := kv.Set("Uptime", uptime.String()); err != nil {
+				panic(err)
+			}
+
+			status, color := "OK", cell.ColorGreen
+			if int(uptime.Seconds())%10 >= 7 {
+				status, color = "DEGRADED", cell.ColorYellow
+			}
+			if err := kv.Set("Status", status, keyvalue.SetValueCellOpts(cell.FgColor(color))); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	kv, err := keyvalue.New(
+		keyvalue.KeyCellOpts(cell.Bold()),
+		keyvalue.WrapValues(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range []struct {
+		key   string
+		value string
+	}{
+		{"Status", "OK"},
+		{"Uptime", "0s"},
+		{"Version", "1.2"},
+		{"Description", "A long value that gets wrapped when it doesn't fit the width of the widget."},
+	} {
+		if err := kv.Set(e.key, e.value); err != nil {
+			panic(err)
+		}
+	}
+	go playKeyValue(ctx, kv, 1*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(kv),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
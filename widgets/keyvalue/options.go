@@ -0,0 +1,128 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+// options.go contains configurable options for KeyValue.
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	keyCellOpts   []cell.Option
+	valueCellOpts []cell.Option
+	separator     string
+	keyAlign      align.Horizontal
+	wrapValues    bool
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		separator: DefaultSeparator,
+		keyAlign:  DefaultKeyAlign,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.separator == "" {
+		return nil
+	}
+	if err := wrap.ValidText(o.separator); err != nil {
+		return fmt.Errorf("invalid Separator %q: %v", o.separator, err)
+	}
+	if strings.ContainsRune(o.separator, '\n') {
+		return fmt.Errorf("invalid Separator %q: newline characters aren't allowed", o.separator)
+	}
+	return nil
+}
+
+// separatorWidth returns the width of the separator in cells.
+func (o *options) separatorWidth() int {
+	return runewidth.StringWidth(o.separator)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// KeyCellOpts sets the cell options on the cells that contain the keys.
+// Can be overridden for individual entries by the SetKeyCellOpts option.
+func KeyCellOpts(opts ...cell.Option) Option {
+	return option(func(o *options) {
+		o.keyCellOpts = opts
+	})
+}
+
+// ValueCellOpts sets the cell options on the cells that contain the values.
+// Can be overridden for individual entries by the SetValueCellOpts option.
+func ValueCellOpts(opts ...cell.Option) Option {
+	return option(func(o *options) {
+		o.valueCellOpts = opts
+	})
+}
+
+// DefaultSeparator is the default value for the Separator option.
+const DefaultSeparator = ": "
+
+// Separator sets the text displayed between the keys and the values.
+// Can be empty.
+// Defaults to DefaultSeparator.
+func Separator(s string) Option {
+	return option(func(o *options) {
+		o.separator = s
+	})
+}
+
+// DefaultKeyAlign is the default value for the KeyAlign option.
+const DefaultKeyAlign = align.HorizontalLeft
+
+// KeyAlign sets the horizontal alignment of the keys within the column that
+// holds the keys. The column is as wide as the longest key.
+// Defaults to DefaultKeyAlign.
+func KeyAlign(h align.Horizontal) Option {
+	return option(func(o *options) {
+		o.keyAlign = h
+	})
+}
+
+// WrapValues configures the widget to wrap values that don't fit the width
+// of the widget at word boundaries. The continuation lines are aligned with
+// the start of the value.
+// Defaults to truncating such values and marking the truncation with an
+// ellipsis.
+func WrapValues() Option {
+	return option(func(o *options) {
+		o.wrapValues = true
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+// set_options.go contains options used when setting entries of the KeyValue
+// widget.
+
+import (
+	"github.com/mum4k/termdash/cell"
+)
+
+// SetOption is used to provide options to Set().
+type SetOption interface {
+	// set sets the provided option.
+	set(*setOptions)
+}
+
+// setOptions stores the provided options.
+type setOptions struct {
+	keyCellOpts   []cell.Option
+	valueCellOpts []cell.Option
+}
+
+// newSetOptions returns new setOptions instance.
+func newSetOptions(sOpts ...SetOption) *setOptions {
+	so := &setOptions{}
+	for _, o := range sOpts {
+		o.set(so)
+	}
+	return so
+}
+
+// setOption implements SetOption.
+type setOption func(*setOptions)
+
+// set implements SetOption.set.
+func (so setOption) set(sOpts *setOptions) {
+	so(sOpts)
+}
+
+// SetKeyCellOpts sets the cell options on the cells that contain the key of
+// this entry. Overrides the KeyCellOpts option.
+func SetKeyCellOpts(opts ...cell.Option) SetOption {
+	return setOption(func(sOpts *setOptions) {
+		sOpts.keyCellOpts = opts
+	})
+}
+
+// SetValueCellOpts sets the cell options on the cells that contain the value
+// of this entry. Overrides the ValueCellOpts option.
+func SetValueCellOpts(opts ...cell.Option) SetOption {
+	return setOption(func(sOpts *setOptions) {
+		sOpts.valueCellOpts = opts
+	})
+}
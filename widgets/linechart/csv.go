@@ -0,0 +1,134 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linechart
+
+// csv.go contains code that exports the series as CSV.
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// CSVOption is used to provide options to WriteCSV.
+type CSVOption interface {
+	// set sets the provided option.
+	set(*csvOptions)
+}
+
+// csvOptions stores the provided CSV options.
+type csvOptions struct {
+	visibleOnly   bool
+	excludeHidden bool
+}
+
+// csvOption implements CSVOption.
+type csvOption func(*csvOptions)
+
+// set implements CSVOption.set.
+func (o csvOption) set(opts *csvOptions) {
+	o(opts)
+}
+
+// CSVVisibleOnly limits the exported rows to the X values that were visible
+// on the LineChart the last time it was drawn, e.g. when the LineChart is
+// zoomed or the XAxisUnscaled option hides some of the values.
+// All the values are exported if the LineChart wasn't drawn yet.
+func CSVVisibleOnly() CSVOption {
+	return csvOption(func(opts *csvOptions) {
+		opts.visibleOnly = true
+	})
+}
+
+// CSVExcludeHidden excludes the series hidden by SetSeriesVisible from the
+// export.
+func CSVExcludeHidden() CSVOption {
+	return csvOption(func(opts *csvOptions) {
+		opts.excludeHidden = true
+	})
+}
+
+// WriteCSV writes the values of the series as CSV into the provided writer.
+//
+// The first row is a header, the first column contains the X values and each
+// of the following columns the values of one series, ordered by the labels of
+// the series. If custom X labels were provided via the SeriesXLabels option,
+// they are included in a column following the X values. Values that are
+// missing or NaN are written as empty fields.
+func (lc *LineChart) WriteCSV(w io.Writer, opts ...CSVOption) error {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+
+	o := &csvOptions{}
+	for _, opt := range opts {
+		opt.set(o)
+	}
+
+	var names []string
+	xSet := map[int]bool{}
+	for name, sv := range lc.series {
+		if o.excludeHidden && lc.hidden[name] {
+			continue
+		}
+		names = append(names, name)
+		for i := range sv.values {
+			xSet[sv.x(i)] = true
+		}
+	}
+	sort.Strings(names)
+
+	var xs []int
+	for x := range xSet {
+		if o.visibleOnly && lc.lastXD != nil {
+			if x < int(lc.lastXD.Scale.Min.Value) || x > int(lc.lastXD.Scale.Max.Value) {
+				continue
+			}
+		}
+		xs = append(xs, x)
+	}
+	sort.Ints(xs)
+
+	withLabels := len(lc.xLabels) > 0
+	header := []string{"x"}
+	if withLabels {
+		header = append(header, "x label")
+	}
+	header = append(header, names...)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, x := range xs {
+		row := []string{strconv.Itoa(x)}
+		if withLabels {
+			row = append(row, lc.xLabels[x])
+		}
+		for _, name := range names {
+			var field string
+			if v, ok := lc.series[name].valueAt(x); ok && !math.IsNaN(v) {
+				field = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+			row = append(row, field)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
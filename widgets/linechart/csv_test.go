@@ -0,0 +1,178 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linechart
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestWriteCSV(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		writes func(*LineChart) error
+		// canvas if not empty, the LineChart gets drawn on a canvas of this
+		// size before the export.
+		canvas  image.Rectangle
+		csvOpts []CSVOption
+		want    string
+	}{
+		{
+			desc: "only the header without series",
+			want: "x\n",
+		},
+		{
+			desc: "exports all series ordered by label",
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("second", []float64{1.5, 2}); err != nil {
+					return err
+				}
+				return lc.Series("first", []float64{0, math.NaN(), 3})
+			},
+			want: "x,first,second\n" +
+				"0,0,1.5\n" +
+				"1,,2\n" +
+				"2,3,\n",
+		},
+		{
+			desc: "exports series with explicit X coordinates",
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{1, 2}, SeriesXValues([]int{3, 10})); err != nil {
+					return err
+				}
+				return lc.Series("second", []float64{5, 6}, SeriesXValues([]int{4, 10}))
+			},
+			want: "x,first,second\n" +
+				"3,1,\n" +
+				"4,,5\n" +
+				"10,2,6\n",
+		},
+		{
+			desc: "includes custom X labels",
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 1}, SeriesXLabels(map[int]string{
+					0: "zero",
+				}))
+			},
+			want: "x,x label,first\n" +
+				"0,zero,0\n" +
+				"1,,1\n",
+		},
+		{
+			desc: "includes hidden series by default",
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{0, 1}); err != nil {
+					return err
+				}
+				if err := lc.Series("second", []float64{2, 3}); err != nil {
+					return err
+				}
+				return lc.SetSeriesVisible("second", false)
+			},
+			want: "x,first,second\n" +
+				"0,0,2\n" +
+				"1,1,3\n",
+		},
+		{
+			desc: "excludes hidden series",
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{0, 1}); err != nil {
+					return err
+				}
+				if err := lc.Series("second", []float64{2, 3, 4}); err != nil {
+					return err
+				}
+				return lc.SetSeriesVisible("second", false)
+			},
+			csvOpts: []CSVOption{
+				CSVExcludeHidden(),
+			},
+			want: "x,first\n" +
+				"0,0\n" +
+				"1,1\n",
+		},
+		{
+			desc: "visible only exports all values when not drawn",
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 1, 2})
+			},
+			csvOpts: []CSVOption{
+				CSVVisibleOnly(),
+			},
+			want: "x,first\n" +
+				"0,0\n" +
+				"1,1\n" +
+				"2,2\n",
+		},
+		{
+			desc: "visible only exports values that fit an unscaled X axis",
+			opts: []Option{
+				XAxisUnscaled(),
+			},
+			writes: func(lc *LineChart) error {
+				var values []float64
+				for i := 0; i < 10; i++ {
+					values = append(values, float64(i))
+				}
+				return lc.Series("first", values)
+			},
+			canvas: image.Rect(0, 0, 4, 4),
+			csvOpts: []CSVOption{
+				CSVVisibleOnly(),
+			},
+			want: "x,first\n" +
+				"8,8\n" +
+				"9,9\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			lc, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if tc.writes != nil {
+				if err := tc.writes(lc); err != nil {
+					t.Fatalf("writes => unexpected error: %v", err)
+				}
+			}
+			if !tc.canvas.Empty() {
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := lc.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			var b bytes.Buffer
+			if err := lc.WriteCSV(&b, tc.csvOpts...); err != nil {
+				t.Fatalf("WriteCSV => unexpected error: %v", err)
+			}
+			if diff := pretty.Compare(tc.want, b.String()); diff != "" {
+				t.Errorf("WriteCSV => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linechart
+
+// cursor_group.go contains code that synchronizes the X cursor among line charts.
+
+import "sync"
+
+// CursorGroup links multiple LineChart instances so that they share the
+// position of the X cursor. When the user clicks into the graph of any of the
+// linked line charts, all of them draw the cursor at the same value on the X
+// axis. This is useful when comparing multiple metrics that share the X axis,
+// e.g. the time.
+//
+// Line charts join the group by providing the XCursorGroup option to New.
+// This object is thread-safe.
+type CursorGroup struct {
+	// mu protects the CursorGroup.
+	mu sync.Mutex
+
+	// x is the position of the cursor on the X axis.
+	x int
+	// set indicates whether the cursor is set.
+	set bool
+
+	// charts are the linked line charts.
+	charts []*LineChart
+}
+
+// NewCursorGroup returns a new empty CursorGroup.
+func NewCursorGroup() *CursorGroup {
+	return &CursorGroup{}
+}
+
+// Set moves the cursor to the specified value on the X axis, i.e. to the
+// position of a value in the series. The cursor is only drawn on line charts
+// whose X axis currently displays that value.
+func (cg *CursorGroup) Set(x int) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.x = x
+	cg.set = true
+	cg.markDirty()
+}
+
+// Clear removes the cursor from all the linked line charts.
+func (cg *CursorGroup) Clear() {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.x = 0
+	cg.set = false
+	cg.markDirty()
+}
+
+// X returns the position of the cursor on the X axis and a bool indicating if
+// the cursor is set.
+func (cg *CursorGroup) X() (int, bool) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	return cg.x, cg.set
+}
+
+// join links the line chart to the group.
+func (cg *CursorGroup) join(lc *LineChart) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.charts = append(cg.charts, lc)
+}
+
+// markDirty reports a change of the content of all the linked line charts.
+// The caller must hold cg.mu.
+func (cg *CursorGroup) markDirty() {
+	for _, lc := range cg.charts {
+		lc.dirty.Mark()
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linechart
+
+// hline.go contains code that draws horizontal reference lines.
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/widgets/internal/axes"
+)
+
+// hLine is a horizontal reference line.
+type hLine struct {
+	// y is the value on the Y axis the line is drawn at.
+	y float64
+	// label is drawn at the right edge of the graph, can be empty.
+	label string
+	// cellOpts are the cell options for the line and its label.
+	cellOpts []cell.Option
+}
+
+// AddHLine adds a horizontal reference line at the specified value on the Y
+// axis, e.g. to mark a threshold. The line spans the width of the graph and
+// the optional label is drawn above the line at the right edge of the graph.
+// The provided cell options apply to both the line and the label.
+//
+// The lines don't affect the scale of the Y axis, a line whose value falls
+// outside of the displayed range isn't drawn. Use the YAxisCustomScale option
+// to ensure the value is always displayed.
+func (lc *LineChart) AddHLine(y float64, label string, opts ...cell.Option) error {
+	if math.IsNaN(y) || math.IsInf(y, 0) {
+		return fmt.Errorf("invalid Y value %v for a horizontal line, must be a finite number", y)
+	}
+	if label != "" {
+		if err := wrap.ValidText(label); err != nil {
+			return fmt.Errorf("invalid label %q for a horizontal line: %v", label, err)
+		}
+		if strings.ContainsRune(label, '\n') {
+			return fmt.Errorf("invalid label %q for a horizontal line: newline characters aren't allowed", label)
+		}
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	defer lc.dirty.Mark()
+	lc.hLines = append(lc.hLines, &hLine{
+		y:        y,
+		label:    label,
+		cellOpts: opts,
+	})
+	return nil
+}
+
+// ClearHLines removes all the horizontal lines added by AddHLine.
+func (lc *LineChart) ClearHLines() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	defer lc.dirty.Mark()
+	lc.hLines = nil
+}
+
+// drawHLines draws the horizontal lines onto the braille canvas.
+func (lc *LineChart) drawHLines(bc *braille.Canvas, yd *axes.YDetails) error {
+	ar := bc.Area()
+	for _, hl := range lc.hLines {
+		y, ok, err := hLinePixel(hl, yd)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := draw.BrailleLine(bc,
+			image.Point{ar.Min.X, y},
+			image.Point{ar.Max.X - 1, y},
+			draw.BrailleLineCellOpts(hl.cellOpts...),
+		); err != nil {
+			return fmt.Errorf("draw.BrailleLine => %v", err)
+		}
+	}
+	return nil
+}
+
+// drawHLineLabels draws the labels of the horizontal lines onto the canvas.
+// Each label is right-aligned within the graph area on the row above the
+// line, or on the row of the line if it is on the top row of the graph.
+func (lc *LineChart) drawHLineLabels(cvs *canvas.Canvas, graphAr image.Rectangle, yd *axes.YDetails) error {
+	for _, hl := range lc.hLines {
+		if hl.label == "" {
+			continue
+		}
+		y, ok, err := hLinePixel(hl, yd)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		row := graphAr.Min.Y + y/braille.RowMult - 1
+		if row < graphAr.Min.Y {
+			row = graphAr.Min.Y
+		}
+		x := graphAr.Max.X - runewidth.StringWidth(hl.label)
+		if x < graphAr.Min.X {
+			x = graphAr.Min.X
+		}
+		if err := draw.Text(cvs, hl.label, image.Point{x, row},
+			draw.TextMaxX(graphAr.Max.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(hl.cellOpts...),
+		); err != nil {
+			return fmt.Errorf("failed to draw the horizontal line label: %v", err)
+		}
+	}
+	return nil
+}
+
+// hLinePixel returns the Y coordinate of the pixel on the braille canvas that
+// represents the horizontal line. Returns false if the line falls outside of
+// the displayed range.
+func hLinePixel(hl *hLine, yd *axes.YDetails) (int, bool, error) {
+	if hl.y < yd.Scale.Min.Value || hl.y > yd.Scale.Max.Value {
+		return 0, false, nil
+	}
+	y, err := yd.Scale.ValueToPixel(hl.y)
+	if err != nil {
+		return 0, false, fmt.Errorf("failure for horizontal line at %v on scale %v, yd.Scale.ValueToPixel => %v", hl.y, yd.Scale, err)
+	}
+	return y, true, nil
+}
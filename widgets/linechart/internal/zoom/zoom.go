@@ -20,11 +20,12 @@ import (
 	"image"
 	"reflect"
 
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/button"
 	"github.com/mum4k/termdash/private/numbers"
 	"github.com/mum4k/termdash/terminal/terminalapi"
-	"github.com/mum4k/termdash/widgets/linechart/internal/axes"
+	"github.com/mum4k/termdash/widgets/internal/axes"
 )
 
 // Option is used to provide options.
@@ -68,7 +69,9 @@ func (o option) set(opts *options) {
 // DefaultScrollStep is the default value for the ScrollStep option.
 const DefaultScrollStep = 10
 
-// ScrollStep sets the amount of zoom in or out on a single mouse scroll event.
+// ScrollStep sets the amount of zoom in or out on a single mouse scroll event
+// or key press. This is also the amount of values the zoomed X axis pans by on
+// a single key press.
 // This is set as a percentage of the current value size of the X axis.
 // Must be a value in range 0 < value <= 100.
 // Defaults to DefaultScrollStep.
@@ -224,6 +227,90 @@ func (t *Tracker) Mouse(m *terminalapi.Mouse) error {
 	return nil
 }
 
+// Keyboard is used to forward keyboard events to the zoom tracker.
+// The left and right arrows pan the zoomed X axis, '+' or '=' zoom in, '-'
+// zooms out and '0' resets the zoom. Other keys are ignored.
+func (t *Tracker) Keyboard(k *terminalapi.Keyboard) error {
+	switch k.Key {
+	case keyboard.KeyArrowLeft:
+		return t.pan(-1)
+	case keyboard.KeyArrowRight:
+		return t.pan(1)
+	case '+', '=':
+		return t.zoomToKey(1)
+	case '-':
+		return t.zoomToKey(-1)
+	case '0':
+		t.zoomX = nil
+	}
+	return nil
+}
+
+// pan moves the zoomed X axis left if the direction is negative or right if
+// it is positive, without moving past the values of the base X axis.
+// Does nothing if zoom isn't applied.
+func (t *Tracker) pan(direction int) error {
+	if t.zoomX == nil {
+		return nil
+	}
+	currMin := int(t.zoomX.Scale.Min.Value)
+	currMax := int(t.zoomX.Scale.Max.Value)
+	baseMin := int(t.baseX.Scale.Min.Value)
+	baseMax := int(t.baseX.Scale.Max.Value)
+
+	_, step := numbers.MinMaxInts([]int{1, (currMax - currMin) * t.opts.scrollStepPerc / 100})
+	shift := direction * step
+	if currMin+shift < baseMin {
+		shift = baseMin - currMin
+	}
+	if currMax+shift > baseMax {
+		shift = baseMax - currMax
+	}
+	if shift == 0 {
+		return nil
+	}
+
+	zoom, err := newZoomedFromBase(currMin+shift, currMax+shift, t.zoomX, t.cvsAr)
+	if err != nil {
+		return err
+	}
+	t.zoomX = zoom
+	return nil
+}
+
+// zoomToKey zooms the current X axis in if the direction is positive or out
+// if it is negative, keeping the middle of the displayed values in place.
+// Doesn't zoom out above the base X axis view.
+func (t *Tracker) zoomToKey(direction int) error {
+	curr := t.baseForZoom()
+	currMin := int(curr.Scale.Min.Value)
+	currMax := int(curr.Scale.Max.Value)
+
+	// The step is relative to the displayed values, so that each key press
+	// zooms by the same ratio.
+	_, step := numbers.MinMaxInts([]int{2, (currMax - currMin) * t.opts.scrollStepPerc / 100})
+	newMin := currMin + direction*step/2
+	newMax := currMax - direction*(step-step/2)
+	if direction > 0 && newMax-newMin < 1 {
+		// Can't zoom in any further.
+		return nil
+	}
+
+	min, max := normalize(t.baseX.Scale.Min, t.baseX.Scale.Max, newMin, newMax, nil)
+	if hasMinMax(min, max, t.baseX) {
+		// Fully unzoom.
+		t.zoomX = nil
+		return nil
+	}
+
+	zoom, err := newZoomedFromBase(min, max, curr, t.cvsAr)
+	if err != nil {
+		return err
+	}
+	t.zoomX = zoom
+	return nil
+}
+
 // Range represents a range of values.
 // The range includes all values x such that Start <= x < End.
 type Range struct {
@@ -19,9 +19,10 @@ import (
 	"testing"
 
 	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/terminal/terminalapi"
-	"github.com/mum4k/termdash/widgets/linechart/internal/axes"
+	"github.com/mum4k/termdash/widgets/internal/axes"
 )
 
 // mustNewXDetails creates the XDetails or panics.
@@ -2026,3 +2027,105 @@ func TestZoomToScroll(t *testing.T) {
 		})
 	}
 }
+
+func TestTrackerKeyboard(t *testing.T) {
+	tests := []struct {
+		desc    string
+		keys    []keyboard.Key
+		wantMin int
+		wantMax int
+	}{
+		{
+			desc:    "no zoom without key presses",
+			wantMin: 0,
+			wantMax: 100,
+		},
+		{
+			desc:    "ignores unknown keys",
+			keys:    []keyboard.Key{'a', keyboard.KeyEnter},
+			wantMin: 0,
+			wantMax: 100,
+		},
+		{
+			desc:    "zooms in around the middle",
+			keys:    []keyboard.Key{'+'},
+			wantMin: 5,
+			wantMax: 95,
+		},
+		{
+			desc:    "zooms in relative to the displayed values",
+			keys:    []keyboard.Key{'+', '='},
+			wantMin: 9,
+			wantMax: 90,
+		},
+		{
+			desc:    "zooms out",
+			keys:    []keyboard.Key{'+', '+', '-'},
+			wantMin: 5,
+			wantMax: 94,
+		},
+		{
+			desc:    "doesn't zoom out above the base axis",
+			keys:    []keyboard.Key{'-'},
+			wantMin: 0,
+			wantMax: 100,
+		},
+		{
+			desc:    "resets the zoom",
+			keys:    []keyboard.Key{'+', '+', '0'},
+			wantMin: 0,
+			wantMax: 100,
+		},
+		{
+			desc:    "doesn't pan without zoom",
+			keys:    []keyboard.Key{keyboard.KeyArrowLeft, keyboard.KeyArrowRight},
+			wantMin: 0,
+			wantMax: 100,
+		},
+		{
+			desc:    "pans right",
+			keys:    []keyboard.Key{'+', '+', keyboard.KeyArrowRight},
+			wantMin: 17,
+			wantMax: 98,
+		},
+		{
+			desc:    "doesn't pan past the end of the base axis",
+			keys:    []keyboard.Key{'+', keyboard.KeyArrowRight, keyboard.KeyArrowRight},
+			wantMin: 10,
+			wantMax: 100,
+		},
+		{
+			desc:    "doesn't pan past the start of the base axis",
+			keys:    []keyboard.Key{'+', keyboard.KeyArrowLeft},
+			wantMin: 0,
+			wantMax: 90,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cvsAr := image.Rect(0, 0, 30, 10)
+			graphAr := image.Rect(3, 0, 30, 8)
+			base := mustNewXDetails(cvsAr, &axes.XProperties{
+				Min:       0,
+				Max:       100,
+				ReqYWidth: 2,
+			})
+			tr, err := New(base, cvsAr, graphAr)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			for _, k := range tc.keys {
+				if err := tr.Keyboard(&terminalapi.Keyboard{Key: k}); err != nil {
+					t.Fatalf("Keyboard(%v) => unexpected error: %v", k, err)
+				}
+			}
+
+			got := tr.Zoom()
+			if gotMin, gotMax := int(got.Scale.Min.Value), int(got.Scale.Max.Value); gotMin != tc.wantMin || gotMax != tc.wantMax {
+				t.Errorf("Zoom => min:%d max:%d, want min:%d max:%d", gotMin, gotMax, tc.wantMin, tc.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package linechart
+
+// legend.go contains code that draws the legend listing the series.
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// LegendPosition determines where the legend is drawn.
+type LegendPosition int
+
+// String implements fmt.Stringer()
+func (lp LegendPosition) String() string {
+	if n, ok := legendPositionNames[lp]; ok {
+		return n
+	}
+	return "LegendPositionUnknown"
+}
+
+// legendPositionNames maps LegendPosition values to human readable names.
+var legendPositionNames = map[LegendPosition]string{
+	LegendBelow:    "LegendBelow",
+	LegendTopLeft:  "LegendTopLeft",
+	LegendTopRight: "LegendTopRight",
+}
+
+const (
+	// LegendBelow draws the legend on a row below the X axis and its labels.
+	// The series are listed next to each other.
+	LegendBelow LegendPosition = iota
+
+	// LegendTopLeft draws the legend inside the graph in its top left
+	// corner. Each series is listed on its own row.
+	LegendTopLeft
+
+	// LegendTopRight draws the legend inside the graph in its top right
+	// corner. Each series is listed on its own row.
+	LegendTopRight
+)
+
+// legendMarker is the rune drawn in front of the name of each series.
+const legendMarker = '■'
+
+// legendSpacing is the number of cells between the series when the legend is
+// drawn below the chart.
+const legendSpacing = 2
+
+// legendEntry is one series listed in the legend.
+type legendEntry struct {
+	name string
+	sv   *seriesValues
+}
+
+// text returns the text of the entry including the marker.
+func (le *legendEntry) text() string {
+	return fmt.Sprintf("%c %s", legendMarker, le.name)
+}
+
+// legendEntries returns the visible series sorted by their names.
+func (lc *LineChart) legendEntries() []*legendEntry {
+	var entries []*legendEntry
+	for name, sv := range lc.series {
+		if lc.hidden[name] {
+			continue
+		}
+		entries = append(entries, &legendEntry{name: name, sv: sv})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// legendHeight returns the number of rows reserved for the legend below the
+// chart.
+func (lc *LineChart) legendHeight() int {
+	if lc.opts.legend && lc.opts.legendPosition == LegendBelow {
+		return 1
+	}
+	return 0
+}
+
+// drawLegend draws the legend onto the canvas. The graphAr is the area of the
+// graph and legendAr the row reserved for a legend below the chart.
+func (lc *LineChart) drawLegend(cvs *canvas.Canvas, graphAr, legendAr image.Rectangle) error {
+	if !lc.opts.legend {
+		return nil
+	}
+
+	entries := lc.legendEntries()
+	if lc.opts.legendPosition == LegendBelow {
+		x := graphAr.Min.X
+		for _, e := range entries {
+			if x >= legendAr.Max.X {
+				break
+			}
+			if err := lc.drawLegendEntry(cvs, e, image.Point{x, legendAr.Min.Y}, legendAr.Max.X); err != nil {
+				return err
+			}
+			x += runewidth.StringWidth(e.text()) + legendSpacing
+		}
+		return nil
+	}
+
+	var width int
+	for _, e := range entries {
+		if w := runewidth.StringWidth(e.text()); w > width {
+			width = w
+		}
+	}
+	if width > graphAr.Dx() {
+		width = graphAr.Dx()
+	}
+
+	x := graphAr.Min.X
+	if lc.opts.legendPosition == LegendTopRight {
+		x = graphAr.Max.X - width
+	}
+	// Clear the area under the legend so that the series don't obscure it.
+	height := len(entries)
+	if height > graphAr.Dy() {
+		height = graphAr.Dy()
+	}
+	if err := cvs.SetAreaCells(image.Rect(x, graphAr.Min.Y, x+width, graphAr.Min.Y+height), ' '); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		y := graphAr.Min.Y + i
+		if y >= graphAr.Max.Y {
+			break
+		}
+		if err := lc.drawLegendEntry(cvs, e, image.Point{x, y}, x+width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawLegendEntry draws one entry of the legend starting at the point, the
+// text is trimmed at maxX.
+func (lc *LineChart) drawLegendEntry(cvs *canvas.Canvas, e *legendEntry, start image.Point, maxX int) error {
+	if err := draw.Text(cvs, e.text(), start,
+		draw.TextMaxX(maxX),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextCellOpts(e.sv.seriesCellOpts...),
+	); err != nil {
+		return fmt.Errorf("failed to draw the legend for series %q: %v", e.name, err)
+	}
+	return nil
+}
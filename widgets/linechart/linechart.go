@@ -22,16 +22,20 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/numbers"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
-	"github.com/mum4k/termdash/widgets/linechart/internal/axes"
+	"github.com/mum4k/termdash/widgets/internal/axes"
 	"github.com/mum4k/termdash/widgets/linechart/internal/zoom"
 )
 
@@ -45,6 +49,18 @@ type seriesValues struct {
 	max float64
 
 	seriesCellOpts []cell.Option
+	// xValues are the explicit X coordinates of the values provided via the
+	// SeriesXValues option, nil if the values are positioned by their index.
+	xValues []int
+	// timed indicates that the values were provided via TimeSeries and the
+	// xValues are points in time.
+	timed bool
+	// rightYAxis indicates that the series is plotted against the right Y
+	// axis, see SeriesRightYAxis.
+	rightYAxis bool
+	// fill indicates that the area under the series is filled, see
+	// SeriesFill.
+	fill bool
 	// The custom labels provided on a call to Series and a bool indicating if
 	// the labels were provided. This allows resetting them to nil.
 	xLabelsSet bool
@@ -80,6 +96,10 @@ func newSeriesValues(values []float64) *seriesValues {
 // highlighting an area on the graph (left mouse clicking and dragging) or by
 // using the mouse scroll button.
 //
+// LineChart also supports keyboard based zoom when focused, which is useful
+// when mouse events aren't available. The '+' and '-' keys zoom in and out,
+// the left and right arrows pan the zoomed X axis and '0' resets the zoom.
+//
 // Implements widgetapi.Widget. This object is thread-safe.
 type LineChart struct {
 	// mu protects the LineChart widget.
@@ -91,6 +111,10 @@ type LineChart struct {
 
 	// yMin are the min and max values for the Y axis.
 	yMin, yMax float64
+	// yRightMin and yRightMax are the min and max values for the right Y
+	// axis. hasRightY indicates if any visible series uses the right Y axis.
+	yRightMin, yRightMax float64
+	hasRightY            bool
 
 	// capacity is the last observed value capacity in pixels when Draw was
 	// called.
@@ -102,8 +126,29 @@ type LineChart struct {
 	// xLabels that were provided on a call to Series.
 	xLabels map[int]string
 
+	// hidden are names of series that aren't drawn, updated by calling
+	// SetSeriesVisible.
+	hidden map[string]bool
+
 	// zoom tracks the zooming of the X axis.
 	zoom *zoom.Tracker
+
+	// lastGraphAr is the area of the graph and lastXD the details of the
+	// displayed X axis as observed on the last call to Draw.
+	// Used to translate mouse events into positions of the X cursor.
+	lastGraphAr image.Rectangle
+	lastXD      *axes.XDetails
+
+	// hLines are the horizontal reference lines added by AddHLine.
+	hLines []*hLine
+
+	// yAxisWidth is the widest the Y axis with its labels has been so far.
+	// Only tracked when the width of the Y axis is kept stable, see the
+	// YLabelFormatter option.
+	yAxisWidth int
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
 }
 
 // New returns a new line chart widget.
@@ -112,10 +157,15 @@ func New(opts ...Option) (*LineChart, error) {
 	if err := opt.validate(); err != nil {
 		return nil, err
 	}
-	return &LineChart{
+	lc := &LineChart{
 		series: map[string]*seriesValues{},
+		hidden: map[string]bool{},
 		opts:   opt,
-	}, nil
+	}
+	if opt.cursorGroup != nil {
+		opt.cursorGroup.join(lc)
+	}
+	return lc, nil
 }
 
 // SeriesOption is used to provide options to Series.
@@ -160,20 +210,117 @@ func SeriesXLabels(labels map[int]string) SeriesOption {
 	})
 }
 
-// yMinMax determines the min and max values for the Y axis.
-func (lc *LineChart) yMinMax() (float64, float64) {
+// SeriesXValues positions the values of the series at the provided X
+// coordinates instead of at their indexes. This allows plotting points with
+// irregular spacing on the X axis, e.g. events identified by timestamps.
+// The X coordinate of the value at index i is xValues[i]. The slice must have
+// the same length as the values, its elements must be zero or positive and
+// sorted in strictly increasing order.
+//
+// If all the visible series provide their X coordinates, the X axis starts at
+// the lowest of them, otherwise it starts at zero. Custom labels provided via
+// SeriesXLabels are keyed by the X coordinates.
+func SeriesXValues(xValues []int) SeriesOption {
+	return seriesOption(func(opts *seriesValues) {
+		// Copy to avoid external modifications. See #174.
+		opts.xValues = make([]int, len(xValues))
+		copy(opts.xValues, xValues)
+	})
+}
+
+// SeriesRightYAxis plots the series against a secondary Y axis on the right
+// side of the LineChart. The right Y axis has its own scale determined from the
+// series plotted against it, which allows combining series with very
+// different ranges of values in one LineChart. The right Y axis is only drawn
+// when at least one visible series uses it.
+// The YAxisCustomScale option and horizontal lines added by AddHLine only
+// apply to the left Y axis.
+func SeriesRightYAxis() SeriesOption {
+	return seriesOption(func(opts *seriesValues) {
+		opts.rightYAxis = true
+	})
+}
+
+// SeriesFill fills the area between the series and the X axis, i.e. the
+// value zero or the bottom of the graph if zero isn't displayed, which turns
+// the LineChart into an area chart.
+// A stacked area chart can be drawn by providing series with cumulative
+// values. Where series share a cell, the last drawn series sets the cell
+// options and series are drawn in alphabetical order based on their name, so
+// the series with the largest values should be named to be drawn first.
+func SeriesFill() SeriesOption {
+	return seriesOption(func(opts *seriesValues) {
+		opts.fill = true
+	})
+}
+
+// x returns the X coordinate of the value at the specified index.
+func (sv *seriesValues) x(i int) int {
+	if sv.xValues != nil {
+		return sv.xValues[i]
+	}
+	return i
+}
+
+// valueAt returns the value at the specified X coordinate. Returns false if
+// the series doesn't have a value there.
+func (sv *seriesValues) valueAt(x int) (float64, bool) {
+	i := x
+	if sv.xValues != nil {
+		i = sort.SearchInts(sv.xValues, x)
+		if i >= len(sv.xValues) || sv.xValues[i] != x {
+			return 0, false
+		}
+	}
+	if i < 0 || i >= len(sv.values) {
+		return 0, false
+	}
+	return sv.values[i], true
+}
+
+// validateXValues validates the X coordinates provided via SeriesXValues.
+func (sv *seriesValues) validateXValues() error {
+	if sv.xValues == nil {
+		return nil
+	}
+	if got, want := len(sv.xValues), len(sv.values); got != want {
+		return fmt.Errorf("invalid SeriesXValues, got %d X coordinates for %d values, must be equal", got, want)
+	}
+	for i, x := range sv.xValues {
+		if x < 0 {
+			return fmt.Errorf("invalid SeriesXValues, X coordinate[%d] %d must be zero or positive", i, x)
+		}
+		if i > 0 && x <= sv.xValues[i-1] {
+			return fmt.Errorf("invalid SeriesXValues, X coordinate[%d] %d must be greater than the previous one %d", i, x, sv.xValues[i-1])
+		}
+	}
+	return nil
+}
+
+// yMinMax determines the min and max values for the left or the right Y
+// axis.
+func (lc *LineChart) yMinMax(right bool) (float64, float64) {
 	var (
 		minimums []float64
 		maximums []float64
 	)
-	for _, sv := range lc.series {
-		minimums = append(minimums, sv.min)
+	for name, sv := range lc.series {
+		if lc.hidden[name] || sv.rightYAxis != right {
+			continue
+		}
+		min := sv.min
+		if lc.opts.yAxisLogarithmic {
+			min = minPositive(sv.values)
+		}
+		minimums = append(minimums, min)
 		maximums = append(maximums, sv.max)
 	}
 
-	if lc.opts.yAxisCustomScale != nil {
-		minimums = append(minimums, lc.opts.yAxisCustomScale.min)
-		maximums = append(maximums, lc.opts.yAxisCustomScale.max)
+	if cs := lc.opts.yAxisCustomScale; cs != nil && !right {
+		if !lc.opts.yAxisLogarithmic || cs.min > 0 {
+			minimums = append(minimums, cs.min)
+		}
+		maximums = append(maximums, cs.max)
 	}
 
 	min, _ := minMax(minimums)
@@ -208,11 +355,74 @@ func (lc *LineChart) Series(label string, values []float64, opts ...SeriesOption
 
 	lc.mu.Lock()
 	defer lc.mu.Unlock()
+	defer lc.dirty.Mark()
 
 	series := newSeriesValues(values)
 	for _, opt := range opts {
 		opt.set(series)
 	}
+	return lc.setSeries(label, series)
+}
+
+// TimePoint is a value observed at a point in time.
+type TimePoint struct {
+	// Time is when the value was observed.
+	Time time.Time
+	// Value is the observed value, math.NaN represents a missing value.
+	Value float64
+}
+
+// TimeSeries sets the points that should be displayed as the line chart with
+// the provided label. The points are positioned on the X axis by their time
+// and the labels of the X axis display the time at round intervals, e.g.
+// "15:04" or "Jan 02" depending on the displayed duration, see also the
+// XAxisTimeUnit and XAxisTimeLocation options.
+//
+// The points must be sorted by time, at least one XAxisTimeUnit apart and
+// can't be before the Unix epoch. The SeriesXValues and SeriesXLabels options
+// don't apply to time series.
+// The X axis displays time labels if any of the visible series is a time
+// series, so time series shouldn't be combined with series provided via
+// Series.
+// Subsequent calls with the same label replace any previously provided values.
+func (lc *LineChart) TimeSeries(label string, points []TimePoint, opts ...SeriesOption) error {
+	if label == "" {
+		return errors.New("the label cannot be empty")
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	defer lc.dirty.Mark()
+
+	values := make([]float64, len(points))
+	xValues := make([]int, len(points))
+	unit := int64(lc.opts.xAxisTimeUnit)
+	for i, p := range points {
+		x := p.Time.UnixNano() / unit
+		if x < 0 {
+			return fmt.Errorf("invalid TimePoint[%d] at %v, cannot be before the Unix epoch", i, p.Time)
+		}
+		if i > 0 && int(x) <= xValues[i-1] {
+			return fmt.Errorf("invalid TimePoint[%d] at %v, must be at least %v after the previous point at %v", i, p.Time, lc.opts.xAxisTimeUnit, points[i-1].Time)
+		}
+		values[i] = p.Value
+		xValues[i] = int(x)
+	}
+
+	series := newSeriesValues(values)
+	for _, opt := range opts {
+		opt.set(series)
+	}
+	series.xValues = xValues
+	series.timed = true
+	series.xLabelsSet = false
+	series.xLabels = nil
+	return lc.setSeries(label, series)
+}
+
+// setSeries validates and stores the series with the provided label.
+// lc.mu must be held when calling this method.
+func (lc *LineChart) setSeries(label string, series *seriesValues) error {
 	if series.xLabelsSet {
 		for i, t := range series.xLabels {
 			if i < 0 {
@@ -224,25 +434,84 @@ func (lc *LineChart) Series(label string, values []float64, opts ...SeriesOption
 		}
 		lc.xLabels = series.xLabels
 	}
+	if err := series.validateXValues(); err != nil {
+		return err
+	}
 
 	lc.series[label] = series
-	yMin, yMax := lc.yMinMax()
-	lc.yMin = yMin
-	lc.yMax = yMax
+	lc.updateYMinMax()
+	return nil
+}
+
+// updateYMinMax recalculates the min and max values for the Y axes.
+// lc.mu must be held when calling this method.
+func (lc *LineChart) updateYMinMax() {
+	lc.yMin, lc.yMax = lc.yMinMax(false)
+	lc.yRightMin, lc.yRightMax = lc.yMinMax(true)
+
+	lc.hasRightY = false
+	for name, sv := range lc.series {
+		if sv.rightYAxis && !lc.hidden[name] {
+			lc.hasRightY = true
+			break
+		}
+	}
+}
+
+// SetSeriesVisible hides or shows the series with the provided label.
+// Hidden series aren't drawn and don't affect the scale of the axes, but their
+// values are retained so that they can be shown again. The visibility is
+// retained when the values of the series are replaced by calling Series and
+// can be set even before the series is provided. All series are visible by
+// default.
+func (lc *LineChart) SetSeriesVisible(label string, visible bool) error {
+	if label == "" {
+		return errors.New("the label cannot be empty")
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	defer lc.dirty.Mark()
+
+	if visible {
+		delete(lc.hidden, label)
+	} else {
+		lc.hidden[label] = true
+	}
+	lc.updateYMinMax()
 	return nil
 }
 
+// timeAxis determines if the X axis displays points in time, which is when
+// any of the visible series is a time series.
+// lc.mu must be held when calling this method.
+func (lc *LineChart) timeAxis() bool {
+	for name, sv := range lc.series {
+		if !lc.hidden[name] && sv.timed {
+			return true
+		}
+	}
+	return false
+}
+
 // xDetails returns the details for the X axis given the specified minimum and
-// maximum value to display.
-func (lc *LineChart) xDetails(cvs *canvas.Canvas, reqYWidth, min, max int) (*axes.XDetails, error) {
+// maximum value to display. The ar is the area available to the X axis, i.e.
+// the canvas without the right Y axis.
+func (lc *LineChart) xDetails(ar image.Rectangle, reqYWidth, min, max int) (*axes.XDetails, error) {
 	xp := &axes.XProperties{
 		Min:          min,
 		Max:          max,
 		ReqYWidth:    reqYWidth,
 		CustomLabels: lc.xLabels,
 		LO:           lc.opts.xLabelOrientation,
+		MinHeight:    lc.opts.xAxisMinHeight,
+		MaxHeight:    lc.opts.xAxisMaxHeight,
+	}
+	if lc.timeAxis() {
+		xp.TimeUnit = lc.opts.xAxisTimeUnit
+		xp.TimeLocation = lc.opts.xAxisTimeLocation
 	}
-	xd, err := axes.NewXDetails(cvs.Area(), xp)
+	xd, err := axes.NewXDetails(ar, xp)
 	if err != nil {
 		return nil, fmt.Errorf("NewXDetails => %v", err)
 	}
@@ -264,35 +533,101 @@ func (lc *LineChart) xDetailsForCap(cvs *canvas.Canvas, bc *braille.Canvas, xd *
 	diff := values - lc.capacity
 	xMin := int(xd.Scale.Min.Value) + diff
 	xMax := int(xd.Scale.Max.Value)
-	unscaledXD, err := lc.xDetails(cvs, yd.Start.X, xMin, xMax)
+	unscaledXD, err := lc.xDetails(lc.xAxisAr(cvs, xd), yd.Start.X, xMin, xMax)
 	if err != nil {
 		return nil, err
 	}
 	return unscaledXD, nil
 }
 
-// axesDetails determines the details about the X and Y axes.
-func (lc *LineChart) axesDetails(cvs *canvas.Canvas) (*axes.XDetails, *axes.YDetails, error) {
-	reqXHeight := axes.RequiredHeight(lc.maxXValue(), lc.xLabels, lc.opts.xLabelOrientation)
+// yAxisMinWidth returns the minimum width of the Y axis and its labels. When
+// the width is kept stable, the Y axis doesn't get narrower than it has been
+// so far, unless the canvas or the YAxisWidth option don't leave enough space.
+func (lc *LineChart) yAxisMinWidth(cvs *canvas.Canvas) int {
+	min := lc.opts.yAxisMinWidth
+	if !lc.opts.yAxisStableWidth || lc.yAxisWidth <= min {
+		return min
+	}
+
+	min = lc.yAxisWidth
+	if max := lc.opts.yAxisMaxWidth; max > 0 && min > max {
+		min = max
+	}
+	// Reserve one column for the line chart itself.
+	if max := cvs.Area().Dx() - 1; min > max {
+		min = max
+	}
+	return min
+}
+
+// xAxisAr returns the area available to the X axis with the provided
+// details.
+func (lc *LineChart) xAxisAr(cvs *canvas.Canvas, xd *axes.XDetails) image.Rectangle {
+	ar := cvs.Area()
+	ar.Max.X = xd.End.X + 1
+	return ar
+}
+
+// rightYDetails returns the details about the right Y axis or nil if no
+// visible series uses it.
+func (lc *LineChart) rightYDetails(cvs *canvas.Canvas) (*axes.YDetails, error) {
+	if !lc.hasRightY {
+		return nil, nil
+	}
+	yp := &axes.YProperties{
+		Min:            lc.yRightMin,
+		Max:            lc.yRightMax,
+		ReqXHeight:     lc.reqXHeight(),
+		ScaleMode:      lc.yScaleMode(),
+		ValueFormatter: lc.opts.yAxisValueFormatter,
+		Unit:           lc.opts.yAxisRightUnit,
+		MaxWidth:       lc.opts.yAxisMaxWidth,
+		Right:          true,
+	}
+	ryd, err := axes.NewYDetails(cvs.Area(), yp)
+	if err != nil {
+		return nil, fmt.Errorf("NewYDetails for the right Y axis => %v", err)
+	}
+	return ryd, nil
+}
+
+// axesDetails determines the details about the X and the Y axes. The
+// returned details of the right Y axis are nil if no visible series uses it.
+func (lc *LineChart) axesDetails(cvs *canvas.Canvas) (*axes.XDetails, *axes.YDetails, *axes.YDetails, error) {
+	ryd, err := lc.rightYDetails(cvs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ar := cvs.Area()
+	if ryd != nil {
+		ar.Max.X -= ryd.Width
+	}
+
 	yp := &axes.YProperties{
 		Min:            lc.yMin,
 		Max:            lc.yMax,
-		ReqXHeight:     reqXHeight,
-		ScaleMode:      lc.opts.yAxisMode,
+		ReqXHeight:     lc.reqXHeight(),
+		ScaleMode:      lc.yScaleMode(),
 		ValueFormatter: lc.opts.yAxisValueFormatter,
+		Unit:           lc.opts.yAxisUnit,
+		MinWidth:       lc.yAxisMinWidth(cvs),
+		MaxWidth:       lc.opts.yAxisMaxWidth,
 	}
-	yd, err := axes.NewYDetails(cvs.Area(), yp)
+	yd, err := axes.NewYDetails(ar, yp)
 	if err != nil {
-		return nil, nil, fmt.Errorf("NewYDetails => %v", err)
+		return nil, nil, nil, fmt.Errorf("NewYDetails => %v", err)
+	}
+	if lc.opts.yAxisStableWidth && yd.Width > lc.yAxisWidth {
+		lc.yAxisWidth = yd.Width
 	}
 
-	const xMin = 0
+	xMin := lc.minXValue()
 	xMax := lc.maxXValue()
-	xd, err := lc.xDetails(cvs, yd.Start.X, xMin, xMax)
+	xd, err := lc.xDetails(ar, yd.Start.X, xMin, xMax)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	return xd, yd, nil
+	return xd, yd, ryd, nil
 }
 
 // Draw draws the values as line charts.
@@ -309,25 +644,68 @@ func (lc *LineChart) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return draw.ResizeNeeded(cvs)
 	}
 
-	xd, yd, err := lc.axesDetails(cvs)
+	chartCvs := cvs
+	var legendAr image.Rectangle
+	if h := lc.legendHeight(); h > 0 {
+		chartAr := cvs.Area()
+		chartAr.Max.Y -= h
+		legendAr = image.Rect(chartAr.Min.X, chartAr.Max.Y, chartAr.Max.X, cvs.Area().Max.Y)
+		c, err := canvas.New(chartAr)
+		if err != nil {
+			return err
+		}
+		chartCvs = c
+	}
+
+	xd, yd, ryd, err := lc.axesDetails(chartCvs)
 	if err != nil {
 		return err
 	}
 
-	adjXD, err := lc.drawSeries(cvs, xd, yd)
+	adjXD, err := lc.drawSeries(chartCvs, xd, yd, ryd)
 	if err != nil {
 		return err
 	}
-	return lc.drawAxes(cvs, adjXD, yd)
+	var th *theme.Theme
+	if meta != nil {
+		th = meta.Theme
+	}
+	if err := lc.drawAxes(chartCvs, adjXD, yd, ryd, th); err != nil {
+		return err
+	}
+	if chartCvs != cvs {
+		if err := chartCvs.CopyTo(cvs); err != nil {
+			return err
+		}
+	}
+	return lc.drawLegend(cvs, lc.lastGraphAr, legendAr)
 }
 
 // drawAxes draws the X,Y axes and their labels.
-func (lc *LineChart) drawAxes(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YDetails) error {
+func (lc *LineChart) drawAxes(cvs *canvas.Canvas, xd *axes.XDetails, yd, ryd *axes.YDetails, th *theme.Theme) error {
+	axesCellOpts := lc.opts.axesCellOpts
+	xLabelCellOpts := lc.opts.xLabelCellOpts
+	yLabelCellOpts := lc.opts.yLabelCellOpts
+	if th != nil {
+		// The cell options provided by the user take precedence over the
+		// theme, since the last cell option wins.
+		axesCellOpts = append([]cell.Option{cell.FgColor(th.Axis)}, axesCellOpts...)
+		xLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, xLabelCellOpts...)
+		yLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, yLabelCellOpts...)
+	}
+
 	lines := []draw.HVLine{
 		{Start: yd.Start, End: yd.End},
 		{Start: xd.Start, End: xd.End},
 	}
-	if err := draw.HVLines(cvs, lines, draw.HVLineCellOpts(lc.opts.axesCellOpts...)); err != nil {
+	if ryd != nil {
+		lines = append(lines,
+			draw.HVLine{Start: ryd.Start, End: ryd.End},
+			// Connect the X axis to the right Y axis.
+			draw.HVLine{Start: xd.End, End: ryd.End},
+		)
+	}
+	if err := draw.HVLines(cvs, lines, draw.HVLineCellOpts(axesCellOpts...)); err != nil {
 		return fmt.Errorf("failed to draw the axes: %v", err)
 	}
 
@@ -335,22 +713,32 @@ func (lc *LineChart) drawAxes(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YD
 		if err := draw.Text(cvs, l.Value.Text(), l.Pos,
 			draw.TextMaxX(yd.Start.X),
 			draw.TextOverrunMode(draw.OverrunModeThreeDot),
-			draw.TextCellOpts(lc.opts.yLabelCellOpts...),
+			draw.TextCellOpts(yLabelCellOpts...),
 		); err != nil {
 			return fmt.Errorf("failed to draw the Y labels: %v", err)
 		}
 	}
+	if ryd != nil {
+		for _, l := range ryd.Labels {
+			if err := draw.Text(cvs, l.Value.Text(), l.Pos,
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextCellOpts(yLabelCellOpts...),
+			); err != nil {
+				return fmt.Errorf("failed to draw the right Y labels: %v", err)
+			}
+		}
+	}
 
 	for _, l := range xd.Labels {
 		switch lc.opts.xLabelOrientation {
 		case axes.LabelOrientationHorizontal:
-			if err := draw.Text(cvs, l.Value.Text(), l.Pos, draw.TextCellOpts(lc.opts.xLabelCellOpts...)); err != nil {
+			if err := draw.Text(cvs, l.Value.Text(), l.Pos, draw.TextCellOpts(xLabelCellOpts...)); err != nil {
 				return fmt.Errorf("failed to draw the X horizontal labels: %v", err)
 			}
 
 		case axes.LabelOrientationVertical:
 			if err := draw.VerticalText(cvs, l.Value.Text(), l.Pos,
-				draw.VerticalTextCellOpts(lc.opts.xLabelCellOpts...),
+				draw.VerticalTextCellOpts(xLabelCellOpts...),
 				draw.VerticalTextOverrunMode(draw.OverrunModeThreeDot),
 			); err != nil {
 				return fmt.Errorf("failed to draw the vertical X labels: %v", err)
@@ -363,14 +751,14 @@ func (lc *LineChart) drawAxes(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YD
 // graphAr returns the area available for the graph itself sized so that it
 // fits between the axes and the canvas borders.
 func (lc *LineChart) graphAr(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YDetails) image.Rectangle {
-	return image.Rect(yd.Start.X+1, yd.Start.Y, cvs.Area().Max.X, xd.End.Y)
+	return image.Rect(yd.Start.X+1, yd.Start.Y, xd.End.X+1, xd.End.Y)
 }
 
 // drawSeries draws the graph representing the stored series.
 // Returns XDetails that might be adjusted to not start at zero value if some
 // of the series didn't fit the graphs and XAxisUnscaled was provided.
 // If the series has NaN values they will be ignored and not draw on the graph.
-func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YDetails) (*axes.XDetails, error) {
+func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd, ryd *axes.YDetails) (*axes.XDetails, error) {
 	graphAr := lc.graphAr(cvs, xd, yd)
 	bc, err := braille.New(graphAr)
 	if err != nil {
@@ -397,12 +785,22 @@ func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.
 	xdZoomed := lc.zoom.Zoom()
 	var names []string
 	for name := range lc.series {
+		if lc.hidden[name] {
+			continue
+		}
 		names = append(names, name)
 	}
 	sort.Strings(names)
 
+	if err := lc.drawHLines(bc, yd); err != nil {
+		return nil, err
+	}
 	for _, name := range names {
 		sv := lc.series[name]
+		syd := yd
+		if sv.rightYAxis && ryd != nil {
+			syd = ryd
+		}
 		// Skip over series that don't have at least two points since we can't
 		// draw a line for just one point.
 		// Skip over series that fall under the minimum value on the X axis.
@@ -414,13 +812,18 @@ func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.
 		for i := 1; i < len(sv.values); i++ {
 			v := sv.values[i]
 			prev = sv.values[i-1]
+			prevX, x := sv.x(i-1), sv.x(i)
 
 			// Skip the values that are missing.
 			if math.IsNaN(v) || math.IsNaN(prev) {
 				continue
 			}
+			// Non-positive values cannot be represented on a logarithmic axis.
+			if lc.opts.yAxisLogarithmic && (v <= 0 || prev <= 0) {
+				continue
+			}
 
-			if i < int(xdZoomed.Scale.Min.Value)+1 || i > int(xdZoomed.Scale.Max.Value) {
+			if prevX < int(xdZoomed.Scale.Min.Value) || x > int(xdZoomed.Scale.Max.Value) {
 				// Don't draw lines for values that aren't supposed to be visible.
 				// These are either values outside of the current zoom or
 				// values at the beginning of a series that falls before athe
@@ -429,25 +832,30 @@ func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.
 				continue
 			}
 
-			startX, err := xdZoomed.Scale.ValueToPixel(i - 1)
+			startX, err := xdZoomed.Scale.ValueToPixel(prevX)
 			if err != nil {
-				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, xdZoomed.Scale.ValueToPixel(%v) => %v", name, i-1, xdZoomed.Scale, i-1, err)
+				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, xdZoomed.Scale.ValueToPixel(%v) => %v", name, i-1, xdZoomed.Scale, prevX, err)
 			}
-			endX, err := xdZoomed.Scale.ValueToPixel(i)
+			endX, err := xdZoomed.Scale.ValueToPixel(x)
 			if err != nil {
-				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, xdZoomed.Scale.ValueToPixel(%v) => %v", name, i, xdZoomed.Scale, i, err)
+				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, xdZoomed.Scale.ValueToPixel(%v) => %v", name, i, xdZoomed.Scale, x, err)
 			}
 
-			startY, err := yd.Scale.ValueToPixel(prev)
+			startY, err := syd.Scale.ValueToPixel(prev)
 			if err != nil {
-				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, yd.Scale.ValueToPixel(%v) => %v", name, i-1, yd.Scale, prev, err)
+				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, syd.Scale.ValueToPixel(%v) => %v", name, i-1, syd.Scale, prev, err)
 			}
 
-			endY, err := yd.Scale.ValueToPixel(v)
+			endY, err := syd.Scale.ValueToPixel(v)
 			if err != nil {
-				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, yd.Scale.ValueToPixel(%v) => %v", name, i, yd.Scale, v, err)
+				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, syd.Scale.ValueToPixel(%v) => %v", name, i, syd.Scale, v, err)
 			}
 
+			if sv.fill {
+				if err := fillUnder(bc, image.Point{startX, startY}, image.Point{endX, endY}, fillBaseY(bc, syd), sv.seriesCellOpts); err != nil {
+					return nil, fmt.Errorf("failure to fill series %v[%d] => %v", name, i, err)
+				}
+			}
 			if err := draw.BrailleLine(bc,
 				image.Point{startX, startY},
 				image.Point{endX, endY},
@@ -463,13 +871,85 @@ func (lc *LineChart) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.
 			return nil, err
 		}
 	}
+	if err := lc.drawCursor(bc, xdZoomed); err != nil {
+		return nil, err
+	}
+	lc.lastGraphAr = graphAr
+	lc.lastXD = xdZoomed
 
 	if err := bc.CopyTo(cvs); err != nil {
 		return nil, fmt.Errorf("bc.Apply => %v", err)
 	}
+	if lc.opts.plot == PlotBlock {
+		if err := toBlocks(cvs, graphAr); err != nil {
+			return nil, err
+		}
+	}
+	if err := lc.drawHLineLabels(cvs, graphAr, yd); err != nil {
+		return nil, err
+	}
 	return xdZoomed, nil
 }
 
+// fillBaseY returns the Y coordinate of the pixel the area under the series
+// is filled to. This is the pixel representing the value zero or the edge of
+// the graph closest to it if zero isn't displayed.
+func fillBaseY(bc *braille.Canvas, yd *axes.YDetails) int {
+	bottom := bc.Area().Dy() - 1
+	switch {
+	case yd.Scale.Min.Value >= 0:
+		return bottom
+	case yd.Scale.Max.Value <= 0:
+		return 0
+	}
+	y, err := yd.Scale.ValueToPixel(0)
+	if err != nil {
+		return bottom
+	}
+	return y
+}
+
+// fillUnder sets all the pixels between the line from start to end and the
+// horizontal line at baseY.
+func fillUnder(bc *braille.Canvas, start, end image.Point, baseY int, cellOpts []cell.Option) error {
+	for x := start.X; x <= end.X; x++ {
+		y := start.Y
+		if dx := end.X - start.X; dx > 0 {
+			y = start.Y + int(math.Round(float64((x-start.X)*(end.Y-start.Y))/float64(dx)))
+		}
+		from, to := y, baseY
+		if from > to {
+			from, to = to, from
+		}
+		for py := from; py <= to; py++ {
+			if err := bc.SetPixel(image.Point{x, py}, cellOpts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toBlocks replaces the braille pattern characters in the area of the canvas
+// with half block characters.
+func toBlocks(cvs *canvas.Canvas, ar image.Rectangle) error {
+	for col := ar.Min.X; col < ar.Max.X; col++ {
+		for row := ar.Min.Y; row < ar.Max.Y; row++ {
+			p := image.Point{col, row}
+			c, err := cvs.Cell(p)
+			if err != nil {
+				return err
+			}
+			if r := braille.ToHalfBlock(c.Rune); r != c.Rune {
+				if _, err := cvs.SetCell(p, r, c.Opts); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // highlightRange highlights the range of X columns on the braille canvas.
 func (lc *LineChart) highlightRange(bc *braille.Canvas, hRange *zoom.Range) error {
 	cellAr := bc.CellArea()
@@ -477,9 +957,60 @@ func (lc *LineChart) highlightRange(bc *braille.Canvas, hRange *zoom.Range) erro
 	return bc.SetAreaCellOpts(ar, cell.BgColor(lc.opts.zoomHightlightColor))
 }
 
+// drawCursor highlights the column that represents the X cursor shared with
+// the CursorGroup. Does nothing if the cursor isn't set or if its value isn't
+// currently displayed.
+func (lc *LineChart) drawCursor(bc *braille.Canvas, xd *axes.XDetails) error {
+	if lc.opts.cursorGroup == nil {
+		return nil
+	}
+	x, ok := lc.opts.cursorGroup.X()
+	if !ok {
+		return nil
+	}
+	if fx := float64(x); fx < xd.Scale.Min.Value || fx > xd.Scale.Max.Rounded {
+		return nil
+	}
+
+	col, err := xd.Scale.ValueToCell(x)
+	if err != nil {
+		return fmt.Errorf("xd.Scale.ValueToCell => %v", err)
+	}
+	cellAr := bc.CellArea()
+	ar := image.Rect(col, cellAr.Min.Y, col+1, cellAr.Max.Y)
+	return bc.SetAreaCellOpts(ar, cell.BgColor(lc.opts.cursorColor))
+}
+
+// cursorMouse moves or clears the X cursor shared with the CursorGroup in
+// response to mouse events that fall onto the graph.
+func (lc *LineChart) cursorMouse(m *terminalapi.Mouse) error {
+	if lc.opts.cursorGroup == nil || lc.lastXD == nil || !m.Position.In(lc.lastGraphAr) {
+		return nil
+	}
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		v, err := lc.lastXD.Scale.CellLabel(m.Position.X - lc.lastGraphAr.Min.X)
+		if err != nil {
+			return fmt.Errorf("lc.lastXD.Scale.CellLabel => %v", err)
+		}
+		lc.opts.cursorGroup.Set(int(v.Value))
+
+	case mouse.ButtonRight:
+		lc.opts.cursorGroup.Clear()
+	}
+	return nil
+}
+
 // Keyboard implements widgetapi.Widget.Keyboard.
 func (lc *LineChart) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
-	return errors.New("the LineChart widget doesn't support keyboard events")
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.zoom == nil {
+		return nil
+	}
+	return lc.zoom.Keyboard(k)
 }
 
 // Mouse implements widgetapi.Widget.Mouse.
@@ -490,6 +1021,9 @@ func (lc *LineChart) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) erro
 	if lc.zoom == nil {
 		return nil
 	}
+	if err := lc.cursorMouse(m); err != nil {
+		return err
+	}
 	return lc.zoom.Mouse(m)
 }
 
@@ -498,39 +1032,104 @@ func (lc *LineChart) minSize() image.Point {
 	// At the very least we need:
 	// - n cells width for the Y axis and its labels as reported by it.
 	// - at least 1 cell width for the graph.
-	reqWidth := axes.RequiredWidth(lc.yMin, lc.yMax) + 1
+	reqWidth := axes.LimitSize(axes.RequiredWidth(lc.yMin, lc.yMax), lc.opts.yAxisMinWidth, lc.opts.yAxisMaxWidth) + 1
+	// - n cells width for the right Y axis and its labels if it is used.
+	if lc.hasRightY {
+		reqWidth += axes.LimitSize(axes.RequiredWidth(lc.yRightMin, lc.yRightMax), 0, lc.opts.yAxisMaxWidth)
+	}
 
 	// And for the height:
 	// - n cells width for the X axis and its labels as reported by it.
 	// - at least 2 cell height for the graph.
-	reqHeight := axes.RequiredHeight(lc.maxXValue(), lc.xLabels, lc.opts.xLabelOrientation) + 2
+	reqHeight := lc.reqXHeight() + 2
+	// - n rows for the legend if it is drawn below the chart.
+	reqHeight += lc.legendHeight()
 	return image.Point{reqWidth, reqHeight}
 }
 
+// reqXHeight determines the height required for the X axis and its labels
+// within the limits set by the XAxisHeight option.
+func (lc *LineChart) reqXHeight() int {
+	req := axes.RequiredHeight(lc.maxXValue(), lc.xLabels, lc.opts.xLabelOrientation)
+	if lc.timeAxis() {
+		req = axes.RequiredTimeHeight(lc.opts.xLabelOrientation)
+	}
+	return axes.LimitSize(req, lc.opts.xAxisMinHeight, lc.opts.xAxisMaxHeight)
+}
+
 // Options implements widgetapi.Widget.Options.
 func (lc *LineChart) Options() widgetapi.Options {
 	lc.mu.RLock()
 	defer lc.mu.RUnlock()
 
 	return widgetapi.Options{
-		MinimumSize: lc.minSize(),
-		WantMouse:   widgetapi.MouseScopeGlobal,
+		MinimumSize:  lc.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeGlobal,
 	}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (lc *LineChart) SetDirtyFunc(markDirty func()) {
+	lc.dirty.Set(markDirty)
+}
+
 // maxXValue returns the maximum value on the X axis among all the series.
 // lc.mu must be held when calling this method.
 func (lc *LineChart) maxXValue() int {
-	maxLen := 0
-	for _, sv := range lc.series {
-		if l := len(sv.values); l > maxLen {
-			maxLen = l
+	max := 0
+	for name, sv := range lc.series {
+		if lc.hidden[name] || len(sv.values) == 0 {
+			continue
+		}
+		if x := sv.x(len(sv.values) - 1); x > max {
+			max = x
 		}
 	}
-	if maxLen == 0 {
+	return max
+}
+
+// minXValue returns the minimum value on the X axis. This is the lowest X
+// coordinate among all the series if all of them provided their X
+// coordinates via SeriesXValues, zero otherwise.
+// lc.mu must be held when calling this method.
+func (lc *LineChart) minXValue() int {
+	min := -1
+	for name, sv := range lc.series {
+		if lc.hidden[name] || len(sv.values) == 0 {
+			continue
+		}
+		if sv.xValues == nil {
+			return 0
+		}
+		if x := sv.x(0); min == -1 || x < min {
+			min = x
+		}
+	}
+	if min == -1 {
 		return 0
 	}
-	return maxLen - 1
+	return min
+}
+
+// yScaleMode returns the mode of the Y axis scale.
+func (lc *LineChart) yScaleMode() axes.YScaleMode {
+	if lc.opts.yAxisLogarithmic {
+		return axes.YScaleModeLogarithmic
+	}
+	return lc.opts.yAxisMode
+}
+
+// minPositive returns the smallest positive value or NaN if there are no
+// positive values.
+func minPositive(values []float64) float64 {
+	min := math.NaN()
+	for _, v := range values {
+		if v > 0 && (math.IsNaN(min) || v < min) {
+			min = v
+		}
+	}
+	return min
 }
 
 // minMax is a wrapper around numbers.MinMax that controls
@@ -19,17 +19,20 @@ import (
 	"image"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
 	"github.com/mum4k/termdash/private/canvas/braille/testbraille"
 	"github.com/mum4k/termdash/private/canvas/testcanvas"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/draw/testdraw"
 	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -62,6 +65,46 @@ func TestLineChartDraws(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc:   "fails with unsupported plot mode",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				PlotMode(Plot(-1)),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails with unsupported legend position",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				Legend(LegendPosition(-1)),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails with negative axis width",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				YAxisWidth(-1, 0),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails with axis width max too small",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				YAxisWidth(0, 1),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails with axis height min larger than max",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				XAxisHeight(4, 3),
+			},
+			wantErr: true,
+		},
 		{
 			desc:   "fails with custom scale where min is NaN",
 			canvas: image.Rect(0, 0, 3, 4),
@@ -102,6 +145,88 @@ func TestLineChartDraws(t *testing.T) {
 			},
 			wantWriteErr: true,
 		},
+		{
+			desc:   "SetSeriesVisible fails without name for the series",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.SetSeriesVisible("", false)
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "series fails when SeriesXValues has a different length",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.Series("series", []float64{1, 2}, SeriesXValues([]int{0}))
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "series fails when SeriesXValues has a negative coordinate",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.Series("series", []float64{1, 2}, SeriesXValues([]int{-1, 2}))
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "series fails when SeriesXValues aren't increasing",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.Series("series", []float64{1, 2}, SeriesXValues([]int{2, 2}))
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "fails with zero time unit",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				XAxisTimeUnit(0),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "time series fails without name",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.TimeSeries("", nil)
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "time series fails when points aren't sorted by time",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+				return lc.TimeSeries("series", []TimePoint{
+					{Time: now, Value: 1},
+					{Time: now.Add(-time.Second), Value: 2},
+				})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "time series fails when points are less than the unit apart",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+				return lc.TimeSeries("series", []TimePoint{
+					{Time: now, Value: 1},
+					{Time: now.Add(time.Millisecond), Value: 2},
+				})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "time series fails on points before the Unix epoch",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(lc *LineChart) error {
+				return lc.TimeSeries("series", []TimePoint{
+					{Time: time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC), Value: 1},
+				})
+			},
+			wantWriteErr: true,
+		},
 		{
 			desc:   "series fails when custom label has negative key",
 			canvas: image.Rect(0, 0, 3, 4),
@@ -153,6 +278,65 @@ func TestLineChartDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:         "respects the minimum axis width and height",
+			canvas:       image.Rect(0, 0, 5, 5),
+			opts:         []Option{YAxisWidth(3, 0), XAxisHeight(3, 0)},
+			wantCapacity: 4,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{2, 0}, End: image.Point{2, 2}},
+					{Start: image.Point{2, 2}, End: image.Point{4, 2}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Zero value labels.
+				testdraw.MustText(c, "0", image.Point{1, 1})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "respects the maximum axis width",
+			canvas: image.Rect(0, 0, 20, 10),
+			opts:   []Option{YAxisWidth(0, 3)},
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 100})
+			},
+			wantCapacity: 34,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{2, 0}, End: image.Point{2, 8}},
+					{Start: image.Point{2, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels, trimmed to fit.
+				testdraw.MustText(c, "0", image.Point{1, 7})
+				testdraw.MustText(c, "5…", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{3, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(3, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{32, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "empty with just one point",
 			canvas: image.Rect(0, 0, 3, 4),
@@ -242,6 +426,40 @@ func TestLineChartDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "uses colors from the theme unless cell options override them",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				XLabelCellOpts(
+					cell.FgColor(cell.ColorBlue),
+				),
+			},
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Axis:  cell.ColorRed,
+					Label: cell.ColorGreen,
+				},
+			},
+			wantCapacity: 2,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{1, 0}, End: image.Point{1, 2}},
+					{Start: image.Point{1, 2}, End: image.Point{2, 2}},
+				}
+				testdraw.MustHVLines(c, lines, draw.HVLineCellOpts(cell.FgColor(cell.ColorRed)))
+
+				// Zero value labels.
+				testdraw.MustText(c, "0", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testdraw.MustText(c, "0", image.Point{2, 3}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "two Y and X labels",
 			canvas: image.Rect(0, 0, 20, 10),
@@ -277,36 +495,36 @@ func TestLineChartDraws(t *testing.T) {
 			},
 		},
 		{
-			desc: "custom Y scale, zero based positive, values fit",
-			opts: []Option{
-				YAxisCustomScale(0, 200),
-			},
+			desc:   "positions values at explicit X coordinates",
 			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{0, 100})
+				return lc.Series("first", []float64{0, 100, 0}, SeriesXValues([]int{0, 2, 10}))
 			},
-			wantCapacity: 26,
+			wantCapacity: 28,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
-					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "0", image.Point{5, 7})
-				testdraw.MustText(c, "103.36", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{7, 9})
-				testdraw.MustText(c, "1", image.Point{19, 9})
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "3", image.Point{10, 9})
+				testdraw.MustText(c, "6", image.Point{14, 9})
+				testdraw.MustText(c, "9", image.Point{18, 9})
 
-				// Braille line.
-				graphAr := image.Rect(7, 0, 20, 8)
+				// Braille lines.
+				graphAr := image.Rect(6, 0, 20, 8)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{25, 16})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{5, 0})
+				testdraw.MustBrailleLine(bc, image.Point{5, 0}, image.Point{26, 31})
 				testbraille.MustCopyTo(bc, c)
 
 				testcanvas.MustApply(c, ft)
@@ -314,36 +532,35 @@ func TestLineChartDraws(t *testing.T) {
 			},
 		},
 		{
-			desc: "custom Y scale, zero based negative, values fit",
-			opts: []Option{
-				YAxisCustomScale(-200, 0),
-			},
+			desc:   "X axis starts at the lowest explicit X coordinate",
 			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{0, -200})
+				return lc.Series("first", []float64{0, 100, 0}, SeriesXValues([]int{5, 7, 15}))
 			},
-			wantCapacity: 26,
+			wantCapacity: 28,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
-					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "-200", image.Point{2, 7})
-				testdraw.MustText(c, "-96.64", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{7, 9})
-				testdraw.MustText(c, "1", image.Point{19, 9})
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "5", image.Point{6, 9})
+				testdraw.MustText(c, "8", image.Point{10, 9})
+				testdraw.MustText(c, "11", image.Point{14, 9})
 
-				// Braille line.
-				graphAr := image.Rect(7, 0, 20, 8)
+				// Braille lines.
+				graphAr := image.Rect(6, 0, 20, 8)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{25, 31})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{5, 0})
+				testdraw.MustBrailleLine(bc, image.Point{5, 0}, image.Point{26, 31})
 				testbraille.MustCopyTo(bc, c)
 
 				testcanvas.MustApply(c, ft)
@@ -351,36 +568,41 @@ func TestLineChartDraws(t *testing.T) {
 			},
 		},
 		{
-			desc: "custom Y scale, negative and positive, values fit",
+			desc:   "X axis displays time labels for time series",
+			canvas: image.Rect(0, 0, 20, 10),
 			opts: []Option{
-				YAxisCustomScale(-200, 200),
+				XAxisTimeLocation(time.UTC),
 			},
-			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{0, 100})
+				start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+				return lc.TimeSeries("first", []TimePoint{
+					{Time: start, Value: 0},
+					{Time: start.Add(2 * time.Second), Value: 100},
+					{Time: start.Add(10 * time.Second), Value: 0},
+				})
 			},
-			wantCapacity: 30,
+			wantCapacity: 28,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{4, 0}, End: image.Point{4, 8}},
-					{Start: image.Point{4, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "-200", image.Point{0, 7})
-				testdraw.MustText(c, "6.57", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{5, 9})
-				testdraw.MustText(c, "1", image.Point{19, 9})
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "10:00:00", image.Point{6, 9})
 
-				// Braille line.
-				graphAr := image.Rect(5, 0, 20, 8)
+				// Braille lines.
+				graphAr := image.Rect(6, 0, 20, 8)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 16}, image.Point{29, 8})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{5, 0})
+				testdraw.MustBrailleLine(bc, image.Point{5, 0}, image.Point{26, 31})
 				testbraille.MustCopyTo(bc, c)
 
 				testcanvas.MustApply(c, ft)
@@ -388,67 +610,222 @@ func TestLineChartDraws(t *testing.T) {
 			},
 		},
 		{
-			desc: "custom Y scale, negative only, values fit",
+			desc:   "plots with half blocks",
+			canvas: image.Rect(0, 0, 20, 10),
 			opts: []Option{
-				YAxisCustomScale(-200, -100),
+				PlotMode(PlotBlock),
 			},
-			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{-200, -100})
+				return lc.Series("first", []float64{0, 100})
 			},
-			wantCapacity: 24,
+			wantCapacity: 28,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{7, 0}, End: image.Point{7, 8}},
-					{Start: image.Point{7, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "-200", image.Point{3, 7})
-				testdraw.MustText(c, "-148.32", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{8, 9})
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
 				testdraw.MustText(c, "1", image.Point{19, 9})
 
-				// Braille line.
-				graphAr := image.Rect(8, 0, 20, 8)
+				// The braille line converted to half blocks.
+				graphAr := image.Rect(6, 0, 20, 8)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{23, 0})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{26, 0})
 				testbraille.MustCopyTo(bc, c)
+				for col := graphAr.Min.X; col < graphAr.Max.X; col++ {
+					for row := graphAr.Min.Y; row < graphAr.Max.Y; row++ {
+						p := image.Point{col, row}
+						cl := testcanvas.MustCell(c, p)
+						testcanvas.MustSetCell(c, p, braille.ToHalfBlock(cl.Rune), cl.Opts)
+					}
+				}
 
 				testcanvas.MustApply(c, ft)
 				return ft
 			},
 		},
 		{
-			desc: "custom Y scale, negative and positive, values don't fit so adjusted",
+			desc: "custom Y scale, zero based positive, values fit",
 			opts: []Option{
-				YAxisCustomScale(-200, 200),
+				YAxisCustomScale(0, 200),
 			},
 			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{-400, 400})
+				return lc.Series("first", []float64{0, 100})
 			},
-			wantCapacity: 28,
+			wantCapacity: 26,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
-					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
+					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "-400", image.Point{1, 7})
-				testdraw.MustText(c, "12.96", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "0", image.Point{5, 7})
+				testdraw.MustText(c, "103.36", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{7, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{25, 16})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom Y scale, zero based negative, values fit",
+			opts: []Option{
+				YAxisCustomScale(-200, 0),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, -200})
+			},
+			wantCapacity: 26,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
+					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "-200", image.Point{2, 7})
+				testdraw.MustText(c, "-96.64", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{7, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{25, 31})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom Y scale, negative and positive, values fit",
+			opts: []Option{
+				YAxisCustomScale(-200, 200),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 100})
+			},
+			wantCapacity: 30,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{4, 0}, End: image.Point{4, 8}},
+					{Start: image.Point{4, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "-200", image.Point{0, 7})
+				testdraw.MustText(c, "6.57", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{5, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(5, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 16}, image.Point{29, 8})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom Y scale, negative only, values fit",
+			opts: []Option{
+				YAxisCustomScale(-200, -100),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{-200, -100})
+			},
+			wantCapacity: 24,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{7, 0}, End: image.Point{7, 8}},
+					{Start: image.Point{7, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "-200", image.Point{3, 7})
+				testdraw.MustText(c, "-148.32", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{8, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(8, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{23, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom Y scale, negative and positive, values don't fit so adjusted",
+			opts: []Option{
+				YAxisCustomScale(-200, 200),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{-400, 400})
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "-400", image.Point{1, 7})
+				testdraw.MustText(c, "12.96", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
 				testdraw.MustText(c, "1", image.Point{19, 9})
 
 				// Braille line.
@@ -485,47 +862,401 @@ func TestLineChartDraws(t *testing.T) {
 				testdraw.MustText(c, "0", image.Point{7, 9})
 				testdraw.MustText(c, "1", image.Point{19, 9})
 
-				// Braille line.
-				graphAr := image.Rect(7, 0, 20, 8)
+				// Braille line.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 5}, image.Point{25, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "AddHLine fails on NaN value",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.AddHLine(math.NaN(), "")
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "AddHLine fails on invalid label",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.AddHLine(1, "a\nb")
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "draws horizontal lines within the range with labels",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{1600, 1900}); err != nil {
+					return err
+				}
+				if err := lc.AddHLine(1000, "SLA", cell.FgColor(cell.ColorRed)); err != nil {
+					return err
+				}
+				// Outside of the range, not drawn.
+				return lc.AddHLine(5000, "max")
+			},
+			wantCapacity: 26,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
+					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{5, 7})
+				testdraw.MustText(c, "980.80", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{7, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille lines.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 15}, image.Point{25, 15}, draw.BrailleLineCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustBrailleLine(bc, image.Point{0, 5}, image.Point{25, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				// The label of the horizontal line.
+				testdraw.MustText(c, "SLA", image.Point{17, 2}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "clears horizontal lines",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{1600, 1900}); err != nil {
+					return err
+				}
+				if err := lc.AddHLine(1000, "SLA"); err != nil {
+					return err
+				}
+				lc.ClearHLines()
+				return nil
+			},
+			wantCapacity: 26,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
+					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{5, 7})
+				testdraw.MustText(c, "980.80", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{7, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 5}, image.Point{25, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws adaptive Y axis",
+			opts: []Option{
+				YAxisAdaptive(),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{1600, 1900})
+			},
+			wantCapacity: 24,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{7, 0}, End: image.Point{7, 8}},
+					{Start: image.Point{7, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "1600", image.Point{3, 7})
+				testdraw.MustText(c, "1754.88", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{8, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(8, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{23, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws logarithmic Y axis",
+			opts: []Option{
+				YAxisLogarithmic(),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{1, 100})
+			},
+			wantCapacity: 32,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{3, 0}, End: image.Point{3, 8}},
+					{Start: image.Point{3, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "1", image.Point{2, 7})
+				testdraw.MustText(c, "10", image.Point{1, 3})
+				testdraw.MustText(c, "100", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{4, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(4, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{30, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "logarithmic Y axis skips non-positive values",
+			opts: []Option{
+				YAxisLogarithmic(),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 1, 100})
+			},
+			wantCapacity: 32,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{3, 0}, End: image.Point{3, 8}},
+					{Start: image.Point{3, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "1", image.Point{2, 7})
+				testdraw.MustText(c, "10", image.Point{1, 3})
+				testdraw.MustText(c, "100", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{4, 9})
+				testdraw.MustText(c, "1", image.Point{11, 9})
+				testdraw.MustText(c, "2", image.Point{19, 9})
+
+				// Braille line, the segment from the zero value is skipped.
+				graphAr := image.Rect(4, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{15, 31}, image.Point{31, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws series against the right Y axis",
+			opts: []Option{
+				YAxisRightUnit("k"),
+			},
+			canvas: image.Rect(0, 0, 30, 10),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("left", []float64{0, 10}); err != nil {
+					return err
+				}
+				return lc.Series("right", []float64{1000, 0}, SeriesRightYAxis())
+			},
+			wantCapacity: 34,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y, X and right Y axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{4, 0}, End: image.Point{4, 8}},
+					{Start: image.Point{4, 8}, End: image.Point{22, 8}},
+					{Start: image.Point{22, 0}, End: image.Point{22, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{3, 7})
+				testdraw.MustText(c, "5.28", image.Point{0, 3})
+				testdraw.MustText(c, "0k", image.Point{23, 7})
+				testdraw.MustText(c, "516.16k", image.Point{23, 3})
+				testdraw.MustText(c, "0", image.Point{5, 9})
+				testdraw.MustText(c, "1", image.Point{21, 9})
+
+				// Braille lines.
+				graphAr := image.Rect(5, 0, 22, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{32, 1})
+				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{32, 31})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the legend below the chart",
+			opts: []Option{
+				Legend(LegendBelow),
+			},
+			canvas: image.Rect(0, 0, 20, 11),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("up", []float64{0, 100}, SeriesCellOpts(cell.FgColor(cell.ColorBlue))); err != nil {
+					return err
+				}
+				return lc.Series("down", []float64{100, 0})
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille lines.
+				graphAr := image.Rect(6, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{26, 31})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{26, 0}, draw.BrailleLineCellOpts(cell.FgColor(cell.ColorBlue)))
+				testbraille.MustCopyTo(bc, c)
+
+				// Legend.
+				testdraw.MustText(c, "■ down", image.Point{6, 10})
+				testdraw.MustText(c, "■ up", image.Point{14, 10}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the legend inside the graph",
+			opts: []Option{
+				Legend(LegendTopRight),
+			},
+			canvas: image.Rect(0, 0, 20, 11),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("up", []float64{0, 100}, SeriesCellOpts(cell.FgColor(cell.ColorBlue))); err != nil {
+					return err
+				}
+				return lc.Series("down", []float64{100, 0})
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 9}},
+					{Start: image.Point{5, 9}, End: image.Point{19, 9}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{4, 8})
+				testdraw.MustText(c, "45.76", image.Point{0, 4})
+				testdraw.MustText(c, "91.52", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{6, 10})
+				testdraw.MustText(c, "1", image.Point{19, 10})
+
+				// Braille lines.
+				graphAr := image.Rect(6, 0, 20, 9)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 5}, image.Point{25, 0})
+				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{26, 35})
+				testdraw.MustBrailleLine(bc, image.Point{0, 35}, image.Point{26, 0}, draw.BrailleLineCellOpts(cell.FgColor(cell.ColorBlue)))
 				testbraille.MustCopyTo(bc, c)
 
+				// Legend.
+				testcanvas.MustSetAreaCells(c, image.Rect(14, 0, 20, 2), ' ')
+				testdraw.MustText(c, "■ down", image.Point{14, 0})
+				testdraw.MustText(c, "■ up", image.Point{14, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+
 				testcanvas.MustApply(c, ft)
 				return ft
 			},
 		},
 		{
-			desc: "draws adaptive Y axis",
-			opts: []Option{
-				YAxisAdaptive(),
-			},
+			desc:   "fills the area under the series",
 			canvas: image.Rect(0, 0, 20, 10),
 			writes: func(lc *LineChart) error {
-				return lc.Series("first", []float64{1600, 1900})
+				return lc.Series("first", []float64{100, 100}, SeriesFill())
 			},
-			wantCapacity: 24,
+			wantCapacity: 28,
 			want: func(size image.Point) *faketerm.Terminal {
 				ft := faketerm.MustNew(size)
 				c := testcanvas.MustNew(ft.Area())
 
 				// Y and X axis.
 				lines := []draw.HVLine{
-					{Start: image.Point{7, 0}, End: image.Point{7, 8}},
-					{Start: image.Point{7, 8}, End: image.Point{19, 8}},
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
 				}
 				testdraw.MustHVLines(c, lines)
 
 				// Value labels.
-				testdraw.MustText(c, "1600", image.Point{3, 7})
-				testdraw.MustText(c, "1754.88", image.Point{0, 3})
-				testdraw.MustText(c, "0", image.Point{8, 9})
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
 				testdraw.MustText(c, "1", image.Point{19, 9})
 
-				// Braille line.
-				graphAr := image.Rect(8, 0, 20, 8)
+				// Filled area.
+				graphAr := image.Rect(6, 0, 20, 8)
 				bc := testbraille.MustNew(graphAr)
-				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{23, 0})
+				for x := 0; x <= 26; x++ {
+					for y := 0; y <= 31; y++ {
+						testbraille.MustSetPixel(bc, image.Point{x, y})
+					}
+				}
+				testdraw.MustBrailleLine(bc, image.Point{0, 0}, image.Point{26, 0})
 				testbraille.MustCopyTo(bc, c)
 
 				testcanvas.MustApply(c, ft)
@@ -909,6 +1640,94 @@ func TestLineChartDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "hidden series isn't drawn and doesn't affect the scale",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				if err := lc.SetSeriesVisible("second", false); err != nil {
+					return err
+				}
+				if err := lc.Series("first", []float64{0, 50, 100}); err != nil {
+					return err
+				}
+				return lc.Series("second", []float64{-10, 200, 300, 400})
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "1", image.Point{12, 9})
+				testdraw.MustText(c, "2", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(6, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{13, 16})
+				testdraw.MustBrailleLine(bc, image.Point{13, 16}, image.Point{27, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "series can be shown again after being hidden",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				if err := lc.Series("first", []float64{0, 50, 100}); err != nil {
+					return err
+				}
+				if err := lc.Series("second", []float64{-10, 200}); err != nil {
+					return err
+				}
+				if err := lc.SetSeriesVisible("second", false); err != nil {
+					return err
+				}
+				return lc.SetSeriesVisible("second", true)
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "-10", image.Point{2, 7})
+				testdraw.MustText(c, "98.48", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "1", image.Point{12, 9})
+				testdraw.MustText(c, "2", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(6, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 30}, image.Point{13, 22})
+				testdraw.MustBrailleLine(bc, image.Point{13, 22}, image.Point{27, 15})
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{13, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "more values than capacity, X rescales",
 			canvas: image.Rect(0, 0, 11, 10),
@@ -1204,6 +2023,50 @@ func TestLineChartDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "draws the X cursor set on the cursor group",
+			opts: []Option{
+				XCursorGroup(func() *CursorGroup {
+					cg := NewCursorGroup()
+					cg.Set(1)
+					return cg
+				}()),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 100})
+			},
+			wantCapacity: 28,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{5, 0}, End: image.Point{5, 8}},
+					{Start: image.Point{5, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0", image.Point{4, 7})
+				testdraw.MustText(c, "51.68", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{6, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(6, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{26, 0})
+
+				// The X cursor.
+				testbraille.MustSetAreaCellOpts(bc, image.Rect(13, 0, 14, 8), cell.BgColor(cell.ColorNumber(240)))
+
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "highlights area for zoom to a custom color",
 			opts: []Option{
@@ -1683,6 +2546,51 @@ func TestLineChartDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "fails on a Y-axis unit with a newline",
+			canvas: image.Rect(0, 0, 20, 10),
+			opts: []Option{
+				YAxisUnit("\n"),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "Y-axis labels with a unit",
+			canvas: image.Rect(0, 0, 20, 10),
+			opts: []Option{
+				YAxisUnit("%"),
+			},
+			writes: func(lc *LineChart) error {
+				return lc.Series("first", []float64{0, 100})
+			},
+			wantCapacity: 26,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{6, 0}, End: image.Point{6, 8}},
+					{Start: image.Point{6, 8}, End: image.Point{19, 8}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Value labels.
+				testdraw.MustText(c, "0%", image.Point{4, 7})
+				testdraw.MustText(c, "51.68%", image.Point{0, 3})
+				testdraw.MustText(c, "0", image.Point{7, 9})
+				testdraw.MustText(c, "1", image.Point{19, 9})
+
+				// Braille line.
+				graphAr := image.Rect(7, 0, 20, 8)
+				bc := testbraille.MustNew(graphAr)
+				testdraw.MustBrailleLine(bc, image.Point{0, 31}, image.Point{25, 0})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "custom Y-axis labels using a value formatter that returns empty labels",
 			canvas: image.Rect(0, 0, 20, 10),
@@ -1896,13 +2804,98 @@ func TestLineChartDraws(t *testing.T) {
 	}
 }
 
-func TestKeyboard(t *testing.T) {
+func TestYAxisStableWidth(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		// wantWidth is the width of the Y axis with its labels on the second
+		// draw.
+		wantWidth int
+	}{
+		{
+			desc:      "Y axis shrinks with the default formatting",
+			wantWidth: 5,
+		},
+		{
+			desc: "Y axis keeps its width with a label formatter",
+			opts: []Option{
+				YLabelFormatter(ValueFormatterSuffix(0, "MB")),
+			},
+			wantWidth: 8,
+		},
+		{
+			desc: "Y axis keeps its width with a unit",
+			opts: []Option{
+				YAxisUnit("%"),
+			},
+			wantWidth: 10,
+		},
+		{
+			desc: "stable width is limited by the YAxisWidth option",
+			opts: []Option{
+				YAxisUnit("%"),
+				YAxisWidth(0, 4),
+			},
+			wantWidth: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			lc, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			for _, values := range [][]float64{{0, 100000}, {0, 1}} {
+				if err := lc.Series("first", values); err != nil {
+					t.Fatalf("Series => unexpected error: %v", err)
+				}
+				c := testcanvas.MustNew(image.Rect(0, 0, 20, 10))
+				if err := lc.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if got := lc.lastGraphAr.Min.X; got != tc.wantWidth {
+				t.Errorf("Draw => Y axis width %d, want %d", got, tc.wantWidth)
+			}
+		})
+	}
+}
+
+func TestKeyboardDoesNothingWithoutZoomTracker(t *testing.T) {
+	lc, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := lc.Keyboard(&terminalapi.Keyboard{Key: '+'}, &widgetapi.EventMeta{}); err != nil {
+		t.Errorf("Keyboard => unexpected error: %v", err)
+	}
+}
+
+func TestKeyboardZoom(t *testing.T) {
 	lc, err := New()
 	if err != nil {
 		t.Fatalf("New => unexpected error: %v", err)
 	}
-	if err := lc.Keyboard(&terminalapi.Keyboard{}, &widgetapi.EventMeta{}); err == nil {
-		t.Errorf("Keyboard => got nil err, wanted one")
+	if err := lc.Series("series", make([]float64, 101)); err != nil {
+		t.Fatalf("Series => unexpected error: %v", err)
+	}
+
+	c := testcanvas.MustNew(image.Rect(0, 0, 30, 10))
+	if err := lc.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if err := lc.Keyboard(&terminalapi.Keyboard{Key: '+'}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+	if err := lc.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	if got, want := int(lc.lastXD.Scale.Min.Value), 5; got != want {
+		t.Errorf("after zoom in, X axis starts at %d, want %d", got, want)
 	}
 }
 
@@ -1916,6 +2909,57 @@ func TestMouseDoesNothingWithoutZoomTracker(t *testing.T) {
 	}
 }
 
+func TestCursorGroup(t *testing.T) {
+	cg := NewCursorGroup()
+	var charts []*LineChart
+	for i := 0; i < 2; i++ {
+		lc, err := New(XCursorGroup(cg))
+		if err != nil {
+			t.Fatalf("New => unexpected error: %v", err)
+		}
+		if err := lc.Series("first", []float64{0, 100}); err != nil {
+			t.Fatalf("Series => unexpected error: %v", err)
+		}
+		if err := lc.Draw(testcanvas.MustNew(image.Rect(0, 0, 20, 10)), &widgetapi.Meta{}); err != nil {
+			t.Fatalf("Draw => unexpected error: %v", err)
+		}
+		charts = append(charts, lc)
+	}
+
+	if _, ok := cg.X(); ok {
+		t.Fatalf("X => cursor is set before any mouse events")
+	}
+
+	// Clicking outside of the graph doesn't move the cursor.
+	if err := charts[0].Mouse(&terminalapi.Mouse{Position: image.Point{2, 2}, Button: mouse.ButtonLeft}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if _, ok := cg.X(); ok {
+		t.Errorf("X => cursor is set after a click outside of the graph")
+	}
+
+	if err := charts[0].Mouse(&terminalapi.Mouse{Position: image.Point{19, 2}, Button: mouse.ButtonLeft}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if got, ok := cg.X(); !ok || got != 1 {
+		t.Errorf("X => (%v, %v), want (1, true)", got, ok)
+	}
+
+	if err := charts[1].Mouse(&terminalapi.Mouse{Position: image.Point{6, 2}, Button: mouse.ButtonLeft}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if got, ok := cg.X(); !ok || got != 0 {
+		t.Errorf("X => (%v, %v), want (0, true)", got, ok)
+	}
+
+	if err := charts[0].Mouse(&terminalapi.Mouse{Position: image.Point{6, 2}, Button: mouse.ButtonRight}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if _, ok := cg.X(); ok {
+		t.Errorf("X => cursor is still set after a right click")
+	}
+}
+
 func TestOptions(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -1927,8 +2971,9 @@ func TestOptions(t *testing.T) {
 		{
 			desc: "reserves space for axis without series",
 			want: widgetapi.Options{
-				MinimumSize: image.Point{3, 4},
-				WantMouse:   widgetapi.MouseScopeGlobal,
+				MinimumSize:  image.Point{3, 4},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
 		{
@@ -1937,8 +2982,9 @@ func TestOptions(t *testing.T) {
 				return lc.Series("series", []float64{0, 100})
 			},
 			want: widgetapi.Options{
-				MinimumSize: image.Point{5, 4},
-				WantMouse:   widgetapi.MouseScopeGlobal,
+				MinimumSize:  image.Point{5, 4},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
 		{
@@ -1947,8 +2993,9 @@ func TestOptions(t *testing.T) {
 				return lc.Series("series", []float64{-100, 100})
 			},
 			want: widgetapi.Options{
-				MinimumSize: image.Point{6, 4},
-				WantMouse:   widgetapi.MouseScopeGlobal,
+				MinimumSize:  image.Point{6, 4},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
 		{
@@ -1960,8 +3007,9 @@ func TestOptions(t *testing.T) {
 				return lc.Series("series", []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
 			},
 			want: widgetapi.Options{
-				MinimumSize: image.Point{4, 5},
-				WantMouse:   widgetapi.MouseScopeGlobal,
+				MinimumSize:  image.Point{4, 5},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
 		{
@@ -1973,8 +3021,9 @@ func TestOptions(t *testing.T) {
 				return lc.Series("series", []float64{0, 100}, SeriesXLabels(map[int]string{0: "text"}))
 			},
 			want: widgetapi.Options{
-				MinimumSize: image.Point{5, 7},
-				WantMouse:   widgetapi.MouseScopeGlobal,
+				MinimumSize:  image.Point{5, 7},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
 	}
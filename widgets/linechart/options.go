@@ -17,9 +17,12 @@ package linechart
 import (
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/mum4k/termdash/cell"
-	"github.com/mum4k/termdash/widgets/linechart/internal/axes"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/widgets/internal/axes"
 	"github.com/mum4k/termdash/widgets/linechart/internal/zoom"
 )
 
@@ -39,10 +42,25 @@ type options struct {
 	yLabelCellOpts      []cell.Option
 	xAxisUnscaled       bool
 	yAxisMode           axes.YScaleMode
+	yAxisLogarithmic    bool
 	yAxisCustomScale    *customScale
 	yAxisValueFormatter ValueFormatter
+	yAxisUnit           string
+	yAxisRightUnit      string
+	yAxisStableWidth    bool
 	zoomHightlightColor cell.Color
 	zoomStepPercent     int
+	cursorGroup         *CursorGroup
+	cursorColor         cell.Color
+	plot                Plot
+	yAxisMinWidth       int
+	yAxisMaxWidth       int
+	xAxisMinHeight      int
+	xAxisMaxHeight      int
+	xAxisTimeUnit       time.Duration
+	xAxisTimeLocation   *time.Location
+	legend              bool
+	legendPosition      LegendPosition
 }
 
 // validate validates the provided options.
@@ -55,17 +73,66 @@ func (o *options) validate() error {
 			return fmt.Errorf("the min(%v) must be less than the max(%v) provided as custom Y scale", o.yAxisCustomScale.min, o.yAxisCustomScale.max)
 		}
 	}
+	if _, ok := legendPositionNames[o.legendPosition]; !ok {
+		return fmt.Errorf("invalid LegendPosition %v(%d)", o.legendPosition, o.legendPosition)
+	}
+	if _, ok := plotNames[o.plot]; !ok {
+		return fmt.Errorf("invalid PlotMode %v(%d)", o.plot, o.plot)
+	}
+	if o.yAxisUnit != "" {
+		if err := wrap.ValidText(o.yAxisUnit); err != nil {
+			return fmt.Errorf("invalid YAxisUnit %q: %v", o.yAxisUnit, err)
+		}
+		if strings.ContainsRune(o.yAxisUnit, '\n') {
+			return fmt.Errorf("invalid YAxisUnit %q: newline characters aren't allowed", o.yAxisUnit)
+		}
+	}
+	if o.yAxisRightUnit != "" {
+		if err := wrap.ValidText(o.yAxisRightUnit); err != nil {
+			return fmt.Errorf("invalid YAxisRightUnit %q: %v", o.yAxisRightUnit, err)
+		}
+		if strings.ContainsRune(o.yAxisRightUnit, '\n') {
+			return fmt.Errorf("invalid YAxisRightUnit %q: newline characters aren't allowed", o.yAxisRightUnit)
+		}
+	}
+	if err := validateMargin("YAxisWidth", o.yAxisMinWidth, o.yAxisMaxWidth); err != nil {
+		return err
+	}
+	if err := validateMargin("XAxisHeight", o.xAxisMinHeight, o.xAxisMaxHeight); err != nil {
+		return err
+	}
+	if o.xAxisTimeUnit <= 0 {
+		return fmt.Errorf("invalid XAxisTimeUnit %v, must be a positive duration", o.xAxisTimeUnit)
+	}
 	if got, min, max := o.zoomStepPercent, 1, 100; got < min || got > max {
 		return fmt.Errorf("invalid ZoomStepPercent %d, must be in range %d <= value <= %d", got, min, max)
 	}
 	return nil
 }
 
+// validateMargin validates the minimum and maximum size of the space reserved
+// for an axis and its labels provided to the named option.
+func validateMargin(name string, min, max int) error {
+	if min < 0 || max < 0 {
+		return fmt.Errorf("invalid %s(min:%d, max:%d), the values must be zero or positive", name, min, max)
+	}
+	// At least one cell for the axis and one for its labels.
+	if minMax := 2; max > 0 && max < minMax {
+		return fmt.Errorf("invalid %s(min:%d, max:%d), the max must be zero or at least %d", name, min, max, minMax)
+	}
+	if max > 0 && min > max {
+		return fmt.Errorf("invalid %s(min:%d, max:%d), the min cannot be larger than the max", name, min, max)
+	}
+	return nil
+}
+
 // newOptions returns a new options instance.
 func newOptions(opts ...Option) *options {
 	opt := &options{
 		zoomHightlightColor: cell.ColorNumber(235),
 		zoomStepPercent:     zoom.DefaultScrollStep,
+		cursorColor:         cell.ColorNumber(240),
+		xAxisTimeUnit:       DefaultXAxisTimeUnit,
 	}
 	for _, o := range opts {
 		o.set(opt)
@@ -82,6 +149,7 @@ func (o option) set(opts *options) {
 }
 
 // AxesCellOpts set the cell options for the X and Y axes.
+// These take precedence over the Axis color of the dashboard theme.
 func AxesCellOpts(co ...cell.Option) Option {
 	return option(func(opts *options) {
 		opts.axesCellOpts = co
@@ -89,6 +157,7 @@ func AxesCellOpts(co ...cell.Option) Option {
 }
 
 // XLabelCellOpts set the cell options for the labels on the X axis.
+// These take precedence over the Label color of the dashboard theme.
 func XLabelCellOpts(co ...cell.Option) Option {
 	return option(func(opts *options) {
 		opts.xLabelCellOpts = co
@@ -112,6 +181,7 @@ func XLabelsHorizontal() Option {
 }
 
 // YLabelCellOpts set the cell options for the labels on the Y axis.
+// These take precedence over the Label color of the dashboard theme.
 func YLabelCellOpts(co ...cell.Option) Option {
 	return option(func(opts *options) {
 		opts.yLabelCellOpts = co
@@ -132,6 +202,20 @@ func YAxisAdaptive() Option {
 	})
 }
 
+// YAxisLogarithmic makes the Y axis logarithmic with base ten. Useful for
+// series whose values span several orders of magnitude. The Y axis starts and
+// ends on powers of ten that enclose the values in the series and its labels
+// are placed on the powers of ten.
+// Only positive values can be represented on a logarithmic axis, zero and
+// negative values in the series are treated as missing values and aren't
+// drawn.
+// This option takes precedence over YAxisAdaptive.
+func YAxisLogarithmic() Option {
+	return option(func(opts *options) {
+		opts.yAxisLogarithmic = true
+	})
+}
+
 // customScale is the custom scale provided via the YAxisCustomScale option.
 type customScale struct {
 	min, max float64
@@ -177,6 +261,60 @@ func XAxisUnscaled() Option {
 	})
 }
 
+// YAxisWidth limits the width of the space left of the graph which is reserved
+// for the Y axis and its labels. By default the width is determined
+// automatically from the widest label. A non-zero min reserves at least that
+// many cells, which helps to align multiple line charts or to leave room for
+// labels of values that arrive later. A non-zero max limits the space so that
+// more of it is left for the graph, labels that don't fit are trimmed.
+// Provide the same min and max to set the width explicitly.
+// Both values include the one cell taken by the axis itself, so the max must
+// be either zero or at least two. Defaults to zero for both.
+func YAxisWidth(min, max int) Option {
+	return option(func(opts *options) {
+		opts.yAxisMinWidth = min
+		opts.yAxisMaxWidth = max
+	})
+}
+
+// XAxisHeight limits the height of the space under the graph which is reserved
+// for the X axis and its labels. By default the height is determined
+// automatically, it is two rows for horizontal labels and enough rows to fit
+// the longest label for vertical labels. A non-zero min reserves at least that
+// many rows, the extra rows are left empty under the labels. A non-zero max
+// limits the space so that more of it is left for the graph, vertical labels
+// that don't fit are trimmed.
+// Provide the same min and max to set the height explicitly.
+// Both values include the one row taken by the axis itself, so the max must
+// be either zero or at least two. Defaults to zero for both.
+func XAxisHeight(min, max int) Option {
+	return option(func(opts *options) {
+		opts.xAxisMinHeight = min
+		opts.xAxisMaxHeight = max
+	})
+}
+
+// DefaultXAxisTimeUnit is the default value for the XAxisTimeUnit option.
+const DefaultXAxisTimeUnit = time.Second
+
+// XAxisTimeUnit sets the resolution of the X axis when it displays series
+// provided via TimeSeries. Points of a time series must be at least one unit
+// apart. Use a shorter unit for series with sub-second intervals.
+func XAxisTimeUnit(d time.Duration) Option {
+	return option(func(opts *options) {
+		opts.xAxisTimeUnit = d
+	})
+}
+
+// XAxisTimeLocation sets the location used to display the labels of the X
+// axis when it displays series provided via TimeSeries.
+// Defaults to the local time.
+func XAxisTimeLocation(loc *time.Location) Option {
+	return option(func(opts *options) {
+		opts.xAxisTimeLocation = loc
+	})
+}
+
 // ZoomHightlightColor sets the background color of the area that is selected
 // with mouse in order to zoom the linechart.
 // Defaults to color number 235.
@@ -196,6 +334,66 @@ func ZoomStepPercent(perc int) Option {
 	})
 }
 
+// XCursorGroup links the LineChart to the provided group of line charts that
+// share the position of the X cursor. Clicking the left mouse button inside
+// the graph of any of the linked line charts moves the cursor to the clicked
+// value, clicking the right mouse button clears it.
+// The default behavior is to not draw any X cursor.
+func XCursorGroup(cg *CursorGroup) Option {
+	return option(func(opts *options) {
+		opts.cursorGroup = cg
+	})
+}
+
+// XCursorColor sets the background color of the column that represents the X
+// cursor. Only takes effect together with the XCursorGroup option.
+// Defaults to color number 240.
+func XCursorColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.cursorColor = c
+	})
+}
+
+// Plot determines the characters used to plot the series.
+type Plot int
+
+// String implements fmt.Stringer()
+func (p Plot) String() string {
+	if n, ok := plotNames[p]; ok {
+		return n
+	}
+	return "PlotUnknown"
+}
+
+// plotNames maps Plot values to human readable names.
+var plotNames = map[Plot]string{
+	PlotBraille: "PlotBraille",
+	PlotBlock:   "PlotBlock",
+}
+
+const (
+	// PlotBraille plots the series using braille pattern characters, which
+	// have the resolution of 2x4 pixels per cell.
+	PlotBraille Plot = iota
+
+	// PlotBlock plots the series using the half block characters, which only
+	// have the resolution of 1x2 pixels per cell, but render correctly with
+	// more terminals and fonts.
+	PlotBlock
+)
+
+// DefaultPlot is the default value for the PlotMode option.
+const DefaultPlot = PlotBraille
+
+// PlotMode sets the characters used to plot the series. The axes and their
+// labels are the same regardless of the selected mode.
+// Defaults to DefaultPlot.
+func PlotMode(p Plot) Option {
+	return option(func(opts *options) {
+		opts.plot = p
+	})
+}
+
 // YAxisFormattedValues sets a value formatter for the Y axis values.
 // If a formatter is set, it will format the values with the desired
 // ValueFormatter and will use the retuning string from the formatter
@@ -206,7 +404,57 @@ func YAxisFormattedValues(vfmt ValueFormatter) Option {
 	})
 }
 
+// YLabelFormatter sets a value formatter for the labels on the Y axis, e.g.
+// one of the ValueFormatter implementations provided by this package.
+//
+// Unlike YAxisFormattedValues, this option also keeps the width of the Y axis
+// stable. The axis doesn't get narrower than the widest labels displayed so
+// far, so the graph doesn't jump sideways as the values and their labels
+// change. Combine with the YAxisCustomScale option to fix the labels
+// entirely.
+// Defaults to the numeric values rounded to two non-zero decimal places.
+func YLabelFormatter(vfmt ValueFormatter) Option {
+	return option(func(opts *options) {
+		opts.yAxisValueFormatter = vfmt
+		opts.yAxisStableWidth = true
+	})
+}
+
+// YAxisUnit sets a unit, e.g. "MB" or "%", that is appended to the labels on
+// the Y axis, including labels produced by the YLabelFormatter or the
+// YAxisFormattedValues option. Keeps the width of the Y axis stable the same
+// way as the YLabelFormatter option.
+// Defaults to no unit.
+func YAxisUnit(unit string) Option {
+	return option(func(opts *options) {
+		opts.yAxisUnit = unit
+		opts.yAxisStableWidth = true
+	})
+}
+
+// YAxisRightUnit sets a unit that is appended to the labels on the right Y
+// axis, see SeriesRightYAxis. Works the same way as YAxisUnit does for the
+// left Y axis.
+// Defaults to no unit.
+func YAxisRightUnit(unit string) Option {
+	return option(func(opts *options) {
+		opts.yAxisRightUnit = unit
+	})
+}
+
 // ValueFormatter will be used to format values onto string based
 // representation.
 // The received float64 value could be a math.NaN value.
 type ValueFormatter func(value float64) string
+
+// Legend draws a legend that lists the names of the visible series in their
+// cell options, i.e. in the colors the series are drawn in. The position
+// determines if the legend is drawn inside the graph or on a row below the
+// chart.
+// Defaults to no legend.
+func Legend(position LegendPosition) Option {
+	return option(func(opts *options) {
+		opts.legend = true
+		opts.legendPosition = position
+	})
+}
@@ -0,0 +1,490 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package list contains a widget that displays a scrollable list of
+// selectable items.
+package list
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Item is one item of the list.
+type Item struct {
+	// Text is the text displayed for the item, truncated if it doesn't fit
+	// the width of the widget.
+	Text string
+	// CellOpts are the cell options on the cells that contain the text.
+	CellOpts []cell.Option
+}
+
+// NewItem returns a new Item with the text and cell options.
+func NewItem(text string, co ...cell.Option) Item {
+	return Item{
+		Text:     text,
+		CellOpts: co,
+	}
+}
+
+// List displays items one per line and highlights the selected item.
+//
+// When focused, the keyboard moves the selected item, the selection can also
+// be set by clicking on an item. Items that don't fit the height of the
+// widget scroll so that the selected item is always visible. With the
+// MultiSelect option each item has a checkbox that is toggled with the
+// CheckKey or by clicking on it.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type List struct {
+	// items are the displayed items.
+	items []Item
+	// checked are the indexes of the checked items.
+	checked map[int]bool
+
+	// selected is the index of the selected item or -1 if no item is
+	// selected.
+	selected int
+	// offset is the index of the first displayed item.
+	offset int
+
+	// lastAr is the area the items were drawn into as of the last call to
+	// Draw.
+	lastAr image.Rectangle
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the List.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new List widget.
+func New(opts ...Option) (*List, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &List{
+		checked:  map[int]bool{},
+		selected: -1,
+		opts:     opt,
+	}, nil
+}
+
+// validateItem validates the item.
+func validateItem(item Item) error {
+	if item.Text == "" {
+		return nil
+	}
+	if err := wrap.ValidText(item.Text); err != nil {
+		return fmt.Errorf("invalid text %q: %v", item.Text, err)
+	}
+	if strings.ContainsRune(item.Text, '\n') {
+		return fmt.Errorf("invalid text %q: newline characters aren't allowed", item.Text)
+	}
+	return nil
+}
+
+// AddItems adds items after all the existing items.
+func (l *List) AddItems(items ...Item) error {
+	for i, item := range items {
+		if err := validateItem(item); err != nil {
+			return fmt.Errorf("invalid item %d: %v", i, err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.dirty.Mark()
+	l.items = append(l.items, items...)
+	return nil
+}
+
+// SetItems replaces all the items. The selection and the checked items are
+// kept if they still exist.
+func (l *List) SetItems(items []Item) error {
+	for i, item := range items {
+		if err := validateItem(item); err != nil {
+			return fmt.Errorf("invalid item %d: %v", i, err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.dirty.Mark()
+	l.items = items
+	if l.selected >= len(items) {
+		l.selected = -1
+	}
+	for i := range l.checked {
+		if i >= len(items) {
+			delete(l.checked, i)
+		}
+	}
+	return nil
+}
+
+// Reset removes all the items and clears the selection and the checked
+// items.
+func (l *List) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.dirty.Mark()
+	l.items = nil
+	l.checked = map[int]bool{}
+	l.selected = -1
+	l.offset = 0
+}
+
+// Selected returns the index of the selected item. Returns false if no item
+// is selected.
+func (l *List) Selected() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.selected, l.selected >= 0
+}
+
+// Select selects the item with the specified index. Doesn't call the function
+// provided via the OnSelect option.
+func (l *List) Select(index int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.dirty.Mark()
+	if err := l.validateIndex(index); err != nil {
+		return err
+	}
+	l.selected = index
+	return nil
+}
+
+// Checked returns the indexes of the checked items in ascending order.
+func (l *List) Checked() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []int
+	for i := range l.checked {
+		res = append(res, i)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// SetChecked checks or unchecks the item with the specified index. Doesn't
+// call the function provided via the OnCheck option.
+func (l *List) SetChecked(index int, checked bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.dirty.Mark()
+	if err := l.validateIndex(index); err != nil {
+		return err
+	}
+	l.setChecked(index, checked)
+	return nil
+}
+
+// validateIndex validates the index of an item.
+// The caller must hold l.mu.
+func (l *List) validateIndex(index int) error {
+	if index < 0 || index >= len(l.items) {
+		return fmt.Errorf("invalid index %d, must be in range 0 <= index < %d", index, len(l.items))
+	}
+	return nil
+}
+
+// setChecked checks or unchecks the item.
+// The caller must hold l.mu.
+func (l *List) setChecked(index int, checked bool) {
+	if checked {
+		l.checked[index] = true
+	} else {
+		delete(l.checked, index)
+	}
+}
+
+// Draw draws the List widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (l *List) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	needAr, err := area.FromSize(l.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	l.lastAr = ar
+	visible := ar.Dy()
+	if l.selected >= 0 {
+		if l.selected < l.offset {
+			l.offset = l.selected
+		}
+		if l.selected >= l.offset+visible {
+			l.offset = l.selected - visible + 1
+		}
+	}
+	if max := len(l.items) - visible; l.offset > max {
+		l.offset = max
+	}
+	if l.offset < 0 {
+		l.offset = 0
+	}
+
+	for y := 0; y < visible && l.offset+y < len(l.items); y++ {
+		itemAr := image.Rect(ar.Min.X, ar.Min.Y+y, ar.Max.X, ar.Min.Y+y+1)
+		if err := l.drawItem(cvs, l.offset+y, itemAr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawItem draws the item with the index into the area.
+func (l *List) drawItem(cvs *canvas.Canvas, index int, itemAr image.Rectangle) error {
+	item := l.items[index]
+	cellOpts := item.CellOpts
+	selected := index == l.selected
+	if selected {
+		if err := draw.Rectangle(cvs, itemAr,
+			draw.RectChar(' '),
+			draw.RectCellOpts(l.opts.selectedCellOpts...),
+		); err != nil {
+			return err
+		}
+		cellOpts = append(append([]cell.Option{}, item.CellOpts...), l.opts.selectedCellOpts...)
+	}
+
+	text := item.Text
+	if l.opts.multiSelect {
+		marker := l.opts.uncheckedMarker
+		if l.checked[index] {
+			marker = l.opts.checkedMarker
+		}
+		text = marker + text
+	}
+	if text == "" {
+		return nil
+	}
+	truncated, err := draw.Truncate(text, itemAr.Dx())
+	if err != nil {
+		return err
+	}
+	return draw.Text(cvs, truncated, itemAr.Min, draw.TextMaxX(itemAr.Max.X), draw.TextCellOpts(cellOpts...))
+}
+
+// minSize determines the minimum required size to draw the widget.
+func (l *List) minSize() image.Point {
+	return image.Point{1, 1}
+}
+
+// move moves the selection by the specified number of items. Selects the
+// first item if no item is selected. Returns true if the selection changed.
+// The caller must hold l.mu.
+func (l *List) move(by int) bool {
+	if len(l.items) == 0 {
+		return false
+	}
+	if l.selected < 0 {
+		l.selected = 0
+		return true
+	}
+	return l.moveTo(l.selected + by)
+}
+
+// moveTo selects the item with the index, clamped to the existing items.
+// Returns true if the selection changed.
+// The caller must hold l.mu.
+func (l *List) moveTo(index int) bool {
+	if len(l.items) == 0 {
+		return false
+	}
+	if index < 0 {
+		index = 0
+	}
+	if max := len(l.items) - 1; index > max {
+		index = max
+	}
+	if index == l.selected {
+		return false
+	}
+	l.selected = index
+	return true
+}
+
+// pageItems returns the number of items to move by when paging.
+// The caller must hold l.mu.
+func (l *List) pageItems() int {
+	if items := l.lastAr.Dy(); items > 1 {
+		return items
+	}
+	return 1
+}
+
+// change describes the effect of an event on the List.
+type change struct {
+	// selected indicates the selected item changed.
+	selected bool
+	// checked indicates the checked state of the selected item changed.
+	checked bool
+	// index is the index of the selected item.
+	index int
+	// state is the checked state of the selected item.
+	state bool
+}
+
+// notify calls the callbacks for the change.
+// Mutex must be released when calling the callbacks so that they can access
+// the List.
+func (l *List) notify(c change) error {
+	if c.selected && l.opts.onSelect != nil {
+		if err := l.opts.onSelect(c.index); err != nil {
+			return err
+		}
+	}
+	if c.checked && l.opts.onCheck != nil {
+		return l.opts.onCheck(c.index, c.state)
+	}
+	return nil
+}
+
+// toggle checks or unchecks the selected item.
+// The caller must hold l.mu.
+func (l *List) toggle() change {
+	if !l.opts.multiSelect || l.selected < 0 {
+		return change{}
+	}
+	state := !l.checked[l.selected]
+	l.setChecked(l.selected, state)
+	return change{
+		checked: true,
+		index:   l.selected,
+		state:   state,
+	}
+}
+
+// Keyboard moves the selected item and checks or unchecks it.
+// Implements widgetapi.Widget.Keyboard.
+func (l *List) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return l.notify(l.keyboard(k))
+}
+
+// keyboard processes the keyboard event.
+func (l *List) keyboard(k *terminalapi.Keyboard) change {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var changed bool
+	switch k.Key {
+	case l.opts.keyUp:
+		changed = l.move(-1)
+	case l.opts.keyDown:
+		changed = l.move(1)
+	case l.opts.keyPgUp:
+		changed = l.move(-l.pageItems())
+	case l.opts.keyPgDown:
+		changed = l.move(l.pageItems())
+	case l.opts.keyHome:
+		changed = l.moveTo(0)
+	case l.opts.keyEnd:
+		changed = l.moveTo(len(l.items) - 1)
+	case l.opts.keyCheck:
+		return l.toggle()
+	}
+	return change{
+		selected: changed,
+		index:    l.selected,
+	}
+}
+
+// Mouse selects the clicked item and checks or unchecks it when the click
+// falls onto its checkbox. The navigation buttons move the selected item.
+// Implements widgetapi.Widget.Mouse.
+func (l *List) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return l.notify(l.mouse(m))
+}
+
+// mouse processes the mouse event.
+func (l *List) mouse(m *terminalapi.Mouse) change {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var changed bool
+	switch m.Button {
+	case l.opts.mouseUpButton:
+		changed = l.move(-1)
+	case l.opts.mouseDownButton:
+		changed = l.move(1)
+	case mouse.ButtonLeft:
+		if !m.Position.In(l.lastAr) {
+			break
+		}
+		index := l.offset + m.Position.Y - l.lastAr.Min.Y
+		if index >= len(l.items) {
+			break
+		}
+		changed = index != l.selected
+		l.selected = index
+
+		markerX := l.lastAr.Min.X + runewidth.StringWidth(l.opts.checkedMarker)
+		if l.opts.multiSelect && m.Position.X < markerX {
+			c := l.toggle()
+			c.selected = changed
+			return c
+		}
+	}
+	return change{
+		selected: changed,
+		index:    l.selected,
+	}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (l *List) Options() widgetapi.Options {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  l.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (l *List) SetDirtyFunc(markDirty func()) {
+	l.dirty.Set(markDirty)
+}
@@ -0,0 +1,477 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// keyEv returns a keyboard event for the key.
+func keyEv(k keyboard.Key) *terminalapi.Keyboard {
+	return &terminalapi.Keyboard{Key: k}
+}
+
+// clickEv returns a left click mouse event at the position.
+func clickEv(x, y int) *terminalapi.Mouse {
+	return &terminalapi.Mouse{Position: image.Point{x, y}, Button: mouse.ButtonLeft}
+}
+
+// fruitItems returns items used in the tests.
+func fruitItems() []Item {
+	return []Item{
+		NewItem("apple"),
+		NewItem("fig"),
+		NewItem("pear"),
+		NewItem("plum"),
+	}
+}
+
+// pressKeys returns a function that delivers the keyboard events.
+func pressKeys(keys ...keyboard.Key) func(*List) error {
+	return func(l *List) error {
+		for _, k := range keys {
+			if err := l.Keyboard(keyEv(k), &widgetapi.EventMeta{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		items  []Item
+		// update is called after the items are set.
+		update func(*List) error
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events        func(*List) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails on checkbox markers of different widths",
+			opts:       []Option{CheckboxMarkers("[x]", "[ ] ")},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "fails on duplicate navigation keys",
+			opts: []Option{
+				NavigationKeys(keyboard.KeyArrowUp, keyboard.KeyArrowUp, keyboard.KeyPgUp, keyboard.KeyPgDn, keyboard.KeyHome, keyboard.KeyEnd),
+			},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails when the check key is a navigation key",
+			opts:       []Option{CheckKey(keyboard.KeyHome)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on duplicate navigation mouse buttons",
+			opts:       []Option{NavigationMouseButtons(mouse.ButtonWheelUp, mouse.ButtonWheelUp)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:          "fails on an item with a newline",
+			canvas:        image.Rect(0, 0, 10, 3),
+			items:         []Item{NewItem("a\nb")},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails to add an item with a newline",
+			canvas: image.Rect(0, 0, 10, 3),
+			update: func(l *List) error {
+				return l.AddItems(NewItem("a\nb"))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails to select an item that doesn't exist",
+			canvas: image.Rect(0, 0, 10, 3),
+			update: func(l *List) error {
+				return l.Select(0)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails to check an item that doesn't exist",
+			canvas: image.Rect(0, 0, 10, 3),
+			items:  fruitItems(),
+			update: func(l *List) error {
+				return l.SetChecked(4, true)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws the items",
+			canvas: image.Rect(0, 0, 6, 5),
+			items: []Item{
+				NewItem("apple", cell.FgColor(cell.ColorRed)),
+				NewItem("fig"),
+			},
+			update: func(l *List) error {
+				return l.AddItems(NewItem("pomegranate"))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "apple", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "fig", image.Point{0, 1})
+				testdraw.MustText(c, "pomeg…", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "highlights the selected item",
+			canvas: image.Rect(0, 0, 6, 4),
+			items:  fruitItems(),
+			update: func(l *List) error {
+				return l.Select(1)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "apple", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 6, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "fig", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "pear", image.Point{0, 2})
+				testdraw.MustText(c, "plum", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "end key selects the last item and scrolls",
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems(),
+			events: pressKeys(keyboard.KeyEnd),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "pear", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 6, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "plum", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "home key selects the first item",
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems(),
+			events: pressKeys(keyboard.KeyEnd, keyboard.KeyHome),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 6, 1), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "apple", image.Point{0, 0}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "fig", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "page down moves by the height of the widget",
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems(),
+			events: pressKeys(keyboard.KeyArrowDown, keyboard.KeyPgDn),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "fig", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 6, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "pear", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws checkboxes with multi selection",
+			opts:   []Option{MultiSelect()},
+			canvas: image.Rect(0, 0, 8, 2),
+			items:  fruitItems()[:2],
+			update: func(l *List) error {
+				return l.SetChecked(1, true)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "[ ] app…", image.Point{0, 0})
+				testdraw.MustText(c, "[x] fig", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "check key toggles the selected item",
+			opts: []Option{
+				MultiSelect(),
+				CheckboxMarkers("+", "-"),
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems()[:2],
+			events: pressKeys(keyboard.KeyArrowDown, keyboard.KeySpace),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 6, 1), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "+apple", image.Point{0, 0}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "-fig", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "mouse click on the checkbox toggles the item",
+			opts: []Option{
+				MultiSelect(),
+				CheckboxMarkers("+", "-"),
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems()[:2],
+			events: func(l *List) error {
+				return l.Mouse(clickEv(0, 1), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "-apple", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 6, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "+fig", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "mouse click on the text only selects the item",
+			opts: []Option{
+				MultiSelect(),
+				CheckboxMarkers("+", "-"),
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems()[:2],
+			events: func(l *List) error {
+				return l.Mouse(clickEv(2, 1), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "-apple", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 6, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "-fig", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "check key does nothing without multi selection",
+			canvas: image.Rect(0, 0, 6, 2),
+			items:  fruitItems()[:2],
+			events: pressKeys(keyboard.KeySpace),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "apple", image.Point{0, 0})
+				testdraw.MustText(c, "fig", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			l, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			err = l.SetItems(tc.items)
+			if err == nil && tc.update != nil {
+				err = tc.update(l)
+			}
+			if (err != nil) != tc.wantUpdateErr {
+				t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if tc.events != nil {
+				if err := l.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				if err := tc.events(l); err != nil {
+					t.Fatalf("events => unexpected error: %v", err)
+				}
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+			}
+
+			if err := l.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	var selected []int
+	var checked []string
+	l, err := New(
+		MultiSelect(),
+		OnSelect(func(index int) error {
+			selected = append(selected, index)
+			return nil
+		}),
+		OnCheck(func(index int, state bool) error {
+			if state {
+				checked = append(checked, "+"+fruitItems()[index].Text)
+			} else {
+				checked = append(checked, "-"+fruitItems()[index].Text)
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := l.SetItems(fruitItems()); err != nil {
+		t.Fatalf("SetItems => unexpected error: %v", err)
+	}
+	if err := l.Draw(testcanvas.MustNew(image.Rect(0, 0, 10, 4)), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	events := []func() error{
+		// Nothing is selected, doesn't check anything.
+		func() error { return pressKeys(keyboard.KeySpace)(l) },
+		func() error { return pressKeys(keyboard.KeyArrowDown, keyboard.KeySpace)(l) },
+		// Already on the first item.
+		func() error { return pressKeys(keyboard.KeyHome)(l) },
+		func() error { return l.Mouse(clickEv(0, 2), &widgetapi.EventMeta{}) },
+		func() error { return l.Mouse(clickEv(0, 2), &widgetapi.EventMeta{}) },
+		func() error {
+			return l.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelDown}, &widgetapi.EventMeta{})
+		},
+	}
+	for i, ev := range events {
+		if err := ev(); err != nil {
+			t.Fatalf("event %d => unexpected error: %v", i, err)
+		}
+	}
+
+	if diff := pretty.Compare([]int{0, 2, 3}, selected); diff != "" {
+		t.Errorf("OnSelect => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if diff := pretty.Compare([]string{"+apple", "+pear", "-pear"}, checked); diff != "" {
+		t.Errorf("OnCheck => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if diff := pretty.Compare([]int{0}, l.Checked()); diff != "" {
+		t.Errorf("Checked => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReset(t *testing.T) {
+	l, err := New(MultiSelect())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := l.SetItems(fruitItems()); err != nil {
+		t.Fatalf("SetItems => unexpected error: %v", err)
+	}
+	if err := l.Select(1); err != nil {
+		t.Fatalf("Select => unexpected error: %v", err)
+	}
+	if err := l.SetChecked(2, true); err != nil {
+		t.Fatalf("SetChecked => unexpected error: %v", err)
+	}
+	l.Reset()
+
+	if _, ok := l.Selected(); ok {
+		t.Errorf("Selected => true after Reset, want false")
+	}
+	if got := l.Checked(); len(got) != 0 {
+		t.Errorf("Checked => %v after Reset, want none", got)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	l, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := l.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
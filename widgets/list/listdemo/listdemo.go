@@ -0,0 +1,114 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary listdemo displays a List widget with multi selection.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/list"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	names := []string{
+		"Mercury", "Venus", "Earth", "Mars", "Jupiter", "Saturn", "Uranus",
+		"Neptune", "Ceres", "Pluto", "Haumea", "Makemake", "Eris",
+	}
+
+	status, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	var l *list.List
+	showStatus := func() error {
+		var checked []string
+		for _, i := range l.Checked() {
+			checked = append(checked, names[i])
+		}
+		msg := "Selected nothing"
+		if i, ok := l.Selected(); ok {
+			msg = fmt.Sprintf("Selected %s", names[i])
+		}
+		return status.Write(fmt.Sprintf("%s, checked: %s", msg, strings.Join(checked, ", ")), text.WriteReplace())
+	}
+	l, err = list.New(
+		list.MultiSelect(),
+		list.OnSelect(func(int) error {
+			return showStatus()
+		}),
+		list.OnCheck(func(int, bool) error {
+			return showStatus()
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	for i, n := range names {
+		item := list.NewItem(n)
+		if i >= 8 {
+			item.CellOpts = []cell.Option{cell.FgColor(cell.ColorGray)}
+		}
+		if err := l.AddItems(item); err != nil {
+			panic(err)
+		}
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.PlaceWidget(l),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Use arrows to select, space or click to check"),
+				container.PlaceWidget(status),
+			),
+			container.SplitPercent(80),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
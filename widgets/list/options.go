@@ -0,0 +1,225 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+// options.go contains configurable options for List.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	multiSelect      bool
+	checkedMarker    string
+	uncheckedMarker  string
+	selectedCellOpts []cell.Option
+	onSelect         SelectFn
+	onCheck          CheckFn
+
+	keyUp           keyboard.Key
+	keyDown         keyboard.Key
+	keyPgUp         keyboard.Key
+	keyPgDown       keyboard.Key
+	keyHome         keyboard.Key
+	keyEnd          keyboard.Key
+	keyCheck        keyboard.Key
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		checkedMarker:    DefaultCheckedMarker,
+		uncheckedMarker:  DefaultUncheckedMarker,
+		selectedCellOpts: []cell.Option{cell.Inverse()},
+		keyUp:            DefaultKeyUp,
+		keyDown:          DefaultKeyDown,
+		keyPgUp:          DefaultKeyPageUp,
+		keyPgDown:        DefaultKeyPageDown,
+		keyHome:          DefaultKeyHome,
+		keyEnd:           DefaultKeyEnd,
+		keyCheck:         DefaultKeyCheck,
+		mouseUpButton:    DefaultMouseButtonUp,
+		mouseDownButton:  DefaultMouseButtonDown,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if cw, uw := runewidth.StringWidth(o.checkedMarker), runewidth.StringWidth(o.uncheckedMarker); cw != uw {
+		return fmt.Errorf("invalid checkbox markers %q and %q, they must occupy the same number of cells, got %d and %d", o.checkedMarker, o.uncheckedMarker, cw, uw)
+	}
+
+	keys := map[keyboard.Key]bool{
+		o.keyUp:     true,
+		o.keyDown:   true,
+		o.keyPgUp:   true,
+		o.keyPgDown: true,
+		o.keyHome:   true,
+		o.keyEnd:    true,
+	}
+	if len(keys) != 6 {
+		return fmt.Errorf("invalid navigation keys: %s, %s, %s, %s, %s, %s, the keys must be unique", o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown, o.keyHome, o.keyEnd)
+	}
+	if keys[o.keyCheck] {
+		return fmt.Errorf("invalid CheckKey %s, it is already used as a navigation key", o.keyCheck)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid navigation mouse buttons: %s, %s, the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// MultiSelect displays a checkbox in front of each item, allowing the user to
+// check any number of items in addition to the selected item.
+func MultiSelect() Option {
+	return option(func(opts *options) {
+		opts.multiSelect = true
+	})
+}
+
+// The default markers displayed in front of the items when the MultiSelect
+// option is provided.
+const (
+	DefaultCheckedMarker   = "[x] "
+	DefaultUncheckedMarker = "[ ] "
+)
+
+// CheckboxMarkers sets the text displayed in front of the checked and the
+// unchecked items when the MultiSelect option is provided. Both markers must
+// occupy the same number of cells.
+// Defaults to DefaultCheckedMarker and DefaultUncheckedMarker.
+func CheckboxMarkers(checked, unchecked string) Option {
+	return option(func(opts *options) {
+		opts.checkedMarker = checked
+		opts.uncheckedMarker = unchecked
+	})
+}
+
+// SelectedCellOpts sets the cell options on the cells of the selected item.
+// These are applied on top of the cell options of the individual items.
+// Defaults to inverse colors.
+func SelectedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectedCellOpts = co
+	})
+}
+
+// SelectFn is called when the selected item changes. The argument is the
+// index of the selected item.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The List isn't locked while the function
+// executes, so it can read from or modify the List.
+type SelectFn func(index int) error
+
+// OnSelect sets the function that is called when the user selects an item.
+func OnSelect(fn SelectFn) Option {
+	return option(func(opts *options) {
+		opts.onSelect = fn
+	})
+}
+
+// CheckFn is called when the user checks or unchecks an item. The arguments
+// are the index of the item and its new state.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The List isn't locked while the function
+// executes, so it can read from or modify the List.
+type CheckFn func(index int, checked bool) error
+
+// OnCheck sets the function that is called when the user checks or unchecks
+// an item. Only applies when the MultiSelect option is provided.
+func OnCheck(fn CheckFn) Option {
+	return option(func(opts *options) {
+		opts.onCheck = fn
+	})
+}
+
+// The default keys that move the selection.
+const (
+	DefaultKeyUp       = keyboard.KeyArrowUp
+	DefaultKeyDown     = keyboard.KeyArrowDown
+	DefaultKeyPageUp   = keyboard.KeyPgUp
+	DefaultKeyPageDown = keyboard.KeyPgDn
+	DefaultKeyHome     = keyboard.KeyHome
+	DefaultKeyEnd      = keyboard.KeyEnd
+)
+
+// NavigationKeys configures the keyboard keys that move the selection one
+// item up and down, one page up and down and to the first and the last item.
+// The provided keys must be unique, e.g. the same key cannot be both up and
+// down.
+func NavigationKeys(up, down, pageUp, pageDown, home, end keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyPgUp = pageUp
+		opts.keyPgDown = pageDown
+		opts.keyHome = home
+		opts.keyEnd = end
+	})
+}
+
+// DefaultKeyCheck is the default value for the CheckKey option.
+const DefaultKeyCheck = keyboard.KeySpace
+
+// CheckKey configures the keyboard key that checks or unchecks the selected
+// item when the MultiSelect option is provided. Must not be one of the
+// navigation keys.
+// Defaults to DefaultKeyCheck.
+func CheckKey(k keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyCheck = k
+	})
+}
+
+// The default mouse buttons that move the selection.
+const (
+	DefaultMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// NavigationMouseButtons configures the mouse buttons that move the
+// selection one item up and down.
+// The provided buttons must be unique, e.g. the same button cannot be both up
+// and down.
+func NavigationMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
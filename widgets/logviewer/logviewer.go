@@ -0,0 +1,649 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logviewer contains a widget that displays a stream of log lines.
+package logviewer
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Level is the severity level of a log line.
+type Level int
+
+// String implements fmt.Stringer()
+func (l Level) String() string {
+	if n, ok := levelNames[l]; ok {
+		return n
+	}
+	return "LevelUnknown"
+}
+
+// levelNames maps Level values to human readable names.
+var levelNames = map[Level]string{
+	LevelNone:  "LevelNone",
+	LevelDebug: "LevelDebug",
+	LevelInfo:  "LevelInfo",
+	LevelWarn:  "LevelWarn",
+	LevelError: "LevelError",
+}
+
+const (
+	// LevelNone is the level of lines that don't indicate any severity.
+	LevelNone Level = iota
+	// LevelDebug is the level of debugging lines.
+	LevelDebug
+	// LevelInfo is the level of informational lines.
+	LevelInfo
+	// LevelWarn is the level of warnings.
+	LevelWarn
+	// LevelError is the level of errors.
+	LevelError
+)
+
+// levelWords maps words that commonly mark the severity of a log line to the
+// levels.
+var levelWords = map[string]Level{
+	"TRACE":    LevelDebug,
+	"DEBUG":    LevelDebug,
+	"INFO":     LevelInfo,
+	"WARN":     LevelWarn,
+	"WARNING":  LevelWarn,
+	"ERR":      LevelError,
+	"ERROR":    LevelError,
+	"FATAL":    LevelError,
+	"CRITICAL": LevelError,
+	"PANIC":    LevelError,
+}
+
+// DetectLevel is the default LevelFn. It returns the level of the first word
+// in the line that marks the severity, e.g. "INFO" or "[error]". Letter case
+// is ignored.
+func DetectLevel(line string) Level {
+	words := strings.FieldsFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		if l, ok := levelWords[strings.ToUpper(w)]; ok {
+			return l
+		}
+	}
+	return LevelNone
+}
+
+// line is one line kept by the LogViewer.
+type line struct {
+	text  string
+	level Level
+}
+
+// LogViewer displays a stream of log lines.
+//
+// Only the most recent lines are kept, see the MaxLines option. In the
+// follow mode the newest lines are always displayed, scrolling up pauses the
+// follow mode and scrolling back to the newest line resumes it. The lines are
+// colored based on their severity level and can be filtered by the level or
+// by their text.
+//
+// When focused, the SearchKey starts an incremental search, typed characters
+// are added to the search query which is confirmed with Enter and cleared with
+// Escape. All occurrences of the query are highlighted and the view jumps to
+// the newest matching line, the NextMatch and PrevMatch keys move between the
+// matching lines.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type LogViewer struct {
+	// lines is a ring buffer of the kept lines.
+	lines []line
+	// first is the index of the oldest line in lines.
+	first int
+	// dropped is the number of lines discarded since the last reset. The
+	// position of a line is its index counting from the oldest line plus the
+	// number of dropped lines, so positions don't change when old lines are
+	// discarded.
+	dropped int
+
+	// follow indicates that the newest lines are displayed.
+	follow bool
+	// top is the position of the first displayed line when not following.
+	top int
+
+	// filter is the text the displayed lines must contain.
+	filter string
+	// minLevel is the minimum level of the displayed lines.
+	minLevel Level
+
+	// typing indicates that the user is typing the search query.
+	typing bool
+	// query is the search query.
+	query string
+	// match is the position of the current matching line or -1.
+	match int
+	// showMatch indicates that the next call to Draw must scroll so that the
+	// current matching line is visible.
+	showMatch bool
+
+	// lastRows is the number of rows the lines were drawn into as of the last
+	// call to Draw.
+	lastRows int
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the LogViewer.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new LogViewer.
+func New(opts ...Option) (*LogViewer, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &LogViewer{
+		follow: opt.follow,
+		match:  -1,
+		opts:   opt,
+	}, nil
+}
+
+// sanitize replaces tabs with spaces and removes other characters that
+// cannot be displayed.
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if err := wrap.ValidText(string(r)); err != nil {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// Write adds the text after all the existing lines. Each line of the text
+// becomes a separate log line, a trailing newline is ignored. Tabs are
+// replaced with spaces and other control characters are removed.
+func (lv *LogViewer) Write(text string) error {
+	text = strings.TrimSuffix(text, "\n")
+	lines := strings.Split(text, "\n")
+	added := make([]line, len(lines))
+	for i, l := range lines {
+		l = sanitize(l)
+		added[i] = line{
+			text:  l,
+			level: lv.opts.levelFn(l),
+		}
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	for _, l := range added {
+		lv.add(l)
+	}
+	return nil
+}
+
+// add adds the line, discarding the oldest line if the buffer is full.
+// The caller must hold lv.mu.
+func (lv *LogViewer) add(l line) {
+	if len(lv.lines) < lv.opts.maxLines {
+		lv.lines = append(lv.lines, l)
+		return
+	}
+	lv.lines[lv.first] = l
+	lv.first = (lv.first + 1) % len(lv.lines)
+	lv.dropped++
+	if lv.match >= 0 && lv.match < lv.dropped {
+		lv.match = -1
+	}
+}
+
+// at returns the line at the position.
+// The caller must hold lv.mu.
+func (lv *LogViewer) at(pos int) line {
+	return lv.lines[(lv.first+pos-lv.dropped)%len(lv.lines)]
+}
+
+// Reset removes all the lines and clears the search.
+func (lv *LogViewer) Reset() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	lv.lines = nil
+	lv.first = 0
+	lv.dropped = 0
+	lv.top = 0
+	lv.typing = false
+	lv.query = ""
+	lv.match = -1
+}
+
+// Len returns the number of kept lines.
+func (lv *LogViewer) Len() int {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return len(lv.lines)
+}
+
+// SetFollow turns the follow mode on or off.
+func (lv *LogViewer) SetFollow(follow bool) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	lv.setFollow(follow)
+}
+
+// Following returns true if the follow mode is on.
+func (lv *LogViewer) Following() bool {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.follow
+}
+
+// SetFilter only displays the lines that contain the text. An empty text
+// displays all the lines.
+func (lv *LogViewer) SetFilter(text string) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	lv.filter = text
+}
+
+// SetMinLevel only displays the lines with the specified or higher level.
+// Lines without a level are only displayed when the level is LevelNone,
+// which is the default.
+func (lv *LogViewer) SetMinLevel(l Level) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	lv.minLevel = l
+}
+
+// Search highlights the occurrences of the query and scrolls to the newest
+// matching line. An empty query clears the search. The search is case
+// sensitive.
+func (lv *LogViewer) Search(query string) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	defer lv.dirty.Mark()
+	lv.typing = false
+	lv.setQuery(query)
+}
+
+// setFollow turns the follow mode on or off.
+// The caller must hold lv.mu.
+func (lv *LogViewer) setFollow(follow bool) {
+	if !follow && lv.follow {
+		// Pause at the currently displayed lines.
+		vis := lv.visible()
+		if top := len(vis) - lv.lastRows; top > 0 {
+			lv.top = vis[top]
+		} else if len(vis) > 0 {
+			lv.top = vis[0]
+		}
+	}
+	lv.follow = follow
+}
+
+// setQuery sets the search query and finds the newest matching line.
+// The caller must hold lv.mu.
+func (lv *LogViewer) setQuery(query string) {
+	lv.query = query
+	lv.match = -1
+	if query == "" {
+		return
+	}
+	vis := lv.visible()
+	for k := len(vis) - 1; k >= 0; k-- {
+		if strings.Contains(lv.at(vis[k]).text, query) {
+			lv.jumpTo(vis[k])
+			return
+		}
+	}
+}
+
+// jumpTo makes the line at the position the current match.
+// The caller must hold lv.mu.
+func (lv *LogViewer) jumpTo(pos int) {
+	lv.setFollow(false)
+	lv.match = pos
+	lv.showMatch = true
+}
+
+// nextMatch moves to the next matching line in the direction, a negative
+// direction moves to older lines. Starts from the newest line if there is no
+// current match.
+// The caller must hold lv.mu.
+func (lv *LogViewer) nextMatch(dir int) {
+	if lv.query == "" {
+		return
+	}
+	vis := lv.visible()
+	if len(vis) == 0 {
+		return
+	}
+
+	k := len(vis)
+	if lv.match >= 0 {
+		k = lv.visibleIndex(vis, lv.match)
+	} else {
+		dir = -1
+	}
+	for k += dir; k >= 0 && k < len(vis); k += dir {
+		if strings.Contains(lv.at(vis[k]).text, lv.query) {
+			lv.jumpTo(vis[k])
+			return
+		}
+	}
+}
+
+// visible returns the positions of the lines that pass the filters.
+// The caller must hold lv.mu.
+func (lv *LogViewer) visible() []int {
+	var res []int
+	for pos := lv.dropped; pos < lv.dropped+len(lv.lines); pos++ {
+		l := lv.at(pos)
+		if l.level < lv.minLevel {
+			continue
+		}
+		if lv.filter != "" && !strings.Contains(l.text, lv.filter) {
+			continue
+		}
+		res = append(res, pos)
+	}
+	return res
+}
+
+// visibleIndex returns the index of the first visible line at or after the
+// position, i.e. len(vis) if there is no such line.
+func (lv *LogViewer) visibleIndex(vis []int, pos int) int {
+	for k, p := range vis {
+		if p >= pos {
+			return k
+		}
+	}
+	return len(vis)
+}
+
+// statusRows returns the number of rows reserved for the search prompt.
+// The caller must hold lv.mu.
+func (lv *LogViewer) statusRows() int {
+	if lv.typing || lv.query != "" {
+		return 1
+	}
+	return 0
+}
+
+// Draw draws the LogViewer widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (lv *LogViewer) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	needAr, err := area.FromSize(lv.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	rows := ar.Dy() - lv.statusRows()
+	lv.lastRows = rows
+
+	vis := lv.visible()
+	maxTop := len(vis) - rows
+	if maxTop < 0 {
+		maxTop = 0
+	}
+	top := maxTop
+	if !lv.follow {
+		top = lv.visibleIndex(vis, lv.top)
+		if lv.showMatch && lv.match >= 0 {
+			if k := lv.visibleIndex(vis, lv.match); k < top || k >= top+rows {
+				top = k - rows/2
+			}
+		}
+		if top > maxTop {
+			top = maxTop
+		}
+		if top < 0 {
+			top = 0
+		}
+		if top < len(vis) {
+			lv.top = vis[top]
+		}
+	}
+	lv.showMatch = false
+
+	for y := 0; y < rows && top+y < len(vis); y++ {
+		start := image.Point{ar.Min.X, ar.Min.Y + y}
+		if err := lv.drawLine(cvs, lv.at(vis[top+y]), start, ar.Dx()); err != nil {
+			return err
+		}
+	}
+
+	if lv.statusRows() > 0 {
+		return lv.drawStatus(cvs, vis)
+	}
+	return nil
+}
+
+// drawLine draws the line starting at the point, truncated to the width.
+func (lv *LogViewer) drawLine(cvs *canvas.Canvas, l line, start image.Point, width int) error {
+	if l.text == "" {
+		return nil
+	}
+	text, err := draw.Truncate(l.text, width)
+	if err != nil {
+		return err
+	}
+	lineOpts := lv.opts.levelCellOpts[l.level]
+	if err := draw.Text(cvs, text, start, draw.TextCellOpts(lineOpts...)); err != nil {
+		return err
+	}
+	if lv.query == "" {
+		return nil
+	}
+
+	matchOpts := append(append([]cell.Option{}, lineOpts...), lv.opts.matchCellOpts...)
+	for i := 0; ; {
+		idx := strings.Index(text[i:], lv.query)
+		if idx < 0 {
+			return nil
+		}
+		i += idx
+		p := image.Point{start.X + runewidth.StringWidth(text[:i]), start.Y}
+		if err := draw.Text(cvs, lv.query, p, draw.TextCellOpts(matchOpts...)); err != nil {
+			return err
+		}
+		i += len(lv.query)
+	}
+}
+
+// drawStatus draws the search prompt and the number of matching lines on the
+// last row of the canvas.
+func (lv *LogViewer) drawStatus(cvs *canvas.Canvas, vis []int) error {
+	ar := cvs.Area()
+	status := "/" + lv.query
+	if !lv.typing {
+		var matches, current int
+		for _, pos := range vis {
+			if !strings.Contains(lv.at(pos).text, lv.query) {
+				continue
+			}
+			matches++
+			if pos == lv.match {
+				current = matches
+			}
+		}
+		status = fmt.Sprintf("%s (%d/%d)", status, current, matches)
+	}
+	text, err := draw.Truncate(status, ar.Dx())
+	if err != nil {
+		return err
+	}
+	return draw.Text(cvs, text, image.Point{ar.Min.X, ar.Max.Y - 1})
+}
+
+// minSize determines the minimum required size to draw the widget.
+// The caller must hold lv.mu.
+func (lv *LogViewer) minSize() image.Point {
+	return image.Point{1, 1 + lv.statusRows()}
+}
+
+// scroll scrolls the lines by the specified number of lines, a negative
+// number scrolls up towards older lines. Scrolling down to the newest line
+// turns the follow mode on.
+// The caller must hold lv.mu.
+func (lv *LogViewer) scroll(by int) {
+	if lv.follow && by >= 0 {
+		return
+	}
+	lv.setFollow(false)
+
+	vis := lv.visible()
+	maxTop := len(vis) - lv.lastRows
+	top := lv.visibleIndex(vis, lv.top) + by
+	if top >= maxTop {
+		lv.follow = true
+		return
+	}
+	if top < 0 {
+		top = 0
+	}
+	lv.top = vis[top]
+}
+
+// pageLines returns the number of lines to scroll by when paging.
+// The caller must hold lv.mu.
+func (lv *LogViewer) pageLines() int {
+	if lv.lastRows > 1 {
+		return lv.lastRows
+	}
+	return 1
+}
+
+// Keyboard scrolls the lines, toggles the follow mode and edits the search
+// query.
+// Implements widgetapi.Widget.Keyboard.
+func (lv *LogViewer) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	if lv.typing {
+		lv.editQuery(k.Key)
+		return nil
+	}
+
+	switch k.Key {
+	case lv.opts.keySearch:
+		lv.typing = true
+		lv.setQuery("")
+	case keyboard.KeyEsc:
+		lv.setQuery("")
+	case lv.opts.keyFollow:
+		lv.setFollow(!lv.follow)
+	case lv.opts.keyNextMatch:
+		lv.nextMatch(1)
+	case lv.opts.keyPrevMatch:
+		lv.nextMatch(-1)
+	case lv.opts.keyUp:
+		lv.scroll(-1)
+	case lv.opts.keyDown:
+		lv.scroll(1)
+	case lv.opts.keyPgUp:
+		lv.scroll(-lv.pageLines())
+	case lv.opts.keyPgDown:
+		lv.scroll(lv.pageLines())
+	}
+	return nil
+}
+
+// editQuery processes the key pressed while the user types the search query.
+// The caller must hold lv.mu.
+func (lv *LogViewer) editQuery(key keyboard.Key) {
+	switch key {
+	case keyboard.KeyEnter:
+		lv.typing = false
+	case keyboard.KeyEsc:
+		lv.typing = false
+		lv.setQuery("")
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if q := []rune(lv.query); len(q) > 0 {
+			lv.setQuery(string(q[:len(q)-1]))
+		}
+	default:
+		if key < 0 || wrap.ValidText(string(key)) != nil {
+			// Ignore special keys and unsupported runes.
+			return
+		}
+		lv.setQuery(lv.query + string(key))
+	}
+}
+
+// Mouse scrolls the lines.
+// Implements widgetapi.Widget.Mouse.
+func (lv *LogViewer) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	switch m.Button {
+	case lv.opts.mouseUpButton:
+		lv.scroll(-1)
+	case lv.opts.mouseDownButton:
+		lv.scroll(1)
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (lv *LogViewer) Options() widgetapi.Options {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  lv.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (lv *LogViewer) SetDirtyFunc(markDirty func()) {
+	lv.dirty.Set(markDirty)
+}
@@ -0,0 +1,526 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logviewer
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// numbered returns text with n lines "l0" to "l<n-1>".
+func numbered(n int) string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("l%d", i))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pressKeys returns a function that delivers the keyboard events.
+func pressKeys(keys ...keyboard.Key) func(*LogViewer) error {
+	return func(lv *LogViewer) error {
+		for _, k := range keys {
+			if err := lv.Keyboard(&terminalapi.Keyboard{Key: k}, &widgetapi.EventMeta{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// mustText draws the lines of text starting at the top left corner of the
+// canvas.
+func mustText(c *canvas.Canvas, text string, opts ...cell.Option) {
+	for y, l := range strings.Split(text, "\n") {
+		testdraw.MustText(c, l, image.Point{0, y}, draw.TextCellOpts(opts...))
+	}
+}
+
+func TestLogViewer(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		// write gets called before the first draw.
+		write func(*LogViewer) error
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events     func(*LogViewer) error
+		want       func(size image.Point) *faketerm.Terminal
+		wantFollow bool
+		wantErr    bool
+	}{
+		{
+			desc: "fails on zero MaxLines",
+			opts: []Option{
+				MaxLines(0),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on a nil level detector",
+			opts: []Option{
+				LevelDetector(nil),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate keys",
+			opts: []Option{
+				SearchKeys('f', 'f', 'n', 'N'),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails when a key is Escape",
+			opts: []Option{
+				ScrollKeys(keyboard.KeyEsc, keyboard.KeyArrowDown, keyboard.KeyPgUp, keyboard.KeyPgDn),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate mouse buttons",
+			opts: []Option{
+				ScrollMouseButtons(mouse.ButtonLeft, mouse.ButtonLeft),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "draws nothing without lines",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantFollow: true,
+		},
+		{
+			desc:   "follows the newest lines",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l3\nl4")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc:   "new lines scroll in follow mode",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(2))
+			},
+			events: func(lv *LogViewer) error {
+				return lv.Write("new\n")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l1\nnew")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc: "starts at the oldest lines when paused",
+			opts: []Option{
+				Paused(),
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l0\nl1")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "discards the oldest lines",
+			opts: []Option{
+				MaxLines(3),
+				Paused(),
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l2\nl3")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates long lines and replaces tabs",
+			canvas: image.Rect(0, 0, 4, 1),
+			write: func(lv *LogViewer) error {
+				return lv.Write("a\tbcdef")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "a b…")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc: "colors lines by level",
+			opts: []Option{
+				LevelCellOpts(LevelInfo, cell.FgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			write: func(lv *LogViewer) error {
+				return lv.Write("E: err\nI info\nplain")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "E: err", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "I info", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testdraw.MustText(c, "plain", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc:   "filters lines by text and level",
+			canvas: image.Rect(0, 0, 9, 3),
+			write: func(lv *LogViewer) error {
+				if err := lv.Write("WARN db\nERROR db\nERROR web\ndb"); err != nil {
+					return err
+				}
+				lv.SetFilter("db")
+				lv.SetMinLevel(LevelWarn)
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "WARN db", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+				testdraw.MustText(c, "ERROR db", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc:   "scrolling up pauses the follow mode",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			events: func(lv *LogViewer) error {
+				if err := pressKeys(keyboard.KeyArrowUp)(lv); err != nil {
+					return err
+				}
+				return lv.Write("new")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l2\nl3")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolling down to the newest line resumes the follow mode",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			events: func(lv *LogViewer) error {
+				if err := pressKeys(keyboard.KeyPgUp, keyboard.KeyArrowDown, keyboard.KeyArrowDown)(lv); err != nil {
+					return err
+				}
+				return lv.Write("new")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l4\nnew")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantFollow: true,
+		},
+		{
+			desc:   "mouse wheel scrolls",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(5))
+			},
+			events: func(lv *LogViewer) error {
+				for i := 0; i < 3; i++ {
+					if err := lv.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelUp}, &widgetapi.EventMeta{}); err != nil {
+						return err
+					}
+				}
+				return lv.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelDown}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l1\nl2")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "the follow key toggles the follow mode",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(2))
+			},
+			events: func(lv *LogViewer) error {
+				if err := pressKeys('f')(lv); err != nil {
+					return err
+				}
+				return lv.Write("new")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l0\nl1")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "shows the prompt while typing the query",
+			canvas: image.Rect(0, 0, 4, 3),
+			write: func(lv *LogViewer) error {
+				return lv.Write("ab\nxy\nax")
+			},
+			events: pressKeys('/', 'x'),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "y", image.Point{1, 0})
+				testdraw.MustText(c, "a", image.Point{0, 1})
+				for _, p := range []image.Point{{0, 0}, {1, 1}} {
+					testdraw.MustText(c, "x", p, draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorYellow),
+					))
+				}
+				testdraw.MustText(c, "/x", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "highlights all occurrences and moves between matching lines",
+			canvas: image.Rect(0, 0, 10, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write("aa\nb\na\nb")
+			},
+			events: pressKeys('/', 'a', keyboard.KeyEnter, 'N'),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				for _, p := range []image.Point{{0, 0}, {1, 0}} {
+					testdraw.MustText(c, "a", p, draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorYellow),
+					))
+				}
+				testdraw.MustText(c, "/a (1/2)", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "backspace edits the query and escape clears it",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(2))
+			},
+			events: pressKeys('/', 'x', keyboard.KeyBackspace2, 'l', keyboard.KeyEsc),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustText(c, "l0\nl1")
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search scrolls to the matching line",
+			canvas: image.Rect(0, 0, 4, 2),
+			write: func(lv *LogViewer) error {
+				return lv.Write(numbered(10))
+			},
+			events: func(lv *LogViewer) error {
+				lv.Search("l2")
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "l2", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlack),
+					cell.BgColor(cell.ColorYellow),
+				))
+				testdraw.MustText(c, "/l2…", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			lv, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if err := tc.write(lv); err != nil {
+				t.Fatalf("write => unexpected error: %v", err)
+			}
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := lv.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if tc.events != nil {
+				if err := tc.events(lv); err != nil {
+					t.Fatalf("events => unexpected error: %v", err)
+				}
+				c, err = canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := lv.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if got := lv.Following(); got != tc.wantFollow {
+				t.Errorf("Following => %v, want %v", got, tc.wantFollow)
+			}
+		})
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want Level
+	}{
+		{"", LevelNone},
+		{"nothing to see", LevelNone},
+		{"2020-01-01 [info] started", LevelInfo},
+		{"level=debug msg=x", LevelDebug},
+		{"W: WARNING disk", LevelWarn},
+		{"ERROR: failed, retrying at INFO", LevelError},
+		{"information", LevelNone},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			if got := DetectLevel(tc.line); got != tc.want {
+				t.Errorf("DetectLevel(%q) => %v, want %v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	lv, err := New(MaxLines(3))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := lv.Write(numbered(2)); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+	if got, want := lv.Len(), 2; got != want {
+		t.Errorf("Len => %d, want %d", got, want)
+	}
+	if err := lv.Write(numbered(2)); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+	if got, want := lv.Len(), 3; got != want {
+		t.Errorf("Len => %d, want %d", got, want)
+	}
+	lv.Reset()
+	if got, want := lv.Len(), 0; got != want {
+		t.Errorf("Len => %d, want %d", got, want)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	lv, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	got := lv.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
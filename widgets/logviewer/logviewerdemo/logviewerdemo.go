@@ -0,0 +1,94 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary logviewerdemo displays a LogViewer widget with a stream of log lines.
+// Exist when Ctrl-Q is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/logviewer"
+)
+
+// messages are the messages of the generated log lines.
+var messages = []string{
+	"DEBUG cache lookup key=%d",
+	"INFO  request served in %dms",
+	"INFO  connection accepted from 10.0.0.%d",
+	"WARN  slow query took %dms",
+	"ERROR upstream returned status %d",
+}
+
+// writeLogs writes a log line to the LogViewer periodically.
+func writeLogs(ctx context.Context, lv *logviewer.LogViewer, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case t := <-ticker.C:
+			msg := fmt.Sprintf(messages[rand.Intn(len(messages))], rand.Intn(500))
+			if err := lv.Write(fmt.Sprintf("%s %s", t.Format("15:04:05.000"), msg)); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lv, err := logviewer.New(logviewer.MaxLines(1000))
+	if err != nil {
+		panic(err)
+	}
+	go writeLogs(ctx, lv, 200*time.Millisecond)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS CTRL-Q TO QUIT, F TO FOLLOW, / TO SEARCH"),
+		container.PlaceWidget(lv),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == keyboard.KeyCtrlQ {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
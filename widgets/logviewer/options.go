@@ -0,0 +1,228 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logviewer
+
+// options.go contains configurable options for LogViewer.
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	maxLines      int
+	levelFn       LevelFn
+	levelCellOpts map[Level][]cell.Option
+	matchCellOpts []cell.Option
+	follow        bool
+
+	keyFollow    keyboard.Key
+	keySearch    keyboard.Key
+	keyNextMatch keyboard.Key
+	keyPrevMatch keyboard.Key
+	keyUp        keyboard.Key
+	keyDown      keyboard.Key
+	keyPgUp      keyboard.Key
+	keyPgDown    keyboard.Key
+
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		maxLines: DefaultMaxLines,
+		levelFn:  DetectLevel,
+		levelCellOpts: map[Level][]cell.Option{
+			LevelDebug: {cell.FgColor(cell.ColorGray)},
+			LevelWarn:  {cell.FgColor(cell.ColorYellow)},
+			LevelError: {cell.FgColor(cell.ColorRed)},
+		},
+		matchCellOpts: []cell.Option{
+			cell.FgColor(cell.ColorBlack),
+			cell.BgColor(cell.ColorYellow),
+		},
+		follow:          true,
+		keyFollow:       DefaultKeyFollow,
+		keySearch:       DefaultKeySearch,
+		keyNextMatch:    DefaultKeyNextMatch,
+		keyPrevMatch:    DefaultKeyPrevMatch,
+		keyUp:           DefaultKeyUp,
+		keyDown:         DefaultKeyDown,
+		keyPgUp:         DefaultKeyPageUp,
+		keyPgDown:       DefaultKeyPageDown,
+		mouseUpButton:   DefaultMouseButtonUp,
+		mouseDownButton: DefaultMouseButtonDown,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got, min := o.maxLines, 1; got < min {
+		return fmt.Errorf("invalid MaxLines %d, must be %d <= MaxLines", got, min)
+	}
+	if o.levelFn == nil {
+		return errors.New("the LevelDetector option requires a non-nil function")
+	}
+
+	keys := map[keyboard.Key]bool{
+		o.keyFollow:    true,
+		o.keySearch:    true,
+		o.keyNextMatch: true,
+		o.keyPrevMatch: true,
+		o.keyUp:        true,
+		o.keyDown:      true,
+		o.keyPgUp:      true,
+		o.keyPgDown:    true,
+	}
+	if len(keys) != 8 {
+		return fmt.Errorf("invalid keys: %s, %s, %s, %s, %s, %s, %s, %s, the keys must be unique", o.keyFollow, o.keySearch, o.keyNextMatch, o.keyPrevMatch, o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown)
+	}
+	if keys[keyboard.KeyEsc] {
+		return fmt.Errorf("the %s key is reserved for clearing the search", keyboard.KeyEsc)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid scroll mouse buttons: %s, %s, the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// DefaultMaxLines is the default value for the MaxLines option.
+const DefaultMaxLines = 10000
+
+// MaxLines sets the maximum number of lines kept by the LogViewer. When more
+// lines are written, the oldest lines are discarded. Must be a positive
+// integer.
+// Defaults to DefaultMaxLines.
+func MaxLines(lines int) Option {
+	return option(func(opts *options) {
+		opts.maxLines = lines
+	})
+}
+
+// LevelFn determines the severity level of a line.
+// Must be thread-safe.
+type LevelFn func(line string) Level
+
+// LevelDetector sets the function that determines the severity level of each
+// written line.
+// Defaults to DetectLevel.
+func LevelDetector(fn LevelFn) Option {
+	return option(func(opts *options) {
+		opts.levelFn = fn
+	})
+}
+
+// LevelCellOpts sets the cell options on the lines with the specified level.
+// Defaults to gray debug lines, yellow warnings and red errors, the other
+// lines are drawn with the default cell options.
+func LevelCellOpts(l Level, co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.levelCellOpts[l] = co
+	})
+}
+
+// MatchCellOpts sets the cell options on the text that matches the search
+// query. These are applied on top of the cell options of the line.
+// Defaults to black text on a yellow background.
+func MatchCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.matchCellOpts = co
+	})
+}
+
+// Paused starts the LogViewer with the follow mode turned off, i.e. new lines
+// don't scroll the displayed lines.
+// Defaults to following the newest lines.
+func Paused() Option {
+	return option(func(opts *options) {
+		opts.follow = false
+	})
+}
+
+// The default keys that control the follow mode and the search.
+const (
+	DefaultKeyFollow    = 'f'
+	DefaultKeySearch    = '/'
+	DefaultKeyNextMatch = 'n'
+	DefaultKeyPrevMatch = 'N'
+)
+
+// SearchKeys configures the keyboard keys that toggle the follow mode, start
+// a new search and move to the next (newer) and the previous (older) match.
+// The keys must be unique and must not collide with the ScrollKeys.
+func SearchKeys(follow, search, nextMatch, prevMatch keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyFollow = follow
+		opts.keySearch = search
+		opts.keyNextMatch = nextMatch
+		opts.keyPrevMatch = prevMatch
+	})
+}
+
+// The default keys that scroll the lines.
+const (
+	DefaultKeyUp       = keyboard.KeyArrowUp
+	DefaultKeyDown     = keyboard.KeyArrowDown
+	DefaultKeyPageUp   = keyboard.KeyPgUp
+	DefaultKeyPageDown = keyboard.KeyPgDn
+)
+
+// ScrollKeys configures the keyboard keys that scroll the lines by one line
+// and by one page. The keys must be unique and must not collide with the
+// SearchKeys.
+func ScrollKeys(up, down, pageUp, pageDown keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyPgUp = pageUp
+		opts.keyPgDown = pageDown
+	})
+}
+
+// The default mouse buttons that scroll the lines.
+const (
+	DefaultMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// ScrollMouseButtons configures the mouse buttons that scroll the lines by
+// one line. The buttons must be unique.
+func ScrollMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
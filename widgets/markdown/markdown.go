@@ -0,0 +1,342 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package markdown contains a widget that displays markdown text.
+package markdown
+
+import (
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Markdown displays text formatted with markdown.
+//
+// Supports a subset of CommonMark: headings, paragraphs, bold and italic
+// text, inline code and fenced code blocks, links, unordered and ordered
+// lists including nested lists, block quotes and thematic breaks. Other
+// markdown is displayed as plain text. The text is wrapped at words to the
+// width of the widget, code blocks are wrapped at runes.
+//
+// When focused, the content can be scrolled using the keyboard and the
+// mouse.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Markdown struct {
+	// blocks are the parsed blocks of the markdown text.
+	blocks []block
+
+	// lines are the blocks rendered into lines of cells.
+	lines [][]*buffer.Cell
+	// linesWidth is the width the lines were rendered for, zero if the lines
+	// must be rendered again.
+	linesWidth int
+
+	// offset is the index of the first displayed line.
+	offset int
+	// lastHeight is the height of the canvas as of the last call to Draw.
+	lastHeight int
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the Markdown.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Markdown widget.
+func New(opts ...Option) (*Markdown, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Markdown{
+		opts: opt,
+	}, nil
+}
+
+// SetText replaces the displayed content with the markdown text and scrolls
+// to the top.
+func (m *Markdown) SetText(text string) {
+	blocks := parse(text)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer m.dirty.Mark()
+	m.blocks = blocks
+	m.linesWidth = 0
+	m.offset = 0
+}
+
+// sanitize replaces tabs with spaces and removes other characters that
+// cannot be displayed, keeping newlines.
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if err := wrap.ValidText(string(r)); err != nil {
+			return -1
+		}
+		return r
+	}, strings.ReplaceAll(text, "\t", "    "))
+}
+
+// inlineCells converts the inline markdown into cells with the base cell
+// options.
+func (m *Markdown) inlineCells(text string, base []cell.Option) []*buffer.Cell {
+	var res []*buffer.Cell
+	for _, s := range parseInline(sanitize(text)) {
+		opts := append([]cell.Option{}, base...)
+		if s.bold {
+			opts = append(opts, cell.Bold())
+		}
+		if s.italic {
+			opts = append(opts, cell.Italic())
+		}
+		if s.code {
+			opts = append(opts, m.opts.codeCellOpts...)
+		}
+		if s.link != "" {
+			opts = append(opts, m.opts.linkCellOpts...)
+		}
+		co := cell.NewOptions(opts...)
+		co.Link = s.link
+		res = append(res, buffer.NewCells(s.text, co)...)
+	}
+	return res
+}
+
+// wrapBlock wraps the cells of a block to the width and prepends the
+// prefixes, the first prefix to the first line and the other prefix to the
+// remaining lines. Both prefixes must have the same width.
+func wrapBlock(cells []*buffer.Cell, width int, mode wrap.Mode, first, other []*buffer.Cell) ([][]*buffer.Cell, error) {
+	available := width - len(first)
+	if available < 1 {
+		available = 1
+	}
+
+	wrapped := [][]*buffer.Cell{nil}
+	if len(cells) > 0 {
+		var err error
+		wrapped, err = wrap.Cells(cells, available, mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var res [][]*buffer.Cell
+	for i, l := range wrapped {
+		prefix := other
+		if i == 0 {
+			prefix = first
+		}
+		res = append(res, append(append([]*buffer.Cell{}, prefix...), l...))
+	}
+	return res, nil
+}
+
+// render renders the blocks into lines of the specified width.
+func (m *Markdown) render(width int) ([][]*buffer.Cell, error) {
+	var lines [][]*buffer.Cell
+	for i, b := range m.blocks {
+		if i > 0 && !(b.kind == blockListItem && m.blocks[i-1].kind == blockListItem) {
+			lines = append(lines, nil) // Empty line between blocks.
+		}
+
+		var (
+			cells        []*buffer.Cell
+			mode         = wrap.AtWords
+			first, other []*buffer.Cell
+		)
+		switch b.kind {
+		case blockRule:
+			lines = append(lines, buffer.NewCells(strings.Repeat("─", width), cell.FgColor(cell.ColorGray)))
+			continue
+
+		case blockHeading:
+			cells = m.inlineCells(b.text, m.opts.headingCellOpts)
+
+		case blockCode:
+			cells = buffer.NewCells(sanitize(b.text), m.opts.codeCellOpts...)
+			mode = wrap.AtRunes
+			first = buffer.NewCells("  ")
+			other = first
+
+		case blockQuote:
+			cells = m.inlineCells(b.text, m.opts.quoteCellOpts)
+			first = buffer.NewCells("│ ", m.opts.quoteCellOpts...)
+			other = first
+
+		case blockListItem:
+			marker := b.marker
+			if marker == "" {
+				marker = m.opts.bullet
+			}
+			marker = strings.Repeat("  ", b.level) + marker + " "
+			cells = m.inlineCells(b.text, nil)
+			first = buffer.NewCells(marker)
+			other = buffer.NewCells(strings.Repeat(" ", runewidth.StringWidth(marker)))
+
+		default:
+			cells = m.inlineCells(b.text, nil)
+		}
+
+		wrapped, err := wrapBlock(cells, width, mode, first, other)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, wrapped...)
+	}
+	return lines, nil
+}
+
+// Draw draws the Markdown widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (m *Markdown) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	needAr, err := area.FromSize(m.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	if width := ar.Dx(); width != m.linesWidth {
+		lines, err := m.render(width)
+		if err != nil {
+			return err
+		}
+		m.lines = lines
+		m.linesWidth = width
+	}
+	m.lastHeight = ar.Dy()
+	m.clampOffset()
+
+	for y := 0; y < ar.Dy() && m.offset+y < len(m.lines); y++ {
+		cur := image.Point{ar.Min.X, ar.Min.Y + y}
+		for _, c := range m.lines[m.offset+y] {
+			if cur.X+runewidth.RuneWidth(c.Rune) > ar.Max.X {
+				break // Skip cells that don't fit, e.g. after deep indentation.
+			}
+			cells, err := cvs.SetCell(cur, c.Rune, c.Opts)
+			if err != nil {
+				return err
+			}
+			cur.X += cells
+		}
+	}
+	return nil
+}
+
+// clampOffset ensures the offset doesn't scroll past the last line.
+// The caller must hold m.mu.
+func (m *Markdown) clampOffset() {
+	if max := len(m.lines) - m.lastHeight; m.offset > max {
+		m.offset = max
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// scroll scrolls the content by the specified number of lines, a negative
+// number scrolls up.
+// The caller must hold m.mu.
+func (m *Markdown) scroll(by int) {
+	m.offset += by
+	m.clampOffset()
+}
+
+// pageLines returns the number of lines to scroll by when paging.
+// The caller must hold m.mu.
+func (m *Markdown) pageLines() int {
+	if m.lastHeight > 1 {
+		return m.lastHeight
+	}
+	return 1
+}
+
+// Keyboard scrolls the content.
+// Implements widgetapi.Widget.Keyboard.
+func (m *Markdown) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch k.Key {
+	case m.opts.keyUp:
+		m.scroll(-1)
+	case m.opts.keyDown:
+		m.scroll(1)
+	case m.opts.keyPgUp:
+		m.scroll(-m.pageLines())
+	case m.opts.keyPgDown:
+		m.scroll(m.pageLines())
+	}
+	return nil
+}
+
+// Mouse scrolls the content.
+// Implements widgetapi.Widget.Mouse.
+func (m *Markdown) Mouse(ev *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch ev.Button {
+	case m.opts.mouseUpButton:
+		m.scroll(-1)
+	case m.opts.mouseDownButton:
+		m.scroll(1)
+	}
+	return nil
+}
+
+// minSize determines the minimum required size to draw the widget.
+func (m *Markdown) minSize() image.Point {
+	return image.Point{1, 1}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (m *Markdown) Options() widgetapi.Options {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  m.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (m *Markdown) SetDirtyFunc(markDirty func()) {
+	m.dirty.Set(markDirty)
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// pressKeys returns a function that delivers the keyboard events.
+func pressKeys(keys ...keyboard.Key) func(*Markdown) error {
+	return func(m *Markdown) error {
+		for _, k := range keys {
+			if err := m.Keyboard(&terminalapi.Keyboard{Key: k}, &widgetapi.EventMeta{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		text   string
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events  func(*Markdown) error
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc: "fails on a wide bullet",
+			opts: []Option{
+				Bullet("->"),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate keys",
+			opts: []Option{
+				ScrollKeys('a', 'a', 'b', 'c'),
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on duplicate mouse buttons",
+			opts: []Option{
+				ScrollMouseButtons(mouse.ButtonLeft, mouse.ButtonLeft),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "draws headings and wrapped paragraphs",
+			canvas: image.Rect(0, 0, 6, 4),
+			text:   "# Hi\nab cd ef",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "Hi", image.Point{0, 0}, draw.TextCellOpts(
+					cell.Bold(),
+					cell.FgColor(cell.ColorCyan),
+				))
+				testdraw.MustText(c, "ab cd", image.Point{0, 2})
+				testdraw.MustText(c, "ef", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws inline styles and links",
+			canvas: image.Rect(0, 0, 7, 1),
+			text:   "**b***i*`c`[l](u)",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "b", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "i", image.Point{1, 0}, draw.TextCellOpts(cell.Italic()))
+				testdraw.MustText(c, "c", image.Point{2, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+				link := cell.NewOptions(cell.FgColor(cell.ColorBlue), cell.Underline())
+				link.Link = "u"
+				testdraw.MustText(c, "l", image.Point{3, 0}, draw.TextCellOpts(link))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws lists with hanging indentation",
+			canvas: image.Rect(0, 0, 8, 3),
+			text:   "- one two\n  1. x",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "• one", image.Point{0, 0})
+				testdraw.MustText(c, "  two", image.Point{0, 1})
+				testdraw.MustText(c, "  1. x", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws code blocks, quotes and rules",
+			canvas: image.Rect(0, 0, 5, 7),
+			text:   "```\nabcd\n```\n> q\n\n***",
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				code := draw.TextCellOpts(cell.FgColor(cell.ColorYellow))
+				testdraw.MustText(c, "  ", image.Point{0, 0})
+				testdraw.MustText(c, "  ", image.Point{0, 1})
+				testdraw.MustText(c, "abc", image.Point{2, 0}, code)
+				testdraw.MustText(c, "d", image.Point{2, 1}, code)
+				quote := draw.TextCellOpts(cell.FgColor(cell.ColorGray))
+				testdraw.MustText(c, "│ q", image.Point{0, 3}, quote)
+				testdraw.MustText(c, "─────", image.Point{0, 5}, quote)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls with the keyboard",
+			canvas: image.Rect(0, 0, 3, 2),
+			text:   "a\n\nb\n\nc",
+			events: pressKeys(keyboard.KeyPgDn, keyboard.KeyPgDn, keyboard.KeyArrowUp),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "b", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls with the mouse",
+			canvas: image.Rect(0, 0, 3, 2),
+			text:   "a\n\nb",
+			events: func(m *Markdown) error {
+				return m.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelDown}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "setting text scrolls to the top",
+			canvas: image.Rect(0, 0, 3, 2),
+			text:   "a\n\nb",
+			events: func(m *Markdown) error {
+				if err := pressKeys(keyboard.KeyArrowDown)(m); err != nil {
+					return err
+				}
+				m.SetText("x\n\ny")
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "x", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			m, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			m.SetText(tc.text)
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := m.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if tc.events != nil {
+				if err := tc.events(m); err != nil {
+					t.Fatalf("events => unexpected error: %v", err)
+				}
+				c, err = canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := m.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	got := m.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary markdowndemo displays a Markdown widget with a runbook.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/markdown"
+)
+
+// runbook is the displayed markdown text.
+const runbook = `# Runbook: high error rate
+
+The **frontend** started returning errors to more than *1%* of the requests.
+Follow the steps below and record your findings in the
+[incident log](https://example.com/incidents).
+
+## Triage
+
+1. Check the dashboards of the backends.
+2. Look for recent deployments:
+   - rollouts of the ` + "`frontend`" + ` service
+   - configuration pushes
+3. Roll back if the errors started after a deployment.
+
+> Don't restart all the replicas at once, the caches take up to ten minutes
+> to warm up.
+
+---
+
+## Useful commands
+
+` + "```" + `
+kubectl -n prod rollout history deployment/frontend
+kubectl -n prod rollout undo deployment/frontend
+` + "```" + `
+`
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	md, err := markdown.New()
+	if err != nil {
+		panic(err)
+	}
+	md.SetText(runbook)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(md),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+// options.go contains configurable options for Markdown.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	headingCellOpts []cell.Option
+	codeCellOpts    []cell.Option
+	linkCellOpts    []cell.Option
+	quoteCellOpts   []cell.Option
+	bullet          string
+
+	keyUp           keyboard.Key
+	keyDown         keyboard.Key
+	keyPgUp         keyboard.Key
+	keyPgDown       keyboard.Key
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		headingCellOpts: []cell.Option{cell.Bold(), cell.FgColor(cell.ColorCyan)},
+		codeCellOpts:    []cell.Option{cell.FgColor(cell.ColorYellow)},
+		linkCellOpts:    []cell.Option{cell.FgColor(cell.ColorBlue), cell.Underline()},
+		quoteCellOpts:   []cell.Option{cell.FgColor(cell.ColorGray)},
+		bullet:          DefaultBullet,
+		keyUp:           DefaultKeyUp,
+		keyDown:         DefaultKeyDown,
+		keyPgUp:         DefaultKeyPageUp,
+		keyPgDown:       DefaultKeyPageDown,
+		mouseUpButton:   DefaultMouseButtonUp,
+		mouseDownButton: DefaultMouseButtonDown,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got := runewidth.StringWidth(o.bullet); got != 1 {
+		return fmt.Errorf("invalid Bullet %q, must occupy exactly one cell, got %d", o.bullet, got)
+	}
+	keys := map[keyboard.Key]bool{
+		o.keyUp:     true,
+		o.keyDown:   true,
+		o.keyPgUp:   true,
+		o.keyPgDown: true,
+	}
+	if len(keys) != 4 {
+		return fmt.Errorf("invalid scroll keys: %s, %s, %s, %s, the keys must be unique", o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid scroll mouse buttons: %s, %s, the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// HeadingCellOpts sets the cell options on the text of the headings.
+// Defaults to bold cyan text.
+func HeadingCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.headingCellOpts = co
+	})
+}
+
+// CodeCellOpts sets the cell options on the text of the code blocks and the
+// inline code.
+// Defaults to yellow text.
+func CodeCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.codeCellOpts = co
+	})
+}
+
+// LinkCellOpts sets the cell options on the text of the links. The cells
+// also link to the URL, so terminals that support the OSC 8 escape sequence
+// display them as clickable hyperlinks.
+// Defaults to blue underlined text.
+func LinkCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.linkCellOpts = co
+	})
+}
+
+// QuoteCellOpts sets the cell options on the text of the block quotes.
+// Defaults to gray text.
+func QuoteCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.quoteCellOpts = co
+	})
+}
+
+// DefaultBullet is the default value for the Bullet option.
+const DefaultBullet = "•"
+
+// Bullet sets the text displayed in front of the items of unordered lists.
+// Must occupy exactly one cell.
+// Defaults to DefaultBullet.
+func Bullet(b string) Option {
+	return option(func(opts *options) {
+		opts.bullet = b
+	})
+}
+
+// The default keys that scroll the content.
+const (
+	DefaultKeyUp       = keyboard.KeyArrowUp
+	DefaultKeyDown     = keyboard.KeyArrowDown
+	DefaultKeyPageUp   = keyboard.KeyPgUp
+	DefaultKeyPageDown = keyboard.KeyPgDn
+)
+
+// ScrollKeys configures the keyboard keys that scroll the content by one line
+// and by one page. The keys must be unique.
+func ScrollKeys(up, down, pageUp, pageDown keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyPgUp = pageUp
+		opts.keyPgDown = pageDown
+	})
+}
+
+// The default mouse buttons that scroll the content.
+const (
+	DefaultMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// ScrollMouseButtons configures the mouse buttons that scroll the content by
+// one line. The buttons must be unique.
+func ScrollMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
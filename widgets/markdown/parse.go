@@ -0,0 +1,290 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+// parse.go contains code that parses the supported subset of markdown.
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// blockKind identifies the kind of a markdown block.
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockListItem
+	blockCode
+	blockQuote
+	blockRule
+)
+
+// block is one block of the markdown document.
+type block struct {
+	kind blockKind
+	// level is the level of a heading or the nesting depth of a list item.
+	level int
+	// marker is the bullet or the number of a list item.
+	marker string
+	// text is the inline markdown of the block or the lines of a code block.
+	text string
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+	bulletRe  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRe = regexp.MustCompile(`^(\s*)(\d{1,9})[.)]\s+(.*)$`)
+	quoteRe   = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	ruleRe    = regexp.MustCompile(`^\s{0,3}(([-*_])\s*){3,}$`)
+	fenceRe   = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+// isRule determines if the line is a thematic break, i.e. at least three of
+// the same characters out of '-', '*' and '_'.
+func isRule(line string) bool {
+	if !ruleRe.MatchString(line) {
+		return false
+	}
+	s := strings.Join(strings.Fields(line), "")
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// parse splits the markdown text into blocks.
+func parse(md string) []block {
+	var (
+		blocks []block
+		// cur is the block that can be continued by the next line.
+		cur *block
+		// fence is the fence of the open code block.
+		fence string
+		code  []string
+	)
+	add := func(b block) {
+		blocks = append(blocks, b)
+		cur = &blocks[len(blocks)-1]
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				add(block{kind: blockCode, text: strings.Join(code, "\n")})
+				cur = nil
+				fence, code = "", nil
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			fence = m[1]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		if isRule(line) {
+			add(block{kind: blockRule})
+			cur = nil
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			add(block{kind: blockHeading, level: len(m[1]), text: m[2]})
+			cur = nil
+			continue
+		}
+		if m := quoteRe.FindStringSubmatch(line); m != nil {
+			if cur != nil && cur.kind == blockQuote {
+				cur.text = joinLines(cur.text, m[1])
+				continue
+			}
+			add(block{kind: blockQuote, text: m[1]})
+			continue
+		}
+		if m := bulletRe.FindStringSubmatch(line); m != nil {
+			add(block{kind: blockListItem, level: indentLevel(m[1]), text: m[2]})
+			continue
+		}
+		if m := orderedRe.FindStringSubmatch(line); m != nil {
+			add(block{kind: blockListItem, level: indentLevel(m[1]), marker: fmt.Sprintf("%s.", m[2]), text: m[3]})
+			continue
+		}
+
+		if cur != nil && cur.kind != blockRule && cur.kind != blockHeading {
+			// Lazy continuation of a paragraph, a list item or a quote.
+			cur.text = joinLines(cur.text, strings.TrimSpace(line))
+			continue
+		}
+		add(block{kind: blockParagraph, text: strings.TrimSpace(line)})
+	}
+
+	if fence != "" {
+		// An unclosed code block extends to the end of the document.
+		blocks = append(blocks, block{kind: blockCode, text: strings.Join(code, "\n")})
+	}
+	return blocks
+}
+
+// joinLines joins two lines of the same block.
+func joinLines(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + " " + line
+}
+
+// indentLevel determines the nesting level of a list item from its
+// indentation, two spaces or a tab per level.
+func indentLevel(indent string) int {
+	width := 0
+	for _, r := range indent {
+		if r == '\t' {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width / 2
+}
+
+// span is a part of the inline text with the same style.
+type span struct {
+	text   string
+	bold   bool
+	italic bool
+	code   bool
+	link   string
+}
+
+// parseInline splits the inline markdown into spans with the same style.
+// Supports bold, italic, code spans, links and backslash escapes.
+func parseInline(text string) []span {
+	var (
+		spans        []span
+		buf          strings.Builder
+		bold, italic bool
+	)
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		spans = append(spans, span{text: buf.String(), bold: bold, italic: italic})
+		buf.Reset()
+	}
+
+	runes := []rune(text)
+	at := func(i int) rune {
+		if i < 0 || i >= len(runes) {
+			return ' '
+		}
+		return runes[i]
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && (unicode.IsPunct(at(i+1)) || unicode.IsSymbol(at(i+1))):
+			i++
+			buf.WriteRune(runes[i])
+
+		case r == '`':
+			end := indexRune(runes, i+1, '`')
+			if end < 0 {
+				buf.WriteRune(r)
+				continue
+			}
+			flush()
+			spans = append(spans, span{text: string(runes[i+1 : end]), code: true})
+			i = end
+
+		case r == '[':
+			label, url, end, ok := parseLink(runes, i)
+			if !ok {
+				buf.WriteRune(r)
+				continue
+			}
+			flush()
+			spans = append(spans, span{text: label, bold: bold, italic: italic, link: url})
+			i = end
+
+		case (r == '*' || r == '_') && at(i+1) == r:
+			if r == '_' && !isDelimiter(at(i-1), at(i+2), bold) {
+				buf.WriteString(string(runes[i : i+2]))
+				i++
+				continue
+			}
+			flush()
+			bold = !bold
+			i++
+
+		case r == '*' || r == '_':
+			if r == '_' && !isDelimiter(at(i-1), at(i+1), italic) {
+				buf.WriteRune(r)
+				continue
+			}
+			flush()
+			italic = !italic
+
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	flush()
+	return spans
+}
+
+// isDelimiter determines if an underscore between the runes starts or, when
+// the emphasis is active, ends emphasis. Underscores inside words, e.g. in
+// snake_case, don't.
+func isDelimiter(before, after rune, active bool) bool {
+	if active {
+		return !isWordRune(after)
+	}
+	return !isWordRune(before)
+}
+
+// isWordRune determines if the rune is a part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// indexRune returns the index of the first occurrence of the rune at or
+// after the start or -1 if not found.
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseLink parses a link in the form [label](url) starting at the index of
+// the opening bracket. Returns the index of the closing parenthesis.
+func parseLink(runes []rune, start int) (label, url string, end int, ok bool) {
+	closeLabel := indexRune(runes, start+1, ']')
+	if closeLabel < 0 || closeLabel+1 >= len(runes) || runes[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+	closeURL := indexRune(runes, closeLabel+2, ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+	return string(runes[start+1 : closeLabel]), strings.TrimSpace(string(runes[closeLabel+2 : closeURL])), closeURL, true
+}
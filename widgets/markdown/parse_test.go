@@ -0,0 +1,161 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc string
+		md   string
+		want []block
+	}{
+		{
+			desc: "empty text",
+			md:   "",
+		},
+		{
+			desc: "headings and paragraphs",
+			md:   "# Title #\n\nfirst\nline\n\n### Sub\ntext",
+			want: []block{
+				{kind: blockHeading, level: 1, text: "Title"},
+				{kind: blockParagraph, text: "first line"},
+				{kind: blockHeading, level: 3, text: "Sub"},
+				{kind: blockParagraph, text: "text"},
+			},
+		},
+		{
+			desc: "hash without space isn't a heading",
+			md:   "#tag",
+			want: []block{
+				{kind: blockParagraph, text: "#tag"},
+			},
+		},
+		{
+			desc: "nested and ordered lists",
+			md:   "- a\n  continued\n  * b\n+ c\n1. one\n10) ten",
+			want: []block{
+				{kind: blockListItem, text: "a continued"},
+				{kind: blockListItem, level: 1, text: "b"},
+				{kind: blockListItem, text: "c"},
+				{kind: blockListItem, marker: "1.", text: "one"},
+				{kind: blockListItem, marker: "10.", text: "ten"},
+			},
+		},
+		{
+			desc: "fenced code blocks keep the lines",
+			md:   "```go\nif a {\n\t# not heading\n}\n```\n~~~\nunclosed",
+			want: []block{
+				{kind: blockCode, text: "if a {\n\t# not heading\n}"},
+				{kind: blockCode, text: "unclosed"},
+			},
+		},
+		{
+			desc: "block quotes and rules",
+			md:   "> quoted\n> more\nlazy\n\n---\n* * *\n-- x",
+			want: []block{
+				{kind: blockQuote, text: "quoted more lazy"},
+				{kind: blockRule},
+				{kind: blockRule},
+				{kind: blockParagraph, text: "-- x"},
+			},
+		},
+		{
+			desc: "mixed rule characters aren't a rule",
+			md:   "-*-",
+			want: []block{
+				{kind: blockParagraph, text: "-*-"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := parse(tc.md)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("parse => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		desc string
+		text string
+		want []span
+	}{
+		{
+			desc: "plain text",
+			text: "hello",
+			want: []span{{text: "hello"}},
+		},
+		{
+			desc: "bold and italic",
+			text: "a **b** *c* __d__ _e_",
+			want: []span{
+				{text: "a "},
+				{text: "b", bold: true},
+				{text: " "},
+				{text: "c", italic: true},
+				{text: " "},
+				{text: "d", bold: true},
+				{text: " "},
+				{text: "e", italic: true},
+			},
+		},
+		{
+			desc: "underscores inside words",
+			text: "snake_case_name",
+			want: []span{{text: "snake_case_name"}},
+		},
+		{
+			desc: "code spans aren't formatted",
+			text: "run `a*b*c` now",
+			want: []span{
+				{text: "run "},
+				{text: "a*b*c", code: true},
+				{text: " now"},
+			},
+		},
+		{
+			desc: "links",
+			text: "see [the docs](http://x.io) or [bad](link",
+			want: []span{
+				{text: "see "},
+				{text: "the docs", link: "http://x.io"},
+				{text: " or [bad](link"},
+			},
+		},
+		{
+			desc: "escapes and unclosed code",
+			text: "\\*lit\\* `open",
+			want: []span{{text: "*lit* `open"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := parseInline(tc.text)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("parseInline => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package menubar contains a widget that displays a menu bar with pull-down
+// menus.
+package menubar
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Item is an entry in a pull-down menu.
+type Item struct {
+	// Label is the text of the item.
+	Label string
+
+	// Action is called when the item is selected. Can be nil.
+	//
+	// The function must be thread-safe as the keyboard and mouse events come
+	// from a separate goroutine. The MenuBar isn't locked while the function
+	// executes, so it can read from or modify the MenuBar and the containers.
+	// Any error returned by the function is reported to the error handler.
+	Action func() error
+}
+
+// Menu is a top-level entry on the menu bar that opens a pull-down menu.
+type Menu struct {
+	// Label is the text of the entry on the bar.
+	Label string
+	// Items are the items of the pull-down menu.
+	Items []Item
+}
+
+// Overlay displays the pull-down menus above the layout of the dashboard.
+// Implemented by *container.Container.
+type Overlay interface {
+	// ShowLayer displays a floating layer, see container.ShowLayer.
+	ShowLayer(id string, z int, ar image.Rectangle, opts ...container.Option) error
+	// HideLayer removes the floating layer, see container.HideLayer.
+	HideLayer(id string)
+}
+
+// MenuBar displays the labels of the menus on a single row. A pull-down menu
+// with the items of the menu is displayed as a layer of the container below
+// the label of the menu, see Attach.
+//
+// A menu is opened by clicking on its label or by pressing Enter, Space or
+// the Down arrow key while the menu bar is focused. The Left and Right arrow
+// keys move between the menus, the Up and Down arrow keys highlight an item,
+// Enter selects it and Esc closes the menu. Clicking on an item selects it,
+// clicking anywhere outside of the pull-down menu closes it.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type MenuBar struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// menus are the displayed menus.
+	menus []Menu
+	// overlay displays the pull-down menus, nil until Attach is called.
+	overlay Overlay
+
+	// active is the index of the highlighted menu on the bar.
+	active int
+	// open indicates that the pull-down of the active menu should be
+	// displayed.
+	open bool
+	// highlighted is the index of the highlighted item in the open menu.
+	highlighted int
+	// shown is the pull-down menu displayed on the overlay, nil if none.
+	shown *pulldown
+
+	// labelAreas are the areas of the labels of the menus on the canvas the
+	// last time Draw was called.
+	labelAreas []image.Rectangle
+	// area is the area of the canvas on the terminal the last time Draw was
+	// called.
+	area image.Rectangle
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new MenuBar with the provided menus.
+func New(menus []Menu, opts ...Option) (*MenuBar, error) {
+	if len(menus) == 0 {
+		return nil, errors.New("a menu bar requires at least one menu")
+	}
+	var ms []Menu
+	for _, m := range menus {
+		if err := validLabel(m.Label); err != nil {
+			return nil, fmt.Errorf("invalid menu: %v", err)
+		}
+		if len(m.Items) == 0 {
+			return nil, fmt.Errorf("invalid menu %q, it requires at least one item", m.Label)
+		}
+		for _, it := range m.Items {
+			if err := validLabel(it.Label); err != nil {
+				return nil, fmt.Errorf("invalid item in menu %q: %v", m.Label, err)
+			}
+		}
+		ms = append(ms, Menu{
+			Label: m.Label,
+			Items: append([]Item(nil), m.Items...),
+		})
+	}
+
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &MenuBar{
+		menus: ms,
+		opts:  opt,
+	}, nil
+}
+
+// validLabel validates the label of a menu or an item.
+func validLabel(label string) error {
+	if label == "" {
+		return errors.New("the label cannot be empty")
+	}
+	if err := wrap.ValidText(label); err != nil {
+		return fmt.Errorf("invalid label %q: %v", label, err)
+	}
+	if strings.ContainsRune(label, '\n') {
+		return fmt.Errorf("invalid label %q: newline characters aren't allowed", label)
+	}
+	return nil
+}
+
+// Attach sets the overlay that displays the pull-down menus, typically the
+// root container of the dashboard. Must be called after the container that
+// holds the MenuBar was created, the menus cannot be opened before that.
+func (mb *MenuBar) Attach(o Overlay) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.overlay = o
+}
+
+// IsOpen asserts whether a pull-down menu is displayed.
+func (mb *MenuBar) IsOpen() bool {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	return mb.open
+}
+
+// Close closes the open pull-down menu.
+// Does nothing if no menu is open.
+func (mb *MenuBar) Close() error {
+	mb.mu.Lock()
+	mb.closeMenu()
+	mb.mu.Unlock()
+	return mb.sync(nil)
+}
+
+// openMenu opens the pull-down of the menu at the index.
+// Caller must hold mb.mu.
+func (mb *MenuBar) openMenu(index int) {
+	defer mb.markDirty()
+	mb.active = index
+	mb.open = true
+	mb.highlighted = 0
+}
+
+// markDirty reports a change of the content of the MenuBar and of the
+// displayed pull-down menu, which displays the state of the MenuBar.
+// Caller must hold mb.mu.
+func (mb *MenuBar) markDirty() {
+	mb.dirty.Mark()
+	if mb.shown != nil {
+		mb.shown.dirty.Mark()
+	}
+}
+
+// closeMenu closes the open pull-down menu.
+// Caller must hold mb.mu.
+func (mb *MenuBar) closeMenu() {
+	defer mb.markDirty()
+	mb.open = false
+}
+
+// choose closes the open menu and returns the action of the highlighted item.
+// Caller must hold mb.mu.
+func (mb *MenuBar) choose() func() error {
+	action := mb.menus[mb.active].Items[mb.highlighted].Action
+	mb.closeMenu()
+	return action
+}
+
+// sync displays the pull-down of the open menu on the overlay or hides it
+// when no menu is open. Then calls the action, if not nil.
+// Must be called without holding mb.mu, since the overlay locks the container
+// which holds its lock while drawing the widget.
+func (mb *MenuBar) sync(action func() error) error {
+	mb.mu.Lock()
+	overlay, shown := mb.overlay, mb.shown
+	open, active := mb.open, mb.active
+	id, z := mb.opts.layerID, mb.opts.layerZIndex
+	var ar image.Rectangle
+	if open {
+		ar = mb.pulldownArea()
+	}
+	mb.mu.Unlock()
+
+	switch {
+	case open && overlay == nil:
+		mb.mu.Lock()
+		mb.closeMenu()
+		mb.mu.Unlock()
+		return errors.New("cannot open the menu, the MenuBar isn't attached to an overlay, see MenuBar.Attach")
+
+	case open && (shown == nil || shown.menu != active):
+		pd := &pulldown{mb: mb, menu: active}
+		if err := overlay.ShowLayer(id, z, ar, container.PlaceWidget(pd)); err != nil {
+			return err
+		}
+		mb.mu.Lock()
+		mb.shown = pd
+		mb.mu.Unlock()
+
+	case !open && shown != nil:
+		overlay.HideLayer(id)
+		mb.mu.Lock()
+		mb.shown = nil
+		mb.mu.Unlock()
+	}
+
+	if action == nil {
+		return nil
+	}
+	return action()
+}
+
+// pulldownArea returns the area of the terminal the pull-down of the active
+// menu occupies. It is placed below the label of the menu, with a border
+// around the items.
+// Caller must hold mb.mu.
+func (mb *MenuBar) pulldownArea() image.Rectangle {
+	width := 0
+	for _, it := range mb.menus[mb.active].Items {
+		if w := runewidth.StringWidth(it.Label); w > width {
+			width = w
+		}
+	}
+	// The border and one cell of padding on each side.
+	size := image.Point{width + 4, len(mb.menus[mb.active].Items) + 2}
+
+	start := image.Point{mb.area.Min.X, mb.area.Min.Y + 1}
+	if mb.active < len(mb.labelAreas) {
+		start.X += mb.labelAreas[mb.active].Min.X
+	}
+	return image.Rectangle{start, start.Add(size)}
+}
+
+// Draw draws the MenuBar widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (mb *MenuBar) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	mb.area = meta.Area
+	ar := cvs.Area()
+	row := image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+1)
+	if err := cvs.SetAreaCells(row, ' ', mb.opts.barCellOpts...); err != nil {
+		return err
+	}
+
+	mb.labelAreas = nil
+	x := row.Min.X
+	for i, m := range mb.menus {
+		if x >= row.Max.X {
+			break
+		}
+		// One cell of padding on each side of the label.
+		end := x + runewidth.StringWidth(m.Label) + 2
+		if end > row.Max.X {
+			end = row.Max.X
+		}
+		labelAr := image.Rect(x, row.Min.Y, end, row.Max.Y)
+		mb.labelAreas = append(mb.labelAreas, labelAr)
+
+		cOpts := mb.opts.barCellOpts
+		if i == mb.active && (mb.open || meta.Focused) {
+			cOpts = append(append([]cell.Option(nil), cOpts...), mb.opts.activeCellOpts...)
+		}
+		if err := cvs.SetAreaCells(labelAr, ' ', cOpts...); err != nil {
+			return err
+		}
+		if labelAr.Dx() > 2 {
+			if err := draw.Text(
+				cvs, m.Label, image.Point{labelAr.Min.X + 1, labelAr.Min.Y},
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextMaxX(labelAr.Max.X-1),
+				draw.TextCellOpts(cOpts...),
+			); err != nil {
+				return err
+			}
+		}
+		x = end
+	}
+	return nil
+}
+
+// keyboard processes the keyboard event.
+// Returns the action of the selected item or nil.
+func (mb *MenuBar) keyboard(k *terminalapi.Keyboard) func() error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if !mb.open {
+		switch k.Key {
+		case keyboard.KeyArrowLeft:
+			mb.active = (mb.active - 1 + len(mb.menus)) % len(mb.menus)
+			mb.markDirty()
+		case keyboard.KeyArrowRight:
+			mb.active = (mb.active + 1) % len(mb.menus)
+			mb.markDirty()
+		case keyboard.KeyEnter, keyboard.KeySpace, keyboard.KeyArrowDown:
+			mb.openMenu(mb.active)
+		}
+		return nil
+	}
+
+	switch k.Key {
+	case keyboard.KeyArrowLeft:
+		mb.openMenu((mb.active - 1 + len(mb.menus)) % len(mb.menus))
+	case keyboard.KeyArrowRight:
+		mb.openMenu((mb.active + 1) % len(mb.menus))
+	case keyboard.KeyArrowUp:
+		if mb.highlighted > 0 {
+			mb.highlighted--
+			mb.markDirty()
+		}
+	case keyboard.KeyArrowDown:
+		if mb.highlighted < len(mb.menus[mb.active].Items)-1 {
+			mb.highlighted++
+			mb.markDirty()
+		}
+	case keyboard.KeyEnter:
+		return mb.choose()
+	case keyboard.KeyEsc:
+		mb.closeMenu()
+	}
+	return nil
+}
+
+// Keyboard navigates the menus and selects items.
+// Implements widgetapi.Widget.Keyboard.
+func (mb *MenuBar) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return mb.sync(mb.keyboard(k))
+}
+
+// mouse processes the mouse event.
+func (mb *MenuBar) mouse(m *terminalapi.Mouse) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if m.Button != mouse.ButtonLeft {
+		return
+	}
+	for i, ar := range mb.labelAreas {
+		if !m.Position.In(ar) {
+			continue
+		}
+		if mb.open && mb.active == i {
+			mb.closeMenu()
+		} else {
+			mb.openMenu(i)
+		}
+		return
+	}
+}
+
+// Mouse opens and closes the menus whose labels were clicked.
+// Implements widgetapi.Widget.Mouse.
+func (mb *MenuBar) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	mb.mouse(m)
+	return mb.sync(nil)
+}
+
+// Options implements widgetapi.Widget.Options.
+func (mb *MenuBar) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (mb *MenuBar) SetDirtyFunc(markDirty func()) {
+	mb.dirty.Set(markDirty)
+}
@@ -0,0 +1,505 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menubar
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/event"
+	"github.com/mum4k/termdash/private/event/testevent"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// testMenus returns menus whose actions record the selected items.
+func testMenus(selected *[]string) []Menu {
+	item := func(label string) Item {
+		return Item{
+			Label: label,
+			Action: func() error {
+				*selected = append(*selected, label)
+				return nil
+			},
+		}
+	}
+	return []Menu{
+		{Label: "File", Items: []Item{item("New"), item("Quit")}},
+		{Label: "Edit", Items: []Item{item("Copy")}},
+	}
+}
+
+func TestMenuBar(t *testing.T) {
+	tests := []struct {
+		desc       string
+		menus      []Menu
+		opts       []Option
+		canvas     image.Rectangle
+		meta       *widgetapi.Meta
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+	}{
+		{
+			desc:       "fails without menus",
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on an empty menu label",
+			menus:      []Menu{{Label: "", Items: []Item{{Label: "a"}}}},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a menu without items",
+			menus:      []Menu{{Label: "File"}},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on an item label with a newline",
+			menus:      []Menu{{Label: "File", Items: []Item{{Label: "a\nb"}}}},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on an empty LayerID",
+			menus:      testMenus(nil),
+			opts:       []Option{LayerID("")},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws the labels of the menus",
+			menus:  testMenus(nil),
+			canvas: image.Rect(0, 0, 20, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(c, c.Area(), ' ')
+				testdraw.MustText(c, "File", image.Point{1, 0})
+				testdraw.MustText(c, "Edit", image.Point{7, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:  "highlights the active menu when focused",
+			menus: testMenus(nil),
+			opts: []Option{
+				BarCellOpts(cell.BgColor(cell.ColorBlue)),
+				ActiveCellOpts(cell.FgColor(cell.ColorRed)),
+			},
+			canvas: image.Rect(0, 0, 20, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				active := []cell.Option{cell.BgColor(cell.ColorBlue), cell.FgColor(cell.ColorRed)}
+				testcanvas.MustSetAreaCells(c, c.Area(), ' ', cell.BgColor(cell.ColorBlue))
+				testcanvas.MustSetAreaCells(c, image.Rect(0, 0, 6, 1), ' ', active...)
+				testdraw.MustText(c, "File", image.Point{1, 0}, draw.TextCellOpts(active...))
+				testdraw.MustText(c, "Edit", image.Point{7, 0}, draw.TextCellOpts(cell.BgColor(cell.ColorBlue)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates labels that don't fit",
+			menus:  testMenus(nil),
+			canvas: image.Rect(0, 0, 9, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(c, c.Area(), ' ')
+				testdraw.MustText(c, "File", image.Point{1, 0})
+				testdraw.MustText(c, "…", image.Point{7, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			mb, err := New(tc.menus, tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := mb.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestNotAttached(t *testing.T) {
+	mb, err := New(testMenus(nil))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := mb.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnter}, &widgetapi.EventMeta{Focused: true}); err == nil {
+		t.Errorf("Keyboard => got nil error, want an error when the MenuBar isn't attached")
+	}
+	if mb.IsOpen() {
+		t.Errorf("IsOpen => true, want false")
+	}
+}
+
+// errorHandler stores the errors reported by the event distribution system.
+type errorHandler struct {
+	mu  sync.Mutex
+	err error
+}
+
+// handle stores the error.
+func (eh *errorHandler) handle(err error) {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	eh.err = err
+}
+
+// get returns the last stored error.
+func (eh *errorHandler) get() error {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	return eh.err
+}
+
+// wantBar returns the terminal with the menu bar drawn on the top row. The
+// menu at the index active is highlighted, none if it is negative.
+func wantBar(ft *faketerm.Terminal, active int) {
+	c := testcanvas.MustNew(image.Rect(0, 0, ft.Area().Dx(), 1))
+	testcanvas.MustSetAreaCells(c, c.Area(), ' ')
+	for i, label := range []string{"File", "Edit"} {
+		var opts []cell.Option
+		if i == active {
+			opts = []cell.Option{cell.Inverse()}
+			testcanvas.MustSetAreaCells(c, image.Rect(i*6, 0, i*6+6, 1), ' ', opts...)
+		}
+		testdraw.MustText(c, label, image.Point{i*6 + 1, 0}, draw.TextCellOpts(opts...))
+	}
+	testcanvas.MustApply(c, ft)
+}
+
+// wantPulldown draws the pull-down menu with the items in the area with the
+// item at the index highlighted.
+func wantPulldown(ft *faketerm.Terminal, ar image.Rectangle, items []string, highlighted int) {
+	c := testcanvas.MustNew(ar)
+	testcanvas.MustSetAreaCells(c, c.Area(), ' ')
+	testdraw.MustBorder(c, c.Area())
+	for i, label := range items {
+		var opts []cell.Option
+		if i == highlighted {
+			opts = []cell.Option{cell.Inverse()}
+		}
+		testcanvas.MustSetAreaCells(c, image.Rect(1, 1+i, ar.Dx()-1, 2+i), ' ', opts...)
+		testdraw.MustText(c, label, image.Point{2, 1 + i}, draw.TextCellOpts(opts...))
+	}
+	testcanvas.MustApply(c, ft)
+}
+
+func TestMenuBarEvents(t *testing.T) {
+	var selected []string
+	mb, err := New(testMenus(&selected))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	size := image.Point{20, 10}
+	got, err := faketerm.New(size)
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(
+		got,
+		container.SplitHorizontal(
+			container.Top(container.PlaceWidget(mb)),
+			container.Bottom(),
+			container.SplitFixed(1),
+		),
+	)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	mb.Attach(cont)
+
+	eds := event.NewDistributionSystem()
+	eh := &errorHandler{}
+	eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+		eh.handle(ev.(*terminalapi.Error).Error())
+	})
+	cont.Subscribe(eds)
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	click := func(p image.Point) []terminalapi.Event {
+		return []terminalapi.Event{
+			&terminalapi.Mouse{Position: p, Button: mouse.ButtonLeft},
+			&terminalapi.Mouse{Position: p, Button: mouse.ButtonRelease},
+		}
+	}
+	key := func(keys ...keyboard.Key) []terminalapi.Event {
+		var evs []terminalapi.Event
+		for _, k := range keys {
+			evs = append(evs, &terminalapi.Keyboard{Key: k})
+		}
+		return evs
+	}
+
+	steps := []struct {
+		desc         string
+		events       []terminalapi.Event
+		wantOpen     bool
+		wantSelected []string
+		// want if not nil is the expected content of the terminal.
+		want func(ft *faketerm.Terminal)
+	}{
+		{
+			desc:     "clicking on a label opens the menu below it",
+			events:   click(image.Point{2, 0}),
+			wantOpen: true,
+			want: func(ft *faketerm.Terminal) {
+				wantBar(ft, 0)
+				wantPulldown(ft, image.Rect(0, 1, 8, 5), []string{"New", "Quit"}, 0)
+			},
+		},
+		{
+			desc:     "the right arrow key opens the next menu",
+			events:   key(keyboard.KeyArrowRight),
+			wantOpen: true,
+			want: func(ft *faketerm.Terminal) {
+				wantBar(ft, 1)
+				wantPulldown(ft, image.Rect(6, 1, 14, 4), []string{"Copy"}, 0)
+			},
+		},
+		{
+			desc:     "the left arrow key opens the previous menu and the down arrow highlights an item",
+			events:   key(keyboard.KeyArrowLeft, keyboard.KeyArrowDown),
+			wantOpen: true,
+			want: func(ft *faketerm.Terminal) {
+				wantBar(ft, 0)
+				wantPulldown(ft, image.Rect(0, 1, 8, 5), []string{"New", "Quit"}, 1)
+			},
+		},
+		{
+			desc:         "enter selects the highlighted item and closes the menu",
+			events:       key(keyboard.KeyEnter),
+			wantSelected: []string{"Quit"},
+			want: func(ft *faketerm.Terminal) {
+				wantBar(ft, 0)
+			},
+		},
+		{
+			desc:         "esc closes the menu",
+			events:       key(keyboard.KeyEnter, keyboard.KeyEsc),
+			wantSelected: []string{"Quit"},
+		},
+		{
+			desc:         "opens the menu for the following steps",
+			events:       click(image.Point{2, 0}),
+			wantOpen:     true,
+			wantSelected: []string{"Quit"},
+		},
+		{
+			desc:         "clicking outside of the menu closes it",
+			events:       click(image.Point{15, 8}),
+			wantSelected: []string{"Quit"},
+		},
+		{
+			desc:         "clicking on a label of the open menu closes it",
+			events:       append(click(image.Point{8, 0}), click(image.Point{8, 0})...),
+			wantSelected: []string{"Quit"},
+		},
+		{
+			desc:         "opens the menu for the following steps",
+			events:       click(image.Point{2, 0}),
+			wantOpen:     true,
+			wantSelected: []string{"Quit"},
+		},
+		{
+			desc:         "clicking on an item selects it",
+			events:       click(image.Point{3, 2}),
+			wantSelected: []string{"Quit", "New"},
+		},
+	}
+
+	processed := 0
+	for _, step := range steps {
+		for _, ev := range step.events {
+			eds.Event(ev)
+		}
+		processed += len(step.events)
+		if err := testevent.WaitFor(5*time.Second, func() error {
+			if got, want := eds.Processed(), processed; got != want {
+				return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("%s: testevent.WaitFor => %v", step.desc, err)
+		}
+		if err := eh.get(); err != nil {
+			t.Fatalf("%s: errorHandler => unexpected error %v", step.desc, err)
+		}
+
+		if got := mb.IsOpen(); got != step.wantOpen {
+			t.Errorf("%s: IsOpen => %v, want %v", step.desc, got, step.wantOpen)
+		}
+		if got := cont.LayerShown(DefaultLayerID); got != step.wantOpen {
+			t.Errorf("%s: LayerShown => %v, want %v", step.desc, got, step.wantOpen)
+		}
+		if fmt.Sprint(selected) != fmt.Sprint(step.wantSelected) {
+			t.Errorf("%s: selected items %v, want %v", step.desc, selected, step.wantSelected)
+		}
+
+		if err := cont.Draw(); err != nil {
+			t.Fatalf("%s: Draw => unexpected error: %v", step.desc, err)
+		}
+		if step.want != nil {
+			want := faketerm.MustNew(size)
+			step.want(want)
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Errorf("%s: Draw => %v", step.desc, diff)
+			}
+		}
+	}
+}
+
+func TestActionError(t *testing.T) {
+	mb, err := New([]Menu{
+		{Label: "File", Items: []Item{{
+			Label:  "Fail",
+			Action: func() error { return errors.New("action failed") },
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	ft, err := faketerm.New(image.Point{20, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(ft, container.PlaceWidget(mb))
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	mb.Attach(cont)
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	meta := &widgetapi.EventMeta{Focused: true}
+	if err := mb.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnter}, meta); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+	if err := mb.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnter}, meta); err == nil {
+		t.Errorf("Keyboard => got nil error, want the error of the action")
+	}
+	if mb.IsOpen() || cont.LayerShown(DefaultLayerID) {
+		t.Errorf("the menu is open after the action, want closed")
+	}
+}
+
+func TestOpenMenuNotDirty(t *testing.T) {
+	mb, err := New(testMenus(nil))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	ft, err := faketerm.New(image.Point{20, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(ft, container.PlaceWidget(mb))
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	mb.Attach(cont)
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	meta := &widgetapi.EventMeta{Focused: true}
+	if err := mb.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnter}, meta); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+	if err := cont.Draw(); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if !mb.IsOpen() {
+		t.Fatalf("IsOpen => false, want true")
+	}
+	if cont.Dirty() {
+		t.Errorf("Dirty => true after drawing the open menu, want false")
+	}
+
+	if err := mb.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowDown}, meta); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+	if !cont.Dirty() {
+		t.Errorf("Dirty => false after highlighting an item, want true")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	mb, err := New(testMenus(nil))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	got := mb.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if got != want {
+		t.Errorf("Options => %+v, want %+v", got, want)
+	}
+}
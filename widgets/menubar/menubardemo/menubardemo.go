@@ -0,0 +1,115 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary menubardemo displays a MenuBar widget with pull-down menus.
+// Exist when 'q' is pressed or when the Quit item is selected.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/menubar"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txt, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+	// item returns an item that logs its selection into the text widget.
+	item := func(label string) menubar.Item {
+		return menubar.Item{
+			Label: label,
+			Action: func() error {
+				return txt.Write(fmt.Sprintf("Selected %q.\n", label))
+			},
+		}
+	}
+
+	mb, err := menubar.New(
+		[]menubar.Menu{
+			{
+				Label: "File",
+				Items: []menubar.Item{
+					item("New"),
+					item("Open..."),
+					item("Save"),
+					{
+						Label: "Quit",
+						Action: func() error {
+							cancel()
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Label: "Edit",
+				Items: []menubar.Item{item("Cut"), item("Copy"), item("Paste")},
+			},
+			{
+				Label: "Help",
+				Items: []menubar.Item{item("About")},
+			},
+		},
+		menubar.BarCellOpts(cell.BgColor(cell.ColorBlue), cell.FgColor(cell.ColorWhite)),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.SplitHorizontal(
+			container.Top(
+				container.PlaceWidget(mb),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("PRESS Q TO QUIT"),
+				container.PlaceWidget(txt),
+			),
+			container.SplitFixed(1),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+	mb.Attach(c)
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
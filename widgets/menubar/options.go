@@ -0,0 +1,135 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menubar
+
+// options.go contains configurable options for MenuBar.
+
+import (
+	"errors"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	barCellOpts         []cell.Option
+	activeCellOpts      []cell.Option
+	itemCellOpts        []cell.Option
+	highlightedCellOpts []cell.Option
+	borderCellOpts      []cell.Option
+	layerID             string
+	layerZIndex         int
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		activeCellOpts:      []cell.Option{cell.Inverse()},
+		highlightedCellOpts: []cell.Option{cell.Inverse()},
+		layerID:             DefaultLayerID,
+		layerZIndex:         DefaultLayerZIndex,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.layerID == "" {
+		return errors.New("invalid LayerID, the ID cannot be empty")
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// BarCellOpts sets the cell options of the bar, including the empty cells
+// after the labels of the menus, e.g. the background color of the bar.
+// Defaults to the default cell options.
+func BarCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.barCellOpts = co
+	})
+}
+
+// ActiveCellOpts sets the cell options applied on top of the label of the
+// menu that is open or highlighted while the menu bar is focused.
+// Defaults to inverse colors.
+func ActiveCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.activeCellOpts = co
+	})
+}
+
+// ItemCellOpts sets the cell options of the items in the pull-down menus.
+// Defaults to the default cell options.
+func ItemCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.itemCellOpts = co
+	})
+}
+
+// HighlightedCellOpts sets the cell options of the highlighted item in the
+// open pull-down menu.
+// Defaults to inverse colors.
+func HighlightedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.highlightedCellOpts = co
+	})
+}
+
+// BorderCellOpts sets the cell options of the border around the pull-down
+// menus.
+// Defaults to the default cell options.
+func BorderCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.borderCellOpts = co
+	})
+}
+
+// DefaultLayerID is the default value for the LayerID option.
+const DefaultLayerID = "menubar"
+
+// LayerID sets the ID of the layer that displays the pull-down menus, see
+// container.ShowLayer. Must be unique across the containers of the dashboard,
+// i.e. each MenuBar on the dashboard needs a different ID.
+// Defaults to DefaultLayerID.
+func LayerID(id string) Option {
+	return option(func(opts *options) {
+		opts.layerID = id
+	})
+}
+
+// DefaultLayerZIndex is the default value for the LayerZIndex option.
+const DefaultLayerZIndex = 100
+
+// LayerZIndex sets the z-index of the layer that displays the pull-down
+// menus, see container.ShowLayer.
+// Defaults to DefaultLayerZIndex.
+func LayerZIndex(z int) Option {
+	return option(func(opts *options) {
+		opts.layerZIndex = z
+	})
+}
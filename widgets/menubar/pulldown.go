@@ -0,0 +1,141 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package menubar
+
+// pulldown.go contains the widget displaying the items of the open menu.
+
+import (
+	"image"
+
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// pulldown displays the items of a menu of the MenuBar in a border. It is
+// placed onto a layer above the layout.
+//
+// Implements widgetapi.Widget and widgetapi.DirtyReporter. This object is
+// thread-safe.
+type pulldown struct {
+	// mb is the MenuBar that displays this pull-down menu.
+	mb *MenuBar
+	// menu is the index of the displayed menu.
+	menu int
+
+	// dirty reports changes of the content to the infrastructure, the
+	// MenuBar reports its changes through it.
+	dirty dirty.Reporter
+}
+
+// current asserts whether this pull-down is the one displayed by the MenuBar.
+// Caller must hold mb.mu.
+func (pd *pulldown) current() bool {
+	return pd.mb.shown == pd && pd.mb.open && pd.mb.active == pd.menu
+}
+
+// Draw draws the items of the menu onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (pd *pulldown) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	mb := pd.mb
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	ar := cvs.Area()
+	if err := cvs.SetAreaCells(ar, ' ', mb.opts.itemCellOpts...); err != nil {
+		return err
+	}
+	if err := draw.Border(cvs, ar, draw.BorderCellOpts(mb.opts.borderCellOpts...)); err != nil {
+		return err
+	}
+
+	for i, it := range mb.menus[pd.menu].Items {
+		rowAr := image.Rect(ar.Min.X+1, ar.Min.Y+1+i, ar.Max.X-1, ar.Min.Y+2+i)
+		if rowAr.Max.Y >= ar.Max.Y || rowAr.Dx() < 3 {
+			break
+		}
+		cOpts := mb.opts.itemCellOpts
+		if pd.current() && i == mb.highlighted {
+			cOpts = mb.opts.highlightedCellOpts
+		}
+		if err := cvs.SetAreaCells(rowAr, ' ', cOpts...); err != nil {
+			return err
+		}
+		if err := draw.Text(
+			cvs, it.Label, image.Point{rowAr.Min.X + 1, rowAr.Min.Y},
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextMaxX(rowAr.Max.X-1),
+			draw.TextCellOpts(cOpts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keyboard navigates the menus and selects items, the same as the keyboard
+// events delivered to the MenuBar. Used when the container of the pull-down
+// menu is focused.
+// Implements widgetapi.Widget.Keyboard.
+func (pd *pulldown) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return pd.mb.Keyboard(k, meta)
+}
+
+// mouse processes the mouse event.
+// Returns the action of the selected item or nil.
+func (pd *pulldown) mouse(m *terminalapi.Mouse) func() error {
+	mb := pd.mb
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if m.Button != mouse.ButtonLeft || !pd.current() {
+		return nil
+	}
+	// Mouse events outside of the canvas have negative positions.
+	if m.Position.X < 0 || m.Position.Y < 0 {
+		mb.closeMenu()
+		return nil
+	}
+	if i := m.Position.Y - 1; m.Position.X > 0 && i >= 0 && i < len(mb.menus[pd.menu].Items) {
+		mb.highlighted = i
+		return mb.choose()
+	}
+	return nil
+}
+
+// Mouse selects the clicked item and closes the menu when clicked outside.
+// Implements widgetapi.Widget.Mouse.
+func (pd *pulldown) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return pd.mb.sync(pd.mouse(m))
+}
+
+// Options implements widgetapi.Widget.Options.
+func (pd *pulldown) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{3, 3},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		// Receives all the mouse events to close the menu when clicked
+		// outside.
+		WantMouse: widgetapi.MouseScopeGlobal,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (pd *pulldown) SetDirtyFunc(markDirty func()) {
+	pd.dirty.Set(markDirty)
+}
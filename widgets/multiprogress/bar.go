@@ -0,0 +1,119 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiprogress
+
+// bar.go contains the state of a single progress bar.
+
+import (
+	"fmt"
+	"time"
+)
+
+// sample is the progress of a bar observed at a point in time.
+type sample struct {
+	at      time.Time
+	current int64
+}
+
+// bar is a single labeled progress bar.
+type bar struct {
+	label   string
+	total   int64
+	current int64
+	// history are the samples within the rate window, oldest first.
+	history []sample
+}
+
+// record records the current progress at the provided time and forgets
+// samples that fell out of the window.
+func (b *bar) record(now time.Time, current int64, window time.Duration) {
+	b.current = current
+	b.history = append(b.history, sample{at: now, current: current})
+	b.prune(now, window)
+}
+
+// prune forgets samples older than the window. The newest sample taken
+// before the window started is kept, since it anchors the rate computation
+// when the progress stalls.
+func (b *bar) prune(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	drop := 0
+	for drop < len(b.history)-1 && !b.history[drop+1].at.After(cutoff) {
+		drop++
+	}
+	b.history = b.history[drop:]
+}
+
+// percent returns the completed percentage.
+func (b *bar) percent() int {
+	return int(b.current * 100 / b.total)
+}
+
+// rate returns the progress per second over the samples in the history up to
+// the provided time. Returns false if there isn't enough history.
+func (b *bar) rate(now time.Time) (float64, bool) {
+	if len(b.history) == 0 {
+		return 0, false
+	}
+	first := b.history[0]
+	elapsed := now.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(b.current-first.current) / elapsed, true
+}
+
+// eta returns the estimated time until the bar completes given the rate.
+// Returns false if the completion can't be estimated.
+func (b *bar) eta(rate float64) (time.Duration, bool) {
+	remaining := b.total - b.current
+	if remaining == 0 {
+		return 0, true
+	}
+	if rate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(remaining) / rate * float64(time.Second)), true
+}
+
+// byteUnits are the binary units used when formatting byte counts.
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// formatRate formats the rate as bytes per second, e.g. "1.5 MiB/s".
+func formatRate(bytesPerSec float64) string {
+	if bytesPerSec < 1024 {
+		return fmt.Sprintf("%.0f B/s", bytesPerSec)
+	}
+	unit := -1
+	for bytesPerSec >= 1024 && unit < len(byteUnits)-1 {
+		bytesPerSec /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s/s", bytesPerSec, byteUnits[unit])
+}
+
+// formatETA formats the estimated time remaining, e.g. "ETA 01:05" or
+// "ETA 2:03:04" when the duration exceeds an hour.
+func formatETA(d time.Duration, ok bool) string {
+	if !ok || d >= 100*time.Hour {
+		return "ETA --:--"
+	}
+	secs := int64((d + time.Second/2) / time.Second)
+	h, m, s := secs/3600, secs/60%60, secs%60
+	if h > 0 {
+		return fmt.Sprintf("ETA %d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("ETA %02d:%02d", m, s)
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiprogress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		desc        string
+		bytesPerSec float64
+		want        string
+	}{
+		{
+			desc:        "zero",
+			bytesPerSec: 0,
+			want:        "0 B/s",
+		},
+		{
+			desc:        "bytes",
+			bytesPerSec: 1023,
+			want:        "1023 B/s",
+		},
+		{
+			desc:        "kibibytes",
+			bytesPerSec: 1536,
+			want:        "1.5 KiB/s",
+		},
+		{
+			desc:        "mebibytes",
+			bytesPerSec: 10 * 1024 * 1024,
+			want:        "10.0 MiB/s",
+		},
+		{
+			desc:        "stays at the largest unit",
+			bytesPerSec: 2048 * 1024 * 1024 * 1024 * 1024 * 1024 * 1024,
+			want:        "2048.0 EiB/s",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := formatRate(tc.bytesPerSec); got != tc.want {
+				t.Errorf("formatRate(%v) => %q, want %q", tc.bytesPerSec, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := []struct {
+		desc string
+		d    time.Duration
+		ok   bool
+		want string
+	}{
+		{
+			desc: "unknown",
+			ok:   false,
+			want: "ETA --:--",
+		},
+		{
+			desc: "zero",
+			ok:   true,
+			want: "ETA 00:00",
+		},
+		{
+			desc: "rounds to seconds",
+			d:    65*time.Second + 600*time.Millisecond,
+			ok:   true,
+			want: "ETA 01:06",
+		},
+		{
+			desc: "hours",
+			d:    2*time.Hour + 3*time.Minute + 4*time.Second,
+			ok:   true,
+			want: "ETA 2:03:04",
+		},
+		{
+			desc: "too long",
+			d:    100 * time.Hour,
+			ok:   true,
+			want: "ETA --:--",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := formatETA(tc.d, tc.ok); got != tc.want {
+				t.Errorf("formatETA(%v, %v) => %q, want %q", tc.d, tc.ok, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBarRate(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 10 * time.Second
+
+	tests := []struct {
+		desc     string
+		updates  map[time.Duration]int64
+		now      time.Duration
+		wantRate float64
+		wantOK   bool
+		wantETA  time.Duration
+	}{
+		{
+			desc:   "no elapsed time",
+			now:    0,
+			wantOK: false,
+		},
+		{
+			desc: "rate over the whole history",
+			updates: map[time.Duration]int64{
+				1 * time.Second: 100,
+				2 * time.Second: 400,
+			},
+			now:      4 * time.Second,
+			wantRate: 100,
+			wantOK:   true,
+			wantETA:  6 * time.Second,
+		},
+		{
+			desc: "forgets samples outside of the window",
+			updates: map[time.Duration]int64{
+				5 * time.Second:  400,
+				15 * time.Second: 700,
+			},
+			now:      20 * time.Second,
+			wantRate: 20,
+			wantOK:   true,
+			wantETA:  15 * time.Second,
+		},
+		{
+			desc: "stalled progress",
+			updates: map[time.Duration]int64{
+				1 * time.Second: 500,
+			},
+			now:      30 * time.Second,
+			wantRate: 0,
+			wantOK:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &bar{total: 1000}
+			b.record(start, 0, window)
+			for offset := time.Duration(0); offset <= tc.now; offset += time.Second {
+				if v, ok := tc.updates[offset]; ok {
+					b.record(start.Add(offset), v, window)
+				}
+			}
+
+			now := start.Add(tc.now)
+			b.prune(now, window)
+			gotRate, gotOK := b.rate(now)
+			if gotRate != tc.wantRate || gotOK != tc.wantOK {
+				t.Errorf("rate => (%v, %v), want (%v, %v)", gotRate, gotOK, tc.wantRate, tc.wantOK)
+			}
+			if !gotOK || gotRate == 0 {
+				return
+			}
+			gotETA, ok := b.eta(gotRate)
+			if !ok || gotETA != tc.wantETA {
+				t.Errorf("eta => (%v, %v), want (%v, true)", gotETA, ok, tc.wantETA)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package multiprogress implements a widget that displays many labeled
+// progress bars.
+package multiprogress
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+const (
+	// percentWidth is the width of the completed percentage, e.g. "100%".
+	percentWidth = 4
+	// rateWidth is the width of the rate, e.g. "1023.9 KiB/s".
+	rateWidth = 12
+	// etaWidth is the width of the ETA, e.g. "ETA 99:59:59".
+	etaWidth = 12
+)
+
+// MultiProgress displays one row per tracked progress bar. Each row contains
+// a label, the bar itself, the completed percentage, the rate in bytes per
+// second and the estimated time remaining. The rate and the ETA are computed
+// from the history of updates within the RateWindow.
+//
+// Rows are displayed in the order in which the bars were added. Rows that
+// don't fit onto the canvas aren't displayed.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type MultiProgress struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// ids are the identifiers of the bars in the order they were added.
+	ids []string
+	// bars are the tracked bars keyed by their identifiers.
+	bars map[string]*bar
+
+	// now returns the current time, can be replaced in tests.
+	now func() time.Time
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new MultiProgress.
+func New(opts ...Option) (*MultiProgress, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &MultiProgress{
+		bars: map[string]*bar{},
+		now:  time.Now,
+		opts: opt,
+	}, nil
+}
+
+// Add starts tracking a new progress bar identified by the id. The total is
+// the number of bytes that completes the bar and must be positive.
+func (mp *MultiProgress) Add(id, label string, total int64) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	defer mp.dirty.Mark()
+
+	if _, ok := mp.bars[id]; ok {
+		return fmt.Errorf("progress bar %q already exists", id)
+	}
+	if total <= 0 {
+		return fmt.Errorf("invalid total %d for progress bar %q, must be a positive number", total, id)
+	}
+	if strings.ContainsAny(label, "\n\t") {
+		return fmt.Errorf("invalid label %q for progress bar %q, cannot contain newlines or tabs", label, id)
+	}
+
+	b := &bar{
+		label: label,
+		total: total,
+	}
+	b.record(mp.now(), 0, mp.opts.rateWindow)
+	mp.bars[id] = b
+	mp.ids = append(mp.ids, id)
+	return nil
+}
+
+// Update sets the number of completed bytes of the progress bar identified by
+// the id. The value must be in the range 0 <= current <= total.
+func (mp *MultiProgress) Update(id string, current int64) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	defer mp.dirty.Mark()
+
+	b, err := mp.bar(id)
+	if err != nil {
+		return err
+	}
+	return mp.update(id, b, current)
+}
+
+// Increment adds the delta to the number of completed bytes of the progress
+// bar identified by the id.
+func (mp *MultiProgress) Increment(id string, delta int64) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	defer mp.dirty.Mark()
+
+	b, err := mp.bar(id)
+	if err != nil {
+		return err
+	}
+	return mp.update(id, b, b.current+delta)
+}
+
+// Remove stops tracking the progress bar identified by the id.
+func (mp *MultiProgress) Remove(id string) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	defer mp.dirty.Mark()
+
+	if _, err := mp.bar(id); err != nil {
+		return err
+	}
+	delete(mp.bars, id)
+	for i, got := range mp.ids {
+		if got == id {
+			mp.ids = append(mp.ids[:i], mp.ids[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Len returns the number of tracked progress bars.
+func (mp *MultiProgress) Len() int {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	return len(mp.ids)
+}
+
+// bar returns the progress bar identified by the id.
+// Caller must hold mp.mu.
+func (mp *MultiProgress) bar(id string) (*bar, error) {
+	b, ok := mp.bars[id]
+	if !ok {
+		return nil, fmt.Errorf("progress bar %q doesn't exist", id)
+	}
+	return b, nil
+}
+
+// update validates and records the progress of the bar.
+// Caller must hold mp.mu.
+func (mp *MultiProgress) update(id string, b *bar, current int64) error {
+	if current < 0 || current > b.total {
+		return fmt.Errorf("invalid progress %d for progress bar %q, must be in range 0 <= progress <= %d", current, id, b.total)
+	}
+	b.record(mp.now(), current, mp.opts.rateWindow)
+	return nil
+}
+
+// labelWidth returns the width of the label column.
+// Caller must hold mp.mu.
+func (mp *MultiProgress) labelWidth() int {
+	if mp.opts.labelWidth > 0 {
+		return mp.opts.labelWidth
+	}
+	var width int
+	for _, b := range mp.bars {
+		if w := runewidth.StringWidth(b.label); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// statsWidth returns the width of the statistics displayed on the right of
+// each bar, including the space that separates them from the bar.
+func (mp *MultiProgress) statsWidth() int {
+	width := 1 + percentWidth
+	if !mp.opts.hideRate {
+		width += 1 + rateWidth
+	}
+	if !mp.opts.hideETA {
+		width += 1 + etaWidth
+	}
+	return width
+}
+
+// stats returns the statistics of the bar.
+func (mp *MultiProgress) stats(b *bar, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(" %*s", percentWidth, fmt.Sprintf("%d%%", b.percent())))
+
+	rate, ok := b.rate(now)
+	if !mp.opts.hideRate {
+		r := "-- B/s"
+		if ok {
+			r = formatRate(rate)
+		}
+		sb.WriteString(fmt.Sprintf(" %*s", rateWidth, r))
+	}
+	if !mp.opts.hideETA {
+		sb.WriteString(fmt.Sprintf(" %*s", etaWidth, formatETA(b.eta(rate))))
+	}
+	return sb.String()
+}
+
+// minWidth returns the minimum width of the canvas, which fits the labels,
+// the statistics and a bar one cell wide.
+// Caller must hold mp.mu.
+func (mp *MultiProgress) minWidth() int {
+	width := mp.statsWidth() + 1
+	if lw := mp.labelWidth(); lw > 0 {
+		width += lw + 1
+	}
+	return width
+}
+
+// Draw draws the MultiProgress widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (mp *MultiProgress) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	ar := cvs.Area()
+	if ar.Dx() < mp.minWidth() || ar.Dy() < 1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	now := mp.now()
+	lw := mp.labelWidth()
+	for i, id := range mp.ids {
+		y := ar.Min.Y + i
+		if y >= ar.Max.Y {
+			break
+		}
+		b := mp.bars[id]
+		b.prune(now, mp.opts.rateWindow)
+		if b.current < b.total {
+			// The rate and the ETA change over time.
+			mp.dirty.Mark()
+		}
+
+		x := ar.Min.X
+		if lw > 0 {
+			if err := draw.Text(
+				cvs, b.label, image.Point{x, y},
+				draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				draw.TextMaxX(x+lw),
+				draw.TextCellOpts(mp.opts.labelCellOpts...),
+			); err != nil {
+				return err
+			}
+			x += lw + 1
+		}
+
+		barEnd := ar.Max.X - mp.statsWidth()
+		filledEnd := x + int(int64(barEnd-x)*b.current/b.total)
+		if filledEnd > x {
+			if err := cvs.SetAreaCells(image.Rect(x, y, filledEnd, y+1), mp.opts.filledRune, mp.opts.filledCellOpts...); err != nil {
+				return err
+			}
+		}
+		if barEnd > filledEnd {
+			if err := cvs.SetAreaCells(image.Rect(filledEnd, y, barEnd, y+1), mp.opts.emptyRune, mp.opts.emptyCellOpts...); err != nil {
+				return err
+			}
+		}
+
+		if err := draw.Text(cvs, mp.stats(b, now), image.Point{barEnd, y}, draw.TextCellOpts(mp.opts.statsCellOpts...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keyboard input isn't supported on the MultiProgress widget.
+func (mp *MultiProgress) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the MultiProgress widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the MultiProgress widget.
+func (mp *MultiProgress) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the MultiProgress widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (mp *MultiProgress) Options() widgetapi.Options {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize:  image.Point{mp.minWidth(), 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (mp *MultiProgress) SetDirtyFunc(markDirty func()) {
+	mp.dirty.Set(markDirty)
+}
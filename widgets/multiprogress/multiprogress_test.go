@@ -0,0 +1,333 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiprogress
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+// advance moves the clock forward.
+func (fc *fakeClock) advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+func TestMultiProgress(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		canvas        image.Rectangle
+		update        func(*MultiProgress, *fakeClock) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails on bar runes wider than a cell",
+			opts:       []Option{BarRunes('世', ' ')},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on negative label width",
+			opts:       []Option{LabelWidth(-1)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on zero rate window",
+			opts:       []Option{RateWindow(0)},
+			wantNewErr: true,
+		},
+		{
+			desc: "fails on duplicate bar",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				if err := mp.Add("a", "a", 10); err != nil {
+					return err
+				}
+				return mp.Add("a", "a", 10)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails on non-positive total",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Add("a", "a", 0)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails on label with a newline",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Add("a", "a\nb", 10)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails on progress over the total",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				if err := mp.Add("a", "a", 10); err != nil {
+					return err
+				}
+				return mp.Update("a", 11)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails on negative progress",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				if err := mp.Add("a", "a", 10); err != nil {
+					return err
+				}
+				return mp.Increment("a", -1)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails to update an unknown bar",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Update("a", 1)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "fails to remove an unknown bar",
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Remove("a")
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "requests resize when the canvas is too narrow",
+			canvas: image.Rect(0, 0, 33, 1),
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Add("a", "a", 10)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws nothing without bars",
+			canvas: image.Rect(0, 0, 40, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws bar with rate and ETA",
+			canvas: image.Rect(0, 0, 40, 1),
+			update: func(mp *MultiProgress, fc *fakeClock) error {
+				if err := mp.Add("a", "a", 1000); err != nil {
+					return err
+				}
+				fc.advance(time.Second)
+				if err := mp.Update("a", 500); err != nil {
+					return err
+				}
+				fc.advance(time.Second)
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "a", image.Point{0, 0})
+				testcanvas.MustSetAreaCells(cvs, image.Rect(2, 0, 5, 1), DefaultFilledRune, cell.FgColor(DefaultFilledColor))
+				testcanvas.MustSetAreaCells(cvs, image.Rect(5, 0, 9, 1), DefaultEmptyRune)
+				testdraw.MustText(cvs, "  50%      250 B/s    ETA 00:02", image.Point{9, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws unknown rate before any time elapsed",
+			opts:   []Option{HideETA()},
+			canvas: image.Rect(0, 0, 22, 1),
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				return mp.Add("a", "a", 1000)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "a", image.Point{0, 0})
+				testcanvas.MustSetAreaCells(cvs, image.Rect(2, 0, 4, 1), DefaultEmptyRune)
+				testdraw.MustText(cvs, "   0%       -- B/s", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws multiple bars in order with truncated labels",
+			opts: []Option{
+				HideRate(),
+				HideETA(),
+				LabelWidth(3),
+				BarRunes('#', '.'),
+				FilledCellOpts(),
+				LabelCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 13, 3),
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				if err := mp.Add("first", "first", 4); err != nil {
+					return err
+				}
+				if err := mp.Add("second", "ab", 4); err != nil {
+					return err
+				}
+				if err := mp.Add("third", "c", 4); err != nil {
+					return err
+				}
+				if err := mp.Increment("first", 2); err != nil {
+					return err
+				}
+				if err := mp.Update("second", 4); err != nil {
+					return err
+				}
+				return mp.Remove("third")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "fi…", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustSetAreaCells(cvs, image.Rect(4, 0, 6, 1), '#')
+				testcanvas.MustSetAreaCells(cvs, image.Rect(6, 0, 8, 1), '.')
+				testdraw.MustText(cvs, "  50%", image.Point{8, 0})
+				testdraw.MustText(cvs, "ab", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustSetAreaCells(cvs, image.Rect(4, 1, 8, 2), '#')
+				testdraw.MustText(cvs, " 100%", image.Point{8, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't draw rows that don't fit",
+			opts:   []Option{HideRate(), HideETA()},
+			canvas: image.Rect(0, 0, 8, 1),
+			update: func(mp *MultiProgress, _ *fakeClock) error {
+				if err := mp.Add("a", "a", 1); err != nil {
+					return err
+				}
+				return mp.Add("b", "b", 1)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "a", image.Point{0, 0})
+				testcanvas.MustSetAreaCells(cvs, image.Rect(2, 0, 3, 1), DefaultEmptyRune)
+				testdraw.MustText(cvs, "   0%", image.Point{3, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			mp, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+			fc := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+			mp.now = func() time.Time { return fc.now }
+
+			if tc.update != nil {
+				err := tc.update(mp, fc)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := mp.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	mp, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := mp.Add(id, id, 1); err != nil {
+			t.Fatalf("Add => unexpected error: %v", err)
+		}
+	}
+	if err := mp.Remove("a"); err != nil {
+		t.Fatalf("Remove => unexpected error: %v", err)
+	}
+	if got, want := mp.Len(), 1; got != want {
+		t.Errorf("Len => %d, want %d", got, want)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	mp, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := mp.Add("a", "abc", 1); err != nil {
+		t.Fatalf("Add => unexpected error: %v", err)
+	}
+
+	got := mp.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{36, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary multiprogressdemo displays simulated downloads tracked by the
+// MultiProgress widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/multiprogress"
+)
+
+// download simulates a download of the provided size that progresses at a
+// randomly fluctuating rate until it completes or the context expires.
+func download(ctx context.Context, mp *multiprogress.MultiProgress, id string, size int64) {
+	const tick = 100 * time.Millisecond
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	// Each download has its own base rate between 64 KiB/s and 4 MiB/s.
+	base := int64(64<<10) + rand.Int63n(4<<20)
+	var done int64
+	for {
+		select {
+		case <-ticker.C:
+			step := base / 10 * (50 + rand.Int63n(100)) / 100
+			if done+step > size {
+				step = size - done
+			}
+			done += step
+			if err := mp.Update(id, done); err != nil {
+				panic(err)
+			}
+			if done == size {
+				return
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mp, err := multiprogress.New(
+		multiprogress.LabelCellOpts(cell.FgColor(cell.ColorCyan)),
+		multiprogress.EmptyCellOpts(cell.FgColor(cell.ColorNumber(240))),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("file-%02d", i)
+		size := int64(1<<20) + rand.Int63n(64<<20)
+		if err := mp.Add(id, fmt.Sprintf("%s.tar.gz", id), size); err != nil {
+			panic(err)
+		}
+		go download(ctx, mp, id, size)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(mp),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(250*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiprogress
+
+// options.go contains configurable options for MultiProgress.
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	filledRune     rune
+	emptyRune      rune
+	filledCellOpts []cell.Option
+	emptyCellOpts  []cell.Option
+	labelCellOpts  []cell.Option
+	statsCellOpts  []cell.Option
+	labelWidth     int
+	rateWindow     time.Duration
+	hideRate       bool
+	hideETA        bool
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		filledRune: DefaultFilledRune,
+		emptyRune:  DefaultEmptyRune,
+		filledCellOpts: []cell.Option{
+			cell.FgColor(DefaultFilledColor),
+		},
+		rateWindow: DefaultRateWindow,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	for _, r := range []rune{o.filledRune, o.emptyRune} {
+		if got, want := runewidth.RuneWidth(r), 1; got != want {
+			return fmt.Errorf("invalid BarRunes %q, each rune must occupy %d cell, %q occupies %d", []rune{o.filledRune, o.emptyRune}, want, r, got)
+		}
+	}
+	if got, min := o.labelWidth, 0; got < min {
+		return fmt.Errorf("invalid LabelWidth %d, must be %d <= LabelWidth", got, min)
+	}
+	if o.rateWindow <= 0 {
+		return fmt.Errorf("invalid RateWindow %v, must be a positive duration", o.rateWindow)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// DefaultFilledRune is the default value for the BarRunes option.
+const DefaultFilledRune = '█'
+
+// DefaultEmptyRune is the default value for the BarRunes option.
+const DefaultEmptyRune = '░'
+
+// BarRunes sets the runes used to draw the completed and the remaining part
+// of the progress bars. Both runes must occupy a single cell.
+func BarRunes(filled, empty rune) Option {
+	return option(func(opts *options) {
+		opts.filledRune = filled
+		opts.emptyRune = empty
+	})
+}
+
+// DefaultFilledColor is the default color of the completed part of the
+// progress bars.
+const DefaultFilledColor = cell.ColorGreen
+
+// FilledCellOpts sets the cell options of the completed part of the progress
+// bars.
+func FilledCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.filledCellOpts = co
+	})
+}
+
+// EmptyCellOpts sets the cell options of the remaining part of the progress
+// bars.
+func EmptyCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.emptyCellOpts = co
+	})
+}
+
+// LabelCellOpts sets the cell options of the labels.
+func LabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.labelCellOpts = co
+	})
+}
+
+// StatsCellOpts sets the cell options of the percentage, rate and ETA
+// displayed on the right of each progress bar.
+func StatsCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.statsCellOpts = co
+	})
+}
+
+// LabelWidth sets the width of the column that contains the labels. Longer
+// labels are truncated. Defaults to zero which means the width of the longest
+// label.
+func LabelWidth(cells int) Option {
+	return option(func(opts *options) {
+		opts.labelWidth = cells
+	})
+}
+
+// DefaultRateWindow is the default value for the RateWindow option.
+const DefaultRateWindow = 10 * time.Second
+
+// RateWindow sets the time window of update history used to compute the rate
+// and the ETA of each progress bar. A longer window produces steadier values
+// that react slower to changes in the rate.
+func RateWindow(d time.Duration) Option {
+	return option(func(opts *options) {
+		opts.rateWindow = d
+	})
+}
+
+// HideRate disables the display of the bytes/sec rate.
+func HideRate() Option {
+	return option(func(opts *options) {
+		opts.hideRate = true
+	})
+}
+
+// HideETA disables the display of the estimated time remaining.
+func HideETA() Option {
+	return option(func(opts *options) {
+		opts.hideETA = true
+	})
+}
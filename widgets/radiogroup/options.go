@@ -0,0 +1,147 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radiogroup
+
+// options.go contains configurable options for RadioGroup.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	selected           int
+	selectedGlyph      string
+	unselectedGlyph    string
+	selectedCellOpts   []cell.Option
+	unselectedCellOpts []cell.Option
+	labelCellOpts      []cell.Option
+	focusedCellOpts    []cell.Option
+	onChange           ChangeFn
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		selectedGlyph:   DefaultSelectedGlyph,
+		unselectedGlyph: DefaultUnselectedGlyph,
+		focusedCellOpts: []cell.Option{cell.Inverse()},
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate(items int) error {
+	if o.selectedGlyph == "" || o.unselectedGlyph == "" {
+		return fmt.Errorf("invalid glyphs %q and %q, they cannot be empty", o.selectedGlyph, o.unselectedGlyph)
+	}
+	if sw, uw := runewidth.StringWidth(o.selectedGlyph), runewidth.StringWidth(o.unselectedGlyph); sw != uw {
+		return fmt.Errorf("invalid glyphs %q and %q, they must occupy the same number of cells, got %d and %d", o.selectedGlyph, o.unselectedGlyph, sw, uw)
+	}
+	if min, max := 0, items-1; o.selected < min || o.selected > max {
+		return fmt.Errorf("invalid Selected(%d), must be value in range %d <= value <= %d", o.selected, min, max)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// Selected sets the index of the initially selected item.
+// Defaults to the first item.
+func Selected(index int) Option {
+	return option(func(opts *options) {
+		opts.selected = index
+	})
+}
+
+// The default glyphs that indicate whether an item is selected.
+const (
+	DefaultSelectedGlyph   = "(*)"
+	DefaultUnselectedGlyph = "( )"
+)
+
+// Glyphs sets the text displayed in front of the selected and the unselected
+// items. Both glyphs must occupy the same number of cells.
+// Defaults to DefaultSelectedGlyph and DefaultUnselectedGlyph.
+func Glyphs(selected, unselected string) Option {
+	return option(func(opts *options) {
+		opts.selectedGlyph = selected
+		opts.unselectedGlyph = unselected
+	})
+}
+
+// SelectedCellOpts sets the cell options of the glyph of the selected item.
+// Defaults to the default cell options.
+func SelectedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectedCellOpts = co
+	})
+}
+
+// UnselectedCellOpts sets the cell options of the glyphs of the unselected
+// items.
+// Defaults to the default cell options.
+func UnselectedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.unselectedCellOpts = co
+	})
+}
+
+// LabelCellOpts sets the cell options of the labels of the items.
+// Defaults to the default cell options.
+func LabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.labelCellOpts = co
+	})
+}
+
+// FocusedCellOpts sets the cell options applied on top of the label of the
+// item under the keyboard cursor when the radio group is focused.
+// Defaults to inverse colors.
+func FocusedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.focusedCellOpts = co
+	})
+}
+
+// ChangeFn is called when the user selects a different item. The arguments
+// are the index and the label of the selected item.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The RadioGroup isn't locked while the
+// function executes, so it can read from or modify the RadioGroup.
+type ChangeFn func(index int, label string) error
+
+// OnChange sets the function that is called when the user selects a
+// different item.
+func OnChange(fn ChangeFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package radiogroup implements a widget that selects one of several items.
+package radiogroup
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// RadioGroup displays a vertical list of labeled items, exactly one of which
+// is selected.
+//
+// The keyboard cursor is moved with the Up and Down arrow keys, Space or Enter
+// selects the item under the cursor. Clicking on an item selects it.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type RadioGroup struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// labels are the labels of the items.
+	labels []string
+	// selected is the index of the selected item.
+	selected int
+	// cursor is the index of the item under the keyboard cursor.
+	cursor int
+	// pressed indicates that the left mouse button is pressed.
+	pressed bool
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new RadioGroup with items that have the provided labels.
+func New(labels []string, opts ...Option) (*RadioGroup, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("a radio group requires at least one item")
+	}
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(len(labels)); err != nil {
+		return nil, err
+	}
+	return &RadioGroup{
+		labels:   append([]string(nil), labels...),
+		selected: opt.selected,
+		cursor:   opt.selected,
+		opts:     opt,
+	}, nil
+}
+
+// Selected returns the index and the label of the selected item.
+func (rg *RadioGroup) Selected() (int, string) {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	return rg.selected, rg.labels[rg.selected]
+}
+
+// Select selects the item at the index. Doesn't call the ChangeFn.
+func (rg *RadioGroup) Select(index int) error {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	defer rg.dirty.Mark()
+
+	if min, max := 0, len(rg.labels)-1; index < min || index > max {
+		return fmt.Errorf("invalid index %d, must be value in range %d <= value <= %d", index, min, max)
+	}
+	rg.selected = index
+	rg.cursor = index
+	return nil
+}
+
+// Draw draws the RadioGroup widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (rg *RadioGroup) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	ar := cvs.Area()
+	gw := runewidth.StringWidth(rg.opts.selectedGlyph)
+	if ar.Dx() < gw || ar.Dy() < len(rg.labels) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	for i, label := range rg.labels {
+		y := ar.Min.Y + i
+		glyph, gOpts := rg.opts.unselectedGlyph, rg.opts.unselectedCellOpts
+		if i == rg.selected {
+			glyph, gOpts = rg.opts.selectedGlyph, rg.opts.selectedCellOpts
+		}
+		if err := draw.Text(cvs, glyph, image.Point{ar.Min.X, y}, draw.TextCellOpts(gOpts...)); err != nil {
+			return err
+		}
+		if label == "" || ar.Dx() < gw+2 {
+			continue
+		}
+
+		lOpts := rg.opts.labelCellOpts
+		if meta.Focused && i == rg.cursor {
+			lOpts = append(append([]cell.Option(nil), lOpts...), rg.opts.focusedCellOpts...)
+		}
+		if err := draw.Text(
+			cvs, label, image.Point{ar.Min.X + gw + 1, y},
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextMaxX(ar.Max.X),
+			draw.TextCellOpts(lOpts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectItem selects the item at the index.
+// Returns true if the selection changed.
+// Caller must hold rg.mu.
+func (rg *RadioGroup) selectItem(index int) bool {
+	rg.cursor = index
+	if rg.selected == index {
+		return false
+	}
+	rg.selected = index
+	return true
+}
+
+// changed calls the ChangeFn if the selection changed and one was provided.
+func (rg *RadioGroup) changed(changed bool, index int, label string) error {
+	if !changed || rg.opts.onChange == nil {
+		return nil
+	}
+	// Mutex must be released when calling the callback.
+	return rg.opts.onChange(index, label)
+}
+
+// keyboard processes the keyboard event.
+// Returns true if the selection changed and the selected item.
+func (rg *RadioGroup) keyboard(k *terminalapi.Keyboard) (bool, int, string) {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	switch k.Key {
+	case keyboard.KeyArrowUp:
+		if rg.cursor > 0 {
+			rg.cursor--
+		}
+	case keyboard.KeyArrowDown:
+		if rg.cursor < len(rg.labels)-1 {
+			rg.cursor++
+		}
+	case keyboard.KeySpace, keyboard.KeyEnter:
+		if rg.selectItem(rg.cursor) {
+			return true, rg.selected, rg.labels[rg.selected]
+		}
+	}
+	return false, 0, ""
+}
+
+// Keyboard moves the keyboard cursor and selects items.
+// Implements widgetapi.Widget.Keyboard.
+func (rg *RadioGroup) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return rg.changed(rg.keyboard(k))
+}
+
+// mouse processes the mouse event.
+// Returns true if the selection changed and the selected item.
+func (rg *RadioGroup) mouse(m *terminalapi.Mouse) (bool, int, string) {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		if rg.pressed {
+			return false, 0, ""
+		}
+		rg.pressed = true
+	case mouse.ButtonRelease:
+		rg.pressed = false
+		return false, 0, ""
+	default:
+		return false, 0, ""
+	}
+
+	if idx := m.Position.Y; idx >= 0 && idx < len(rg.labels) && rg.selectItem(idx) {
+		return true, rg.selected, rg.labels[rg.selected]
+	}
+	return false, 0, ""
+}
+
+// Mouse selects the clicked item.
+// Implements widgetapi.Widget.Mouse.
+func (rg *RadioGroup) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return rg.changed(rg.mouse(m))
+}
+
+// Options implements widgetapi.Widget.Options.
+func (rg *RadioGroup) Options() widgetapi.Options {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	gw := runewidth.StringWidth(rg.opts.selectedGlyph)
+	width := gw
+	for _, l := range rg.labels {
+		if l == "" {
+			continue
+		}
+		if w := gw + 1 + runewidth.StringWidth(l); w > width {
+			width = w
+		}
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{gw, len(rg.labels)},
+		MaximumSize:  image.Point{width, len(rg.labels)},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (rg *RadioGroup) SetDirtyFunc(markDirty func()) {
+	rg.dirty.Set(markDirty)
+}
@@ -0,0 +1,294 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radiogroup
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// changeTracker tracks calls to the ChangeFn.
+type changeTracker struct {
+	// calls are the labels the ChangeFn was called with.
+	calls []string
+	// wantErr when set to true, makes the ChangeFn return an error.
+	wantErr bool
+}
+
+// change implements ChangeFn.
+func (ct *changeTracker) change(index int, label string) error {
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, label)
+	return nil
+}
+
+// mustDrawItems draws the items with the selected one.
+func mustDrawItems(cvs *canvas.Canvas, labels []string, selected int) {
+	for i, l := range labels {
+		glyph := DefaultUnselectedGlyph
+		if i == selected {
+			glyph = DefaultSelectedGlyph
+		}
+		testdraw.MustText(cvs, glyph, image.Point{0, i})
+		testdraw.MustText(cvs, l, image.Point{4, i})
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	labels := []string{"low", "mid", "high"}
+
+	tests := []struct {
+		desc         string
+		labels       []string
+		opts         []Option
+		canvas       image.Rectangle
+		meta         *widgetapi.Meta
+		events       []terminalapi.Event
+		tracker      *changeTracker
+		want         func(size image.Point) *faketerm.Terminal
+		wantSelected int
+		wantCalls    []string
+		wantNewErr   bool
+		wantEventErr bool
+	}{
+		{
+			desc:       "fails without items",
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on glyphs of different widths",
+			labels:     labels,
+			opts:       []Option{Glyphs("(*)", "()")},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on selected index out of range",
+			labels:     labels,
+			opts:       []Option{Selected(3)},
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws the items",
+			labels: labels,
+			canvas: image.Rect(0, 0, 8, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawItems(cvs, labels, 0)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the initially selected item with cell options",
+			labels: labels,
+			opts: []Option{
+				Selected(1),
+				SelectedCellOpts(cell.FgColor(cell.ColorGreen)),
+			},
+			canvas: image.Rect(0, 0, 8, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawItems(cvs, labels, 1)
+				testdraw.MustText(cvs, DefaultSelectedGlyph, image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:   "requests resize when the items don't fit",
+			labels: labels,
+			canvas: image.Rect(0, 0, 8, 2),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "moves the cursor and selects using the keyboard",
+			labels: labels,
+			canvas: image.Rect(0, 0, 8, 3),
+			meta:   &widgetapi.Meta{Focused: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawItems(cvs, labels, 2)
+				testdraw.MustText(cvs, "mid", image.Point{4, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 2,
+			wantCalls:    []string{"high"},
+		},
+		{
+			desc:   "selects the clicked item",
+			labels: labels,
+			canvas: image.Rect(0, 0, 8, 3),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 1}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 2}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 2}, Button: mouse.ButtonRelease},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawItems(cvs, labels, 1)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+			wantCalls:    []string{"mid"},
+		},
+		{
+			desc:   "returns the error from the ChangeFn",
+			labels: labels,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			tracker:      &changeTracker{wantErr: true},
+			wantEventErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.tracker != nil {
+				opts = append(opts, OnChange(tc.tracker.change))
+			}
+			rg, err := New(tc.labels, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = rg.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = rg.Mouse(e, &widgetapi.EventMeta{})
+				}
+				if err != nil {
+					break
+				}
+			}
+			if (err != nil) != tc.wantEventErr {
+				t.Errorf("event => unexpected error: %v, wantEventErr: %v", err, tc.wantEventErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := rg.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if got, _ := rg.Selected(); got != tc.wantSelected {
+				t.Errorf("Selected => %d, want %d", got, tc.wantSelected)
+			}
+			if tc.tracker != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.tracker.calls); diff != "" {
+					t.Errorf("ChangeFn calls => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	rg, err := New([]string{"a", "b"})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := rg.Select(2); err == nil {
+		t.Errorf("Select(2) => unexpected success, want an error")
+	}
+	if err := rg.Select(1); err != nil {
+		t.Fatalf("Select(1) => unexpected error: %v", err)
+	}
+	if idx, label := rg.Selected(); idx != 1 || label != "b" {
+		t.Errorf("Selected => %d, %q, want 1, \"b\"", idx, label)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	rg, err := New([]string{"low", "high"})
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := rg.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{3, 2},
+		MaximumSize:  image.Point{8, 2},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
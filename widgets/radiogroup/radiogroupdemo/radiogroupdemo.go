@@ -0,0 +1,92 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary radiogroupdemo displays a RadioGroup widget that selects the color
+// of a text.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/radiogroup"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sample, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := sample.Write("Termdash"); err != nil {
+		panic(err)
+	}
+
+	colors := []cell.Color{cell.ColorDefault, cell.ColorRed, cell.ColorGreen, cell.ColorBlue}
+	rg, err := radiogroup.New(
+		[]string{"Default", "Red", "Green", "Blue"},
+		radiogroup.SelectedCellOpts(cell.FgColor(cell.ColorYellow)),
+		radiogroup.OnChange(func(index int, label string) error {
+			sample.Reset()
+			return sample.Write("Termdash", text.WriteCellOpts(cell.FgColor(colors[index])))
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Color"),
+				container.PlaceWidget(rg),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Sample"),
+				container.PlaceWidget(sample),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatterplot
+
+// options.go contains configurable options for ScatterPlot.
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/widgets/internal/axes"
+)
+
+// Option is used to provide options to New().
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	axesCellOpts        []cell.Option
+	xLabelCellOpts      []cell.Option
+	yLabelCellOpts      []cell.Option
+	xAxisCustomScale    *customScale
+	yAxisCustomScale    *customScale
+	yAxisMode           axes.YScaleMode
+	xAxisValueFormatter ValueFormatter
+	yAxisValueFormatter ValueFormatter
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if err := o.xAxisCustomScale.validate("XAxisCustomScale"); err != nil {
+		return err
+	}
+	return o.yAxisCustomScale.validate("YAxisCustomScale")
+}
+
+// newOptions returns a new options instance.
+func newOptions(opts ...Option) *options {
+	opt := &options{}
+	for _, o := range opts {
+		o.set(opt)
+	}
+	return opt
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// AxesCellOpts set the cell options for the X and Y axes.
+// These take precedence over the Axis color of the dashboard theme.
+func AxesCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.axesCellOpts = co
+	})
+}
+
+// XLabelCellOpts set the cell options for the labels on the X axis.
+// These take precedence over the Label color of the dashboard theme.
+func XLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.xLabelCellOpts = co
+	})
+}
+
+// YLabelCellOpts set the cell options for the labels on the Y axis.
+// These take precedence over the Label color of the dashboard theme.
+func YLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.yLabelCellOpts = co
+	})
+}
+
+// YAxisAdaptive makes the Y axis adapt its base value depending on the
+// provided series.
+// Without this option, the Y axis always starts at the zero value regardless of
+// values available in the series. The X axis always adapts to the values.
+func YAxisAdaptive() Option {
+	return option(func(opts *options) {
+		opts.yAxisMode = axes.YScaleModeAdaptive
+	})
+}
+
+// customScale is the custom scale provided via the XAxisCustomScale or the
+// YAxisCustomScale option.
+type customScale struct {
+	min, max float64
+}
+
+// validate validates the custom scale provided to the named option.
+// A nil custom scale is valid.
+func (cs *customScale) validate(name string) error {
+	if cs == nil {
+		return nil
+	}
+	if math.IsNaN(cs.min) || math.IsNaN(cs.max) || math.IsInf(cs.min, 0) || math.IsInf(cs.max, 0) {
+		return fmt.Errorf("both the min(%v) and the max(%v) provided to %s must be finite numbers", cs.min, cs.max, name)
+	}
+	if cs.min >= cs.max {
+		return fmt.Errorf("the min(%v) must be less than the max(%v) provided to %s", cs.min, cs.max, name)
+	}
+	return nil
+}
+
+// XAxisCustomScale when provided, the scale of the X axis is based on the
+// specified minimum and maximum value instead of determining those from the
+// series. Points outside of the range aren't drawn.
+// Both the minimum and the maximum must be finite numbers and the minimum must
+// be smaller than the maximum.
+func XAxisCustomScale(min, max float64) Option {
+	return option(func(opts *options) {
+		opts.xAxisCustomScale = &customScale{
+			min: min,
+			max: max,
+		}
+	})
+}
+
+// YAxisCustomScale when provided, the scale of the Y axis is based on the
+// specified minimum and maximum value instead of determining those from the
+// series. Points outside of the range aren't drawn.
+// Both the minimum and the maximum must be finite numbers and the minimum must
+// be smaller than the maximum.
+//
+// Providing this option also sets YAxisAdaptive.
+func YAxisCustomScale(min, max float64) Option {
+	return option(func(opts *options) {
+		opts.yAxisCustomScale = &customScale{
+			min: min,
+			max: max,
+		}
+		opts.yAxisMode = axes.YScaleModeAdaptive
+	})
+}
+
+// ValueFormatter formats a value on an axis into the text of its label.
+type ValueFormatter func(value float64) string
+
+// XAxisValueFormatter sets the formatter of the labels on the X axis.
+// By default the values are rounded to two non-zero decimal places.
+func XAxisValueFormatter(vf ValueFormatter) Option {
+	return option(func(opts *options) {
+		opts.xAxisValueFormatter = vf
+	})
+}
+
+// YAxisValueFormatter sets the formatter of the labels on the Y axis.
+// By default the values are rounded to two non-zero decimal places.
+func YAxisValueFormatter(vf ValueFormatter) Option {
+	return option(func(opts *options) {
+		opts.yAxisValueFormatter = vf
+	})
+}
@@ -0,0 +1,474 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scatterplot contains a widget that plots points on X and Y axes.
+package scatterplot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/theme"
+	"github.com/mum4k/termdash/widgetapi"
+	"github.com/mum4k/termdash/widgets/internal/axes"
+)
+
+const (
+	// nonZeroDecimals determines the precision of the values on the X axis
+	// labels, it matches the precision of the Y axis labels.
+	nonZeroDecimals = 2
+
+	// xAxisHeight is the height of the X axis and its labels.
+	xAxisHeight = 2
+
+	// xLabelSpacing is the minimum number of empty cells between the labels
+	// on the X axis.
+	xLabelSpacing = 2
+)
+
+// Marker is the shape drawn for each point of a series.
+type Marker int
+
+// String implements fmt.Stringer()
+func (m Marker) String() string {
+	if n, ok := markerNames[m]; ok {
+		return n
+	}
+	return "MarkerUnknown"
+}
+
+// markerNames maps Marker values to human readable names.
+var markerNames = map[Marker]string{
+	MarkerDot:    "MarkerDot",
+	MarkerPlus:   "MarkerPlus",
+	MarkerCross:  "MarkerCross",
+	MarkerSquare: "MarkerSquare",
+}
+
+const (
+	// MarkerDot draws each point as a single braille pixel.
+	MarkerDot Marker = iota
+
+	// MarkerPlus draws each point as a plus sign three pixels wide and high.
+	MarkerPlus
+
+	// MarkerCross draws each point as a diagonal cross three pixels wide and
+	// high.
+	MarkerCross
+
+	// MarkerSquare draws each point as a square two pixels wide and high.
+	MarkerSquare
+)
+
+// markerPixels are the pixels of each marker relative to the pixel of the
+// point.
+var markerPixels = map[Marker][]image.Point{
+	MarkerDot:    {{0, 0}},
+	MarkerPlus:   {{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {0, 1}},
+	MarkerCross:  {{0, 0}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}},
+	MarkerSquare: {{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+}
+
+// series is one series of points.
+type series struct {
+	xs, ys   []float64
+	cellOpts []cell.Option
+	marker   Marker
+}
+
+// SeriesOption is used to provide options to Series.
+type SeriesOption interface {
+	// set sets the provided option.
+	set(*series)
+}
+
+// seriesOption implements SeriesOption.
+type seriesOption func(*series)
+
+// set implements SeriesOption.set.
+func (so seriesOption) set(s *series) {
+	so(s)
+}
+
+// SeriesCellOpts sets the cell options for this series, e.g. its color.
+// Note that the braille canvas has resolution of 2x4 pixels per cell, but each
+// cell can only have one set of cell options set. Meaning that where series
+// share a cell, the last drawn series sets the cell options. Series are drawn
+// in alphabetical order based on their name.
+func SeriesCellOpts(co ...cell.Option) SeriesOption {
+	return seriesOption(func(s *series) {
+		s.cellOpts = co
+	})
+}
+
+// SeriesMarker sets the shape drawn for each point of this series.
+// Defaults to MarkerDot.
+func SeriesMarker(m Marker) SeriesOption {
+	return seriesOption(func(s *series) {
+		s.marker = m
+	})
+}
+
+// ScatterPlot plots series of points with X and Y coordinates.
+//
+// The scale of both axes is determined from the points of all the series
+// unless a custom scale is provided via the options.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type ScatterPlot struct {
+	// series are the series to plot keyed by their labels.
+	series map[string]*series
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the ScatterPlot.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new ScatterPlot widget.
+func New(opts ...Option) (*ScatterPlot, error) {
+	opt := newOptions(opts...)
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &ScatterPlot{
+		series: map[string]*series{},
+		opts:   opt,
+	}, nil
+}
+
+// Series sets the points of the series with the specified label, replacing
+// any points previously provided for it. The point at index i has the
+// coordinates xs[i] and ys[i], so both slices must have the same length.
+// Points with a NaN coordinate aren't drawn.
+func (sp *ScatterPlot) Series(label string, xs, ys []float64, opts ...SeriesOption) error {
+	if len(xs) != len(ys) {
+		return fmt.Errorf("the X(len:%d) and Y(len:%d) coordinates must have the same length", len(xs), len(ys))
+	}
+	for i := range xs {
+		if math.IsInf(xs[i], 0) || math.IsInf(ys[i], 0) {
+			return fmt.Errorf("invalid point %d (%v, %v), the coordinates cannot be infinite", i, xs[i], ys[i])
+		}
+	}
+	s := &series{
+		// Copy to avoid external modifications.
+		xs: append([]float64(nil), xs...),
+		ys: append([]float64(nil), ys...),
+	}
+	for _, opt := range opts {
+		opt.set(s)
+	}
+	if _, ok := markerNames[s.marker]; !ok {
+		return fmt.Errorf("invalid Marker %v(%d)", s.marker, s.marker)
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	defer sp.dirty.Mark()
+	sp.series[label] = s
+	return nil
+}
+
+// RemoveSeries removes the series with the specified label. Does nothing if
+// the series doesn't exist.
+func (sp *ScatterPlot) RemoveSeries(label string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	defer sp.dirty.Mark()
+	delete(sp.series, label)
+}
+
+// minMax returns the minimum and the maximum of the coordinates of all the
+// points, ignoring points with a NaN coordinate. Returns zeroes if there are
+// no such points.
+// The caller must hold sp.mu.
+func (sp *ScatterPlot) minMax() (xMin, xMax, yMin, yMax float64) {
+	first := true
+	for _, s := range sp.series {
+		for i := range s.xs {
+			x, y := s.xs[i], s.ys[i]
+			if math.IsNaN(x) || math.IsNaN(y) {
+				continue
+			}
+			if first {
+				xMin, xMax, yMin, yMax = x, x, y, y
+				first = false
+				continue
+			}
+			xMin, xMax = math.Min(xMin, x), math.Max(xMax, x)
+			yMin, yMax = math.Min(yMin, y), math.Max(yMax, y)
+		}
+	}
+	return xMin, xMax, yMin, yMax
+}
+
+// yRange returns the range of the Y axis.
+// The caller must hold sp.mu.
+func (sp *ScatterPlot) yRange() (float64, float64) {
+	if cs := sp.opts.yAxisCustomScale; cs != nil {
+		return cs.min, cs.max
+	}
+	_, _, min, max := sp.minMax()
+	return min, max
+}
+
+// xRange returns the range of the X axis.
+// The caller must hold sp.mu.
+func (sp *ScatterPlot) xRange() (float64, float64) {
+	if cs := sp.opts.xAxisCustomScale; cs != nil {
+		return cs.min, cs.max
+	}
+	min, max, _, _ := sp.minMax()
+	return min, max
+}
+
+// xScale maps values on the X axis to pixels on the braille canvas.
+type xScale struct {
+	min, max float64
+	// pixels is the width of the graph in pixels.
+	pixels int
+}
+
+// newXScale returns a new scale for the X axis of a graph with the width in
+// cells. If the minimum and the maximum are equal, the scale is anchored at
+// the zero value so that the points can still be drawn.
+func newXScale(min, max float64, graphWidth int) *xScale {
+	if min == max {
+		switch {
+		case min > 0:
+			min = 0
+		case max < 0:
+			max = 0
+		default:
+			max = 1
+		}
+	}
+	return &xScale{
+		min:    min,
+		max:    max,
+		pixels: graphWidth * braille.ColMult,
+	}
+}
+
+// valueToPixel returns the X coordinate of the pixel that represents the
+// value. Returns false if the value falls outside of the scale.
+func (xs *xScale) valueToPixel(v float64) (int, bool) {
+	if v < xs.min || v > xs.max {
+		return 0, false
+	}
+	return int(math.Round((v - xs.min) / (xs.max - xs.min) * float64(xs.pixels-1))), true
+}
+
+// pixelToValue returns the value represented by the X coordinate of the
+// pixel.
+func (xs *xScale) pixelToValue(x int) float64 {
+	return xs.min + float64(x)/float64(xs.pixels-1)*(xs.max-xs.min)
+}
+
+// Draw draws the series onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (sp *ScatterPlot) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	needAr, err := area.FromSize(sp.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	yMin, yMax := sp.yRange()
+	yd, err := axes.NewYDetails(cvs.Area(), &axes.YProperties{
+		Min:            yMin,
+		Max:            yMax,
+		ReqXHeight:     xAxisHeight,
+		ScaleMode:      sp.opts.yAxisMode,
+		ValueFormatter: sp.opts.yAxisValueFormatter,
+	})
+	if err != nil {
+		return fmt.Errorf("NewYDetails => %v", err)
+	}
+	graphAr := image.Rect(yd.Start.X+1, yd.Start.Y, cvs.Area().Max.X, yd.End.Y)
+	xMin, xMax := sp.xRange()
+	xs := newXScale(xMin, xMax, graphAr.Dx())
+
+	if err := sp.drawSeries(cvs, graphAr, xs, yd); err != nil {
+		return err
+	}
+	var th *theme.Theme
+	if meta != nil {
+		th = meta.Theme
+	}
+	return sp.drawAxes(cvs, graphAr, xs, yd, th)
+}
+
+// drawSeries draws the points of all the series onto the graph area.
+func (sp *ScatterPlot) drawSeries(cvs *canvas.Canvas, graphAr image.Rectangle, xs *xScale, yd *axes.YDetails) error {
+	bc, err := braille.New(graphAr)
+	if err != nil {
+		return fmt.Errorf("braille.New => %v", err)
+	}
+
+	var names []string
+	for name := range sp.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pixelAr := bc.Area()
+	for _, name := range names {
+		s := sp.series[name]
+		for i := range s.xs {
+			x, y := s.xs[i], s.ys[i]
+			if math.IsNaN(x) || math.IsNaN(y) || y < yd.Scale.Min.Value || y > yd.Scale.Max.Value {
+				continue
+			}
+			px, ok := xs.valueToPixel(x)
+			if !ok {
+				continue
+			}
+			py, err := yd.Scale.ValueToPixel(y)
+			if err != nil {
+				return fmt.Errorf("failure for point (%v, %v) on scale %v, yd.Scale.ValueToPixel => %v", x, y, yd.Scale, err)
+			}
+
+			for _, d := range markerPixels[s.marker] {
+				p := image.Point{px + d.X, py + d.Y}
+				if !p.In(pixelAr) {
+					continue
+				}
+				if err := bc.SetPixel(p, s.cellOpts...); err != nil {
+					return fmt.Errorf("SetPixel(%v) => %v", p, err)
+				}
+			}
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// drawAxes draws the X,Y axes and their labels.
+func (sp *ScatterPlot) drawAxes(cvs *canvas.Canvas, graphAr image.Rectangle, xs *xScale, yd *axes.YDetails, th *theme.Theme) error {
+	axesCellOpts := sp.opts.axesCellOpts
+	xLabelCellOpts := sp.opts.xLabelCellOpts
+	yLabelCellOpts := sp.opts.yLabelCellOpts
+	if th != nil {
+		// The cell options provided by the user take precedence over the
+		// theme, since the last cell option wins.
+		axesCellOpts = append([]cell.Option{cell.FgColor(th.Axis)}, axesCellOpts...)
+		xLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, xLabelCellOpts...)
+		yLabelCellOpts = append([]cell.Option{cell.FgColor(th.Label)}, yLabelCellOpts...)
+	}
+
+	lines := []draw.HVLine{
+		{Start: yd.Start, End: yd.End},
+		{Start: yd.End, End: image.Point{graphAr.Max.X - 1, yd.End.Y}},
+	}
+	if err := draw.HVLines(cvs, lines, draw.HVLineCellOpts(axesCellOpts...)); err != nil {
+		return fmt.Errorf("failed to draw the axes: %v", err)
+	}
+
+	for _, l := range yd.Labels {
+		if err := draw.Text(cvs, l.Value.Text(), l.Pos,
+			draw.TextMaxX(yd.Start.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(yLabelCellOpts...),
+		); err != nil {
+			return fmt.Errorf("failed to draw the Y labels: %v", err)
+		}
+	}
+
+	for _, l := range sp.xLabels(graphAr, xs) {
+		if err := draw.Text(cvs, l.Value.Text(), l.Pos, draw.TextCellOpts(xLabelCellOpts...)); err != nil {
+			return fmt.Errorf("failed to draw the X labels: %v", err)
+		}
+	}
+	return nil
+}
+
+// xLabels returns the labels under the X axis. The labels start under the
+// first column of the graph and are placed left to right while they fit.
+func (sp *ScatterPlot) xLabels(graphAr image.Rectangle, xs *xScale) []*axes.Label {
+	var labels []*axes.Label
+	row := graphAr.Max.Y + 1
+	for x := graphAr.Min.X; x < graphAr.Max.X; {
+		v := xs.pixelToValue((x - graphAr.Min.X) * braille.ColMult)
+		value := axes.NewValue(v, nonZeroDecimals, axes.ValueFormatter(sp.opts.xAxisValueFormatter))
+		width := runewidth.StringWidth(value.Text())
+		if x+width > graphAr.Max.X {
+			break
+		}
+		labels = append(labels, &axes.Label{
+			Value: value,
+			Pos:   image.Point{x, row},
+		})
+		x += width + xLabelSpacing
+	}
+	return labels
+}
+
+// Keyboard input isn't supported on the ScatterPlot widget.
+func (*ScatterPlot) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the ScatterPlot widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the ScatterPlot widget.
+func (*ScatterPlot) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the ScatterPlot widget doesn't support mouse events")
+}
+
+// minSize determines the minimum required size to draw the scatter plot.
+func (sp *ScatterPlot) minSize() image.Point {
+	// At the very least we need:
+	// - n cells width for the Y axis and its labels as reported by it.
+	// - at least 1 cell width for the graph.
+	yMin, yMax := sp.yRange()
+	reqWidth := axes.RequiredWidth(yMin, yMax) + 1
+
+	// And for the height:
+	// - the X axis and its labels.
+	// - at least 2 cell height for the graph.
+	return image.Point{reqWidth, xAxisHeight + 2}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (sp *ScatterPlot) Options() widgetapi.Options {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize: sp.minSize(),
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (sp *ScatterPlot) SetDirtyFunc(markDirty func()) {
+	sp.dirty.Set(markDirty)
+}
@@ -0,0 +1,368 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatterplot
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/theme"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustAxes draws the Y axis at column x and the X axis at row y up to the
+// column maxX.
+func mustAxes(c *canvas.Canvas, x, y, maxX int, opts ...cell.Option) {
+	testdraw.MustHVLines(c, []draw.HVLine{
+		{Start: image.Point{x, 0}, End: image.Point{x, y}},
+		{Start: image.Point{x, y}, End: image.Point{maxX, y}},
+	}, draw.HVLineCellOpts(opts...))
+}
+
+func TestScatterPlot(t *testing.T) {
+	tests := []struct {
+		desc         string
+		opts         []Option
+		canvas       image.Rectangle
+		writes       func(*ScatterPlot) error
+		want         func(size image.Point) *faketerm.Terminal
+		meta         *widgetapi.Meta
+		wantNewErr   bool
+		wantWriteErr bool
+	}{
+		{
+			desc:       "fails on custom X scale with min equal to max",
+			opts:       []Option{XAxisCustomScale(1, 1)},
+			canvas:     image.Rect(0, 0, 10, 6),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on custom Y scale with NaN",
+			opts:       []Option{YAxisCustomScale(math.NaN(), 1)},
+			canvas:     image.Rect(0, 0, 10, 6),
+			wantNewErr: true,
+		},
+		{
+			desc:   "fails when the coordinates have different lengths",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{1, 2}, []float64{1})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "fails on an infinite coordinate",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{math.Inf(1)}, []float64{1})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "fails on an unsupported marker",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{1}, []float64{1}, SeriesMarker(Marker(-1)))
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "draws resize needed character when canvas is too small",
+			canvas: image.Rect(0, 0, 2, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the axes without any series",
+			canvas: image.Rect(0, 0, 10, 5),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 1, 3, 9)
+				testdraw.MustText(c, "0", image.Point{0, 2})
+				testdraw.MustText(c, "0", image.Point{2, 4})
+				testdraw.MustText(c, "0.40", image.Point{5, 4})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws points of a series",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{0, 4, 8}, []float64{0, 2, 4}, SeriesCellOpts(cell.FgColor(cell.ColorRed)))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 9)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 10, 4))
+				opts := []cell.Option{cell.FgColor(cell.ColorRed)}
+				testbraille.MustSetPixel(bc, image.Point{0, 15}, opts...)
+				testbraille.MustSetPixel(bc, image.Point{5, 8}, opts...)
+				testbraille.MustSetPixel(bc, image.Point{9, 0}, opts...)
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "skips points with NaN coordinates",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{0, 4, 8, math.NaN()}, []float64{0, 2, 4, 100})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 9)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 10, 4))
+				testbraille.MustSetPixel(bc, image.Point{0, 15})
+				testbraille.MustSetPixel(bc, image.Point{5, 8})
+				testbraille.MustSetPixel(bc, image.Point{9, 0})
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom scales hide points outside of the range",
+			opts: []Option{
+				XAxisCustomScale(0, 4),
+				YAxisCustomScale(0, 4),
+			},
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{0, 2, 8}, []float64{0, 2, 4}, SeriesMarker(MarkerSquare))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 9)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 10, 4))
+				for _, p := range []image.Point{
+					{0, 15}, {1, 15},
+					{5, 8}, {6, 8}, {5, 9}, {6, 9},
+				} {
+					testbraille.MustSetPixel(bc, p)
+				}
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "clips markers at the edges and formats the X labels",
+			opts: []Option{
+				XAxisValueFormatter(func(v float64) string { return fmt.Sprintf("%.0fB", v) }),
+			},
+			canvas: image.Rect(0, 0, 14, 6),
+			writes: func(sp *ScatterPlot) error {
+				return sp.Series("a", []float64{0, 100}, []float64{1, 1}, SeriesMarker(MarkerCross))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 13)
+				testdraw.MustText(c, "0.81", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0B", image.Point{5, 5})
+				testdraw.MustText(c, "47B", image.Point{9, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 14, 4))
+				for _, p := range []image.Point{
+					{0, 0}, {1, 1},
+					{17, 0}, {16, 1},
+				} {
+					testbraille.MustSetPixel(bc, p)
+				}
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws series in alphabetical order",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				if err := sp.Series("b", []float64{8}, []float64{4}, SeriesCellOpts(cell.FgColor(cell.ColorBlue))); err != nil {
+					return err
+				}
+				return sp.Series("a", []float64{0, 8}, []float64{0, 4}, SeriesCellOpts(cell.FgColor(cell.ColorRed)))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 9)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 10, 4))
+				testbraille.MustSetPixel(bc, image.Point{0, 15}, cell.FgColor(cell.ColorRed))
+				testbraille.MustSetPixel(bc, image.Point{9, 0}, cell.FgColor(cell.ColorBlue))
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "removed series aren't drawn",
+			canvas: image.Rect(0, 0, 10, 6),
+			writes: func(sp *ScatterPlot) error {
+				if err := sp.Series("b", []float64{100}, []float64{100}); err != nil {
+					return err
+				}
+				sp.RemoveSeries("b")
+				return sp.Series("a", []float64{0, 4, 8}, []float64{0, 2, 4})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 4, 4, 9)
+				testdraw.MustText(c, "3.24", image.Point{0, 0})
+				testdraw.MustText(c, "0", image.Point{3, 3})
+				testdraw.MustText(c, "0", image.Point{5, 5})
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 10, 4))
+				testbraille.MustSetPixel(bc, image.Point{0, 15})
+				testbraille.MustSetPixel(bc, image.Point{5, 8})
+				testbraille.MustSetPixel(bc, image.Point{9, 0})
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "uses colors from the theme",
+			canvas: image.Rect(0, 0, 10, 5),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Axis:  cell.ColorBlue,
+					Label: cell.ColorGreen,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustAxes(c, 1, 3, 9, cell.FgColor(cell.ColorBlue))
+				labelOpts := draw.TextCellOpts(cell.FgColor(cell.ColorGreen))
+				testdraw.MustText(c, "0", image.Point{0, 2}, labelOpts)
+				testdraw.MustText(c, "0", image.Point{2, 4}, labelOpts)
+				testdraw.MustText(c, "0.40", image.Point{5, 4}, labelOpts)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sp, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.writes != nil {
+				err := tc.writes(sp)
+				if (err != nil) != tc.wantWriteErr {
+					t.Errorf("writes => unexpected error: %v, wantWriteErr: %v", err, tc.wantWriteErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			meta := tc.meta
+			if meta == nil {
+				meta = &widgetapi.Meta{}
+			}
+			if err := sp.Draw(c, meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	sp, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := sp.Series("a", []float64{1}, []float64{100}); err != nil {
+		t.Fatalf("Series => unexpected error: %v", err)
+	}
+
+	got := sp.Options()
+	want := widgetapi.Options{
+		MinimumSize: image.Point{5, 4},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
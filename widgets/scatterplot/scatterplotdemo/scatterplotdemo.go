@@ -0,0 +1,116 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary scatterplotdemo displays a ScatterPlot widget with request latency
+// plotted against the payload size.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/scatterplot"
+)
+
+// requests returns the payload sizes in kilobytes and the latencies in
+// milliseconds of n random requests.
+func requests(n int, msPerKB, jitter float64) ([]float64, []float64) {
+	var sizes, latencies []float64
+	for i := 0; i < n; i++ {
+		size := rand.Float64() * 512
+		sizes = append(sizes, size)
+		latencies = append(latencies, size*msPerKB+rand.Float64()*jitter)
+	}
+	return sizes, latencies
+}
+
+// playScatterPlot periodically replaces the plotted requests.
+// Exits when the context expires.
+func playScatterPlot(ctx context.Context, sp *scatterplot.ScatterPlot, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		sizes, latencies := requests(60, 0.2, 40)
+		if err := sp.Series("cached", sizes, latencies,
+			scatterplot.SeriesCellOpts(cell.FgColor(cell.ColorGreen)),
+		); err != nil {
+			panic(err)
+		}
+		sizes, latencies = requests(30, 0.5, 80)
+		if err := sp.Series("uncached", sizes, latencies,
+			scatterplot.SeriesCellOpts(cell.FgColor(cell.ColorRed)),
+			scatterplot.SeriesMarker(scatterplot.MarkerPlus),
+		); err != nil {
+			panic(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sp, err := scatterplot.New(
+		scatterplot.AxesCellOpts(cell.FgColor(cell.ColorRed)),
+		scatterplot.XAxisValueFormatter(func(v float64) string {
+			return fmt.Sprintf("%.0fKB", v)
+		}),
+		scatterplot.YAxisValueFormatter(func(v float64) string {
+			return fmt.Sprintf("%.0fms", v)
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playScatterPlot(ctx, sp, 2*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(sp),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
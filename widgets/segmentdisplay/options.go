@@ -16,6 +16,7 @@ package segmentdisplay
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mum4k/termdash/align"
 )
@@ -42,6 +43,8 @@ type options struct {
 	vAlign          align.Vertical
 	maximizeSegSize bool
 	gapPercent      int
+	style           Style
+	marqueeInterval time.Duration
 }
 
 // validate validates the provided options.
@@ -49,6 +52,12 @@ func (o *options) validate() error {
 	if min, max := 0, 100; o.gapPercent < min || o.gapPercent > max {
 		return fmt.Errorf("invalid GapPercent %d, must be %d <= value <= %d", o.gapPercent, min, max)
 	}
+	if o.marqueeInterval < 0 {
+		return fmt.Errorf("invalid Marquee interval %v, must be a positive duration", o.marqueeInterval)
+	}
+	if _, ok := styleNames[o.style]; !ok {
+		return fmt.Errorf("unsupported Style %v", o.style)
+	}
 	return nil
 }
 
@@ -58,6 +67,7 @@ func newOptions() *options {
 		hAlign:     align.HorizontalCenter,
 		vAlign:     align.VerticalMiddle,
 		gapPercent: DefaultGapPercent,
+		style:      DefaultStyle,
 	}
 }
 
@@ -110,3 +120,59 @@ func GapPercent(perc int) Option {
 		opts.gapPercent = perc
 	})
 }
+
+// Style is the style of the simulated segment display.
+type Style int
+
+// String implements fmt.Stringer()
+func (s Style) String() string {
+	if n, ok := styleNames[s]; ok {
+		return n
+	}
+	return "StyleUnknown"
+}
+
+// styleNames maps Style values to human readable names.
+var styleNames = map[Style]string{
+	StyleSixteenSegment: "StyleSixteenSegment",
+	StyleSevenSegment:   "StyleSevenSegment",
+}
+
+const (
+	// StyleSixteenSegment simulates a sixteen-segment display which can
+	// display most of the ASCII characters.
+	StyleSixteenSegment Style = iota
+
+	// StyleSevenSegment simulates a seven-segment display which is well
+	// suited for digits and can display only a limited set of letters.
+	// Characters that cannot be displayed on a seven-segment display are
+	// drawn as blank segments.
+	StyleSevenSegment
+)
+
+// DefaultStyle is the default value for the DisplayStyle option.
+const DefaultStyle = StyleSixteenSegment
+
+// DisplayStyle sets the style of the simulated segment display.
+// The dot and colon characters are always displayed using the dot segment
+// regardless of the selected style.
+// Defaults to DefaultStyle.
+func DisplayStyle(s Style) Option {
+	return option(func(opts *options) {
+		opts.style = s
+	})
+}
+
+// Marquee makes text that doesn't fit the canvas scroll horizontally, moving
+// by one segment each time the interval elapses. The text is displayed in
+// segments of the largest height, as if MaximizeSegmentHeight was provided,
+// and repeats after a gap of blank segments. Text that fits the canvas
+// doesn't scroll.
+// Since the widget only moves when it is redrawn, the interval should be a
+// multiple of the redraw interval of termdash.
+// A zero interval disables scrolling, which is the default.
+func Marquee(interval time.Duration) Option {
+	return option(func(opts *options) {
+		opts.marqueeInterval = interval
+	})
+}
@@ -22,10 +22,12 @@ import (
 	"image"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/attrrange"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/segdisp"
 	"github.com/mum4k/termdash/private/segdisp/dotseg"
 	"github.com/mum4k/termdash/private/segdisp/sixteen"
@@ -57,9 +59,21 @@ type SegmentDisplay struct {
 	lastCanFit int
 
 	// dotChars are characters that are drawn using the dot segment.
-	// All other characters are draws using the 16-segment display.
+	// All other characters are drawn using the 16-segment display, which also
+	// simulates the seven-segment display when StyleSevenSegment is selected.
 	dotChars map[rune]bool
 
+	// marqueeOffset is the position in the text displayed in the first
+	// segment when the text scrolls, see the Marquee option.
+	marqueeOffset int
+	// lastShift is the time the text last scrolled and shiftDrawn indicates
+	// that the current text was already drawn.
+	lastShift  time.Time
+	shiftDrawn bool
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the widget.
 	mu sync.Mutex
 
@@ -117,6 +131,7 @@ func NewChunk(text string, wOpts ...WriteOption) *TextChunk {
 func (sd *SegmentDisplay) Write(chunks []*TextChunk, opts ...Option) error {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
+	defer sd.dirty.Mark()
 
 	for _, o := range opts {
 		o.set(sd.opts)
@@ -128,7 +143,14 @@ func (sd *SegmentDisplay) Write(chunks []*TextChunk, opts ...Option) error {
 	if len(chunks) == 0 {
 		return errors.New("at least one text chunk must be specified")
 	}
+	prevText, offset, lastShift, shiftDrawn := sd.buff.String(), sd.marqueeOffset, sd.lastShift, sd.shiftDrawn
 	sd.reset()
+	defer func() {
+		// Writing the same text again doesn't restart the scrolling.
+		if sd.buff.String() == prevText {
+			sd.marqueeOffset, sd.lastShift, sd.shiftDrawn = offset, lastShift, shiftDrawn
+		}
+	}()
 
 	for i, tc := range chunks {
 		if tc.text == "" {
@@ -168,6 +190,7 @@ func (sd *SegmentDisplay) Capacity() int {
 func (sd *SegmentDisplay) Reset() {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
+	defer sd.dirty.Mark()
 	sd.reset()
 }
 
@@ -177,6 +200,67 @@ func (sd *SegmentDisplay) reset() {
 	sd.buff.Reset()
 	sd.givenWOpts = nil
 	sd.wOptsTracker = attrrange.NewTracker()
+	sd.marqueeOffset = 0
+	sd.shiftDrawn = false
+}
+
+// marqueeGap is the number of blank segments between the end and the
+// repeated start of text that scrolls.
+const marqueeGap = 3
+
+// scrolls asserts whether the text scrolls because it doesn't fit the
+// segments in the area.
+func (sd *SegmentDisplay) scrolls(segAr *segArea) bool {
+	return sd.opts.marqueeInterval > 0 && sd.buff.Len() > segAr.canFit
+}
+
+// scroll moves the text by one segment if the marquee interval elapsed since
+// it last moved.
+// Caller must hold sd.mu.
+func (sd *SegmentDisplay) scroll(now time.Time) {
+	if !sd.shiftDrawn {
+		sd.shiftDrawn = true
+		sd.lastShift = now
+		return
+	}
+	if now.Sub(sd.lastShift) < sd.opts.marqueeInterval {
+		return
+	}
+	sd.marqueeOffset = (sd.marqueeOffset + 1) % (sd.buff.Len() + marqueeGap)
+	sd.lastShift = now
+}
+
+// displayed returns the characters displayed in the segments along with their
+// positions in the text. The position is -1 for the blank segments between
+// the end and the repeated start of text that scrolls.
+func (sd *SegmentDisplay) displayed(segAr *segArea) ([]rune, []int) {
+	text := []rune(sd.buff.String())
+	var (
+		chars     []rune
+		positions []int
+	)
+	if !sd.scrolls(segAr) {
+		for i, c := range text {
+			if i >= segAr.canFit {
+				break
+			}
+			chars = append(chars, c)
+			positions = append(positions, i)
+		}
+		return chars, positions
+	}
+
+	for i := 0; i < segAr.canFit; i++ {
+		pos := (sd.marqueeOffset + i) % (len(text) + marqueeGap)
+		if pos >= len(text) {
+			chars = append(chars, ' ')
+			positions = append(positions, -1)
+			continue
+		}
+		chars = append(chars, text[pos])
+		positions = append(positions, pos)
+	}
+	return chars, positions
 }
 
 // preprocess determines the size of individual segments maximizing their
@@ -191,7 +275,7 @@ func (sd *SegmentDisplay) preprocess(cvsAr image.Rectangle) (*segArea, error) {
 	}
 
 	need := sd.buff.Len()
-	if (need > 0 && need <= segAr.canFit) || sd.opts.maximizeSegSize {
+	if (need > 0 && need <= segAr.canFit) || sd.opts.maximizeSegSize || sd.opts.marqueeInterval > 0 {
 		return segAr, nil
 	}
 
@@ -218,24 +302,20 @@ func (sd *SegmentDisplay) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return nil
 	}
 
-	text := sd.buff.String()
+	if sd.scrolls(segAr) {
+		sd.scroll(time.Now())
+		// The next scroll position needs to be drawn.
+		sd.dirty.Mark()
+	}
+	chars, positions := sd.displayed(segAr)
 	aligned, err := alignfor.Rectangle(cvs.Area(), segAr.needArea(), sd.opts.hAlign, sd.opts.vAlign)
 	if err != nil {
 		return fmt.Errorf("alignfor.Rectangle => %v", err)
 	}
 
-	optRange, err := sd.wOptsTracker.ForPosition(0) // Text options for the current byte.
-	if err != nil {
-		return err
-	}
-
 	gaps := segAr.gaps
 	startX := aligned.Min.X
-	for i, c := range text {
-		if i >= segAr.canFit {
-			break
-		}
-
+	for i, c := range chars {
 		endX := startX + segAr.segment.Dx()
 		ar := image.Rect(startX, aligned.Min.Y, endX, aligned.Max.Y)
 		startX = endX
@@ -249,14 +329,14 @@ func (sd *SegmentDisplay) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 			return fmt.Errorf("canvas.New => %v", err)
 		}
 
-		if i >= optRange.High { // Get the next write options.
-			or, err := sd.wOptsTracker.ForPosition(i)
+		wOpts := newWriteOptions()
+		if pos := positions[i]; pos >= 0 {
+			optRange, err := sd.wOptsTracker.ForPosition(pos) // Text options for the current byte.
 			if err != nil {
 				return err
 			}
-			optRange = or
+			wOpts = sd.givenWOpts[optRange.AttrIdx]
 		}
-		wOpts := sd.givenWOpts[optRange.AttrIdx]
 		if err := sd.drawChar(dCvs, c, wOpts); err != nil {
 			return err
 		}
@@ -281,9 +361,18 @@ func (sd *SegmentDisplay) drawChar(dCvs *canvas.Canvas, c rune, wOpts *writeOpti
 		return nil
 	}
 
-	disp := sixteen.New()
-	if err := disp.SetCharacter(c); err != nil {
-		return fmt.Errorf("sixteen.Display.SetCharacter => %v", err)
+	var disp *sixteen.Display
+	if sd.opts.style == StyleSevenSegment {
+		d, err := sevenSegmentDisplay(c)
+		if err != nil {
+			return fmt.Errorf("sevenSegmentDisplay => %v", err)
+		}
+		disp = d
+	} else {
+		disp = sixteen.New()
+		if err := disp.SetCharacter(c); err != nil {
+			return fmt.Errorf("sixteen.Display.SetCharacter => %v", err)
+		}
 	}
 	if err := disp.Draw(dCvs, sixteen.CellOpts(wOpts.cellOpts...)); err != nil {
 		return fmt.Errorf("sixteen.Display.Draw => %v", err)
@@ -310,3 +399,8 @@ func (sd *SegmentDisplay) Options() widgetapi.Options {
 		WantMouse:    widgetapi.MouseScopeNone,
 	}
 }
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (sd *SegmentDisplay) SetDirtyFunc(markDirty func()) {
+	sd.dirty.Set(markDirty)
+}
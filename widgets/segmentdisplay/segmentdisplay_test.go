@@ -17,6 +17,7 @@ package segmentdisplay
 import (
 	"image"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/align"
@@ -226,6 +227,44 @@ func TestSegmentDisplay(t *testing.T) {
 			},
 			wantCapacity: 3,
 		},
+		{
+			desc: "New fails on unsupported DisplayStyle",
+			opts: []Option{
+				DisplayStyle(Style(-1)),
+			},
+			canvas:     image.Rect(0, 0, segdisp.MinCols, segdisp.MinRows),
+			wantNewErr: true,
+		},
+		{
+			desc: "draws characters on a seven-segment display",
+			opts: []Option{
+				GapPercent(0),
+				DisplayStyle(StyleSevenSegment),
+			},
+			canvas: image.Rect(0, 0, segdisp.MinCols*3, segdisp.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("1:X")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				one := testcanvas.MustNew(image.Rect(0, 0, segdisp.MinCols, segdisp.MinRows))
+				d := sixteen.New()
+				testsixteen.MustSetSegment(d, sixteen.B)
+				testsixteen.MustSetSegment(d, sixteen.C)
+				testsixteen.MustDraw(d, one)
+				testcanvas.MustCopyTo(one, cvs)
+
+				// The colon still uses the dot segment.
+				mustDrawChar(cvs, ':', image.Rect(segdisp.MinCols, 0, segdisp.MinCols*2, segdisp.MinRows))
+				// The X cannot be displayed on a seven-segment display and
+				// remains blank.
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 3,
+		},
 		{
 			desc: "write sanitizes text by default",
 			opts: []Option{
@@ -622,6 +661,67 @@ func TestSegmentDisplay(t *testing.T) {
 			},
 			wantCapacity: 2,
 		},
+		{
+			desc: "New fails on negative Marquee interval",
+			opts: []Option{
+				Marquee(-1 * time.Second),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "marquee displays the window of text at the current offset",
+			opts: []Option{
+				Marquee(time.Hour),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, segdisp.MinCols*2, segdisp.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				if err := sd.Write([]*TextChunk{NewChunk("123")}); err != nil {
+					return err
+				}
+				sd.marqueeOffset = 2
+				sd.shiftDrawn = true
+				sd.lastShift = time.Now()
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				// The second segment is the blank gap before the text repeats.
+				mustDrawChar(cvs, '3', image.Rect(0, 0, segdisp.MinCols, segdisp.MinRows))
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "marquee doesn't scroll text that fits",
+			opts: []Option{
+				Marquee(time.Hour),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, segdisp.MinCols*2, segdisp.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				if err := sd.Write([]*TextChunk{NewChunk("12")}); err != nil {
+					return err
+				}
+				sd.marqueeOffset = 1
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				mustDrawChar(cvs, '1', image.Rect(0, 0, segdisp.MinCols, segdisp.MinRows))
+				mustDrawChar(cvs, '2', image.Rect(segdisp.MinCols, 0, segdisp.MinCols*2, segdisp.MinRows))
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
 		{
 			desc: "draws multiple segments, not enough space, maximizes displayed text by default and fits all",
 			opts: []Option{
@@ -977,6 +1077,44 @@ func TestSegmentDisplay(t *testing.T) {
 	}
 }
 
+func TestScroll(t *testing.T) {
+	sd, err := New(Marquee(time.Second))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := sd.Write([]*TextChunk{NewChunk("ab")}); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	for _, tc := range []struct {
+		desc  string
+		now   time.Time
+		write string
+		want  int
+	}{
+		{desc: "first draw doesn't scroll", now: start, want: 0},
+		{desc: "doesn't scroll before the interval", now: start.Add(500 * time.Millisecond), want: 0},
+		{desc: "scrolls after the interval", now: start.Add(time.Second), want: 1},
+		{desc: "scrolls through the gap", now: start.Add(2 * time.Second), want: 2},
+		{desc: "writing the same text keeps the offset", now: start.Add(2 * time.Second), write: "ab", want: 2},
+		{desc: "scrolls to the end of the gap", now: start.Add(4 * time.Second), want: 3},
+		{desc: "wraps around to the start", now: start.Add(5 * time.Second), want: 4},
+		{desc: "wraps around to the start", now: start.Add(6 * time.Second), want: 0},
+		{desc: "writing new text resets the offset", now: start.Add(7 * time.Second), write: "cd", want: 0},
+	} {
+		if tc.write != "" {
+			if err := sd.Write([]*TextChunk{NewChunk(tc.write)}); err != nil {
+				t.Fatalf("Write => unexpected error: %v", err)
+			}
+		}
+		sd.scroll(tc.now)
+		if got := sd.marqueeOffset; got != tc.want {
+			t.Errorf("%s: marqueeOffset => %d, want %d", tc.desc, got, tc.want)
+		}
+	}
+}
+
 func TestKeyboard(t *testing.T) {
 	sd, err := New()
 	if err != nil {
@@ -0,0 +1,126 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segmentdisplay
+
+// seven_segment.go simulates a seven-segment display using the segments of
+// the sixteen-segment display.
+
+import "github.com/mum4k/termdash/private/segdisp/sixteen"
+
+// The seven segments of a seven-segment display expressed as segments of the
+// sixteen-segment display.
+//
+//	   a
+//	 -----
+//	|     |
+//	f     b
+//	|  g  |
+//	 -----
+//	|     |
+//	e     c
+//	|     |
+//	 -----
+//	   d
+var (
+	sevenA = []sixteen.Segment{sixteen.A1, sixteen.A2}
+	sevenB = []sixteen.Segment{sixteen.B}
+	sevenC = []sixteen.Segment{sixteen.C}
+	sevenD = []sixteen.Segment{sixteen.D1, sixteen.D2}
+	sevenE = []sixteen.Segment{sixteen.E}
+	sevenF = []sixteen.Segment{sixteen.F}
+	sevenG = []sixteen.Segment{sixteen.G1, sixteen.G2}
+)
+
+// sevenSegs combines the provided seven-segment display segments.
+func sevenSegs(segs ...[]sixteen.Segment) []sixteen.Segment {
+	var res []sixteen.Segment
+	for _, s := range segs {
+		res = append(res, s...)
+	}
+	return res
+}
+
+// sevenCharacterSegments maps characters that can be displayed on a
+// seven-segment display to their segments.
+var sevenCharacterSegments = map[rune][]sixteen.Segment{
+	' ': nil,
+	'-': sevenG,
+	'_': sevenD,
+	'=': sevenSegs(sevenG, sevenD),
+
+	'0': sevenSegs(sevenA, sevenB, sevenC, sevenD, sevenE, sevenF),
+	'1': sevenSegs(sevenB, sevenC),
+	'2': sevenSegs(sevenA, sevenB, sevenG, sevenE, sevenD),
+	'3': sevenSegs(sevenA, sevenB, sevenG, sevenC, sevenD),
+	'4': sevenSegs(sevenF, sevenG, sevenB, sevenC),
+	'5': sevenSegs(sevenA, sevenF, sevenG, sevenC, sevenD),
+	'6': sevenSegs(sevenA, sevenF, sevenG, sevenE, sevenC, sevenD),
+	'7': sevenSegs(sevenA, sevenB, sevenC),
+	'8': sevenSegs(sevenA, sevenB, sevenC, sevenD, sevenE, sevenF, sevenG),
+	'9': sevenSegs(sevenA, sevenB, sevenC, sevenD, sevenF, sevenG),
+
+	'A': sevenSegs(sevenA, sevenB, sevenC, sevenE, sevenF, sevenG),
+	'a': sevenSegs(sevenA, sevenB, sevenC, sevenE, sevenF, sevenG),
+	'B': sevenSegs(sevenF, sevenE, sevenD, sevenC, sevenG),
+	'b': sevenSegs(sevenF, sevenE, sevenD, sevenC, sevenG),
+	'C': sevenSegs(sevenA, sevenF, sevenE, sevenD),
+	'c': sevenSegs(sevenG, sevenE, sevenD),
+	'D': sevenSegs(sevenB, sevenC, sevenD, sevenE, sevenG),
+	'd': sevenSegs(sevenB, sevenC, sevenD, sevenE, sevenG),
+	'E': sevenSegs(sevenA, sevenF, sevenG, sevenE, sevenD),
+	'e': sevenSegs(sevenA, sevenF, sevenG, sevenE, sevenD),
+	'F': sevenSegs(sevenA, sevenF, sevenG, sevenE),
+	'f': sevenSegs(sevenA, sevenF, sevenG, sevenE),
+	'G': sevenSegs(sevenA, sevenF, sevenE, sevenD, sevenC),
+	'g': sevenSegs(sevenA, sevenF, sevenE, sevenD, sevenC),
+	'H': sevenSegs(sevenF, sevenE, sevenB, sevenC, sevenG),
+	'h': sevenSegs(sevenF, sevenE, sevenG, sevenC),
+	'I': sevenSegs(sevenF, sevenE),
+	'i': sevenSegs(sevenE),
+	'J': sevenSegs(sevenB, sevenC, sevenD, sevenE),
+	'j': sevenSegs(sevenB, sevenC, sevenD),
+	'L': sevenSegs(sevenF, sevenE, sevenD),
+	'l': sevenSegs(sevenF, sevenE),
+	'N': sevenSegs(sevenE, sevenG, sevenC),
+	'n': sevenSegs(sevenE, sevenG, sevenC),
+	'O': sevenSegs(sevenA, sevenB, sevenC, sevenD, sevenE, sevenF),
+	'o': sevenSegs(sevenC, sevenD, sevenE, sevenG),
+	'P': sevenSegs(sevenA, sevenB, sevenF, sevenG, sevenE),
+	'p': sevenSegs(sevenA, sevenB, sevenF, sevenG, sevenE),
+	'R': sevenSegs(sevenE, sevenG),
+	'r': sevenSegs(sevenE, sevenG),
+	'S': sevenSegs(sevenA, sevenF, sevenG, sevenC, sevenD),
+	's': sevenSegs(sevenA, sevenF, sevenG, sevenC, sevenD),
+	'T': sevenSegs(sevenF, sevenG, sevenE, sevenD),
+	't': sevenSegs(sevenF, sevenG, sevenE, sevenD),
+	'U': sevenSegs(sevenF, sevenE, sevenD, sevenC, sevenB),
+	'u': sevenSegs(sevenE, sevenD, sevenC),
+	'Y': sevenSegs(sevenF, sevenG, sevenB, sevenC, sevenD),
+	'y': sevenSegs(sevenF, sevenG, sevenB, sevenC, sevenD),
+}
+
+// sevenSegmentDisplay returns a sixteen-segment display that has the segments
+// of the seven-segment representation of the character set.
+// Characters that cannot be displayed on a seven-segment display result in a
+// blank display.
+func sevenSegmentDisplay(c rune) (*sixteen.Display, error) {
+	disp := sixteen.New()
+	for _, s := range sevenCharacterSegments[c] {
+		if err := disp.SetSegment(s); err != nil {
+			return nil, err
+		}
+	}
+	return disp, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slider
+
+// options.go contains configurable options for Slider.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	step            float64
+	value           *float64
+	valueFormat     string
+	trackRune       rune
+	fillRune        rune
+	handleRune      rune
+	trackCellOpts   []cell.Option
+	fillCellOpts    []cell.Option
+	handleCellOpts  []cell.Option
+	focusedCellOpts []cell.Option
+	onChange        ChangeFn
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		step:            DefaultStep,
+		trackRune:       DefaultTrackRune,
+		fillRune:        DefaultFillRune,
+		handleRune:      DefaultHandleRune,
+		focusedCellOpts: []cell.Option{cell.FgColor(cell.ColorNumber(DefaultFocusedColorNumber))},
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate(min, max float64) error {
+	if min >= max {
+		return fmt.Errorf("invalid range, min %v must be smaller than max %v", min, max)
+	}
+	if o.step <= 0 || o.step > max-min {
+		return fmt.Errorf("invalid Step(%v), must be value in range 0 < value <= %v", o.step, max-min)
+	}
+	if v := o.value; v != nil && (*v < min || *v > max) {
+		return fmt.Errorf("invalid Value(%v), must be value in range %v <= value <= %v", *v, min, max)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// DefaultStep is the default value for the Step option.
+const DefaultStep = 1
+
+// Step sets the amount by which the value changes when adjusted with the
+// keyboard. Values set with the mouse are rounded to a multiple of the step
+// from the minimum.
+// Must be a positive number not larger than the range of the slider.
+// Defaults to DefaultStep.
+func Step(step float64) Option {
+	return option(func(opts *options) {
+		opts.step = step
+	})
+}
+
+// Value sets the initial value of the slider.
+// Defaults to the minimum.
+func Value(v float64) Option {
+	return option(func(opts *options) {
+		opts.value = &v
+	})
+}
+
+// ValueFormat displays the value on the right of the track, formatted with
+// fmt.Sprintf using the provided format, e.g. "%.1f".
+// The value isn't displayed by default.
+func ValueFormat(format string) Option {
+	return option(func(opts *options) {
+		opts.valueFormat = format
+	})
+}
+
+// The default runes used to draw the slider.
+const (
+	DefaultTrackRune  = '─'
+	DefaultFillRune   = '━'
+	DefaultHandleRune = '█'
+)
+
+// Runes sets the runes used to draw the track on the right of the handle, the
+// track on its left and the handle.
+// Defaults to DefaultTrackRune, DefaultFillRune and DefaultHandleRune.
+func Runes(track, fill, handle rune) Option {
+	return option(func(opts *options) {
+		opts.trackRune = track
+		opts.fillRune = fill
+		opts.handleRune = handle
+	})
+}
+
+// TrackCellOpts sets the cell options of the track on the right of the
+// handle.
+// Defaults to the default cell options.
+func TrackCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.trackCellOpts = co
+	})
+}
+
+// FillCellOpts sets the cell options of the track on the left of the handle.
+// Defaults to the default cell options.
+func FillCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.fillCellOpts = co
+	})
+}
+
+// HandleCellOpts sets the cell options of the handle.
+// Defaults to the default cell options.
+func HandleCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.handleCellOpts = co
+	})
+}
+
+// DefaultFocusedColorNumber is the default color number of the handle when
+// the slider is focused.
+const DefaultFocusedColorNumber = 33
+
+// FocusedCellOpts sets the cell options applied on top of the handle when the
+// slider is focused.
+// Defaults to a foreground color of DefaultFocusedColorNumber.
+func FocusedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.focusedCellOpts = co
+	})
+}
+
+// ChangeFn is called when the user changes the value of the slider. The
+// argument is the new value.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Slider isn't locked while the function
+// executes, so it can read from or modify the Slider.
+type ChangeFn func(value float64) error
+
+// OnChange sets the function that is called when the user changes the value.
+func OnChange(fn ChangeFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
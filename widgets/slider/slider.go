@@ -0,0 +1,286 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slider implements a widget that adjusts a numeric value.
+package slider
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// minTrackWidth is the minimum width of the track in cells.
+const minTrackWidth = 3
+
+// Slider displays a horizontal track with a handle whose position represents
+// a value between a minimum and a maximum.
+//
+// The value is adjusted by the Left and Right arrow keys by one step, by PgDn
+// and PgUp by ten steps and set to the minimum or maximum by Home and End.
+// Clicking on the track or dragging the handle with the mouse moves the
+// handle, the mouse wheel adjusts the value by one step.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Slider struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// min and max are the boundaries of the value.
+	min, max float64
+	// value is the current value.
+	value float64
+
+	// trackWidth is the width of the track the last time Draw was called.
+	trackWidth int
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new Slider for values in the range min <= value <= max.
+func New(min, max float64, opts ...Option) (*Slider, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(min, max); err != nil {
+		return nil, err
+	}
+
+	s := &Slider{
+		min:   min,
+		max:   max,
+		value: min,
+		opts:  opt,
+	}
+	if opt.value != nil {
+		s.value = *opt.value
+	}
+	return s, nil
+}
+
+// Value returns the current value.
+func (s *Slider) Value() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// SetValue sets the current value. Doesn't call the ChangeFn.
+func (s *Slider) SetValue(v float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.dirty.Mark()
+
+	if v < s.min || v > s.max {
+		return fmt.Errorf("invalid value %v, must be value in range %v <= value <= %v", v, s.min, s.max)
+	}
+	s.value = v
+	return nil
+}
+
+// snap rounds the value to the nearest step from the minimum and clamps it
+// into the range.
+func (s *Slider) snap(v float64) float64 {
+	steps := math.Round((v - s.min) / s.opts.step)
+	v = s.min + steps*s.opts.step
+	return math.Max(s.min, math.Min(s.max, v))
+}
+
+// valueText returns the formatted value or an empty string if the value
+// isn't displayed.
+func (s *Slider) valueText(v float64) string {
+	if s.opts.valueFormat == "" {
+		return ""
+	}
+	return fmt.Sprintf(s.opts.valueFormat, v)
+}
+
+// valueWidth returns the number of cells reserved for the displayed value,
+// including the space that separates it from the track.
+func (s *Slider) valueWidth() int {
+	if s.opts.valueFormat == "" {
+		return 0
+	}
+	width := 0
+	for _, v := range []float64{s.min, s.max, s.value} {
+		if w := runewidth.StringWidth(s.valueText(v)); w > width {
+			width = w
+		}
+	}
+	return width + 1
+}
+
+// handleX returns the position of the handle on a track of the width.
+func (s *Slider) handleX(width int) int {
+	return int(math.Round((s.value - s.min) / (s.max - s.min) * float64(width-1)))
+}
+
+// Draw draws the Slider widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (s *Slider) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ar := cvs.Area()
+	vw := s.valueWidth()
+	if ar.Dx() < minTrackWidth+vw || ar.Dy() < 1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	s.trackWidth = ar.Dx() - vw
+	hx := s.handleX(s.trackWidth)
+	for x := 0; x < s.trackWidth; x++ {
+		var (
+			r     rune
+			cOpts []cell.Option
+		)
+		switch {
+		case x < hx:
+			r, cOpts = s.opts.fillRune, s.opts.fillCellOpts
+		case x == hx:
+			r, cOpts = s.opts.handleRune, s.opts.handleCellOpts
+			if meta.Focused {
+				cOpts = append(append([]cell.Option(nil), cOpts...), s.opts.focusedCellOpts...)
+			}
+		default:
+			r, cOpts = s.opts.trackRune, s.opts.trackCellOpts
+		}
+		if _, err := cvs.SetCell(image.Point{ar.Min.X + x, ar.Min.Y}, r, cOpts...); err != nil {
+			return err
+		}
+	}
+
+	if vw == 0 {
+		return nil
+	}
+	text := s.valueText(s.value)
+	start := image.Point{ar.Max.X - runewidth.StringWidth(text), ar.Min.Y}
+	return draw.Text(cvs, text, start)
+}
+
+// setValue sets the value snapped to the step.
+// Returns true and the new value if it changed.
+func (s *Slider) setValue(v float64) (bool, float64) {
+	v = s.snap(v)
+	if v == s.value {
+		return false, 0
+	}
+	s.value = v
+	return true, v
+}
+
+// changed calls the ChangeFn if the value changed and one was provided.
+func (s *Slider) changed(changed bool, v float64) error {
+	if !changed || s.opts.onChange == nil {
+		return nil
+	}
+	// Mutex must be released when calling the callback.
+	return s.opts.onChange(v)
+}
+
+// keyboard processes the keyboard event.
+// Returns true and the new value if it changed.
+func (s *Slider) keyboard(k *terminalapi.Keyboard) (bool, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch k.Key {
+	case keyboard.KeyArrowLeft:
+		return s.setValue(s.value - s.opts.step)
+	case keyboard.KeyArrowRight:
+		return s.setValue(s.value + s.opts.step)
+	case keyboard.KeyPgDn:
+		return s.setValue(s.value - 10*s.opts.step)
+	case keyboard.KeyPgUp:
+		return s.setValue(s.value + 10*s.opts.step)
+	case keyboard.KeyHome:
+		return s.setValue(s.min)
+	case keyboard.KeyEnd:
+		return s.setValue(s.max)
+	}
+	return false, 0
+}
+
+// Keyboard adjusts the value.
+// Implements widgetapi.Widget.Keyboard.
+func (s *Slider) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return s.changed(s.keyboard(k))
+}
+
+// mouse processes the mouse event.
+// Returns true and the new value if it changed.
+func (s *Slider) mouse(m *terminalapi.Mouse) (bool, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonLeft:
+		if s.trackWidth == 0 {
+			return false, 0
+		}
+		x := m.Position.X
+		if x < 0 {
+			x = 0
+		}
+		if x > s.trackWidth-1 {
+			x = s.trackWidth - 1
+		}
+		return s.setValue(s.min + float64(x)/float64(s.trackWidth-1)*(s.max-s.min))
+	case mouse.ButtonWheelUp:
+		return s.setValue(s.value + s.opts.step)
+	case mouse.ButtonWheelDown:
+		return s.setValue(s.value - s.opts.step)
+	}
+	return false, 0
+}
+
+// Mouse moves the handle to the clicked position.
+// Implements widgetapi.Widget.Mouse.
+func (s *Slider) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return s.changed(s.mouse(m))
+}
+
+// Options implements widgetapi.Widget.Options.
+func (s *Slider) Options() widgetapi.Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize:  image.Point{minTrackWidth + s.valueWidth(), 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (s *Slider) SetDirtyFunc(markDirty func()) {
+	s.dirty.Set(markDirty)
+}
@@ -0,0 +1,312 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slider
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// changeTracker tracks calls to the ChangeFn.
+type changeTracker struct {
+	// calls are the values the ChangeFn was called with.
+	calls []float64
+	// wantErr when set to true, makes the ChangeFn return an error.
+	wantErr bool
+}
+
+// change implements ChangeFn.
+func (ct *changeTracker) change(v float64) error {
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, v)
+	return nil
+}
+
+// mustDrawTrack draws a track of the width with the handle at the position.
+func mustDrawTrack(cvs *canvas.Canvas, width, handle int, handleOpts ...cell.Option) {
+	for x := 0; x < width; x++ {
+		switch {
+		case x < handle:
+			testcanvas.MustSetCell(cvs, image.Point{x, 0}, DefaultFillRune)
+		case x == handle:
+			testcanvas.MustSetCell(cvs, image.Point{x, 0}, DefaultHandleRune, handleOpts...)
+		default:
+			testcanvas.MustSetCell(cvs, image.Point{x, 0}, DefaultTrackRune)
+		}
+	}
+}
+
+func TestSlider(t *testing.T) {
+	tests := []struct {
+		desc         string
+		min, max     float64
+		opts         []Option
+		canvas       image.Rectangle
+		meta         *widgetapi.Meta
+		events       []terminalapi.Event
+		tracker      *changeTracker
+		want         func(size image.Point) *faketerm.Terminal
+		wantValue    float64
+		wantCalls    []float64
+		wantNewErr   bool
+		wantEventErr bool
+	}{
+		{
+			desc:       "fails when min isn't smaller than max",
+			min:        10,
+			max:        10,
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on zero step",
+			max:        10,
+			opts:       []Option{Step(0)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on step larger than the range",
+			max:        10,
+			opts:       []Option{Step(11)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on value out of range",
+			max:        10,
+			opts:       []Option{Value(11)},
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws the handle at the minimum",
+			max:    10,
+			canvas: image.Rect(0, 0, 11, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTrack(cvs, 11, 0)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the handle at the value with the value displayed",
+			max:    10,
+			opts:   []Option{Value(4), ValueFormat("%.0f")},
+			canvas: image.Rect(0, 0, 14, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTrack(cvs, 11, 4, cell.FgColor(cell.ColorNumber(DefaultFocusedColorNumber)))
+				testdraw.MustText(cvs, "4", image.Point{13, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValue: 4,
+		},
+		{
+			desc:   "requests resize when the track doesn't fit",
+			max:    10,
+			opts:   []Option{ValueFormat("%.0f")},
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "adjusts the value using the keyboard",
+			max:    100,
+			opts:   []Option{Step(5)},
+			canvas: image.Rect(0, 0, 11, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnd},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTrack(cvs, 11, 0)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValue: 0,
+			wantCalls: []float64{5, 55, 100, 50, 0},
+		},
+		{
+			desc:   "moves the handle with the mouse",
+			max:    1,
+			opts:   []Option{Step(0.25)},
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonWheelUp},
+			},
+			tracker: &changeTracker{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawTrack(cvs, 5, 4)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantValue: 1,
+			wantCalls: []float64{0.25, 0.75, 1},
+		},
+		{
+			desc:   "returns the error from the ChangeFn",
+			max:    10,
+			canvas: image.Rect(0, 0, 11, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+			},
+			tracker:      &changeTracker{wantErr: true},
+			wantEventErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.tracker != nil {
+				opts = append(opts, OnChange(tc.tracker.change))
+			}
+			s, err := New(tc.min, tc.max, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// draw draws the widget onto a new canvas.
+			draw := func() *canvas.Canvas {
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := s.Draw(c, tc.meta); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				return c
+			}
+			// Draw once so that the width of the track is known.
+			draw()
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = s.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = s.Mouse(e, &widgetapi.EventMeta{})
+				}
+				if err != nil {
+					break
+				}
+			}
+			if (err != nil) != tc.wantEventErr {
+				t.Errorf("event => unexpected error: %v, wantEventErr: %v", err, tc.wantEventErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c := draw()
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if got := s.Value(); got != tc.wantValue {
+				t.Errorf("Value => %v, want %v", got, tc.wantValue)
+			}
+			if tc.tracker != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.tracker.calls); diff != "" {
+					t.Errorf("ChangeFn calls => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	s, err := New(0, 10)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := s.SetValue(11); err == nil {
+		t.Errorf("SetValue(11) => unexpected success, want an error")
+	}
+	if err := s.SetValue(2.5); err != nil {
+		t.Fatalf("SetValue(2.5) => unexpected error: %v", err)
+	}
+	if got, want := s.Value(), 2.5; got != want {
+		t.Errorf("Value => %v, want %v", got, want)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	s, err := New(0, 100, ValueFormat("%.0f%%"))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := s.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{8, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary sliderdemo displays Slider widgets that tune the alert thresholds
+// of a simulated metric.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/slider"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	warning, err := slider.New(0, 100,
+		slider.Value(60),
+		slider.ValueFormat("%.0f%%"),
+		slider.FillCellOpts(cell.FgColor(cell.ColorYellow)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	critical, err := slider.New(0, 100,
+		slider.Value(85),
+		slider.ValueFormat("%.0f%%"),
+		slider.FillCellOpts(cell.FgColor(cell.ColorRed)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	status, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-ticker.C:
+				v := 50 + 45*math.Sin(float64(i)/20)
+				color, state := cell.ColorGreen, "OK"
+				switch {
+				case v >= critical.Value():
+					color, state = cell.ColorRed, "CRITICAL"
+				case v >= warning.Value():
+					color, state = cell.ColorYellow, "WARNING"
+				}
+				status.Reset()
+				if err := status.Write(fmt.Sprintf("CPU usage %.0f%% ", v)); err != nil {
+					panic(err)
+				}
+				if err := status.Write(state, text.WriteCellOpts(cell.FgColor(color))); err != nil {
+					panic(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitHorizontal(
+					container.Top(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Warning threshold"),
+						container.PlaceWidget(warning),
+					),
+					container.Bottom(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Critical threshold"),
+						container.PlaceWidget(critical),
+					),
+				),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Status"),
+				container.PlaceWidget(status),
+			),
+			container.SplitPercent(60),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
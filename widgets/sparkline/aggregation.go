@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkline
+
+// aggregation.go contains code that summarizes data points that don't fit the
+// width of the SparkLine.
+
+// AggregationMode determines how data points are summarized when there are
+// more of them than columns available to the SparkLine.
+type AggregationMode int
+
+// String implements fmt.Stringer()
+func (am AggregationMode) String() string {
+	if n, ok := aggregationModeNames[am]; ok {
+		return n
+	}
+	return "AggregationModeUnknown"
+}
+
+// aggregationModeNames maps AggregationMode values to human readable names.
+var aggregationModeNames = map[AggregationMode]string{
+	AggregationNone:    "AggregationNone",
+	AggregationLast:    "AggregationLast",
+	AggregationAverage: "AggregationAverage",
+	AggregationMax:     "AggregationMax",
+	AggregationMin:     "AggregationMin",
+}
+
+const (
+	// AggregationNone doesn't aggregate the data points, only the last data
+	// points that fit the width of the SparkLine are displayed.
+	AggregationNone AggregationMode = iota
+
+	// The following modes summarize all the data points by splitting them into
+	// consecutive groups, one for each column of the SparkLine.
+
+	// AggregationLast displays the last data point of each group.
+	AggregationLast
+	// AggregationAverage displays the average of the data points in each
+	// group, rounded down.
+	AggregationAverage
+	// AggregationMax displays the largest data point of each group.
+	AggregationMax
+	// AggregationMin displays the smallest data point of each group.
+	AggregationMin
+)
+
+// aggregate summarizes the data points so that they fit into the specified
+// width. Returns the data points unchanged if they already fit or if the mode
+// is AggregationNone.
+func aggregate(data []int, width int, mode AggregationMode) []int {
+	if mode == AggregationNone || width <= 0 || len(data) <= width {
+		return data
+	}
+
+	res := make([]int, width)
+	for i := range res {
+		group := data[i*len(data)/width : (i+1)*len(data)/width]
+		res[i] = aggregateGroup(group, mode)
+	}
+	return res
+}
+
+// aggregateGroup summarizes a non-empty group of data points into a single
+// value.
+func aggregateGroup(group []int, mode AggregationMode) int {
+	switch mode {
+	case AggregationAverage:
+		var sum int
+		for _, v := range group {
+			sum += v
+		}
+		return sum / len(group)
+
+	case AggregationMax:
+		max := group[0]
+		for _, v := range group {
+			if v > max {
+				max = v
+			}
+		}
+		return max
+
+	case AggregationMin:
+		min := group[0]
+		for _, v := range group {
+			if v < min {
+				min = v
+			}
+		}
+		return min
+
+	default:
+		return group[len(group)-1]
+	}
+}
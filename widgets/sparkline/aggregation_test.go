@@ -0,0 +1,124 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkline
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		desc  string
+		data  []int
+		width int
+		mode  AggregationMode
+		want  []int
+	}{
+		{
+			desc:  "no aggregation",
+			data:  []int{1, 2, 3, 4},
+			width: 2,
+			mode:  AggregationNone,
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			desc:  "data points fit the width",
+			data:  []int{1, 2, 3, 4},
+			width: 4,
+			mode:  AggregationMax,
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			desc:  "zero width",
+			data:  []int{1, 2, 3, 4},
+			width: 0,
+			mode:  AggregationMax,
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			desc:  "last data point of each group",
+			data:  []int{1, 2, 3, 4, 5, 6},
+			width: 3,
+			mode:  AggregationLast,
+			want:  []int{2, 4, 6},
+		},
+		{
+			desc:  "average of each group rounded down",
+			data:  []int{1, 2, 3, 4, 5, 6},
+			width: 3,
+			mode:  AggregationAverage,
+			want:  []int{1, 3, 5},
+		},
+		{
+			desc:  "maximum of each group",
+			data:  []int{6, 1, 2, 5, 3, 4},
+			width: 3,
+			mode:  AggregationMax,
+			want:  []int{6, 5, 4},
+		},
+		{
+			desc:  "minimum of each group",
+			data:  []int{6, 1, 2, 5, 3, 4},
+			width: 3,
+			mode:  AggregationMin,
+			want:  []int{1, 2, 3},
+		},
+		{
+			desc:  "groups of uneven size",
+			data:  []int{1, 2, 3, 4, 5},
+			width: 2,
+			mode:  AggregationLast,
+			want:  []int{2, 5},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := aggregate(tc.data, tc.width, tc.mode)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("aggregate => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAggregationModeString(t *testing.T) {
+	tests := []struct {
+		desc string
+		mode AggregationMode
+		want string
+	}{
+		{
+			desc: "unknown",
+			mode: AggregationMode(-1),
+			want: "AggregationModeUnknown",
+		},
+		{
+			desc: "defined value",
+			mode: AggregationAverage,
+			want: "AggregationAverage",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.mode.String(); got != tc.want {
+				t.Errorf("String => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkline
+
+// hover.go contains code that reports the values under the mouse cursor.
+
+import (
+	"image"
+)
+
+// Hover describes the column of the SparkLine under the mouse cursor.
+type Hover struct {
+	// Value is the value displayed in the column. When the data points are
+	// summarized by the Aggregation option, this is the summarized value.
+	Value int
+
+	// First and Last are the indexes of the first and the last data point
+	// represented by the column. The indexes refer to all the data points
+	// added since the SparkLine was created or last cleared. These are equal
+	// unless the data points are summarized by the Aggregation option.
+	First, Last int
+}
+
+// HoverFn is called when the mouse cursor moves onto a different column of the
+// SparkLine. The argument is nil when the cursor moves off the bars.
+//
+// The callback function must be thread-safe as the mouse events come from a
+// separate goroutine. The SparkLine isn't locked while the function executes,
+// so it can read from or modify the SparkLine.
+type HoverFn func(h *Hover) error
+
+// hoverColumns determines which data points are represented by each of the
+// visible columns. The visible values are the ones that are drawn, starting
+// with the leftmost column.
+func hoverColumns(data, visible []int, width int, mode AggregationMode) []*Hover {
+	var cols []*Hover
+	if aggregated := mode != AggregationNone && width > 0 && len(data) > width; aggregated {
+		for i, v := range visible {
+			cols = append(cols, &Hover{
+				Value: v,
+				First: i * len(data) / width,
+				Last:  (i+1)*len(data)/width - 1,
+			})
+		}
+		return cols
+	}
+
+	offset := len(data) - len(visible)
+	for i, v := range visible {
+		cols = append(cols, &Hover{
+			Value: v,
+			First: offset + i,
+			Last:  offset + i,
+		})
+	}
+	return cols
+}
+
+// hoverAt returns the column at the specified position or nil if the position
+// doesn't fall onto any of the columns.
+func (sl *SparkLine) hoverAt(p image.Point) *Hover {
+	if !p.In(sl.hoverAr) {
+		return nil
+	}
+	return sl.hoverCols[p.X-sl.hoverAr.Min.X]
+}
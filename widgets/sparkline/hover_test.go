@@ -0,0 +1,153 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkline
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestHover(t *testing.T) {
+	tests := []struct {
+		desc    string
+		opts    []Option
+		data    []int
+		canvas  image.Rectangle
+		events  []image.Point
+		want    []*Hover
+		wantErr bool
+	}{
+		{
+			desc:   "reports the hovered data points",
+			data:   []int{1, 2, 3},
+			canvas: image.Rect(0, 0, 3, 2),
+			events: []image.Point{{0, 0}, {2, 1}},
+			want: []*Hover{
+				{Value: 1, First: 0, Last: 0},
+				{Value: 3, First: 2, Last: 2},
+			},
+		},
+		{
+			desc:   "doesn't report the same column twice",
+			data:   []int{1, 2, 3},
+			canvas: image.Rect(0, 0, 3, 2),
+			events: []image.Point{{1, 0}, {1, 1}},
+			want: []*Hover{
+				{Value: 2, First: 1, Last: 1},
+			},
+		},
+		{
+			desc:   "reports nil when the cursor leaves the bars",
+			data:   []int{1, 2},
+			canvas: image.Rect(0, 0, 4, 2),
+			events: []image.Point{{2, 0}, {1, 0}, {-1, -1}, {3, 0}, {-1, -1}},
+			want: []*Hover{
+				{Value: 1, First: 0, Last: 0},
+				nil,
+				{Value: 2, First: 1, Last: 1},
+				nil,
+			},
+		},
+		{
+			desc:   "indexes account for data points that don't fit",
+			data:   []int{1, 2, 3, 4, 5},
+			canvas: image.Rect(0, 0, 2, 1),
+			events: []image.Point{{0, 0}},
+			want: []*Hover{
+				{Value: 4, First: 3, Last: 3},
+			},
+		},
+		{
+			desc: "reports the aggregated value and the range of data points",
+			opts: []Option{
+				Aggregation(AggregationMax),
+			},
+			data:   []int{1, 5, 3, 4, 2, 6},
+			canvas: image.Rect(0, 0, 2, 1),
+			events: []image.Point{{0, 0}, {1, 0}},
+			want: []*Hover{
+				{Value: 5, First: 0, Last: 2},
+				{Value: 6, First: 3, Last: 5},
+			},
+		},
+		{
+			desc: "doesn't report the label row",
+			opts: []Option{
+				Label("foo"),
+			},
+			data:   []int{1},
+			canvas: image.Rect(0, 0, 3, 2),
+			events: []image.Point{{2, 0}, {2, 1}},
+			want: []*Hover{
+				{Value: 1, First: 0, Last: 0},
+			},
+		},
+		{
+			desc:    "returns the error from the callback",
+			data:    []int{1},
+			canvas:  image.Rect(0, 0, 1, 1),
+			events:  []image.Point{{0, 0}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got []*Hover
+			fn := func(h *Hover) error {
+				if tc.wantErr {
+					return errors.New("callback error")
+				}
+				got = append(got, h)
+				return nil
+			}
+			sp, err := New(append(tc.opts, OnHover(fn))...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if err := sp.Add(tc.data); err != nil {
+				t.Fatalf("Add => unexpected error: %v", err)
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := sp.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			for _, p := range tc.events {
+				err := sp.Mouse(&terminalapi.Mouse{
+					Position: p,
+					Button:   mouse.ButtonRelease,
+				}, &widgetapi.EventMeta{})
+				if (err != nil) != tc.wantErr {
+					t.Errorf("Mouse => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+			}
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("HoverFn => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
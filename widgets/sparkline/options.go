@@ -42,6 +42,13 @@ type options struct {
 	labelCellOpts []cell.Option
 	height        int
 	color         cell.Color
+	// colorSet indicates if the color was set explicitly, otherwise the
+	// color of the dashboard theme takes precedence.
+	colorSet    bool
+	aggregation AggregationMode
+	initialData []int
+	onHover     HoverFn
+	mirror      bool
 }
 
 // newOptions returns options with the default values set.
@@ -56,6 +63,12 @@ func (o *options) validate() error {
 	if got, min := o.height, 0; got < min {
 		return fmt.Errorf("invalid Height %d, must be %d <= Height", got, min)
 	}
+	if min := 2; o.mirror && o.height > 0 && o.height < min {
+		return fmt.Errorf("invalid Height %d, must be at least %d with the Mirror option", o.height, min)
+	}
+	if _, ok := aggregationModeNames[o.aggregation]; !ok {
+		return fmt.Errorf("unsupported Aggregation mode %v(%d)", o.aggregation, o.aggregation)
+	}
 	return nil
 }
 
@@ -80,9 +93,50 @@ func Height(h int) Option {
 const DefaultColor = cell.ColorGreen
 
 // Color sets the color of the SparkLine.
-// Defaults to DefaultColor if not set.
+// Defaults to the Primary color of the dashboard theme if set, otherwise to
+// DefaultColor.
 func Color(c cell.Color) Option {
 	return option(func(opts *options) {
 		opts.color = c
+		opts.colorSet = true
+	})
+}
+
+// Aggregation sets how the data points are summarized when there are more of
+// them than can fit the width of the SparkLine. This allows to display an
+// overview of the entire series instead of just the most recent data points.
+// Defaults to AggregationNone.
+func Aggregation(mode AggregationMode) Option {
+	return option(func(opts *options) {
+		opts.aggregation = mode
+	})
+}
+
+// InitialData sets the data points that the SparkLine displays from the start,
+// as if Add was called right after New.
+// Only takes effect when provided to New.
+func InitialData(data []int) Option {
+	return option(func(opts *options) {
+		opts.initialData = append([]int(nil), data...)
+	})
+}
+
+// OnHover sets a function that is called with the value under the mouse
+// cursor each time the cursor moves onto a different column of the SparkLine.
+// See HoverFn for details.
+func OnHover(fn HoverFn) Option {
+	return option(func(opts *options) {
+		opts.onHover = fn
+	})
+}
+
+// Mirror draws the series added via AddSeries below the middle of the
+// SparkLine growing downwards, while the data points added via Add grow
+// upwards from the middle. Useful to display complementary values, e.g.
+// received and transmitted network traffic.
+// The SparkLine must be at least two cells high.
+func Mirror() Option {
+	return option(func(opts *options) {
+		opts.mirror = true
 	})
 }
@@ -0,0 +1,151 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkline
+
+// series.go contains code that draws multiple series on one SparkLine.
+
+import (
+	"image"
+	"sort"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+)
+
+// series is an additional series of data points added via AddSeries.
+type series struct {
+	// name identifies the series.
+	name string
+	// color is the color of the bars.
+	color cell.Color
+	// data are the data points of the series.
+	data []int
+}
+
+// bar is one value of a series in a column of the SparkLine.
+type bar struct {
+	value int
+	color cell.Color
+}
+
+// column collects the bars of all the series drawn into one column.
+type column struct {
+	up   []bar
+	down []bar
+}
+
+// columns returns the bars in each column of the area and the maximum value
+// among them. Each series is aligned to the right edge of the area.
+// The series added via Add is drawn upwards, the additional series are drawn
+// downwards if the Mirror option was provided.
+func (sl *SparkLine) columns(ar image.Rectangle, primary []int, color cell.Color) ([]*column, int) {
+	width := ar.Dx()
+	cols := make([]*column, width)
+	for i := range cols {
+		cols[i] = &column{}
+	}
+
+	var max int
+	addSeries := func(visible []int, m int, c cell.Color, down bool) {
+		if m > max {
+			max = m
+		}
+		offset := width - len(visible)
+		for i, v := range visible {
+			col := cols[offset+i]
+			if down {
+				col.down = append(col.down, bar{value: v, color: c})
+			} else {
+				col.up = append(col.up, bar{value: v, color: c})
+			}
+		}
+	}
+
+	visible, m := visibleMax(primary, width)
+	addSeries(visible, m, color, false)
+	for _, s := range sl.extra {
+		visible, m := visibleMax(aggregate(s.data, width, sl.opts.aggregation), width)
+		addSeries(visible, m, s.color, sl.opts.mirror)
+	}
+	return cols, max
+}
+
+// drawBars draws the bars of one column starting at the baseline cell and
+// growing in the direction indicated by down. The bars are drawn from the
+// largest to the smallest, so that overlaid smaller bars remain visible.
+func drawBars(cvs *canvas.Canvas, x, baseline, height int, bars []bar, max int, down bool) error {
+	sorted := append([]bar(nil), bars...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].value > sorted[j].value
+	})
+
+	for i, b := range sorted {
+		blocks := toBlocks(b.value, max, height)
+		curY := baseline
+		step := -1
+		if down {
+			step = 1
+		}
+		for j := 0; j < blocks.full; j++ {
+			if _, err := cvs.SetCell(
+				image.Point{x, curY},
+				sparks[len(sparks)-1], // Last spark represents full cell.
+				cell.FgColor(b.color),
+			); err != nil {
+				return err
+			}
+			curY += step
+		}
+
+		if blocks.partSpark == 0 {
+			continue
+		}
+		// The larger bar drawn before fills the rest of this cell.
+		covered := i > 0 && toBlocks(sorted[i-1].value, max, height).full > blocks.full
+		r := blocks.partSpark
+		var opts []cell.Option
+		switch {
+		case !down && covered:
+			opts = []cell.Option{cell.FgColor(b.color), cell.BgColor(sorted[i-1].color)}
+		case !down:
+			opts = []cell.Option{cell.FgColor(b.color)}
+
+		// Sparks are aligned to the bottom of the cell, the partial cell of a
+		// bar growing downwards is drawn by swapping the colors of the
+		// complementary spark.
+		case covered:
+			r = complementSpark(r)
+			opts = []cell.Option{cell.FgColor(sorted[i-1].color), cell.BgColor(b.color)}
+		default:
+			r = complementSpark(r)
+			opts = []cell.Option{cell.FgColor(b.color), cell.Inverse()}
+		}
+		if _, err := cvs.SetCell(image.Point{x, curY}, r, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// complementSpark returns the spark that fills the part of the cell that the
+// provided partial spark leaves empty.
+func complementSpark(r rune) rune {
+	for i, s := range sparks {
+		if s == r {
+			return sparks[len(sparks)-2-i]
+		}
+	}
+	return r
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
@@ -38,15 +39,29 @@ import (
 type SparkLine struct {
 	// data are the data points the SparkLine displays.
 	data []int
+	// extra are the additional series added via AddSeries in the order they
+	// were first added.
+	extra []*series
 
 	// lastWidth is the width of the canvas as of the last time when Draw was called.
 	lastWidth int
 
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the SparkLine.
 	mu sync.Mutex
 
 	// opts are the provided options.
 	opts *options
+
+	// hoverCols are the columns of the SparkLine as of the last call to Draw
+	// and hoverAr is the area they occupy. Only populated when the OnHover
+	// option is provided.
+	hoverCols []*Hover
+	hoverAr   image.Rectangle
+	// lastHover is the column last reported to the HoverFn.
+	lastHover *Hover
 }
 
 // New returns a new SparkLine.
@@ -59,9 +74,15 @@ func New(opts ...Option) (*SparkLine, error) {
 		return nil, err
 	}
 
-	return &SparkLine{
+	sl := &SparkLine{
 		opts: opt,
-	}, nil
+	}
+	if len(opt.initialData) > 0 {
+		if err := sl.Add(opt.initialData); err != nil {
+			return nil, err
+		}
+	}
+	return sl, nil
 }
 
 // Draw draws the SparkLine widget onto the canvas.
@@ -80,42 +101,36 @@ func (sl *SparkLine) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	ar := sl.area(cvs)
-	visible, max := visibleMax(sl.data, ar.Dx())
-	var curX int
-	if len(visible) < ar.Dx() {
-		curX = ar.Max.X - len(visible)
-	} else {
-		curX = ar.Min.X
-	}
-
-	for _, v := range visible {
-		blocks := toBlocks(v, max, ar.Dy())
-		curY := ar.Max.Y - 1
-		for i := 0; i < blocks.full; i++ {
-			if _, err := cvs.SetCell(
-				image.Point{curX, curY},
-				sparks[len(sparks)-1], // Last spark represents full cell.
-				cell.FgColor(sl.opts.color),
-			); err != nil {
-				return err
-			}
+	primary := aggregate(sl.data, ar.Dx(), sl.opts.aggregation)
+	visible, _ := visibleMax(primary, ar.Dx())
+	if sl.opts.onHover != nil {
+		curX := ar.Max.X - len(visible)
+		sl.hoverCols = hoverColumns(sl.data, visible, ar.Dx(), sl.opts.aggregation)
+		sl.hoverAr = image.Rect(curX, ar.Min.Y, curX+len(visible), ar.Max.Y)
+	}
 
-			curY--
-		}
+	color := sl.opts.color
+	if meta != nil && meta.Theme != nil && !sl.opts.colorSet {
+		color = meta.Theme.Primary
+	}
 
-		if blocks.partSpark != 0 {
-			if _, err := cvs.SetCell(
-				image.Point{curX, curY},
-				blocks.partSpark,
-				cell.FgColor(sl.opts.color),
-			); err != nil {
+	upHeight := ar.Dy()
+	if sl.opts.mirror {
+		upHeight = (ar.Dy() + 1) / 2
+	}
+	upBaseline := ar.Min.Y + upHeight - 1
+	cols, max := sl.columns(ar, primary, color)
+	for i, col := range cols {
+		x := ar.Min.X + i
+		if err := drawBars(cvs, x, upBaseline, upHeight, col.up, max, false); err != nil {
+			return err
+		}
+		if sl.opts.mirror {
+			if err := drawBars(cvs, x, upBaseline+1, ar.Dy()-upHeight, col.down, max, true); err != nil {
 				return err
 			}
 		}
-
-		curX++
 	}
-
 	if sl.opts.label != "" {
 		// Label is placed immediately above the SparkLine.
 		lStart := image.Point{ar.Min.X, ar.Min.Y - 1}
@@ -153,12 +168,13 @@ func (sl *SparkLine) ValueCapacity() int {
 // The last added data point will be the one displayed all the way on the right
 // of the SparkLine. If there are more data points than we can fit bars to the
 // width of the SparkLine, only the last n data points that fit will be
-// visible.
+// visible, unless an aggregation mode was set via the Aggregation option.
 //
 // Provided options override values set when New() was called.
 func (sl *SparkLine) Add(data []int, opts ...Option) error {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
+	defer sl.dirty.Mark()
 
 	for _, opt := range opts {
 		opt.set(sl.opts)
@@ -173,13 +189,53 @@ func (sl *SparkLine) Add(data []int, opts ...Option) error {
 	return nil
 }
 
-// Clear removes all the data points in the SparkLine, effectively returning to
-// an empty graph.
+// AddSeries adds data points to an additional series identified by the name.
+// The series is created on the first call with the name. All the series share
+// the scale of the SparkLine and are aligned to its right edge, so the last
+// data points of each series are displayed in the same column.
+//
+// The additional series are overlaid over the data points added via Add in the
+// provided color, the smaller values are drawn in front of the larger ones.
+// When the Mirror option is provided, the additional series are drawn below
+// the middle of the SparkLine growing downwards instead.
+//
+// All data points must be positive integers.
+func (sl *SparkLine) AddSeries(name string, color cell.Color, data []int) error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	defer sl.dirty.Mark()
+
+	for i, d := range data {
+		if d < 0 {
+			return fmt.Errorf("data point[%d]: %v must be a positive integer", i, d)
+		}
+	}
+	for _, s := range sl.extra {
+		if s.name == name {
+			s.color = color
+			s.data = append(s.data, data...)
+			return nil
+		}
+	}
+	sl.extra = append(sl.extra, &series{
+		name:  name,
+		color: color,
+		data:  append([]int(nil), data...),
+	})
+	return nil
+}
+
+// Clear removes all the data points in the SparkLine including the additional
+// series, effectively returning to an empty graph.
 func (sl *SparkLine) Clear() {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
+	defer sl.dirty.Mark()
 
 	sl.data = nil
+	sl.extra = nil
+	sl.hoverCols = nil
+	sl.hoverAr = image.ZR
 }
 
 // Keyboard input isn't supported on the SparkLine widget.
@@ -187,9 +243,33 @@ func (*SparkLine) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) e
 	return errors.New("the SparkLine widget doesn't support keyboard events")
 }
 
-// Mouse input isn't supported on the SparkLine widget.
-func (*SparkLine) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
-	return errors.New("the SparkLine widget doesn't support mouse events")
+// Mouse reports the column under the mouse cursor to the HoverFn.
+// Mouse input is only supported when the OnHover option is provided.
+// Implements widgetapi.Widget.Mouse.
+func (sl *SparkLine) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	if changed, h := sl.hover(m); changed {
+		// Mutex must be released when calling the callback so that it can
+		// access the SparkLine.
+		return sl.opts.onHover(h)
+	}
+	return nil
+}
+
+// hover determines the column under the mouse cursor. Returns true if it
+// differs from the one last reported to the HoverFn.
+func (sl *SparkLine) hover(m *terminalapi.Mouse) (bool, *Hover) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if sl.opts.onHover == nil {
+		return false, nil
+	}
+	h := sl.hoverAt(m.Position)
+	if h == sl.lastHover || (h != nil && sl.lastHover != nil && *h == *sl.lastHover) {
+		return false, nil
+	}
+	sl.lastHover = h
+	return true, h
 }
 
 // area returns the area of the canvas available to the SparkLine.
@@ -221,9 +301,12 @@ func (sl *SparkLine) minSize() image.Point {
 	const minWidth = 1 // At least one data point.
 
 	var minHeight int
-	if sl.opts.height > 0 {
+	switch {
+	case sl.opts.height > 0:
 		minHeight = sl.opts.height
-	} else {
+	case sl.opts.mirror:
+		minHeight = 2 // One line above and one below the middle.
+	default:
 		minHeight = 1 // At least one line of characters.
 	}
 
@@ -244,10 +327,20 @@ func (sl *SparkLine) Options() widgetapi.Options {
 		max = min // Fix the height to the one specified.
 	}
 
+	wantMouse := widgetapi.MouseScopeNone
+	if sl.opts.onHover != nil {
+		// Global scope so that the cursor moving off the widget is reported.
+		wantMouse = widgetapi.MouseScopeGlobal
+	}
 	return widgetapi.Options{
 		MinimumSize:  min,
 		MaximumSize:  max,
 		WantKeyboard: widgetapi.KeyScopeNone,
-		WantMouse:    widgetapi.MouseScopeNone,
+		WantMouse:    wantMouse,
 	}
 }
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (sl *SparkLine) SetDirtyFunc(markDirty func()) {
+	sl.dirty.Set(markDirty)
+}
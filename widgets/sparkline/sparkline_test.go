@@ -25,6 +25,7 @@ import (
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/draw/testdraw"
 	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/theme"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
@@ -55,6 +56,20 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "fails on unsupported aggregation mode",
+			opts: []Option{
+				Aggregation(AggregationMode(-1)),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
 		{
 			desc: "draws empty for no data points",
 			update: func(sl *SparkLine) error {
@@ -95,6 +110,62 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantCapacity: 9,
 		},
+		{
+			desc: "displays initial data points set via option",
+			opts: []Option{
+				InitialData([]int{0, 1, 2, 3, 4, 5, 6, 7, 8}),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 9, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▁▂▃▄▅▆▇█", image.Point{1, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 9,
+		},
+		{
+			desc: "fails on negative initial data points",
+			opts: []Option{
+				InitialData([]int{0, -1}),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "aggregates data points that don't fit",
+			opts: []Option{
+				Aggregation(AggregationMax),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{8, 0, 0, 4, 2, 0, 1, 0})
+			},
+			canvas: image.Rect(0, 0, 4, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "█▄▂▁", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 4,
+		},
 		{
 			desc: "sparkline can be cleared",
 			update: func(sl *SparkLine) error {
@@ -131,6 +202,55 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantCapacity: 9,
 		},
+		{
+			desc: "uses the primary color from the theme",
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+			},
+			canvas: image.Rect(0, 0, 9, 1),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorBlue,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▁▂▃▄▅▆▇█", image.Point{1, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlue),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 9,
+		},
+		{
+			desc: "explicitly set color takes precedence over the theme",
+			opts: []Option{
+				Color(cell.ColorMagenta),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+			},
+			canvas: image.Rect(0, 0, 9, 1),
+			meta: &widgetapi.Meta{
+				Theme: &theme.Theme{
+					Primary: cell.ColorBlue,
+				},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▁▂▃▄▅▆▇█", image.Point{1, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorMagenta),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 9,
+		},
 		{
 			desc: "sets sparkline color on a call to Add",
 			update: func(sl *SparkLine) error {
@@ -149,6 +269,96 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantCapacity: 9,
 		},
+		{
+			desc: "fails with Mirror on a fixed height that is too small",
+			opts: []Option{
+				Mirror(),
+				Height(1),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on negative data points in an additional series",
+			update: func(sl *SparkLine) error {
+				return sl.AddSeries("tx", cell.ColorRed, []int{1, -1})
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "overlays additional series",
+			update: func(sl *SparkLine) error {
+				if err := sl.Add([]int{8, 4, 0}); err != nil {
+					return err
+				}
+				if err := sl.AddSeries("tx", cell.ColorRed, []int{4}); err != nil {
+					return err
+				}
+				return sl.AddSeries("tx", cell.ColorRed, []int{8, 8})
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▄', cell.FgColor(cell.ColorRed), cell.BgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▄', cell.FgColor(DefaultColor), cell.BgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '█', cell.FgColor(cell.ColorRed))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 3,
+		},
+		{
+			desc: "mirrors additional series below the middle",
+			opts: []Option{
+				Mirror(),
+			},
+			update: func(sl *SparkLine) error {
+				if err := sl.Add([]int{8, 4}); err != nil {
+					return err
+				}
+				return sl.AddSeries("tx", cell.ColorRed, []int{4, 8})
+			},
+			canvas: image.Rect(0, 0, 2, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{0, 1}, '▄', cell.FgColor(cell.ColorRed), cell.Inverse())
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▄', cell.FgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{1, 1}, '█', cell.FgColor(cell.ColorRed))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "additional series can be cleared",
+			update: func(sl *SparkLine) error {
+				if err := sl.AddSeries("tx", cell.ColorRed, []int{8}); err != nil {
+					return err
+				}
+				sl.Clear()
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantCapacity: 1,
+		},
 		{
 			desc: "draws data points from the right",
 			update: func(sl *SparkLine) error {
@@ -529,6 +739,17 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeNone,
 			},
 		},
+		{
+			desc: "mirrored needs two rows",
+			opts: []Option{
+				Mirror(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 2},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
 		{
 			desc: "no label and fixed height",
 			opts: []Option{
@@ -554,6 +775,17 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeNone,
 			},
 		},
+		{
+			desc: "wants all mouse events with a hover callback",
+			opts: []Option{
+				OnHover(func(*Hover) error { return nil }),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
 	}
 
 	for _, tc := range tests {
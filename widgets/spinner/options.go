@@ -0,0 +1,127 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+// options.go contains configurable options for Spinner.
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	frames        []string
+	frameInterval time.Duration
+	label         string
+	labelCellOpts []cell.Option
+	glyphCellOpts []cell.Option
+	stopped       bool
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		frames: Dots,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if len(o.frames) == 0 {
+		return errors.New("invalid Frames, at least one frame is required")
+	}
+	width := runewidth.StringWidth(o.frames[0])
+	for _, f := range o.frames {
+		if w := runewidth.StringWidth(f); w != width || w == 0 {
+			return fmt.Errorf("invalid Frames %q, all the frames must occupy the same non-zero number of cells", o.frames)
+		}
+	}
+	if o.frameInterval < 0 {
+		return fmt.Errorf("invalid FrameInterval(%v), must be value in range 0 <= value", o.frameInterval)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// The built-in sets of frames.
+var (
+	// Dots is an animation of dots rotating in a braille cell.
+	Dots = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	// Line is an animation of a rotating line drawn with ASCII characters.
+	Line = []string{"-", "\\", "|", "/"}
+	// Braille is an animation of a gap rotating in a full braille cell.
+	Braille = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+)
+
+// Frames sets the frames of the animation, e.g. one of the built-in sets
+// Dots, Line or Braille. All the frames must occupy the same number of cells.
+// Defaults to Dots.
+func Frames(frames []string) Option {
+	return option(func(opts *options) {
+		opts.frames = frames
+	})
+}
+
+// FrameInterval sets the minimum time between two frames of the animation.
+// By default the animation advances by one frame each time the widget is
+// drawn, i.e. at the redraw interval of termdash, this option slows it down
+// when the redraw interval is short.
+func FrameInterval(d time.Duration) Option {
+	return option(func(opts *options) {
+		opts.frameInterval = d
+	})
+}
+
+// Label sets the text displayed on the right of the spinner.
+func Label(text string, co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.label = text
+		opts.labelCellOpts = co
+	})
+}
+
+// GlyphCellOpts sets the cell options of the animated glyph.
+// Defaults to the default cell options.
+func GlyphCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.glyphCellOpts = co
+	})
+}
+
+// Stopped creates the spinner stopped, the animation starts when Start is
+// called.
+func Stopped() Option {
+	return option(func(opts *options) {
+		opts.stopped = true
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spinner implements a widget that indicates ongoing activity.
+package spinner
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Spinner displays an animated glyph followed by an optional label.
+//
+// The animation advances by one frame each time the widget is drawn, so its
+// speed follows the redraw interval of termdash, see also FrameInterval.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Spinner struct {
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// frame is the index of the displayed frame.
+	frame int
+	// lastFrame is the time the displayed frame was first drawn.
+	lastFrame time.Time
+	// drawn indicates that the widget was drawn at least once.
+	drawn bool
+	// running indicates that the animation is running.
+	running bool
+
+	// label is the text displayed on the right of the glyph.
+	label string
+	// labelCellOpts are the cell options of the label.
+	labelCellOpts []cell.Option
+
+	// opts are the provided options.
+	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+}
+
+// New returns a new Spinner.
+func New(opts ...Option) (*Spinner, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Spinner{
+		running:       !opt.stopped,
+		label:         opt.label,
+		labelCellOpts: opt.labelCellOpts,
+		opts:          opt,
+	}, nil
+}
+
+// SetLabel replaces the label displayed on the right of the glyph.
+func (s *Spinner) SetLabel(text string, co ...cell.Option) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.dirty.Mark()
+
+	s.label = text
+	s.labelCellOpts = co
+}
+
+// Start starts the animation.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.dirty.Mark()
+	s.running = true
+}
+
+// Stop stops the animation and hides the glyph, the label remains visible.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.dirty.Mark()
+	s.running = false
+}
+
+// Running asserts whether the animation is running.
+func (s *Spinner) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// advance moves to the next frame if the FrameInterval elapsed since the
+// displayed frame was first drawn.
+// Caller must hold s.mu.
+func (s *Spinner) advance(now time.Time) {
+	if !s.drawn {
+		s.drawn = true
+		s.lastFrame = now
+		return
+	}
+	if now.Sub(s.lastFrame) < s.opts.frameInterval {
+		return
+	}
+	s.frame = (s.frame + 1) % len(s.opts.frames)
+	s.lastFrame = now
+}
+
+// Draw draws the Spinner widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (s *Spinner) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ar := cvs.Area()
+	gw := runewidth.StringWidth(s.opts.frames[0])
+	if ar.Dx() < gw || ar.Dy() < 1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if s.running {
+		s.advance(time.Now())
+		// The next frame needs to be drawn.
+		s.dirty.Mark()
+		if err := draw.Text(cvs, s.opts.frames[s.frame], ar.Min, draw.TextCellOpts(s.opts.glyphCellOpts...)); err != nil {
+			return err
+		}
+	}
+
+	if s.label == "" || ar.Dx() < gw+2 {
+		return nil
+	}
+	return draw.Text(
+		cvs, s.label, image.Point{ar.Min.X + gw + 1, ar.Min.Y},
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		draw.TextMaxX(ar.Max.X),
+		draw.TextCellOpts(s.labelCellOpts...),
+	)
+}
+
+// Keyboard input isn't supported on the Spinner widget.
+func (s *Spinner) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the Spinner widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Spinner widget.
+func (s *Spinner) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the Spinner widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (s *Spinner) Options() widgetapi.Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gw := runewidth.StringWidth(s.opts.frames[0])
+	return widgetapi.Options{
+		MinimumSize:  image.Point{gw, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (s *Spinner) SetDirtyFunc(markDirty func()) {
+	s.dirty.Set(markDirty)
+}
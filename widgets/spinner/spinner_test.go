@@ -0,0 +1,260 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestSpinner(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		// draws is the number of times the widget is drawn before the
+		// comparison.
+		draws      int
+		update     func(*Spinner)
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+	}{
+		{
+			desc:       "fails without frames",
+			opts:       []Option{Frames(nil)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on frames of different widths",
+			opts:       []Option{Frames([]string{"a", "bb"})},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on empty frames",
+			opts:       []Option{Frames([]string{"", ""})},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on negative frame interval",
+			opts:       []Option{FrameInterval(-1)},
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws the first frame",
+			canvas: image.Rect(0, 0, 3, 1),
+			draws:  1,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, Dots[0], image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "advances one frame per draw and wraps around",
+			opts:   []Option{Frames(Line)},
+			canvas: image.Rect(0, 0, 3, 1),
+			draws:  6,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, Line[1], image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't advance before the frame interval elapses",
+			opts:   []Option{Frames(Braille), FrameInterval(time.Hour)},
+			canvas: image.Rect(0, 0, 3, 1),
+			draws:  3,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, Braille[0], image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the label with cell options",
+			opts: []Option{
+				Frames(Line),
+				GlyphCellOpts(cell.FgColor(cell.ColorGreen)),
+				Label("Loading", cell.FgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 7, 1),
+			draws:  1,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "-", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testdraw.MustText(cvs, "Load…", image.Point{2, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "stopped spinner draws only the label",
+			opts:   []Option{Stopped(), Label("Idle")},
+			canvas: image.Rect(0, 0, 7, 1),
+			draws:  1,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "Idle", image.Point{2, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "updates the label and starts the animation",
+			opts:   []Option{Stopped(), Frames(Line), Label("Idle")},
+			canvas: image.Rect(0, 0, 7, 1),
+			draws:  1,
+			update: func(s *Spinner) {
+				s.SetLabel("Busy")
+				s.Start()
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "-", image.Point{0, 0})
+				testdraw.MustText(cvs, "Busy", image.Point{2, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "requests resize when the glyph doesn't fit",
+			opts:   []Option{Frames([]string{"[=  ]", "[ = ]"})},
+			canvas: image.Rect(0, 0, 4, 1),
+			draws:  1,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+			if tc.update != nil {
+				tc.update(s)
+			}
+
+			var c *canvas.Canvas
+			for i := 0; i < tc.draws; i++ {
+				c, err = canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := s.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if !s.Running() {
+		t.Errorf("Running => false, want true")
+	}
+	s.Stop()
+	if s.Running() {
+		t.Errorf("Running after Stop => true, want false")
+	}
+}
+
+func TestMarksDirtyWhileRunning(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	var marks int
+	s.SetDirtyFunc(func() { marks++ })
+
+	cvs := testcanvas.MustNew(image.Rect(0, 0, 3, 1))
+	if err := s.Draw(cvs, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if marks == 0 {
+		t.Errorf("Draw => didn't mark the running spinner dirty")
+	}
+
+	s.Stop()
+	marks = 0
+	if err := s.Draw(cvs, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if marks != 0 {
+		t.Errorf("Draw => marked the stopped spinner dirty %d times, want none", marks)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := s.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
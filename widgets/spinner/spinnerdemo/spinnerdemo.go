@@ -0,0 +1,114 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary spinnerdemo displays the built-in animations of the Spinner widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/spinner"
+)
+
+// toggle periodically stops and starts the spinner until the context expires.
+func toggle(ctx context.Context, s *spinner.Spinner, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if s.Running() {
+				s.Stop()
+				s.SetLabel("Paused")
+			} else {
+				s.Start()
+				s.SetLabel("Working")
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dots, err := spinner.New(
+		spinner.Label("Loading dots"),
+		spinner.GlyphCellOpts(cell.FgColor(cell.ColorCyan)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	line, err := spinner.New(
+		spinner.Frames(spinner.Line),
+		spinner.FrameInterval(200*time.Millisecond),
+		spinner.Label("Slow line"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	braille, err := spinner.New(
+		spinner.Frames(spinner.Braille),
+		spinner.Label("Working", cell.FgColor(cell.ColorYellow)),
+		spinner.GlyphCellOpts(cell.FgColor(cell.ColorGreen)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go toggle(ctx, braille, 3*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(container.PlaceWidget(dots)),
+			container.Bottom(
+				container.SplitHorizontal(
+					container.Top(container.PlaceWidget(line)),
+					container.Bottom(container.PlaceWidget(braille)),
+				),
+			),
+			container.SplitPercent(33),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
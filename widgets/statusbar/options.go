@@ -0,0 +1,106 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusbar
+
+// options.go contains configurable options for StatusBar.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	cellOpts      []cell.Option
+	gap           int
+	truncateOrder []Segment
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		gap:           DefaultGap,
+		truncateOrder: defaultTruncateOrder,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.gap < 0 {
+		return fmt.Errorf("invalid Gap %d, must be zero or positive", o.gap)
+	}
+	seen := map[Segment]bool{}
+	for _, s := range o.truncateOrder {
+		if _, ok := segmentNames[s]; !ok {
+			return fmt.Errorf("invalid TruncateOrder, unsupported segment %v(%d)", s, s)
+		}
+		if seen[s] {
+			return fmt.Errorf("invalid TruncateOrder, segment %v is provided more than once", s)
+		}
+		seen[s] = true
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// CellOpts sets the cell options on all the cells of the bar, including the
+// empty cells between the segments, e.g. the background color of the bar.
+// The cell options of the individual segments set by SetCellOpts are applied
+// on top of these.
+func CellOpts(opts ...cell.Option) Option {
+	return option(func(o *options) {
+		o.cellOpts = opts
+	})
+}
+
+// DefaultGap is the default value for the Gap option.
+const DefaultGap = 1
+
+// Gap sets the minimum number of empty cells between the displayed segments.
+// Defaults to DefaultGap.
+func Gap(cells int) Option {
+	return option(func(o *options) {
+		o.gap = cells
+	})
+}
+
+// defaultTruncateOrder is the default value for the TruncateOrder option.
+var defaultTruncateOrder = []Segment{Center, Right, Left}
+
+// TruncateOrder sets the order in which the segments are truncated when they
+// don't fit the width of the bar. The first segment is truncated and hidden
+// before any of the following segments is truncated. The segments that aren't
+// provided are truncated after the provided ones in the default order.
+// Defaults to truncating the Center segment first, followed by the Right and
+// the Left segments.
+func TruncateOrder(segments ...Segment) Option {
+	return option(func(o *options) {
+		o.truncateOrder = segments
+	})
+}
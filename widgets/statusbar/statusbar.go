@@ -0,0 +1,285 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statusbar contains a widget that displays a single row status bar.
+package statusbar
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Segment identifies one of the segments of the StatusBar.
+type Segment int
+
+// String implements fmt.Stringer()
+func (s Segment) String() string {
+	if n, ok := segmentNames[s]; ok {
+		return n
+	}
+	return "SegmentUnknown"
+}
+
+// segmentNames maps Segment values to human readable names.
+var segmentNames = map[Segment]string{
+	Left:   "Left",
+	Center: "Center",
+	Right:  "Right",
+}
+
+const (
+	// Left is the segment aligned to the left edge of the bar.
+	Left Segment = iota
+	// Center is the segment centered on the bar.
+	Center
+	// Right is the segment aligned to the right edge of the bar.
+	Right
+
+	// segmentCount is the number of the segments.
+	segmentCount int = iota
+)
+
+// segment is the content of one segment of the bar.
+type segment struct {
+	text string
+	opts *setOptions
+}
+
+// StatusBar displays a single row of text split into the left, center and
+// right segments, e.g.:
+//
+//	NORMAL        main.go        Ln 12, Col 4
+//
+// The left and right segments are aligned to the edges of the widget and the
+// center segment is centered between them. Segments that don't fit the width
+// of the widget are truncated in the order set by the TruncateOrder option and
+// hidden if they don't fit at all.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type StatusBar struct {
+	// segments are the displayed segments indexed by Segment.
+	segments [segmentCount]segment
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the StatusBar.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new StatusBar widget.
+func New(opts ...Option) (*StatusBar, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &StatusBar{
+		opts: opt,
+	}, nil
+}
+
+// Set sets the text of the specified segment. An empty text clears the
+// segment. The provided options replace any options provided when the segment
+// was last set.
+func (sb *StatusBar) Set(s Segment, text string, sOpts ...SetOption) error {
+	if _, ok := segmentNames[s]; !ok {
+		return fmt.Errorf("unsupported segment %v(%d)", s, s)
+	}
+	if text != "" {
+		if err := wrap.ValidText(text); err != nil {
+			return fmt.Errorf("invalid text %q: %v", text, err)
+		}
+		if strings.ContainsRune(text, '\n') {
+			return fmt.Errorf("invalid text %q: newline characters aren't allowed", text)
+		}
+	}
+
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	defer sb.dirty.Mark()
+	sb.segments[s] = segment{
+		text: text,
+		opts: newSetOptions(sOpts...),
+	}
+	return nil
+}
+
+// Reset clears all the segments.
+func (sb *StatusBar) Reset() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	defer sb.dirty.Mark()
+	sb.segments = [segmentCount]segment{}
+}
+
+// Draw draws the StatusBar widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (sb *StatusBar) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	ar := cvs.Area()
+	row := image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+1)
+	if len(sb.opts.cellOpts) > 0 {
+		if err := cvs.SetAreaCells(row, ' ', sb.opts.cellOpts...); err != nil {
+			return err
+		}
+	}
+
+	var texts [segmentCount]string
+	widths := sb.widths(row.Dx())
+	for s, w := range widths {
+		if w == 0 {
+			continue
+		}
+		t, err := draw.Truncate(sb.segments[s].text, w)
+		if err != nil {
+			return err
+		}
+		texts[s] = t
+		// Full-width runes are never cut in half, so the truncated text can
+		// be narrower.
+		widths[s] = runewidth.StringWidth(t)
+	}
+
+	// The center segment is centered on the bar unless that would make it
+	// overlap the other segments.
+	lo, hi := 0, row.Dx()-widths[Center]
+	if widths[Left] > 0 {
+		lo = widths[Left] + sb.opts.gap
+	}
+	if widths[Right] > 0 {
+		hi -= widths[Right] + sb.opts.gap
+	}
+	center := (row.Dx() - widths[Center]) / 2
+	if center < lo {
+		center = lo
+	}
+	if center > hi {
+		center = hi
+	}
+
+	starts := [segmentCount]int{
+		Left:   row.Min.X,
+		Center: row.Min.X + center,
+		Right:  row.Max.X - widths[Right],
+	}
+	for s, t := range texts {
+		if t == "" {
+			continue
+		}
+		cellOpts := append(append([]cell.Option{}, sb.opts.cellOpts...), sb.segments[s].opts.cellOpts...)
+		if err := draw.Text(cvs, t, image.Point{starts[s], row.Min.Y}, draw.TextCellOpts(cellOpts...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// widths returns the number of cells each of the segments can occupy on a bar
+// of the specified width. Segments that don't fit are truncated in the order
+// of the TruncateOrder option, a segment that gets zero cells is hidden.
+func (sb *StatusBar) widths(width int) [segmentCount]int {
+	var ws [segmentCount]int
+	for s, seg := range sb.segments {
+		ws[s] = runewidth.StringWidth(seg.text)
+	}
+	order := append([]Segment{}, sb.opts.truncateOrder...)
+	for _, s := range defaultTruncateOrder {
+		if !containsSegment(order, s) {
+			order = append(order, s)
+		}
+	}
+	for _, s := range order {
+		excess := sb.required(ws) - width
+		if excess <= 0 {
+			break
+		}
+		if excess >= ws[s] {
+			ws[s] = 0
+		} else {
+			ws[s] -= excess
+		}
+	}
+	return ws
+}
+
+// containsSegment asserts whether the segments contain the segment s.
+func containsSegment(segments []Segment, s Segment) bool {
+	for _, seg := range segments {
+		if seg == s {
+			return true
+		}
+	}
+	return false
+}
+
+// required returns the number of cells required to display segments of the
+// specified widths including the gaps between them.
+func (sb *StatusBar) required(ws [segmentCount]int) int {
+	var sum, shown int
+	for _, w := range ws {
+		if w > 0 {
+			sum += w
+			shown++
+		}
+	}
+	if shown > 1 {
+		sum += (shown - 1) * sb.opts.gap
+	}
+	return sum
+}
+
+// Keyboard input isn't supported on the StatusBar widget.
+func (*StatusBar) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	return errors.New("the StatusBar widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the StatusBar widget.
+func (*StatusBar) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	return errors.New("the StatusBar widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (sb *StatusBar) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (sb *StatusBar) SetDirtyFunc(markDirty func()) {
+	sb.dirty.Set(markDirty)
+}
@@ -0,0 +1,339 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusbar
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// set is a text set on a segment of the widget.
+type set struct {
+	segment Segment
+	text    string
+	opts    []SetOption
+}
+
+func TestStatusBar(t *testing.T) {
+	tests := []struct {
+		desc        string
+		opts        []Option
+		canvas      image.Rectangle
+		sets        []set
+		want        func(size image.Point) *faketerm.Terminal
+		wantNewErr  bool
+		wantSetErr  bool
+		wantDrawErr bool
+	}{
+		{
+			desc:       "fails on a negative gap",
+			opts:       []Option{Gap(-1)},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on an unsupported segment in TruncateOrder",
+			opts:       []Option{TruncateOrder(Left, Segment(-1))},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a duplicate segment in TruncateOrder",
+			opts:       []Option{TruncateOrder(Left, Right, Left)},
+			canvas:     image.Rect(0, 0, 20, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on setting an unsupported segment",
+			canvas:     image.Rect(0, 0, 20, 1),
+			sets:       []set{{segment: Segment(3), text: "text"}},
+			wantSetErr: true,
+		},
+		{
+			desc:       "fails on a text with a newline",
+			canvas:     image.Rect(0, 0, 20, 1),
+			sets:       []set{{segment: Left, text: "a\nb"}},
+			wantSetErr: true,
+		},
+		{
+			desc:   "draws nothing without segments",
+			canvas: image.Rect(0, 0, 20, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws the segments",
+			canvas: image.Rect(0, 0, 20, 1),
+			sets: []set{
+				{segment: Left, text: "ab"},
+				{segment: Center, text: "cd"},
+				{segment: Right, text: "ef"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "ab", image.Point{0, 0})
+				testdraw.MustText(c, "cd", image.Point{9, 0})
+				testdraw.MustText(c, "ef", image.Point{18, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "replaces and clears segments",
+			canvas: image.Rect(0, 0, 20, 1),
+			sets: []set{
+				{segment: Left, text: "ab"},
+				{segment: Right, text: "ef"},
+				{segment: Left, text: "xy"},
+				{segment: Right, text: ""},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "xy", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "styles the bar, segment options are applied on top",
+			opts:   []Option{CellOpts(cell.BgColor(cell.ColorBlue))},
+			canvas: image.Rect(0, 0, 6, 1),
+			sets: []set{
+				{segment: Left, text: "a", opts: []SetOption{SetCellOpts(cell.FgColor(cell.ColorRed))}},
+				{segment: Right, text: "b"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(c, c.Area(), ' ', cell.BgColor(cell.ColorBlue))
+				testdraw.MustText(c, "a", image.Point{0, 0}, draw.TextCellOpts(cell.BgColor(cell.ColorBlue), cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "b", image.Point{5, 0}, draw.TextCellOpts(cell.BgColor(cell.ColorBlue)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "moves the center segment so it doesn't overlap the others",
+			canvas: image.Rect(0, 0, 20, 1),
+			sets: []set{
+				{segment: Left, text: "0123456789"},
+				{segment: Center, text: "abcd"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "0123456789", image.Point{0, 0})
+				testdraw.MustText(c, "abcd", image.Point{11, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates the center segment first",
+			canvas: image.Rect(0, 0, 12, 1),
+			sets: []set{
+				{segment: Left, text: "left"},
+				{segment: Center, text: "center"},
+				{segment: Right, text: "right"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "left", image.Point{0, 0})
+				testdraw.MustText(c, "…", image.Point{5, 0})
+				testdraw.MustText(c, "right", image.Point{7, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "hides segments that don't fit",
+			canvas: image.Rect(0, 0, 10, 1),
+			sets: []set{
+				{segment: Left, text: "left"},
+				{segment: Center, text: "center"},
+				{segment: Right, text: "right"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "left", image.Point{0, 0})
+				testdraw.MustText(c, "right", image.Point{5, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates in the custom order, then in the default order",
+			opts:   []Option{TruncateOrder(Left)},
+			canvas: image.Rect(0, 0, 10, 1),
+			sets: []set{
+				{segment: Left, text: "left"},
+				{segment: Center, text: "center"},
+				{segment: Right, text: "right"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "cen…", image.Point{0, 0})
+				testdraw.MustText(c, "right", image.Point{5, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "custom gap",
+			opts:   []Option{Gap(0)},
+			canvas: image.Rect(0, 0, 6, 1),
+			sets: []set{
+				{segment: Left, text: "abc"},
+				{segment: Right, text: "def"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "abcdef", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates the last segment to the width of the bar",
+			canvas: image.Rect(0, 0, 3, 1),
+			sets: []set{
+				{segment: Left, text: "hello"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "he…", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't cut full-width runes in half",
+			canvas: image.Rect(0, 0, 6, 1),
+			sets: []set{
+				{segment: Left, text: "a"},
+				{segment: Right, text: "世界世界"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "世…", image.Point{3, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sb, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, s := range tc.sets {
+				err := sb.Set(s.segment, s.text, s.opts...)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("Set => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = sb.Draw(c, &widgetapi.Meta{})
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	sb, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := sb.Set(Left, "a"); err != nil {
+		t.Fatalf("Set => unexpected error: %v", err)
+	}
+	sb.Reset()
+
+	c := testcanvas.MustNew(image.Rect(0, 0, 10, 1))
+	if err := sb.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got := faketerm.MustNew(c.Size())
+	testcanvas.MustApply(c, got)
+	if diff := faketerm.Diff(faketerm.MustNew(c.Size()), got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	sb, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := sb.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		MaximumSize:  image.Point{0, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
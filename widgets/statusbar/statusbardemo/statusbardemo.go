@@ -0,0 +1,111 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary statusbardemo displays a StatusBar widget below a text widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/statusbar"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// playStatusBar periodically updates the clock on the StatusBar widget.
+// Exits when the context expires.
+func playStatusBar(ctx context.Context, sb *statusbar.StatusBar, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case t := <-ticker.C:
+			if err := sb.Set(statusbar.Right, t.Format("15:04:05")); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txt, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := txt.Write("Resize the terminal to see how the segments of the status bar are truncated."); err != nil {
+		panic(err)
+	}
+
+	sb, err := statusbar.New(
+		statusbar.CellOpts(cell.BgColor(cell.ColorBlue), cell.FgColor(cell.ColorWhite)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := sb.Set(statusbar.Left, " NORMAL ", statusbar.SetCellOpts(cell.BgColor(cell.ColorGreen), cell.FgColor(cell.ColorBlack), cell.Bold())); err != nil {
+		panic(err)
+	}
+	if err := sb.Set(statusbar.Center, "statusbardemo.go - PRESS Q TO QUIT"); err != nil {
+		panic(err)
+	}
+	if err := sb.Set(statusbar.Right, time.Now().Format("15:04:05")); err != nil {
+		panic(err)
+	}
+	go playStatusBar(ctx, sb, 1*time.Second)
+
+	c, err := container.New(
+		t,
+		container.SplitHorizontal(
+			container.Top(
+				container.Border(linestyle.Light),
+				container.BorderTitle("StatusBar demo"),
+				container.PlaceWidget(txt),
+			),
+			container.Bottom(
+				container.PlaceWidget(sb),
+			),
+			container.SplitFixedFromEnd(1),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
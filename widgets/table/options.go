@@ -0,0 +1,264 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+// options.go contains configurable options for Table.
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/wrap"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// Column describes one column of the table.
+type Column struct {
+	// Header is the text displayed in the header row above the column.
+	Header string
+
+	// Width is the fixed width of the column in cells.
+	// At most one of Width and WidthPercent can be set. Columns with neither
+	// evenly share the width that remains after all the other columns.
+	Width int
+
+	// WidthPercent is the width of the column as a percentage of the width of
+	// the widget, excluding the gaps between the columns.
+	WidthPercent int
+
+	// Align is the horizontal alignment of the text in the column.
+	Align align.Horizontal
+
+	// Less reports whether the text of the first cell sorts before the text of
+	// the second cell when sorting by this column. Optional, by default cells
+	// whose text are numbers are sorted numerically and all the other cells
+	// alphabetically.
+	Less func(a, b string) bool
+}
+
+// validate validates the column.
+func (c *Column) validate() error {
+	if c.Header != "" {
+		if err := wrap.ValidText(c.Header); err != nil {
+			return fmt.Errorf("invalid Header %q: %v", c.Header, err)
+		}
+		if strings.ContainsRune(c.Header, '\n') {
+			return fmt.Errorf("invalid Header %q: newline characters aren't allowed", c.Header)
+		}
+	}
+	if c.Width < 0 {
+		return fmt.Errorf("invalid Width %d, must be zero or positive", c.Width)
+	}
+	if c.WidthPercent < 0 || c.WidthPercent > 100 {
+		return fmt.Errorf("invalid WidthPercent %d, must be in range 0 <= WidthPercent <= 100", c.WidthPercent)
+	}
+	if c.Width > 0 && c.WidthPercent > 0 {
+		return fmt.Errorf("only one of Width(%d) and WidthPercent(%d) can be set", c.Width, c.WidthPercent)
+	}
+	return nil
+}
+
+// options holds the provided options.
+type options struct {
+	columns          []Column
+	columnGap        int
+	headerCellOpts   []cell.Option
+	selectedCellOpts []cell.Option
+	sortAsc          rune
+	sortDesc         rune
+	onSelect         SelectFn
+
+	keyUp           keyboard.Key
+	keyDown         keyboard.Key
+	keyPgUp         keyboard.Key
+	keyPgDown       keyboard.Key
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		columnGap:        DefaultColumnGap,
+		headerCellOpts:   []cell.Option{cell.Bold()},
+		selectedCellOpts: []cell.Option{cell.Inverse()},
+		sortAsc:          DefaultSortAscRune,
+		sortDesc:         DefaultSortDescRune,
+		keyUp:            DefaultKeyUp,
+		keyDown:          DefaultKeyDown,
+		keyPgUp:          DefaultKeyPageUp,
+		keyPgDown:        DefaultKeyPageDown,
+		mouseUpButton:    DefaultMouseButtonUp,
+		mouseDownButton:  DefaultMouseButtonDown,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if len(o.columns) == 0 {
+		return errors.New("at least one column must be provided via the Columns option")
+	}
+	percent := 0
+	for i, c := range o.columns {
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("invalid column %d: %v", i, err)
+		}
+		percent += c.WidthPercent
+	}
+	if percent > 100 {
+		return fmt.Errorf("invalid columns, the sum of WidthPercent is %d, must be at most 100", percent)
+	}
+	if o.columnGap < 0 {
+		return fmt.Errorf("invalid ColumnGap %d, must be zero or positive", o.columnGap)
+	}
+
+	keys := map[keyboard.Key]bool{
+		o.keyUp:     true,
+		o.keyDown:   true,
+		o.keyPgUp:   true,
+		o.keyPgDown: true,
+	}
+	if len(keys) != 4 {
+		return fmt.Errorf("invalid navigation keys: %s, %s, %s, %s, the keys must be unique", o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid navigation mouse buttons: %s, %s, the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// Columns sets the columns of the table. Required, at least one column must
+// be provided.
+func Columns(cols ...Column) Option {
+	return option(func(opts *options) {
+		opts.columns = cols
+	})
+}
+
+// DefaultColumnGap is the default value for the ColumnGap option.
+const DefaultColumnGap = 1
+
+// ColumnGap sets the number of empty cells between the columns.
+// Defaults to DefaultColumnGap.
+func ColumnGap(cells int) Option {
+	return option(func(opts *options) {
+		opts.columnGap = cells
+	})
+}
+
+// HeaderCellOpts sets the cell options on the cells of the header row.
+// Defaults to bold text.
+func HeaderCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.headerCellOpts = co
+	})
+}
+
+// SelectedCellOpts sets the cell options on the cells of the selected row.
+// These are applied on top of the cell options of the individual cells.
+// Defaults to inverse colors.
+func SelectedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectedCellOpts = co
+	})
+}
+
+// The default runes that mark the column the table is sorted by.
+const (
+	DefaultSortAscRune  = '▲'
+	DefaultSortDescRune = '▼'
+)
+
+// SortRunes sets the runes displayed in the header of the column the table
+// is sorted by, when sorted in the ascending and in the descending order.
+// Defaults to DefaultSortAscRune and DefaultSortDescRune.
+func SortRunes(asc, desc rune) Option {
+	return option(func(opts *options) {
+		opts.sortAsc = asc
+		opts.sortDesc = desc
+	})
+}
+
+// SelectFn is called when the selected row changes. The argument is the index
+// of the selected row in the order the rows were added, regardless of the
+// order they are displayed in.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Table isn't locked while the function
+// executes, so it can read from or modify the Table.
+type SelectFn func(row int) error
+
+// OnSelect sets the function that is called when the user selects a row.
+func OnSelect(fn SelectFn) Option {
+	return option(func(opts *options) {
+		opts.onSelect = fn
+	})
+}
+
+// The default keys that move the selection.
+const (
+	DefaultKeyUp       = keyboard.KeyArrowUp
+	DefaultKeyDown     = keyboard.KeyArrowDown
+	DefaultKeyPageUp   = keyboard.KeyPgUp
+	DefaultKeyPageDown = keyboard.KeyPgDn
+)
+
+// NavigationKeys configures the keyboard keys that move the selection one
+// row or one page up and down.
+// The provided keys must be unique, e.g. the same key cannot be both up and
+// down.
+func NavigationKeys(up, down, pageUp, pageDown keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyPgUp = pageUp
+		opts.keyPgDown = pageDown
+	})
+}
+
+// The default mouse buttons that move the selection.
+const (
+	DefaultMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// NavigationMouseButtons configures the mouse buttons that move the
+// selection one row up and down.
+// The provided buttons must be unique, e.g. the same button cannot be both up
+// and down.
+func NavigationMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
@@ -0,0 +1,593 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package table contains a widget that displays rows of text in columns.
+package table
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/alignfor"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Cell is one cell of the table.
+type Cell struct {
+	// Text is the text displayed in the cell, truncated if it doesn't fit
+	// the width of the column.
+	Text string
+	// CellOpts are the cell options on the cells that contain the text.
+	CellOpts []cell.Option
+}
+
+// NewCell returns a new Cell with the text and cell options.
+func NewCell(text string, co ...cell.Option) Cell {
+	return Cell{
+		Text:     text,
+		CellOpts: co,
+	}
+}
+
+// Table displays rows of cells aligned into columns under a header row.
+//
+// The rows can be sorted by any of the columns, either by calling SortBy or
+// by clicking on the header of the column, clicking again reverses the order.
+// When focused, the keyboard moves the selected row, the selection can also
+// be set by clicking on a row. Rows that don't fit the height of the widget
+// scroll so that the selected row is always visible.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Table struct {
+	// rows are the rows in the order they were added.
+	rows [][]Cell
+	// order are indexes into rows in the order the rows are displayed.
+	order []int
+
+	// sortCol is the index of the column the rows are sorted by or -1 if the
+	// rows aren't sorted.
+	sortCol int
+	// sortDesc indicates if the rows are sorted in the descending order.
+	sortDesc bool
+
+	// selected is the index into rows of the selected row or -1 if no row is
+	// selected.
+	selected int
+	// offset is the position in order of the first displayed row.
+	offset int
+
+	// lastWidths are the widths of the columns as of the last call to Draw.
+	lastWidths []int
+	// lastRowsAr is the area that contains the rows as of the last call to
+	// Draw.
+	lastRowsAr image.Rectangle
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the Table.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Table widget.
+func New(opts ...Option) (*Table, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Table{
+		sortCol:  -1,
+		selected: -1,
+		opts:     opt,
+	}, nil
+}
+
+// validateRow validates the cells of one row.
+func (t *Table) validateRow(cells []Cell) error {
+	if got, max := len(cells), len(t.opts.columns); got > max {
+		return fmt.Errorf("the row has %d cells, but there are only %d columns", got, max)
+	}
+	for i, c := range cells {
+		if c.Text == "" {
+			continue
+		}
+		if err := wrap.ValidText(c.Text); err != nil {
+			return fmt.Errorf("invalid text %q in cell %d: %v", c.Text, i, err)
+		}
+		if strings.ContainsRune(c.Text, '\n') {
+			return fmt.Errorf("invalid text %q in cell %d: newline characters aren't allowed", c.Text, i)
+		}
+	}
+	return nil
+}
+
+// AddRow adds a row after all the existing rows. The row can have fewer cells
+// than there are columns, the remaining cells are empty.
+func (t *Table) AddRow(cells ...Cell) error {
+	if err := t.validateRow(cells); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	t.rows = append(t.rows, cells)
+	t.sort()
+	return nil
+}
+
+// SetRows replaces all the rows. The selection is kept if the selected row
+// still exists.
+func (t *Table) SetRows(rows [][]Cell) error {
+	for i, r := range rows {
+		if err := t.validateRow(r); err != nil {
+			return fmt.Errorf("invalid row %d: %v", i, err)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	t.rows = rows
+	if t.selected >= len(rows) {
+		t.selected = -1
+	}
+	t.sort()
+	return nil
+}
+
+// Reset removes all the rows and clears the selection and the sorting.
+func (t *Table) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	t.rows = nil
+	t.order = nil
+	t.sortCol = -1
+	t.sortDesc = false
+	t.selected = -1
+	t.offset = 0
+}
+
+// SortBy sorts the rows by the column with the specified index, either in the
+// ascending or in the descending order.
+func (t *Table) SortBy(col int, desc bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	if col < 0 || col >= len(t.opts.columns) {
+		return fmt.Errorf("invalid column %d, must be in range 0 <= col < %d", col, len(t.opts.columns))
+	}
+	t.sortCol = col
+	t.sortDesc = desc
+	t.sort()
+	return nil
+}
+
+// Selected returns the index of the selected row in the order the rows were
+// added. Returns false if no row is selected.
+func (t *Table) Selected() (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.selected, t.selected >= 0
+}
+
+// Select selects the row with the specified index in the order the rows
+// were added. Doesn't call the function provided via the OnSelect option.
+func (t *Table) Select(row int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	if row < 0 || row >= len(t.rows) {
+		return fmt.Errorf("invalid row %d, must be in range 0 <= row < %d", row, len(t.rows))
+	}
+	t.selected = row
+	return nil
+}
+
+// cellText returns the text of the cell in the row and column or an empty
+// string if the row doesn't have such cell.
+func (t *Table) cellText(row, col int) string {
+	if r := t.rows[row]; col < len(r) {
+		return r[col].Text
+	}
+	return ""
+}
+
+// less is the default comparison of cells when sorting. Numbers sort before
+// other text and are compared numerically.
+func less(a, b string) bool {
+	af, aErr := strconv.ParseFloat(a, 64)
+	bf, bErr := strconv.ParseFloat(b, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return af < bf
+	case aErr == nil:
+		return true
+	case bErr == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+// sort determines the order in which the rows are displayed.
+// The caller must hold t.mu.
+func (t *Table) sort() {
+	t.order = make([]int, len(t.rows))
+	for i := range t.order {
+		t.order[i] = i
+	}
+	if t.sortCol < 0 {
+		return
+	}
+
+	lessFn := less
+	if fn := t.opts.columns[t.sortCol].Less; fn != nil {
+		lessFn = fn
+	}
+	sort.SliceStable(t.order, func(i, j int) bool {
+		a := t.cellText(t.order[i], t.sortCol)
+		b := t.cellText(t.order[j], t.sortCol)
+		if t.sortDesc {
+			return lessFn(b, a)
+		}
+		return lessFn(a, b)
+	})
+}
+
+// position returns the position of the selected row in the displayed order
+// or -1 if no row is selected.
+func (t *Table) position() int {
+	for pos, i := range t.order {
+		if i == t.selected {
+			return pos
+		}
+	}
+	return -1
+}
+
+// columnWidths returns the widths of the columns in cells when the table is
+// the specified number of cells wide. Columns that don't fit have zero
+// width.
+func (t *Table) columnWidths(width int) []int {
+	cols := t.opts.columns
+	avail := width - t.opts.columnGap*(len(cols)-1)
+	if avail < 0 {
+		avail = 0
+	}
+
+	widths := make([]int, len(cols))
+	used, auto := 0, 0
+	for i, c := range cols {
+		switch {
+		case c.Width > 0:
+			widths[i] = c.Width
+		case c.WidthPercent > 0:
+			widths[i] = avail * c.WidthPercent / 100
+		default:
+			auto++
+			continue
+		}
+		used += widths[i]
+	}
+
+	if remaining := avail - used; auto > 0 && remaining > 0 {
+		share, extra := remaining/auto, remaining%auto
+		for i, c := range cols {
+			if c.Width > 0 || c.WidthPercent > 0 {
+				continue
+			}
+			widths[i] = share
+			if extra > 0 {
+				widths[i]++
+				extra--
+			}
+		}
+	}
+
+	x := 0
+	for i := range widths {
+		if max := width - x; widths[i] > max {
+			widths[i] = max
+		}
+		if widths[i] < 0 {
+			widths[i] = 0
+		}
+		x += widths[i] + t.opts.columnGap
+	}
+	return widths
+}
+
+// Draw draws the Table widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (t *Table) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	needAr, err := area.FromSize(t.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	t.lastWidths = t.columnWidths(ar.Dx())
+	t.lastRowsAr = image.Rect(ar.Min.X, ar.Min.Y+1, ar.Max.X, ar.Max.Y)
+	if err := t.drawHeader(cvs, image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+1)); err != nil {
+		return err
+	}
+
+	visible := t.lastRowsAr.Dy()
+	if pos := t.position(); pos >= 0 {
+		if pos < t.offset {
+			t.offset = pos
+		}
+		if pos >= t.offset+visible {
+			t.offset = pos - visible + 1
+		}
+	}
+	if max := len(t.order) - visible; t.offset > max {
+		t.offset = max
+	}
+	if t.offset < 0 {
+		t.offset = 0
+	}
+
+	for y := 0; y < visible && t.offset+y < len(t.order); y++ {
+		row := t.order[t.offset+y]
+		rowAr := image.Rect(ar.Min.X, t.lastRowsAr.Min.Y+y, ar.Max.X, t.lastRowsAr.Min.Y+y+1)
+		if err := t.drawRow(cvs, row, rowAr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawHeader draws the header row into the area.
+func (t *Table) drawHeader(cvs *canvas.Canvas, headerAr image.Rectangle) error {
+	x := headerAr.Min.X
+	for i, c := range t.opts.columns {
+		header := c.Header
+		if i == t.sortCol {
+			r := t.opts.sortAsc
+			if t.sortDesc {
+				r = t.opts.sortDesc
+			}
+			if header == "" {
+				header = string(r)
+			} else {
+				header = fmt.Sprintf("%s %c", header, r)
+			}
+		}
+		colAr := image.Rect(x, headerAr.Min.Y, x+t.lastWidths[i], headerAr.Max.Y)
+		if err := drawText(cvs, header, colAr, c.Align, t.opts.headerCellOpts); err != nil {
+			return err
+		}
+		x += t.lastWidths[i] + t.opts.columnGap
+	}
+	return nil
+}
+
+// drawRow draws the row with the specified index into the area.
+func (t *Table) drawRow(cvs *canvas.Canvas, row int, rowAr image.Rectangle) error {
+	selected := row == t.selected
+	if selected {
+		if err := draw.Rectangle(cvs, rowAr,
+			draw.RectChar(' '),
+			draw.RectCellOpts(t.opts.selectedCellOpts...),
+		); err != nil {
+			return err
+		}
+	}
+
+	x := rowAr.Min.X
+	for i, c := range t.rows[row] {
+		cellOpts := c.CellOpts
+		if selected {
+			cellOpts = append(append([]cell.Option{}, c.CellOpts...), t.opts.selectedCellOpts...)
+		}
+		colAr := image.Rect(x, rowAr.Min.Y, x+t.lastWidths[i], rowAr.Max.Y)
+		if err := drawText(cvs, c.Text, colAr, t.opts.columns[i].Align, cellOpts); err != nil {
+			return err
+		}
+		x += t.lastWidths[i] + t.opts.columnGap
+	}
+	return nil
+}
+
+// drawText draws the text aligned within the area, truncating it if it
+// doesn't fit.
+func drawText(cvs *canvas.Canvas, text string, ar image.Rectangle, h align.Horizontal, cellOpts []cell.Option) error {
+	if text == "" || ar.Dx() < 1 {
+		return nil
+	}
+	truncated, err := draw.Truncate(text, ar.Dx())
+	if err != nil {
+		return err
+	}
+	start, err := alignfor.Text(ar, truncated, h, align.VerticalTop)
+	if err != nil {
+		return err
+	}
+	return draw.Text(cvs, truncated, start, draw.TextMaxX(ar.Max.X), draw.TextCellOpts(cellOpts...))
+}
+
+// minSize determines the minimum required size to draw the widget, i.e. the
+// header and one row.
+func (t *Table) minSize() image.Point {
+	return image.Point{1, 2}
+}
+
+// move moves the selection by the specified number of rows in the displayed
+// order. Selects the first row if no row is selected. Returns true if the
+// selection changed.
+// The caller must hold t.mu.
+func (t *Table) move(by int) bool {
+	if len(t.order) == 0 {
+		return false
+	}
+	pos := t.position()
+	if pos < 0 {
+		t.selected = t.order[0]
+		return true
+	}
+
+	next := pos + by
+	if next < 0 {
+		next = 0
+	}
+	if max := len(t.order) - 1; next > max {
+		next = max
+	}
+	if next == pos {
+		return false
+	}
+	t.selected = t.order[next]
+	return true
+}
+
+// pageRows returns the number of rows to move by when paging.
+// The caller must hold t.mu.
+func (t *Table) pageRows() int {
+	if rows := t.lastRowsAr.Dy(); rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+// Keyboard moves the selected row.
+// Implements widgetapi.Widget.Keyboard.
+func (t *Table) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	if changed, row := t.keyboard(k); changed {
+		// Mutex must be released when calling the callback so that it can
+		// access the Table.
+		return t.opts.onSelect(row)
+	}
+	return nil
+}
+
+// keyboard processes the keyboard event. Returns true if the selected row
+// changed and the SelectFn should be called.
+func (t *Table) keyboard(k *terminalapi.Keyboard) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed bool
+	switch k.Key {
+	case t.opts.keyUp:
+		changed = t.move(-1)
+	case t.opts.keyDown:
+		changed = t.move(1)
+	case t.opts.keyPgUp:
+		changed = t.move(-t.pageRows())
+	case t.opts.keyPgDown:
+		changed = t.move(t.pageRows())
+	}
+	return changed && t.opts.onSelect != nil, t.selected
+}
+
+// Mouse sorts the rows on a click on the header, selects the row on a click
+// on a row and moves the selected row with the navigation buttons.
+// Implements widgetapi.Widget.Mouse.
+func (t *Table) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	if changed, row := t.mouse(m); changed {
+		// Mutex must be released when calling the callback so that it can
+		// access the Table.
+		return t.opts.onSelect(row)
+	}
+	return nil
+}
+
+// mouse processes the mouse event. Returns true if the selected row changed
+// and the SelectFn should be called.
+func (t *Table) mouse(m *terminalapi.Mouse) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed bool
+	switch m.Button {
+	case t.opts.mouseUpButton:
+		changed = t.move(-1)
+	case t.opts.mouseDownButton:
+		changed = t.move(1)
+	case mouse.ButtonLeft:
+		switch {
+		case m.Position.Y == t.lastRowsAr.Min.Y-1:
+			if col := t.columnAt(m.Position.X); col >= 0 {
+				t.sortDesc = col == t.sortCol && !t.sortDesc
+				t.sortCol = col
+				t.sort()
+			}
+		case m.Position.In(t.lastRowsAr):
+			pos := t.offset + m.Position.Y - t.lastRowsAr.Min.Y
+			if pos < len(t.order) && t.order[pos] != t.selected {
+				t.selected = t.order[pos]
+				changed = true
+			}
+		}
+	}
+	return changed && t.opts.onSelect != nil, t.selected
+}
+
+// columnAt returns the index of the column at the X coordinate or -1 if the
+// coordinate doesn't fall onto any of the columns.
+// The caller must hold t.mu.
+func (t *Table) columnAt(x int) int {
+	start := t.lastRowsAr.Min.X
+	for i, w := range t.lastWidths {
+		if x >= start && x < start+w {
+			return i
+		}
+		start += w + t.opts.columnGap
+	}
+	return -1
+}
+
+// Options implements widgetapi.Widget.Options.
+func (t *Table) Options() widgetapi.Options {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  t.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (t *Table) SetDirtyFunc(markDirty func()) {
+	t.dirty.Set(markDirty)
+}
@@ -0,0 +1,594 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// keyEv returns a keyboard event for the key.
+func keyEv(k keyboard.Key) *terminalapi.Keyboard {
+	return &terminalapi.Keyboard{Key: k}
+}
+
+// clickEv returns a left click mouse event at the position.
+func clickEv(x, y int) *terminalapi.Mouse {
+	return &terminalapi.Mouse{Position: image.Point{x, y}, Button: mouse.ButtonLeft}
+}
+
+// fruitRows returns rows used in the tests.
+func fruitRows() [][]Cell {
+	return [][]Cell{
+		{NewCell("pear"), NewCell("10")},
+		{NewCell("apple"), NewCell("9")},
+		{NewCell("fig"), NewCell("100")},
+	}
+}
+
+// nameRows returns single column rows used in the tests.
+func nameRows() [][]Cell {
+	return [][]Cell{
+		{NewCell("pear")},
+		{NewCell("apple")},
+		{NewCell("fig")},
+	}
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		rows   [][]Cell
+		// update is called after the rows are set.
+		update func(*Table) error
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events        func(*Table) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool
+		wantDrawErr   bool
+	}{
+		{
+			desc:       "fails without columns",
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a header with a newline",
+			opts:       []Option{Columns(Column{Header: "a\nb"})},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a negative width",
+			opts:       []Option{Columns(Column{Width: -1})},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on both width and percentage",
+			opts:       []Option{Columns(Column{Width: 1, WidthPercent: 1})},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails when percentages exceed a hundred",
+			opts:       []Option{Columns(Column{WidthPercent: 60}, Column{WidthPercent: 50})},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a negative column gap",
+			opts:       []Option{Columns(Column{}), ColumnGap(-1)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "fails on duplicate navigation keys",
+			opts: []Option{
+				Columns(Column{}),
+				NavigationKeys(keyboard.KeyArrowUp, keyboard.KeyArrowUp, keyboard.KeyPgUp, keyboard.KeyPgDn),
+			},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "fails on duplicate navigation mouse buttons",
+			opts: []Option{
+				Columns(Column{}),
+				NavigationMouseButtons(mouse.ButtonWheelUp, mouse.ButtonWheelUp),
+			},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:          "fails on a row with too many cells",
+			opts:          []Option{Columns(Column{})},
+			canvas:        image.Rect(0, 0, 10, 3),
+			rows:          [][]Cell{{NewCell("a"), NewCell("b")}},
+			wantUpdateErr: true,
+		},
+		{
+			desc:          "fails on a cell with a newline",
+			opts:          []Option{Columns(Column{})},
+			canvas:        image.Rect(0, 0, 10, 3),
+			rows:          [][]Cell{{NewCell("a\nb")}},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails to sort by a column that doesn't exist",
+			opts:   []Option{Columns(Column{})},
+			canvas: image.Rect(0, 0, 10, 3),
+			update: func(tbl *Table) error {
+				return tbl.SortBy(1, false)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "fails to select a row that doesn't exist",
+			opts:   []Option{Columns(Column{})},
+			canvas: image.Rect(0, 0, 10, 3),
+			update: func(tbl *Table) error {
+				return tbl.Select(0)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws resize needed character when canvas is too small",
+			opts:   []Option{Columns(Column{})},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the header and the rows",
+			opts: []Option{
+				Columns(
+					Column{Header: "Name", Width: 5},
+					Column{Header: "Qty", Align: align.HorizontalRight},
+				),
+			},
+			canvas: image.Rect(0, 0, 10, 4),
+			rows:   fruitRows(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "Name", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "Qty", image.Point{7, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "pear", image.Point{0, 1})
+				testdraw.MustText(c, "10", image.Point{8, 1})
+				testdraw.MustText(c, "apple", image.Point{0, 2})
+				testdraw.MustText(c, "9", image.Point{9, 2})
+				testdraw.MustText(c, "fig", image.Point{0, 3})
+				testdraw.MustText(c, "100", image.Point{7, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "truncates text that doesn't fit and uses the cell options",
+			opts: []Option{
+				Columns(
+					Column{WidthPercent: 50},
+					Column{WidthPercent: 50},
+				),
+				ColumnGap(0),
+				HeaderCellOpts(cell.FgColor(cell.ColorRed)),
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			rows: [][]Cell{
+				{NewCell("abcd", cell.FgColor(cell.ColorBlue)), NewCell("xy")},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "ab…", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testdraw.MustText(c, "xy", image.Point{3, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "sorts rows and marks the sorted column",
+			opts: []Option{
+				Columns(
+					Column{Header: "Name", Width: 5},
+					Column{Header: "Qty"},
+				),
+			},
+			canvas: image.Rect(0, 0, 11, 4),
+			rows:   fruitRows(),
+			update: func(tbl *Table) error {
+				return tbl.SortBy(1, false)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "Name", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "Qty ▲", image.Point{6, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "apple", image.Point{0, 1})
+				testdraw.MustText(c, "9", image.Point{6, 1})
+				testdraw.MustText(c, "pear", image.Point{0, 2})
+				testdraw.MustText(c, "10", image.Point{6, 2})
+				testdraw.MustText(c, "fig", image.Point{0, 3})
+				testdraw.MustText(c, "100", image.Point{6, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "click on the header sorts and a second click reverses the order",
+			opts: []Option{
+				Columns(
+					Column{Header: "Name", Width: 5},
+					Column{Header: "Qty"},
+				),
+			},
+			canvas: image.Rect(0, 0, 11, 4),
+			rows:   fruitRows(),
+			events: func(tbl *Table) error {
+				if err := tbl.Mouse(clickEv(1, 0), &widgetapi.EventMeta{}); err != nil {
+					return err
+				}
+				return tbl.Mouse(clickEv(1, 0), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "Name…", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "Qty", image.Point{6, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "pear", image.Point{0, 1})
+				testdraw.MustText(c, "10", image.Point{6, 1})
+				testdraw.MustText(c, "fig", image.Point{0, 2})
+				testdraw.MustText(c, "100", image.Point{6, 2})
+				testdraw.MustText(c, "apple", image.Point{0, 3})
+				testdraw.MustText(c, "9", image.Point{6, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom comparison and sort runes",
+			opts: []Option{
+				Columns(
+					Column{Header: "N", Width: 5, Less: func(a, b string) bool {
+						return len(a) < len(b)
+					}},
+				),
+				SortRunes('+', '-'),
+			},
+			canvas: image.Rect(0, 0, 5, 4),
+			rows:   nameRows(),
+			update: func(tbl *Table) error {
+				return tbl.SortBy(0, true)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "N -", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "apple", image.Point{0, 1})
+				testdraw.MustText(c, "pear", image.Point{0, 2})
+				testdraw.MustText(c, "fig", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "keyboard selects rows and scrolls to keep the selection visible",
+			opts: []Option{
+				Columns(Column{Width: 5}),
+			},
+			canvas: image.Rect(0, 0, 5, 3),
+			rows:   nameRows(),
+			events: func(tbl *Table) error {
+				for _, k := range []keyboard.Key{keyboard.KeyArrowDown, keyboard.KeyPgDn} {
+					if err := tbl.Keyboard(keyEv(k), &widgetapi.EventMeta{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "apple", image.Point{0, 1})
+				testdraw.MustRectangle(c, image.Rect(0, 2, 5, 3), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "fig", image.Point{0, 2}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "click selects a row",
+			opts: []Option{
+				Columns(Column{Width: 5}),
+				SelectedCellOpts(cell.BgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 6, 4),
+			rows: [][]Cell{
+				{NewCell("pear")},
+				{NewCell("apple", cell.FgColor(cell.ColorRed))},
+			},
+			events: func(tbl *Table) error {
+				return tbl.Mouse(clickEv(0, 2), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "pear", image.Point{0, 1})
+				testdraw.MustRectangle(c, image.Rect(0, 2, 6, 3), draw.RectChar(' '), draw.RectCellOpts(cell.BgColor(cell.ColorBlue)))
+				testdraw.MustText(c, "apple", image.Point{0, 2}, draw.TextCellOpts(cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "selection follows the row when sorted",
+			opts: []Option{
+				Columns(Column{Width: 5}),
+			},
+			canvas: image.Rect(0, 0, 5, 4),
+			rows:   nameRows(),
+			update: func(tbl *Table) error {
+				if err := tbl.Select(0); err != nil {
+					return err
+				}
+				return tbl.SortBy(0, false)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▲", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+				testdraw.MustText(c, "apple", image.Point{0, 1})
+				testdraw.MustText(c, "fig", image.Point{0, 2})
+				testdraw.MustRectangle(c, image.Rect(0, 3, 5, 4), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "pear", image.Point{0, 3}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tbl, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			err = tbl.SetRows(tc.rows)
+			if err == nil && tc.update != nil {
+				err = tc.update(tbl)
+			}
+			if (err != nil) != tc.wantUpdateErr {
+				t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if tc.events != nil {
+				if err := tbl.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				if err := tc.events(tbl); err != nil {
+					t.Fatalf("events => unexpected error: %v", err)
+				}
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+			}
+
+			err = tbl.Draw(c, &widgetapi.Meta{})
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestColumnWidths(t *testing.T) {
+	tests := []struct {
+		desc  string
+		cols  []Column
+		gap   int
+		width int
+		want  []int
+	}{
+		{
+			desc:  "columns without widths share the width",
+			cols:  []Column{{}, {}, {}},
+			gap:   1,
+			width: 12,
+			want:  []int{4, 3, 3},
+		},
+		{
+			desc:  "fixed, percentage and remaining widths",
+			cols:  []Column{{Width: 3}, {WidthPercent: 50}, {}},
+			gap:   1,
+			width: 12,
+			want:  []int{3, 5, 2},
+		},
+		{
+			desc:  "columns that don't fit are narrowed",
+			cols:  []Column{{Width: 4}, {Width: 4}, {Width: 4}},
+			gap:   1,
+			width: 7,
+			want:  []int{4, 2, 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tbl, err := New(Columns(tc.cols...), ColumnGap(tc.gap))
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			got := tbl.columnWidths(tc.width)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("columnWidths => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOnSelect(t *testing.T) {
+	var got []int
+	tbl, err := New(
+		Columns(Column{}),
+		OnSelect(func(row int) error {
+			got = append(got, row)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := tbl.SetRows(nameRows()); err != nil {
+		t.Fatalf("SetRows => unexpected error: %v", err)
+	}
+	if err := tbl.SortBy(0, false); err != nil {
+		t.Fatalf("SortBy => unexpected error: %v", err)
+	}
+	if err := tbl.Draw(testcanvas.MustNew(image.Rect(0, 0, 5, 4)), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	events := []func() error{
+		func() error { return tbl.Keyboard(keyEv(keyboard.KeyArrowDown), &widgetapi.EventMeta{}) },
+		func() error { return tbl.Keyboard(keyEv(keyboard.KeyArrowDown), &widgetapi.EventMeta{}) },
+		// Doesn't change the selection.
+		func() error { return tbl.Mouse(clickEv(0, 2), &widgetapi.EventMeta{}) },
+		func() error {
+			return tbl.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelDown}, &widgetapi.EventMeta{})
+		},
+		// Already on the last row.
+		func() error { return tbl.Keyboard(keyEv(keyboard.KeyPgDn), &widgetapi.EventMeta{}) },
+		func() error { return tbl.Mouse(clickEv(0, 1), &widgetapi.EventMeta{}) },
+	}
+	for i, ev := range events {
+		if err := ev(); err != nil {
+			t.Fatalf("event %d => unexpected error: %v", i, err)
+		}
+	}
+
+	// Sorted order is apple(1), fig(2), pear(0).
+	want := []int{1, 2, 0, 1}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("OnSelect => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if row, ok := tbl.Selected(); !ok || row != 1 {
+		t.Errorf("Selected => %d, %v, want 1, true", row, ok)
+	}
+}
+
+func TestReset(t *testing.T) {
+	tbl, err := New(Columns(Column{Header: "N"}))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := tbl.AddRow(NewCell("a")); err != nil {
+		t.Fatalf("AddRow => unexpected error: %v", err)
+	}
+	if err := tbl.SortBy(0, false); err != nil {
+		t.Fatalf("SortBy => unexpected error: %v", err)
+	}
+	tbl.Reset()
+
+	c := testcanvas.MustNew(image.Rect(0, 0, 5, 2))
+	if err := tbl.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got := faketerm.MustNew(c.Size())
+	testcanvas.MustApply(c, got)
+
+	want := faketerm.MustNew(c.Size())
+	wc := testcanvas.MustNew(want.Area())
+	testdraw.MustText(wc, "N", image.Point{0, 0}, draw.TextCellOpts(cell.Bold()))
+	testcanvas.MustApply(wc, want)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+	if _, ok := tbl.Selected(); ok {
+		t.Errorf("Selected => true after Reset, want false")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tbl, err := New(Columns(Column{}))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := tbl.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 2},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary tabledemo displays a Table widget with a list of processes.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/table"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// process is one row in the table.
+type process struct {
+	pid  int
+	name string
+	cpu  float64
+}
+
+// rows returns the table rows for the processes.
+func rows(procs []*process) [][]table.Cell {
+	var res [][]table.Cell
+	for _, p := range procs {
+		cpu := table.NewCell(fmt.Sprintf("%.1f", p.cpu))
+		if p.cpu > 50 {
+			cpu.CellOpts = []cell.Option{cell.FgColor(cell.ColorRed)}
+		}
+		res = append(res, []table.Cell{
+			table.NewCell(fmt.Sprint(p.pid)),
+			table.NewCell(p.name),
+			cpu,
+		})
+	}
+	return res
+}
+
+// playTable periodically updates the CPU usage of the processes.
+// Exits when the context expires.
+func playTable(ctx context.Context, tbl *table.Table, procs []*process, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, p := range procs {
+				p.cpu = rand.Float64() * 100
+			}
+			if err := tbl.SetRows(rows(procs)); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	procs := []*process{
+		{pid: 1, name: "init"},
+		{pid: 112, name: "sshd"},
+		{pid: 240, name: "postgres"},
+		{pid: 251, name: "nginx"},
+		{pid: 1024, name: "termdash"},
+		{pid: 2048, name: "go"},
+	}
+
+	selected, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	tbl, err := table.New(
+		table.Columns(
+			table.Column{Header: "PID", Width: 6, Align: align.HorizontalRight},
+			table.Column{Header: "Name"},
+			table.Column{Header: "CPU %", WidthPercent: 30, Align: align.HorizontalRight},
+		),
+		table.OnSelect(func(row int) error {
+			return selected.Write(fmt.Sprintf("Selected %s", procs[row].name), text.WriteReplace())
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := tbl.SetRows(rows(procs)); err != nil {
+		panic(err)
+	}
+	go playTable(ctx, tbl, procs, 2*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.PlaceWidget(tbl),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Click a header to sort, use arrows to select"),
+				container.PlaceWidget(selected),
+			),
+			container.SplitPercent(80),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
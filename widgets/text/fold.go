@@ -0,0 +1,160 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+// fold.go contains code that folds (collapses) sections of the text.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/private/canvas/buffer"
+)
+
+// fold is a foldable section of the text.
+// The section starts with a header line which remains visible when the fold is
+// collapsed and is followed by the lines up to and including the last line.
+type fold struct {
+	// last is the number of the last line that belongs to the fold.
+	last int
+	// collapsed indicates if the lines following the header are hidden.
+	collapsed bool
+}
+
+// AddFold defines a foldable section of the text. The header line is always
+// displayed and is prefixed with a marker that indicates the state of the
+// fold, the lines following it up to and including the last line are hidden
+// when the fold is collapsed. The fold starts expanded.
+//
+// Lines are numbered from zero and refer to the lines of the text content,
+// i.e. the text separated by newline characters, before any wrapping. The
+// lines don't need to exist yet, so folds can be defined ahead of writing the
+// text. Folds can be nested, but they cannot partially overlap. All folds are
+// removed when the content is reset.
+//
+// The user can toggle a fold by clicking on its header line or by pressing the
+// key configured by FoldKey, which toggles the first fold whose header line is
+// visible.
+func (t *Text) AddFold(header, last int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+
+	if header < 0 {
+		return fmt.Errorf("invalid fold header line %d, must be a zero or a positive number", header)
+	}
+	if last <= header {
+		return fmt.Errorf("invalid fold last line %d, must be greater than the header line %d", last, header)
+	}
+	if _, ok := t.folds[header]; ok {
+		return fmt.Errorf("a fold with header line %d already exists", header)
+	}
+	for h, f := range t.folds {
+		if (h < header && header <= f.last && f.last < last) || (header < h && h <= last && last < f.last) {
+			return fmt.Errorf("fold of lines %d-%d partially overlaps with the existing fold of lines %d-%d", header, last, h, f.last)
+		}
+	}
+
+	if t.folds == nil {
+		t.folds = map[int]*fold{}
+	}
+	t.folds[header] = &fold{last: last}
+	t.contentChanged = true
+	return nil
+}
+
+// SetFolded collapses (folded is true) or expands (folded is false) the fold
+// with the specified header line.
+func (t *Text) SetFolded(header int, folded bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+
+	f, ok := t.folds[header]
+	if !ok {
+		return fmt.Errorf("no fold with header line %d", header)
+	}
+	f.collapsed = folded
+	t.contentChanged = true
+	return nil
+}
+
+// ToggleFold collapses the fold with the specified header line if it is
+// expanded or expands it if it is collapsed.
+func (t *Text) ToggleFold(header int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+
+	if !t.toggleFold(header) {
+		return fmt.Errorf("no fold with header line %d", header)
+	}
+	return nil
+}
+
+// toggleFold toggles the fold with the specified header line.
+// Returns false if there is no such fold. Caller must hold t.mu.
+func (t *Text) toggleFold(header int) bool {
+	f, ok := t.folds[header]
+	if !ok {
+		return false
+	}
+	f.collapsed = !f.collapsed
+	t.contentChanged = true
+	return true
+}
+
+// hidden asserts whether the specified line is hidden by a collapsed fold.
+func (t *Text) hidden(line int) bool {
+	for h, f := range t.folds {
+		if f.collapsed && h < line && line <= f.last {
+			return true
+		}
+	}
+	return false
+}
+
+// foldMarker returns the cells that prefix the header line of the fold.
+func (t *Text) foldMarker(f *fold) []*buffer.Cell {
+	r := t.opts.foldExpanded
+	if f.collapsed {
+		r = t.opts.foldCollapsed
+	}
+	return []*buffer.Cell{buffer.NewCell(r), buffer.NewCell(' ')}
+}
+
+// contentLines splits the content into lines at the newline characters.
+// The returned lines don't include the newline characters.
+func (t *Text) contentLines() [][]*buffer.Cell {
+	var lines [][]*buffer.Cell
+	start := 0
+	for i, c := range t.content {
+		if c.Rune == '\n' {
+			lines = append(lines, t.content[start:i])
+			start = i + 1
+		}
+	}
+	return append(lines, t.content[start:])
+}
+
+// firstVisibleFold returns the header line of the first fold whose header is
+// visible on the canvas. Returns false if there isn't any.
+func (t *Text) firstVisibleFold() (int, bool) {
+	for _, line := range t.rows {
+		if _, ok := t.folds[line]; ok {
+			return line, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,386 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestAddFold(t *testing.T) {
+	tests := []struct {
+		desc    string
+		folds   [][2]int
+		header  int
+		last    int
+		wantErr bool
+	}{
+		{
+			desc:    "fails on negative header",
+			header:  -1,
+			last:    1,
+			wantErr: true,
+		},
+		{
+			desc:    "fails when last isn't after header",
+			header:  1,
+			last:    1,
+			wantErr: true,
+		},
+		{
+			desc:    "fails on duplicate header",
+			folds:   [][2]int{{1, 2}},
+			header:  1,
+			last:    3,
+			wantErr: true,
+		},
+		{
+			desc:    "fails on partial overlap at the end",
+			folds:   [][2]int{{1, 3}},
+			header:  2,
+			last:    4,
+			wantErr: true,
+		},
+		{
+			desc:    "fails on partial overlap at the start",
+			folds:   [][2]int{{2, 4}},
+			header:  1,
+			last:    3,
+			wantErr: true,
+		},
+		{
+			desc:   "accepts a nested fold",
+			folds:  [][2]int{{1, 5}},
+			header: 2,
+			last:   4,
+		},
+		{
+			desc:   "accepts an enclosing fold",
+			folds:  [][2]int{{2, 4}},
+			header: 1,
+			last:   5,
+		},
+		{
+			desc:   "accepts adjacent folds",
+			folds:  [][2]int{{1, 2}},
+			header: 3,
+			last:   4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			widget, err := New()
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			for _, f := range tc.folds {
+				if err := widget.AddFold(f[0], f[1]); err != nil {
+					t.Fatalf("AddFold => unexpected error: %v", err)
+				}
+			}
+
+			err = widget.AddFold(tc.header, tc.last)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("AddFold => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFolds(t *testing.T) {
+	tests := []struct {
+		desc   string
+		canvas image.Rectangle
+		opts   []Option
+		text   string
+		setup  func(*Text) error
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events  func(*Text)
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc:   "draws an expanded fold",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				return widget.AddFold(0, 2)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ a", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testdraw.MustText(c, "c", image.Point{0, 2})
+				testdraw.MustText(c, "d", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "hides lines of a collapsed fold",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 2); err != nil {
+					return err
+				}
+				return widget.SetFolded(0, true)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▸ a", image.Point{0, 0})
+				testdraw.MustText(c, "d", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "collapsed outer fold hides nested folds",
+			canvas: image.Rect(0, 0, 10, 4),
+			opts: []Option{
+				FoldMarkers('-', '+'),
+			},
+			text: "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 2); err != nil {
+					return err
+				}
+				if err := widget.AddFold(1, 2); err != nil {
+					return err
+				}
+				return widget.ToggleFold(0)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "+ a", image.Point{0, 0})
+				testdraw.MustText(c, "d", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "fails to toggle a fold that doesn't exist",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb",
+			setup: func(widget *Text) error {
+				return widget.ToggleFold(0)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails to set a fold that doesn't exist",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb",
+			setup: func(widget *Text) error {
+				return widget.SetFolded(0, true)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "scrolling skips over collapsed lines",
+			canvas: image.Rect(0, 0, 10, 2),
+			text:   "a\nb\nc\nd\ne",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(1, 3); err != nil {
+					return err
+				}
+				return widget.SetFolded(1, true)
+			},
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{
+					Key: keyboard.KeyArrowDown,
+				}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▸ b", image.Point{0, 0})
+				testdraw.MustText(c, "e", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "mouse click on the header line toggles the fold",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				return widget.AddFold(1, 2)
+			},
+			events: func(widget *Text) {
+				widget.Mouse(&terminalapi.Mouse{
+					Position: image.Point{3, 1},
+					Button:   mouse.ButtonLeft,
+				}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "▸ b", image.Point{0, 1})
+				testdraw.MustText(c, "d", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "mouse click on other lines doesn't toggle the fold",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				return widget.AddFold(1, 2)
+			},
+			events: func(widget *Text) {
+				widget.Mouse(&terminalapi.Mouse{
+					Position: image.Point{0, 2},
+					Button:   mouse.ButtonLeft,
+				}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "▾ b", image.Point{0, 1})
+				testdraw.MustText(c, "c", image.Point{0, 2})
+				testdraw.MustText(c, "d", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "fold key toggles the first visible fold",
+			canvas: image.Rect(0, 0, 10, 4),
+			opts: []Option{
+				FoldKey('f'),
+			},
+			text: "a\nb\nc\nd\ne",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(1, 2); err != nil {
+					return err
+				}
+				return widget.AddFold(3, 4)
+			},
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{
+					Key: 'f',
+				}, &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "▸ b", image.Point{0, 1})
+				testdraw.MustText(c, "▾ d", image.Point{0, 2})
+				testdraw.MustText(c, "e", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "reset removes the folds",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 1); err != nil {
+					return err
+				}
+				widget.Reset()
+				return widget.Write("a\nb")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			widget, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if err := widget.Write(tc.text); err != nil {
+				t.Fatalf("Write => unexpected error: %v", err)
+			}
+
+			if tc.setup != nil {
+				err := tc.setup(widget)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("setup => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if tc.events != nil {
+				if err := widget.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				tc.events(widget)
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+			}
+
+			if err := widget.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
@@ -35,6 +35,8 @@ type options struct {
 	scrollUp         rune
 	scrollDown       rune
 	wrapMode         wrap.Mode
+	wrapIndent       int
+	wrapIndentAlign  bool
 	rollContent      bool
 	maxTextCells     int
 	disableScrolling bool
@@ -44,6 +46,10 @@ type options struct {
 	keyDown          keyboard.Key
 	keyPgUp          keyboard.Key
 	keyPgDown        keyboard.Key
+	keyFold          keyboard.Key
+	mouseFoldButton  mouse.Button
+	foldExpanded     rune
+	foldCollapsed    rune
 }
 
 // newOptions returns a new options instance.
@@ -58,6 +64,10 @@ func newOptions(opts ...Option) *options {
 		keyPgUp:         DefaultScrollKeyPageUp,
 		keyPgDown:       DefaultScrollKeyPageDown,
 		maxTextCells:    DefaultMaxTextCells,
+		keyFold:         DefaultFoldKey,
+		mouseFoldButton: DefaultFoldMouseButton,
+		foldExpanded:    DefaultFoldExpandedRune,
+		foldCollapsed:   DefaultFoldCollapsedRune,
 	}
 	for _, o := range opts {
 		o.set(opt)
@@ -82,6 +92,9 @@ func (o *options) validate() error {
 	if o.maxTextCells < 0 {
 		return fmt.Errorf("invalid MaxTextCells(%d), must be zero or a positive integer", o.maxTextCells)
 	}
+	if o.wrapIndent < 0 {
+		return fmt.Errorf("invalid WrapHangingIndent(%d), must be zero or a positive integer", o.wrapIndent)
+	}
 	return nil
 }
 
@@ -127,6 +140,31 @@ func WrapAtRunes() Option {
 	})
 }
 
+// WrapHangingIndent indents the continuation lines created when wrapping long
+// lines by the specified number of cells, which makes them easier to tell
+// apart from new lines. Lines that start after a newline character aren't
+// indented. Only takes effect together with WrapAtWords or WrapAtRunes.
+// The value must be zero or a positive integer, defaults to zero.
+//
+// The widget neither displays line numbers nor expands tabs (Write rejects
+// them), so the indent is always counted from the first cell of the canvas.
+func WrapHangingIndent(cells int) Option {
+	return option(func(opts *options) {
+		opts.wrapIndent = cells
+	})
+}
+
+// WrapHangingIndentAligned aligns the continuation lines created when wrapping
+// long lines with the first non-space character of the original line. When
+// combined with WrapHangingIndent, the continuation lines are indented further
+// by the specified number of cells. Only takes effect together with
+// WrapAtWords or WrapAtRunes.
+func WrapHangingIndentAligned() Option {
+	return option(func(opts *options) {
+		opts.wrapIndentAlign = true
+	})
+}
+
 // RollContent configures the text widget so that it rolls the text content up
 // if more text than the size of the container is added. If not provided, the
 // content is trimmed instead.
@@ -199,3 +237,45 @@ func MaxTextCells(max int) Option {
 		opts.maxTextCells = max
 	})
 }
+
+// The default runes that mark the header lines of folds.
+const (
+	DefaultFoldExpandedRune  = '▾'
+	DefaultFoldCollapsedRune = '▸'
+)
+
+// FoldMarkers configures the runes that prefix the header lines of folds
+// added via AddFold. The expanded rune is shown when the fold is expanded and
+// the collapsed rune when it is collapsed. If not provided, the default fold
+// runes will be used.
+func FoldMarkers(expanded, collapsed rune) Option {
+	return option(func(opts *options) {
+		opts.foldExpanded = expanded
+		opts.foldCollapsed = collapsed
+	})
+}
+
+// The default key and mouse button that toggle folds.
+const (
+	DefaultFoldKey         = keyboard.KeyEnter
+	DefaultFoldMouseButton = mouse.ButtonLeft
+)
+
+// FoldKey configures the keyboard key that toggles the first fold whose
+// header line is visible. The scroll keys take precedence if the same key is
+// used for both. Has no effect if the scrolling is disabled.
+func FoldKey(k keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyFold = k
+	})
+}
+
+// FoldMouseButton configures the mouse button that toggles a fold when the
+// user clicks on its header line. The scroll mouse buttons take precedence if
+// the same button is used for both. Has no effect if the scrolling is
+// disabled.
+func FoldMouseButton(b mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseFoldButton = b
+	})
+}
@@ -50,6 +50,9 @@ type scrollTracker struct {
 	// first tracks the first line that will be printed.
 	first int
 
+	// target stores a request to scroll to the line, -1 if there is none.
+	target int
+
 	// state is the state of the scrolling FSM.
 	state rollState
 }
@@ -57,9 +60,9 @@ type scrollTracker struct {
 // newScrollTracker returns a new scroll tracker.
 func newScrollTracker(opts *options) *scrollTracker {
 	if opts.rollContent {
-		return &scrollTracker{state: rollToEnd}
+		return &scrollTracker{target: -1, state: rollToEnd}
 	}
-	return &scrollTracker{state: rollingDisabled}
+	return &scrollTracker{target: -1, state: rollingDisabled}
 }
 
 // upOneLine processes a user request to scroll up by one line.
@@ -82,12 +85,26 @@ func (st *scrollTracker) downOnePage() {
 	st.scrollPage++
 }
 
+// toLine processes a request to scroll so that the line is visible.
+func (st *scrollTracker) toLine(line int) {
+	st.target = line
+}
+
 // doScroll processes any outstanding scroll requests and calculates the
 // resulting first line.
 func (st *scrollTracker) doScroll(lines, height int) int {
 	first := st.first + st.scroll + st.scrollPage*height
+	if st.target >= 0 {
+		first = st.target
+		if first > 0 && height > 1 {
+			// Start one line above, the scroll up marker replaces the first
+			// line.
+			first--
+		}
+	}
 	st.scroll = 0
 	st.scrollPage = 0
+	st.target = -1
 	return normalizeScroll(first, lines, height)
 }
 
@@ -115,7 +132,7 @@ func rollingDisabled(st *scrollTracker, lines, height int) rollState {
 func rollToEnd(st *scrollTracker, lines, height int) rollState {
 	// If the user didn't scroll, just roll the content so that the last line
 	// is visible.
-	if st.scroll == 0 && st.scrollPage == 0 {
+	if st.scroll == 0 && st.scrollPage == 0 && st.target < 0 {
 		st.first = normalizeScroll(math.MaxInt32, lines, height)
 		return rollToEnd
 	}
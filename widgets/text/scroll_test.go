@@ -145,6 +145,33 @@ func TestScrollTrackerNoContentRolling(t *testing.T) {
 			},
 			want: 3,
 		},
+		{
+			desc:   "scrolls to a line, keeping the line above it visible",
+			lines:  8,
+			height: 3,
+			events: func(st *scrollTracker) {
+				st.toLine(4)
+			},
+			want: 3,
+		},
+		{
+			desc:   "scrolls to a line on a canvas with a single line",
+			lines:  8,
+			height: 1,
+			events: func(st *scrollTracker) {
+				st.toLine(4)
+			},
+			want: 4,
+		},
+		{
+			desc:   "scroll to a line capped at the last line",
+			lines:  8,
+			height: 3,
+			events: func(st *scrollTracker) {
+				st.toLine(7)
+			},
+			want: 5,
+		},
 	}
 
 	for _, tc := range tests {
@@ -242,6 +269,21 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 			height: 2,
 			want:   3,
 		},
+		{
+			desc:   "scrolling to a line breaks away from the last line",
+			lines:  7,
+			height: 2,
+			events: func() {
+				st.toLine(2)
+			},
+			want: 1,
+		},
+		{
+			desc:   "keeps scrolled to the line when new content arrives",
+			lines:  7,
+			height: 2,
+			want:   1,
+		},
 		{
 			desc:   "resize so that the last line becomes visible",
 			lines:  7,
@@ -0,0 +1,90 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+// search.go contains code that searches the text.
+
+import (
+	"strings"
+
+	"github.com/mum4k/termdash/private/canvas/buffer"
+)
+
+// Search scrolls to the next line of the text content that contains the
+// query. Repeating the search with the same query moves to the following
+// matching line, a new query starts with the first line displayed on the
+// canvas. The search continues from the beginning of the content after it
+// reaches the end. The search is case sensitive.
+//
+// Lines hidden in collapsed folds are searched too, the folds that hide the
+// matching line get expanded so that it is displayed.
+//
+// Returns the number of the matching line of the content, i.e. the same
+// numbering as used by AddFold, or false if no line contains the query.
+func (t *Text) Search(query string) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if query == "" {
+		return 0, false
+	}
+
+	start := 0
+	if query == t.query && t.match >= 0 {
+		start = t.match + 1
+	} else {
+		for _, line := range t.rows {
+			if line >= 0 {
+				start = line
+				break
+			}
+		}
+	}
+	t.query = query
+
+	lines := t.contentLines()
+	for i := range lines {
+		line := (start + i) % len(lines)
+		if !strings.Contains(cellsText(lines[line]), query) {
+			continue
+		}
+		t.match = line
+		t.reveal(line)
+		t.scrollTo = line
+		t.dirty.Mark()
+		return line, true
+	}
+	t.match = -1
+	return 0, false
+}
+
+// reveal expands the collapsed folds that hide the specified line.
+func (t *Text) reveal(line int) {
+	for h, f := range t.folds {
+		if f.collapsed && h < line && line <= f.last {
+			f.collapsed = false
+			t.contentChanged = true
+		}
+	}
+}
+
+// cellsText returns the text in the cells.
+func cellsText(cells []*buffer.Cell) string {
+	var b strings.Builder
+	for _, c := range cells {
+		b.WriteRune(c.Rune)
+	}
+	return b.String()
+}
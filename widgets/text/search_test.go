@@ -0,0 +1,259 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestSearch(t *testing.T) {
+	// result is the result of a single search.
+	type result struct {
+		Line  int
+		Found bool
+	}
+
+	tests := []struct {
+		desc   string
+		canvas image.Rectangle
+		opts   []Option
+		text   string
+		setup  func(*Text) error
+		// queries are searched for one after another, the widget is drawn
+		// before each search.
+		queries []string
+		want    func(size image.Point) *faketerm.Terminal
+		// wantResults are the results of the searches.
+		wantResults []result
+	}{
+		{
+			desc:        "doesn't find an empty query",
+			canvas:      image.Rect(0, 0, 10, 2),
+			text:        "a\nb",
+			queries:     []string{""},
+			wantResults: []result{{0, false}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:        "doesn't find a query that no line contains",
+			canvas:      image.Rect(0, 0, 10, 2),
+			text:        "a\nb",
+			queries:     []string{"x"},
+			wantResults: []result{{0, false}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:        "scrolls to the matching line",
+			canvas:      image.Rect(0, 0, 10, 2),
+			text:        "a\nb\nc\nd\ne",
+			queries:     []string{"d"},
+			wantResults: []result{{3, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "c", image.Point{0, 0})
+				testdraw.MustText(c, "d", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:        "the scroll up marker doesn't hide the matching line",
+			canvas:      image.Rect(0, 0, 10, 3),
+			text:        "a\nb\nc\nd\ne\nf",
+			queries:     []string{"c"},
+			wantResults: []result{{2, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "c", image.Point{0, 1})
+				testdraw.MustText(c, "⇩", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:        "repeated search moves to the next match and wraps around",
+			canvas:      image.Rect(0, 0, 10, 2),
+			text:        "xa\nb\nxc\nd",
+			queries:     []string{"x", "x", "x"},
+			wantResults: []result{{0, true}, {2, true}, {0, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "xa", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "expands the collapsed fold that hides the matching line",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 2); err != nil {
+					return err
+				}
+				return widget.SetFolded(0, true)
+			},
+			queries:     []string{"c"},
+			wantResults: []result{{2, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ a", image.Point{0, 0})
+				testdraw.MustText(c, "b", image.Point{0, 1})
+				testdraw.MustText(c, "c", image.Point{0, 2})
+				testdraw.MustText(c, "d", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "expands nested collapsed folds that hide the matching line",
+			canvas: image.Rect(0, 0, 10, 5),
+			text:   "a\nb\nc\nd\ne",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 3); err != nil {
+					return err
+				}
+				if err := widget.AddFold(1, 3); err != nil {
+					return err
+				}
+				if err := widget.SetFolded(1, true); err != nil {
+					return err
+				}
+				return widget.SetFolded(0, true)
+			},
+			queries:     []string{"c"},
+			wantResults: []result{{2, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ a", image.Point{0, 0})
+				testdraw.MustText(c, "▾ b", image.Point{0, 1})
+				testdraw.MustText(c, "c", image.Point{0, 2})
+				testdraw.MustText(c, "d", image.Point{0, 3})
+				testdraw.MustText(c, "e", image.Point{0, 4})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "keeps folds that don't hide the matching line collapsed",
+			canvas: image.Rect(0, 0, 10, 4),
+			text:   "a\nb\nc\nd\ne",
+			setup: func(widget *Text) error {
+				if err := widget.AddFold(0, 1); err != nil {
+					return err
+				}
+				return widget.SetFolded(0, true)
+			},
+			queries:     []string{"d"},
+			wantResults: []result{{3, true}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▸ a", image.Point{0, 0})
+				testdraw.MustText(c, "c", image.Point{0, 1})
+				testdraw.MustText(c, "d", image.Point{0, 2})
+				testdraw.MustText(c, "e", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			widget, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if err := widget.Write(tc.text); err != nil {
+				t.Fatalf("Write => unexpected error: %v", err)
+			}
+			if tc.setup != nil {
+				if err := tc.setup(widget); err != nil {
+					t.Fatalf("setup => unexpected error: %v", err)
+				}
+			}
+
+			var c *canvas.Canvas
+			var gotResults []result
+			draw := func() {
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := widget.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+			for _, q := range tc.queries {
+				draw()
+				line, found := widget.Search(q)
+				gotResults = append(gotResults, result{line, found})
+			}
+			draw()
+
+			if diff := pretty.Compare(tc.wantResults, gotResults); diff != "" {
+				t.Errorf("Search => unexpected diff (-want, +got):\n%s", diff)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
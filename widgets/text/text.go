@@ -21,8 +21,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/canvas/buffer"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/private/wrap"
 	"github.com/mum4k/termdash/terminal/terminalapi"
@@ -35,6 +37,11 @@ import (
 // options. The entire text content is either trimmed or rolled up through the
 // canvas according to the provided options.
 //
+// Lines are wrapped and trimmed at grapheme cluster boundaries. The cells of
+// the terminal hold a single rune, so only the first rune of each cluster is
+// displayed. Combining characters and the runes joined by the zero width
+// joiner are dropped, e.g. a decomposed "é" is displayed as "e".
+//
 // By default the widget supports scrolling of content with either the keyboard
 // or mouse. See the options for the default keys and mouse buttons.
 //
@@ -45,10 +52,29 @@ type Text struct {
 	content []*buffer.Cell
 	// wrapped is the content wrapped to the current width of the canvas.
 	wrapped [][]*buffer.Cell
+	// lineOf maps the wrapped lines to the lines of the content they were
+	// created from.
+	lineOf []int
+
+	// folds are the foldable sections of the content, keyed by the number of
+	// their header line.
+	folds map[int]*fold
+	// rows maps the rows of the last canvas the widget drew on to the lines of
+	// the content drawn on them, contains -1 for rows without content.
+	rows []int
 
 	// scroll tracks scrolling the position.
 	scroll *scrollTracker
 
+	// query is the text of the last search.
+	query string
+	// match is the number of the content line that matched the last search,
+	// -1 if there is none.
+	match int
+	// scrollTo is the number of the content line to scroll to on the next
+	// draw, -1 if there is none.
+	scrollTo int
+
 	// lastWidth stores the width of the last canvas the widget drew on.
 	// Used to determine if the previous line wrapping was invalidated.
 	lastWidth int
@@ -57,6 +83,9 @@ type Text struct {
 	// invalidated.
 	contentChanged bool
 
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
 	// mu protects the Text widget.
 	mu sync.Mutex
 
@@ -71,8 +100,10 @@ func New(opts ...Option) (*Text, error) {
 		return nil, err
 	}
 	return &Text{
-		scroll: newScrollTracker(opt),
-		opts:   opt,
+		scroll:   newScrollTracker(opt),
+		match:    -1,
+		scrollTo: -1,
+		opts:     opt,
 	}, nil
 }
 
@@ -80,6 +111,7 @@ func New(opts ...Option) (*Text, error) {
 func (t *Text) Reset() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	defer t.dirty.Mark()
 	t.reset()
 }
 
@@ -87,7 +119,13 @@ func (t *Text) Reset() {
 func (t *Text) reset() {
 	t.content = nil
 	t.wrapped = nil
+	t.lineOf = nil
+	t.folds = nil
+	t.rows = nil
 	t.scroll = newScrollTracker(t.opts)
+	t.query = ""
+	t.match = -1
+	t.scrollTo = -1
 	t.lastWidth = 0
 	t.contentChanged = true
 }
@@ -95,11 +133,11 @@ func (t *Text) reset() {
 // contentCells calculates the number of cells the content takes to display on
 // terminal.
 func (t *Text) contentCells() int {
-	cells := 0
-	for _, c := range t.content {
-		cells += runewidth.RuneWidth(c.Rune, runewidth.CountAsWidth('\n', 1))
+	runes := make([]rune, len(t.content))
+	for i, c := range t.content {
+		runes[i] = c.Rune
 	}
-	return cells
+	return runewidth.StringWidth(string(runes), runewidth.CountAsWidth('\n', 1))
 }
 
 // Write writes text for the widget to display. Multiple calls append
@@ -111,6 +149,7 @@ func (t *Text) contentCells() int {
 func (t *Text) Write(text string, wOpts ...WriteOption) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	defer t.dirty.Mark()
 
 	if err := wrap.ValidText(text); err != nil {
 		return err
@@ -120,7 +159,55 @@ func (t *Text) Write(text string, wOpts ...WriteOption) error {
 	if opts.replace {
 		t.reset()
 	}
+	t.write(text, opts.cellOpts)
+	return nil
+}
+
+// WriteRich writes text with cell options that change along the text, e.g.
+// the result of cell.ParseANSI. Multiple calls append additional text, the
+// same restrictions on the text apply as for Write.
+// The cell options of the text are used instead of the ones provided via
+// WriteCellOpts, the remaining write options apply as for Write.
+func (t *Text) WriteRich(rts *cell.RichTextString, wOpts ...WriteOption) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+
+	text := rts.Text()
+	if err := wrap.ValidText(text); err != nil {
+		return err
+	}
 
+	opts := newWriteOptions(wOpts...)
+	if opts.replace {
+		t.reset()
+	}
+
+	cur := cell.NewOptions()
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		o := rts.Opts(i)
+		if i < len(text) && o == nil {
+			continue
+		}
+		if i > start {
+			co := *cur
+			if opts.link != "" {
+				co.Link = opts.link
+			}
+			t.write(text[start:i], &co)
+		}
+		if o != nil {
+			cur = o
+		}
+		start = i
+	}
+	return nil
+}
+
+// write appends the validated text to the content.
+// The caller must hold t.mu.
+func (t *Text) write(text string, cellOpts *cell.Options) {
 	truncated := truncateToCells(text, t.opts.maxTextCells)
 	textCells := runewidth.StringWidth(truncated, runewidth.CountAsWidth('\n', 1))
 	contentCells := t.contentCells()
@@ -131,10 +218,9 @@ func (t *Text) Write(text string, wOpts ...WriteOption) error {
 	}
 
 	for _, r := range truncated {
-		t.content = append(t.content, buffer.NewCell(r, opts.cellOpts))
+		t.content = append(t.content, buffer.NewCell(r, cellOpts))
 	}
 	t.contentChanged = true
-	return nil
 }
 
 // minLinesForMarkers are the minimum amount of lines required on the canvas in
@@ -183,8 +269,12 @@ func (t *Text) draw(cvs *canvas.Canvas) error {
 	var cur image.Point // Tracks the current drawing position on the canvas.
 	height := cvs.Area().Dy()
 	fromLine := t.scroll.firstLine(len(t.wrapped), height)
+	t.rows = make([]int, height)
+	for i := range t.rows {
+		t.rows[i] = -1
+	}
 
-	for _, line := range t.wrapped[fromLine:] {
+	for i, line := range t.wrapped[fromLine:] {
 		// Scroll up marker.
 		scrlUp, err := t.drawScrollUp(cvs, cur, fromLine)
 		if err != nil {
@@ -204,8 +294,9 @@ func (t *Text) draw(cvs *canvas.Canvas) error {
 		if scrlDown || cur.Y >= height {
 			break // Skip all lines falling after (under) the canvas.
 		}
+		t.rows[cur.Y] = t.lineOf[fromLine+i]
 
-		for _, cell := range line {
+		for _, cell := range clusterCells(line) {
 			tr, err := lineTrim(cvs, cur, cell.Rune, t.opts)
 			if err != nil {
 				return err
@@ -226,6 +317,44 @@ func (t *Text) draw(cvs *canvas.Canvas) error {
 	return nil
 }
 
+// wrap wraps the content that isn't hidden by folds to the specified width.
+func (t *Text) wrap(width int) error {
+	t.wrapped = nil
+	t.lineOf = nil
+	if width <= 0 {
+		return nil
+	}
+
+	wOpts := []wrap.Option{wrap.HangingIndent(t.opts.wrapIndent)}
+	if t.opts.wrapIndentAlign {
+		wOpts = append(wOpts, wrap.HangingIndentAligned())
+	}
+	for i, line := range t.contentLines() {
+		if t.hidden(i) {
+			continue
+		}
+		if f, ok := t.folds[i]; ok {
+			line = append(t.foldMarker(f), line...)
+		}
+
+		if len(line) == 0 {
+			t.wrapped = append(t.wrapped, nil) // An empty line.
+			t.lineOf = append(t.lineOf, i)
+			continue
+		}
+
+		wr, err := wrap.Cells(line, width, t.opts.wrapMode, wOpts...)
+		if err != nil {
+			return err
+		}
+		for _, l := range wr {
+			t.wrapped = append(t.wrapped, l)
+			t.lineOf = append(t.lineOf, i)
+		}
+	}
+	return nil
+}
+
 // Draw draws the text onto the canvas.
 // Implements widgetapi.Widget.Draw.
 func (t *Text) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
@@ -235,15 +364,24 @@ func (t *Text) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	width := cvs.Area().Dx()
 	if len(t.content) > 0 && (t.contentChanged || t.lastWidth != width) {
 		// The previous text preprocessing (line wrapping) is invalidated when
-		// new text is added or the width of the canvas changed.
-		wr, err := wrap.Cells(t.content, width, t.opts.wrapMode)
-		if err != nil {
+		// new text is added, a fold is toggled or the width of the canvas
+		// changed.
+		if err := t.wrap(width); err != nil {
 			return err
 		}
-		t.wrapped = wr
 	}
 	t.lastWidth = width
 
+	if t.scrollTo >= 0 {
+		for i, line := range t.lineOf {
+			if line == t.scrollTo {
+				t.scroll.toLine(i)
+				break
+			}
+		}
+		t.scrollTo = -1
+	}
+
 	if len(t.wrapped) == 0 {
 		return nil // Nothing to draw if there's no text.
 	}
@@ -269,6 +407,10 @@ func (t *Text) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) erro
 		t.scroll.upOnePage()
 	case k.Key == t.opts.keyPgDown:
 		t.scroll.downOnePage()
+	case k.Key == t.opts.keyFold:
+		if line, ok := t.firstVisibleFold(); ok {
+			t.toggleFold(line)
+		}
 	}
 	return nil
 }
@@ -283,6 +425,10 @@ func (t *Text) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
 		t.scroll.upOneLine()
 	case b == t.opts.mouseDownButton:
 		t.scroll.downOneLine()
+	case b == t.opts.mouseFoldButton:
+		if y := m.Position.Y; y >= 0 && y < len(t.rows) {
+			t.toggleFold(t.rows[y])
+		}
 	}
 	return nil
 }
@@ -307,6 +453,11 @@ func (t *Text) Options() widgetapi.Options {
 	}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (t *Text) SetDirtyFunc(markDirty func()) {
+	t.dirty.Set(markDirty)
+}
+
 // truncateToCells truncates the beginning of text, so that it can be displayed
 // in at most maxCells. Setting maxCells to zero disables truncating.
 func truncateToCells(text string, maxCells int) string {
@@ -316,18 +467,36 @@ func truncateToCells(text string, maxCells int) string {
 	}
 
 	haveCells := 0
-	textRunes := []rune(text)
-	i := len(textRunes) - 1
+	clusters := runewidth.Clusters([]rune(text))
+	i := len(clusters) - 1
 	for ; i >= 0; i-- {
-		haveCells += runewidth.RuneWidth(textRunes[i], runewidth.CountAsWidth('\n', 1))
+		haveCells += runewidth.ClusterWidth(clusters[i], runewidth.CountAsWidth('\n', 1))
 		if haveCells > maxCells {
 			break
 		}
 	}
 
 	var b strings.Builder
-	for j := i + 1; j < len(textRunes); j++ {
-		b.WriteRune(textRunes[j])
+	for j := i + 1; j < len(clusters); j++ {
+		b.WriteString(string(clusters[j]))
 	}
 	return b.String()
 }
+
+// clusterCells returns the cells that start the grapheme clusters on the
+// line. The canvas cells hold a single rune, so the remaining runes of each
+// cluster aren't drawn and the cluster occupies the width of its first rune.
+func clusterCells(line []*buffer.Cell) []*buffer.Cell {
+	runes := make([]rune, len(line))
+	for i, c := range line {
+		runes[i] = c.Rune
+	}
+
+	var res []*buffer.Cell
+	idx := 0
+	for _, c := range runewidth.Clusters(runes) {
+		res = append(res, line[idx])
+		idx += len(c)
+	}
+	return res
+}
@@ -65,6 +65,17 @@ func TestTextDraws(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "fails when WrapHangingIndent is negative",
+			opts: []Option{
+				WrapHangingIndent(-1),
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
 		{
 			desc: "fails when scroll mouse buttons aren't unique",
 			opts: []Option{
@@ -185,6 +196,76 @@ func TestTextDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "marks text written with a link",
+			canvas: image.Rect(0, 0, 12, 1),
+			writes: func(widget *Text) error {
+				if err := widget.Write("see "); err != nil {
+					return err
+				}
+				return widget.Write("docs", WriteLink("https://example.com"), WriteCellOpts(cell.Underline()))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "see ", image.Point{0, 0})
+				testdraw.MustText(c, "docs", image.Point{4, 0},
+					draw.TextCellOpts(&cell.Options{
+						Underline: true,
+						Link:      "https://example.com",
+					}),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "marks text written with the link cell option",
+			canvas: image.Rect(0, 0, 4, 1),
+			writes: func(widget *Text) error {
+				return widget.Write("docs", WriteCellOpts(cell.Link("https://example.com")))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "docs", image.Point{0, 0},
+					draw.TextCellOpts(cell.Link("https://example.com")),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "writes rich text parsed from ANSI escape sequences",
+			canvas: image.Rect(0, 0, 10, 2),
+			writes: func(widget *Text) error {
+				rts, err := cell.ParseANSI("\x1b[31mred\x1b[0m\n\x1b[1;34mbold\x1b[0m!")
+				if err != nil {
+					return err
+				}
+				return widget.WriteRich(rts)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "red", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorMaroon)))
+				testdraw.MustText(c, "bold", image.Point{0, 1}, draw.TextCellOpts(cell.Bold(), cell.FgColor(cell.ColorNavy)))
+				testdraw.MustText(c, "!", image.Point{4, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "rich text write fails for invalid text",
+			canvas: image.Rect(0, 0, 10, 2),
+			writes: func(widget *Text) error {
+				return widget.WriteRich(cell.NewRichTextString(cell.ColorDefault).AddText("a\tb"))
+			},
+			wantWriteErr: true,
+		},
 		{
 			desc:   "respects newlines in the input text",
 			canvas: image.Rect(0, 0, 10, 10),
@@ -259,6 +340,39 @@ func TestTextDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "trims long lines at grapheme cluster boundaries",
+			canvas: image.Rect(0, 0, 5, 2),
+			writes: func(widget *Text) error {
+				return widget.Write("ab👍🏽\nabc👨‍👩‍👧d")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "ab👍", image.Point{0, 0})
+				testdraw.MustText(c, "abc", image.Point{0, 1})
+				testdraw.MustText(c, "…", image.Point{4, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "displays only the first rune of each grapheme cluster",
+			canvas: image.Rect(0, 0, 10, 2),
+			writes: func(widget *Text) error {
+				return widget.Write("e\u0301x\n👨\u200d👩\u200d👧y")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "ex", image.Point{0, 0})
+				testdraw.MustText(c, "👨y", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "trims content when longer than canvas, no scroll marker on small canvas",
 			canvas: image.Rect(0, 0, 10, 2),
@@ -496,6 +610,25 @@ func TestTextDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "wraps lines at grapheme cluster boundaries",
+			canvas: image.Rect(0, 0, 4, 2),
+			opts: []Option{
+				WrapAtRunes(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("abc👍🏽d")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "abc", image.Point{0, 0})
+				testdraw.MustText(c, "👍d", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "wraps lines at full-width rune boundaries",
 			canvas: image.Rect(0, 0, 10, 6),
@@ -563,6 +696,48 @@ func TestTextDraws(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc:   "indents wrapped continuation lines",
+			canvas: image.Rect(0, 0, 10, 5),
+			opts: []Option{
+				WrapAtWords(),
+				WrapHangingIndent(2),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("hello world again\nshort")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "hello", image.Point{0, 0})
+				testdraw.MustText(c, "  world", image.Point{0, 1})
+				testdraw.MustText(c, "  again", image.Point{0, 2})
+				testdraw.MustText(c, "short", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "aligns wrapped continuation lines with the first non-space character",
+			canvas: image.Rect(0, 0, 8, 5),
+			opts: []Option{
+				WrapAtRunes(),
+				WrapHangingIndentAligned(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("  abcdefghij")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "  abcdef", image.Point{0, 0})
+				testdraw.MustText(c, "  ghij", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "rolls content upwards and trims lines",
 			canvas: image.Rect(0, 0, 10, 2),
@@ -1150,6 +1325,18 @@ func TestTruncateToCells(t *testing.T) {
 			maxCells: 4,
 			want:     "世界",
 		},
+		{
+			desc:     "doesn't split emoji with modifiers",
+			text:     "a👍🏽b",
+			maxCells: 3,
+			want:     "👍🏽b",
+		},
+		{
+			desc:     "truncates the whole emoji with modifiers",
+			text:     "a👍🏽b",
+			maxCells: 2,
+			want:     "b",
+		},
 	}
 
 	for _, tc := range tests {
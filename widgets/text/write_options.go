@@ -30,6 +30,7 @@ type WriteOption interface {
 type writeOptions struct {
 	cellOpts *cell.Options
 	replace  bool
+	link     string
 }
 
 // newWriteOptions returns new writeOptions instance.
@@ -40,6 +41,12 @@ func newWriteOptions(wOpts ...WriteOption) *writeOptions {
 	for _, o := range wOpts {
 		o.set(wo)
 	}
+	if wo.link != "" {
+		// Copy, since WriteCellOpts might have been provided after WriteLink.
+		co := *wo.cellOpts
+		co.Link = wo.link
+		wo.cellOpts = &co
+	}
 	return wo
 }
 
@@ -65,3 +72,14 @@ func WriteReplace() WriteOption {
 		wOpts.replace = true
 	})
 }
+
+// WriteLink marks the text as a hyperlink to the provided URL. Terminals that
+// support the OSC 8 escape sequence make the text clickable, e.g. with
+// ctrl-click, which opens the URL in a browser. Other terminals display the
+// text with the cell options provided via WriteCellOpts.
+// Only works when using the tcell backend.
+func WriteLink(url string) WriteOption {
+	return writeOption(func(wOpts *writeOptions) {
+		wOpts.link = url
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package texteditor
+
+// buffer.go contains the editable text and the editing operations.
+
+import (
+	"strings"
+	"unicode"
+)
+
+// position is a position of the cursor in the buffer.
+type position struct {
+	// line is the index of the line.
+	line int
+	// col is the index of the rune on the line the cursor is in front of.
+	col int
+}
+
+// before asserts whether the position is in front of the other position.
+func (p position) before(other position) bool {
+	return p.line < other.line || (p.line == other.line && p.col < other.col)
+}
+
+// buffer stores the edited text as lines of runes, the cursor and the
+// selection.
+// This object is not thread-safe.
+type buffer struct {
+	// lines are the lines of the text without the line breaks.
+	// Always contains at least one line.
+	lines [][]rune
+	// cursor is the position of the cursor.
+	cursor position
+	// anchor is the end of the selection opposite to the cursor, nil when
+	// nothing is selected.
+	anchor *position
+}
+
+// newBuffer returns a new empty buffer.
+func newBuffer() *buffer {
+	return &buffer{
+		lines: [][]rune{nil},
+	}
+}
+
+// text returns the text in the buffer, lines are separated by '\n'.
+func (b *buffer) text() string {
+	return b.between(position{}, b.endPos())
+}
+
+// reset removes all the text from the buffer.
+func (b *buffer) reset() {
+	b.lines = [][]rune{nil}
+	b.cursor = position{}
+	b.anchor = nil
+}
+
+// endPos returns the position at the end of the text.
+func (b *buffer) endPos() position {
+	last := len(b.lines) - 1
+	return position{line: last, col: len(b.lines[last])}
+}
+
+// selection returns the start and the end of the selection.
+// Returns false if nothing is selected.
+func (b *buffer) selection() (position, position, bool) {
+	if b.anchor == nil || *b.anchor == b.cursor {
+		return position{}, position{}, false
+	}
+	if b.anchor.before(b.cursor) {
+		return *b.anchor, b.cursor, true
+	}
+	return b.cursor, *b.anchor, true
+}
+
+// selected asserts whether the position is within the selection.
+func (b *buffer) selected(p position) bool {
+	start, end, ok := b.selection()
+	return ok && !p.before(start) && p.before(end)
+}
+
+// selectedText returns the selected text.
+func (b *buffer) selectedText() string {
+	start, end, ok := b.selection()
+	if !ok {
+		return ""
+	}
+	return b.between(start, end)
+}
+
+// between returns the text between the two positions.
+func (b *buffer) between(start, end position) string {
+	var sb strings.Builder
+	for l := start.line; l <= end.line; l++ {
+		line := b.lines[l]
+		from, to := 0, len(line)
+		if l == start.line {
+			from = start.col
+		}
+		if l == end.line {
+			to = end.col
+		}
+		sb.WriteString(string(line[from:to]))
+		if l != end.line {
+			sb.WriteRune('\n')
+		}
+	}
+	return sb.String()
+}
+
+// deleteBetween removes the text between the two positions and moves the
+// cursor to the start.
+func (b *buffer) deleteBetween(start, end position) {
+	head := b.lines[start.line][:start.col]
+	tail := b.lines[end.line][end.col:]
+	joined := append(append([]rune(nil), head...), tail...)
+
+	lines := append([][]rune(nil), b.lines[:start.line]...)
+	lines = append(lines, joined)
+	b.lines = append(lines, b.lines[end.line+1:]...)
+	b.cursor = start
+	b.anchor = nil
+}
+
+// deleteSelection removes the selected text.
+// Returns false if nothing is selected.
+func (b *buffer) deleteSelection() bool {
+	start, end, ok := b.selection()
+	if !ok {
+		b.anchor = nil
+		return false
+	}
+	b.deleteBetween(start, end)
+	return true
+}
+
+// insert inserts the text at the cursor, replacing the selected text.
+// Line breaks ("\n" and "\r\n") start new lines, tabs are replaced with
+// spaces and other non-printable runes are skipped.
+func (b *buffer) insert(text string) {
+	b.deleteSelection()
+	for _, r := range strings.ReplaceAll(text, "\r\n", "\n") {
+		switch {
+		case r == '\n' || r == '\r':
+			line := b.lines[b.cursor.line]
+			head := append([]rune(nil), line[:b.cursor.col]...)
+			tail := append([]rune(nil), line[b.cursor.col:]...)
+
+			lines := append([][]rune(nil), b.lines[:b.cursor.line]...)
+			lines = append(lines, head, tail)
+			b.lines = append(lines, b.lines[b.cursor.line+1:]...)
+			b.cursor = position{line: b.cursor.line + 1}
+
+		case r == '\t':
+			b.insertRune(' ')
+
+		case unicode.IsPrint(r):
+			b.insertRune(r)
+		}
+	}
+}
+
+// insertRune inserts a single printable rune at the cursor.
+func (b *buffer) insertRune(r rune) {
+	line := b.lines[b.cursor.line]
+	updated := append(append(append([]rune(nil), line[:b.cursor.col]...), r), line[b.cursor.col:]...)
+	b.lines[b.cursor.line] = updated
+	b.cursor.col++
+}
+
+// backspace removes the selected text or the rune in front of the cursor,
+// joining the line with the previous one when the cursor is at its start.
+func (b *buffer) backspace() {
+	if b.deleteSelection() {
+		return
+	}
+	if b.cursor == (position{}) {
+		return
+	}
+	b.deleteBetween(b.prevPos(b.cursor), b.cursor)
+}
+
+// deleteForward removes the selected text or the rune after the cursor,
+// joining the next line when the cursor is at the end of its line.
+func (b *buffer) deleteForward() {
+	if b.deleteSelection() {
+		return
+	}
+	if b.cursor == b.endPos() {
+		return
+	}
+	b.deleteBetween(b.cursor, b.nextPos(b.cursor))
+}
+
+// prevPos returns the position one rune in front of the position.
+func (b *buffer) prevPos(p position) position {
+	switch {
+	case p.col > 0:
+		return position{line: p.line, col: p.col - 1}
+	case p.line > 0:
+		return position{line: p.line - 1, col: len(b.lines[p.line-1])}
+	default:
+		return p
+	}
+}
+
+// nextPos returns the position one rune after the position.
+func (b *buffer) nextPos(p position) position {
+	switch {
+	case p.col < len(b.lines[p.line]):
+		return position{line: p.line, col: p.col + 1}
+	case p.line < len(b.lines)-1:
+		return position{line: p.line + 1}
+	default:
+		return p
+	}
+}
+
+// moveTo moves the cursor to the position, which must be valid. Extends the
+// selection if one was started.
+func (b *buffer) moveTo(p position) {
+	b.cursor = p
+}
+
+// mark starts a selection at the cursor or removes the selection if one was
+// already started.
+func (b *buffer) mark() {
+	if b.anchor != nil {
+		b.anchor = nil
+		return
+	}
+	a := b.cursor
+	b.anchor = &a
+}
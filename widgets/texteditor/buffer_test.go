@@ -0,0 +1,163 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package texteditor
+
+import (
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	tests := []struct {
+		desc          string
+		ops           func(b *buffer)
+		wantText      string
+		wantCursor    position
+		wantSelection string
+	}{
+		{
+			desc:     "empty buffer",
+			ops:      func(b *buffer) {},
+			wantText: "",
+		},
+		{
+			desc: "inserts text with newlines",
+			ops: func(b *buffer) {
+				b.insert("ab\ncd\r\nef")
+			},
+			wantText:   "ab\ncd\nef",
+			wantCursor: position{line: 2, col: 2},
+		},
+		{
+			desc: "replaces tabs and skips non-printable runes",
+			ops: func(b *buffer) {
+				b.insert("a\tb\x07c")
+			},
+			wantText:   "a bc",
+			wantCursor: position{line: 0, col: 4},
+		},
+		{
+			desc: "inserts in the middle of a line",
+			ops: func(b *buffer) {
+				b.insert("ad")
+				b.moveTo(position{line: 0, col: 1})
+				b.insert("b\nc")
+			},
+			wantText:   "ab\ncd",
+			wantCursor: position{line: 1, col: 1},
+		},
+		{
+			desc: "backspace joins lines",
+			ops: func(b *buffer) {
+				b.insert("ab\ncd")
+				b.moveTo(position{line: 1, col: 0})
+				b.backspace()
+			},
+			wantText:   "abcd",
+			wantCursor: position{line: 0, col: 2},
+		},
+		{
+			desc: "backspace at the start does nothing",
+			ops: func(b *buffer) {
+				b.insert("ab")
+				b.moveTo(position{})
+				b.backspace()
+			},
+			wantText: "ab",
+		},
+		{
+			desc: "delete joins lines",
+			ops: func(b *buffer) {
+				b.insert("ab\ncd")
+				b.moveTo(position{line: 0, col: 2})
+				b.deleteForward()
+			},
+			wantText:   "abcd",
+			wantCursor: position{line: 0, col: 2},
+		},
+		{
+			desc: "selection across lines",
+			ops: func(b *buffer) {
+				b.insert("ab\ncd")
+				b.moveTo(position{line: 0, col: 1})
+				b.mark()
+				b.moveTo(position{line: 1, col: 1})
+			},
+			wantText:      "ab\ncd",
+			wantCursor:    position{line: 1, col: 1},
+			wantSelection: "b\nc",
+		},
+		{
+			desc: "selection backwards",
+			ops: func(b *buffer) {
+				b.insert("abc")
+				b.mark()
+				b.moveTo(position{line: 0, col: 1})
+			},
+			wantText:      "abc",
+			wantCursor:    position{line: 0, col: 1},
+			wantSelection: "bc",
+		},
+		{
+			desc: "insert replaces the selection",
+			ops: func(b *buffer) {
+				b.insert("ab\ncd")
+				b.moveTo(position{line: 0, col: 1})
+				b.mark()
+				b.moveTo(position{line: 1, col: 1})
+				b.insert("x")
+			},
+			wantText:   "axd",
+			wantCursor: position{line: 0, col: 2},
+		},
+		{
+			desc: "backspace deletes the selection",
+			ops: func(b *buffer) {
+				b.insert("abc")
+				b.mark()
+				b.moveTo(position{line: 0, col: 1})
+				b.backspace()
+			},
+			wantText:   "a",
+			wantCursor: position{line: 0, col: 1},
+		},
+		{
+			desc: "mark toggles the selection off",
+			ops: func(b *buffer) {
+				b.insert("abc")
+				b.mark()
+				b.moveTo(position{line: 0, col: 1})
+				b.mark()
+			},
+			wantText:   "abc",
+			wantCursor: position{line: 0, col: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := newBuffer()
+			tc.ops(b)
+			if got := b.text(); got != tc.wantText {
+				t.Errorf("text => %q, want %q", got, tc.wantText)
+			}
+			if got := b.cursor; got != tc.wantCursor {
+				t.Errorf("cursor => %+v, want %+v", got, tc.wantCursor)
+			}
+			if got := b.selectedText(); got != tc.wantSelection {
+				t.Errorf("selectedText => %q, want %q", got, tc.wantSelection)
+			}
+		})
+	}
+}
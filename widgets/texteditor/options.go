@@ -0,0 +1,97 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package texteditor
+
+// options.go contains configurable options for TextEditor.
+
+import (
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	textCellOpts             []cell.Option
+	cursorCellOpts           []cell.Option
+	selectionCellOpts        []cell.Option
+	defaultText              string
+	exclusiveKeyboardOnFocus bool
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		cursorCellOpts:    []cell.Option{cell.Inverse()},
+		selectionCellOpts: []cell.Option{cell.BgColor(cell.ColorNumber(DefaultSelectionColorNumber))},
+	}
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// TextCellOpts sets the cell options on the text.
+// Defaults to the default cell options.
+func TextCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.textCellOpts = co
+	})
+}
+
+// CursorCellOpts sets the cell options on the cell with the cursor, which is
+// only displayed while the widget is focused.
+// Defaults to inverse colors.
+func CursorCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.cursorCellOpts = co
+	})
+}
+
+// DefaultSelectionColorNumber is the default color number of the background
+// of the selected text.
+const DefaultSelectionColorNumber = 240
+
+// SelectionCellOpts sets the cell options on the selected text.
+// Defaults to a gray background.
+func SelectionCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectionCellOpts = co
+	})
+}
+
+// DefaultText sets the text present in a newly created editor. The lines are
+// separated by '\n'.
+func DefaultText(text string) Option {
+	return option(func(opts *options) {
+		opts.defaultText = text
+	})
+}
+
+// ExclusiveKeyboardOnFocus when set ensures that when this widget is focused,
+// no other widget receives any keyboard events.
+func ExclusiveKeyboardOnFocus() Option {
+	return option(func(opts *options) {
+		opts.exclusiveKeyboardOnFocus = true
+	})
+}
@@ -0,0 +1,387 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package texteditor implements a widget for editing multi-line text.
+package texteditor
+
+import (
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// TextEditor allows the user to edit multi-line text.
+//
+// Lines that don't fit the width of the widget are wrapped. The cursor is
+// moved with the arrow keys, Home, End, PgUp, PgDn and by clicking with the
+// mouse. Enter starts a new line.
+//
+// Text is selected by dragging the mouse with the left button pressed or by
+// pressing Ctrl-Space and moving the cursor, Esc removes the selection.
+// Typing, pasting or deleting replaces the selected text.
+//
+// Implements widgetapi.Widget and widgetapi.Paster. This object is
+// thread-safe.
+type TextEditor struct {
+	// buf is the edited text.
+	buf *buffer
+
+	// offset is the index of the first displayed row.
+	offset int
+	// lastArea is the area of the canvas the last time Draw was called.
+	lastArea image.Rectangle
+	// dragging indicates that the left mouse button was pressed within the
+	// widget and wasn't released yet.
+	dragging bool
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new TextEditor.
+func New(opts ...Option) (*TextEditor, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	te := &TextEditor{
+		buf:  newBuffer(),
+		opts: opt,
+	}
+	te.buf.insert(opt.defaultText)
+	return te, nil
+}
+
+// Read returns the edited text, the lines are separated by '\n'.
+func (te *TextEditor) Read() string {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	return te.buf.text()
+}
+
+// ReadAndClear returns the edited text and removes it from the editor.
+func (te *TextEditor) ReadAndClear() string {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	defer te.dirty.Mark()
+
+	text := te.buf.text()
+	te.buf.reset()
+	te.offset = 0
+	return text
+}
+
+// SetText replaces the edited text and moves the cursor to its end.
+func (te *TextEditor) SetText(text string) {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	defer te.dirty.Mark()
+
+	te.buf.reset()
+	te.buf.insert(text)
+	te.offset = 0
+}
+
+// Selection returns the selected text or an empty string if nothing is
+// selected.
+func (te *TextEditor) Selection() string {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	return te.buf.selectedText()
+}
+
+// row is a part of a line displayed on one row of the widget.
+type row struct {
+	// line is the index of the line.
+	line int
+	// start is the index of the first rune of the line on the row.
+	start int
+	// end is the index after the last rune of the line on the row.
+	end int
+	// last indicates that this is the last row of the line.
+	last bool
+}
+
+// contains asserts whether the row displays the cursor at the position.
+func (r row) contains(p position) bool {
+	return p.line == r.line && p.col >= r.start && (p.col < r.end || (p.col == r.end && r.last))
+}
+
+// layout wraps the lines into rows that fit the width. A line that exactly
+// fills its last row gets an additional empty row for the cursor at its end.
+func layout(lines [][]rune, width int) []row {
+	var rows []row
+	for i, line := range lines {
+		start, cells := 0, 0
+		for j, r := range line {
+			rw := runewidth.RuneWidth(r)
+			if cells+rw > width && j > start {
+				rows = append(rows, row{line: i, start: start, end: j})
+				start, cells = j, 0
+			}
+			cells += rw
+		}
+		if cells >= width && len(line) > start {
+			rows = append(rows, row{line: i, start: start, end: len(line)})
+			start = len(line)
+		}
+		rows = append(rows, row{line: i, start: start, end: len(line), last: true})
+	}
+	return rows
+}
+
+// cursorRow returns the index of the row that displays the cursor.
+func cursorRow(rows []row, p position) int {
+	for i, r := range rows {
+		if r.contains(p) {
+			return i
+		}
+	}
+	return len(rows) - 1
+}
+
+// colAt returns the position on the row that is closest to the provided
+// number of cells from the start of the row.
+func colAt(lines [][]rune, r row, cells int) position {
+	line := lines[r.line]
+	col, x := r.start, 0
+	for col < r.end {
+		rw := runewidth.RuneWidth(line[col])
+		if x+rw > cells {
+			break
+		}
+		x += rw
+		col++
+	}
+	if col == r.end && !r.last {
+		// The end of a row that isn't last belongs to the next row.
+		col--
+	}
+	return position{line: r.line, col: col}
+}
+
+// cellsTo returns the number of cells between the start of the row and the
+// position.
+func cellsTo(lines [][]rune, r row, p position) int {
+	return runewidth.StringWidth(string(lines[r.line][r.start:p.col]))
+}
+
+// rows returns the rows of the text for the width of the last drawn canvas.
+// Caller must hold te.mu.
+func (te *TextEditor) rows() []row {
+	width := te.lastArea.Dx()
+	if width <= 0 {
+		width = int(^uint(0) >> 1)
+	}
+	return layout(te.buf.lines, width)
+}
+
+// moveRows moves the cursor by the number of rows up (negative) or down
+// (positive), keeping its distance from the start of the row.
+// Caller must hold te.mu.
+func (te *TextEditor) moveRows(n int) {
+	rows := te.rows()
+	cur := cursorRow(rows, te.buf.cursor)
+	target := cur + n
+	switch {
+	case target < 0:
+		te.buf.moveTo(position{})
+		return
+	case target >= len(rows):
+		te.buf.moveTo(te.buf.endPos())
+		return
+	}
+	cells := cellsTo(te.buf.lines, rows[cur], te.buf.cursor)
+	te.buf.moveTo(colAt(te.buf.lines, rows[target], cells))
+}
+
+// Draw draws the TextEditor widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (te *TextEditor) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	ar := cvs.Area()
+	te.lastArea = ar
+	rows := te.rows()
+
+	// Scroll so that the cursor is visible.
+	cur := cursorRow(rows, te.buf.cursor)
+	if cur < te.offset {
+		te.offset = cur
+	}
+	if cur >= te.offset+ar.Dy() {
+		te.offset = cur - ar.Dy() + 1
+	}
+	if max := len(rows) - ar.Dy(); te.offset > max {
+		te.offset = max
+	}
+	if te.offset < 0 {
+		te.offset = 0
+	}
+
+	for y := 0; y < ar.Dy() && te.offset+y < len(rows); y++ {
+		r := rows[te.offset+y]
+		line := te.buf.lines[r.line]
+		x := 0
+		for col := r.start; col < r.end; col++ {
+			cOpts := te.opts.textCellOpts
+			if te.buf.selected(position{line: r.line, col: col}) {
+				cOpts = append(append([]cell.Option(nil), cOpts...), te.opts.selectionCellOpts...)
+			}
+			cells, err := cvs.SetCell(image.Point{x, y}, line[col], cOpts...)
+			if err != nil {
+				return err
+			}
+			x += cells
+		}
+	}
+
+	if meta.Focused {
+		cx := cellsTo(te.buf.lines, rows[cur], te.buf.cursor)
+		if p := (image.Point{cx, cur - te.offset}); p.In(ar) {
+			if err := cvs.SetCellOpts(p, te.opts.cursorCellOpts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Keyboard edits the text and moves the cursor.
+// Implements widgetapi.Widget.Keyboard.
+func (te *TextEditor) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	b := te.buf
+	switch k.Key {
+	case keyboard.KeyEnter:
+		b.insert("\n")
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		b.backspace()
+	case keyboard.KeyDelete:
+		b.deleteForward()
+	case keyboard.KeyArrowLeft:
+		b.moveTo(b.prevPos(b.cursor))
+	case keyboard.KeyArrowRight:
+		b.moveTo(b.nextPos(b.cursor))
+	case keyboard.KeyArrowUp:
+		te.moveRows(-1)
+	case keyboard.KeyArrowDown:
+		te.moveRows(1)
+	case keyboard.KeyPgUp:
+		te.moveRows(-te.lastArea.Dy())
+	case keyboard.KeyPgDn:
+		te.moveRows(te.lastArea.Dy())
+	case keyboard.KeyHome:
+		b.moveTo(position{line: b.cursor.line})
+	case keyboard.KeyEnd:
+		b.moveTo(position{line: b.cursor.line, col: len(b.lines[b.cursor.line])})
+	case keyboard.KeyCtrlSpace:
+		b.mark()
+	case keyboard.KeyEsc:
+		b.anchor = nil
+	default:
+		if k.Key > 0 {
+			b.insert(string(k.Key))
+		}
+	}
+	return nil
+}
+
+// Paste inserts the pasted text at the cursor, replacing the selected text.
+// Implements widgetapi.Paster.Paste.
+func (te *TextEditor) Paste(p *terminalapi.Paste, meta *widgetapi.EventMeta) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	te.buf.insert(p.Text)
+	return nil
+}
+
+// Mouse moves the cursor to the clicked position and selects text when the
+// mouse is dragged with the left button pressed. The mouse wheel moves the
+// cursor up and down.
+// Implements widgetapi.Widget.Mouse.
+func (te *TextEditor) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	b := te.buf
+	switch m.Button {
+	case mouse.ButtonLeft:
+		p := te.pointPos(m.Position)
+		if !te.dragging {
+			te.dragging = true
+			b.anchor = &p
+		}
+		b.moveTo(p)
+
+	case mouse.ButtonRelease:
+		te.dragging = false
+		if b.anchor != nil && *b.anchor == b.cursor {
+			b.anchor = nil
+		}
+
+	case mouse.ButtonWheelUp:
+		te.moveRows(-1)
+	case mouse.ButtonWheelDown:
+		te.moveRows(1)
+	}
+	return nil
+}
+
+// pointPos returns the position of the cursor for a point on the canvas.
+// Caller must hold te.mu.
+func (te *TextEditor) pointPos(p image.Point) position {
+	rows := te.rows()
+	idx := te.offset + p.Y - te.lastArea.Min.Y
+	switch {
+	case idx < 0:
+		return position{}
+	case idx >= len(rows):
+		return te.buf.endPos()
+	}
+	return colAt(te.buf.lines, rows[idx], p.X-te.lastArea.Min.X)
+}
+
+// Options implements widgetapi.Widget.Options.
+func (te *TextEditor) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:              image.Point{1, 1},
+		WantKeyboard:             widgetapi.KeyScopeFocused,
+		WantMouse:                widgetapi.MouseScopeWidget,
+		ExclusiveKeyboardOnFocus: te.opts.exclusiveKeyboardOnFocus,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (te *TextEditor) SetDirtyFunc(markDirty func()) {
+	te.dirty.Set(markDirty)
+}
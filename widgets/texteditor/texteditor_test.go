@@ -0,0 +1,370 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package texteditor
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestTextEditor(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		canvas        image.Rectangle
+		meta          *widgetapi.Meta
+		events        []terminalapi.Event
+		want          func(size image.Point) *faketerm.Terminal
+		wantText      string
+		wantSelection string
+	}{
+		{
+			desc:   "draws the default text",
+			opts:   []Option{DefaultText("ab\ncd")},
+			canvas: image.Rect(0, 0, 4, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "ab", image.Point{0, 0})
+				testdraw.MustText(cvs, "cd", image.Point{0, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "ab\ncd",
+		},
+		{
+			desc:   "wraps long lines",
+			opts:   []Option{DefaultText("abcdef\ng")},
+			canvas: image.Rect(0, 0, 4, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abcd", image.Point{0, 0})
+				testdraw.MustText(cvs, "ef", image.Point{0, 1})
+				testdraw.MustText(cvs, "g", image.Point{0, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abcdef\ng",
+		},
+		{
+			desc:   "draws the cursor when focused",
+			opts:   []Option{DefaultText("ab")},
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "ab", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, 0, cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "ab",
+		},
+		{
+			desc:   "cursor at the end of a full row moves to the next row",
+			opts:   []Option{DefaultText("abcd")},
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abcd", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{0, 1}, 0, cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abcd",
+		},
+		{
+			desc:   "scrolls to keep the cursor visible",
+			opts:   []Option{DefaultText("a\nb\nc\nd")},
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "c", image.Point{0, 0})
+				testdraw.MustText(cvs, "d", image.Point{0, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "a\nb\nc\nd",
+		},
+		{
+			desc:   "edits text using the keyboard",
+			canvas: image.Rect(0, 0, 4, 3),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'a'},
+				&terminalapi.Keyboard{Key: 'c'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: 'b'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnd},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'd'},
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyBackspace2},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyHome},
+				&terminalapi.Keyboard{Key: keyboard.KeyDelete},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "bc", image.Point{0, 0})
+				testdraw.MustText(cvs, "d", image.Point{0, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "bc\nd",
+		},
+		{
+			desc:   "arrow keys move over wrapped rows",
+			opts:   []Option{DefaultText("abcdef")},
+			canvas: image.Rect(0, 0, 4, 3),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: 'x'},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abxc", image.Point{0, 0})
+				testdraw.MustText(cvs, "def", image.Point{0, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abxcdef",
+		},
+		{
+			desc:   "selects text using the keyboard",
+			opts:   []Option{DefaultText("abc")},
+			canvas: image.Rect(0, 0, 4, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyCtrlSpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "a", image.Point{0, 0})
+				testdraw.MustText(cvs, "bc", image.Point{1, 0}, draw.TextCellOpts(
+					cell.BgColor(cell.ColorNumber(DefaultSelectionColorNumber)),
+				))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText:      "abc",
+			wantSelection: "bc",
+		},
+		{
+			desc:   "escape clears the selection",
+			opts:   []Option{DefaultText("abc")},
+			canvas: image.Rect(0, 0, 4, 1),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyCtrlSpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abc", image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abc",
+		},
+		{
+			desc:   "selects text by dragging the mouse",
+			opts:   []Option{DefaultText("abcdef")},
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				opts := draw.TextCellOpts(cell.BgColor(cell.ColorNumber(DefaultSelectionColorNumber)))
+				testdraw.MustText(cvs, "a", image.Point{0, 0})
+				testdraw.MustText(cvs, "bcd", image.Point{1, 0}, opts)
+				testdraw.MustText(cvs, "e", image.Point{0, 1}, opts)
+				testdraw.MustText(cvs, "f", image.Point{1, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText:      "abcdef",
+			wantSelection: "bcde",
+		},
+		{
+			desc:   "click moves the cursor",
+			opts:   []Option{DefaultText("abc\nd")},
+			canvas: image.Rect(0, 0, 4, 3),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+				&terminalapi.Keyboard{Key: 'x'},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abcx", image.Point{0, 0})
+				// The full row is followed by an empty row for the cursor.
+				testdraw.MustText(cvs, "d", image.Point{0, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abcx\nd",
+		},
+		{
+			desc:   "pastes text replacing the selection",
+			opts:   []Option{DefaultText("abc")},
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyCtrlSpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Paste{Text: "x\ny"},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "abx", image.Point{0, 0})
+				testdraw.MustText(cvs, "y", image.Point{0, 1})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantText: "abx\ny",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			te, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			// Draw once so that the widget knows its size.
+			if err := te.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					err = te.Keyboard(e, &widgetapi.EventMeta{})
+				case *terminalapi.Mouse:
+					err = te.Mouse(e, &widgetapi.EventMeta{})
+				case *terminalapi.Paste:
+					err = te.Paste(e, &widgetapi.EventMeta{})
+				}
+				if err != nil {
+					t.Fatalf("event %v => unexpected error: %v", ev, err)
+				}
+			}
+
+			c, err = canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := te.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if got := te.Read(); got != tc.wantText {
+				t.Errorf("Read => %q, want %q", got, tc.wantText)
+			}
+			if got := te.Selection(); got != tc.wantSelection {
+				t.Errorf("Selection => %q, want %q", got, tc.wantSelection)
+			}
+		})
+	}
+}
+
+func TestReadAndClear(t *testing.T) {
+	te, err := New(DefaultText("ab\ncd"))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if got, want := te.ReadAndClear(), "ab\ncd"; got != want {
+		t.Errorf("ReadAndClear => %q, want %q", got, want)
+	}
+	if got, want := te.Read(), ""; got != want {
+		t.Errorf("Read after ReadAndClear => %q, want %q", got, want)
+	}
+
+	te.SetText("xy")
+	if got, want := te.Read(), "xy"; got != want {
+		t.Errorf("Read after SetText => %q, want %q", got, want)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	te, err := New(ExclusiveKeyboardOnFocus())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := te.Options()
+	want := widgetapi.Options{
+		MinimumSize:              image.Point{1, 1},
+		WantKeyboard:             widgetapi.KeyScopeFocused,
+		WantMouse:                widgetapi.MouseScopeWidget,
+		ExclusiveKeyboardOnFocus: true,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
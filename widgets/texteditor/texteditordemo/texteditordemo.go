@@ -0,0 +1,113 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary texteditordemo displays a TextEditor widget and statistics of the
+// edited text.
+// Exist when Ctrl-Q is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/mum4k/termdash/widgets/texteditor"
+)
+
+// periodic executes the provided closure periodically every interval.
+// Exits when the context expires.
+func periodic(ctx context.Context, interval time.Duration, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := fn(); err != nil {
+				panic(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := tcell.New(tcell.BracketedPaste())
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	te, err := texteditor.New(
+		texteditor.DefaultText("Type here.\nSelect text with the mouse or with Ctrl-Space and the arrow keys."),
+	)
+	if err != nil {
+		panic(err)
+	}
+	stats, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	go periodic(ctx, 250*time.Millisecond, func() error {
+		txt := te.Read()
+		stats.Reset()
+		return stats.Write(fmt.Sprintf(
+			"Lines: %d\nRunes: %d\nSelected: %d runes",
+			strings.Count(txt, "\n")+1,
+			len([]rune(txt)),
+			len([]rune(te.Selection())),
+		))
+	})
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS CTRL-Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Editor"),
+				container.PlaceWidget(te),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Statistics"),
+				container.PlaceWidget(stats),
+			),
+			container.SplitPercent(70),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == keyboard.KeyCtrlQ {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -329,6 +329,23 @@ func (fe *fieldEditor) reset() {
 	*fe = *newFieldEditor()
 }
 
+// prefix returns the content before the cursor.
+func (fe *fieldEditor) prefix() string {
+	return string(fe.data[:fe.curDataPos])
+}
+
+// replacePrefix replaces the content before the cursor with the text and
+// moves the cursor after it.
+func (fe *fieldEditor) replacePrefix(text string) {
+	rest := append(fieldData(nil), fe.data[fe.curDataPos:]...)
+	fe.data = fe.data[:0]
+	fe.curDataPos = 0
+	for _, r := range text {
+		fe.insert(r)
+	}
+	fe.data = append(fe.data, rest...)
+}
+
 // insert inserts the rune at the current position of the cursor.
 func (fe *fieldEditor) insert(r rune) {
 	rw := runewidth.RuneWidth(r)
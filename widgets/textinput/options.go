@@ -48,6 +48,7 @@ type options struct {
 	placeHolderColor cell.Color
 	highlightedColor cell.Color
 	cursorColor      cell.Color
+	cursorCellOpts   []cell.Option
 	border           linestyle.LineStyle
 	borderColor      cell.Color
 
@@ -65,6 +66,12 @@ type options struct {
 	onSubmit                 SubmitFn
 	clearOnSubmit            bool
 	exclusiveKeyboardOnFocus bool
+	pasteStripNewlines       bool
+
+	suggest                    SuggestFn
+	suggestionRows             int
+	suggestionCellOpts         []cell.Option
+	selectedSuggestionCellOpts []cell.Option
 }
 
 // validate validates the provided options.
@@ -83,6 +90,9 @@ func (o *options) validate() error {
 			return fmt.Errorf("invalid HideTextWidth rune %c(%d), has rune width of %d cells, only runes with width of %d are accepted", r, r, got, want)
 		}
 	}
+	if min, rows := 1, o.suggestionRows; rows < min {
+		return fmt.Errorf("invalid SuggestionRows(%d), must be value in range %d <= value", rows, min)
+	}
 	if o.defaultText != "" {
 		if err := wrap.ValidText(o.defaultText); err != nil {
 			return fmt.Errorf("invalid DefaultText: %v", err)
@@ -104,6 +114,10 @@ func newOptions() *options {
 		highlightedColor: cell.ColorNumber(DefaultHighlightedColorNumber),
 		cursorColor:      cell.ColorNumber(DefaultCursorColorNumber),
 		labelAlign:       DefaultLabelAlign,
+		suggestionRows:   DefaultSuggestionRows,
+		selectedSuggestionCellOpts: []cell.Option{
+			cell.BgColor(cell.ColorNumber(DefaultSelectedSuggestionColorNumber)),
+		},
 	}
 }
 
@@ -150,6 +164,17 @@ func CursorColor(c cell.Color) Option {
 	})
 }
 
+// CursorCellOpts sets additional cell options for the cell the cursor is on,
+// e.g. cell.Inverse() or cell.Bold(). These are applied after the colors set
+// by the HighlightedColor and CursorColor options, so options that set colors
+// take precedence over them. The options also apply when the text is hidden
+// via the HideTextWith option.
+func CursorCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.cursorCellOpts = cOpts
+	})
+}
+
 // Border adds a border around the text input field.
 func Border(ls linestyle.LineStyle) Option {
 	return option(func(opts *options) {
@@ -285,6 +310,14 @@ func ExclusiveKeyboardOnFocus() Option {
 	})
 }
 
+// PasteStripNewlines removes line breaks from pasted text instead of replacing
+// them with spaces.
+func PasteStripNewlines() Option {
+	return option(func(opts *options) {
+		opts.pasteStripNewlines = true
+	})
+}
+
 // DefaultText sets the text to be present in a newly created input field.
 // The text must not contain any control or space characters other than ' '.
 // The user can edit this text as normal.
@@ -293,3 +326,59 @@ func DefaultText(text string) Option {
 		opts.defaultText = text
 	})
 }
+
+// SuggestFn is a function that returns the suggested completions for the text
+// before the cursor.
+//
+// The SuggestFn must not attempt to read from or modify the TextInput
+// instance, it is called while the TextInput is mutex locked.
+type SuggestFn func(prefix string) []string
+
+// Suggestions enables completion of the typed text. Whenever the user edits
+// the text, the SuggestFn is called with the text before the cursor and the
+// returned items are displayed in a list below the input field.
+//
+// The items are selected with the Up and Down arrow keys. Pressing Tab or
+// clicking an item replaces the text before the cursor with the item, Enter
+// does the same when an item is selected. Esc closes the list.
+//
+// The widget requests the space for the list, i.e. its maximum height grows
+// by the number of rows configured via SuggestionRows.
+func Suggestions(fn SuggestFn) Option {
+	return option(func(opts *options) {
+		opts.suggest = fn
+	})
+}
+
+// DefaultSuggestionRows is the default value for the SuggestionRows option.
+const DefaultSuggestionRows = 5
+
+// SuggestionRows sets the maximum number of suggestions displayed at once,
+// the list scrolls if there are more.
+// Must be a positive number, defaults to DefaultSuggestionRows.
+func SuggestionRows(rows int) Option {
+	return option(func(opts *options) {
+		opts.suggestionRows = rows
+	})
+}
+
+// SuggestionCellOpts sets the cell options for the suggested items.
+// Defaults to the default cell options.
+func SuggestionCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.suggestionCellOpts = cOpts
+	})
+}
+
+// DefaultSelectedSuggestionColorNumber is the default background color number
+// of the selected suggestion.
+const DefaultSelectedSuggestionColorNumber = 240
+
+// SelectedSuggestionCellOpts sets the cell options for the selected
+// suggestion.
+// Defaults to a background color of DefaultSelectedSuggestionColorNumber.
+func SelectedSuggestionCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectedSuggestionCellOpts = cOpts
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textinput
+
+// suggest.go contains the state of the list of suggested completions.
+
+// suggestions tracks the suggested completions and the one selected by the
+// user.
+// This object isn't thread-safe.
+type suggestions struct {
+	// items are the suggested completions, the list is closed when empty.
+	items []string
+	// selected is the index of the selected item or -1 if none is selected.
+	selected int
+	// first is the index of the first displayed item.
+	first int
+}
+
+// newSuggestions returns a new, closed, list of suggestions.
+func newSuggestions() *suggestions {
+	return &suggestions{selected: -1}
+}
+
+// isOpen asserts whether there are any suggestions to display.
+func (s *suggestions) isOpen() bool {
+	return len(s.items) > 0
+}
+
+// update replaces the suggested items and resets the selection.
+func (s *suggestions) update(items []string) {
+	s.items = items
+	s.selected = -1
+	s.first = 0
+}
+
+// close removes all the suggestions.
+func (s *suggestions) close() {
+	s.update(nil)
+}
+
+// next selects the next item, stopping at the last one.
+func (s *suggestions) next() {
+	if s.selected < len(s.items)-1 {
+		s.selected++
+	}
+}
+
+// prev selects the previous item, moving before the first item removes the
+// selection.
+func (s *suggestions) prev() {
+	if s.selected >= 0 {
+		s.selected--
+	}
+}
+
+// chosen returns the item that is accepted by the user, which is the
+// selected one or the first item if none is selected.
+// Returns false if there are no suggestions.
+func (s *suggestions) chosen() (string, bool) {
+	if !s.isOpen() {
+		return "", false
+	}
+	if s.selected < 0 {
+		return s.items[0], true
+	}
+	return s.items[s.selected], true
+}
+
+// visible returns the items that fit into the provided number of rows and the
+// index of the first one, scrolling the list so the selected item is visible.
+func (s *suggestions) visible(rows int) ([]string, int) {
+	if rows <= 0 {
+		return nil, 0
+	}
+	if s.selected >= 0 && s.selected < s.first {
+		s.first = s.selected
+	}
+	if s.selected >= s.first+rows {
+		s.first = s.selected - rows + 1
+	}
+	end := s.first + rows
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+	return s.items[s.first:end], s.first
+}
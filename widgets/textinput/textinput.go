@@ -28,6 +28,7 @@ import (
 	"github.com/mum4k/termdash/private/alignfor"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
 	"github.com/mum4k/termdash/private/draw"
 	"github.com/mum4k/termdash/private/runewidth"
 	"github.com/mum4k/termdash/private/wrap"
@@ -44,6 +45,9 @@ import (
 // Read. The text input field can be navigated using arrows, the Home and End
 // button and using mouse.
 //
+// When configured with the Suggestions option, the widget displays a list of
+// suggested completions below the input field.
+//
 // Implements widgetapi.Widget. This object is thread-safe.
 type TextInput struct {
 	// mu protects the widget.
@@ -56,8 +60,17 @@ type TextInput struct {
 	// time Draw() was called.
 	forField image.Rectangle
 
+	// suggestions are the completions suggested for the current text.
+	suggestions *suggestions
+	// forSuggestions is the area that was occupied by the list of suggestions
+	// last time Draw() was called.
+	forSuggestions image.Rectangle
+
 	// opts are the provided options.
 	opts *options
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
 }
 
 // New returns a new TextInput.
@@ -70,8 +83,9 @@ func New(opts ...Option) (*TextInput, error) {
 		return nil, err
 	}
 	ti := &TextInput{
-		editor: newFieldEditor(),
-		opts:   opt,
+		editor:      newFieldEditor(),
+		suggestions: newSuggestions(),
+		opts:        opt,
 	}
 
 	for _, r := range ti.opts.defaultText {
@@ -103,9 +117,11 @@ func (ti *TextInput) Read() string {
 func (ti *TextInput) ReadAndClear() string {
 	ti.mu.Lock()
 	defer ti.mu.Unlock()
+	defer ti.dirty.Mark()
 
 	c := ti.editor.content()
 	ti.editor.reset()
+	ti.suggestions.close()
 	return c
 }
 
@@ -146,11 +162,11 @@ func (ti *TextInput) drawCursor(cvs *canvas.Canvas, curPos int) error {
 		curPos + ti.forField.Min.X,
 		ti.forField.Min.Y,
 	}
-	if err := cvs.SetCellOpts(
-		p,
+	cOpts := append([]cell.Option{
 		cell.FgColor(ti.opts.highlightedColor),
 		cell.BgColor(ti.opts.cursorColor),
-	); err != nil {
+	}, ti.opts.cursorCellOpts...)
+	if err := cvs.SetCellOpts(p, cOpts...); err != nil {
 		return err
 	}
 	if cursorRune != 0 {
@@ -161,13 +177,91 @@ func (ti *TextInput) drawCursor(cvs *canvas.Canvas, curPos int) error {
 	return nil
 }
 
+// splitSuggestions splits the canvas area into the area for the input field
+// and the area for the list of suggestions which is image.ZR unless the
+// Suggestions option was provided.
+func (ti *TextInput) splitSuggestions(cvsAr image.Rectangle) (inputAr, suggestAr image.Rectangle) {
+	if ti.opts.suggest == nil {
+		return cvsAr, image.ZR
+	}
+	_, height := ti.inputSize()
+	if cvsAr.Dy() <= height {
+		return cvsAr, image.ZR
+	}
+	inputAr = image.Rect(cvsAr.Min.X, cvsAr.Min.Y, cvsAr.Max.X, cvsAr.Min.Y+height)
+	suggestAr = image.Rect(cvsAr.Min.X, inputAr.Max.Y, cvsAr.Max.X, cvsAr.Max.Y)
+	return inputAr, suggestAr
+}
+
+// drawSuggestions draws the list of suggestions below the text input field.
+func (ti *TextInput) drawSuggestions(cvs *canvas.Canvas, suggestAr image.Rectangle) error {
+	ti.forSuggestions = image.ZR
+	if !ti.suggestions.isOpen() || suggestAr.Empty() {
+		return nil
+	}
+
+	rows := ti.opts.suggestionRows
+	if rows > suggestAr.Dy() {
+		rows = suggestAr.Dy()
+	}
+	items, first := ti.suggestions.visible(rows)
+	ti.forSuggestions = image.Rect(
+		ti.forField.Min.X, suggestAr.Min.Y,
+		ti.forField.Max.X, suggestAr.Min.Y+len(items),
+	)
+
+	for i, item := range items {
+		cOpts := ti.opts.suggestionCellOpts
+		if first+i == ti.suggestions.selected {
+			cOpts = ti.opts.selectedSuggestionCellOpts
+		}
+		rowAr := image.Rect(
+			ti.forSuggestions.Min.X, ti.forSuggestions.Min.Y+i,
+			ti.forSuggestions.Max.X, ti.forSuggestions.Min.Y+i+1,
+		)
+		if err := cvs.SetAreaCells(rowAr, ' ', cOpts...); err != nil {
+			return err
+		}
+		if err := draw.Text(
+			cvs, item, rowAr.Min,
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextMaxX(rowAr.Max.X),
+			draw.TextCellOpts(cOpts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suggest updates the suggested completions after the content was edited.
+func (ti *TextInput) suggest() {
+	if ti.opts.suggest == nil {
+		return
+	}
+	ti.suggestions.update(ti.opts.suggest(ti.editor.prefix()))
+}
+
+// complete replaces the text before the cursor with the chosen suggestion.
+// Returns false if there are no suggestions.
+func (ti *TextInput) complete() bool {
+	item, ok := ti.suggestions.chosen()
+	if !ok {
+		return false
+	}
+	ti.editor.replacePrefix(item)
+	ti.suggestions.close()
+	return true
+}
+
 // Draw draws the TextInput widget onto the canvas.
 // Implements widgetapi.Widget.Draw.
 func (ti *TextInput) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	ti.mu.Lock()
 	defer ti.mu.Unlock()
 
-	labelAr, textAr, err := split(cvs.Area(), ti.opts.label, ti.opts.widthPerc)
+	inputAr, suggestAr := ti.splitSuggestions(cvs.Area())
+	labelAr, textAr, err := split(inputAr, ti.opts.label, ti.opts.widthPerc)
 	if err != nil {
 		return err
 	}
@@ -207,6 +301,9 @@ func (ti *TextInput) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		if err := ti.drawCursor(cvs, curPos); err != nil {
 			return err
 		}
+		if err := ti.drawSuggestions(cvs, suggestAr); err != nil {
+			return err
+		}
 	} else if ti.opts.placeHolder != "" && text == "" {
 		if err := draw.Text(
 			cvs, ti.opts.placeHolder, ti.forField.Min,
@@ -227,26 +324,59 @@ func (ti *TextInput) keyboard(k *terminalapi.Keyboard) (bool, string) {
 	ti.mu.Lock()
 	defer ti.mu.Unlock()
 
+	if ti.suggestions.isOpen() {
+		switch k.Key {
+		case keyboard.KeyArrowDown:
+			ti.suggestions.next()
+			return false, ""
+
+		case keyboard.KeyArrowUp:
+			ti.suggestions.prev()
+			return false, ""
+
+		case keyboard.KeyTab:
+			ti.complete()
+			return false, ""
+
+		case keyboard.KeyEnter:
+			if ti.suggestions.selected >= 0 {
+				ti.complete()
+				return false, ""
+			}
+
+		case keyboard.KeyEsc:
+			ti.suggestions.close()
+			return false, ""
+		}
+	}
+
 	switch k.Key {
 	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
 		ti.editor.deleteBefore()
+		ti.suggest()
 
 	case keyboard.KeyDelete:
 		ti.editor.delete()
+		ti.suggest()
 
 	case keyboard.KeyArrowLeft:
 		ti.editor.cursorLeft()
+		ti.suggestions.close()
 
 	case keyboard.KeyArrowRight:
 		ti.editor.cursorRight()
+		ti.suggestions.close()
 
 	case keyboard.KeyHome, keyboard.KeyCtrlA:
 		ti.editor.cursorStart()
+		ti.suggestions.close()
 
 	case keyboard.KeyEnd, keyboard.KeyCtrlE:
 		ti.editor.cursorEnd()
+		ti.suggestions.close()
 
 	case keyboard.KeyEnter:
+		ti.suggestions.close()
 		text := ti.editor.content()
 		if ti.opts.clearOnSubmit {
 			ti.editor.reset()
@@ -265,6 +395,7 @@ func (ti *TextInput) keyboard(k *terminalapi.Keyboard) (bool, string) {
 			return false, ""
 		}
 		ti.editor.insert(rune(k.Key))
+		ti.suggest()
 	}
 
 	return false, ""
@@ -282,15 +413,55 @@ func (ti *TextInput) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta
 	return nil
 }
 
+// Paste inserts the pasted text at the cursor in a single edit, without
+// submitting the content. Line breaks and tabs are replaced with spaces,
+// unless the PasteStripNewlines option removes the line breaks. Runes that
+// the Filter rejects are skipped.
+// Implements widgetapi.Paster.
+func (ti *TextInput) Paste(p *terminalapi.Paste, meta *widgetapi.EventMeta) error {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	for _, r := range strings.ReplaceAll(p.Text, "\r\n", "\n") {
+		switch r {
+		case '\r', '\n':
+			if ti.opts.pasteStripNewlines {
+				continue
+			}
+			r = ' '
+		case '\t':
+			r = ' '
+		}
+		if err := wrap.ValidText(string(r)); err != nil {
+			continue
+		}
+		if ti.opts.filter != nil && !ti.opts.filter(r) {
+			continue
+		}
+		ti.editor.insert(r)
+	}
+	ti.suggest()
+	return nil
+}
+
 // Mouse processes mouse events.
 // Implements widgetapi.Widget.Mouse.
 func (ti *TextInput) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
 	ti.mu.Lock()
 	defer ti.mu.Unlock()
 
-	if m.Button != mouse.ButtonLeft || !m.Position.In(ti.forField) {
+	if m.Button != mouse.ButtonLeft {
 		return nil
 	}
+	if m.Position.In(ti.forSuggestions) {
+		ti.suggestions.selected = ti.suggestions.first + m.Position.Y - ti.forSuggestions.Min.Y
+		ti.complete()
+		return nil
+	}
+	if !m.Position.In(ti.forField) {
+		return nil
+	}
+	ti.suggestions.close()
 
 	cellIdx := m.Position.X - ti.forField.Min.X
 	ti.editor.cursorRelCell(cellIdx)
@@ -305,23 +476,18 @@ func (ti *TextInput) Options() widgetapi.Options {
 	ti.mu.Lock()
 	defer ti.mu.Unlock()
 
-	needWidth := minFieldWidth
-	if lw := runewidth.StringWidth(ti.opts.label); lw > 0 {
-		needWidth += lw
-	}
-
-	needHeight := minFieldHeight
-	if ti.opts.border != linestyle.None {
-		needWidth += 2
-		needHeight += 2
-	}
-
+	needWidth, needHeight := ti.inputSize()
 	maxWidth := 0
 	if ti.opts.maxWidthCells != nil {
 		additional := *ti.opts.maxWidthCells - minFieldWidth
 		maxWidth = needWidth + additional
 	}
 
+	maxHeight := needHeight
+	if ti.opts.suggest != nil {
+		maxHeight += ti.opts.suggestionRows
+	}
+
 	return widgetapi.Options{
 		MinimumSize: image.Point{
 			needWidth,
@@ -329,7 +495,7 @@ func (ti *TextInput) Options() widgetapi.Options {
 		},
 		MaximumSize: image.Point{
 			maxWidth,
-			needHeight,
+			maxHeight,
 		},
 		WantKeyboard:             widgetapi.KeyScopeFocused,
 		WantMouse:                widgetapi.MouseScopeWidget,
@@ -337,6 +503,27 @@ func (ti *TextInput) Options() widgetapi.Options {
 	}
 }
 
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (ti *TextInput) SetDirtyFunc(markDirty func()) {
+	ti.dirty.Set(markDirty)
+}
+
+// inputSize returns the minimum width and the height of the label and the
+// text input field including its border.
+func (ti *TextInput) inputSize() (int, int) {
+	needWidth := minFieldWidth
+	if lw := runewidth.StringWidth(ti.opts.label); lw > 0 {
+		needWidth += lw
+	}
+
+	needHeight := minFieldHeight
+	if ti.opts.border != linestyle.None {
+		needWidth += 2
+		needHeight += 2
+	}
+	return needWidth, needHeight
+}
+
 // split splits the available area into label and text input areas according to
 // configuration. The returned labelAr might be image.ZR if no label was
 // configured.
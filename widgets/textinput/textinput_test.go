@@ -17,6 +17,7 @@ package textinput
 import (
 	"errors"
 	"image"
+	"strings"
 	"sync"
 	"testing"
 
@@ -64,6 +65,17 @@ func (ct *callbackTracker) submit(text string) error {
 	return nil
 }
 
+// suggestCommands suggests the commands that start with the prefix.
+func suggestCommands(prefix string) []string {
+	var res []string
+	for _, c := range []string{"help", "hello", "history"} {
+		if strings.HasPrefix(c, prefix) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
 func TestTextInput(t *testing.T) {
 	// Makes the empty text input field visible and cursor in test outputs.
 	textFieldRune = '_'
@@ -117,6 +129,14 @@ func TestTextInput(t *testing.T) {
 			},
 			wantNewErr: true,
 		},
+		{
+			desc: "fails on SuggestionRows too low",
+			opts: []Option{
+				Suggestions(suggestCommands),
+				SuggestionRows(0),
+			},
+			wantNewErr: true,
+		},
 		{
 			desc: "fails on invalid DefaultText which has control characters",
 			opts: []Option{
@@ -331,6 +351,76 @@ func TestTextInput(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "sets custom cursor cell options",
+			opts: []Option{
+				CursorCellOpts(cell.Inverse(), cell.FgColor(cell.ColorBlue)),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta: &widgetapi.Meta{
+				Focused: true,
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(
+					cvs,
+					cvs.Area(),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testcanvas.MustSetCell(
+					cvs,
+					image.Point{0, 0},
+					cursorRune,
+					cell.BgColor(cell.ColorNumber(DefaultCursorColorNumber)),
+					cell.FgColor(cell.ColorBlue),
+					cell.Inverse(),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "cursor cell options apply to hidden text",
+			opts: []Option{
+				HideTextWith('*'),
+				DefaultText("ab"),
+				CursorCellOpts(cell.Bold()),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta: &widgetapi.Meta{
+				Focused: true,
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(
+					cvs,
+					cvs.Area(),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testdraw.MustText(
+					cvs,
+					"**",
+					image.Point{0, 0},
+					draw.TextCellOpts(cell.BgColor(cell.ColorNumber(DefaultFillColorNumber))),
+				)
+				testcanvas.MustSetCell(
+					cvs,
+					image.Point{2, 0},
+					cursorRune,
+					cell.BgColor(cell.ColorNumber(DefaultCursorColorNumber)),
+					cell.FgColor(cell.ColorNumber(DefaultHighlightedColorNumber)),
+					cell.Bold(),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc: "sets width percentage, results in area too small",
 			opts: []Option{
@@ -1434,6 +1524,112 @@ func TestTextInput(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "draws suggestions below the text field",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			canvas: image.Rect(0, 0, 10, 4),
+			meta: &widgetapi.Meta{
+				Focused: true,
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: 'e'},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(
+					cvs,
+					image.Rect(0, 0, 10, 1),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testdraw.MustText(cvs, "he", image.Point{0, 0})
+				testcanvas.MustSetCell(
+					cvs,
+					image.Point{2, 0},
+					cursorRune,
+					cell.BgColor(cell.ColorNumber(DefaultCursorColorNumber)),
+					cell.FgColor(cell.ColorNumber(DefaultHighlightedColorNumber)),
+				)
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 1, 10, 3), ' ')
+				testdraw.MustText(cvs, "help", image.Point{0, 1})
+				testdraw.MustText(cvs, "hello", image.Point{0, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "highlights the selected suggestion and scrolls the list",
+			opts: []Option{
+				Suggestions(suggestCommands),
+				SuggestionRows(2),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			meta: &widgetapi.Meta{
+				Focused: true,
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(
+					cvs,
+					image.Rect(0, 0, 10, 1),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testdraw.MustText(cvs, "h", image.Point{0, 0})
+				testcanvas.MustSetCell(
+					cvs,
+					image.Point{1, 0},
+					cursorRune,
+					cell.BgColor(cell.ColorNumber(DefaultCursorColorNumber)),
+					cell.FgColor(cell.ColorNumber(DefaultHighlightedColorNumber)),
+				)
+				selected := cell.BgColor(cell.ColorNumber(DefaultSelectedSuggestionColorNumber))
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 1, 10, 2), ' ')
+				testdraw.MustText(cvs, "hello", image.Point{0, 1})
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 2, 10, 3), ' ', selected)
+				testdraw.MustText(cvs, "history", image.Point{0, 2}, draw.TextCellOpts(selected))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "doesn't draw suggestions when not focused",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			canvas: image.Rect(0, 0, 10, 4),
+			meta:   &widgetapi.Meta{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(
+					cvs,
+					image.Rect(0, 0, 10, 1),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testdraw.MustText(cvs, "h", image.Point{0, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc:   "ignores mouse events outside of the text field",
 			canvas: image.Rect(0, 0, 10, 1),
@@ -1592,6 +1788,7 @@ func TestTextInput(t *testing.T) {
 func TestTextInputRead(t *testing.T) {
 	tests := []struct {
 		desc   string
+		opts   []Option
 		events []terminalapi.Event
 		want   string
 	}{
@@ -1609,11 +1806,116 @@ func TestTextInputRead(t *testing.T) {
 			},
 			want: "abc",
 		},
+		{
+			desc: "inserts pasted text at the cursor",
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'a'},
+				&terminalapi.Keyboard{Key: 'b'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Paste{Text: "xyz"},
+			},
+			want: "axyzb",
+		},
+		{
+			desc: "replaces line breaks and tabs in pasted text with spaces",
+			events: []terminalapi.Event{
+				&terminalapi.Paste{Text: "a\nb\r\nc\td"},
+			},
+			want: "a b c d",
+		},
+		{
+			desc: "strips line breaks from pasted text",
+			opts: []Option{
+				PasteStripNewlines(),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Paste{Text: "ab\ncd\r\n"},
+			},
+			want: "abcd",
+		},
+		{
+			desc: "tab completes the first suggestion",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: 'e'},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: "help",
+		},
+		{
+			desc: "enter completes the selected suggestion",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: "hello",
+		},
+		{
+			desc: "completion replaces only the text before the cursor",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: ' '},
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyHome},
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: 'i'},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: "history x",
+		},
+		{
+			desc: "escape closes the suggestions",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: "h",
+		},
+		{
+			desc: "pasted text is completed",
+			opts: []Option{
+				Suggestions(suggestCommands),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Paste{Text: "his"},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+			},
+			want: "history",
+		},
+		{
+			desc: "filters pasted text",
+			opts: []Option{
+				Filter(func(r rune) bool {
+					return r >= '0' && r <= '9'
+				}),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Paste{Text: "12-34 56"},
+			},
+			want: "123456",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			ti, err := New()
+			ti, err := New(tc.opts...)
 			if err != nil {
 				t.Fatalf("New => unexpected error: %v", err)
 			}
@@ -1626,6 +1928,11 @@ func TestTextInputRead(t *testing.T) {
 						t.Fatalf("Keyboard => unexpected error: %v", err)
 					}
 
+				case *terminalapi.Paste:
+					if err := ti.Paste(e, &widgetapi.EventMeta{}); err != nil {
+						t.Fatalf("Paste => unexpected error: %v", err)
+					}
+
 				default:
 					t.Fatalf("unsupported event type: %T", ev)
 				}
@@ -1658,6 +1965,35 @@ func TestTextInputRead(t *testing.T) {
 	}
 }
 
+func TestTextInputSuggestionsMouse(t *testing.T) {
+	ti, err := New(Suggestions(suggestCommands))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := ti.Keyboard(&terminalapi.Keyboard{Key: 'h'}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+
+	c, err := canvas.New(image.Rect(0, 0, 10, 4))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := ti.Draw(c, &widgetapi.Meta{Focused: true}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	// Clicks the second suggestion.
+	if err := ti.Mouse(&terminalapi.Mouse{
+		Button:   mouse.ButtonLeft,
+		Position: image.Point{3, 2},
+	}, &widgetapi.EventMeta{}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if got, want := ti.Read(), "hello"; got != want {
+		t.Errorf("Read => %q, want %q", got, want)
+	}
+}
+
 func TestOptions(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -1685,6 +2021,19 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeWidget,
 			},
 		},
+		{
+			desc: "suggestions increase the maximum height",
+			opts: []Option{
+				Suggestions(suggestCommands),
+				SuggestionRows(3),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{4, 1},
+				MaximumSize:  image.Point{0, 4},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
 		{
 			desc: "no label, has border",
 			opts: []Option{
@@ -0,0 +1,181 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+// options.go contains configurable options for Tree.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options holds the provided options.
+type options struct {
+	indent           int
+	expandedRune     rune
+	collapsedRune    rune
+	selectedCellOpts []cell.Option
+	onSelect         SelectFn
+
+	keyUp           keyboard.Key
+	keyDown         keyboard.Key
+	keyToggle       keyboard.Key
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		indent:           DefaultIndent,
+		expandedRune:     DefaultExpandedRune,
+		collapsedRune:    DefaultCollapsedRune,
+		selectedCellOpts: []cell.Option{cell.Inverse()},
+		keyUp:            DefaultKeyUp,
+		keyDown:          DefaultKeyDown,
+		keyToggle:        DefaultKeyToggle,
+		mouseUpButton:    DefaultMouseButtonUp,
+		mouseDownButton:  DefaultMouseButtonDown,
+	}
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.indent < 0 {
+		return fmt.Errorf("invalid Indent %d, must be zero or positive", o.indent)
+	}
+	for _, r := range []rune{o.expandedRune, o.collapsedRune} {
+		if got, want := runewidth.RuneWidth(r), 1; got != want {
+			return fmt.Errorf("invalid marker rune %q, it occupies %d cells, must occupy exactly %d", r, got, want)
+		}
+	}
+	keys := map[keyboard.Key]bool{
+		o.keyUp:     true,
+		o.keyDown:   true,
+		o.keyToggle: true,
+	}
+	if len(keys) != 3 {
+		return fmt.Errorf("invalid navigation keys: %s, %s, %s, the keys must be unique", o.keyUp, o.keyDown, o.keyToggle)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid navigation mouse buttons: %s, %s, the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// DefaultIndent is the default value for the Indent option.
+const DefaultIndent = 2
+
+// Indent sets the number of cells each level of the tree is indented by.
+// Defaults to DefaultIndent.
+func Indent(cells int) Option {
+	return option(func(opts *options) {
+		opts.indent = cells
+	})
+}
+
+// The default runes that mark expanded and collapsed nodes.
+const (
+	DefaultExpandedRune  = '▾'
+	DefaultCollapsedRune = '▸'
+)
+
+// MarkerRunes sets the runes displayed in front of expanded and collapsed
+// nodes that have children. The runes must occupy exactly one cell.
+// Defaults to DefaultExpandedRune and DefaultCollapsedRune.
+func MarkerRunes(expanded, collapsed rune) Option {
+	return option(func(opts *options) {
+		opts.expandedRune = expanded
+		opts.collapsedRune = collapsed
+	})
+}
+
+// SelectedCellOpts sets the cell options on the cells of the selected node.
+// These are applied on top of the cell options of the node.
+// Defaults to inverse colors.
+func SelectedCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.selectedCellOpts = co
+	})
+}
+
+// SelectFn is called when the selected node changes. The argument is the
+// selected node as provided to SetRoots.
+//
+// The callback function must be thread-safe as the keyboard and mouse events
+// come from a separate goroutine. The Tree isn't locked while the function
+// executes, so it can read from or modify the Tree.
+type SelectFn func(n *Node) error
+
+// OnSelect sets the function that is called when the user selects a node.
+func OnSelect(fn SelectFn) Option {
+	return option(func(opts *options) {
+		opts.onSelect = fn
+	})
+}
+
+// The default keys that move the selection and expand or collapse the
+// selected node.
+const (
+	DefaultKeyUp     = keyboard.KeyArrowUp
+	DefaultKeyDown   = keyboard.KeyArrowDown
+	DefaultKeyToggle = keyboard.KeyEnter
+)
+
+// NavigationKeys configures the keyboard keys that move the selection one
+// node up and down and the key that expands or collapses the selected node.
+// The provided keys must be unique.
+func NavigationKeys(up, down, toggle keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyToggle = toggle
+	})
+}
+
+// The default mouse buttons that move the selection.
+const (
+	DefaultMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// NavigationMouseButtons configures the mouse buttons that move the
+// selection one node up and down.
+// The provided buttons must be unique, e.g. the same button cannot be both up
+// and down.
+func NavigationMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
@@ -0,0 +1,471 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tree contains a widget that displays hierarchical nodes.
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/dirty"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/runewidth"
+	"github.com/mum4k/termdash/private/wrap"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Node is one node of the tree.
+//
+// The nodes must not be modified after they were provided to SetRoots, call
+// SetRoots again with new or modified nodes instead.
+type Node struct {
+	// ID identifies the node among its siblings, e.g. the name of a pod.
+	// Optional, defaults to the Label. The Tree remembers which nodes are
+	// expanded and which node is selected by their IDs, so the state is kept
+	// when the nodes are replaced by a call to SetRoots.
+	ID string
+
+	// Label is the text displayed for the node.
+	Label string
+
+	// Icon is an optional rune displayed in front of the label.
+	Icon rune
+
+	// CellOpts are the cell options on the cells that contain the icon and
+	// the label.
+	CellOpts []cell.Option
+
+	// Children are the child nodes.
+	Children []*Node
+
+	// Expanded indicates if the children are initially displayed. Only
+	// applies to nodes the Tree hasn't displayed before, afterwards the user
+	// expands and collapses the nodes.
+	Expanded bool
+}
+
+// id returns the ID of the node.
+func (n *Node) id() string {
+	if n.ID != "" {
+		return n.ID
+	}
+	return n.Label
+}
+
+// pathSep separates the IDs of nodes in a path.
+const pathSep = "\x00"
+
+// line is one displayed line of the tree.
+type line struct {
+	node *Node
+	// path identifies the node, these are the IDs of all the nodes from the
+	// root to this node joined by pathSep.
+	path  string
+	depth int
+}
+
+// Tree displays hierarchical nodes that can be expanded and collapsed, e.g.
+//
+//	▾ default
+//	  ▸ nginx-7d4f
+//	  ▾ redis-5c8b
+//	      redis
+//	▸ kube-system
+//
+// When focused, the keyboard moves the selected node and expands or collapses
+// it. The mouse selects the clicked node and expands or collapses it. Nodes
+// that don't fit the height of the widget scroll so that the selected node is
+// always visible.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Tree struct {
+	// roots are the top level nodes.
+	roots []*Node
+	// expanded maps paths of the nodes to their expanded state.
+	expanded map[string]bool
+	// selected is the path of the selected node, empty if no node is
+	// selected.
+	selected string
+
+	// lines are the displayed lines as of the last change.
+	lines []*line
+	// offset is the index into lines of the first displayed line.
+	offset int
+	// lastAr is the area the lines were drawn into as of the last call to
+	// Draw.
+	lastAr image.Rectangle
+
+	// dirty reports changes of the content to the infrastructure.
+	dirty dirty.Reporter
+
+	// mu protects the Tree.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Tree widget.
+func New(opts ...Option) (*Tree, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Tree{
+		expanded: map[string]bool{},
+		opts:     opt,
+	}, nil
+}
+
+// validateNodes validates the nodes and all their descendants.
+func validateNodes(nodes []*Node) error {
+	ids := map[string]bool{}
+	for _, n := range nodes {
+		if n == nil {
+			return errors.New("the nodes cannot be nil")
+		}
+		if n.Label != "" {
+			if err := wrap.ValidText(n.Label); err != nil {
+				return fmt.Errorf("invalid Label %q: %v", n.Label, err)
+			}
+			if strings.ContainsRune(n.Label, '\n') {
+				return fmt.Errorf("invalid Label %q: newline characters aren't allowed", n.Label)
+			}
+		}
+		if n.Icon != 0 {
+			if w := runewidth.RuneWidth(n.Icon); w < 1 {
+				return fmt.Errorf("invalid Icon %q of node %q, it must occupy at least one cell", n.Icon, n.Label)
+			}
+		}
+		if ids[n.id()] {
+			return fmt.Errorf("duplicate node ID %q among siblings, use the ID field to distinguish nodes with the same Label", n.id())
+		}
+		ids[n.id()] = true
+		if err := validateNodes(n.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRoots replaces the nodes displayed by the tree. The expanded and the
+// selected nodes are remembered by their IDs and kept if they still exist.
+func (t *Tree) SetRoots(roots ...*Node) error {
+	if err := validateNodes(roots); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.dirty.Mark()
+	t.roots = roots
+	t.flatten()
+	if t.find(t.selected) < 0 {
+		t.selected = ""
+	}
+	return nil
+}
+
+// Selected returns the selected node or nil if no node is selected.
+func (t *Tree) Selected() *Node {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if i := t.find(t.selected); i >= 0 {
+		return t.lines[i].node
+	}
+	return nil
+}
+
+// flatten determines the displayed lines.
+// The caller must hold t.mu.
+func (t *Tree) flatten() {
+	t.lines = nil
+	t.addLines(t.roots, "", 0)
+}
+
+// addLines adds lines for the nodes and their expanded descendants.
+// The caller must hold t.mu.
+func (t *Tree) addLines(nodes []*Node, parent string, depth int) {
+	for _, n := range nodes {
+		path := n.id()
+		if depth > 0 {
+			path = parent + pathSep + path
+		}
+		t.lines = append(t.lines, &line{
+			node:  n,
+			path:  path,
+			depth: depth,
+		})
+		if t.isExpanded(n, path) {
+			t.addLines(n.Children, path, depth+1)
+		}
+	}
+}
+
+// isExpanded determines if the node on the path is expanded.
+// The caller must hold t.mu.
+func (t *Tree) isExpanded(n *Node, path string) bool {
+	if exp, ok := t.expanded[path]; ok {
+		return exp
+	}
+	return n.Expanded
+}
+
+// find returns the index of the line with the path or -1 if the node isn't
+// displayed.
+// The caller must hold t.mu.
+func (t *Tree) find(path string) int {
+	if path == "" {
+		return -1
+	}
+	for i, l := range t.lines {
+		if l.path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// Draw draws the Tree widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (t *Tree) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	needAr, err := area.FromSize(t.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	ar := cvs.Area()
+	t.lastAr = ar
+	if i := t.find(t.selected); i >= 0 {
+		if i < t.offset {
+			t.offset = i
+		}
+		if i >= t.offset+ar.Dy() {
+			t.offset = i - ar.Dy() + 1
+		}
+	}
+	if max := len(t.lines) - ar.Dy(); t.offset > max {
+		t.offset = max
+	}
+	if t.offset < 0 {
+		t.offset = 0
+	}
+
+	for y := 0; y < ar.Dy() && t.offset+y < len(t.lines); y++ {
+		lineAr := image.Rect(ar.Min.X, ar.Min.Y+y, ar.Max.X, ar.Min.Y+y+1)
+		if err := t.drawLine(cvs, t.lines[t.offset+y], lineAr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawLine draws one line of the tree into the area.
+func (t *Tree) drawLine(cvs *canvas.Canvas, l *line, lineAr image.Rectangle) error {
+	n := l.node
+	cellOpts := n.CellOpts
+	selected := l.path == t.selected
+	if selected {
+		cellOpts = append(append([]cell.Option{}, n.CellOpts...), t.opts.selectedCellOpts...)
+	}
+
+	var b strings.Builder
+	switch {
+	case len(n.Children) == 0:
+		b.WriteString(" ")
+	case t.isExpanded(n, l.path):
+		b.WriteRune(t.opts.expandedRune)
+	default:
+		b.WriteRune(t.opts.collapsedRune)
+	}
+	b.WriteString(" ")
+	if n.Icon != 0 {
+		b.WriteRune(n.Icon)
+		b.WriteString(" ")
+	}
+	b.WriteString(n.Label)
+
+	start := image.Point{lineAr.Min.X + l.depth*t.opts.indent, lineAr.Min.Y}
+	width := lineAr.Max.X - start.X
+	if width < 1 {
+		return nil
+	}
+	text, err := draw.Truncate(b.String(), width)
+	if err != nil {
+		return err
+	}
+	if selected {
+		textAr := image.Rect(start.X, lineAr.Min.Y, start.X+runewidth.StringWidth(text), lineAr.Max.Y)
+		if err := draw.Rectangle(cvs, textAr, draw.RectChar(' '), draw.RectCellOpts(cellOpts...)); err != nil {
+			return err
+		}
+	}
+	return draw.Text(cvs, text, start, draw.TextMaxX(lineAr.Max.X), draw.TextCellOpts(cellOpts...))
+}
+
+// minSize determines the minimum required size to draw the widget.
+func (t *Tree) minSize() image.Point {
+	return image.Point{1, 1}
+}
+
+// move moves the selection by the specified number of lines. Selects the
+// first node if no node is selected. Returns true if the selection changed.
+// The caller must hold t.mu.
+func (t *Tree) move(by int) bool {
+	if len(t.lines) == 0 {
+		return false
+	}
+	i := t.find(t.selected)
+	if i < 0 {
+		t.selected = t.lines[0].path
+		return true
+	}
+
+	next := i + by
+	if next < 0 {
+		next = 0
+	}
+	if max := len(t.lines) - 1; next > max {
+		next = max
+	}
+	if next == i {
+		return false
+	}
+	t.selected = t.lines[next].path
+	return true
+}
+
+// toggle expands or collapses the node on the line.
+// The caller must hold t.mu.
+func (t *Tree) toggle(l *line) {
+	if len(l.node.Children) == 0 {
+		return
+	}
+	t.expanded[l.path] = !t.isExpanded(l.node, l.path)
+	t.flatten()
+}
+
+// selectedNode returns the selected node or nil if no node is selected.
+// The caller must hold t.mu.
+func (t *Tree) selectedNode() *Node {
+	if i := t.find(t.selected); i >= 0 {
+		return t.lines[i].node
+	}
+	return nil
+}
+
+// Keyboard moves the selected node and expands or collapses it.
+// Implements widgetapi.Widget.Keyboard.
+func (t *Tree) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	if changed, n := t.keyboard(k); changed {
+		// Mutex must be released when calling the callback so that it can
+		// access the Tree.
+		return t.opts.onSelect(n)
+	}
+	return nil
+}
+
+// keyboard processes the keyboard event. Returns true if the selected node
+// changed and the SelectFn should be called.
+func (t *Tree) keyboard(k *terminalapi.Keyboard) (bool, *Node) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed bool
+	switch k.Key {
+	case t.opts.keyUp:
+		changed = t.move(-1)
+	case t.opts.keyDown:
+		changed = t.move(1)
+	case t.opts.keyToggle:
+		if i := t.find(t.selected); i >= 0 {
+			t.toggle(t.lines[i])
+		}
+	}
+	return changed && t.opts.onSelect != nil, t.selectedNode()
+}
+
+// Mouse selects the clicked node and expands or collapses it. The navigation
+// buttons move the selected node.
+// Implements widgetapi.Widget.Mouse.
+func (t *Tree) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	if changed, n := t.mouse(m); changed {
+		// Mutex must be released when calling the callback so that it can
+		// access the Tree.
+		return t.opts.onSelect(n)
+	}
+	return nil
+}
+
+// mouse processes the mouse event. Returns true if the selected node changed
+// and the SelectFn should be called.
+func (t *Tree) mouse(m *terminalapi.Mouse) (bool, *Node) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed bool
+	switch m.Button {
+	case t.opts.mouseUpButton:
+		changed = t.move(-1)
+	case t.opts.mouseDownButton:
+		changed = t.move(1)
+	case mouse.ButtonLeft:
+		if !m.Position.In(t.lastAr) {
+			break
+		}
+		i := t.offset + m.Position.Y - t.lastAr.Min.Y
+		if i >= len(t.lines) {
+			break
+		}
+		l := t.lines[i]
+		changed = l.path != t.selected
+		t.selected = l.path
+		t.toggle(l)
+	}
+	return changed && t.opts.onSelect != nil, t.selectedNode()
+}
+
+// Options implements widgetapi.Widget.Options.
+func (t *Tree) Options() widgetapi.Options {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return widgetapi.Options{
+		MinimumSize:  t.minSize(),
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// SetDirtyFunc implements widgetapi.DirtyReporter.SetDirtyFunc.
+func (t *Tree) SetDirtyFunc(markDirty func()) {
+	t.dirty.Set(markDirty)
+}
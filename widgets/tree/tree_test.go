@@ -0,0 +1,482 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/canvas/testcanvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/private/draw/testdraw"
+	"github.com/mum4k/termdash/private/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// keyEv returns a keyboard event for the key.
+func keyEv(k keyboard.Key) *terminalapi.Keyboard {
+	return &terminalapi.Keyboard{Key: k}
+}
+
+// clickEv returns a left click mouse event at the position.
+func clickEv(x, y int) *terminalapi.Mouse {
+	return &terminalapi.Mouse{Position: image.Point{x, y}, Button: mouse.ButtonLeft}
+}
+
+// podNodes returns nodes used in the tests.
+func podNodes() []*Node {
+	return []*Node{
+		{
+			Label:    "default",
+			Expanded: true,
+			Children: []*Node{
+				{Label: "nginx", Children: []*Node{{Label: "web"}}},
+				{Label: "redis"},
+			},
+		},
+		{
+			Label:    "system",
+			Children: []*Node{{Label: "dns"}},
+		},
+	}
+}
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		canvas image.Rectangle
+		roots  []*Node
+		// update is called after the roots are set.
+		update func(*Tree) error
+		// events are delivered after the first draw, the result of the
+		// second draw is compared.
+		events        func(*Tree) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails on a negative indent",
+			opts:       []Option{Indent(-1)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on a zero width marker rune",
+			opts:       []Option{MarkerRunes(0, '+')},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on duplicate navigation keys",
+			opts:       []Option{NavigationKeys(keyboard.KeyEnter, keyboard.KeyArrowDown, keyboard.KeyEnter)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on duplicate navigation mouse buttons",
+			opts:       []Option{NavigationMouseButtons(mouse.ButtonWheelUp, mouse.ButtonWheelUp)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:          "fails on a nil node",
+			canvas:        image.Rect(0, 0, 10, 3),
+			roots:         []*Node{{Label: "a", Children: []*Node{nil}}},
+			wantUpdateErr: true,
+		},
+		{
+			desc:          "fails on a label with a newline",
+			canvas:        image.Rect(0, 0, 10, 3),
+			roots:         []*Node{{Label: "a\nb"}},
+			wantUpdateErr: true,
+		},
+		{
+			desc:          "fails on duplicate sibling IDs",
+			canvas:        image.Rect(0, 0, 10, 3),
+			roots:         []*Node{{Label: "a"}, {ID: "a", Label: "b"}},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "accepts same labels with different IDs",
+			canvas: image.Rect(0, 0, 5, 2),
+			roots:  []*Node{{ID: "1", Label: "a"}, {ID: "2", Label: "a"}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "  a", image.Point{0, 0})
+				testdraw.MustText(c, "  a", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws nothing without nodes",
+			canvas: image.Rect(0, 0, 5, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws the expanded nodes",
+			canvas: image.Rect(0, 0, 12, 5),
+			roots:  podNodes(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ default", image.Point{0, 0})
+				testdraw.MustText(c, "▸ nginx", image.Point{2, 1})
+				testdraw.MustText(c, "  redis", image.Point{2, 2})
+				testdraw.MustText(c, "▸ system", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws with custom indent and markers",
+			opts: []Option{
+				Indent(1),
+				MarkerRunes('-', '+'),
+			},
+			canvas: image.Rect(0, 0, 12, 4),
+			roots:  podNodes(),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "- default", image.Point{0, 0})
+				testdraw.MustText(c, "+ nginx", image.Point{1, 1})
+				testdraw.MustText(c, "  redis", image.Point{1, 2})
+				testdraw.MustText(c, "+ system", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws icons and node cell options",
+			canvas: image.Rect(0, 0, 8, 1),
+			roots: []*Node{
+				{Label: "pod", Icon: '*', CellOpts: []cell.Option{cell.FgColor(cell.ColorRed)}},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "  * pod", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "truncates labels that don't fit",
+			canvas: image.Rect(0, 0, 6, 1),
+			roots:  []*Node{{Label: "kube-system"}},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "  kub…", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "first key press selects the first node",
+			canvas: image.Rect(0, 0, 12, 4),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				return tr.Keyboard(keyEv(keyboard.KeyArrowDown), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 9, 1), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▾ default", image.Point{0, 0}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ nginx", image.Point{2, 1})
+				testdraw.MustText(c, "  redis", image.Point{2, 2})
+				testdraw.MustText(c, "▸ system", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "toggle key expands the selected node",
+			canvas: image.Rect(0, 0, 12, 5),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				for _, k := range []keyboard.Key{keyboard.KeyArrowDown, keyboard.KeyArrowDown, keyboard.KeyEnter} {
+					if err := tr.Keyboard(keyEv(k), &widgetapi.EventMeta{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ default", image.Point{0, 0})
+				testdraw.MustRectangle(c, image.Rect(2, 1, 9, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▾ nginx", image.Point{2, 1}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "  web", image.Point{4, 2})
+				testdraw.MustText(c, "  redis", image.Point{2, 3})
+				testdraw.MustText(c, "▸ system", image.Point{0, 4})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "toggle key collapses the selected node",
+			canvas: image.Rect(0, 0, 12, 5),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				for _, k := range []keyboard.Key{keyboard.KeyArrowDown, keyboard.KeyEnter} {
+					if err := tr.Keyboard(keyEv(k), &widgetapi.EventMeta{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 9, 1), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ default", image.Point{0, 0}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ system", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "mouse click selects and expands the node",
+			canvas: image.Rect(0, 0, 12, 5),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				return tr.Mouse(clickEv(5, 3), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ default", image.Point{0, 0})
+				testdraw.MustText(c, "▸ nginx", image.Point{2, 1})
+				testdraw.MustText(c, "  redis", image.Point{2, 2})
+				testdraw.MustRectangle(c, image.Rect(0, 3, 8, 4), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▾ system", image.Point{0, 3}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "  dns", image.Point{2, 4})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "mouse click below the nodes does nothing",
+			canvas: image.Rect(0, 0, 12, 5),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				return tr.Mouse(clickEv(0, 4), &widgetapi.EventMeta{})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▾ default", image.Point{0, 0})
+				testdraw.MustText(c, "▸ nginx", image.Point{2, 1})
+				testdraw.MustText(c, "  redis", image.Point{2, 2})
+				testdraw.MustText(c, "▸ system", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls to keep the selected node visible",
+			canvas: image.Rect(0, 0, 12, 2),
+			roots:  podNodes(),
+			events: func(tr *Tree) error {
+				for i := 0; i < 4; i++ {
+					if err := tr.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelDown}, &widgetapi.EventMeta{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "  redis", image.Point{2, 0})
+				testdraw.MustRectangle(c, image.Rect(0, 1, 8, 2), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ system", image.Point{0, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "keeps the expanded state when the nodes are replaced",
+			canvas: image.Rect(0, 0, 12, 3),
+			roots:  podNodes(),
+			update: func(tr *Tree) error {
+				if err := tr.Mouse(clickEv(0, 0), &widgetapi.EventMeta{}); err != nil {
+					return err
+				}
+				return tr.SetRoots(podNodes()...)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 9, 1), draw.RectChar(' '), draw.RectCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ default", image.Point{0, 0}, draw.TextCellOpts(cell.Inverse()))
+				testdraw.MustText(c, "▸ system", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tr, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			err = tr.SetRoots(tc.roots...)
+			if err == nil && tc.update != nil {
+				// Events in update need the area of a previous draw.
+				if err := tr.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				err = tc.update(tr)
+			}
+			if (err != nil) != tc.wantUpdateErr {
+				t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.events != nil {
+				if err := tr.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				if err := tc.events(tr); err != nil {
+					t.Fatalf("events => unexpected error: %v", err)
+				}
+				if c, err = canvas.New(tc.canvas); err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+			}
+
+			if err := tr.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOnSelect(t *testing.T) {
+	var got []string
+	tr, err := New(
+		OnSelect(func(n *Node) error {
+			got = append(got, n.Label)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := tr.SetRoots(podNodes()...); err != nil {
+		t.Fatalf("SetRoots => unexpected error: %v", err)
+	}
+	if err := tr.Draw(testcanvas.MustNew(image.Rect(0, 0, 12, 5)), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	events := []func() error{
+		func() error { return tr.Keyboard(keyEv(keyboard.KeyArrowDown), &widgetapi.EventMeta{}) },
+		func() error { return tr.Keyboard(keyEv(keyboard.KeyArrowDown), &widgetapi.EventMeta{}) },
+		// Only toggles the node.
+		func() error { return tr.Keyboard(keyEv(keyboard.KeyEnter), &widgetapi.EventMeta{}) },
+		func() error { return tr.Mouse(clickEv(4, 2), &widgetapi.EventMeta{}) },
+		func() error {
+			return tr.Mouse(&terminalapi.Mouse{Button: mouse.ButtonWheelUp}, &widgetapi.EventMeta{})
+		},
+		// Only collapses the node.
+		func() error { return tr.Mouse(clickEv(2, 1), &widgetapi.EventMeta{}) },
+	}
+	for i, ev := range events {
+		if err := ev(); err != nil {
+			t.Fatalf("event %d => unexpected error: %v", i, err)
+		}
+	}
+
+	want := []string{"default", "nginx", "web", "nginx"}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("OnSelect => unexpected diff (-want, +got):\n%s", diff)
+	}
+	if n := tr.Selected(); n == nil || n.Label != "nginx" {
+		t.Errorf("Selected => %v, want the nginx node", n)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tr, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := tr.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
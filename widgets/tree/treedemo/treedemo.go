@@ -0,0 +1,131 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary treedemo displays a Tree widget with namespaces, pods and
+// containers. Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/tcell"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/mum4k/termdash/widgets/tree"
+)
+
+// pod returns a node for a pod with the specified containers.
+func pod(name string, running bool, containers ...string) *tree.Node {
+	n := &tree.Node{
+		Label:    name,
+		Icon:     '●',
+		CellOpts: []cell.Option{cell.FgColor(cell.ColorGreen)},
+	}
+	if !running {
+		n.CellOpts = []cell.Option{cell.FgColor(cell.ColorRed)}
+	}
+	for _, c := range containers {
+		n.Children = append(n.Children, &tree.Node{Label: c, Icon: '□'})
+	}
+	return n
+}
+
+// namespaces returns the nodes displayed in the tree.
+func namespaces() []*tree.Node {
+	return []*tree.Node{
+		{
+			Label:    "default",
+			Expanded: true,
+			Children: []*tree.Node{
+				pod("nginx-7d4f", true, "nginx", "log-shipper"),
+				pod("redis-5c8b", true, "redis"),
+				pod("worker-9a1e", false, "worker"),
+			},
+		},
+		{
+			Label: "kube-system",
+			Children: []*tree.Node{
+				pod("coredns-6d4b", true, "coredns"),
+				pod("kube-proxy-x2k9", true, "kube-proxy"),
+			},
+		},
+		{
+			Label: "monitoring",
+			Children: []*tree.Node{
+				pod("prometheus-0", true, "prometheus", "config-reloader"),
+			},
+		},
+	}
+}
+
+func main() {
+	t, err := tcell.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	selected, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	tr, err := tree.New(
+		tree.OnSelect(func(n *tree.Node) error {
+			return selected.Write(fmt.Sprintf("Selected %s", n.Label), text.WriteReplace())
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := tr.SetRoots(namespaces()...); err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.PlaceWidget(tr),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Use arrows to select, Enter or click to expand"),
+				container.PlaceWidget(selected),
+			),
+			container.SplitPercent(80),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}